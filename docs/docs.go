@@ -126,6 +126,173 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/activities/calendar": {
+            "get": {
+                "description": "Lists the activities open at some point between from and to (RFC3339, defaults to 30 days around now).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Activities"
+                ],
+                "summary": "Get activity calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region (defaults to the server region)",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ActivityCalendarResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/activities/overrides": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Activities"
+                ],
+                "summary": "List activity schedule overrides",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ActivityOverrideListResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Extends, previews or force-ends an activity. Online players are notified when the activity opens or closes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Activities"
+                ],
+                "summary": "Create activity schedule override",
+                "parameters": [
+                    {
+                        "description": "Override",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ActivityOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ActivityOverrideResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/activities/overrides/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Activities"
+                ],
+                "summary": "Delete activity schedule override",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/admin/authz/accounts/{id}/overrides": {
             "get": {
                 "produces": [
@@ -714,6 +881,109 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/arena/leaderboard": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Arena"
+                ],
+                "summary": "Get exercise leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArenaLeaderboardResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/arena/season": {
+            "get": {
+                "description": "Returns the exercise season in progress, opening the first one if needed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Arena"
+                ],
+                "summary": "Get exercise season",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArenaSeasonResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/arena/season/settle": {
+            "post": {
+                "description": "Ends the season in progress now: merit is paid by final score, scores are reset and a new season starts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Arena"
+                ],
+                "summary": "Settle exercise season",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArenaSettlementResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/attire/battle-ui": {
             "get": {
                 "produces": [
@@ -1457,26 +1727,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/config-entries": {
+        "/api/v1/build-banners": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ConfigEntries"
+                    "Build Banners"
                 ],
-                "summary": "List config entries",
+                "summary": "List build banners",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by category",
-                        "name": "category",
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -1484,15 +1754,21 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryListResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildBannerListResponseDoc"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
-                    }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
                 }
             },
             "post": {
@@ -1503,17 +1779,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ConfigEntries"
+                    "Build Banners"
                 ],
-                "summary": "Create config entry",
+                "summary": "Create build banner",
                 "parameters": [
                     {
-                        "description": "Config entry",
+                        "description": "Build banner",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ConfigEntryMutationRequest"
+                            "$ref": "#/definitions/types.BuildBannerRequest"
                         }
                     }
                 ],
@@ -1521,7 +1797,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildBannerSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -1539,19 +1815,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/config-entries/{id}": {
+        "/api/v1/build-banners/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ConfigEntries"
+                    "Build Banners"
                 ],
-                "summary": "Get config entry",
+                "summary": "Get build banner",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Config entry ID",
+                        "description": "Build banner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1561,7 +1837,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildBannerSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -1592,24 +1868,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ConfigEntries"
+                    "Build Banners"
                 ],
-                "summary": "Update config entry",
+                "summary": "Replace build banner",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Config entry ID",
+                        "description": "Build banner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Config entry",
+                        "description": "Build banner",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ConfigEntryMutationRequest"
+                            "$ref": "#/definitions/types.BuildBannerRequest"
                         }
                     }
                 ],
@@ -1617,7 +1893,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildBannerSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -1645,13 +1921,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ConfigEntries"
+                    "Build Banners"
                 ],
-                "summary": "Delete config entry",
+                "summary": "Delete build banner",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Config entry ID",
+                        "description": "Build banner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1661,7 +1937,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -1685,16 +1961,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dorm3d-apartments": {
+        "/api/v1/build-banners/{id}/rolls": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Build Banners"
                 ],
-                "summary": "List Dorm3d apartments",
+                "summary": "List build banner rolls",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Build banner ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Pagination offset",
@@ -1712,7 +1995,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentListResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildRollLogListResponseDoc"
                         }
                     },
                     "400": {
@@ -1721,45 +2004,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dorm3d"
-                ],
-                "summary": "Create Dorm3d apartment",
-                "parameters": [
-                    {
-                        "description": "Dorm3d apartment",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.Dorm3dApartmentRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -1773,19 +2019,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dorm3d-apartments/{id}": {
+        "/api/v1/build-banners/{id}/stats": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Build Banners"
                 ],
-                "summary": "Get Dorm3d apartment",
+                "summary": "Get observed build banner rates",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Build banner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1795,7 +2041,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentResponseDoc"
+                            "$ref": "#/definitions/handlers.BuildBannerStatsResponseDoc"
                         }
                     },
                     "400": {
@@ -1817,8 +2063,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/chat/broadcast": {
+            "post": {
+                "description": "Sends a system notice to the online commanders of a room, or of every room when room_id is omitted. System notices are not stored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1826,24 +2075,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Chat"
                 ],
-                "summary": "Update Dorm3d apartment",
+                "summary": "Broadcast system chat message",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dorm3d apartment",
+                        "description": "Message",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.Dorm3dApartmentRequest"
+                            "$ref": "#/definitions/types.ChatBroadcastRequest"
                         }
                     }
                 ],
@@ -1859,9 +2101,61 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/chat/messages": {
+            "get": {
+                "description": "Returns public chat messages, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Search chat messages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "room_id",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    {
+                        "type": "integer",
+                        "description": "Sender commander ID",
+                        "name": "sender_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text contained in the message (case-insensitive)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ChatMessageListResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -1873,19 +2167,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/chat/messages/{id}": {
             "delete": {
+                "description": "Removes a message from the room history. Clients that already received it keep showing it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Chat"
                 ],
-                "summary": "Delete Dorm3d apartment",
+                "summary": "Delete chat message",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Message ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1919,41 +2216,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dorm3d-apartments/{id}/gifts": {
+        "/api/v1/chat/mutes": {
             "get": {
+                "description": "Returns the mutes still running, the ones ending first first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
-                ],
-                "summary": "Get Dorm3d apartment gifts",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Chat"
                 ],
+                "summary": "List chat mutes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentGiftsResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.ChatMuteListResponseDoc"
                         }
                     },
                     "500": {
@@ -1964,7 +2241,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
+                "description": "Keeps a commander from posting in public chat, replacing any running mute. The mute is announced in every room.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1972,27 +2250,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Chat"
                 ],
-                "summary": "Update Dorm3d apartment gifts",
+                "summary": "Mute commander in chat",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dorm3d gifts",
+                        "description": "Mute",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/orm.Dorm3dGift"
-                            }
+                            "$ref": "#/definitions/types.ChatMuteRequest"
                         }
                     }
                 ],
@@ -2000,7 +2268,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ChatMuteResponseDoc"
                         }
                     },
                     "400": {
@@ -2024,20 +2292,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dorm3d-apartments/{id}/ins": {
-            "get": {
+        "/api/v1/chat/mutes/{commander_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "Chat"
                 ],
-                "summary": "Get Dorm3d apartment ins",
+                "summary": "Unmute commander in chat",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Commander ID",
-                        "name": "id",
+                        "name": "commander_id",
                         "in": "path",
                         "required": true
                     }
@@ -2046,7 +2314,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentInsResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -2068,56 +2336,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/config-entries": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "ConfigEntries"
                 ],
-                "summary": "Update Dorm3d apartment ins",
+                "summary": "List config entries",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Filter by category",
+                        "name": "category",
+                        "in": "query"
                     },
                     {
-                        "description": "Dorm3d ins",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/orm.Dorm3dIns"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Filter by key",
+                        "name": "key",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.ConfigEntryListResponseDoc"
                         }
                     },
                     "500": {
@@ -2127,31 +2375,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/dorm3d-apartments/{id}/rooms": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "ConfigEntries"
                 ],
-                "summary": "Get Dorm3d apartment rooms",
+                "summary": "Create config entry",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Config entry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ConfigEntryMutationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentRoomsResponseDoc"
+                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -2160,12 +2411,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2173,44 +2418,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/config-entries/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "ConfigEntries"
                 ],
-                "summary": "Update Dorm3d apartment rooms",
+                "summary": "Get config entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Config entry ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Dorm3d rooms",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/orm.Dorm3dRoom"
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ConfigEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -2232,31 +2464,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/dorm3d-apartments/{id}/ships": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "ConfigEntries"
                 ],
-                "summary": "Get Dorm3d apartment ships",
+                "summary": "Update config entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Config entry ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Config entry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ConfigEntryMutationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.Dorm3dApartmentShipsResponseDoc"
+                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -2279,43 +2521,28 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dorm3d"
+                    "ConfigEntries"
                 ],
-                "summary": "Update Dorm3d apartment ships",
+                "summary": "Delete config entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Config entry ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Dorm3d ships",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/orm.Dorm3dShip"
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ConfigEntryMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -2339,15 +2566,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/equipment": {
+        "/api/v1/dorm3d-apartments": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Equipment"
+                    "Dorm3d"
                 ],
-                "summary": "List equipment",
+                "summary": "List Dorm3d apartments",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2366,7 +2593,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListEquipmentResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentListResponseDoc"
                         }
                     },
                     "400": {
@@ -2391,17 +2618,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Equipment"
+                    "Dorm3d"
                 ],
-                "summary": "Create equipment",
+                "summary": "Create Dorm3d apartment",
                 "parameters": [
                     {
-                        "description": "Equipment",
+                        "description": "Dorm3d apartment",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.EquipmentPayload"
+                            "$ref": "#/definitions/types.Dorm3dApartmentRequest"
                         }
                     }
                 ],
@@ -2409,7 +2636,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -2427,19 +2654,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/equipment/{id}": {
+        "/api/v1/dorm3d-apartments/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Equipment"
+                    "Dorm3d"
                 ],
-                "summary": "Get equipment details",
+                "summary": "Get Dorm3d apartment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Equipment ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2449,7 +2676,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EquipmentDetailResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentResponseDoc"
                         }
                     },
                     "400": {
@@ -2480,24 +2707,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Equipment"
+                    "Dorm3d"
                 ],
-                "summary": "Update equipment",
+                "summary": "Update Dorm3d apartment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Equipment ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Equipment",
+                        "description": "Dorm3d apartment",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.EquipmentPayload"
+                            "$ref": "#/definitions/types.Dorm3dApartmentRequest"
                         }
                     }
                 ],
@@ -2505,7 +2732,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -2533,13 +2760,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Equipment"
+                    "Dorm3d"
                 ],
-                "summary": "Delete equipment",
+                "summary": "Delete Dorm3d apartment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Equipment ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2549,7 +2776,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -2573,34 +2800,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/exchange-codes": {
+        "/api/v1/dorm3d-apartments/{id}/gifts": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "List exchange codes",
+                "summary": "Get Dorm3d apartment gifts",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Commander ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeCodeListResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentGiftsResponseDoc"
                         }
                     },
                     "400": {
@@ -2609,6 +2831,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2617,7 +2845,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2625,17 +2853,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "Create exchange code",
+                "summary": "Update Dorm3d apartment gifts",
                 "parameters": [
                     {
-                        "description": "Exchange code",
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dorm3d gifts",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ExchangeCodeRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/orm.Dorm3dGift"
+                            }
                         }
                     }
                 ],
@@ -2652,6 +2890,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2661,19 +2905,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/exchange-codes/{id}": {
+        "/api/v1/dorm3d-apartments/{id}/ins": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "Get exchange code",
+                "summary": "Get Dorm3d apartment ins",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2683,7 +2927,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeCodeSummaryResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentInsResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -2708,24 +2958,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "Update exchange code",
+                "summary": "Update Dorm3d apartment ins",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Exchange code",
+                        "description": "Dorm3d ins",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ExchangeCodeRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/orm.Dorm3dIns"
+                            }
                         }
                     }
                 ],
@@ -2755,19 +3008,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/dorm3d-apartments/{id}/rooms": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "Delete exchange code",
+                "summary": "Get Dorm3d apartment rooms",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2777,7 +3032,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentRoomsResponseDoc"
                         }
                     },
                     "400": {
@@ -2799,43 +3054,90 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/exchange-codes/{id}/redeems": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "List exchange code redeems",
+                "summary": "Update Dorm3d apartment rooms",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Dorm3d rooms",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/orm.Dorm3dRoom"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/dorm3d-apartments/{id}/ships": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dorm3d"
+                ],
+                "summary": "Get Dorm3d apartment ships",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Commander ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeCodeRedeemListResponseDoc"
+                            "$ref": "#/definitions/handlers.Dorm3dApartmentShipsResponseDoc"
                         }
                     },
                     "400": {
@@ -2858,7 +3160,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2866,24 +3168,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Dorm3d"
                 ],
-                "summary": "Create exchange code redeem",
+                "summary": "Update Dorm3d apartment ships",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Exchange code redeem",
+                        "description": "Dorm3d ships",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ExchangeCodeRedeemRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/orm.Dorm3dShip"
+                            }
                         }
                     }
                 ],
@@ -2906,8 +3211,72 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/drop-bonuses": {
+            "get": {
+                "description": "Returns every drop bonus, past ones included, the most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Drop Tables"
+                ],
+                "summary": "List drop bonuses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DropBonusListResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Scales the drop rates of the matching tables while the bonus runs. Running bonuses multiply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Drop Tables"
+                ],
+                "summary": "Create drop bonus",
+                "parameters": [
+                    {
+                        "description": "Drop bonus",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DropBonusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DropBonusResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -2921,29 +3290,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/exchange-codes/{id}/redeems/{commander_id}": {
+        "/api/v1/drop-bonuses/{id}": {
             "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Exchange Codes"
+                    "Drop Tables"
                 ],
-                "summary": "Delete exchange code redeem",
+                "summary": "Delete drop bonus",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exchange code ID",
+                        "description": "Drop bonus ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "commander_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -2974,16 +3336,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/items": {
+        "/api/v1/drop-tables": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Drop Tables"
                 ],
-                "summary": "List items",
+                "summary": "List drop tables",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table kind (chapter, event, item)",
+                        "name": "kind",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Pagination offset",
@@ -3001,7 +3369,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListItemsResponseDoc"
+                            "$ref": "#/definitions/handlers.DropTableListResponseDoc"
                         }
                     },
                     "400": {
@@ -3019,6 +3387,7 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "description": "Sets the drop weights of a chapter, an event or a virtual item. A source has at most one table.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3026,17 +3395,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Drop Tables"
                 ],
-                "summary": "Create item",
+                "summary": "Create drop table",
                 "parameters": [
                     {
-                        "description": "Item",
+                        "description": "Drop table",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ItemCreateRequest"
+                            "$ref": "#/definitions/types.DropTableRequest"
                         }
                     }
                 ],
@@ -3044,7 +3413,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.DropTableSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -3053,6 +3422,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3062,19 +3437,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/items/{id}": {
+        "/api/v1/drop-tables/simulate": {
+            "post": {
+                "description": "Rolls the table of a source, a chapter by default, with the drop bonuses running now and compares the observed rates with the expected ones. Nothing is granted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Drop Tables"
+                ],
+                "summary": "Simulate drop table",
+                "parameters": [
+                    {
+                        "description": "Simulation",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DropTableSimulationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DropTableSimulationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/drop-tables/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Drop Tables"
                 ],
-                "summary": "Get item details",
+                "summary": "Get drop table",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Item ID",
+                        "description": "Drop table ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3084,7 +3511,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ItemSummaryResponseDoc"
+                            "$ref": "#/definitions/handlers.DropTableSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -3115,24 +3542,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Drop Tables"
                 ],
-                "summary": "Update item",
+                "summary": "Replace drop table",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Item ID",
+                        "description": "Drop table ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Item",
+                        "description": "Drop table",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ItemUpdateRequest"
+                            "$ref": "#/definitions/types.DropTableRequest"
                         }
                     }
                 ],
@@ -3140,7 +3567,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.DropTableSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -3155,6 +3582,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3164,17 +3597,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "The source goes back to its default drops.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Drop Tables"
                 ],
-                "summary": "Delete item",
+                "summary": "Delete drop table",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Item ID",
+                        "description": "Drop table ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3184,7 +3618,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -3208,28 +3642,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/juustagram/language": {
+        "/api/v1/equipment": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Equipment"
                 ],
-                "summary": "List Juustagram language entries",
+                "summary": "List equipment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Language key prefix",
-                        "name": "prefix",
-                        "in": "query"
-                    }
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramLanguageListResponseDoc"
+                            "$ref": "#/definitions/handlers.ListEquipmentResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -3240,7 +3686,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3248,17 +3694,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Equipment"
                 ],
-                "summary": "Update Juustagram language entry",
+                "summary": "Create equipment",
                 "parameters": [
                     {
-                        "description": "Language entry",
+                        "description": "Equipment",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramLanguage"
+                            "$ref": "#/definitions/types.EquipmentPayload"
                         }
                     }
                 ],
@@ -3266,7 +3712,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -3275,12 +3721,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3288,34 +3728,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/equipment/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Equipment"
                 ],
-                "summary": "Create Juustagram language entry",
+                "summary": "Get equipment details",
                 "parameters": [
                     {
-                        "description": "Language entry",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramLanguage"
-                        }
+                        "type": "integer",
+                        "description": "Equipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.EquipmentDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -3324,6 +3761,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3332,7 +3775,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3340,17 +3783,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Equipment"
                 ],
-                "summary": "Delete Juustagram language entry",
+                "summary": "Update equipment",
                 "parameters": [
                     {
-                        "description": "Language key",
+                        "type": "integer",
+                        "description": "Equipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Equipment",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramLanguageDeleteRequest"
+                            "$ref": "#/definitions/types.EquipmentPayload"
                         }
                     }
                 ],
@@ -3358,7 +3808,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -3380,22 +3830,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/juustagram/language/{key}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Equipment"
                 ],
-                "summary": "Get Juustagram language entry",
+                "summary": "Delete equipment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Language key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "Equipment ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -3404,7 +3852,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramLanguageResponseDoc"
+                            "$ref": "#/definitions/handlers.EquipmentMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -3422,15 +3876,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/juustagram/npc-templates": {
+        "/api/v1/exchange-codes": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "List Juustagram NPC templates",
+                "summary": "List exchange codes",
                 "parameters": [
                     {
                         "type": "integer",
@@ -3449,7 +3903,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramNpcTemplateListResponseDoc"
+                            "$ref": "#/definitions/handlers.ExchangeCodeListResponseDoc"
                         }
                     },
                     "400": {
@@ -3466,7 +3920,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3474,17 +3928,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Update Juustagram NPC template",
+                "summary": "Create exchange code",
                 "parameters": [
                     {
-                        "description": "Juustagram NPC template",
+                        "description": "Exchange code",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramNpcTemplate"
+                            "$ref": "#/definitions/types.ExchangeCodeRequest"
                         }
                     }
                 ],
@@ -3501,12 +3955,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3514,38 +3962,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/exchange-codes/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Create Juustagram NPC template",
+                "summary": "Get exchange code",
                 "parameters": [
                     {
-                        "description": "Juustagram NPC template",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramNpcTemplate"
-                        }
+                        "type": "integer",
+                        "description": "Exchange code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ExchangeCodeSummaryResponseDoc"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -3558,7 +4003,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3566,17 +4011,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Delete Juustagram NPC template",
+                "summary": "Update exchange code",
                 "parameters": [
                     {
-                        "description": "NPC Template ID",
+                        "type": "integer",
+                        "description": "Exchange code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exchange code",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramNpcTemplateDeleteRequest"
+                            "$ref": "#/definitions/types.ExchangeCodeRequest"
                         }
                     }
                 ],
@@ -3606,21 +4058,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/juustagram/npc-templates/{id}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Get Juustagram NPC template",
+                "summary": "Delete exchange code",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "NPC Template ID",
+                        "description": "Exchange code ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3630,7 +4080,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramNpcTemplateResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -3654,16 +4104,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/juustagram/ship-groups": {
+        "/api/v1/exchange-codes/{id}/redeems": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "List Juustagram ship groups",
+                "summary": "List exchange code redeems",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exchange code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Pagination offset",
@@ -3681,7 +4138,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramShipGroupListResponseDoc"
+                            "$ref": "#/definitions/handlers.ExchangeCodeRedeemListResponseDoc"
                         }
                     },
                     "400": {
@@ -3690,6 +4147,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3698,7 +4161,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3706,17 +4169,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Update Juustagram ship group",
+                "summary": "Create exchange code redeem",
                 "parameters": [
                     {
-                        "description": "Juustagram ship group",
+                        "type": "integer",
+                        "description": "Exchange code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exchange code redeem",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramShipGroupTemplate"
+                            "$ref": "#/definitions/types.ExchangeCodeRedeemRequest"
                         }
                     }
                 ],
@@ -3739,6 +4209,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3746,34 +4222,89 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/exchange-codes/{id}/redeems/{commander_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Exchange Codes"
                 ],
-                "summary": "Create Juustagram ship group",
+                "summary": "Delete exchange code redeem",
                 "parameters": [
                     {
-                        "description": "Juustagram ship group",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Exchange code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramShipGroupTemplate"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/guilds": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Guilds"
+                ],
+                "summary": "List guilds",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildListResponseDoc"
                         }
                     },
                     "400": {
@@ -3790,7 +4321,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3798,17 +4329,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Delete Juustagram ship group",
+                "summary": "Create guild",
                 "parameters": [
                     {
-                        "description": "Ship group",
+                        "description": "Guild",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramShipGroupDeleteRequest"
+                            "$ref": "#/definitions/types.GuildCreateRequest"
                         }
                     }
                 ],
@@ -3816,7 +4347,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -3831,6 +4362,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3840,19 +4377,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/juustagram/ship-groups/{id}": {
+        "/api/v1/guilds/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Get Juustagram ship group",
+                "summary": "Get guild with members, applications and logs",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ship Group ID",
+                        "description": "Guild ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3862,7 +4399,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramShipGroupResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -3884,36 +4421,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/juustagram/templates": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "List Juustagram templates",
+                "summary": "Dissolve guild",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Guild ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramTemplateListResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -3922,6 +4452,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3930,7 +4466,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3938,17 +4474,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Update Juustagram template",
+                "summary": "Update guild",
                 "parameters": [
                     {
-                        "description": "Juustagram template",
+                        "type": "integer",
+                        "description": "Guild ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Guild update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramTemplate"
+                            "$ref": "#/definitions/types.GuildUpdateRequest"
                         }
                     }
                 ],
@@ -3956,7 +4499,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -3971,6 +4514,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3978,7 +4527,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/guilds/{id}/members": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -3987,17 +4538,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Create Juustagram template",
+                "summary": "Add guild member",
                 "parameters": [
                     {
-                        "description": "Juustagram template",
+                        "type": "integer",
+                        "description": "Guild ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramTemplate"
+                            "$ref": "#/definitions/types.GuildMemberAddRequest"
                         }
                     }
                 ],
@@ -4005,7 +4563,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -4014,6 +4572,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4021,27 +4591,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/guilds/{id}/members/{commander_id}": {
             "delete": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Delete Juustagram template",
+                "summary": "Remove guild member",
                 "parameters": [
                     {
-                        "description": "Template ID",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramTemplateDeleteRequest"
-                        }
+                        "type": "integer",
+                        "description": "Guild ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4070,31 +4644,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/juustagram/templates/{id}": {
-            "get": {
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Guilds"
                 ],
-                "summary": "Get Juustagram template",
+                "summary": "Update guild member duty",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Template ID",
+                        "description": "Guild ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duty",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GuildMemberUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramTemplateResponseDoc"
+                            "$ref": "#/definitions/handlers.GuildDetailResponseDoc"
                         }
                     },
                     "400": {
@@ -4118,20 +4709,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/livingarea-covers": {
+        "/api/v1/items": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GameData"
+                    "Items"
+                ],
+                "summary": "List items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List living area covers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConfigEntryListResponseDoc"
+                            "$ref": "#/definitions/handlers.ListItemsResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -4141,65 +4752,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/me/commander": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Items"
                 ],
-                "summary": "Get commander profile",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.MeCommanderResponseDoc"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/me/give-item": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Me"
-                ],
-                "summary": "Give item to user",
+                "summary": "Create item",
                 "parameters": [
                     {
-                        "description": "Item grant",
+                        "description": "Item",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.GiveItemRequest"
+                            "$ref": "#/definitions/types.ItemCreateRequest"
                         }
                     }
                 ],
@@ -4207,7 +4779,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -4216,18 +4788,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4237,34 +4797,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/me/give-ship": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/items/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Items"
                 ],
-                "summary": "Give ship to user",
+                "summary": "Get item details",
                 "parameters": [
                     {
-                        "description": "Ship grant",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.GiveShipRequest"
-                        }
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ItemSummaryResponseDoc"
                         }
                     },
                     "400": {
@@ -4273,14 +4828,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4292,10 +4841,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/me/give-skin": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -4303,25 +4850,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Items"
                 ],
-                "summary": "Give skin to user",
+                "summary": "Update item",
                 "parameters": [
                     {
-                        "description": "Skin grant",
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.GiveSkinRequest"
+                            "$ref": "#/definitions/types.ItemUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -4330,14 +4884,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4349,26 +4897,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/me/permissions": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Items"
+                ],
+                "summary": "Delete item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get effective permissions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MePermissionsResponseDoc"
+                            "$ref": "#/definitions/handlers.ItemMutationResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4382,32 +4943,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/me/resources": {
+        "/api/v1/juustagram/language": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Juustagram"
+                ],
+                "summary": "List Juustagram language entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language key prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user resources",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerResourcesResponseDoc"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramLanguageListResponseDoc"
                         }
                     },
                     "500": {
@@ -4426,17 +4983,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Me"
+                    "Juustagram"
                 ],
-                "summary": "Update user resources",
+                "summary": "Update Juustagram language entry",
                 "parameters": [
                     {
-                        "description": "Resource update",
+                        "description": "Language entry",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ResourceUpdateRequest"
+                            "$ref": "#/definitions/types.JuustagramLanguage"
                         }
                     }
                 ],
@@ -4453,14 +5010,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4472,36 +5023,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/notices": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notices"
+                    "Juustagram"
                 ],
-                "summary": "List notices",
+                "summary": "Create Juustagram language entry",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Language entry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramLanguage"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.NoticeListResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -4518,7 +5067,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -4526,17 +5075,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notices"
+                    "Juustagram"
                 ],
-                "summary": "Create notice",
+                "summary": "Delete Juustagram language entry",
                 "parameters": [
                     {
-                        "description": "Notice",
-                        "name": "notice",
+                        "description": "Language key",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.NoticeSummary"
+                            "$ref": "#/definitions/types.JuustagramLanguageDeleteRequest"
                         }
                     }
                 ],
@@ -4553,6 +5102,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4562,20 +5117,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/notices/active": {
+        "/api/v1/juustagram/language/{key}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notices"
+                    "Juustagram"
                 ],
-                "summary": "List active notices",
-                "responses": {
+                "summary": "Get Juustagram language entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.NoticeActiveResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramLanguageResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -4587,29 +5157,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/notices/{id}": {
+        "/api/v1/juustagram/npc-templates": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notices"
+                    "Juustagram"
                 ],
-                "summary": "Get notice",
+                "summary": "List Juustagram NPC templates",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Notice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.NoticeSummaryResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramNpcTemplateListResponseDoc"
                         }
                     },
                     "400": {
@@ -4618,12 +5193,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4640,24 +5209,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notices"
+                    "Juustagram"
                 ],
-                "summary": "Update notice",
+                "summary": "Update Juustagram NPC template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Notice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Notice",
-                        "name": "notice",
+                        "description": "Juustagram NPC template",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.NoticeSummary"
+                            "$ref": "#/definitions/types.JuustagramNpcTemplate"
                         }
                     }
                 ],
@@ -4688,91 +5250,33 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "produces": [
+            "post": {
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Notices"
-                ],
-                "summary": "Delete notice",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Notice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/players": {
-            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "List players",
+                "summary": "Create Juustagram NPC template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort by last_login",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filters: online, banned",
-                        "name": "filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Minimum level",
-                        "name": "min_level",
-                        "in": "query"
+                        "description": "Juustagram NPC template",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramNpcTemplate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListPlayersResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -4789,7 +5293,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -4797,17 +5301,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Create player",
+                "summary": "Delete Juustagram NPC template",
                 "parameters": [
                     {
-                        "description": "Player create",
+                        "description": "NPC Template ID",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerCreateRequest"
+                            "$ref": "#/definitions/types.JuustagramNpcTemplateDeleteRequest"
                         }
                     }
                 ],
@@ -4815,7 +5319,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -4824,8 +5328,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4839,20 +5343,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/compensations/push-online": {
-            "post": {
+        "/api/v1/juustagram/npc-templates/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
+                ],
+                "summary": "Get Juustagram NPC template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "NPC Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Push compensation notifications to online players",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PushCompensationResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramNpcTemplateResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -4864,23 +5389,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/search": {
+        "/api/v1/juustagram/ship-groups": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Search players",
+                "summary": "List Juustagram ship groups",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "Pagination offset",
@@ -4892,31 +5410,13 @@ const docTemplate = `{
                         "description": "Pagination limit",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort by last_login",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filters: online, banned",
-                        "name": "filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Minimum level",
-                        "name": "min_level",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListPlayersResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramShipGroupListResponseDoc"
                         }
                     },
                     "400": {
@@ -4932,35 +5432,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player details",
+                "summary": "Update Juustagram ship group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Juustagram ship group",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramShipGroupTemplate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerDetailResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -4973,21 +5482,26 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Delete player",
+                "summary": "Create Juustagram ship group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Juustagram ship group",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramShipGroupTemplate"
+                        }
                     }
                 ],
                 "responses": {
@@ -5003,12 +5517,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5017,7 +5525,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5025,24 +5533,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player",
+                "summary": "Delete Juustagram ship group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Player updates",
+                        "description": "Ship group",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerUpdateRequest"
+                            "$ref": "#/definitions/types.JuustagramShipGroupDeleteRequest"
                         }
                     }
                 ],
@@ -5050,7 +5551,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -5065,12 +5566,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5080,19 +5575,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/arena-shop": {
+        "/api/v1/juustagram/ship-groups/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player arena shop",
+                "summary": "Get Juustagram ship group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Ship Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5102,7 +5597,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramShipGroupResponseDoc"
                         }
                     },
                     "400": {
@@ -5124,41 +5619,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/juustagram/templates": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player arena shop state",
+                "summary": "List Juustagram templates",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     },
                     {
-                        "description": "State updates",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ArenaShopUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramTemplateListResponseDoc"
                         }
                     },
                     "400": {
@@ -5167,12 +5657,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5181,68 +5665,33 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "produces": [
+            "put": {
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Players"
-                ],
-                "summary": "Clear player arena shop state",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.PlayerArenaShopDeleteResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/players/{id}/arena-shop/refresh": {
-            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Refresh player arena shop",
+                "summary": "Update Juustagram template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Juustagram template",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramTemplate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -5264,35 +5713,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/attires": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player attires",
+                "summary": "Create Juustagram template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Juustagram template",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramTemplate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerAttiresResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5305,7 +5757,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5313,24 +5765,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Add player attire",
+                "summary": "Delete Juustagram template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Attire request",
+                        "description": "Template ID",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerAttireCreateRequest"
+                            "$ref": "#/definitions/types.JuustagramTemplateDeleteRequest"
                         }
                     }
                 ],
@@ -5362,41 +5807,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/attires/selected": {
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/juustagram/templates/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player attire selection",
+                "summary": "Get Juustagram template",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Template ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Selection update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerAttireSelectionUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramTemplateResponseDoc"
                         }
                     },
                     "400": {
@@ -5420,53 +5853,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/attires/{type}/{attire_id}": {
-            "delete": {
+        "/api/v1/livingarea-covers": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "GameData"
                 ],
-                "summary": "Remove player attire",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Attire type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
+                "summary": "List living area covers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConfigEntryListResponseDoc"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Attire ID",
-                        "name": "attire_id",
-                        "in": "path",
-                        "required": true
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     }
+                }
+            }
+        },
+        "/api/v1/me/commander": {
+            "get": {
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Get commander profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.MeCommanderResponseDoc"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5478,8 +5913,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/me/give-item": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5487,38 +5924,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Me"
                 ],
-                "summary": "Update player attire",
+                "summary": "Give item to user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Attire type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Attire ID",
-                        "name": "attire_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Attire update",
+                        "description": "Item grant",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerAttireUpdateRequest"
+                            "$ref": "#/definitions/types.GiveItemRequest"
                         }
                     }
                 ],
@@ -5535,8 +5951,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5550,7 +5972,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/ban": {
+        "/api/v1/me/give-ship": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -5559,24 +5981,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Me"
                 ],
-                "summary": "Ban player",
+                "summary": "Give ship to user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Ban request",
+                        "description": "Ship grant",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.BanPlayerRequest"
+                            "$ref": "#/definitions/types.GiveShipRequest"
                         }
                     }
                 ],
@@ -5593,8 +6008,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5606,27 +6027,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/me/give-skin": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Me"
                 ],
-                "summary": "Unban player",
+                "summary": "Give skin to user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Skin grant",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GiveSkinRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
                             "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
@@ -5637,8 +6065,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5652,39 +6086,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/buffs": {
+        "/api/v1/me/permissions": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Me"
                 ],
-                "summary": "Get player buffs",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                "summary": "Get effective permissions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MePermissionsResponseDoc"
+                        }
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Only active buffs",
-                        "name": "active",
-                        "in": "query"
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     }
+                }
+            }
+        },
+        "/api/v1/me/resources": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
                 ],
+                "summary": "Get user resources",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuffsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerResourcesResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5697,7 +6153,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5705,24 +6161,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Me"
                 ],
-                "summary": "Add buff to player",
+                "summary": "Update user resources",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Buff grant",
+                        "description": "Resource update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerBuffAddRequest"
+                            "$ref": "#/definitions/types.ResourceUpdateRequest"
                         }
                     }
                 ],
@@ -5739,8 +6188,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -5754,36 +6209,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/buffs/{buff_id}": {
+        "/api/v1/notices": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Notices"
                 ],
-                "summary": "Get player buff",
+                "summary": "List notices",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Buff ID",
-                        "name": "buff_id",
-                        "in": "path",
-                        "required": true
-                    }
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuffEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.NoticeListResponseDoc"
                         }
                     },
                     "400": {
@@ -5792,12 +6245,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5806,28 +6253,26 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Notices"
                 ],
-                "summary": "Delete buff from player",
+                "summary": "Create notice",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Buff ID",
-                        "name": "buff_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Notice",
+                        "name": "notice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.NoticeSummary"
+                        }
                     }
                 ],
                 "responses": {
@@ -5843,12 +6288,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5856,60 +6295,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/notices/active": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
-                ],
-                "summary": "Update player buff",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Buff ID",
-                        "name": "buff_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Buff update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerBuffUpdateRequest"
-                        }
-                    }
+                    "Notices"
                 ],
+                "summary": "List active notices",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuffEntryResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.NoticeActiveResponseDoc"
                         }
                     },
                     "500": {
@@ -5921,19 +6322,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/builds": {
+        "/api/v1/notices/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Notices"
                 ],
-                "summary": "Get player builds",
+                "summary": "Get notice",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Notice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5943,7 +6344,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuildsResponseDoc"
+                            "$ref": "#/definitions/handlers.NoticeSummaryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -5960,7 +6367,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5968,24 +6375,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Notices"
                 ],
-                "summary": "Create player build",
+                "summary": "Update notice",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Notice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Build create",
-                        "name": "payload",
+                        "description": "Notice",
+                        "name": "notice",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerBuildCreateRequest"
+                            "$ref": "#/definitions/types.NoticeSummary"
                         }
                     }
                 ],
@@ -5993,7 +6400,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuildEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -6015,36 +6422,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/builds/counters": {
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Notices"
                 ],
-                "summary": "Update player build counters",
+                "summary": "Delete notice",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Notice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Build counters update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerBuildCounterUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -6060,12 +6453,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6075,33 +6462,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/builds/queue": {
+        "/api/v1/payments/orders": {
             "get": {
+                "description": "Returns the payment ledger, newest orders first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Payments"
                 ],
-                "summary": "Get player build queue snapshot",
+                "summary": "Search charge orders",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order status (pending, paid, delivered, failed, refunded)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuildQueueResponseDoc"
+                            "$ref": "#/definitions/handlers.PaymentOrderListResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -6115,36 +6520,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/builds/{build_id}": {
-            "get": {
+        "/api/v1/payments/orders/{id}/approve": {
+            "post": {
+                "description": "Marks a pending order as paid, as a payment provider would. The commander gets the goods once the client confirms the order.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Payments"
                 ],
-                "summary": "Get player build",
+                "summary": "Approve charge order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Order ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Build ID",
-                        "name": "build_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerBuildEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PaymentOrderResponseDoc"
                         }
                     },
                     "400": {
@@ -6159,6 +6558,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6166,36 +6571,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/payments/orders/{id}/refund": {
+            "post": {
+                "description": "Refunds a delivered order. The commander keeps the goods but has to pay the refund back before charging them again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Payments"
                 ],
-                "summary": "Delete player build",
+                "summary": "Refund charge order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Order ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Build ID",
-                        "name": "build_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PaymentOrderResponseDoc"
                         }
                     },
                     "400": {
@@ -6210,6 +6611,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6217,101 +6624,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player build",
+                "summary": "List players",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Build ID",
-                        "name": "build_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Build update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerBuildUpdateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
+                    {
+                        "type": "string",
+                        "description": "Sort by last_login",
+                        "name": "sort",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/players/{id}/builds/{build_id}/quick-finish": {
-            "patch": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Players"
-                ],
-                "summary": "Quick finish player build",
-                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Filters: online, banned",
+                        "name": "filter",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Build ID",
-                        "name": "build_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Minimum level",
+                        "name": "min_level",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.ListPlayersResponseDoc"
                         }
                     },
                     "400": {
@@ -6320,12 +6680,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6333,43 +6687,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/chapter-progress": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "List player chapter progress",
+                "summary": "Create player",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Player create",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterProgressListResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -6378,8 +6723,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -6391,53 +6736,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/players/compensations/push-online": {
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create player chapter progress",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Chapter progress payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerChapterProgressCreateRequest"
-                        }
-                    }
-                ],
+                "summary": "Push compensation notifications to online players",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PushCompensationResponseDoc"
                         }
                     },
                     "500": {
@@ -6449,7 +6763,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/chapter-progress/search": {
+        "/api/v1/players/search": {
             "get": {
                 "produces": [
                     "application/json"
@@ -6457,37 +6771,43 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Search player chapter progress",
+                "summary": "Search players",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Filter by chapter ID",
-                        "name": "chapter_id",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by updated_at \u003e= RFC3339",
-                        "name": "updated_since",
+                        "description": "Sort by last_login",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "Filters: online, banned",
+                        "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
+                        "description": "Minimum level",
+                        "name": "min_level",
                         "in": "query"
                     }
                 ],
@@ -6495,7 +6815,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterProgressListResponseDoc"
+                            "$ref": "#/definitions/handlers.ListPlayersResponseDoc"
                         }
                     },
                     "400": {
@@ -6504,12 +6824,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6519,7 +6833,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/chapter-progress/{chapter_id}": {
+        "/api/v1/players/{id}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -6527,7 +6841,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player chapter progress",
+                "summary": "Get player details",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6535,26 +6849,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Chapter ID",
-                        "name": "chapter_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerDetailResponseDoc"
                         }
                     },
                     "404": {
@@ -6578,7 +6879,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player chapter progress",
+                "summary": "Delete player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6586,13 +6887,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Chapter ID",
-                        "name": "chapter_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -6632,7 +6926,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player chapter progress",
+                "summary": "Update player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6642,19 +6936,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Chapter ID",
-                        "name": "chapter_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Chapter progress payload",
+                        "description": "Player updates",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerChapterProgressUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerUpdateRequest"
                         }
                     }
                 ],
@@ -6662,7 +6949,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMutationResponseDoc"
                         }
                     },
                     "400": {
@@ -6677,6 +6964,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -6686,15 +6979,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/chapter-state": {
+        "/api/v1/players/{id}/activities/progress": {
             "get": {
+                "description": "PT, claimed milestones, login sign-in days, event shop purchases and claimed tasks of every activity the player took part in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player chapter state",
+                "summary": "List player event progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6708,7 +7002,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerActivityProgressResponseDoc"
                         }
                     },
                     "400": {
@@ -6730,18 +7024,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/activities/progress/{activity_id}": {
+            "delete": {
+                "description": "Clears the progress of the player in an activity. The tasks of a task list activity are reset too.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create player chapter state",
+                "summary": "Reset player event progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6751,20 +7045,64 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Chapter state payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Activity ID",
+                        "name": "activity_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerChapterStateCreateRequest"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/players/{id}/arena": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player exercise state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerArenaResponseDoc"
                         }
                     },
                     "400": {
@@ -6786,15 +7124,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/arena-shop": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player chapter state",
+                "summary": "Get player arena shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6808,7 +7148,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
                         }
                     },
                     "400": {
@@ -6831,7 +7171,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -6841,7 +7181,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player chapter state",
+                "summary": "Update player arena shop state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6851,12 +7191,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Chapter state payload",
-                        "name": "payload",
+                        "description": "State updates",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerChapterStateUpdateRequest"
+                            "$ref": "#/definitions/types.ArenaShopUpdateRequest"
                         }
                     }
                 ],
@@ -6864,7 +7204,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
                         }
                     },
                     "400": {
@@ -6886,17 +7226,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/chapter-state/search": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Search player chapter states",
+                "summary": "Clear player arena shop state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6904,37 +7242,53 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerArenaShopDeleteResponseDoc"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Filter by chapter ID",
-                        "name": "chapter_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by updated_at \u003e= RFC3339",
-                        "name": "updated_since",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/arena-shop/refresh": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Refresh player arena shop",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerChapterStateListResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerArenaShopResponseDoc"
                         }
                     },
                     "400": {
@@ -6958,7 +7312,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/compensations": {
+        "/api/v1/players/{id}/attires": {
             "get": {
                 "produces": [
                     "application/json"
@@ -6966,7 +7320,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player compensations",
+                "summary": "Get player attires",
                 "parameters": [
                     {
                         "type": "integer",
@@ -6980,7 +7334,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerCompensationsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerAttiresResponseDoc"
                         }
                     },
                     "404": {
@@ -7007,7 +7361,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create compensation for player",
+                "summary": "Add player attire",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7017,12 +7371,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Compensation request",
+                        "description": "Attire request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateCompensationRequest"
+                            "$ref": "#/definitions/types.PlayerAttireCreateRequest"
                         }
                     }
                 ],
@@ -7054,15 +7408,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/compensations/push": {
-            "post": {
+        "/api/v1/players/{id}/attires/selected": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Push compensation notification",
+                "summary": "Update player attire selection",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7070,6 +7427,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Selection update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerAttireSelectionUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -7100,15 +7466,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/compensations/{compensation_id}": {
-            "get": {
+        "/api/v1/players/{id}/attires/{type}/{attire_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player compensation",
+                "summary": "Remove player attire",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7119,8 +7485,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Compensation ID",
-                        "name": "compensation_id",
+                        "description": "Attire type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attire ID",
+                        "name": "attire_id",
                         "in": "path",
                         "required": true
                     }
@@ -7129,7 +7502,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerCompensationsResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -7152,14 +7525,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete compensation from player",
+                "summary": "Update player attire",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7170,10 +7546,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Compensation ID",
-                        "name": "compensation_id",
+                        "description": "Attire type",
+                        "name": "type",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attire ID",
+                        "name": "attire_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attire update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerAttireUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -7202,8 +7594,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/players/{id}/ban": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -7213,7 +7607,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update compensation for player",
+                "summary": "Ban player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7223,19 +7617,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Compensation ID",
-                        "name": "compensation_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Compensation update",
+                        "description": "Ban request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateCompensationRequest"
+                            "$ref": "#/definitions/types.BanPlayerRequest"
                         }
                     }
                 ],
@@ -7265,17 +7652,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/equipment": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player equipment bag",
+                "summary": "Unban player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7289,61 +7674,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerEquipmentResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Players"
-                ],
-                "summary": "Upsert player equipment entry",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Equipment upsert",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerEquipmentUpsertRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -7357,7 +7698,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/equipment/{equipment_id}": {
+        "/api/v1/players/{id}/blueprints": {
             "get": {
                 "produces": [
                     "application/json"
@@ -7365,7 +7706,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player equipment entry",
+                "summary": "Get player research projects and blueprint progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7373,20 +7714,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Equipment ID",
-                        "name": "equipment_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerEquipmentEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBlueprintsResponseDoc"
                         }
                     },
                     "400": {
@@ -7408,15 +7742,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/blueprints/{blueprint_id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player equipment entry",
+                "summary": "Grant or overwrite the progress of a player blueprint",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7427,17 +7766,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Equipment ID",
-                        "name": "equipment_id",
+                        "description": "Blueprint ID",
+                        "name": "blueprint_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Blueprint progress",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerBlueprintUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBlueprintsResponseDoc"
                         }
                     },
                     "400": {
@@ -7461,7 +7809,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/flags": {
+        "/api/v1/players/{id}/buffs": {
             "get": {
                 "produces": [
                     "application/json"
@@ -7469,7 +7817,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player common flags",
+                "summary": "Get player buffs",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7477,13 +7825,19 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only active buffs",
+                        "name": "active",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerFlagsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuffsResponseDoc"
                         }
                     },
                     "404": {
@@ -7510,7 +7864,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player common flag",
+                "summary": "Add buff to player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7520,12 +7874,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Flag request",
+                        "description": "Buff grant",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerFlagRequest"
+                            "$ref": "#/definitions/types.PlayerBuffAddRequest"
                         }
                     }
                 ],
@@ -7557,15 +7911,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/flags/{flag_id}": {
-            "delete": {
+        "/api/v1/players/{id}/buffs/{buff_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player common flag",
+                "summary": "Get player buff",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7576,8 +7930,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Flag ID",
-                        "name": "flag_id",
+                        "description": "Buff ID",
+                        "name": "buff_id",
                         "in": "path",
                         "required": true
                     }
@@ -7586,7 +7940,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuffEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -7608,17 +7962,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/fleets": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player fleets",
+                "summary": "Delete buff from player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7626,13 +7978,26 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Buff ID",
+                        "name": "buff_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerFleetsResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -7649,7 +8014,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -7659,7 +8024,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create player fleet",
+                "summary": "Update player buff",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7669,12 +8034,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Fleet create",
+                        "type": "integer",
+                        "description": "Buff ID",
+                        "name": "buff_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buff update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerFleetCreateRequest"
+                            "$ref": "#/definitions/types.PlayerBuffUpdateRequest"
                         }
                     }
                 ],
@@ -7682,7 +8054,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuffEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -7697,12 +8069,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -7712,7 +8078,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/fleets/{fleet_id}": {
+        "/api/v1/players/{id}/builds": {
             "get": {
                 "produces": [
                     "application/json"
@@ -7720,7 +8086,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player fleet",
+                "summary": "Get player builds",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7728,26 +8094,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Fleet ID",
-                        "name": "fleet_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuildsResponseDoc"
                         }
                     },
                     "404": {
@@ -7764,14 +8117,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player fleet",
+                "summary": "Create player build",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7781,18 +8137,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Fleet ID",
-                        "name": "fleet_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Build create",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerBuildCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuildEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -7814,7 +8172,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/players/{id}/builds/counters": {
             "patch": {
                 "consumes": [
                     "application/json"
@@ -7825,7 +8185,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player fleet",
+                "summary": "Update player build counters",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7835,19 +8195,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Fleet ID",
-                        "name": "fleet_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Fleet updates",
+                        "description": "Build counters update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerFleetUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerBuildCounterUpdateRequest"
                         }
                     }
                 ],
@@ -7855,7 +8208,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -7879,18 +8232,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/give-item": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/players/{id}/builds/queue": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Give item to player",
+                "summary": "Get player build queue snapshot",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7898,28 +8248,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Item grant",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.GiveItemRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuildQueueResponseDoc"
                         }
                     },
                     "404": {
@@ -7937,18 +8272,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/give-ship": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/players/{id}/builds/{build_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Give ship to player",
+                "summary": "Get player build",
                 "parameters": [
                     {
                         "type": "integer",
@@ -7958,20 +8290,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Ship grant",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.GiveShipRequest"
-                        }
-                    }
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "build_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerBuildEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -7993,20 +8323,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/give-skin": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Give skin to player",
+                "summary": "Delete player build",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8016,18 +8341,16 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Skin grant",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.GiveSkinRequest"
-                        }
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "build_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
@@ -8051,17 +8374,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/guide": {
-            "get": {
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player guide indices",
+                "summary": "Update player build",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8069,13 +8393,35 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "build_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Build update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerBuildUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuideResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -8091,18 +8437,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/players/{id}/builds/{build_id}/quick-finish": {
             "patch": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player guide indices",
+                "summary": "Quick finish player build",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8112,13 +8457,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Guide update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerGuideUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Build ID",
+                        "name": "build_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -8149,7 +8492,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/guild-shop": {
+        "/api/v1/players/{id}/chapter-progress": {
             "get": {
                 "produces": [
                     "application/json"
@@ -8157,7 +8500,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player guild shop",
+                "summary": "List player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8165,13 +8508,31 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterProgressListResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -8188,7 +8549,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -8198,7 +8559,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player guild shop state",
+                "summary": "Create player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8208,12 +8569,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "State updates",
-                        "name": "body",
+                        "description": "Chapter progress payload",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.GuildShopUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerChapterProgressCreateRequest"
                         }
                     }
                 ],
@@ -8221,7 +8582,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
                         }
                     },
                     "400": {
@@ -8245,7 +8606,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/guild-shop/goods": {
+        "/api/v1/players/{id}/chapter-progress/search": {
             "get": {
                 "produces": [
                     "application/json"
@@ -8253,7 +8614,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player guild shop goods",
+                "summary": "Search player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8261,13 +8622,37 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by chapter ID",
+                        "name": "chapter_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by updated_at \u003e= RFC3339",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopGoodsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterProgressListResponseDoc"
                         }
                     },
                     "400": {
@@ -8289,18 +8674,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/chapter-progress/{chapter_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player guild shop good",
+                "summary": "Get player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8310,20 +8694,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Guild shop good",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.GuildShopGoodCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Chapter ID",
+                        "name": "chapter_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
                         }
                     },
                     "400": {
@@ -8338,12 +8720,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -8351,9 +8727,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/guild-shop/goods/{index}": {
+            },
             "delete": {
                 "produces": [
                     "application/json"
@@ -8361,7 +8735,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player guild shop good",
+                "summary": "Delete player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8372,8 +8746,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Goods index",
-                        "name": "index",
+                        "description": "Chapter ID",
+                        "name": "chapter_id",
                         "in": "path",
                         "required": true
                     }
@@ -8382,7 +8756,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -8415,7 +8789,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player guild shop good",
+                "summary": "Update player chapter progress",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8426,18 +8800,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Goods index",
-                        "name": "index",
+                        "description": "Chapter ID",
+                        "name": "chapter_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Good updates",
-                        "name": "body",
+                        "description": "Chapter progress payload",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.GuildShopGoodPatchRequest"
+                            "$ref": "#/definitions/types.PlayerChapterProgressUpdateRequest"
                         }
                     }
                 ],
@@ -8445,7 +8819,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterProgressResponseDoc"
                         }
                     },
                     "400": {
@@ -8469,15 +8843,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/guild-shop/refresh": {
-            "post": {
+        "/api/v1/players/{id}/chapter-state": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Refresh player guild shop",
+                "summary": "Get player chapter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8491,7 +8865,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -8507,17 +8887,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/items": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player items",
+                "summary": "Create player chapter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8525,13 +8906,28 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Chapter state payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerChapterStateCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerItemsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -8547,17 +8943,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/items/{item_id}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player item",
+                "summary": "Delete player chapter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8565,20 +8959,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerItemEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -8601,14 +8988,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player item",
+                "summary": "Update player chapter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8618,18 +9008,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Chapter state payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerChapterStateUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterStateResponseDoc"
                         }
                     },
                     "400": {
@@ -8651,18 +9043,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/chapter-state/search": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player item quantity",
+                "summary": "Search player chapter states",
                 "parameters": [
                     {
                         "type": "integer",
@@ -8673,26 +9064,34 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by chapter ID",
+                        "name": "chapter_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Item quantity update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerItemQuantityUpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by updated_at \u003e= RFC3339",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerChapterStateListResponseDoc"
                         }
                     },
                     "400": {
@@ -8716,41 +9115,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/chat-groups/read": {
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/players/{id}/cheater-marks": {
+            "get": {
+                "description": "Marks are reported by the client or raised when a battle result fails validation, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Mark Juustagram chat groups read",
+                "summary": "List player cheater marks",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Chat group IDs",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramChatReadRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerCheaterMarksResponseDoc"
                         }
                     },
                     "400": {
@@ -8772,38 +9160,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}": {
+            },
             "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Delete Juustagram chat group",
+                "summary": "Clear player cheater marks",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Chat Group ID",
-                        "name": "chat_group_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramChatGroupDeleteResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -8827,49 +9206,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}/replies/{sequence}": {
-            "delete": {
+        "/api/v1/players/{id}/compensations": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Delete Juustagram chat reply",
+                "summary": "Get player compensations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Chat Group ID",
-                        "name": "chat_group_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Reply sequence",
-                        "name": "sequence",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramChatReplyDeleteResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerCompensationsResponseDoc"
                         }
                     },
                     "404": {
@@ -8885,9 +9244,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}/reply": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -8896,31 +9253,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Reply to Juustagram chat group",
+                "summary": "Create compensation for player",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Chat Group ID",
-                        "name": "chat_group_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Chat reply",
+                        "description": "Compensation request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramChatReplyRequest"
+                            "$ref": "#/definitions/types.CreateCompensationRequest"
                         }
                     }
                 ],
@@ -8928,7 +9278,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -8952,36 +9302,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/discuss/{message_id}": {
-            "get": {
+        "/api/v1/players/{id}/compensations/push": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "List Juustagram discuss selections",
+                "summary": "Push compensation notification",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramPlayerDiscussListResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9005,55 +9348,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/discuss/{message_id}/{discuss_id}": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/players/{id}/compensations/{compensation_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Update Juustagram discuss selection",
+                "summary": "Get player compensation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Discuss ID",
-                        "name": "discuss_id",
+                        "description": "Compensation ID",
+                        "name": "compensation_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Discuss selection",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramPlayerDiscussUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramPlayerDiscussResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerCompensationsResponseDoc"
                         }
                     },
                     "400": {
@@ -9081,28 +9405,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Delete Juustagram discuss selection",
+                "summary": "Delete compensation from player",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Discuss ID",
-                        "name": "discuss_id",
+                        "description": "Compensation ID",
+                        "name": "compensation_id",
                         "in": "path",
                         "required": true
                     }
@@ -9133,43 +9450,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/groups": {
-            "get": {
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "List Juustagram chat groups",
+                "summary": "Update compensation for player",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Compensation ID",
+                        "name": "compensation_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Compensation update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateCompensationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupListResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9191,6 +9513,46 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/api/v1/players/{id}/equipment": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player equipment bag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerEquipmentResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
             },
             "post": {
                 "consumes": [
@@ -9200,24 +9562,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Create Juustagram group",
+                "summary": "Upsert player equipment entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Juustagram group",
+                        "description": "Equipment upsert",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramGroupCreateRequest"
+                            "$ref": "#/definitions/types.PlayerEquipmentUpsertRequest"
                         }
                     }
                 ],
@@ -9225,7 +9587,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9234,12 +9596,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -9249,27 +9605,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/groups/{group_id}": {
+        "/api/v1/players/{id}/equipment/{equipment_id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Get Juustagram chat group",
+                "summary": "Get player equipment entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "group_id",
+                        "description": "Equipment ID",
+                        "name": "equipment_id",
                         "in": "path",
                         "required": true
                     }
@@ -9278,7 +9634,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerEquipmentEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -9306,21 +9662,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Delete Juustagram group",
+                "summary": "Remove player equipment entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "group_id",
+                        "description": "Equipment ID",
+                        "name": "equipment_id",
                         "in": "path",
                         "required": true
                     }
@@ -9329,7 +9685,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupDeleteResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9351,54 +9707,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/flags": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Update Juustagram group settings",
+                "summary": "Get player common flags",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Group ID",
-                        "name": "group_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Juustagram group update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.JuustagramGroupUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFlagsResponseDoc"
                         }
                     },
                     "404": {
@@ -9414,9 +9747,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/groups/{group_id}/chat-groups": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -9425,31 +9756,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Activate Juustagram chat group",
+                "summary": "Add player common flag",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Group ID",
-                        "name": "group_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Chat group",
+                        "description": "Flag request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramChatGroupCreateRequest"
+                            "$ref": "#/definitions/types.PlayerFlagRequest"
                         }
                     }
                 ],
@@ -9457,7 +9781,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9481,29 +9805,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/message-states": {
-            "get": {
+        "/api/v1/players/{id}/flags/{flag_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "List Juustagram message states",
+                "summary": "Remove player common flag",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Flag ID",
+                        "name": "flag_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageStateListResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9527,42 +9858,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/message-states/{message_id}": {
+        "/api/v1/players/{id}/fleets": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Get Juustagram message state",
+                "summary": "Get player fleets",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageStateResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFleetsResponseDoc"
                         }
                     },
                     "404": {
@@ -9579,7 +9897,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -9587,31 +9905,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Update Juustagram message state",
+                "summary": "Create player fleet",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Message state update",
+                        "description": "Fleet create",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramMessageStateUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerFleetCreateRequest"
                         }
                     }
                 ],
@@ -9619,7 +9930,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageStateResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -9634,6 +9945,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -9641,27 +9958,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/fleets/{fleet_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Delete Juustagram message state",
+                "summary": "Get player fleet",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
+                        "description": "Fleet ID",
+                        "name": "fleet_id",
                         "in": "path",
                         "required": true
                     }
@@ -9670,65 +9989,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/messages": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Juustagram"
-                ],
-                "summary": "List Juustagram messages",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Commander ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageListResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -9750,29 +10011,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/juustagram/messages/{message_id}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Get Juustagram message",
+                "summary": "Remove player fleet",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
+                        "description": "Fleet ID",
+                        "name": "fleet_id",
                         "in": "path",
                         "required": true
                     }
@@ -9781,7 +10040,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -9812,31 +10071,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Update Juustagram message state",
+                "summary": "Update player fleet",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
+                        "description": "Fleet ID",
+                        "name": "fleet_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Message update",
+                        "description": "Fleet updates",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramMessageUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerFleetUpdateRequest"
                         }
                     }
                 ],
@@ -9844,7 +10103,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFleetEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -9868,36 +10127,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/juustagram/messages/{message_id}/discuss": {
+        "/api/v1/players/{id}/friends": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Get Juustagram message discuss options",
+                "summary": "Get player friends, pending requests and blacklist",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramDiscussResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFriendsResponseDoc"
                         }
                     },
                     "400": {
@@ -9928,31 +10180,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Juustagram"
+                    "Players"
                 ],
-                "summary": "Reply to Juustagram message",
+                "summary": "Add a friend to a player",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Commander ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Message ID",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Discuss reply",
+                        "description": "Friend to add",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.JuustagramDiscussRequest"
+                            "$ref": "#/definitions/types.PlayerFriendAddRequest"
                         }
                     }
                 ],
@@ -9960,7 +10205,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFriendsResponseDoc"
                         }
                     },
                     "400": {
@@ -9975,6 +10220,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -9984,7 +10235,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/kick": {
+        "/api/v1/players/{id}/friends/blocks": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -9995,7 +10246,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Kick player",
+                "summary": "Add a commander to a player's blacklist",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10005,11 +10256,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Kick request",
+                        "description": "Commander to block",
                         "name": "payload",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.KickPlayerRequest"
+                            "$ref": "#/definitions/types.PlayerFriendBlockRequest"
                         }
                     }
                 ],
@@ -10017,7 +10269,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.KickPlayerResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerFriendsResponseDoc"
                         }
                     },
                     "400": {
@@ -10041,15 +10293,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/likes": {
-            "get": {
+        "/api/v1/players/{id}/friends/blocks/{blocked_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player likes",
+                "summary": "Remove a commander from a player's blacklist",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10057,56 +10309,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.PlayerLikesResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Players"
-                ],
-                "summary": "Add player like",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
+                        "description": "Blocked commander ID",
+                        "name": "blocked_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Like request",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerLikeRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -10137,7 +10346,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/likes/{group_id}": {
+        "/api/v1/players/{id}/friends/{friend_id}": {
             "delete": {
                 "produces": [
                     "application/json"
@@ -10145,7 +10354,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player like",
+                "summary": "Remove a friend from a player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10156,8 +10365,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Group ID",
-                        "name": "group_id",
+                        "description": "Friend commander ID",
+                        "name": "friend_id",
                         "in": "path",
                         "required": true
                     }
@@ -10190,15 +10399,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/livingarea-covers": {
-            "get": {
+        "/api/v1/players/{id}/give-item": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player living area covers",
+                "summary": "Give item to player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10206,13 +10418,28 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Item grant",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GiveItemRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerLivingAreaCoverResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -10228,7 +10455,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/players/{id}/give-ship": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -10239,7 +10468,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player living area cover",
+                "summary": "Give ship to player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10249,12 +10478,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Cover request",
+                        "description": "Ship grant",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerLivingAreaCoverRequest"
+                            "$ref": "#/definitions/types.GiveShipRequest"
                         }
                     }
                 ],
@@ -10286,8 +10515,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/livingarea-covers/selected": {
-            "patch": {
+        "/api/v1/players/{id}/give-skin": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -10297,7 +10526,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player living area cover selection",
+                "summary": "Give skin to player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10307,18 +10536,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Cover selection",
+                        "description": "Skin grant",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerLivingAreaCoverSelectRequest"
+                            "$ref": "#/definitions/types.GiveSkinRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
                             "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
@@ -10344,15 +10573,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/livingarea-covers/{cover_id}": {
-            "delete": {
+        "/api/v1/players/{id}/guide": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player living area cover",
+                "summary": "Get player guide indices",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10360,26 +10589,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Cover ID",
-                        "name": "cover_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuideResponseDoc"
                         }
                     },
                     "404": {
@@ -10406,7 +10622,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player living area cover",
+                "summary": "Update player guide indices",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10416,19 +10632,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Cover ID",
-                        "name": "cover_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Cover update",
+                        "description": "Guide update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerLivingAreaCoverUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerGuideUpdateRequest"
                         }
                     }
                 ],
@@ -10460,7 +10669,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/love-letter": {
+        "/api/v1/players/{id}/guild-shop": {
             "get": {
                 "produces": [
                     "application/json"
@@ -10468,7 +10677,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player love letter state",
+                "summary": "Get player guild shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10482,13 +10691,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerLoveLetterStateResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
                         }
                     },
                     "404": {
@@ -10505,14 +10708,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player love letter state",
+                "summary": "Update player guild shop state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10520,13 +10726,22 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "State updates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GuildShopUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
                         }
                     },
                     "400": {
@@ -10548,18 +10763,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/guild-shop/goods": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player love letter state",
+                "summary": "Get player guild shop goods",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10567,22 +10781,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Love letter state update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerLoveLetterStateUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerLoveLetterStateResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopGoodsResponseDoc"
                         }
                     },
                     "400": {
@@ -10604,17 +10809,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/mails": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player mails",
+                "summary": "Add player guild shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10622,13 +10828,28 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Guild shop good",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GuildShopGoodCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMailsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -10637,6 +10858,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -10646,15 +10873,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/mails/{mail_id}": {
-            "get": {
+        "/api/v1/players/{id}/guild-shop/goods/{index}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player mail",
+                "summary": "Delete player guild shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10665,8 +10892,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Mail ID",
-                        "name": "mail_id",
+                        "description": "Goods index",
+                        "name": "index",
                         "in": "path",
                         "required": true
                     }
@@ -10675,7 +10902,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMailEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
                         }
                     },
                     "400": {
@@ -10698,14 +10925,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player mail",
+                "summary": "Update player guild shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10716,17 +10946,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Mail ID",
-                        "name": "mail_id",
+                        "description": "Goods index",
+                        "name": "index",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Good updates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.GuildShopGoodPatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
                         }
                     },
                     "400": {
@@ -10748,18 +10987,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/guild-shop/refresh": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player mail",
+                "summary": "Refresh player guild shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10767,35 +11005,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Mail ID",
-                        "name": "mail_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Mail updates",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerMailUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMailEntryResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerGuildShopResponseDoc"
                         }
                     },
                     "404": {
@@ -10813,7 +11029,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/medal-shop": {
+        "/api/v1/players/{id}/items": {
             "get": {
                 "produces": [
                     "application/json"
@@ -10821,7 +11037,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player medal shop",
+                "summary": "Get player items",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10835,13 +11051,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerItemsResponseDoc"
                         }
                     },
                     "404": {
@@ -10857,18 +11067,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/items/{item_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player medal shop",
+                "summary": "Get player item",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10878,20 +11087,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "State updates",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.MedalShopUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerItemEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -10913,17 +11120,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/medal-shop/goods": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player medal shop goods",
+                "summary": "Remove player item",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10931,13 +11136,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopGoodsResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -10960,7 +11172,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -10970,7 +11182,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player medal shop good",
+                "summary": "Update player item quantity",
                 "parameters": [
                     {
                         "type": "integer",
@@ -10980,12 +11192,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Medal shop good",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item quantity update",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.MedalShopGoodCreateRequest"
+                            "$ref": "#/definitions/types.PlayerItemQuantityUpdateRequest"
                         }
                     }
                 ],
@@ -10993,7 +11212,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -11008,12 +11227,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -11023,36 +11236,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/medal-shop/goods/{index}": {
-            "delete": {
-                "produces": [
+        "/api/v1/players/{id}/juustagram/chat-groups/read": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Delete player medal shop good",
+                "summary": "Mark Juustagram chat groups read",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Goods index",
-                        "name": "index",
-                        "in": "path",
-                        "required": true
+                        "description": "Chat group IDs",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramChatReadRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -11074,48 +11292,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player medal shop good",
+                "summary": "Delete Juustagram chat group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Goods index",
-                        "name": "index",
+                        "description": "Chat Group ID",
+                        "name": "chat_group_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Good updates",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.MedalShopGoodPatchRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramChatGroupDeleteResponseDoc"
                         }
                     },
                     "400": {
@@ -11139,29 +11347,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/medal-shop/refresh": {
-            "post": {
+        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}/replies/{sequence}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Refresh player medal shop",
+                "summary": "Delete Juustagram chat reply",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Chat Group ID",
+                        "name": "chat_group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reply sequence",
+                        "name": "sequence",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramChatReplyDeleteResponseDoc"
                         }
                     },
                     "400": {
@@ -11185,29 +11407,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/minigame-shop": {
-            "get": {
+        "/api/v1/players/{id}/juustagram/chat-groups/{chat_group_id}/reply": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player minigame shop",
+                "summary": "Reply to Juustagram chat group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Chat Group ID",
+                        "name": "chat_group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chat reply",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramChatReplyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -11223,41 +11470,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/juustagram/discuss/{message_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player minigame shop state",
+                "summary": "List Juustagram discuss selections",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "State updates",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.MiniGameShopUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramPlayerDiscussListResponseDoc"
                         }
                     },
                     "400": {
@@ -11281,29 +11525,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/minigame-shop/goods": {
-            "get": {
+        "/api/v1/players/{id}/juustagram/discuss/{message_id}/{discuss_id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player minigame shop goods",
+                "summary": "Update Juustagram discuss selection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Discuss ID",
+                        "name": "discuss_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discuss selection",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramPlayerDiscussUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopGoodsResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramPlayerDiscussResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -11320,40 +11596,42 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Add player minigame shop good",
+                "summary": "Delete Juustagram discuss selection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Minigame shop good",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.MiniGameShopGoodCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Discuss ID",
+                        "name": "discuss_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -11368,12 +11646,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -11383,36 +11655,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/minigame-shop/goods/{goods_id}": {
-            "delete": {
+        "/api/v1/players/{id}/juustagram/groups": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Delete player minigame shop good",
+                "summary": "List Juustagram chat groups",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Goods ID",
-                        "name": "goods_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupListResponseDoc"
                         }
                     },
                     "400": {
@@ -11435,7 +11712,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -11443,31 +11720,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player minigame shop good",
+                "summary": "Create Juustagram group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Goods ID",
-                        "name": "goods_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Good updates",
-                        "name": "body",
+                        "description": "Juustagram group",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.MiniGameShopGoodPatchRequest"
+                            "$ref": "#/definitions/types.JuustagramGroupCreateRequest"
                         }
                     }
                 ],
@@ -11475,7 +11745,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
                         }
                     },
                     "400": {
@@ -11499,60 +11769,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/minigame-shop/refresh": {
-            "post": {
+        "/api/v1/players/{id}/juustagram/groups/{group_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Refresh player minigame shop",
+                "summary": "Get Juustagram chat group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/players/{id}/misc-items": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Players"
-                ],
-                "summary": "Get player misc items",
-                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
-                        "name": "id",
+                        "description": "Group ID",
+                        "name": "group_id",
                         "in": "path",
                         "required": true
                     }
@@ -11561,7 +11798,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiscItemsResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -11577,29 +11820,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/misc-items/{item_id}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player misc item",
+                "summary": "Delete Juustagram group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
+                        "description": "Group ID",
+                        "name": "group_id",
                         "in": "path",
                         "required": true
                     }
@@ -11608,7 +11849,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerMiscItemEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupDeleteResponseDoc"
                         }
                     },
                     "400": {
@@ -11631,7 +11872,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -11639,31 +11880,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player misc item",
+                "summary": "Update Juustagram group settings",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
+                        "description": "Group ID",
+                        "name": "group_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Misc item update",
+                        "description": "Juustagram group update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerMiscItemUpdateRequest"
+                            "$ref": "#/definitions/types.JuustagramGroupUpdateRequest"
                         }
                     }
                 ],
@@ -11671,7 +11912,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
                         }
                     },
                     "400": {
@@ -11693,36 +11934,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/juustagram/groups/{group_id}/chat-groups": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Remove player misc item",
+                "summary": "Activate Juustagram chat group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
+                        "description": "Group ID",
+                        "name": "group_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Chat group",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramChatGroupCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramGroupResponseDoc"
                         }
                     },
                     "400": {
@@ -11746,19 +12001,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/punishments": {
+        "/api/v1/players/{id}/juustagram/message-states": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player punishments",
+                "summary": "List Juustagram message states",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -11768,7 +12023,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerPunishmentsResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageStateListResponseDoc"
                         }
                     },
                     "400": {
@@ -11790,41 +12045,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/juustagram/message-states/{message_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Create punishment for player",
+                "summary": "Get Juustagram message state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Punishment request",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.BanPlayerRequest"
-                        }
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageStateResponseDoc"
                         }
                     },
                     "400": {
@@ -11846,38 +12098,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/punishments/{punishment_id}": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player punishment",
+                "summary": "Update Juustagram message state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Punishment ID",
-                        "name": "punishment_id",
+                        "description": "Message ID",
+                        "name": "message_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Message state update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.JuustagramMessageStateUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerPunishmentEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageStateResponseDoc"
                         }
                     },
                     "400": {
@@ -11905,21 +12167,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Delete player punishment",
+                "summary": "Delete Juustagram message state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Punishment ID",
-                        "name": "punishment_id",
+                        "description": "Message ID",
+                        "name": "message_id",
                         "in": "path",
                         "required": true
                     }
@@ -11950,48 +12212,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/juustagram/messages": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player punishment",
+                "summary": "List Juustagram messages",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Punishment ID",
-                        "name": "punishment_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
                     },
                     {
-                        "description": "Punishment update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerPunishmentUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageListResponseDoc"
                         }
                     },
                     "400": {
@@ -12015,29 +12272,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/random-flagship": {
+        "/api/v1/players/{id}/juustagram/messages/{message_id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player random flagship toggle",
+                "summary": "Get Juustagram message",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -12045,6 +12315,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     }
                 }
             },
@@ -12056,24 +12332,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player random flagship toggle",
+                "summary": "Update Juustagram message state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Random flagship request",
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerRandomFlagShipRequest"
+                            "$ref": "#/definitions/types.JuustagramMessageUpdateRequest"
                         }
                     }
                 ],
@@ -12081,7 +12364,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
                         }
                     },
                     "400": {
@@ -12105,29 +12388,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/random-flagship-mode": {
+        "/api/v1/players/{id}/juustagram/messages/{message_id}/discuss": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Get player random flagship mode",
+                "summary": "Get Juustagram message discuss options",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipModeResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramDiscussResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -12135,10 +12431,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -12146,24 +12448,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Juustagram"
                 ],
-                "summary": "Update player random flagship mode",
+                "summary": "Reply to Juustagram message",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Player ID",
+                        "description": "Commander ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Random flagship mode request",
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discuss reply",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerRandomFlagShipModeRequest"
+                            "$ref": "#/definitions/types.JuustagramDiscussRequest"
                         }
                     }
                 ],
@@ -12171,7 +12480,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.JuustagramMessageResponseDoc"
                         }
                     },
                     "400": {
@@ -12195,15 +12504,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/random-flagships": {
-            "get": {
+        "/api/v1/players/{id}/kick": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player random flagships",
+                "summary": "Kick player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12213,17 +12525,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Filter by ship ID",
-                        "name": "ship_id",
-                        "in": "query"
+                        "description": "Kick request",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.KickPlayerRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipListResponseDoc"
+                            "$ref": "#/definitions/handlers.KickPlayerResponseDoc"
                         }
                     },
                     "400": {
@@ -12245,6 +12559,46 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/api/v1/players/{id}/likes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player likes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerLikesResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
             },
             "post": {
                 "consumes": [
@@ -12256,7 +12610,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Upsert player random flagship",
+                "summary": "Add player like",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12266,12 +12620,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Random flagship upsert",
+                        "description": "Like request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerRandomFlagShipUpsertRequest"
+                            "$ref": "#/definitions/types.PlayerLikeRequest"
                         }
                     }
                 ],
@@ -12303,7 +12657,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/random-flagships/{ship_id}/{phantom_id}": {
+        "/api/v1/players/{id}/likes/{group_id}": {
             "delete": {
                 "produces": [
                     "application/json"
@@ -12311,7 +12665,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player random flagship",
+                "summary": "Remove player like",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12322,15 +12676,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Ship ID",
-                        "name": "ship_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Phantom ID",
-                        "name": "phantom_id",
+                        "description": "Group ID",
+                        "name": "group_id",
                         "in": "path",
                         "required": true
                     }
@@ -12363,7 +12710,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/remaster": {
+        "/api/v1/players/{id}/livingarea-covers": {
             "get": {
                 "produces": [
                     "application/json"
@@ -12371,7 +12718,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player remaster state",
+                "summary": "Get player living area covers",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12385,13 +12732,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRemasterStateResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerLivingAreaCoverResponseDoc"
                         }
                     },
                     "404": {
@@ -12408,7 +12749,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -12418,7 +12759,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player remaster state",
+                "summary": "Add player living area cover",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12428,12 +12769,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Remaster state update",
+                        "description": "Cover request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerRemasterStateUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerLivingAreaCoverRequest"
                         }
                     }
                 ],
@@ -12441,7 +12782,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRemasterStateResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -12465,15 +12806,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/remaster/progress": {
-            "get": {
+        "/api/v1/players/{id}/livingarea-covers/selected": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player remaster progress",
+                "summary": "Update player living area cover selection",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12483,23 +12827,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Filter by chapter ID",
-                        "name": "chapter_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by received",
-                        "name": "received",
-                        "in": "query"
+                        "description": "Cover selection",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerLivingAreaCoverSelectRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerRemasterProgressResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -12521,18 +12862,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/livingarea-covers/{cover_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create or update player remaster progress",
+                "summary": "Remove player living area cover",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12542,13 +12882,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Remaster progress",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerRemasterProgressCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Cover ID",
+                        "name": "cover_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -12577,17 +12915,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/remaster/progress/{chapter_id}/{pos}": {
-            "delete": {
-                "produces": [
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player remaster progress",
+                "summary": "Update player living area cover",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12598,17 +12937,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Chapter ID",
-                        "name": "chapter_id",
+                        "description": "Cover ID",
+                        "name": "cover_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Reward position",
-                        "name": "pos",
-                        "in": "path",
-                        "required": true
+                        "description": "Cover update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerLivingAreaCoverUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -12637,18 +12978,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/love-letter": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player remaster progress",
+                "summary": "Get player love letter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12656,36 +12996,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Chapter ID",
-                        "name": "chapter_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Reward position",
-                        "name": "pos",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Remaster progress update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerRemasterProgressUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerLoveLetterStateResponseDoc"
                         }
                     },
                     "400": {
@@ -12707,17 +13024,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/resources": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player resources",
+                "summary": "Delete player love letter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12731,7 +13046,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerResourcesResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -12748,7 +13069,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -12758,7 +13079,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player resources",
+                "summary": "Update player love letter state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12768,12 +13089,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Resource update",
+                        "description": "Love letter state update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ResourceUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerLoveLetterStateUpdateRequest"
                         }
                     }
                 ],
@@ -12781,7 +13102,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerLoveLetterStateResponseDoc"
                         }
                     },
                     "400": {
@@ -12805,7 +13126,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/resources/{resource_id}": {
+        "/api/v1/players/{id}/mails": {
             "get": {
                 "produces": [
                     "application/json"
@@ -12813,7 +13134,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player resource",
+                "summary": "Get player mails",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12821,26 +13142,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Resource ID",
-                        "name": "resource_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerResourceEntryResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMailsResponseDoc"
                         }
                     },
                     "404": {
@@ -12856,15 +13164,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/mails/{mail_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player resource",
+                "summary": "Get player mail",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12875,8 +13185,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Resource ID",
-                        "name": "resource_id",
+                        "description": "Mail ID",
+                        "name": "mail_id",
                         "in": "path",
                         "required": true
                     }
@@ -12885,7 +13195,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMailEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -12907,17 +13217,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/secretaries": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player secretaries",
+                "summary": "Remove player mail",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12925,13 +13233,26 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Mail ID",
+                        "name": "mail_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSecretariesResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -12948,7 +13269,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -12958,7 +13279,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Replace player secretaries",
+                "summary": "Update player mail",
                 "parameters": [
                     {
                         "type": "integer",
@@ -12968,12 +13289,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Secretary replace",
+                        "type": "integer",
+                        "description": "Mail ID",
+                        "name": "mail_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mail updates",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerSecretariesReplaceRequest"
+                            "$ref": "#/definitions/types.PlayerMailUpdateRequest"
                         }
                     }
                 ],
@@ -12981,7 +13309,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMailEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -13003,15 +13331,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/medal-shop": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player secretaries",
+                "summary": "Get player medal shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13025,7 +13355,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -13041,10 +13377,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/send-mail": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -13054,7 +13388,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Send mail to player",
+                "summary": "Update player medal shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13064,12 +13398,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Mail request",
-                        "name": "payload",
+                        "description": "State updates",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.SendMailRequest"
+                            "$ref": "#/definitions/types.MedalShopUpdateRequest"
                         }
                     }
                 ],
@@ -13077,7 +13411,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13101,7 +13435,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/ships": {
+        "/api/v1/players/{id}/medal-shop/goods": {
             "get": {
                 "produces": [
                     "application/json"
@@ -13109,7 +13443,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player ships",
+                "summary": "Get player medal shop goods",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13123,7 +13457,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShipsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopGoodsResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -13150,7 +13490,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create player ship",
+                "summary": "Add player medal shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13160,12 +13500,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Owned ship create",
-                        "name": "payload",
+                        "description": "Medal shop good",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerShipCreateRequest"
+                            "$ref": "#/definitions/types.MedalShopGoodCreateRequest"
                         }
                     }
                 ],
@@ -13173,7 +13513,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13188,6 +13528,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -13197,15 +13543,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/ships/{owned_id}": {
-            "get": {
+        "/api/v1/players/{id}/medal-shop/goods/{index}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player ship",
+                "summary": "Delete player medal shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13216,8 +13562,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Owned ship ID",
-                        "name": "owned_id",
+                        "description": "Goods index",
+                        "name": "index",
                         "in": "path",
                         "required": true
                     }
@@ -13226,7 +13572,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13249,14 +13595,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player ship",
+                "summary": "Update player medal shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13267,17 +13616,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Owned ship ID",
-                        "name": "owned_id",
+                        "description": "Goods index",
+                        "name": "index",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Good updates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.MedalShopGoodPatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13299,18 +13657,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/medal-shop/refresh": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player ship",
+                "summary": "Refresh player medal shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13318,29 +13675,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Owned ship ID",
-                        "name": "owned_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Owned ship updates",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerShipUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMedalShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13364,7 +13705,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/ships/{owned_id}/equipment": {
+        "/api/v1/players/{id}/meowfficers": {
             "get": {
                 "produces": [
                     "application/json"
@@ -13372,7 +13713,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player ship equipment slots",
+                "summary": "Get player meowfficers, meowfficer boxes and cat lodge",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13380,20 +13721,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Owned ship ID",
-                        "name": "owned_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShipEquipmentResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMeowfficersResponseDoc"
                         }
                     },
                     "400": {
@@ -13416,7 +13750,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -13426,7 +13760,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player ship equipment slots",
+                "summary": "Grant a meowfficer to a player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13436,19 +13770,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Owned ship ID",
-                        "name": "owned_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Ship equipment update",
+                        "description": "Meowfficer template",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerShipEquipmentUpdateRequest"
+                            "$ref": "#/definitions/types.PlayerMeowfficerCreateRequest"
                         }
                     }
                 ],
@@ -13456,7 +13783,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMeowfficersResponseDoc"
                         }
                     },
                     "400": {
@@ -13480,15 +13807,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/shopping-street": {
-            "get": {
+        "/api/v1/players/{id}/meowfficers/boxes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player shopping street",
+                "summary": "Grant a meowfficer box to a player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13498,17 +13828,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Include offer metadata",
-                        "name": "include_offers",
-                        "in": "query"
+                        "description": "Box pool",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerMeowfficerBoxRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMeowfficersResponseDoc"
                         }
                     },
                     "400": {
@@ -13530,18 +13863,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/meowfficers/{meowfficer_id}": {
+            "delete": {
+                "description": "Meowfficers assigned to a fleet or training in the cat lodge can't be deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player shopping street state",
+                "summary": "Delete a player meowfficer",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13551,20 +13884,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "State updates",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ShoppingStreetUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Meowfficer ID",
+                        "name": "meowfficer_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -13579,6 +13910,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -13587,14 +13924,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Clear player shopping street",
+                "summary": "Update a player meowfficer",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13602,13 +13942,35 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Meowfficer ID",
+                        "name": "meowfficer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meowfficer fields",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerMeowfficerUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMeowfficersResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -13626,7 +13988,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/shopping-street/goods": {
+        "/api/v1/players/{id}/minigame-shop": {
             "get": {
                 "produces": [
                     "application/json"
@@ -13634,7 +13996,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player shopping street goods",
+                "summary": "Get player minigame shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13648,7 +14010,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetGoodsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "404": {
@@ -13675,7 +14037,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Replace player shopping street goods",
+                "summary": "Update player minigame shop state",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13685,12 +14047,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Goods list",
+                        "description": "State updates",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ShoppingStreetGoodsReplaceRequest"
+                            "$ref": "#/definitions/types.MiniGameShopUpdateRequest"
                         }
                     }
                 ],
@@ -13698,7 +14060,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13720,18 +14082,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/minigame-shop/goods": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player shopping street good",
+                "summary": "Get player minigame shop goods",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13739,28 +14100,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Shopping street good",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ShoppingStreetGoodCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopGoodsResponseDoc"
                         }
                     },
                     "404": {
@@ -13769,12 +14115,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -13782,17 +14122,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/shopping-street/goods/{goods_id}": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player shopping street good",
+                "summary": "Add player minigame shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13802,18 +14143,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Goods ID",
-                        "name": "goods_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Minigame shop good",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.MiniGameShopGoodCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetGoodResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13828,6 +14171,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -13835,7 +14184,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/players/{id}/minigame-shop/goods/{goods_id}": {
             "delete": {
                 "produces": [
                     "application/json"
@@ -13843,7 +14194,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player shopping street good",
+                "summary": "Delete player minigame shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13864,7 +14215,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13897,7 +14248,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player shopping street good",
+                "summary": "Update player minigame shop good",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13919,7 +14270,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ShoppingStreetGoodPatchRequest"
+                            "$ref": "#/definitions/types.MiniGameShopGoodPatchRequest"
                         }
                     }
                 ],
@@ -13927,7 +14278,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "400": {
@@ -13951,18 +14302,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/shopping-street/refresh": {
+        "/api/v1/players/{id}/minigame-shop/refresh": {
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Refresh player shopping street",
+                "summary": "Refresh player minigame shop",
                 "parameters": [
                     {
                         "type": "integer",
@@ -13970,27 +14318,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Refresh settings",
-                        "name": "body",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/types.ShoppingStreetRefreshRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiniGameShopResponseDoc"
                         }
                     },
                     "404": {
@@ -14008,7 +14342,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/skins": {
+        "/api/v1/players/{id}/misc-items": {
             "get": {
                 "produces": [
                     "application/json"
@@ -14016,7 +14350,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player skins",
+                "summary": "Get player misc items",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14030,7 +14364,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSkinsResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiscItemsResponseDoc"
                         }
                     },
                     "404": {
@@ -14048,7 +14382,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/skins/{skin_id}": {
+        "/api/v1/players/{id}/misc-items/{item_id}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -14056,7 +14390,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player skin",
+                "summary": "Get player misc item",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14067,8 +14401,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Skin ID",
-                        "name": "skin_id",
+                        "description": "Item ID",
+                        "name": "item_id",
                         "in": "path",
                         "required": true
                     }
@@ -14077,7 +14411,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSkinEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerMiscItemEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -14100,14 +14434,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player skin",
+                "summary": "Update player misc item",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14118,10 +14455,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Skin ID",
-                        "name": "skin_id",
+                        "description": "Item ID",
+                        "name": "item_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Misc item update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerMiscItemUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -14151,17 +14497,14 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player skin",
+                "summary": "Remove player misc item",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14172,26 +14515,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Skin ID",
-                        "name": "skin_id",
+                        "description": "Item ID",
+                        "name": "item_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Skin update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.PlayerSkinUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSkinEntryResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -14215,7 +14549,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/stories": {
+        "/api/v1/players/{id}/punishments": {
             "get": {
                 "produces": [
                     "application/json"
@@ -14223,7 +14557,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player story progress",
+                "summary": "Get player punishments",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14237,7 +14571,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerStoriesResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerPunishmentsResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -14264,7 +14604,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player story progress",
+                "summary": "Create punishment for player",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14274,12 +14614,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Story request",
+                        "description": "Punishment request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.PlayerStoryRequest"
+                            "$ref": "#/definitions/types.BanPlayerRequest"
                         }
                     }
                 ],
@@ -14311,15 +14651,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/stories/{story_id}": {
-            "put": {
+        "/api/v1/players/{id}/punishments/{punishment_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Add player story progress",
+                "summary": "Get player punishment",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14330,8 +14670,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Story ID",
-                        "name": "story_id",
+                        "description": "Punishment ID",
+                        "name": "punishment_id",
                         "in": "path",
                         "required": true
                     }
@@ -14340,7 +14680,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerPunishmentEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -14370,7 +14710,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Remove player story progress",
+                "summary": "Delete player punishment",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14381,8 +14721,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Story ID",
-                        "name": "story_id",
+                        "description": "Punishment ID",
+                        "name": "punishment_id",
                         "in": "path",
                         "required": true
                     }
@@ -14413,17 +14753,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/players/{id}/support-requisition": {
-            "get": {
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player support requisition counters",
+                "summary": "Update player punishment",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14431,13 +14772,35 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Punishment ID",
+                        "name": "punishment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Punishment update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerPunishmentUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSupportRequisitionResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -14455,15 +14818,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/support-requisition/reset": {
-            "post": {
+        "/api/v1/players/{id}/random-flagship": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Reset player support requisition counters",
+                "summary": "Get player random flagship toggle",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14477,7 +14840,57 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PlayerSupportRequisitionResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player random flagship toggle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Random flagship request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerRandomFlagShipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -14495,7 +14908,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/players/{id}/tb": {
+        "/api/v1/players/{id}/random-flagship-mode": {
             "get": {
                 "produces": [
                     "application/json"
@@ -14503,7 +14916,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Get player TB state",
+                "summary": "Get player random flagship mode",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14517,7 +14930,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipModeResponseDoc"
                         }
                     },
                     "404": {
@@ -14525,16 +14938,10 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -14544,7 +14951,7 @@ const docTemplate = `{
                 "tags": [
                     "Players"
                 ],
-                "summary": "Update player TB state",
+                "summary": "Update player random flagship mode",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14554,12 +14961,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "TB payload",
-                        "name": "body",
+                        "description": "Random flagship mode request",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CommanderTBRequest"
+                            "$ref": "#/definitions/types.PlayerRandomFlagShipModeRequest"
                         }
                     }
                 ],
@@ -14567,7 +14974,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -14589,18 +14996,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/players/{id}/random-flagships": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Create player TB state",
+                "summary": "Get player random flagships",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14610,20 +15016,17 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "TB payload",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.CommanderTBRequest"
-                        }
+                        "type": "integer",
+                        "description": "Filter by ship ID",
+                        "name": "ship_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRandomFlagShipListResponseDoc"
                         }
                     },
                     "400": {
@@ -14638,12 +15041,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -14652,14 +15049,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Players"
                 ],
-                "summary": "Delete player TB state",
+                "summary": "Upsert player random flagship",
                 "parameters": [
                     {
                         "type": "integer",
@@ -14667,6 +15067,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Random flagship upsert",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerRandomFlagShipUpsertRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -14676,6 +15085,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -14691,34 +15106,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/rarities": {
-            "get": {
+        "/api/v1/players/{id}/random-flagships/{ship_id}/{phantom_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rarities"
+                    "Players"
                 ],
-                "summary": "List rarities",
+                "summary": "Remove player random flagship",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Ship ID",
+                        "name": "ship_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Phantom ID",
+                        "name": "phantom_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListRaritiesResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -14727,45 +15151,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rarities"
-                ],
-                "summary": "Create rarity",
-                "parameters": [
-                    {
-                        "description": "Rarity",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.RaritySummary"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -14779,19 +15166,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/rarities/{id}": {
+        "/api/v1/players/{id}/remaster": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rarities"
+                    "Players"
                 ],
-                "summary": "Get rarity details",
+                "summary": "Get player remaster state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Rarity ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -14801,7 +15188,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RaritySummaryResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRemasterStateResponseDoc"
                         }
                     },
                     "400": {
@@ -14824,7 +15211,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -14832,24 +15219,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rarities"
+                    "Players"
                 ],
-                "summary": "Update rarity",
+                "summary": "Update player remaster state",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Rarity ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Rarity",
+                        "description": "Remaster state update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.RaritySummary"
+                            "$ref": "#/definitions/types.PlayerRemasterStateUpdateRequest"
                         }
                     }
                 ],
@@ -14857,7 +15244,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRemasterStateResponseDoc"
                         }
                     },
                     "400": {
@@ -14879,29 +15266,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/players/{id}/remaster/progress": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rarities"
+                    "Players"
                 ],
-                "summary": "Delete rarity",
+                "summary": "Get player remaster progress",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Rarity ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by chapter ID",
+                        "name": "chapter_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by received",
+                        "name": "received",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerRemasterProgressResponseDoc"
                         }
                     },
                     "400": {
@@ -14923,9 +15324,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/registration/challenges": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -14934,17 +15333,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Registration"
+                    "Players"
                 ],
-                "summary": "Create registration challenge",
+                "summary": "Create or update player remaster progress",
                 "parameters": [
                     {
-                        "description": "Challenge payload",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remaster progress",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UserRegistrationChallengeRequest"
+                            "$ref": "#/definitions/types.PlayerRemasterProgressCreateRequest"
                         }
                     }
                 ],
@@ -14952,7 +15358,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserRegistrationChallengeResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -14967,14 +15373,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
-                    "429": {
-                        "description": "Too Many Requests",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -14982,29 +15382,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/registration/challenges/{id}": {
-            "get": {
+        "/api/v1/players/{id}/remaster/progress/{chapter_id}/{pos}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Registration"
+                    "Players"
                 ],
-                "summary": "Get registration challenge status",
+                "summary": "Delete player remaster progress",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Challenge ID",
+                        "type": "integer",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Chapter ID",
+                        "name": "chapter_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reward position",
+                        "name": "pos",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserRegistrationStatusResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "404": {
@@ -15020,10 +15440,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/registration/challenges/{id}/verify": {
-            "post": {
+            },
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -15031,24 +15449,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Registration"
+                    "Players"
                 ],
-                "summary": "Verify registration challenge",
+                "summary": "Update player remaster progress",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Challenge ID",
+                        "type": "integer",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Verify payload",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Chapter ID",
+                        "name": "chapter_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Reward position",
+                        "name": "pos",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remaster progress update",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UserRegistrationVerifyRequest"
+                            "$ref": "#/definitions/types.PlayerRemasterProgressUpdateRequest"
                         }
                     }
                 ],
@@ -15056,7 +15488,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserRegistrationStatusResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -15071,12 +15503,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -15086,20 +15512,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/requisition-ships": {
+        "/api/v1/players/{id}/resources": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "RequisitionShips"
+                    "Players"
+                ],
+                "summary": "Get player resources",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List requisition ships",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RequisitionShipListResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerResourcesResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -15110,7 +15551,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -15118,17 +15559,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "RequisitionShips"
+                    "Players"
                 ],
-                "summary": "Create requisition ship",
+                "summary": "Update player resources",
                 "parameters": [
                     {
-                        "description": "Requisition ship",
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource update",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.RequisitionShipRequest"
+                            "$ref": "#/definitions/types.ResourceUpdateRequest"
                         }
                     }
                 ],
@@ -15136,7 +15584,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RequisitionShipMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -15145,6 +15593,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -15154,20 +15608,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/requisition-ships/{ship_id}": {
-            "delete": {
+        "/api/v1/players/{id}/resources/{resource_id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "RequisitionShips"
+                    "Players"
                 ],
-                "summary": "Delete requisition ship",
+                "summary": "Get player resource",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ship ID",
-                        "name": "ship_id",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "resource_id",
                         "in": "path",
                         "required": true
                     }
@@ -15176,7 +15637,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RequisitionShipMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerResourceEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -15198,36 +15659,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/resources": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Resources"
+                    "Players"
                 ],
-                "summary": "List resources",
+                "summary": "Remove player resource",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Resource ID",
+                        "name": "resource_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListResourcesResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -15236,45 +15697,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Resources"
-                ],
-                "summary": "Create resource",
-                "parameters": [
-                    {
-                        "description": "Resource",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.ResourceCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
@@ -15288,19 +15712,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/resources/{id}": {
+        "/api/v1/players/{id}/secretaries": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Resources"
+                    "Players"
                 ],
-                "summary": "Get resource details",
+                "summary": "Get player secretaries",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -15310,13 +15734,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResourceSummaryResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerSecretariesResponseDoc"
                         }
                     },
                     "404": {
@@ -15341,24 +15759,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resources"
+                    "Players"
                 ],
-                "summary": "Update resource",
+                "summary": "Replace player secretaries",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Resource",
+                        "description": "Secretary replace",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ResourceUpdatePayload"
+                            "$ref": "#/definitions/types.PlayerSecretariesReplaceRequest"
                         }
                     }
                 ],
@@ -15366,7 +15784,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "400": {
@@ -15394,13 +15812,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resources"
+                    "Players"
                 ],
-                "summary": "Delete resource",
+                "summary": "Remove player secretaries",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Resource ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -15410,13 +15828,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
                         }
                     },
                     "404": {
@@ -15434,25 +15846,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/server/config": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Server"
-                ],
-                "summary": "Get server config",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ServerConfigResponseDoc"
-                        }
-                    }
-                }
-            },
-            "put": {
+        "/api/v1/players/{id}/send-mail": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -15460,17 +15855,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Server"
+                    "Players"
                 ],
-                "summary": "Update server config",
+                "summary": "Send mail to player",
                 "parameters": [
                     {
-                        "description": "Server config",
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mail request",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.ServerConfigUpdate"
+                            "$ref": "#/definitions/types.SendMailRequest"
                         }
                     }
                 ],
@@ -15486,42 +15888,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/server/connections": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Server"
-                ],
-                "summary": "List active connections",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConnectionListResponseDoc"
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     }
                 }
             }
         },
-        "/api/v1/server/connections/{id}": {
+        "/api/v1/players/{id}/ships": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server"
+                    "Players"
                 ],
-                "summary": "Get connection details",
+                "summary": "Get player ships",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Connection ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -15531,45 +15926,57 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ConnectionDetailResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerShipsResponseDoc"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server"
+                    "Players"
                 ],
-                "summary": "Disconnect connection",
+                "summary": "Create player ship",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Connection ID",
+                        "description": "Player ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Owned ship create",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerShipCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
                         }
                     },
                     "400": {
@@ -15583,23 +15990,2510 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.APIErrorResponseDoc"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/server/maintenance": {
+        "/api/v1/players/{id}/ships/{owned_id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server"
+                    "Players"
                 ],
-                "summary": "Get maintenance status",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
+                "summary": "Get player ship",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owned ship ID",
+                        "name": "owned_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Remove player ship",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owned ship ID",
+                        "name": "owned_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player ship",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owned ship ID",
+                        "name": "owned_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Owned ship updates",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerShipUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerOwnedShipEntryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/ships/{owned_id}/equipment": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player ship equipment slots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owned ship ID",
+                        "name": "owned_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShipEquipmentResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player ship equipment slots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owned ship ID",
+                        "name": "owned_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ship equipment update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerShipEquipmentUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/shopping-street": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player shopping street",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include offer metadata",
+                        "name": "include_offers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player shopping street state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "State updates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ShoppingStreetUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Clear player shopping street",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/shopping-street/goods": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player shopping street goods",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetGoodsResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Replace player shopping street goods",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Goods list",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ShoppingStreetGoodsReplaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Add player shopping street good",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shopping street good",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ShoppingStreetGoodCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/shopping-street/goods/{goods_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player shopping street good",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Goods ID",
+                        "name": "goods_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetGoodResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Delete player shopping street good",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Goods ID",
+                        "name": "goods_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player shopping street good",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Goods ID",
+                        "name": "goods_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Good updates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ShoppingStreetGoodPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/shopping-street/refresh": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Refresh player shopping street",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refresh settings",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.ShoppingStreetRefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerShoppingStreetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/skins": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player skins",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerSkinsResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/skins/{skin_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player skin",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skin ID",
+                        "name": "skin_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerSkinEntryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Remove player skin",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skin ID",
+                        "name": "skin_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player skin",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skin ID",
+                        "name": "skin_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Skin update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerSkinUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerSkinEntryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/stories": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player story progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerStoriesResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Add player story progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Story request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PlayerStoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/stories/{story_id}": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Add player story progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Story ID",
+                        "name": "story_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Remove player story progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Story ID",
+                        "name": "story_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/support-requisition": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player support requisition counters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerSupportRequisitionResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/support-requisition/reset": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Reset player support requisition counters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerSupportRequisitionResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/tb": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player TB state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update player TB state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "TB payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CommanderTBRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Create player TB state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "TB payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CommanderTBRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommanderTBResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Delete player TB state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/players/{id}/world": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get player Operation Siren state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlayerWorldResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Maps, fleets, items and port purchases are wiped; the player has to enter the world again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Reset player Operation Siren state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rarities": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rarities"
+                ],
+                "summary": "List rarities",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListRaritiesResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rarities"
+                ],
+                "summary": "Create rarity",
+                "parameters": [
+                    {
+                        "description": "Rarity",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RaritySummary"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rarities/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rarities"
+                ],
+                "summary": "Get rarity details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rarity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RaritySummaryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rarities"
+                ],
+                "summary": "Update rarity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rarity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rarity",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RaritySummary"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rarities"
+                ],
+                "summary": "Delete rarity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rarity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RarityMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/registration/challenges": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Create registration challenge",
+                "parameters": [
+                    {
+                        "description": "Challenge payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UserRegistrationChallengeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRegistrationChallengeResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/registration/challenges/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Get registration challenge status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRegistrationStatusResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/registration/challenges/{id}/verify": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Verify registration challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verify payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UserRegistrationVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRegistrationStatusResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/requisition-ships": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RequisitionShips"
+                ],
+                "summary": "List requisition ships",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequisitionShipListResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RequisitionShips"
+                ],
+                "summary": "Create requisition ship",
+                "parameters": [
+                    {
+                        "description": "Requisition ship",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RequisitionShipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequisitionShipMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/requisition-ships/{ship_id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RequisitionShips"
+                ],
+                "summary": "Delete requisition ship",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ship ID",
+                        "name": "ship_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequisitionShipMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/resources": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "List resources",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListResourcesResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Create resource",
+                "parameters": [
+                    {
+                        "description": "Resource",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ResourceCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/resources/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Get resource details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResourceSummaryResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Update resource",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ResourceUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resources"
+                ],
+                "summary": "Delete resource",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Resource ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResourceMutationResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/server/config": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Get server config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ServerConfigResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Update server config",
+                "parameters": [
+                    {
+                        "description": "Server config",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ServerConfigUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/server/connections": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "List active connections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConnectionListResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/server/connections/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Get connection details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConnectionDetailResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Disconnect connection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Connection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OKResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/server/maintenance": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Get maintenance status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
                             "$ref": "#/definitions/handlers.ServerMaintenanceResponseDoc"
                         }
                     }
@@ -17453,6 +20347,124 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/theme-previews/{commander_id}/{md5}": {
+            "get": {
+                "description": "Serves a preview kept by the local storage provider. Other providers serve previews from their bucket.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "ThemePreviews"
+                ],
+                "summary": "Download backyard theme preview",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preview md5",
+                        "name": "md5",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Stores a preview with the local storage provider. The request carries the SecurityToken of SC_19104 in the X-Oss-Security-Token header (or the token query parameter), and the body must hash to the md5 of the path.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ThemePreviews"
+                ],
+                "summary": "Upload backyard theme preview",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Commander ID",
+                        "name": "commander_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preview md5",
+                        "name": "md5",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload token",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ThemePreviewResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.APIErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/user/auth/login": {
             "post": {
                 "consumes": [
@@ -17812,116 +20824,259 @@ const docTemplate = `{
         "handlers.APIErrorResponseDoc": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/types.APIError"
+                "error": {
+                    "$ref": "#/definitions/types.APIError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.AccountOverridesResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.AccountOverridesResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.AccountRolesResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.AccountRolesResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ActivityAllowlistResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ActivityAllowlistPayload"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ActivityCalendarResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ActivityCalendarResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ActivityOverrideListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ActivityOverrideListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ActivityOverrideResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ActivityOverridePayload"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.AdminUserListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.AdminUserListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.AdminUserResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.AdminUserResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ArenaLeaderboardResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ArenaLeaderboardResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ArenaSeasonResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ArenaSeasonResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ArenaSettlementResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ArenaSettlementResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.AuthBootstrapStatusResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.AuthBootstrapStatusResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AccountOverridesResponseDoc": {
+        "handlers.AuthLoginResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AccountOverridesResponse"
+                    "$ref": "#/definitions/types.AuthLoginResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AccountRolesResponseDoc": {
+        "handlers.AuthSessionResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AccountRolesResponse"
+                    "$ref": "#/definitions/types.AuthSessionResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.ActivityAllowlistResponseDoc": {
+        "handlers.BuffDetailResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.ActivityAllowlistPayload"
+                    "$ref": "#/definitions/types.BuffPayload"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AdminUserListResponseDoc": {
+        "handlers.BuffMutationResponseDoc": {
+            "type": "object",
+            "properties": {
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.BuildBannerListResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AdminUserListResponse"
+                    "$ref": "#/definitions/types.BuildBannerListResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AdminUserResponseDoc": {
+        "handlers.BuildBannerStatsResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AdminUserResponse"
+                    "$ref": "#/definitions/types.BuildBannerStatsResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AuthBootstrapStatusResponseDoc": {
+        "handlers.BuildBannerSummaryResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AuthBootstrapStatusResponse"
+                    "$ref": "#/definitions/types.BuildBannerSummary"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AuthLoginResponseDoc": {
+        "handlers.BuildRollLogListResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AuthLoginResponse"
+                    "$ref": "#/definitions/types.BuildRollLogListResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.AuthSessionResponseDoc": {
+        "handlers.ChatMessageListResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.AuthSessionResponse"
+                    "$ref": "#/definitions/types.ChatMessageListResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.BuffDetailResponseDoc": {
+        "handlers.ChatMuteListResponseDoc": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.BuffPayload"
+                    "$ref": "#/definitions/types.ChatMuteListResponse"
                 },
                 "ok": {
                     "type": "boolean"
                 }
             }
         },
-        "handlers.BuffMutationResponseDoc": {
+        "handlers.ChatMuteResponseDoc": {
             "type": "object",
             "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ChatMute"
+                },
                 "ok": {
                     "type": "boolean"
                 }
@@ -18071,6 +21226,61 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.DropBonusListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.DropBonusListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.DropBonusResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.DropBonus"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.DropTableListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.DropTableListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.DropTableSimulationResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.DropTableSimulationResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.DropTableSummaryResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.DropTableSummary"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.EquipmentDetailResponseDoc": {
             "type": "object",
             "properties": {
@@ -18123,6 +21333,28 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.GuildDetailResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.GuildDetailResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.GuildListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.GuildListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.ItemMutationResponseDoc": {
             "type": "object",
             "properties": {
@@ -18617,6 +21849,28 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PaymentOrderListResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PaymentOrderListResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PaymentOrderResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PaymentOrder"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PermissionListResponseDoc": {
             "type": "object",
             "properties": {
@@ -18628,6 +21882,28 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerActivityProgressResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerActivityProgressResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PlayerArenaResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerArenaState"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PlayerArenaShopDeleteResponseDoc": {
             "type": "object",
             "properties": {
@@ -18658,6 +21934,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerBlueprintsResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerBlueprintsResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PlayerBuffEntryResponseDoc": {
             "type": "object",
             "properties": {
@@ -18757,6 +22044,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerCheaterMarksResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerCheaterMarksResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PlayerCompensationsResponseDoc": {
             "type": "object",
             "properties": {
@@ -18834,6 +22132,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerFriendsResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerFriendsResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PlayerGuideResponseDoc": {
             "type": "object",
             "properties": {
@@ -18966,6 +22275,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerMeowfficersResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerMeowfficersResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PlayerMiniGameShopGoodsResponseDoc": {
             "type": "object",
             "properties": {
@@ -19241,6 +22561,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.PlayerWorldResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.PlayerWorldResponse"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.PushCompensationResponseDoc": {
             "type": "object",
             "properties": {
@@ -19522,6 +22853,17 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.ThemePreviewResponseDoc": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/types.ThemePreview"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.UserAuthLoginResponseDoc": {
             "type": "object",
             "properties": {
@@ -19973,42 +23315,154 @@ const docTemplate = `{
                 }
             }
         },
-        "types.AccountRolesUpdateRequest": {
+        "types.AccountRolesUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.ActivityAllowlistPatchPayload": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ActivityAllowlistPayload": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ActivityCalendarEntry": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "extended": {
+                    "type": "boolean"
+                },
+                "force_ended": {
+                    "type": "boolean"
+                },
+                "open": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ActivityCalendarResponse": {
             "type": "object",
             "properties": {
-                "roles": {
+                "events": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/types.ActivityCalendarEntry"
                     }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "types.ActivityAllowlistPatchPayload": {
+        "types.ActivityOverrideListResponse": {
             "type": "object",
             "properties": {
-                "add": {
+                "overrides": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/types.ActivityOverridePayload"
                     }
+                }
+            }
+        },
+        "types.ActivityOverridePayload": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
                 },
-                "remove": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "activity_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
                 }
             }
         },
-        "types.ActivityAllowlistPayload": {
+        "types.ActivityOverrideRequest": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "action": {
+                    "type": "string"
+                },
+                "activity_id": {
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
                 }
             }
         },
@@ -20087,6 +23541,99 @@ const docTemplate = `{
                 }
             }
         },
+        "types.ArenaLeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ArenaLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ArenaLeaderboardEntry"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                },
+                "season_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ArenaPayout": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "merit": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ArenaSeason": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.ArenaSeasonResponse": {
+            "type": "object",
+            "properties": {
+                "season": {
+                    "$ref": "#/definitions/types.ArenaSeason"
+                }
+            }
+        },
+        "types.ArenaSettlementResponse": {
+            "type": "object",
+            "properties": {
+                "next_season_id": {
+                    "type": "integer"
+                },
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ArenaPayout"
+                    }
+                },
+                "season_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.ArenaShopItem": {
             "type": "object",
             "properties": {
@@ -20140,132 +23687,371 @@ const docTemplate = `{
                 }
             }
         },
-        "types.AuthBootstrapRequest": {
+        "types.AuthBootstrapRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AuthBootstrapStatusResponse": {
+            "type": "object",
+            "properties": {
+                "admin_count": {
+                    "type": "integer"
+                },
+                "can_bootstrap": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.AuthLoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AuthLoginResponse": {
+            "type": "object",
+            "properties": {
+                "session": {
+                    "$ref": "#/definitions/types.AuthSession"
+                },
+                "user": {
+                    "$ref": "#/definitions/types.AdminUser"
+                }
+            }
+        },
+        "types.AuthPasswordChangeRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AuthSession": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AuthSessionResponse": {
+            "type": "object",
+            "properties": {
+                "csrf_token": {
+                    "type": "string"
+                },
+                "session": {
+                    "$ref": "#/definitions/types.AuthSession"
+                },
+                "user": {
+                    "$ref": "#/definitions/types.AdminUser"
+                }
+            }
+        },
+        "types.BanPlayerRequest": {
+            "type": "object",
+            "properties": {
+                "duration_sec": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "lift_timestamp": {
+                    "type": "string"
+                },
+                "permanent": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.BuffListResponse": {
+            "type": "object",
+            "properties": {
+                "buffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuffPayload"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                }
+            }
+        },
+        "types.BuffPayload": {
+            "type": "object",
+            "properties": {
+                "benefit_type": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_time": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.BuildBannerListResponse": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
+                "banners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuildBannerSummary"
+                    }
                 },
-                "username": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
                 }
             }
         },
-        "types.AuthBootstrapStatusResponse": {
+        "types.BuildBannerRateUp": {
             "type": "object",
+            "required": [
+                "ship_id"
+            ],
             "properties": {
-                "admin_count": {
-                    "type": "integer"
+                "rate": {
+                    "type": "integer",
+                    "maximum": 10000
                 },
-                "can_bootstrap": {
-                    "type": "boolean"
+                "ship_id": {
+                    "type": "integer"
                 }
             }
         },
-        "types.AuthLoginRequest": {
+        "types.BuildBannerRequest": {
             "type": "object",
+            "required": [
+                "name",
+                "pool_id"
+            ],
             "properties": {
-                "password": {
+                "common_weight": {
+                    "type": "integer"
+                },
+                "elite_weight": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "ends_at": {
                     "type": "string"
                 },
-                "username": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "pity_threshold": {
+                    "type": "integer",
+                    "maximum": 400
+                },
+                "pool_id": {
+                    "type": "integer"
+                },
+                "rare_weight": {
+                    "type": "integer"
+                },
+                "rate_ups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuildBannerRateUp"
+                    }
+                },
+                "region": {
+                    "type": "string",
+                    "enum": [
+                        "CN",
+                        "EN",
+                        "JP",
+                        "KR",
+                        "TW"
+                    ]
+                },
+                "starts_at": {
                     "type": "string"
+                },
+                "super_rare_weight": {
+                    "type": "integer"
+                },
+                "ultra_rare_weight": {
+                    "type": "integer"
                 }
             }
         },
-        "types.AuthLoginResponse": {
+        "types.BuildBannerStatsResponse": {
             "type": "object",
             "properties": {
-                "session": {
-                    "$ref": "#/definitions/types.AuthSession"
+                "pity": {
+                    "type": "integer"
                 },
-                "user": {
-                    "$ref": "#/definitions/types.AdminUser"
+                "rarities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuildRollRarityCount"
+                    }
+                },
+                "rate_ups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuildRollRateUpCount"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "types.AuthPasswordChangeRequest": {
+        "types.BuildBannerSummary": {
             "type": "object",
             "properties": {
-                "current_password": {
-                    "type": "string"
+                "common_weight": {
+                    "type": "integer"
                 },
-                "new_password": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "types.AuthSession": {
-            "type": "object",
-            "properties": {
-                "expires_at": {
+                },
+                "elite_weight": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "ends_at": {
                     "type": "string"
                 },
                 "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
-                }
-            }
-        },
-        "types.AuthSessionResponse": {
-            "type": "object",
-            "properties": {
-                "csrf_token": {
+                },
+                "pity_threshold": {
+                    "type": "integer"
+                },
+                "pool_id": {
+                    "type": "integer"
+                },
+                "rare_weight": {
+                    "type": "integer"
+                },
+                "rate_ups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BuildBannerRateUp"
+                    }
+                },
+                "region": {
                     "type": "string"
                 },
-                "session": {
-                    "$ref": "#/definitions/types.AuthSession"
+                "starts_at": {
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/types.AdminUser"
+                "super_rare_weight": {
+                    "type": "integer"
+                },
+                "ultra_rare_weight": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "types.BanPlayerRequest": {
+        "types.BuildRollLogEntry": {
             "type": "object",
             "properties": {
-                "duration_sec": {
-                    "type": "integer",
-                    "minimum": 1
+                "commander_id": {
+                    "type": "integer"
                 },
-                "lift_timestamp": {
+                "created_at": {
                     "type": "string"
                 },
-                "permanent": {
+                "id": {
+                    "type": "integer"
+                },
+                "pity": {
+                    "type": "boolean"
+                },
+                "pool_id": {
+                    "type": "integer"
+                },
+                "rarity_id": {
+                    "type": "integer"
+                },
+                "rate_up": {
                     "type": "boolean"
+                },
+                "ship_id": {
+                    "type": "integer"
                 }
             }
         },
-        "types.BuffListResponse": {
+        "types.BuildRollLogListResponse": {
             "type": "object",
             "properties": {
-                "buffs": {
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                },
+                "rolls": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.BuffPayload"
+                        "$ref": "#/definitions/types.BuildRollLogEntry"
                     }
-                },
-                "meta": {
-                    "$ref": "#/definitions/types.PaginationMeta"
                 }
             }
         },
-        "types.BuffPayload": {
+        "types.BuildRollRarityCount": {
             "type": "object",
             "properties": {
-                "benefit_type": {
-                    "type": "string"
-                },
-                "desc": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "id": {
+                "rarity_id": {
                     "type": "integer"
                 },
-                "max_time": {
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "types.BuildRollRateUpCount": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "rate": {
+                    "type": "number"
+                },
+                "ship_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -20546,31 +24332,134 @@ const docTemplate = `{
                 "round": {
                     "type": "integer"
                 },
-                "submarine_group_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.ChapterGroup"
-                    }
+                "submarine_group_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ChapterGroup"
+                    }
+                },
+                "support_group_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ChapterGroup"
+                    }
+                },
+                "time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ChapterStrategy": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ChatBroadcastRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "room_id": {
+                    "description": "Omitted to broadcast to every room.",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ChatMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "integer"
+                },
+                "sender_id": {
+                    "type": "integer"
+                },
+                "sender_level": {
+                    "type": "integer"
+                },
+                "sender_name": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.ChatMessageListResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ChatMessage"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                }
+            }
+        },
+        "types.ChatMute": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
                 },
-                "support_group_list": {
+                "muted_until": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.ChatMuteListResponse": {
+            "type": "object",
+            "properties": {
+                "mutes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.ChapterGroup"
+                        "$ref": "#/definitions/types.ChatMute"
                     }
-                },
-                "time": {
-                    "type": "integer"
                 }
             }
         },
-        "types.ChapterStrategy": {
+        "types.ChatMuteRequest": {
             "type": "object",
+            "required": [
+                "commander_id",
+                "duration_seconds"
+            ],
             "properties": {
-                "count": {
+                "commander_id": {
                     "type": "integer"
                 },
-                "id": {
+                "duration_seconds": {
                     "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
@@ -20860,11 +24749,325 @@ const docTemplate = `{
                         "$ref": "#/definitions/orm.Dorm3dRoom"
                     }
                 },
-                "ships": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/orm.Dorm3dShip"
-                    }
+                "ships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/orm.Dorm3dShip"
+                    }
+                }
+            }
+        },
+        "types.DropBonus": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DropBonusListResponse": {
+            "type": "object",
+            "properties": {
+                "bonuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DropBonus"
+                    }
+                }
+            }
+        },
+        "types.DropBonusRequest": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "chapter",
+                        "event",
+                        "item"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "rate": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DropTableEntry": {
+            "type": "object",
+            "required": [
+                "drop_id",
+                "drop_type",
+                "weight"
+            ],
+            "properties": {
+                "count": {
+                    "description": "Omitted or 0 drops one.",
+                    "type": "integer",
+                    "maximum": 100000
+                },
+                "drop_id": {
+                    "type": "integer"
+                },
+                "drop_type": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "integer",
+                    "maximum": 1000000
+                }
+            }
+        },
+        "types.DropTableListResponse": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DropTableSummary"
+                    }
+                }
+            }
+        },
+        "types.DropTableRequest": {
+            "type": "object",
+            "required": [
+                "entries",
+                "kind",
+                "source_id"
+            ],
+            "properties": {
+                "empty_weight": {
+                    "type": "integer",
+                    "maximum": 1000000
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entries": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.DropTableEntry"
+                    }
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "chapter",
+                        "event",
+                        "item"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "rank_a_rate": {
+                    "type": "integer",
+                    "maximum": 1000
+                },
+                "rank_b_rate": {
+                    "type": "integer",
+                    "maximum": 1000
+                },
+                "rank_s_rate": {
+                    "type": "integer",
+                    "maximum": 1000
+                },
+                "rate_multiplier": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "rolls": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                },
+                "source_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.DropTableSimulationEntry": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "drop_id": {
+                    "type": "integer"
+                },
+                "drop_type": {
+                    "type": "integer"
+                },
+                "expected_rate": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "types.DropTableSimulationRequest": {
+            "type": "object",
+            "required": [
+                "rolls",
+                "source_id"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "chapter",
+                        "event",
+                        "item"
+                    ]
+                },
+                "rank": {
+                    "type": "string",
+                    "enum": [
+                        "S",
+                        "A",
+                        "B"
+                    ]
+                },
+                "rolls": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1
+                },
+                "source_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.DropTableSimulationResponse": {
+            "type": "object",
+            "properties": {
+                "bonus_rate": {
+                    "description": "Combined rate of the bonuses running, in percent.",
+                    "type": "integer"
+                },
+                "empty": {
+                    "type": "integer"
+                },
+                "empty_rate": {
+                    "type": "number"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DropTableSimulationEntry"
+                    }
+                },
+                "expected_empty_rate": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "string"
+                },
+                "rolls": {
+                    "type": "integer"
+                },
+                "table_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.DropTableSummary": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "empty_weight": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DropTableEntry"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank_a_rate": {
+                    "type": "integer"
+                },
+                "rank_b_rate": {
+                    "type": "integer"
+                },
+                "rank_s_rate": {
+                    "type": "integer"
+                },
+                "rate_multiplier": {
+                    "type": "integer"
+                },
+                "rolls": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
@@ -21042,6 +25245,32 @@ const docTemplate = `{
                 }
             }
         },
+        "types.GameDataCacheMetrics": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "loaded": {
+                    "type": "boolean"
+                },
+                "loaded_at": {
+                    "type": "string"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "reloads": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.GiveItemRequest": {
             "type": "object",
             "required": [
@@ -21082,6 +25311,160 @@ const docTemplate = `{
                 }
             }
         },
+        "types.GuildCreateRequest": {
+            "type": "object",
+            "required": [
+                "leader_id",
+                "name"
+            ],
+            "properties": {
+                "announce": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "faction": {
+                    "type": "integer"
+                },
+                "leader_id": {
+                    "type": "integer"
+                },
+                "manifesto": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "policy": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.GuildDetailResponse": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GuildMemberEntry"
+                    }
+                },
+                "guild": {
+                    "$ref": "#/definitions/types.GuildSummary"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GuildLogEntry"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GuildMemberEntry"
+                    }
+                }
+            }
+        },
+        "types.GuildListResponse": {
+            "type": "object",
+            "properties": {
+                "guilds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GuildSummary"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
+                }
+            }
+        },
+        "types.GuildLogEntry": {
+            "type": "object",
+            "properties": {
+                "arg1": {
+                    "type": "integer"
+                },
+                "cmd": {
+                    "type": "integer"
+                },
+                "commander_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.GuildMemberAddRequest": {
+            "type": "object",
+            "required": [
+                "commander_id"
+            ],
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "duty": {
+                    "type": "integer",
+                    "maximum": 4,
+                    "minimum": 1
+                }
+            }
+        },
+        "types.GuildMemberEntry": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "contribution": {
+                    "type": "integer"
+                },
+                "duty": {
+                    "type": "integer"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "liveness": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.GuildMemberUpdateRequest": {
+            "type": "object",
+            "required": [
+                "duty"
+            ],
+            "properties": {
+                "duty": {
+                    "type": "integer",
+                    "maximum": 4,
+                    "minimum": 1
+                }
+            }
+        },
         "types.GuildShopGood": {
             "type": "object",
             "properties": {
@@ -21168,6 +25551,98 @@ const docTemplate = `{
                 }
             }
         },
+        "types.GuildSummary": {
+            "type": "object",
+            "properties": {
+                "announce": {
+                    "type": "string"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "capital": {
+                    "type": "integer"
+                },
+                "change_faction_cd": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "faction": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kick_leader_cd": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "manifesto": {
+                    "type": "string"
+                },
+                "member_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "policy": {
+                    "type": "integer"
+                },
+                "tech_group": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.GuildUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "announce": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "capital_delta": {
+                    "type": "integer"
+                },
+                "change_faction_cd": {
+                    "type": "integer"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "faction": {
+                    "type": "integer"
+                },
+                "kick_leader_cd": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "manifesto": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                },
+                "policy": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.ItemCreateRequest": {
             "type": "object",
             "properties": {
@@ -22345,19 +26820,86 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "credential_id": {
+                "credential_id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.PaymentOrder": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "fail_code": {
+                    "type": "integer"
+                },
+                "gem": {
+                    "type": "integer"
+                },
+                "gem_free": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "money": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "pay_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "refunded_at": {
                     "type": "string"
                 },
-                "label": {
+                "repaid_at": {
                     "type": "string"
                 },
-                "last_used_at": {
+                "shop_id": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
+                }
+            }
+        },
+        "types.PaymentOrderListResponse": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/types.PaginationMeta"
                 },
-                "transports": {
+                "orders": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/types.PaymentOrder"
                     }
                 }
             }
@@ -22404,6 +26946,104 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerActivityProgress": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "claimed_targets": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "claimed_tasks": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "last_sign_at": {
+                    "type": "string"
+                },
+                "pt": {
+                    "type": "integer"
+                },
+                "shop_purchases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerActivityShopPurchase"
+                    }
+                },
+                "sign_days": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.PlayerActivityProgressResponse": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerActivityProgress"
+                    }
+                }
+            }
+        },
+        "types.PlayerActivityShopPurchase": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "good_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerArenaState": {
+            "type": "object",
+            "properties": {
+                "fight_count": {
+                    "type": "integer"
+                },
+                "fight_count_reset_time": {
+                    "type": "string"
+                },
+                "flash_target_count": {
+                    "type": "integer"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "rival_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.PlayerAttireCreateRequest": {
             "type": "object",
             "required": [
@@ -22478,6 +27118,90 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerBlueprintEntry": {
+            "type": "object",
+            "properties": {
+                "blueprint_id": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "ship_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "integer"
+                },
+                "submitted_tasks": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.PlayerBlueprintUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "ship_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "integer"
+                },
+                "submitted_tasks": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.PlayerBlueprintsResponse": {
+            "type": "object",
+            "properties": {
+                "blueprints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerBlueprintEntry"
+                    }
+                },
+                "catchup_target": {
+                    "type": "integer"
+                },
+                "catchup_version": {
+                    "type": "integer"
+                },
+                "cold_time": {
+                    "type": "integer"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerTechProjectEntry"
+                    }
+                },
+                "refresh_flag": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.PlayerBuffAddRequest": {
             "type": "object",
             "required": [
@@ -22735,6 +27459,43 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerCheaterMark": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "stage_id": {
+                    "type": "integer"
+                },
+                "system": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerCheaterMarksResponse": {
+            "type": "object",
+            "properties": {
+                "marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerCheaterMark"
+                    }
+                }
+            }
+        },
         "types.PlayerCompensationAttachment": {
             "type": "object",
             "properties": {
@@ -23010,23 +27771,100 @@ const docTemplate = `{
                 "fleets": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.PlayerFleetEntry"
+                        "$ref": "#/definitions/types.PlayerFleetEntry"
+                    }
+                }
+            }
+        },
+        "types.PlayerFleetUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "ship_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.PlayerFriendAddRequest": {
+            "type": "object",
+            "required": [
+                "friend_id"
+            ],
+            "properties": {
+                "friend_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerFriendBlockRequest": {
+            "type": "object",
+            "required": [
+                "blocked_id"
+            ],
+            "properties": {
+                "blocked_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerFriendEntry": {
+            "type": "object",
+            "properties": {
+                "commander_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "last_login": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.PlayerFriendsResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerFriendEntry"
+                    }
+                },
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerFriendEntry"
                     }
-                }
-            }
-        },
-        "types.PlayerFleetUpdateRequest": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string",
-                    "minLength": 1
                 },
-                "ship_ids": {
+                "incoming_requests": {
                     "type": "array",
-                    "minItems": 1,
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/types.PlayerFriendEntry"
+                    }
+                },
+                "outgoing_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerFriendEntry"
                     }
                 }
             }
@@ -23317,6 +28155,186 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerMeowfficerBoxEntry": {
+            "type": "object",
+            "properties": {
+                "begin_time": {
+                    "type": "integer"
+                },
+                "finish_time": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pool_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerBoxRequest": {
+            "type": "object",
+            "required": [
+                "pool_id"
+            ],
+            "properties": {
+                "pool_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerCreateRequest": {
+            "type": "object",
+            "required": [
+                "template_id"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 12
+                },
+                "template_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerEntry": {
+            "type": "object",
+            "properties": {
+                "abilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "ability_origin": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_locked": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skill_exp": {
+                    "type": "integer"
+                },
+                "skill_id": {
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "used_pt": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerHome": {
+            "type": "object",
+            "properties": {
+                "clean_time": {
+                    "type": "integer"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerMeowfficerHomeSlot"
+                    }
+                },
+                "usage_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerHomeSlot": {
+            "type": "object",
+            "properties": {
+                "exp_time": {
+                    "type": "integer"
+                },
+                "meowfficer_id": {
+                    "type": "integer"
+                },
+                "slot_id": {
+                    "type": "integer"
+                },
+                "style": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficerUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "abilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "is_locked": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 12
+                },
+                "skill_exp": {
+                    "type": "integer"
+                },
+                "skill_id": {
+                    "type": "integer"
+                },
+                "used_pt": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerMeowfficersResponse": {
+            "type": "object",
+            "properties": {
+                "boxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerMeowfficerBoxEntry"
+                    }
+                },
+                "home": {
+                    "$ref": "#/definitions/types.PlayerMeowfficerHome"
+                },
+                "meowfficers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerMeowfficerEntry"
+                    }
+                }
+            }
+        },
         "types.PlayerMiscItemEntry": {
             "type": "object",
             "properties": {
@@ -23880,6 +28898,9 @@ const docTemplate = `{
                 "exp": {
                     "type": "integer"
                 },
+                "in_onsen": {
+                    "type": "boolean"
+                },
                 "intimacy": {
                     "type": "integer"
                 },
@@ -24016,6 +29037,20 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerTechProjectEntry": {
+            "type": "object",
+            "properties": {
+                "finish_time": {
+                    "type": "integer"
+                },
+                "refresh_id": {
+                    "type": "integer"
+                },
+                "tech_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.PlayerUpdateRequest": {
             "type": "object",
             "properties": {
@@ -24094,6 +29129,92 @@ const docTemplate = `{
                 }
             }
         },
+        "types.PlayerWorldGroup": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "ship_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.PlayerWorldItem": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerWorldMap": {
+            "type": "object",
+            "properties": {
+                "is_cleared": {
+                    "type": "boolean"
+                },
+                "map_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PlayerWorldResponse": {
+            "type": "object",
+            "properties": {
+                "action_power": {
+                    "type": "integer"
+                },
+                "action_power_extra": {
+                    "type": "integer"
+                },
+                "camp": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerWorldGroup"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerWorldItem"
+                    }
+                },
+                "last_recover_time": {
+                    "type": "integer"
+                },
+                "map_id": {
+                    "type": "integer"
+                },
+                "maps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.PlayerWorldMap"
+                    }
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "step_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.PushCompensationResponse": {
             "type": "object",
             "properties": {
@@ -24396,6 +29517,9 @@ const docTemplate = `{
                 "client_count": {
                     "type": "integer"
                 },
+                "game_data_cache": {
+                    "$ref": "#/definitions/types.GameDataCacheMetrics"
+                },
                 "handler_errors": {
                     "type": "integer"
                 },
@@ -25115,6 +30239,20 @@ const docTemplate = `{
                 }
             }
         },
+        "types.ThemePreview": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "md5": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.UpdateCompensationRequest": {
             "type": "object",
             "properties": {
@@ -6,11 +6,17 @@ import (
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/region"
 )
 
 func Activities(buffer *[]byte, client *connection.Client) (int, int, error) {
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return 0, 11200, err
+	}
 	allowlist, err := loadActivityAllowlist()
 	if err != nil {
 		return 0, 11200, err
@@ -23,18 +29,36 @@ func Activities(buffer *[]byte, client *connection.Client) (int, int, error) {
 	if err != nil {
 		return 0, 11200, err
 	}
+	progressList, err := orm.ListActivityEventProgress(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 11200, err
+	}
+	progress := make(map[uint32]*orm.ActivityEventProgress, len(progressList))
+	for i := range progressList {
+		progress[progressList[i].ActivityID] = &progressList[i]
+	}
 	finished := filterPermanentActivityIDs(orm.ToUint32List(state.FinishedActivityIDs), permanentIDs)
 	finishedSet := make(map[uint32]struct{}, len(finished))
 	for _, id := range finished {
 		finishedSet[id] = struct{}{}
 	}
 
-	activityIDs := make([]uint32, 0, len(allowlist)+1)
-	for _, activityID := range allowlist {
+	// allowlisted activities keep their order, scheduled ones follow by id
+	open := openActivityIDs(windows, time.Now())
+	candidates := make([]uint32, 0, len(allowlist)+len(windows))
+	candidates = append(candidates, allowlist...)
+	for _, window := range windows {
+		candidates = append(candidates, window.ActivityID)
+	}
+	activityIDs := make([]uint32, 0, len(open)+1)
+	for _, activityID := range candidates {
+		if _, ok := open[activityID]; !ok {
+			continue
+		}
 		if _, ok := finishedSet[activityID]; ok {
 			continue
 		}
-		activityIDs = append(activityIDs, activityID)
+		activityIDs = appendUniqueUint32(activityIDs, activityID)
 	}
 	if state.CurrentActivityID != 0 {
 		if _, ok := permanentIDs[state.CurrentActivityID]; ok {
@@ -55,7 +79,11 @@ func Activities(buffer *[]byte, client *connection.Client) (int, int, error) {
 		if err != nil {
 			return 0, 11200, err
 		}
-		info, err := buildActivityInfo(template, activityStopTime(template.Time))
+		stopTime := activityStopTime(template.Time)
+		if window, ok := open[activityID]; ok {
+			stopTime = window.stopTime()
+		}
+		info, err := buildActivityInfo(template, stopTime)
 		if err != nil {
 			return 0, 11200, err
 		}
@@ -69,65 +97,73 @@ func Activities(buffer *[]byte, client *connection.Client) (int, int, error) {
 		if found {
 			info.GroupList = activityFleetGroupsToProto(groups)
 		}
+		if err := applyActivityEventProgress(info, template, progress[template.ID], client.Commander.CommanderID); err != nil {
+			return 0, 11200, err
+		}
 		response.ActivityList = append(response.ActivityList, info)
 	}
 	return client.SendMessage(11200, &response)
 }
 
 func activityStopTime(raw json.RawMessage) uint32 {
+	_, stop, ok := parseActivityTimer(raw)
+	if !ok {
+		return 0
+	}
+	return uint32(stop.Unix())
+}
+
+// parseActivityTimer reads the ["timer", start, stop] time of an activity
+// template. start is zero when it is not a date.
+func parseActivityTimer(raw json.RawMessage) (time.Time, time.Time, bool) {
 	var label string
 	if err := json.Unmarshal(raw, &label); err == nil {
-		return 0
+		return time.Time{}, time.Time{}, false
 	}
 	var value []any
 	if err := json.Unmarshal(raw, &value); err != nil {
-		return 0
+		return time.Time{}, time.Time{}, false
 	}
 	if len(value) < 3 {
-		return 0
+		return time.Time{}, time.Time{}, false
 	}
 	typeTag, ok := value[0].(string)
 	if !ok || typeTag != "timer" {
-		return 0
-	}
-	end, ok := value[2].([]any)
-	if !ok || len(end) != 2 {
-		return 0
-	}
-	date, ok := end[0].([]any)
-	if !ok || len(date) != 3 {
-		return 0
-	}
-	clock, ok := end[1].([]any)
-	if !ok || len(clock) != 3 {
-		return 0
+		return time.Time{}, time.Time{}, false
 	}
-	year, ok := parseJSONInt(date[0])
+	stop, ok := parseActivityDate(value[2])
 	if !ok {
-		return 0
+		return time.Time{}, time.Time{}, false
 	}
-	month, ok := parseJSONInt(date[1])
-	if !ok {
-		return 0
-	}
-	day, ok := parseJSONInt(date[2])
-	if !ok {
-		return 0
-	}
-	hour, ok := parseJSONInt(clock[0])
-	if !ok {
-		return 0
+	start, _ := parseActivityDate(value[1])
+	return start, stop, true
+}
+
+// parseActivityDate reads a [[year, month, day], [hour, minute, second]] date.
+func parseActivityDate(raw any) (time.Time, bool) {
+	value, ok := raw.([]any)
+	if !ok || len(value) != 2 {
+		return time.Time{}, false
 	}
-	minute, ok := parseJSONInt(clock[1])
-	if !ok {
-		return 0
+	date, ok := value[0].([]any)
+	if !ok || len(date) != 3 {
+		return time.Time{}, false
 	}
-	second, ok := parseJSONInt(clock[2])
-	if !ok {
-		return 0
+	clock, ok := value[1].([]any)
+	if !ok || len(clock) != 3 {
+		return time.Time{}, false
+	}
+	parts := make([]int, 0, 6)
+	for _, group := range [][]any{date, clock} {
+		for _, part := range group {
+			number, ok := parseJSONInt(part)
+			if !ok {
+				return time.Time{}, false
+			}
+			parts = append(parts, number)
+		}
 	}
-	stop := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
-	return uint32(stop.Unix())
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, time.UTC), true
 }
 
 func parseJSONInt(value any) (int, bool) {
@@ -139,16 +175,12 @@ func parseJSONInt(value any) (int, bool) {
 }
 
 func loadActivityAllowlist() ([]uint32, error) {
-	entry, err := orm.GetConfigEntry("ServerCfg/activities.json", "allowlist")
+	allowlist, err := gamedata.Get[[]uint32](gamedata.Default, "ServerCfg/activities.json", "allowlist")
 	if err != nil {
 		if db.IsNotFound(err) {
 			return []uint32{}, nil
 		}
 		return nil, err
 	}
-	var allowlist []uint32
-	if err := json.Unmarshal(entry.Data, &allowlist); err != nil {
-		return nil, err
-	}
-	return allowlist, nil
+	return *allowlist, nil
 }
@@ -12,6 +12,10 @@ const (
 	activityTypePuzzleConnect   = 1001
 	activityTypeTown            = 116
 	activityTypeEventSingle     = 112
+	activityTypeEventShop       = 1
+	activityTypeLoginSign       = 5
+	activityTypeEventPt         = 24
 
 	activityCmdSingleEventRefresh = 2
+	activityCmdEventClaim         = 1
 )
@@ -0,0 +1,473 @@
+package answer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/region"
+)
+
+const activityEventPtCategory = "ShareCfg/activity_event_pt.json"
+
+// activityShopMaxCount caps a single purchase of an event shop good without
+// num_limit.
+const activityShopMaxCount = 999
+
+// SC_11203 results of the event activities, matching the month shop ones.
+const (
+	activityResultOK           = 0
+	activityResultInvalid      = 1
+	activityResultInsufficient = 2
+	activityResultLimit        = 3
+	activityResultUnsupported  = 4
+	activityResultDBError      = 5
+)
+
+// activityEventPtConfig is an activity_event_pt entry: pt is the virtual
+// item counted by the activity, drop_client holds the [type, id, count]
+// reward of each target.
+type activityEventPtConfig struct {
+	ID         uint32     `json:"id"`
+	Pt         uint32     `json:"pt"`
+	Target     []uint32   `json:"target"`
+	DropClient [][]uint32 `json:"drop_client"`
+}
+
+// loadActivityEventPtConfig returns the PT config of an activity, keyed by
+// its config_id, or nil when there is none.
+func loadActivityEventPtConfig(template activityTemplate) (*activityEventPtConfig, error) {
+	key := template.ConfigID
+	if key == 0 {
+		key = template.ID
+	}
+	config, err := gamedata.Get[activityEventPtConfig](gamedata.Default, activityEventPtCategory, strconv.FormatUint(uint64(key), 10))
+	if db.IsNotFound(err) {
+		return nil, nil
+	}
+	return config, err
+}
+
+// parseLoginSignAwards reads the config_data of a login sign-in activity,
+// the [type, id, count] rewards of each day.
+func parseLoginSignAwards(template activityTemplate) ([][][]uint32, error) {
+	var days [][][]uint32
+	if err := json.Unmarshal(template.ConfigData, &days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func sendActivityOperationResult(client *connection.Client, result uint32, awards []*protobuf.DROPINFO) (int, int, error) {
+	if awards == nil {
+		awards = []*protobuf.DROPINFO{}
+	}
+	response := protobuf.SC_11203{
+		Result:         proto.Uint32(result),
+		AwardList:      awards,
+		Number:         []uint32{},
+		ReturnUserList: []*protobuf.RETURN_USER_INFO{},
+	}
+	return client.SendMessage(11203, &response)
+}
+
+// handleEventPtClaim claims the reward of the target arg1, or of every
+// reached target when arg1 is 0.
+func handleEventPtClaim(template activityTemplate, payload *protobuf.CS_11202, client *connection.Client) (int, int, error) {
+	if payload.GetCmd() != activityCmdEventClaim {
+		return sendActivityOperationResult(client, activityResultUnsupported, nil)
+	}
+	config, err := loadActivityEventPtConfig(template)
+	if err != nil {
+		return 0, 11203, err
+	}
+	if config == nil {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	if err := ensureCommanderLoaded(client, "ActivityEvent"); err != nil {
+		return 0, 11203, err
+	}
+	var drops []*protobuf.DROPINFO
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		progress, err := orm.GetActivityEventProgressTx(ctx, tx, client.Commander.CommanderID, template.ID)
+		if err != nil {
+			return err
+		}
+		awards := make([][]uint32, 0)
+		for i, target := range config.Target {
+			if payload.GetArg1() != 0 && target != payload.GetArg1() {
+				continue
+			}
+			if progress.Pt < target || progress.HasClaimedTarget(target) || i >= len(config.DropClient) {
+				continue
+			}
+			awards = append(awards, config.DropClient[i])
+			progress.ClaimedTargets = append(progress.ClaimedTargets, int64(target))
+		}
+		if len(awards) == 0 {
+			return db.ErrNotFound
+		}
+		if drops, err = grantActivityAwardsTx(ctx, tx, client, awards); err != nil {
+			return err
+		}
+		return orm.SaveActivityEventProgressTx(ctx, tx, progress)
+	})
+	if db.IsNotFound(err) {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	if err != nil {
+		return 0, 11203, err
+	}
+	return sendActivityOperationResult(client, activityResultOK, drops)
+}
+
+// handleEventShopBuy buys arg2 (at least one) of the activity_shop_template
+// good arg1, listed in the config_data of the activity. num_limit caps the
+// purchases of a commander for the whole activity.
+func handleEventShopBuy(template activityTemplate, payload *protobuf.CS_11202, client *connection.Client) (int, int, error) {
+	if payload.GetCmd() != activityCmdEventClaim {
+		return sendActivityOperationResult(client, activityResultUnsupported, nil)
+	}
+	goods, err := parseActivityConfigIDs(template.ConfigData)
+	if err != nil {
+		return 0, 11203, err
+	}
+	goodID := payload.GetArg1()
+	if !containsUint32(goods, goodID) {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	good, ok, err := loadActivityShopEntry(goodID)
+	if err != nil {
+		return 0, 11203, err
+	}
+	if !ok {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	count := payload.GetArg2()
+	if count == 0 {
+		count = 1
+	}
+	maxCount := good.NumLimit
+	if maxCount == 0 {
+		maxCount = activityShopMaxCount
+	}
+	cost := uint64(good.ResourceNum) * uint64(count)
+	amount := uint64(good.Num) * uint64(count)
+	if count > maxCount || cost > math.MaxUint32 || amount > math.MaxUint32 {
+		return sendActivityOperationResult(client, activityResultLimit, nil)
+	}
+	if err := ensureCommanderLoaded(client, "ActivityEvent"); err != nil {
+		return 0, 11203, err
+	}
+	errLimit := errors.New("limit")
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		progress, err := orm.GetActivityEventProgressTx(ctx, tx, client.Commander.CommanderID, template.ID)
+		if err != nil {
+			return err
+		}
+		if good.NumLimit > 0 && progress.ShopPurchases[goodID] > good.NumLimit-count {
+			return errLimit
+		}
+		if err := consumeShopCostTx(ctx, tx, client, good.ResourceCategory, good.ResourceType, uint32(cost)); err != nil {
+			return err
+		}
+		if err := grantShopCommodityTx(ctx, tx, client, good.CommodityType, good.CommodityID, uint32(amount)); err != nil {
+			return err
+		}
+		progress.ShopPurchases[goodID] += count
+		return orm.SaveActivityEventProgressTx(ctx, tx, progress)
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, errShopInsufficient):
+		return sendActivityOperationResult(client, activityResultInsufficient, nil)
+	case errors.Is(err, errLimit):
+		return sendActivityOperationResult(client, activityResultLimit, nil)
+	case errors.Is(err, errShopUnsupported):
+		return sendActivityOperationResult(client, activityResultUnsupported, nil)
+	default:
+		return sendActivityOperationResult(client, activityResultDBError, nil)
+	}
+	if err := recordTaskEvent(client, taskEventShopPurchase, 0, 1); err != nil {
+		return 0, 11203, err
+	}
+	return sendActivityOperationResult(client, activityResultOK, []*protobuf.DROPINFO{buildDrop(good.CommodityType, good.CommodityID, uint32(amount))})
+}
+
+// handleLoginSign claims the reward of the next day of a login sign-in
+// activity, once per UTC day.
+func handleLoginSign(template activityTemplate, payload *protobuf.CS_11202, client *connection.Client) (int, int, error) {
+	if payload.GetCmd() != activityCmdEventClaim {
+		return sendActivityOperationResult(client, activityResultUnsupported, nil)
+	}
+	days, err := parseLoginSignAwards(template)
+	if err != nil {
+		return 0, 11203, err
+	}
+	if err := ensureCommanderLoaded(client, "ActivityEvent"); err != nil {
+		return 0, 11203, err
+	}
+	now := uint32(time.Now().Unix())
+	var drops []*protobuf.DROPINFO
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		progress, err := orm.GetActivityEventProgressTx(ctx, tx, client.Commander.CommanderID, template.ID)
+		if err != nil {
+			return err
+		}
+		if progress.SignDays >= uint32(len(days)) || isSameDay(progress.LastSignAt, now) {
+			return db.ErrNotFound
+		}
+		if drops, err = grantActivityAwardsTx(ctx, tx, client, days[progress.SignDays]); err != nil {
+			return err
+		}
+		progress.SignDays++
+		progress.LastSignAt = now
+		return orm.SaveActivityEventProgressTx(ctx, tx, progress)
+	})
+	if db.IsNotFound(err) {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	if err != nil {
+		return 0, 11203, err
+	}
+	return sendActivityOperationResult(client, activityResultOK, drops)
+}
+
+// grantActivityAwardsTx grants [type, id, count] rewards within tx, so they
+// are only kept if the claim is saved.
+func grantActivityAwardsTx(ctx context.Context, tx pgx.Tx, client *connection.Client, awards [][]uint32) ([]*protobuf.DROPINFO, error) {
+	drops := make([]*protobuf.DROPINFO, 0, len(awards))
+	for _, award := range awards {
+		if len(award) < 3 || award[2] == 0 {
+			continue
+		}
+		var err error
+		if award[0] == consts.DROP_TYPE_WORLD_ITEM {
+			err = orm.AddWorldItemTx(ctx, tx, client.Commander.CommanderID, award[1], award[2])
+		} else {
+			err = grantShopCommodityTx(ctx, tx, client, award[0], award[1], award[2])
+		}
+		if errors.Is(err, errShopUnsupported) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		drops = append(drops, newDropInfo(award[0], award[1], award[2]))
+	}
+	return drops, nil
+}
+
+// handleActivityTaskClaim claims the reward of the finished task arg1 of a
+// task list activity.
+func handleActivityTaskClaim(template activityTemplate, payload *protobuf.CS_11202, client *connection.Client) (int, int, error) {
+	if payload.GetCmd() != activityCmdEventClaim {
+		return sendActivityOperationResult(client, activityResultUnsupported, nil)
+	}
+	taskIDs, err := parseActivityTaskIDs(template.ConfigData)
+	if err != nil {
+		return 0, 11203, err
+	}
+	taskID := payload.GetArg1()
+	if !containsUint32(taskIDs, taskID) {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	taskTemplate, err := loadTaskTemplate(taskID)
+	if err != nil {
+		return 0, 11203, err
+	}
+	if taskTemplate == nil {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	commanderID := client.Commander.CommanderID
+	task, err := orm.GetCommanderTask(commanderID, taskID)
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 11203, err
+	}
+	if task == nil || task.Progress < taskTemplate.TargetNum {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		progress, err := orm.GetActivityEventProgressTx(ctx, tx, commanderID, template.ID)
+		if err != nil {
+			return err
+		}
+		if progress.HasClaimedTask(taskID) {
+			return db.ErrNotFound
+		}
+		if err := orm.SubmitCommanderTaskTx(ctx, tx, commanderID, taskID, uint32(time.Now().Unix())); err != nil {
+			return err
+		}
+		progress.ClaimedTasks = append(progress.ClaimedTasks, int64(taskID))
+		return orm.SaveActivityEventProgressTx(ctx, tx, progress)
+	})
+	if db.IsNotFound(err) {
+		return sendActivityOperationResult(client, activityResultInvalid, nil)
+	}
+	if err != nil {
+		return 0, 11203, err
+	}
+	if err := ensureCommanderLoaded(client, "ActivityEvent"); err != nil {
+		return 0, 11203, err
+	}
+	drops, err := grantTaskAwards(client, taskTemplate.AwardDisplay)
+	if err != nil {
+		return 0, 11203, err
+	}
+	return sendActivityOperationResult(client, activityResultOK, drops)
+}
+
+// acceptActivityTasks adds the tasks of a task list activity the commander
+// does not have yet, so their progress is tracked.
+func acceptActivityTasks(commanderID uint32, taskIDs []uint32, now time.Time) error {
+	for _, taskID := range taskIDs {
+		_, err := orm.GetCommanderTask(commanderID, taskID)
+		if err == nil {
+			continue
+		}
+		if !db.IsNotFound(err) {
+			return err
+		}
+		task := orm.CommanderTask{CommanderID: commanderID, TaskID: taskID, AcceptTime: uint32(now.Unix())}
+		if err := orm.UpsertCommanderTask(&task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyActivityEventProgress fills the commander progress of an event
+// activity in info: PT and claimed targets, signed days, shop purchases or
+// task list.
+func applyActivityEventProgress(info *protobuf.ACTIVITYINFO, template activityTemplate, progress *orm.ActivityEventProgress, commanderID uint32) error {
+	if progress == nil {
+		progress = &orm.ActivityEventProgress{CommanderID: commanderID, ActivityID: template.ID}
+	}
+	switch template.Type {
+	case activityTypeEventPt:
+		info.Data1 = proto.Uint32(progress.Pt)
+		info.Data1List = orm.ToUint32List(progress.ClaimedTargets)
+	case activityTypeLoginSign:
+		info.Data1 = proto.Uint32(progress.SignDays)
+		info.Data2 = proto.Uint32(progress.LastSignAt)
+	case activityTypeEventShop:
+		purchases := make([]*protobuf.KEYVALUE_P11, 0, len(progress.ShopPurchases))
+		goods, err := parseActivityConfigIDs(template.ConfigData)
+		if err != nil {
+			return err
+		}
+		for _, goodID := range goods {
+			if count := progress.ShopPurchases[goodID]; count > 0 {
+				purchases = append(purchases, &protobuf.KEYVALUE_P11{Key: proto.Uint32(goodID), Value: proto.Uint32(count)})
+			}
+		}
+		info.Date1KeyValueList = []*protobuf.KEYVALUELIST_P11{{Key: proto.Uint32(1), ValueList: purchases}}
+	case activityTypeTasks:
+		taskIDs, err := parseActivityTaskIDs(template.ConfigData)
+		if err != nil {
+			return err
+		}
+		if err := acceptActivityTasks(commanderID, taskIDs, time.Now()); err != nil {
+			return err
+		}
+		info.TaskList = make([]*protobuf.TASKINFO, 0, len(taskIDs))
+		for _, taskID := range taskIDs {
+			task, err := orm.GetCommanderTask(commanderID, taskID)
+			if err != nil {
+				return err
+			}
+			info.TaskList = append(info.TaskList, &protobuf.TASKINFO{
+				Id:         proto.Uint32(taskID),
+				Progress:   proto.Uint32(task.Progress),
+				AcceptTime: proto.Uint32(task.AcceptTime),
+				SubmitTime: proto.Uint32(task.SubmitTime),
+			})
+		}
+	}
+	return nil
+}
+
+// activityOpen reports whether activityID is in the schedule and open at now.
+func activityOpen(activityID uint32, now time.Time) (bool, error) {
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return false, err
+	}
+	_, ok := openActivityIDs(windows, now)[activityID]
+	return ok, nil
+}
+
+// addActivityEventPt credits a virtual item drop to the PT of every open
+// PT activity counting it.
+func addActivityEventPt(client *connection.Client, itemID uint32, count uint32) error {
+	ctx := context.Background()
+	return orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return addActivityEventPtTx(ctx, tx, client, itemID, count)
+	})
+}
+
+func addActivityEventPtTx(ctx context.Context, tx pgx.Tx, client *connection.Client, itemID uint32, count uint32) error {
+	if count == 0 {
+		return nil
+	}
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return err
+	}
+	for _, window := range openActivityIDs(windows, time.Now()) {
+		if window.Type != activityTypeEventPt {
+			continue
+		}
+		config, err := loadActivityEventPtConfig(window.template)
+		if err != nil {
+			return err
+		}
+		if config == nil || config.Pt != itemID {
+			continue
+		}
+		if _, err := orm.AddActivityEventPtTx(ctx, tx, client.Commander.CommanderID, window.ActivityID, count); err != nil {
+			return fmt.Errorf("failed to add pt to activity %d: %w", window.ActivityID, err)
+		}
+	}
+	return nil
+}
+
+// ResetActivityEventProgress clears the progress of a commander in an
+// activity. The tasks of a task list activity are dropped as well, they are
+// accepted again the next time the activities are listed.
+func ResetActivityEventProgress(commanderID uint32, activityID uint32) error {
+	template, err := loadActivityTemplate(activityID)
+	if err != nil && !db.IsNotFound(err) {
+		return err
+	}
+	taskIDs := []uint32{}
+	if err == nil && template.Type == activityTypeTasks {
+		if taskIDs, err = parseActivityTaskIDs(template.ConfigData); err != nil {
+			return err
+		}
+	}
+	ctx := context.Background()
+	return orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteCommanderTasksTx(ctx, tx, commanderID, taskIDs); err != nil {
+			return err
+		}
+		return orm.DeleteActivityEventProgressTx(ctx, tx, commanderID, activityID)
+	})
+}
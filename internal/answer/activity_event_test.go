@@ -0,0 +1,137 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func setupActivityEventTest(t *testing.T, gold uint32) *connection.Client {
+	t.Helper()
+	client := setupConfigTest(t)
+	clearTable(t, &orm.OwnedResource{})
+	clearTable(t, &orm.CommanderItem{})
+	if err := client.Commander.Load(); err != nil {
+		t.Fatalf("load commander: %v", err)
+	}
+	if err := client.Commander.SetResource(1, gold); err != nil {
+		t.Fatalf("seed gold: %v", err)
+	}
+	return client
+}
+
+func sendActivityOperation(t *testing.T, client *connection.Client, activityID uint32, arg1 uint32, arg2 uint32) *protobuf.SC_11203 {
+	t.Helper()
+	request := protobuf.CS_11202{
+		ActivityId: proto.Uint32(activityID),
+		Cmd:        proto.Uint32(activityCmdEventClaim),
+		Arg1:       proto.Uint32(arg1),
+		Arg2:       proto.Uint32(arg2),
+	}
+	buffer, err := proto.Marshal(&request)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := ActivityOperation(&buffer, client); err != nil {
+		t.Fatalf("activity operation: %v", err)
+	}
+	var response protobuf.SC_11203
+	decodeResponse(t, client, &response)
+	return &response
+}
+
+func TestActivityOperationEventPtClaim(t *testing.T) {
+	client := setupActivityEventTest(t, 0)
+	seedConfigEntry(t, "ShareCfg/activity_template.json", "70", `{"id":70,"type":24,"config_id":50}`)
+	seedConfigEntry(t, activityEventPtCategory, "50", `{"id":50,"pt":500,"target":[100,200],"drop_client":[[1,1,10],[1,1,20]]}`)
+	seedActivityAllowlist(t, []uint32{70})
+
+	if _, err := applyDrop(client, consts.DROP_TYPE_VITEM, 500, 150); err != nil {
+		t.Fatalf("apply pt drop: %v", err)
+	}
+	response := sendActivityOperation(t, client, 70, 0, 0)
+	if response.GetResult() != activityResultOK || len(response.GetAwardList()) != 1 || response.GetAwardList()[0].GetNumber() != 10 {
+		t.Fatalf("unexpected claim response %+v", response)
+	}
+	if client.Commander.GetResourceCount(1) != 10 {
+		t.Fatalf("expected the first milestone reward")
+	}
+	if response := sendActivityOperation(t, client, 70, 200, 0); response.GetResult() != activityResultInvalid {
+		t.Fatalf("expected the second milestone to be out of reach, got %d", response.GetResult())
+	}
+	if response := sendActivityOperation(t, client, 70, 100, 0); response.GetResult() != activityResultInvalid {
+		t.Fatalf("expected the first milestone to be claimed once, got %d", response.GetResult())
+	}
+	progress, err := orm.GetActivityEventProgress(client.Commander.CommanderID, 70)
+	if err != nil {
+		t.Fatalf("load progress: %v", err)
+	}
+	if progress.Pt != 150 || !progress.HasClaimedTarget(100) || progress.HasClaimedTarget(200) {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestActivityOperationEventShopPurchaseCap(t *testing.T) {
+	client := setupActivityEventTest(t, 100)
+	seedConfigEntry(t, "ShareCfg/activity_template.json", "71", `{"id":71,"type":1,"config_data":[900]}`)
+	seedActivityShopGood(t, 900, 1, 1, 10, 2, 20001, 1, 2)
+
+	if response := sendActivityOperation(t, client, 71, 900, 1); response.GetResult() != activityResultInvalid {
+		t.Fatalf("expected the shop of a closed activity to be refused, got %d", response.GetResult())
+	}
+	seedActivityAllowlist(t, []uint32{71})
+	if response := sendActivityOperation(t, client, 71, 901, 1); response.GetResult() != activityResultInvalid {
+		t.Fatalf("expected goods outside the shop to be refused, got %d", response.GetResult())
+	}
+	if response := sendActivityOperation(t, client, 71, 900, 0xFFFFFFFF); response.GetResult() != activityResultLimit {
+		t.Fatalf("expected a count over the cap to be refused, got %d", response.GetResult())
+	}
+	if response := sendActivityOperation(t, client, 71, 900, 2); response.GetResult() != activityResultOK {
+		t.Fatalf("expected purchase to succeed, got %d", response.GetResult())
+	}
+	if response := sendActivityOperation(t, client, 71, 900, 1); response.GetResult() != activityResultLimit {
+		t.Fatalf("expected purchase cap, got %d", response.GetResult())
+	}
+	if client.Commander.GetResourceCount(1) != 80 || client.Commander.GetItemCount(20001) != 2 {
+		t.Fatalf("unexpected inventory after purchases")
+	}
+	progress, err := orm.GetActivityEventProgress(client.Commander.CommanderID, 71)
+	if err != nil {
+		t.Fatalf("load progress: %v", err)
+	}
+	if progress.ShopPurchases[900] != 2 {
+		t.Fatalf("expected 2 purchases, got %d", progress.ShopPurchases[900])
+	}
+}
+
+func TestActivityOperationLoginSignOncePerDay(t *testing.T) {
+	client := setupActivityEventTest(t, 0)
+	seedConfigEntry(t, "ShareCfg/activity_template.json", "72", `{"id":72,"type":5,"config_data":[[[1,1,5]],[[1,1,7]]]}`)
+	seedActivityAllowlist(t, []uint32{72})
+
+	if response := sendActivityOperation(t, client, 72, 0, 0); response.GetResult() != activityResultOK {
+		t.Fatalf("expected first sign-in to succeed, got %d", response.GetResult())
+	}
+	if response := sendActivityOperation(t, client, 72, 0, 0); response.GetResult() != activityResultInvalid {
+		t.Fatalf("expected second sign-in of the day to fail, got %d", response.GetResult())
+	}
+	if client.Commander.GetResourceCount(1) != 5 {
+		t.Fatalf("expected the first day reward only")
+	}
+
+	if err := ResetActivityEventProgress(client.Commander.CommanderID, 72); err != nil {
+		t.Fatalf("reset progress: %v", err)
+	}
+	progress, err := orm.GetActivityEventProgress(client.Commander.CommanderID, 72)
+	if err != nil {
+		t.Fatalf("load progress: %v", err)
+	}
+	if progress.SignDays != 0 || progress.LastSignAt != 0 {
+		t.Fatalf("expected progress to be reset, got %+v", progress)
+	}
+}
@@ -3,6 +3,7 @@ package answer
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/orm"
@@ -26,12 +27,31 @@ func ActivityOperation(buffer *[]byte, client *connection.Client) (int, int, err
 		return 0, 11203, err
 	}
 
+	switch template.Type {
+	case activityTypeEventPt, activityTypeEventShop, activityTypeLoginSign, activityTypeTasks:
+		open, err := activityOpen(template.ID, time.Now())
+		if err != nil {
+			return 0, 11203, err
+		}
+		if !open {
+			return sendActivityOperationResult(client, activityResultInvalid, nil)
+		}
+	}
+
 	switch template.Type {
 	case activityTypeEventSingle:
 		if payload.GetCmd() != activityCmdSingleEventRefresh {
 			return 0, 11203, fmt.Errorf("unsupported single event cmd: %d", payload.GetCmd())
 		}
 		return handleSingleEventRefresh(template.ConfigData, client)
+	case activityTypeEventPt:
+		return handleEventPtClaim(template, &payload, client)
+	case activityTypeEventShop:
+		return handleEventShopBuy(template, &payload, client)
+	case activityTypeLoginSign:
+		return handleLoginSign(template, &payload, client)
+	case activityTypeTasks:
+		return handleActivityTaskClaim(template, &payload, client)
 	default:
 		return handleActivityOperationNoop(client)
 	}
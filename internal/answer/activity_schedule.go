@@ -0,0 +1,295 @@
+package answer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/region"
+)
+
+const (
+	ActivitySourceTemplate  = "template"
+	ActivitySourceAllowlist = "allowlist"
+	ActivitySourcePreview   = "preview"
+	ActivitySourceExtend    = "extend"
+
+	// activitySchedulerInterval is how often the scheduler looks for
+	// activities opening or closing.
+	activitySchedulerInterval = time.Minute
+)
+
+// ActivityWindow is the time an activity is open. A zero Start or End leaves
+// that side unbounded.
+type ActivityWindow struct {
+	ActivityID uint32
+	Type       uint32
+	Start      time.Time
+	End        time.Time
+	Source     string
+	Extended   bool
+	ForceEnded bool
+	template   activityTemplate
+}
+
+func (window ActivityWindow) OpenAt(now time.Time) bool {
+	if !window.Start.IsZero() && now.Before(window.Start) {
+		return false
+	}
+	return window.End.IsZero() || now.Before(window.End)
+}
+
+// Overlaps reports whether the window is open at some point of [from, to).
+func (window ActivityWindow) Overlaps(from time.Time, to time.Time) bool {
+	if !window.Start.IsZero() && !window.Start.Before(to) {
+		return false
+	}
+	return window.End.IsZero() || window.End.After(from)
+}
+
+// stopTime is the stop_time sent to the client.
+func (window ActivityWindow) stopTime() uint32 {
+	if !window.End.IsZero() {
+		return uint32(window.End.Unix())
+	}
+	return activityStopTime(window.template.Time)
+}
+
+// ActivitySchedule resolves the time window of every scheduled activity of
+// regionName: the timers of activity_template, the allowlist (always open)
+// and the admin overrides, in that order. Windows are sorted by activity id.
+func ActivitySchedule(regionName string) ([]ActivityWindow, error) {
+	templates, err := gamedata.List[activityTemplate](gamedata.Default, activityTemplateCategory)
+	if err != nil {
+		return nil, err
+	}
+	allowlist, err := loadActivityAllowlist()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := orm.ListActivityScheduleOverrides()
+	if err != nil {
+		return nil, err
+	}
+	return resolveActivitySchedule(templates, allowlist, overrides, regionName), nil
+}
+
+func resolveActivitySchedule(templates []activityTemplate, allowlist []uint32, overrides []orm.ActivityScheduleOverride, regionName string) []ActivityWindow {
+	byID := make(map[uint32]activityTemplate, len(templates))
+	windows := map[uint32]*ActivityWindow{}
+	for _, template := range templates {
+		byID[template.ID] = template
+		start, stop, ok := parseActivityTimer(template.Time)
+		if !ok {
+			continue
+		}
+		windows[template.ID] = &ActivityWindow{ActivityID: template.ID, Type: template.Type, Start: start, End: stop, Source: ActivitySourceTemplate, template: template}
+	}
+	window := func(activityID uint32, source string) *ActivityWindow {
+		if existing, ok := windows[activityID]; ok {
+			return existing
+		}
+		template, ok := byID[activityID]
+		if !ok {
+			template = activityTemplate{ID: activityID}
+		}
+		created := &ActivityWindow{ActivityID: activityID, Type: template.Type, Source: source, template: template}
+		windows[activityID] = created
+		return created
+	}
+	for _, activityID := range allowlist {
+		allowed := window(activityID, ActivitySourceAllowlist)
+		allowed.Source = ActivitySourceAllowlist
+		allowed.Start = time.Time{}
+		allowed.End = time.Time{}
+	}
+	for _, override := range overrides {
+		if override.Region != "" && override.Region != regionName {
+			continue
+		}
+		switch override.Action {
+		case orm.ActivityOverridePreview:
+			previewed := window(override.ActivityID, ActivitySourcePreview)
+			previewed.Source = ActivitySourcePreview
+			previewed.Start = time.Time{}
+			if override.StartsAt != nil {
+				previewed.Start = *override.StartsAt
+			}
+			if override.EndsAt != nil && (previewed.End.IsZero() || override.EndsAt.After(previewed.End)) {
+				previewed.End = *override.EndsAt
+			}
+		case orm.ActivityOverrideExtend:
+			if override.EndsAt == nil {
+				continue
+			}
+			extended := window(override.ActivityID, ActivitySourceExtend)
+			if extended.Source == ActivitySourceAllowlist {
+				// already open for good
+				continue
+			}
+			if extended.End.IsZero() && extended.Source != ActivitySourceExtend {
+				continue
+			}
+			if extended.End.IsZero() || override.EndsAt.After(extended.End) {
+				extended.End = *override.EndsAt
+				extended.Extended = true
+			}
+		case orm.ActivityOverrideForceEnd:
+			endsAt := override.CreatedAt
+			if override.EndsAt != nil {
+				endsAt = *override.EndsAt
+			}
+			ended, ok := windows[override.ActivityID]
+			if !ok {
+				continue
+			}
+			if ended.End.IsZero() || endsAt.Before(ended.End) {
+				ended.End = endsAt
+			}
+			ended.ForceEnded = true
+		}
+	}
+	resolved := make([]ActivityWindow, 0, len(windows))
+	for _, window := range windows {
+		resolved = append(resolved, *window)
+	}
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].ActivityID < resolved[j].ActivityID
+	})
+	return resolved
+}
+
+// openActivityIDs returns the ids of the windows open at now.
+func openActivityIDs(windows []ActivityWindow, now time.Time) map[uint32]ActivityWindow {
+	open := map[uint32]ActivityWindow{}
+	for _, window := range windows {
+		if window.OpenAt(now) {
+			open[window.ActivityID] = window
+		}
+	}
+	return open
+}
+
+// activityTransitions returns the activities opened and closed between two
+// runs of the scheduler.
+func activityTransitions(previous map[uint32]ActivityWindow, current map[uint32]ActivityWindow) ([]uint32, []uint32) {
+	opened := []uint32{}
+	closed := []uint32{}
+	for activityID := range current {
+		if _, ok := previous[activityID]; !ok {
+			opened = append(opened, activityID)
+		}
+	}
+	for activityID := range previous {
+		if _, ok := current[activityID]; !ok {
+			closed = append(closed, activityID)
+		}
+	}
+	sort.Slice(opened, func(i, j int) bool { return opened[i] < opened[j] })
+	sort.Slice(closed, func(i, j int) bool { return closed[i] < closed[j] })
+	return opened, closed
+}
+
+var activityScheduler = struct {
+	mu      sync.Mutex
+	started bool
+	open    map[uint32]ActivityWindow
+	wake    chan struct{}
+}{wake: make(chan struct{}, 1)}
+
+// RunActivityScheduler opens and closes activities as their windows start
+// and end, pushing the change to online commanders. It never returns.
+func RunActivityScheduler() {
+	ticker := time.NewTicker(activitySchedulerInterval)
+	defer ticker.Stop()
+	for {
+		if err := tickActivityScheduler(time.Now()); err != nil {
+			logger.LogEvent("Activities", "Scheduler", err.Error(), logger.LOG_LEVEL_ERROR)
+		}
+		select {
+		case <-ticker.C:
+		case <-activityScheduler.wake:
+		}
+	}
+}
+
+// RefreshActivitySchedule makes the scheduler re-evaluate the windows now,
+// e.g. after an override changed.
+func RefreshActivitySchedule() {
+	select {
+	case activityScheduler.wake <- struct{}{}:
+	default:
+	}
+}
+
+func tickActivityScheduler(now time.Time) error {
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return err
+	}
+	current := openActivityIDs(windows, now)
+	activityScheduler.mu.Lock()
+	previous := activityScheduler.open
+	started := activityScheduler.started
+	activityScheduler.open = current
+	activityScheduler.started = true
+	activityScheduler.mu.Unlock()
+	if !started {
+		// clients fetch the list when logging in, only changes are pushed
+		return nil
+	}
+	opened, closed := activityTransitions(previous, current)
+	if len(opened) == 0 && len(closed) == 0 {
+		return nil
+	}
+	logger.LogEvent("Activities", "Scheduler", fmt.Sprintf("opened=%v closed=%v", opened, closed), logger.LOG_LEVEL_INFO)
+	pushActivityTransitions(current, opened, previous, closed, now)
+	return nil
+}
+
+func pushActivityTransitions(current map[uint32]ActivityWindow, opened []uint32, previous map[uint32]ActivityWindow, closed []uint32, now time.Time) {
+	server := connection.BelfastInstance
+	if server == nil {
+		return
+	}
+	updates := make([]*protobuf.ACTIVITYINFO, 0, len(opened)+len(closed))
+	for _, activityID := range opened {
+		window := current[activityID]
+		info, err := buildActivityInfo(window.template, window.stopTime())
+		if err != nil {
+			logger.LogEvent("Activities", "Scheduler", fmt.Sprintf("failed to build activity %d: %s", activityID, err.Error()), logger.LOG_LEVEL_WARN)
+			continue
+		}
+		if info != nil {
+			updates = append(updates, info)
+		}
+	}
+	for _, activityID := range closed {
+		stop := previous[activityID].End
+		if stop.IsZero() || stop.After(now) {
+			stop = now
+		}
+		updates = append(updates, &protobuf.ACTIVITYINFO{Id: proto.Uint32(activityID), StopTime: proto.Uint32(uint32(stop.Unix()))})
+	}
+	for _, client := range server.ListClients() {
+		if client.Commander == nil {
+			continue
+		}
+		for _, info := range updates {
+			update := protobuf.SC_11201{ActivityInfo: info}
+			// the scheduler runs outside the dispatcher of the client
+			if err := client.Push(11201, &update); err != nil {
+				logger.LogEvent("Activities", "Scheduler", fmt.Sprintf("failed to push activity %d to %d: %s", info.GetId(), client.Commander.CommanderID, err.Error()), logger.LOG_LEVEL_DEBUG)
+				break
+			}
+		}
+	}
+}
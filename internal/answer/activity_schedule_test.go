@@ -0,0 +1,78 @@
+package answer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+func scheduleTestTime(day int) time.Time {
+	return time.Date(2030, time.January, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestResolveActivitySchedule(t *testing.T) {
+	templates := []activityTemplate{
+		{ID: 1, Type: 5, Time: json.RawMessage(`["timer",[[2030,1,1],[0,0,0]],[[2030,1,8],[0,0,0]]]`)},
+		{ID: 2, Time: json.RawMessage(`["timer",[[2030,1,10],[0,0,0]],[[2030,1,20],[0,0,0]]]`)},
+		{ID: 3, Time: json.RawMessage(`"always"`)},
+		{ID: 4, Time: json.RawMessage(`["timer",[[2029,1,1],[0,0,0]],[[2029,2,1],[0,0,0]]]`)},
+		{ID: 5, Time: json.RawMessage(`"stop"`)},
+	}
+	extendTo := scheduleTestTime(12)
+	previewFrom := scheduleTestTime(5)
+	forceAt := scheduleTestTime(15)
+	overrides := []orm.ActivityScheduleOverride{
+		{ActivityID: 1, Action: orm.ActivityOverrideExtend, EndsAt: &extendTo},
+		{ActivityID: 2, Action: orm.ActivityOverridePreview, Region: "EN", StartsAt: &previewFrom},
+		{ActivityID: 2, Action: orm.ActivityOverrideForceEnd, EndsAt: &forceAt},
+		{ActivityID: 5, Action: orm.ActivityOverridePreview, Region: "JP"},
+		{ActivityID: 3, Action: orm.ActivityOverrideForceEnd, CreatedAt: scheduleTestTime(3)},
+		{ActivityID: 9, Action: orm.ActivityOverrideForceEnd, CreatedAt: scheduleTestTime(3)},
+	}
+	windows := resolveActivitySchedule(templates, []uint32{4, 3}, overrides, "EN")
+	byID := map[uint32]ActivityWindow{}
+	for _, window := range windows {
+		byID[window.ActivityID] = window
+	}
+	if len(windows) != 4 {
+		t.Fatalf("expected 4 windows, got %+v", windows)
+	}
+	if window := byID[1]; !window.Extended || !window.End.Equal(extendTo) || window.Type != 5 || window.Source != ActivitySourceTemplate {
+		t.Fatalf("unexpected extended window %+v", window)
+	}
+	if window := byID[2]; window.Source != ActivitySourcePreview || !window.Start.Equal(previewFrom) || !window.End.Equal(forceAt) || !window.ForceEnded {
+		t.Fatalf("unexpected previewed window %+v", window)
+	}
+	if window := byID[3]; window.Source != ActivitySourceAllowlist || !window.End.Equal(scheduleTestTime(3)) || !window.ForceEnded {
+		t.Fatalf("expected force end to close an allowlisted activity, got %+v", window)
+	}
+	if window := byID[4]; window.Source != ActivitySourceAllowlist || !window.OpenAt(scheduleTestTime(1)) || window.stopTime() != uint32(time.Date(2029, time.February, 1, 0, 0, 0, 0, time.UTC).Unix()) {
+		t.Fatalf("expected allowlisted activity to stay open with its template stop time, got %+v", window)
+	}
+	if _, ok := byID[5]; ok {
+		t.Fatalf("expected the JP preview to be ignored in EN")
+	}
+
+	open := openActivityIDs(windows, scheduleTestTime(6))
+	if _, ok := open[1]; !ok {
+		t.Fatalf("expected activity 1 to be open on day 6")
+	}
+	if _, ok := open[3]; ok {
+		t.Fatalf("expected activity 3 to be force-ended on day 6")
+	}
+	if !byID[1].Overlaps(scheduleTestTime(11), scheduleTestTime(13)) || byID[1].Overlaps(scheduleTestTime(12), scheduleTestTime(13)) {
+		t.Fatalf("unexpected overlap of the extended window")
+	}
+}
+
+func TestActivityTransitions(t *testing.T) {
+	previous := map[uint32]ActivityWindow{1: {}, 2: {}}
+	current := map[uint32]ActivityWindow{2: {}, 3: {}, 4: {}}
+	opened, closed := activityTransitions(previous, current)
+	if !reflect.DeepEqual(opened, []uint32{3, 4}) || !reflect.DeepEqual(closed, []uint32{1}) {
+		t.Fatalf("unexpected transitions opened=%v closed=%v", opened, closed)
+	}
+}
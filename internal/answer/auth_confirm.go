@@ -13,12 +13,12 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-var protoValidAnswer protobuf.SC_10021
-
-func updateServerList(servers []config.ServerConfig) {
+// updateServerList refreshes the shared server list and returns it.
+func updateServerList(servers []config.ServerConfig) []*protobuf.SERVERINFO {
 	statuses := getServerStatusCache(servers)
-	Servers = buildServerInfo(servers, statuses)
-	protoValidAnswer.Serverlist = Servers
+	list := buildServerInfo(servers, statuses)
+	Servers = list
+	return list
 }
 
 func Forge_SC10021(buffer *[]byte, client *connection.Client) (int, int, error) {
@@ -36,7 +36,12 @@ func Forge_SC10021(buffer *[]byte, client *connection.Client) (int, int, error)
 		return 0, 10021, fmt.Errorf("failed to convert arg2 to int: %s", err.Error())
 	}
 	client.AuthArg2 = uint32(intArg2)
-	protoValidAnswer.ServerTicket = proto.String(formatServerTicket(client.AuthArg2))
+	// each login gets its own response: the ticket is signed for this account
+	response := protobuf.SC_10021{
+		Result: proto.Uint32(0),
+		Device: proto.Uint32(0),
+	}
+	response.ServerTicket = proto.String(formatServerTicket(client.AuthArg2, serverTicketTarget(config.Current().Servers)))
 
 	yostarusAuth, err := orm.GetYostarusMapByArg2(uint32(intArg2))
 	if err != nil {
@@ -48,27 +53,20 @@ func Forge_SC10021(buffer *[]byte, client *connection.Client) (int, int, error)
 					logger.LogEvent("Server", "SC_10021", fmt.Sprintf("failed to create commander: %s", err.Error()), logger.LOG_LEVEL_ERROR)
 					return 0, 10021, err
 				}
-				protoValidAnswer.AccountId = proto.Uint32(accountID)
+				response.AccountId = proto.Uint32(accountID)
 			} else {
-				protoValidAnswer.AccountId = proto.Uint32(0) // CS_10024 handles account creation.
+				response.AccountId = proto.Uint32(0) // CS_10024 handles account creation.
 			}
 		} else {
 			logger.LogEvent("Server", "SC_10021", fmt.Sprintf("failed to fetch account for arg2 %d: %s", intArg2, err.Error()), logger.LOG_LEVEL_ERROR)
 			return 0, 10021, err
 		}
 	} else {
-		protoValidAnswer.AccountId = proto.Uint32(yostarusAuth.AccountID)
+		response.AccountId = proto.Uint32(yostarusAuth.AccountID)
 	}
 
 	// Update server list
-	updateServerList(config.Current().Servers)
-	logger.LogEvent("Server", "SC_10021", fmt.Sprintf("sending %d servers", len(protoValidAnswer.Serverlist)), logger.LOG_LEVEL_WARN)
-	return client.SendMessage(10021, &protoValidAnswer)
-}
-
-func init() {
-	protoValidAnswer.ServerTicket = proto.String("=*=*=*=BELFAST=*=*=*=")
-	protoValidAnswer.Serverlist = Servers
-	protoValidAnswer.Result = proto.Uint32(0)
-	protoValidAnswer.Device = proto.Uint32(0)
+	response.Serverlist = updateServerList(config.Current().Servers)
+	logger.LogEvent("Server", "SC_10021", fmt.Sprintf("sending %d servers", len(response.Serverlist)), logger.LOG_LEVEL_WARN)
+	return client.SendMessage(10021, &response)
 }
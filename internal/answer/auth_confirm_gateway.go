@@ -25,13 +25,10 @@ func Forge_SC10021_Gateway(buffer *[]byte, client *connection.Client) (int, int,
 	response := protobuf.SC_10021{
 		Result:       proto.Uint32(0),
 		AccountId:    proto.Uint32(0),
-		ServerTicket: proto.String(formatServerTicket(client.AuthArg2)),
+		ServerTicket: proto.String(formatServerTicket(client.AuthArg2, serverTicketTarget(config.Current().Servers))),
 		Device:       proto.Uint32(0),
 	}
-	servers := config.Current().Servers
-	statuses := getServerStatusCache(servers)
-	Servers = buildServerInfo(servers, statuses)
-	response.Serverlist = Servers
+	response.Serverlist = updateServerList(config.Current().Servers)
 	logger.LogEvent("Server", "SC_10021", fmt.Sprintf("sending %d servers", len(response.Serverlist)), logger.LOG_LEVEL_WARN)
 	return client.SendMessage(10021, &response)
 }
@@ -69,7 +69,7 @@ func HandleLocalLogin(payload *protobuf.CS_10020, client *connection.Client) (in
 	response := protobuf.SC_10021{
 		Result:       proto.Uint32(localLoginResultOK),
 		AccountId:    proto.Uint32(0),
-		ServerTicket: proto.String(formatServerTicket(client.AuthArg2)),
+		ServerTicket: proto.String(formatServerTicket(client.AuthArg2, serverTicketTarget(config.Current().Servers))),
 		Device:       proto.Uint32(0),
 	}
 
@@ -94,8 +94,7 @@ func HandleLocalLogin(payload *protobuf.CS_10020, client *connection.Client) (in
 		response.AccountId = proto.Uint32(mapping.AccountID)
 	}
 
-	updateServerList(config.Current().Servers)
-	response.Serverlist = Servers
+	response.Serverlist = updateServerList(config.Current().Servers)
 	logger.LogEvent("Server", "SC_10021", fmt.Sprintf("sending %d servers", len(response.Serverlist)), logger.LOG_LEVEL_WARN)
 	return client.SendMessage(10021, &response)
 }
@@ -104,10 +103,9 @@ func sendLocalLoginFailure(client *connection.Client, result uint32) (int, int,
 	response := protobuf.SC_10021{
 		Result:       proto.Uint32(result),
 		AccountId:    proto.Uint32(0),
-		ServerTicket: proto.String(formatServerTicket(0)),
+		ServerTicket: proto.String(formatServerTicket(0, 0)),
 		Device:       proto.Uint32(0),
 	}
-	updateServerList(config.Current().Servers)
-	response.Serverlist = Servers
+	response.Serverlist = updateServerList(config.Current().Servers)
 	return client.SendMessage(10021, &response)
 }
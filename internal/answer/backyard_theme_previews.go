@@ -0,0 +1,87 @@
+package answer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/objectstore"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const themePreviewTimeout = 10 * time.Second
+
+// verifyThemePreviews checks that both previews of a template were uploaded
+// by the commander, reporting whether they were checked at all: nothing is
+// checked while storage is disabled.
+func verifyThemePreviews(commanderID uint32, iconMd5 string, imageMd5 string) (bool, error) {
+	store := objectstore.Current()
+	if store == nil {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), themePreviewTimeout)
+	defer cancel()
+	for _, md5 := range []string{iconMd5, imageMd5} {
+		if err := objectstore.Verify(ctx, store, commanderID, md5); err != nil {
+			return false, fmt.Errorf("preview %q: %w", md5, err)
+		}
+	}
+	return true, nil
+}
+
+// themePreviewAvailable reports whether the previews of a published version
+// can be downloaded. They were verified when it was published, so the store
+// is not checked again. It is always true while storage is disabled.
+func themePreviewAvailable(version *orm.BackyardPublishedThemeVersion) bool {
+	return objectstore.Current() == nil || version.PreviewsVerified
+}
+
+// collectThemePreviews deletes the previews no published theme of the
+// commander refers to anymore. Failures are only logged: a leftover preview
+// is harmless.
+func collectThemePreviews(commanderID uint32, md5s ...string) {
+	store := objectstore.Current()
+	if store == nil {
+		return
+	}
+	inUse, err := orm.ListBackyardPublishedPreviewMd5s(commanderID)
+	if err != nil {
+		logger.LogEvent("Backyard", "Previews", fmt.Sprintf("failed to list previews of %d: %s", commanderID, err.Error()), logger.LOG_LEVEL_WARN)
+		return
+	}
+	used := make(map[string]struct{}, len(inUse))
+	for md5 := range inUse {
+		used[strings.ToLower(md5)] = struct{}{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), themePreviewTimeout)
+	defer cancel()
+	for _, md5 := range md5s {
+		md5 = strings.ToLower(md5)
+		if _, ok := used[md5]; ok || !objectstore.ValidMd5(md5) {
+			continue
+		}
+		used[md5] = struct{}{}
+		if err := store.Delete(ctx, objectstore.PreviewKey(commanderID, md5)); err != nil {
+			logger.LogEvent("Backyard", "Previews", fmt.Sprintf("failed to delete preview %s of %d: %s", md5, commanderID, err.Error()), logger.LOG_LEVEL_WARN)
+		}
+	}
+}
+
+// themePreviewMd5s lists the previews of every published version of a
+// theme, which may differ from the ones of its template.
+func themePreviewMd5s(themeID string) []string {
+	if objectstore.Current() == nil {
+		return nil
+	}
+	md5s, err := orm.ListBackyardThemePreviewMd5s(themeID)
+	if err != nil {
+		return nil
+	}
+	list := make([]string, 0, len(md5s))
+	for md5 := range md5s {
+		list = append(list, md5)
+	}
+	return list
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/objectstore"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"github.com/jackc/pgx/v5"
@@ -15,6 +18,7 @@ import (
 )
 
 var errThemeTemplateLimit = errors.New("theme template limit exceeded")
+var errThemePreviewsChanged = errors.New("theme previews changed while publishing")
 
 const (
 	legacyThemeListTypeRecommended = int32(1)
@@ -25,9 +29,9 @@ const (
 	maxLegacyThemeListPayloadBytes = maxPacketSizeBytes - packetHeaderSizeBytes
 )
 
+// GetOSSArgs19103 hands out the credentials previews are uploaded with.
+// While storage is disabled the credentials are empty.
 func GetOSSArgs19103(buffer *[]byte, client *connection.Client) (int, int, error) {
-	// TODO: integrate real OSS/S3 creds for publishing previews.
-	// For now, return success with empty credentials.
 	resp := protobuf.SC_19104{
 		Result:        proto.Uint32(0),
 		AccessId:      proto.String(""),
@@ -35,6 +39,22 @@ func GetOSSArgs19103(buffer *[]byte, client *connection.Client) (int, int, error
 		ExpireTime:    proto.Uint32(0),
 		SecurityToken: proto.String(""),
 	}
+	store := objectstore.Current()
+	if store == nil {
+		return client.SendMessage(19104, &resp)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), themePreviewTimeout)
+	defer cancel()
+	creds, err := store.Credentials(ctx, client.Commander.CommanderID, time.Now())
+	if err != nil {
+		logger.LogEvent("Backyard", "Previews", fmt.Sprintf("failed to issue credentials to %d: %s", client.Commander.CommanderID, err.Error()), logger.LOG_LEVEL_WARN)
+		resp.Result = proto.Uint32(1)
+		return client.SendMessage(19104, &resp)
+	}
+	resp.AccessId = proto.String(creds.AccessID)
+	resp.AccessSecret = proto.String(creds.AccessSecret)
+	resp.ExpireTime = proto.Uint32(uint32(creds.Expires.Unix()))
+	resp.SecurityToken = proto.String(creds.SecurityToken)
 	return client.SendMessage(19104, &resp)
 }
 
@@ -137,6 +157,19 @@ func PublishCustomThemeTemplate19111(buffer *[]byte, client *connection.Client)
 	commanderID := client.Commander.CommanderID
 	pos := request.GetPos()
 
+	// previews are checked before the transaction: it may take a round trip
+	// to the object store
+	template, err := orm.GetBackyardCustomThemeTemplate(commanderID, pos)
+	if err != nil {
+		resp := protobuf.SC_19112{Result: proto.Int32(1)}
+		return client.SendMessage(19112, &resp)
+	}
+	verified, err := verifyThemePreviews(commanderID, template.IconImageMd5, template.ImageMd5)
+	if err != nil {
+		resp := protobuf.SC_19112{Result: proto.Int32(1)}
+		return client.SendMessage(19112, &resp)
+	}
+
 	ctx := context.Background()
 	if err := db.DefaultStore.WithPGXTx(ctx, func(tx pgx.Tx) error {
 		var entry orm.BackyardCustomThemeTemplate
@@ -148,6 +181,9 @@ WHERE commander_id = $1 AND pos = $2
 		if err := row.Scan(&entry.CommanderID, &entry.Pos, &entry.Name, &entry.FurniturePutList, &entry.IconImageMd5, &entry.ImageMd5, &entry.UploadTime); err != nil {
 			return err
 		}
+		if entry.IconImageMd5 != template.IconImageMd5 || entry.ImageMd5 != template.ImageMd5 {
+			return errThemePreviewsChanged
+		}
 		if entry.UploadTime == 0 {
 			var publishedCount int64
 			if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM backyard_custom_theme_templates WHERE commander_id = $1 AND upload_time > 0`, int64(commanderID)).Scan(&publishedCount); err != nil {
@@ -157,7 +193,7 @@ WHERE commander_id = $1 AND pos = $2
 				return errThemeTemplateLimit
 			}
 		}
-		version, err := orm.CreateBackyardPublishedThemeVersionTx(ctx, tx, commanderID, pos, entry.Name, entry.FurniturePutList, entry.IconImageMd5, entry.ImageMd5)
+		version, err := orm.CreateBackyardPublishedThemeVersionTx(ctx, tx, commanderID, pos, entry.Name, entry.FurniturePutList, entry.IconImageMd5, entry.ImageMd5, verified)
 		if err != nil {
 			return err
 		}
@@ -188,6 +224,7 @@ func UnpublishCustomThemeTemplate19125(buffer *[]byte, client *connection.Client
 		return client.SendMessage(19126, &resp)
 	}
 	themeID := orm.BackyardThemeID(commanderID, pos)
+	previews := append(themePreviewMd5s(themeID), entry.IconImageMd5, entry.ImageMd5)
 	ctx := context.Background()
 	if err := db.DefaultStore.WithPGXTx(ctx, func(tx pgx.Tx) error {
 		_, err := tx.Exec(ctx, `UPDATE backyard_custom_theme_templates SET upload_time = 0 WHERE commander_id = $1 AND pos = $2`, int64(commanderID), int64(pos))
@@ -199,6 +236,7 @@ func UnpublishCustomThemeTemplate19125(buffer *[]byte, client *connection.Client
 		resp := protobuf.SC_19126{Result: proto.Int32(1)}
 		return client.SendMessage(19126, &resp)
 	}
+	collectThemePreviews(commanderID, previews...)
 	resp := protobuf.SC_19126{Result: proto.Int32(0)}
 	return client.SendMessage(19126, &resp)
 }
@@ -210,16 +248,22 @@ func DeleteCustomThemeTemplate19123(buffer *[]byte, client *connection.Client) (
 	}
 	commanderID := client.Commander.CommanderID
 	pos := request.GetPos()
+	themeID := orm.BackyardThemeID(commanderID, pos)
+	previews := themePreviewMd5s(themeID)
+	if entry, err := orm.GetBackyardCustomThemeTemplate(commanderID, pos); err == nil {
+		previews = append(previews, entry.IconImageMd5, entry.ImageMd5)
+	}
 	ctx := context.Background()
 	if err := db.DefaultStore.WithPGXTx(ctx, func(tx pgx.Tx) error {
 		if err := orm.DeleteBackyardCustomThemeTemplateTx(ctx, tx, commanderID, pos); err != nil {
 			return err
 		}
-		return orm.DeleteBackyardPublishedThemeVersionsByThemeIDTx(ctx, tx, orm.BackyardThemeID(commanderID, pos))
+		return orm.DeleteBackyardPublishedThemeVersionsByThemeIDTx(ctx, tx, themeID)
 	}); err != nil {
 		resp := protobuf.SC_19124{Result: proto.Int32(1)}
 		return client.SendMessage(19124, &resp)
 	}
+	collectThemePreviews(commanderID, previews...)
 	resp := protobuf.SC_19124{Result: proto.Int32(0)}
 	return client.SendMessage(19124, &resp)
 }
@@ -281,7 +325,7 @@ func GetPreviewMd5s19131(buffer *[]byte, client *connection.Client) (int, int, e
 	list := make([]*protobuf.THEME_MD5, 0, len(request.GetIdList()))
 	for _, id := range request.GetIdList() {
 		ver, err := orm.LatestBackyardPublishedThemeVersion(id)
-		if err != nil {
+		if err != nil || !themePreviewAvailable(ver) {
 			continue
 		}
 		list = append(list, &protobuf.THEME_MD5{Id: proto.String(id), Md5: proto.String(ver.IconImageMd5)})
@@ -1,12 +1,18 @@
 package answer_test
 
 import (
+	"bytes"
+	"crypto/md5"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/ggmolly/belfast/internal/answer"
+	"github.com/ggmolly/belfast/internal/config"
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/objectstore"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/packets"
 	"github.com/ggmolly/belfast/internal/protobuf"
@@ -398,3 +404,86 @@ func TestGetThemeShopList19117Regression(t *testing.T) {
 		t.Fatalf("expected theme id %s in list, got %v", themeID, resp.GetThemeIdList())
 	}
 }
+
+func TestThemePreviewsVerifiedAndCollected(t *testing.T) {
+	dir := t.TempDir()
+	if err := objectstore.Configure(config.StorageConfig{Provider: objectstore.LocalName, Dir: dir, Secret: "test"}); err != nil {
+		t.Fatalf("configure storage: %v", err)
+	}
+	t.Cleanup(func() { _ = objectstore.Configure(config.StorageConfig{}) })
+	local := objectstore.Current().(*objectstore.Local)
+
+	client := newThemeTestClient(t)
+	commanderID := client.Commander.CommanderID
+	icon := []byte("icon preview")
+	image := []byte("image preview")
+	iconMd5 := fmt.Sprintf("%x", md5.Sum(icon))
+	imageMd5 := fmt.Sprintf("%x", md5.Sum(image))
+	execAnswerExternalTestSQLT(t, "INSERT INTO backyard_custom_theme_templates (commander_id, pos, name, furniture_put_list, icon_image_md5, image_md5, upload_time) VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7)", int64(commanderID), int64(1), "t1", `[]`, iconMd5, imageMd5, int64(0))
+
+	buf, err := proto.Marshal(&protobuf.CS_19111{Pos: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := answer.PublishCustomThemeTemplate19111(&buf, client); err != nil {
+		t.Fatalf("PublishCustomThemeTemplate19111 failed: %v", err)
+	}
+	resp := &protobuf.SC_19112{}
+	decodeResponse(t, client, 19112, resp)
+	if resp.GetResult() != 1 {
+		t.Fatalf("expected publish without previews to fail, got %d", resp.GetResult())
+	}
+
+	if _, err := local.Put(objectstore.PreviewKey(commanderID, iconMd5), bytes.NewReader(icon), iconMd5); err != nil {
+		t.Fatalf("upload icon: %v", err)
+	}
+	if _, err := local.Put(objectstore.PreviewKey(commanderID, imageMd5), bytes.NewReader(image), imageMd5); err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := answer.PublishCustomThemeTemplate19111(&buf, client); err != nil {
+		t.Fatalf("PublishCustomThemeTemplate19111 failed: %v", err)
+	}
+	decodeResponse(t, client, 19112, resp)
+	if resp.GetResult() != 0 {
+		t.Fatalf("expected publish to succeed, got %d", resp.GetResult())
+	}
+
+	themeID := orm.BackyardThemeID(commanderID, 1)
+	// versions published without verified previews are not listed
+	unverifiedID := orm.BackyardThemeID(commanderID, 2)
+	execAnswerExternalTestSQLT(t, "INSERT INTO backyard_published_theme_versions (theme_id, upload_time, owner_id, pos, name, furniture_put_list, icon_image_md5, image_md5, like_count, fav_count) VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, $8, 0, 0)", unverifiedID, int64(1), int64(commanderID), int64(2), "t2", `[]`, iconMd5, imageMd5)
+	md5Buf, err := proto.Marshal(&protobuf.CS_19131{IdList: []string{themeID, unverifiedID}})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := answer.GetPreviewMd5s19131(&md5Buf, client); err != nil {
+		t.Fatalf("GetPreviewMd5s19131 failed: %v", err)
+	}
+	md5Resp := &protobuf.SC_19132{}
+	decodeResponse(t, client, 19132, md5Resp)
+	if len(md5Resp.GetList()) != 1 || md5Resp.GetList()[0].GetMd5() != iconMd5 {
+		t.Fatalf("unexpected preview md5s %v", md5Resp.GetList())
+	}
+
+	unpublishBuf, err := proto.Marshal(&protobuf.CS_19125{Pos: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := answer.UnpublishCustomThemeTemplate19125(&unpublishBuf, client); err != nil {
+		t.Fatalf("UnpublishCustomThemeTemplate19125 failed: %v", err)
+	}
+	unpublishResp := &protobuf.SC_19126{}
+	decodeResponse(t, client, 19126, unpublishResp)
+	if unpublishResp.GetResult() != 0 {
+		t.Fatalf("expected unpublish to succeed, got %d", unpublishResp.GetResult())
+	}
+	for _, md5 := range []string{iconMd5, imageMd5} {
+		if _, err := os.Stat(filepath.Join(dir, "backyard", fmt.Sprint(commanderID), md5)); !os.IsNotExist(err) {
+			t.Fatalf("expected preview %s to be deleted, got %v", md5, err)
+		}
+	}
+}
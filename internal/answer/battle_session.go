@@ -4,28 +4,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/consts"
 	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/loot"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/region"
 	"google.golang.org/protobuf/proto"
 )
 
 var battleSessionKey uint32
 
 const (
-	battleSystemScenario = 1
-	battleSystemRoutine  = 2
-	battleSystemDuel     = 3
-	battleSystemSub      = 11
-	battleSystemWorld    = 51
+	battleSystemScenario  = 1
+	battleSystemRoutine   = 2
+	battleSystemDuel      = 3
+	battleSystemChallenge = 6
+	battleSystemSub       = 11
+	battleSystemWorld     = 51
+	battleSystemGuild     = 61
+	battleSystemWorldBoss = 79
 )
 
 const (
-	rankScoreS = 4
+	rankScoreWin = 2
+	rankScoreS   = 4
 )
 
 var commanderXpTableA = []uint32{0, 0, 20, 27, 35, 42, 49, 57, 65, 72}
@@ -49,6 +57,56 @@ func BeginStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 	if err := proto.Unmarshal(*buffer, &payload); err != nil {
 		return 0, 40002, err
 	}
+	if payload.GetSystem() == battleSystemRoutine {
+		_, result, err := checkDailyRaidStage(client, payload.GetData(), 1, time.Now())
+		if err != nil {
+			return 0, 40002, err
+		}
+		if result != dailyRaidResultOK {
+			response := protobuf.SC_40002{Result: proto.Uint32(result), Key: proto.Uint32(0), DropPerformance: []*protobuf.DROPPERFORMANCE{}}
+			return client.SendMessage(40002, &response)
+		}
+	}
+	if payload.GetSystem() == battleSystemWorld {
+		ok, err := checkWorldStage(client)
+		if err != nil {
+			return 0, 40002, err
+		}
+		if !ok {
+			response := protobuf.SC_40002{Result: proto.Uint32(worldResultFailed), Key: proto.Uint32(0), DropPerformance: []*protobuf.DROPPERFORMANCE{}}
+			return client.SendMessage(40002, &response)
+		}
+	}
+	if payload.GetSystem() == battleSystemDuel {
+		ok, err := checkExerciseDuel(client, payload.GetData())
+		if err != nil {
+			return 0, 40002, err
+		}
+		if !ok {
+			response := protobuf.SC_40002{Result: proto.Uint32(exerciseResultFailed), Key: proto.Uint32(0), DropPerformance: []*protobuf.DROPPERFORMANCE{}}
+			return client.SendMessage(40002, &response)
+		}
+	}
+	if payload.GetSystem() == battleSystemWorldBoss {
+		ok, err := checkWorldBossStage(client, payload.GetData(), true)
+		if err != nil {
+			return 0, 40002, err
+		}
+		if !ok {
+			response := protobuf.SC_40002{Result: proto.Uint32(worldBossResultFailed), Key: proto.Uint32(0), DropPerformance: []*protobuf.DROPPERFORMANCE{}}
+			return client.SendMessage(40002, &response)
+		}
+	}
+	if payload.GetSystem() == battleSystemChallenge {
+		ok, err := checkChallengeStage(client, payload.GetData(), payload.GetShipIdList())
+		if err != nil {
+			return 0, 40002, err
+		}
+		if !ok {
+			response := protobuf.SC_40002{Result: proto.Uint32(challengeResultFailed), Key: proto.Uint32(0), DropPerformance: []*protobuf.DROPPERFORMANCE{}}
+			return client.SendMessage(40002, &response)
+		}
+	}
 	key := nextBattleSessionKey()
 	session := orm.BattleSession{
 		CommanderID: client.Commander.CommanderID,
@@ -82,11 +140,23 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 			return 0, 40004, err
 		}
 	}
+	// morale recovered before the battle must not be computed from the new morale
+	if _, err := recoverMorale(client); err != nil {
+		return 0, 40004, err
+	}
+	verdict := validateBattleResult(client, session, &payload, time.Now())
+	if err := flagBattleResult(client, &payload, verdict); err != nil {
+		return 0, 40004, err
+	}
+	if verdict.rejected {
+		session = nil
+	}
+	score := verdict.score
+	statsByShip := verdict.stats
 	shipIDs := []uint32{}
 	if session != nil {
 		shipIDs = orm.ToUint32List(session.ShipIDs)
 	}
-	statsByShip := buildStatisticsMap(payload.GetStatistics())
 	mvp := resolveMvpShip(shipIDs, statsByShip)
 	shipExpList := make([]*protobuf.SHIP_EXP, 0, len(shipIDs))
 	baseExpedition, err := loadExpeditionConfig(payload.GetData())
@@ -98,7 +168,7 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 		baseShipExp = baseExpedition.Exp
 	}
 	applyMorale := battleUsesMorale(payload.GetSystem())
-	isRankS := payload.GetScore() >= rankScoreS
+	isRankS := score >= rankScoreS
 	shipExpGains := make(map[uint32]uint32)
 	shipEnergyUpdates := make(map[uint32]uint32)
 	shipIntimacyUpdates := make(map[uint32]uint32)
@@ -149,12 +219,17 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 			return 0, 40004, err
 		}
 		if update != nil {
-			if err := updateChapterProgressAfterBattle(client.Commander.CommanderID, update, payload.GetScore()); err != nil {
+			if err := updateChapterProgressAfterBattle(client.Commander.CommanderID, update, score); err != nil {
+				return 0, 40004, err
+			}
+		}
+		if update != nil && score >= rankScoreWin {
+			if err := recordChapterBattleCounts(client, update, time.Now()); err != nil {
 				return 0, 40004, err
 			}
 		}
 		if update != nil && update.defeated {
-			drops, err := buildChapterAwardDrops(update.template)
+			drops, err := rollChapterDrops(client, update.template, score, time.Now())
 			if err != nil {
 				return 0, 40004, err
 			}
@@ -166,9 +241,49 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 			}
 		}
 	}
+	if session != nil && score >= rankScoreWin {
+		if _, err := orm.RecordStageClear(client.Commander.CommanderID, session.StageID, score); err != nil {
+			return 0, 40004, err
+		}
+		if payload.GetSystem() == battleSystemRoutine {
+			drops, err := finishDailyRaid(client, session.StageID, time.Now())
+			if err != nil {
+				return 0, 40004, err
+			}
+			dropList = append(dropList, drops...)
+		}
+		if payload.GetSystem() == battleSystemWorld {
+			if err := finishWorldStage(client, session.StageID); err != nil {
+				return 0, 40004, err
+			}
+			if err := grantWorldBossSummonPt(client); err != nil {
+				return 0, 40004, err
+			}
+		}
+	}
 	if err := applyBattleShipUpdates(client, shipExpGains, shipEnergyUpdates, shipIntimacyUpdates); err != nil {
 		return 0, 40004, err
 	}
+	if session != nil && payload.GetSystem() == battleSystemGuild {
+		if err := recordGuildBossDamage(client, session.StageID, statsByShip); err != nil {
+			return 0, 40004, err
+		}
+	}
+	if session != nil && payload.GetSystem() == battleSystemWorldBoss {
+		if err := finishWorldBossStage(client, session.StageID, statsByShip); err != nil {
+			return 0, 40004, err
+		}
+	}
+	if session != nil && payload.GetSystem() == battleSystemDuel {
+		if err := finishExerciseDuel(client, session.StageID, score >= rankScoreWin); err != nil {
+			return 0, 40004, err
+		}
+	}
+	if session != nil && payload.GetSystem() == battleSystemChallenge {
+		if err := finishChallengeStage(client, session.StageID, score, statsByShip, time.Now()); err != nil {
+			return 0, 40004, err
+		}
+	}
 	playerExp := uint32(0)
 	if payload.GetSystem() == battleSystemScenario || payload.GetSystem() == battleSystemRoutine || payload.GetSystem() == battleSystemSub {
 		playerExp = computeCommanderExpGain(len(shipIDs), isRankS)
@@ -177,6 +292,11 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 	if err := applyCommanderExpGain(client, playerExp); err != nil {
 		return 0, 40004, err
 	}
+	if session != nil && score >= rankScoreWin {
+		if err := recordTaskEvent(client, taskEventBattleWin, payload.GetSystem(), 1); err != nil {
+			return 0, 40004, err
+		}
+	}
 	if err := orm.DeleteBattleSession(client.Commander.CommanderID); err != nil {
 		return 0, 40004, err
 	}
@@ -192,9 +312,10 @@ func FinishStage(buffer *[]byte, client *connection.Client) (int, int, error) {
 }
 
 type expeditionConfig struct {
-	ID    uint32 `json:"id"`
-	Exp   uint32 `json:"exp"`
-	Level uint32 `json:"level"`
+	ID           uint32     `json:"id"`
+	Exp          uint32     `json:"exp"`
+	Level        uint32     `json:"level"`
+	AwardDisplay [][]uint32 `json:"award_display"`
 }
 
 type shipLevelConfig struct {
@@ -299,7 +420,7 @@ func resolveMvpShip(shipIDs []uint32, stats map[uint32]*protobuf.STATISTICSINFO)
 
 func battleUsesMorale(system uint32) bool {
 	switch system {
-	case battleSystemDuel, battleSystemWorld:
+	case battleSystemDuel, battleSystemChallenge, battleSystemWorld, battleSystemWorldBoss:
 		return false
 	default:
 		return true
@@ -616,10 +737,75 @@ func buildChapterAwardDrops(template *chapterTemplate) (map[string]*protobuf.DRO
 	return drops, nil
 }
 
+// rollChapterDrops returns the drops of an enemy defeated in a chapter.
+// Chapters with a drop table roll it for the battle rank, the others grant
+// their award list; the tables of the open events roll on top. Dropped ships
+// are recorded for the chapter drop list.
+func rollChapterDrops(client *connection.Client, template *chapterTemplate, score uint32, now time.Time) (map[string]*protobuf.DROPINFO, error) {
+	if template == nil {
+		return map[string]*protobuf.DROPINFO{}, nil
+	}
+	rank := loot.RankFromScore(score)
+	rolled, ok, err := loot.RollSource(orm.DropKindChapter, template.ID, rank, now)
+	if err != nil {
+		return nil, err
+	}
+	drops := map[string]*protobuf.DROPINFO{}
+	if !ok {
+		if drops, err = buildChapterAwardDrops(template); err != nil {
+			return nil, err
+		}
+	}
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return nil, err
+	}
+	activityIDs := []uint32{}
+	for activityID := range openActivityIDs(windows, now) {
+		activityIDs = append(activityIDs, activityID)
+	}
+	eventDrops, err := loot.RollEvents(activityIDs, rank, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, drop := range append(rolled, eventDrops...) {
+		dropType, dropID, count, err := resolveChapterAwardDrop(drop.Type, drop.ID)
+		if err != nil {
+			return nil, err
+		}
+		count *= drop.Count
+		key := fmt.Sprintf("%d_%d", dropType, dropID)
+		if existing, ok := drops[key]; ok {
+			existing.Number = proto.Uint32(existing.GetNumber() + count)
+			continue
+		}
+		drops[key] = newDropInfo(dropType, dropID, count)
+	}
+	for _, drop := range drops {
+		if drop.GetType() != consts.DROP_TYPE_SHIP {
+			continue
+		}
+		if err := orm.AddChapterDrop(&orm.ChapterDrop{CommanderID: client.Commander.CommanderID, ChapterID: template.ID, ShipID: drop.GetId()}); err != nil {
+			return nil, err
+		}
+	}
+	return drops, nil
+}
+
+// resolveChapterAwardDrop turns a virtual item into one of the drops it
+// stands for, picked with the item's drop table when it has one and
+// uniformly among its display_icon otherwise.
 func resolveChapterAwardDrop(dropType uint32, dropID uint32) (uint32, uint32, uint32, error) {
 	if dropType != consts.DROP_TYPE_ITEM {
 		return dropType, dropID, 1, nil
 	}
+	picked, ok, err := loot.PickItem(dropID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if ok {
+		return picked.Type, picked.ID, picked.Count, nil
+	}
 	config, err := loadVirtualItemConfig(dropID)
 	if err != nil {
 		return 0, 0, 0, err
@@ -627,7 +813,6 @@ func resolveChapterAwardDrop(dropType uint32, dropID uint32) (uint32, uint32, ui
 	if config == nil || len(config.DisplayIcon) == 0 {
 		return dropType, dropID, 1, nil
 	}
-	// TODO: honor loot odds/weights instead of uniform selection.
 	entry := config.DisplayIcon[randomIndex(len(config.DisplayIcon))]
 	if len(entry) < 2 {
 		return dropType, dropID, 1, nil
@@ -847,19 +1032,212 @@ func QuitBattle(buffer *[]byte, client *connection.Client) (int, int, error) {
 	return client.SendMessage(40006, &response)
 }
 
-func DailyQuickBattle(buffer *[]byte, client *connection.Client) (int, int, error) {
-	var payload protobuf.CS_40007
-	if err := proto.Unmarshal(*buffer, &payload); err != nil {
-		return 0, 40008, err
+const (
+	// battleMinDuration is the shortest time a battle can take between
+	// BeginStage and FinishStage.
+	battleMinDuration = 5 * time.Second
+	// battleDamagePerAttack bounds the damage a ship can deal per second of
+	// battle, for each point of its cannon, torpedo and aviation stats.
+	battleDamagePerAttack = 4
+	// battleDamagePerLevel is the same bound for each level of a ship whose
+	// template has no stats.
+	battleDamagePerLevel = 20
+)
+
+const (
+	battleSuspectUnknownKey   = "unknown_session_key"
+	battleSuspectKeyMismatch  = "session_key_mismatch"
+	battleSuspectStage        = "session_stage_mismatch"
+	battleSuspectTooShort     = "battle_too_short"
+	battleSuspectUnknownShip  = "ship_not_in_session"
+	battleSuspectInvalidScore = "invalid_score"
+	battleSuspectSunkFleet    = "sunk_fleet_victory"
+	battleSuspectDamage       = "damage_exceeds_fleet"
+	battleSuspectEnemyHP      = "damage_exceeds_enemy_hp"
+	battleSuspectRankS        = "rank_s_with_sunk_ship"
+)
+
+type battleFinding struct {
+	reason string
+	detail string
+}
+
+// battleVerdict is the trusted view of a FinishStage report. A rejected
+// result is settled as a defeat without a session, so it grants nothing.
+type battleVerdict struct {
+	rejected bool
+	score    uint32
+	stats    map[uint32]*protobuf.STATISTICSINFO
+	findings []battleFinding
+}
+
+func (verdict *battleVerdict) reject(reason string, detail string) {
+	verdict.rejected = true
+	verdict.findings = append(verdict.findings, battleFinding{reason: reason, detail: detail})
+}
+
+// validateBattleResult checks a FinishStage report against the session
+// stored by BeginStage: the key, stage and fleet must match, the battle
+// must have lasted long enough, and the score and damage must be possible
+// for the fleet. Damage above what the ships' stats allow, or above the hp
+// left to the enemy when the server tracks it, is capped; an S rank with a
+// sunk ship is downgraded.
+func validateBattleResult(client *connection.Client, session *orm.BattleSession, payload *protobuf.CS_40003, now time.Time) battleVerdict {
+	verdict := battleVerdict{score: payload.GetScore(), stats: buildStatisticsMap(payload.GetStatistics())}
+	if session == nil {
+		if payload.GetKey() != 0 {
+			verdict.reject(battleSuspectUnknownKey, fmt.Sprintf("key %d", payload.GetKey()))
+		}
+	} else {
+		if payload.GetKey() != session.Key {
+			verdict.reject(battleSuspectKeyMismatch, fmt.Sprintf("key %d, expected %d", payload.GetKey(), session.Key))
+		}
+		if payload.GetSystem() != session.System || payload.GetData() != session.StageID {
+			verdict.reject(battleSuspectStage, fmt.Sprintf("stage %d/%d, expected %d/%d", payload.GetSystem(), payload.GetData(), session.System, session.StageID))
+		}
+		elapsed := now.Sub(session.CreatedAt)
+		if elapsed < battleMinDuration {
+			verdict.reject(battleSuspectTooShort, fmt.Sprintf("finished after %s", elapsed.Round(time.Millisecond)))
+		}
+		shipIDs := orm.ToUint32List(session.ShipIDs)
+		seconds := uint64(math.Max(elapsed.Seconds(), 1))
+		for shipID, stats := range verdict.stats {
+			if !containsUint32(shipIDs, shipID) {
+				verdict.reject(battleSuspectUnknownShip, fmt.Sprintf("ship %d", shipID))
+				continue
+			}
+			limit := battleShipDamageRate(client.Commander.OwnedShipsMap[shipID]) * seconds
+			if limit > math.MaxUint32 {
+				limit = math.MaxUint32
+			}
+			if uint64(stats.GetDamageCaused()) > limit {
+				verdict.findings = append(verdict.findings, battleFinding{
+					reason: battleSuspectDamage,
+					detail: fmt.Sprintf("ship %d dealt %d, capped to %d", shipID, stats.GetDamageCaused(), limit),
+				})
+				stats.DamageCaused = proto.Uint32(uint32(limit))
+			}
+		}
+		if enemyHP, ok := battleEnemyHP(client, session); ok {
+			capBattleDamage(&verdict, enemyHP)
+		}
+	}
+	if verdict.score > rankScoreS {
+		verdict.reject(battleSuspectInvalidScore, fmt.Sprintf("score %d", verdict.score))
+	}
+	if verdict.score >= rankScoreWin && len(verdict.stats) > 0 {
+		sunk := 0
+		for _, stats := range verdict.stats {
+			if stats.GetHpRest() == 0 {
+				sunk++
+			}
+		}
+		if sunk == len(verdict.stats) {
+			verdict.reject(battleSuspectSunkFleet, fmt.Sprintf("score %d", verdict.score))
+		} else if sunk > 0 && verdict.score == rankScoreS {
+			verdict.findings = append(verdict.findings, battleFinding{
+				reason: battleSuspectRankS,
+				detail: fmt.Sprintf("%d ships sunk", sunk),
+			})
+			verdict.score = rankScoreS - 1
+		}
+	}
+	if verdict.rejected {
+		verdict.score = 0
+		verdict.stats = make(map[uint32]*protobuf.STATISTICSINFO)
+	}
+	return verdict
+}
+
+// battleShipDamageRate is the most damage a ship can deal per second of
+// battle, derived from its cannon, torpedo and aviation stats at its level
+// and affinity. Ships without template stats fall back to their level.
+func battleShipDamageRate(owned *orm.OwnedShip) uint64 {
+	if owned == nil {
+		return battleDamagePerLevel
+	}
+	level := uint64(max(owned.Level, 1))
+	stats := loadShipDataStatistics(owned.ShipID)
+	if stats == nil {
+		return level * battleDamagePerLevel
+	}
+	extraLimit := getExtraAttrLevelLimit()
+	attack := 0.0
+	for _, index := range []int{shipAttrIndexCannon, shipAttrIndexTorpedo, shipAttrIndexAir} {
+		attack += shipGrowthForIndex(stats, index, owned.Level, extraLimit)
+	}
+	attack *= 1 + intimacyAttrBonusRate(owned.Intimacy)
+	if attack < 1 {
+		return level * battleDamagePerLevel
+	}
+	return uint64(attack) * battleDamagePerAttack
+}
+
+// battleEnemyHP returns the hp left to the enemy of a session, for the
+// bosses whose hp the server tracks.
+func battleEnemyHP(client *connection.Client, session *orm.BattleSession) (uint64, bool) {
+	switch session.System {
+	case battleSystemWorldBoss:
+		boss, err := orm.GetWorldBoss(session.StageID)
+		if err != nil {
+			return 0, false
+		}
+		return uint64(boss.HP), true
+	case battleSystemGuild:
+		member, err := loadGuildMembership(client.Commander.CommanderID)
+		if err != nil || member == nil {
+			return 0, false
+		}
+		op, err := orm.GetGuildOperation(member.GuildID)
+		if err != nil || op.BossDamage > op.BossHP {
+			return 0, false
+		}
+		return uint64(op.BossHP - op.BossDamage), true
+	default:
+		return 0, false
+	}
+}
+
+// capBattleDamage lowers the damage of a verdict so the fleet does not deal
+// more than enemyHP, crediting ships in id order.
+func capBattleDamage(verdict *battleVerdict, enemyHP uint64) {
+	total := uint64(0)
+	shipIDs := make([]uint32, 0, len(verdict.stats))
+	for shipID, stats := range verdict.stats {
+		total += uint64(stats.GetDamageCaused())
+		shipIDs = append(shipIDs, shipID)
+	}
+	if total <= enemyHP {
+		return
 	}
-	rewardCount := int(payload.GetCnt())
-	rewardList := make([]*protobuf.QUICK_REWARD, 0, rewardCount)
-	for i := 0; i < rewardCount; i++ {
-		rewardList = append(rewardList, &protobuf.QUICK_REWARD{})
+	verdict.findings = append(verdict.findings, battleFinding{
+		reason: battleSuspectEnemyHP,
+		detail: fmt.Sprintf("fleet dealt %d, enemy had %d hp", total, enemyHP),
+	})
+	sort.Slice(shipIDs, func(i, j int) bool { return shipIDs[i] < shipIDs[j] })
+	remaining := enemyHP
+	for _, shipID := range shipIDs {
+		stats := verdict.stats[shipID]
+		damage := min(uint64(stats.GetDamageCaused()), remaining)
+		remaining -= damage
+		stats.DamageCaused = proto.Uint32(uint32(damage))
 	}
-	response := protobuf.SC_40008{
-		Result:     proto.Uint32(0),
-		RewardList: rewardList,
+}
+
+// flagBattleResult records a cheater mark for every finding of a verdict.
+func flagBattleResult(client *connection.Client, payload *protobuf.CS_40003, verdict battleVerdict) error {
+	for _, finding := range verdict.findings {
+		mark := orm.CheaterMark{
+			CommanderID: client.Commander.CommanderID,
+			Source:      orm.CheaterMarkSourceBattle,
+			Reason:      finding.reason,
+			System:      payload.GetSystem(),
+			StageID:     payload.GetData(),
+			Detail:      finding.detail,
+		}
+		if err := orm.CreateCheaterMark(&mark); err != nil {
+			return err
+		}
 	}
-	return client.SendMessage(40008, &response)
+	return nil
 }
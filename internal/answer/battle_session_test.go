@@ -4,7 +4,9 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/consts"
 	"github.com/ggmolly/belfast/internal/db"
 	"github.com/ggmolly/belfast/internal/orm"
@@ -96,6 +98,7 @@ func TestFinishStageClearsBattleSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -226,6 +229,7 @@ func TestFinishStageUpdatesBossProgress(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -321,6 +325,7 @@ func TestFinishStageGrantsChapterAwards(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -429,6 +434,7 @@ func TestFinishStageResolvesVirtualAwardDrops(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -502,6 +508,7 @@ func TestThirdClearKeepsRawStarCounts(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -521,30 +528,6 @@ func TestThirdClearKeepsRawStarCounts(t *testing.T) {
 	}
 }
 
-func TestDailyQuickBattleReturnsRewards(t *testing.T) {
-	client := setupPlayerUpdateTest(t)
-	payload := protobuf.CS_40007{
-		System: proto.Uint32(1),
-		Id:     proto.Uint32(9001),
-		Cnt:    proto.Uint32(2),
-	}
-	buffer, err := proto.Marshal(&payload)
-	if err != nil {
-		t.Fatalf("marshal payload: %v", err)
-	}
-	if _, _, err := DailyQuickBattle(&buffer, client); err != nil {
-		t.Fatalf("daily quick battle failed: %v", err)
-	}
-	var response protobuf.SC_40008
-	decodeResponse(t, client, &response)
-	if response.GetResult() != 0 {
-		t.Fatalf("expected result 0, got %d", response.GetResult())
-	}
-	if len(response.GetRewardList()) != 2 {
-		t.Fatalf("expected 2 rewards, got %d", len(response.GetRewardList()))
-	}
-}
-
 func TestFinishStageAppliesExpMoraleAndCommanderExp(t *testing.T) {
 	client := setupPlayerUpdateTest(t)
 	clearTable(t, &orm.BattleSession{})
@@ -600,6 +583,7 @@ func TestFinishStageAppliesExpMoraleAndCommanderExp(t *testing.T) {
 	if err != nil {
 		t.Fatalf("marshal finish payload: %v", err)
 	}
+	backdateBattleSession(t, client.Commander.CommanderID)
 	if _, _, err := FinishStage(&finishBuffer, client); err != nil {
 		t.Fatalf("finish stage failed: %v", err)
 	}
@@ -652,3 +636,135 @@ func TestFinishStageAppliesExpMoraleAndCommanderExp(t *testing.T) {
 		t.Fatalf("expected ship 102 intimacy 5100, got %d", owned.Intimacy)
 	}
 }
+
+// backdateBattleSession moves the start of the battle session of a
+// commander before battleMinDuration, so it can be finished right away.
+func backdateBattleSession(t *testing.T, commanderID uint32) {
+	t.Helper()
+	execAnswerTestSQLT(t, "UPDATE battle_sessions SET created_at = created_at - INTERVAL '1 minute' WHERE commander_id = $1", int64(commanderID))
+}
+
+func TestValidateBattleResult(t *testing.T) {
+	client := &connection.Client{Commander: &orm.Commander{
+		CommanderID:   1,
+		OwnedShipsMap: map[uint32]*orm.OwnedShip{101: {ID: 101, Level: 2}, 102: {ID: 102, Level: 2}},
+	}}
+	now := time.Now()
+	session := &orm.BattleSession{CommanderID: 1, System: battleSystemScenario, StageID: 101010, Key: 7, ShipIDs: orm.ToInt64List([]uint32{101, 102}), CreatedAt: now.Add(-10 * time.Second)}
+	report := func(key uint32, score uint32, shipID uint32, damage uint32, hpRest uint32) *protobuf.CS_40003 {
+		return &protobuf.CS_40003{
+			System: proto.Uint32(battleSystemScenario),
+			Data:   proto.Uint32(101010),
+			Key:    proto.Uint32(key),
+			Score:  proto.Uint32(score),
+			Statistics: []*protobuf.STATISTICSINFO{
+				{ShipId: proto.Uint32(shipID), DamageCaused: proto.Uint32(damage), HpRest: proto.Uint32(hpRest)},
+			},
+		}
+	}
+
+	verdict := validateBattleResult(client, session, report(7, rankScoreS, 101, 300, 50), now)
+	if verdict.rejected || len(verdict.findings) != 0 || verdict.score != rankScoreS {
+		t.Fatalf("expected a clean result, got %+v", verdict)
+	}
+	verdict = validateBattleResult(client, session, report(7, rankScoreS, 101, 1000000, 50), now)
+	if verdict.rejected || len(verdict.findings) != 1 || verdict.stats[101].GetDamageCaused() != 2*battleDamagePerLevel*10 {
+		t.Fatalf("expected damage to be capped, got %+v", verdict)
+	}
+
+	sunkShip := report(7, rankScoreS, 101, 300, 50)
+	sunkShip.Statistics = append(sunkShip.Statistics, &protobuf.STATISTICSINFO{ShipId: proto.Uint32(102), DamageCaused: proto.Uint32(0), HpRest: proto.Uint32(0)})
+	verdict = validateBattleResult(client, session, sunkShip, now)
+	if verdict.rejected || verdict.score != rankScoreS-1 || len(verdict.findings) != 1 || verdict.findings[0].reason != battleSuspectRankS {
+		t.Fatalf("expected an S rank with a sunk ship to be downgraded, got %+v", verdict)
+	}
+
+	cases := map[string]*protobuf.CS_40003{
+		battleSuspectKeyMismatch:  report(8, rankScoreS, 101, 300, 50),
+		battleSuspectUnknownShip:  report(7, rankScoreS, 103, 300, 50),
+		battleSuspectInvalidScore: report(7, rankScoreS+1, 101, 300, 50),
+		battleSuspectSunkFleet:    report(7, rankScoreS, 101, 300, 0),
+	}
+	for reason, payload := range cases {
+		verdict := validateBattleResult(client, session, payload, now)
+		if !verdict.rejected || verdict.score != 0 || len(verdict.stats) != 0 || verdict.findings[0].reason != reason {
+			t.Fatalf("expected %s to be rejected, got %+v", reason, verdict)
+		}
+	}
+	verdict = validateBattleResult(client, session, report(7, rankScoreS, 101, 300, 50), session.CreatedAt.Add(time.Second))
+	if !verdict.rejected || verdict.findings[0].reason != battleSuspectTooShort {
+		t.Fatalf("expected a short battle to be rejected, got %+v", verdict)
+	}
+	verdict = validateBattleResult(client, nil, report(7, rankScoreS, 101, 300, 50), now)
+	if !verdict.rejected || verdict.findings[0].reason != battleSuspectUnknownKey {
+		t.Fatalf("expected a reused key to be rejected, got %+v", verdict)
+	}
+}
+
+func TestBattleShipDamageRateUsesShipStats(t *testing.T) {
+	setupConfigTest(t)
+	seedConfigEntry(t, "sharecfgdata/ship_data_statistics.json", "900", `{"id":900,"attrs":[1000,50,30,0,20,0,0,0,0,0,0,0],"attrs_growth":[0,2000,1000,0,0,0,0,0,0,0,0,0],"attrs_growth_extra":[0,0,0,0,0,0,0,0,0,0,0,0]}`)
+	owned := &orm.OwnedShip{ShipID: 900, Level: 11, Intimacy: 0}
+	// cannon 50+10*2, torpedo 30+10*1, air 20
+	if rate := battleShipDamageRate(owned); rate != 130*battleDamagePerAttack {
+		t.Fatalf("expected a rate of %d, got %d", 130*battleDamagePerAttack, rate)
+	}
+	if rate := battleShipDamageRate(&orm.OwnedShip{ShipID: 901, Level: 3}); rate != 3*battleDamagePerLevel {
+		t.Fatalf("expected the level fallback, got %d", rate)
+	}
+}
+
+func TestCapBattleDamageToEnemyHP(t *testing.T) {
+	verdict := battleVerdict{stats: map[uint32]*protobuf.STATISTICSINFO{
+		1: {ShipId: proto.Uint32(1), DamageCaused: proto.Uint32(600)},
+		2: {ShipId: proto.Uint32(2), DamageCaused: proto.Uint32(600)},
+	}}
+	capBattleDamage(&verdict, 1000)
+	if verdict.stats[1].GetDamageCaused() != 600 || verdict.stats[2].GetDamageCaused() != 400 || len(verdict.findings) != 1 {
+		t.Fatalf("expected fleet damage to be capped at the enemy hp, got %+v", verdict)
+	}
+	capBattleDamage(&verdict, 1000)
+	if len(verdict.findings) != 1 {
+		t.Fatalf("expected damage within the enemy hp to be kept, got %+v", verdict.findings)
+	}
+}
+
+func TestFinishStageFlagsReusedSessionKey(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.BattleSession{})
+	clearTable(t, &orm.CheaterMark{})
+
+	begin := marshalPacketRequest(t, &protobuf.CS_40001{System: proto.Uint32(battleSystemScenario), Data: proto.Uint32(101010)})
+	if _, _, err := BeginStage(&begin, client); err != nil {
+		t.Fatalf("begin stage failed: %v", err)
+	}
+	started := &protobuf.SC_40002{}
+	decodePacketMessage(t, client, 40002, started)
+	client.Buffer.Reset()
+	finish := marshalPacketRequest(t, &protobuf.CS_40003{
+		System:         proto.Uint32(battleSystemScenario),
+		Data:           proto.Uint32(101010),
+		Key:            proto.Uint32(started.GetKey()),
+		Score:          proto.Uint32(rankScoreS),
+		TotalTime:      proto.Uint32(1),
+		BotPercentage:  proto.Uint32(0),
+		ExtraParam:     proto.Uint32(0),
+		AutoBefore:     proto.Uint32(0),
+		AutoSwitchTime: proto.Uint32(0),
+		AutoAfter:      proto.Uint32(0),
+	})
+	for i := 0; i < 2; i++ {
+		if _, _, err := FinishStage(&finish, client); err != nil {
+			t.Fatalf("finish stage failed: %v", err)
+		}
+		client.Buffer.Reset()
+	}
+
+	marks, err := orm.ListCheaterMarks(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("list cheater marks: %v", err)
+	}
+	if len(marks) != 2 || marks[0].Reason != battleSuspectUnknownKey || marks[1].Reason != battleSuspectTooShort {
+		t.Fatalf("unexpected cheater marks: %+v", marks)
+	}
+}
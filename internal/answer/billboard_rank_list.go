@@ -1,6 +1,9 @@
 package answer
 
 import (
+	"time"
+
+	"github.com/ggmolly/belfast/internal/arena"
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
@@ -8,10 +11,16 @@ import (
 )
 
 const (
-	// Client expects roughly 20 rows per page; we return at most one.
 	billboardRankSupportedMaxType = 50
+	billboardRankPageSize         = 20
+
+	// PowerRank.TYPE_MILITARY_RANK, backed by the exercise leaderboard.
+	billboardRankTypeMilitary = 4
+	// PowerRank.TYPE_CHALLENGE, the best challenge score of an activity.
+	billboardRankTypeChallenge = 5
 
-	// Minimal implementation: return a coherent single-row leaderboard.
+	// Other types have no backing data yet: return a coherent single-row
+	// leaderboard.
 	billboardRankPoint = 1
 	billboardRankRank  = 1
 )
@@ -25,7 +34,7 @@ func BillboardRankListPage(buffer *[]byte, client *connection.Client) (int, int,
 
 	page := payload.GetPage()
 	rankType := payload.GetType()
-	if page != 1 || !isSupportedBillboardRankType(rankType) {
+	if page == 0 || !isSupportedBillboardRankType(rankType) {
 		response := protobuf.SC_18202{List: []*protobuf.RANK_INFO_P18{}}
 		return client.SendMessage(18202, &response)
 	}
@@ -34,11 +43,66 @@ func BillboardRankListPage(buffer *[]byte, client *connection.Client) (int, int,
 		return client.SendMessage(18202, &response)
 	}
 
+	if rankType == billboardRankTypeMilitary {
+		return billboardMilitaryRankPage(client, page)
+	}
+	if rankType == billboardRankTypeChallenge {
+		return billboardChallengeRankPage(client, payload.GetActId(), page)
+	}
+	if page != 1 {
+		response := protobuf.SC_18202{List: []*protobuf.RANK_INFO_P18{}}
+		return client.SendMessage(18202, &response)
+	}
+
 	row := billboardRankRow(client.Commander)
 	response := protobuf.SC_18202{List: []*protobuf.RANK_INFO_P18{row}}
 	return client.SendMessage(18202, &response)
 }
 
+func billboardMilitaryRankPage(client *connection.Client, page uint32) (int, int, error) {
+	_, season, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		return 0, 18202, err
+	}
+	entries, err := orm.ListArenaLeaderboard(season.SeasonID, int(page-1)*billboardRankPageSize, billboardRankPageSize)
+	if err != nil {
+		return 0, 18202, err
+	}
+	list := make([]*protobuf.RANK_INFO_P18, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, &protobuf.RANK_INFO_P18{
+			UserId:    proto.Uint32(entry.CommanderID),
+			Point:     proto.Uint32(entry.Score),
+			Name:      proto.String(entry.Name),
+			Lv:        proto.Uint32(uint32(entry.Level)),
+			ArenaRank: proto.Uint32(arena.RankTier(entry.Score)),
+			Display:   billboardRankDisplay(arenaLeaderboardCommander(entry)),
+		})
+	}
+	response := protobuf.SC_18202{List: list}
+	return client.SendMessage(18202, &response)
+}
+
+func billboardChallengeRankPage(client *connection.Client, activityID uint32, page uint32) (int, int, error) {
+	entries, err := orm.ListChallengeLeaderboard(activityID, int(page-1)*billboardRankPageSize, billboardRankPageSize)
+	if err != nil {
+		return 0, 18202, err
+	}
+	list := make([]*protobuf.RANK_INFO_P18, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, &protobuf.RANK_INFO_P18{
+			UserId:    proto.Uint32(entry.CommanderID),
+			Point:     proto.Uint32(entry.Score),
+			Name:      proto.String(entry.Name),
+			Lv:        proto.Uint32(uint32(entry.Level)),
+			ArenaRank: proto.Uint32(0),
+			Display:   billboardRankDisplay(arenaLeaderboardCommander(orm.ArenaLeaderboardEntry(entry))),
+		})
+	}
+	response := protobuf.SC_18202{List: list}
+	return client.SendMessage(18202, &response)
+}
+
 func BillboardMyRank(buffer *[]byte, client *connection.Client) (int, int, error) {
 	var payload protobuf.CS_18203
 	if err := proto.Unmarshal(*buffer, &payload); err != nil {
@@ -56,6 +120,27 @@ func BillboardMyRank(buffer *[]byte, client *connection.Client) (int, int, error
 		return client.SendMessage(18204, &response)
 	}
 
+	if rankType == billboardRankTypeMilitary {
+		state, _, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+		if err != nil {
+			return 0, 18204, err
+		}
+		rank, err := arena.Rank(state)
+		if err != nil {
+			return 0, 18204, err
+		}
+		response := protobuf.SC_18204{Point: proto.Uint32(state.Score), Rank: proto.Uint32(rank)}
+		return client.SendMessage(18204, &response)
+	}
+	if rankType == billboardRankTypeChallenge {
+		point, rank, err := orm.GetChallengeRank(payload.GetActId(), client.Commander.CommanderID)
+		if err != nil {
+			return 0, 18204, err
+		}
+		response := protobuf.SC_18204{Point: proto.Uint32(point), Rank: proto.Uint32(rank)}
+		return client.SendMessage(18204, &response)
+	}
+
 	response := protobuf.SC_18204{Point: proto.Uint32(billboardRankPoint), Rank: proto.Uint32(billboardRankRank)}
 	return client.SendMessage(18204, &response)
 }
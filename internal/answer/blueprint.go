@@ -0,0 +1,387 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	blueprintConfigCategory           = "ShareCfg/ship_data_blueprint.json"
+	blueprintStrengthenConfigCategory = "ShareCfg/ship_strengthen_blueprint.json"
+
+	// blueprintColdTime is how long a new development is locked after one
+	// was stopped.
+	blueprintColdTime = uint32(24 * 60 * 60)
+)
+
+// blueprintTemplate is a research ship. blueprint_version is the research
+// series it belongs to, strengthen_item the blueprint item spent on it, and
+// strengthen_effect / fate_strengthen the ship_strengthen_blueprint ids of
+// its development and fate simulation levels. Every unlock_item_tasks entry
+// is a [task_id, item_id, count] to submit before the ship can be built.
+type blueprintTemplate struct {
+	ID               uint32     `json:"id"`
+	ShipID           uint32     `json:"ship_id"`
+	BlueprintVersion uint32     `json:"blueprint_version"`
+	StrengthenItem   uint32     `json:"strengthen_item"`
+	StrengthenEffect []uint32   `json:"strengthen_effect"`
+	FateStrengthen   []uint32   `json:"fate_strengthen"`
+	Time             uint32     `json:"time"`
+	UnlockItemTasks  [][]uint32 `json:"unlock_item_tasks"`
+}
+
+// blueprintStrengthenTemplate is a development level: need_exp blueprints
+// and a ship level of need_lv are required to reach it.
+type blueprintStrengthenTemplate struct {
+	ID      uint32 `json:"id"`
+	Lv      uint32 `json:"lv"`
+	NeedExp uint32 `json:"need_exp"`
+	NeedLv  uint32 `json:"need_lv"`
+}
+
+func loadBlueprintTemplates() (map[uint32]blueprintTemplate, error) {
+	entries, err := gamedata.List[blueprintTemplate](gamedata.Default, blueprintConfigCategory)
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[uint32]blueprintTemplate, len(entries))
+	for _, template := range entries {
+		templates[template.ID] = template
+	}
+	return templates, nil
+}
+
+func loadBlueprintTemplate(blueprintID uint32) (*blueprintTemplate, error) {
+	cached, err := gamedata.Get[blueprintTemplate](gamedata.Default, blueprintConfigCategory, strconv.FormatUint(uint64(blueprintID), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template := *cached
+	return &template, nil
+}
+
+func loadBlueprintStrengthenTemplate(id uint32) (*blueprintStrengthenTemplate, error) {
+	cached, err := gamedata.Get[blueprintStrengthenTemplate](gamedata.Default, blueprintStrengthenConfigCategory, strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template := *cached
+	return &template, nil
+}
+
+func (template *blueprintTemplate) unlockTask(taskID uint32) ([]uint32, bool) {
+	for _, task := range template.UnlockItemTasks {
+		if len(task) >= 3 && task[0] == taskID {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
+func (template *blueprintTemplate) tasksDone(blueprint *orm.CommanderBlueprint) bool {
+	submitted := orm.ToUint32List(blueprint.SubmittedTasks)
+	for _, task := range template.UnlockItemTasks {
+		if len(task) >= 3 && !containsUint32(submitted, task[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ShipyardData sends SC_63100 during login.
+func ShipyardData(buffer *[]byte, client *connection.Client) (int, int, error) {
+	state, err := orm.GetOrCreateTechState(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63100, err
+	}
+	blueprints, err := orm.ListCommanderBlueprints(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63100, err
+	}
+	response := protobuf.SC_63100{
+		ColdTime:                 proto.Uint32(state.ColdTime),
+		DailyCatchupStrengthen:   proto.Uint32(0),
+		DailyCatchupStrengthenUr: proto.Uint32(0),
+		BlueprintList:            make([]*protobuf.BLUPRINTINFO, 0, len(blueprints)),
+	}
+	for _, blueprint := range blueprints {
+		response.BlueprintList = append(response.BlueprintList, &protobuf.BLUPRINTINFO{
+			Id:             proto.Uint32(blueprint.BlueprintID),
+			ShipId:         proto.Uint32(blueprint.ShipID),
+			StartTime:      proto.Uint32(blueprint.StartTime),
+			BluePrintLevel: proto.Uint32(blueprint.Level),
+			Exp:            proto.Uint32(blueprint.Exp),
+			StartDuration:  proto.Uint32(blueprint.Duration),
+		})
+	}
+	return client.SendMessage(63100, &response)
+}
+
+// BlueprintStart handles CS_63200. A single ship can be in development at a
+// time, and none can be started during the cold time of a stopped one.
+func BlueprintStart(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63200
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63201, err
+	}
+	failed := &protobuf.SC_63201{Result: proto.Uint32(techResultFailed), Time: proto.Uint32(0)}
+	template, err := loadBlueprintTemplate(payload.GetBlueprintId())
+	if err != nil {
+		return 0, 63201, err
+	}
+	if template == nil {
+		return client.SendMessage(63201, failed)
+	}
+	state, err := orm.GetOrCreateTechState(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63201, err
+	}
+	now := uint32(time.Now().Unix())
+	if state.ColdTime > now {
+		return client.SendMessage(63201, failed)
+	}
+	blueprints, err := orm.ListCommanderBlueprints(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63201, err
+	}
+	for _, blueprint := range blueprints {
+		if blueprint.BlueprintID == template.ID || blueprint.ShipID == 0 {
+			return client.SendMessage(63201, failed)
+		}
+	}
+	blueprint := orm.CommanderBlueprint{
+		CommanderID: client.Commander.CommanderID,
+		BlueprintID: template.ID,
+		StartTime:   now,
+		Duration:    template.Time,
+	}
+	if err := orm.SaveCommanderBlueprint(&blueprint); err != nil {
+		return 0, 63201, err
+	}
+	return client.SendMessage(63201, &protobuf.SC_63201{Result: proto.Uint32(techResultOK), Time: proto.Uint32(now)})
+}
+
+// BlueprintFinish handles CS_63202: once its timer ran out and its item tasks
+// were submitted, the ship in development is built.
+func BlueprintFinish(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63202
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63203, err
+	}
+	failed := &protobuf.SC_63203{Result: proto.Uint32(techResultFailed)}
+	blueprint, err := orm.GetCommanderBlueprint(client.Commander.CommanderID, payload.GetBlueprintId())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(63203, failed)
+		}
+		return 0, 63203, err
+	}
+	template, err := loadBlueprintTemplate(blueprint.BlueprintID)
+	if err != nil {
+		return 0, 63203, err
+	}
+	now := uint32(time.Now().Unix())
+	if template == nil || blueprint.ShipID != 0 || blueprint.StartTime+blueprint.Duration > now || !template.tasksDone(blueprint) {
+		return client.SendMessage(63203, failed)
+	}
+	ship, err := client.Commander.AddShip(template.ShipID)
+	if err != nil {
+		return 0, 63203, err
+	}
+	blueprint.ShipID = ship.ID
+	if err := orm.SaveCommanderBlueprint(blueprint); err != nil {
+		return 0, 63203, err
+	}
+	return client.SendMessage(63203, &protobuf.SC_63203{
+		Result: proto.Uint32(techResultOK),
+		Ship:   orm.ToProtoOwnedShip(*ship, nil, nil),
+	})
+}
+
+// BlueprintStop handles CS_63206: the development is dropped along with the
+// submitted items, and a new one can't start before the cold time ends.
+func BlueprintStop(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63206
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63207, err
+	}
+	blueprint, err := orm.GetCommanderBlueprint(client.Commander.CommanderID, payload.GetBlueprintId())
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 63207, err
+	}
+	if blueprint == nil || blueprint.ShipID != 0 {
+		return client.SendMessage(63207, &protobuf.SC_63207{Result: proto.Uint32(techResultFailed)})
+	}
+	state, err := orm.GetOrCreateTechState(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63207, err
+	}
+	state.ColdTime = uint32(time.Now().Unix()) + blueprintColdTime
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteCommanderBlueprintTx(ctx, tx, blueprint.CommanderID, blueprint.BlueprintID); err != nil {
+			return err
+		}
+		return orm.SaveTechStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		return 0, 63207, err
+	}
+	return client.SendMessage(63207, &protobuf.SC_63207{Result: proto.Uint32(techResultOK)})
+}
+
+// BlueprintSubmitTask handles CS_63210: the items of an unlock task are
+// handed in for the ship in development.
+func BlueprintSubmitTask(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63210
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63211, err
+	}
+	failed := &protobuf.SC_63211{Result: proto.Uint32(techResultFailed)}
+	blueprint, err := orm.GetCommanderBlueprint(client.Commander.CommanderID, payload.GetBlueprintid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(63211, failed)
+		}
+		return 0, 63211, err
+	}
+	template, err := loadBlueprintTemplate(blueprint.BlueprintID)
+	if err != nil {
+		return 0, 63211, err
+	}
+	if template == nil || blueprint.ShipID != 0 {
+		return client.SendMessage(63211, failed)
+	}
+	task, ok := template.unlockTask(payload.GetTaskId())
+	if !ok || task[1] != payload.GetItemid() || task[2] != payload.GetNumber() || containsUint32(orm.ToUint32List(blueprint.SubmittedTasks), task[0]) {
+		return client.SendMessage(63211, failed)
+	}
+	if !client.Commander.HasEnoughItem(task[1], task[2]) {
+		return client.SendMessage(63211, &protobuf.SC_63211{Result: proto.Uint32(techResultNotEnough)})
+	}
+	blueprint.SubmittedTasks = append(blueprint.SubmittedTasks, int64(task[0]))
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := client.Commander.ConsumeItemTx(ctx, tx, task[1], task[2]); err != nil {
+			return errTechCostNotEnough
+		}
+		return orm.SaveCommanderBlueprintTx(ctx, tx, blueprint)
+	})
+	if err != nil {
+		if errors.Is(err, errTechCostNotEnough) {
+			return client.SendMessage(63211, &protobuf.SC_63211{Result: proto.Uint32(techResultNotEnough)})
+		}
+		return 0, 63211, err
+	}
+	return client.SendMessage(63211, &protobuf.SC_63211{Result: proto.Uint32(techResultOK)})
+}
+
+// strengthenBlueprint spends count blueprints on the research ship shipID.
+// Development levels come first; fate simulation levels only once they are
+// all reached. Levels are gained as long as the experience and the ship
+// level allow it, and experience left once the last level of the stage is
+// reached is dropped.
+func strengthenBlueprint(client *connection.Client, shipID uint32, count uint32, fate bool) (uint32, error) {
+	ship, ok := client.Commander.OwnedShipsMap[shipID]
+	if !ok || count == 0 {
+		return techResultFailed, nil
+	}
+	blueprint, err := orm.GetCommanderBlueprintByShip(client.Commander.CommanderID, shipID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return techResultFailed, nil
+		}
+		return 0, err
+	}
+	template, err := loadBlueprintTemplate(blueprint.BlueprintID)
+	if err != nil {
+		return 0, err
+	}
+	if template == nil || template.StrengthenItem == 0 {
+		return techResultFailed, nil
+	}
+	levels := template.StrengthenEffect
+	base := uint32(0)
+	if fate {
+		levels = template.FateStrengthen
+		base = uint32(len(template.StrengthenEffect))
+	}
+	if blueprint.Level < base || blueprint.Level >= base+uint32(len(levels)) {
+		return techResultFailed, nil
+	}
+	if !client.Commander.HasEnoughItem(template.StrengthenItem, count) {
+		return techResultNotEnough, nil
+	}
+	blueprint.Exp += count
+	for blueprint.Level < base+uint32(len(levels)) {
+		next, err := loadBlueprintStrengthenTemplate(levels[blueprint.Level-base])
+		if err != nil {
+			return 0, err
+		}
+		if next == nil || blueprint.Exp < next.NeedExp || ship.Level < next.NeedLv {
+			break
+		}
+		blueprint.Exp -= next.NeedExp
+		blueprint.Level++
+	}
+	if blueprint.Level == base+uint32(len(levels)) {
+		blueprint.Exp = 0
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := client.Commander.ConsumeItemTx(ctx, tx, template.StrengthenItem, count); err != nil {
+			return errTechCostNotEnough
+		}
+		return orm.SaveCommanderBlueprintTx(ctx, tx, blueprint)
+	})
+	if err != nil {
+		if errors.Is(err, errTechCostNotEnough) {
+			return techResultNotEnough, nil
+		}
+		return 0, err
+	}
+	return techResultOK, nil
+}
+
+// BlueprintStrengthen handles CS_63204.
+func BlueprintStrengthen(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63204
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63205, err
+	}
+	result, err := strengthenBlueprint(client, payload.GetShipId(), payload.GetCount(), false)
+	if err != nil {
+		return 0, 63205, err
+	}
+	return client.SendMessage(63205, &protobuf.SC_63205{Result: proto.Uint32(result)})
+}
+
+// BlueprintFateSimulation handles CS_63212.
+func BlueprintFateSimulation(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63212
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63213, err
+	}
+	result, err := strengthenBlueprint(client, payload.GetShipId(), payload.GetCount(), true)
+	if err != nil {
+		return 0, 63213, err
+	}
+	return client.SendMessage(63213, &protobuf.SC_63213{Result: proto.Uint32(result)})
+}
@@ -0,0 +1,330 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/challenge"
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	challengeResultOK     = 0
+	challengeResultFailed = 1
+)
+
+// StartChallenge handles CS_24002: it starts a run of the current season with
+// the fleets the commander picked.
+func StartChallenge(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_24002
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 24003, err
+	}
+	result, err := startChallenge(client, &payload, time.Now())
+	if err != nil {
+		return 0, 24003, err
+	}
+	response := protobuf.SC_24003{Result: proto.Uint32(result)}
+	return client.SendMessage(24003, &response)
+}
+
+func startChallenge(client *connection.Client, payload *protobuf.CS_24002, now time.Time) (uint32, error) {
+	activityID := payload.GetActivityId()
+	mode := payload.GetMode()
+	if !challenge.ValidMode(mode) || len(payload.GetGroupList()) == 0 {
+		return challengeResultFailed, nil
+	}
+	if _, ok := challengeOpen(activityID, now); !ok {
+		return challengeResultFailed, nil
+	}
+	activity, err := loadActivityTemplate(activityID)
+	if err != nil {
+		return 0, err
+	}
+	config, err := loadActivityEventChallenge(activity)
+	if err != nil {
+		return 0, err
+	}
+	groups, ok, err := challengeGroups(client, payload.GetGroupList())
+	if err != nil || !ok {
+		return challengeResultFailed, err
+	}
+	seasonID, err := challengeSeasonID(activityID, now)
+	if err != nil {
+		return 0, err
+	}
+	dungeons := challenge.Dungeons(config.InfiniteStage, seasonID, mode)
+	if len(dungeons) == 0 {
+		return challengeResultFailed, nil
+	}
+
+	commanderID := client.Commander.CommanderID
+	runs, err := orm.ListChallengeRuns(commanderID, activityID)
+	if err != nil {
+		return 0, err
+	}
+	for _, run := range runs {
+		if run.Mode == mode && run.SeasonID != seasonID {
+			if _, err := orm.DeleteChallengeRun(commanderID, activityID, mode); err != nil {
+				return 0, err
+			}
+		}
+	}
+	run := orm.ChallengeRun{
+		CommanderID: commanderID,
+		ActivityID:  activityID,
+		Mode:        mode,
+		SeasonID:    seasonID,
+		Level:       1,
+		DungeonIDs:  orm.ToInt64List(dungeons),
+		BuffIDs:     orm.ToInt64List(config.Buff),
+		Groups:      groups,
+		StartedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := orm.CreateChallengeRun(&run); err != nil {
+		if errors.Is(err, orm.ErrChallengeRunExists) {
+			return challengeResultFailed, nil
+		}
+		return 0, err
+	}
+	return challengeResultOK, nil
+}
+
+// challengeGroups checks the fleets of a new run: every ship and meowfficer
+// must be owned and used once.
+func challengeGroups(client *connection.Client, groupList []*protobuf.GROUPINFO_P24) (orm.ChallengeGroups, bool, error) {
+	if client.Commander.OwnedShipsMap == nil {
+		if err := client.Commander.Load(); err != nil {
+			return nil, false, err
+		}
+	}
+	usedShips := make(map[uint32]struct{})
+	usedCommanders := make(map[uint32]struct{})
+	groups := make(orm.ChallengeGroups, 0, len(groupList))
+	for _, groupInfo := range groupList {
+		if len(groupInfo.GetShipList()) == 0 {
+			return nil, false, nil
+		}
+		group := orm.ChallengeGroup{
+			ID:         groupInfo.GetId(),
+			Ships:      make([]orm.ChallengeShip, 0, len(groupInfo.GetShipList())),
+			Commanders: make([]orm.ChallengeCommander, 0, len(groupInfo.GetCommanders())),
+		}
+		for _, shipID := range groupInfo.GetShipList() {
+			if _, ok := client.Commander.OwnedShipsMap[shipID]; !ok {
+				return nil, false, nil
+			}
+			if _, ok := usedShips[shipID]; ok {
+				return nil, false, nil
+			}
+			usedShips[shipID] = struct{}{}
+			group.Ships = append(group.Ships, orm.ChallengeShip{ID: shipID, HP: challenge.FullHP})
+		}
+		for _, commander := range groupInfo.GetCommanders() {
+			if _, err := orm.GetMeowfficer(client.Commander.CommanderID, commander.GetId()); err != nil {
+				if db.IsNotFound(err) {
+					return nil, false, nil
+				}
+				return nil, false, err
+			}
+			if _, ok := usedCommanders[commander.GetId()]; ok {
+				return nil, false, nil
+			}
+			usedCommanders[commander.GetId()] = struct{}{}
+			group.Commanders = append(group.Commanders, orm.ChallengeCommander{Pos: commander.GetPos(), ID: commander.GetId()})
+		}
+		groups = append(groups, group)
+	}
+	return groups, true, nil
+}
+
+// GiveUpChallenge handles CS_24011: the run ends, keeping the score of the
+// levels already cleared.
+func GiveUpChallenge(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_24011
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 24012, err
+	}
+	deleted, err := orm.DeleteChallengeRun(client.Commander.CommanderID, payload.GetActivityId(), payload.GetMode())
+	if err != nil {
+		return 0, 24012, err
+	}
+	result := uint32(challengeResultOK)
+	if !deleted {
+		result = challengeResultFailed
+	}
+	response := protobuf.SC_24012{Result: proto.Uint32(result)}
+	return client.SendMessage(24012, &response)
+}
+
+// challengeOpen returns the window of a challenge activity open at now.
+func challengeOpen(activityID uint32, now time.Time) (ActivityWindow, bool) {
+	window, ok := challengeWindow(activityID)
+	if !ok || window.Type != activityTypeChallenge || !window.OpenAt(now) {
+		return ActivityWindow{}, false
+	}
+	return window, true
+}
+
+// findChallengeRun returns the run of the current season whose next level is
+// stageID, or nil.
+func findChallengeRun(commanderID uint32, stageID uint32, now time.Time) (*orm.ChallengeRun, error) {
+	runs, err := orm.ListChallengeRuns(commanderID, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		run := &runs[i]
+		dungeonID, ok := challenge.DungeonAt(orm.ToUint32List(run.DungeonIDs), run.Level, run.Mode)
+		if !ok || dungeonID != stageID {
+			continue
+		}
+		if _, ok := challengeOpen(run.ActivityID, now); !ok {
+			continue
+		}
+		seasonID, err := challengeSeasonID(run.ActivityID, now)
+		if err != nil {
+			return nil, err
+		}
+		if seasonID == run.SeasonID {
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkChallengeStage reports whether a challenge battle may start on
+// stageID with shipIDs: only the ships of the run that are still afloat may
+// sortie.
+func checkChallengeStage(client *connection.Client, stageID uint32, shipIDs []uint32) (bool, error) {
+	run, err := findChallengeRun(client.Commander.CommanderID, stageID, time.Now())
+	if err != nil || run == nil {
+		return false, err
+	}
+	if len(shipIDs) == 0 {
+		return false, nil
+	}
+	living := make(map[uint32]struct{})
+	for _, group := range run.Groups {
+		for _, ship := range group.Ships {
+			if ship.HP > 0 {
+				living[ship.ID] = struct{}{}
+			}
+		}
+	}
+	for _, shipID := range shipIDs {
+		if _, ok := living[shipID]; !ok {
+			return false, nil
+		}
+		// a ship listed twice is refused as well
+		delete(living, shipID)
+	}
+	return true, nil
+}
+
+// challengeHPRatio converts the hp a ship has left after a battle into the
+// ratio a run keeps, in 1/10000 of its max hp. It returns FullHP when the max
+// hp of the ship is unknown.
+func challengeHPRatio(owned *orm.OwnedShip, hpRest uint32) uint32 {
+	if hpRest == 0 {
+		return 0
+	}
+	if owned == nil {
+		return challenge.FullHP
+	}
+	stats := loadShipDataStatistics(owned.ShipID)
+	if stats == nil {
+		return challenge.FullHP
+	}
+	maxHP := shipGrowthForIndex(stats, shipAttrIndexDurability, owned.Level, getExtraAttrLevelLimit())
+	maxHP *= 1 + intimacyAttrBonusRate(owned.Intimacy)
+	if maxHP < 1 {
+		return challenge.FullHP
+	}
+	ratio := uint64(float64(hpRest) * challenge.FullHP / maxHP)
+	if ratio >= challenge.FullHP {
+		return challenge.FullHP
+	}
+	// a ship still afloat keeps at least some hp
+	return uint32(max(ratio, 1))
+}
+
+// finishChallengeStage scores a challenge battle. A win clears the level and
+// carries the hp ratio of the fleet over to the next one; a loss, or clearing the last
+// level of a normal run, ends the run.
+func finishChallengeStage(client *connection.Client, stageID uint32, score uint32, stats map[uint32]*protobuf.STATISTICSINFO, now time.Time) error {
+	commanderID := client.Commander.CommanderID
+	found, err := findChallengeRun(commanderID, stageID, now)
+	if err != nil || found == nil {
+		return err
+	}
+	if client.Commander.OwnedShipsMap == nil {
+		if err := client.Commander.Load(); err != nil {
+			return err
+		}
+	}
+	var run *orm.ChallengeRun
+	ended := false
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		locked, err := orm.GetChallengeRunTx(ctx, tx, commanderID, found.ActivityID, found.Mode)
+		if err != nil {
+			return err
+		}
+		if locked.Level != found.Level || locked.SeasonID != found.SeasonID {
+			// another battle already scored this level
+			return nil
+		}
+		run = locked
+		if score < rankScoreWin {
+			ended = true
+			_, err := orm.DeleteChallengeRunTx(ctx, tx, commanderID, run.ActivityID, run.Mode)
+			return err
+		}
+		run.Score += challenge.Points(run.Level, score >= rankScoreS)
+		for i := range run.Groups {
+			for j := range run.Groups[i].Ships {
+				ship := &run.Groups[i].Ships[j]
+				if entry, ok := stats[ship.ID]; ok {
+					// a battle never heals the fleet
+					ship.HP = min(ship.HP, challengeHPRatio(client.Commander.OwnedShipsMap[ship.ID], entry.GetHpRest()))
+				}
+			}
+		}
+		if err := orm.RecordChallengeScoreTx(ctx, tx, commanderID, run.ActivityID, run.SeasonID, run.Score, run.Level); err != nil {
+			return err
+		}
+		run.Level++
+		run.UpdatedAt = now
+		if _, ok := challenge.DungeonAt(orm.ToUint32List(run.DungeonIDs), run.Level, run.Mode); !ok {
+			ended = true
+			_, err := orm.DeleteChallengeRunTx(ctx, tx, commanderID, run.ActivityID, run.Mode)
+			return err
+		}
+		return orm.SaveChallengeRunTx(ctx, tx, run)
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			// given up while the battle was running
+			return nil
+		}
+		return err
+	}
+	if run == nil {
+		return nil
+	}
+	if ended {
+		_, _, err = client.SendMessage(24010, &protobuf.SC_24010{Score: proto.Uint32(run.Score)})
+		return err
+	}
+	_, _, err = client.SendMessage(24100, &protobuf.SC_24100{Score: proto.Uint32(run.Score)})
+	return err
+}
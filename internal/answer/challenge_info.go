@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/challenge"
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/region"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -36,7 +39,14 @@ func ChallengeInfo(buffer *[]byte, client *connection.Client) (int, int, error)
 		return 0, 24005, err
 	}
 
-	seasonID := uint32(1)
+	now := time.Now()
+	window, _ := challengeWindow(activity.ID)
+	seasonID := challenge.SeasonID(window.Start, window.End, len(config.InfiniteStage), now)
+	commanderID := client.Commander.CommanderID
+	scores, err := orm.ListChallengeScores(commanderID, activity.ID)
+	if err != nil {
+		return 0, 24005, err
+	}
 	currentChallenge := &protobuf.CHALLENGEINFO{
 		SeasonMaxScore:   proto.Uint32(0),
 		ActivityMaxScore: proto.Uint32(0),
@@ -46,12 +56,40 @@ func ChallengeInfo(buffer *[]byte, client *connection.Client) (int, int, error)
 		DungeonIdList:    challengeDungeonList(config, seasonID),
 		BuffList:         config.Buff,
 	}
+	for _, score := range scores {
+		if score.SeasonID == seasonID {
+			currentChallenge.SeasonMaxScore = proto.Uint32(score.MaxScore)
+			currentChallenge.SeasonMaxLevel = proto.Uint32(score.MaxLevel)
+		}
+		currentChallenge.ActivityMaxScore = proto.Uint32(max(currentChallenge.GetActivityMaxScore(), score.MaxScore))
+		currentChallenge.ActivityMaxLevel = proto.Uint32(max(currentChallenge.GetActivityMaxLevel(), score.MaxLevel))
+	}
+
+	runs, err := orm.ListChallengeRuns(commanderID, activity.ID)
+	if err != nil {
+		return 0, 24005, err
+	}
+	userChallenges := make([]*protobuf.USERCHALLENGEINFO, 0, len(runs))
+	for i := range runs {
+		run := &runs[i]
+		if run.SeasonID != seasonID {
+			// the season ended: its score was kept when the levels were cleared
+			if _, err := orm.DeleteChallengeRun(commanderID, run.ActivityID, run.Mode); err != nil {
+				return 0, 24005, err
+			}
+			continue
+		}
+		info, err := userChallengeInfo(client, run)
+		if err != nil {
+			return 0, 24005, err
+		}
+		userChallenges = append(userChallenges, info)
+	}
 
 	response := protobuf.SC_24005{
 		Result:           proto.Uint32(0),
 		CurrentChallenge: currentChallenge,
-		// TODO: Populate user challenge data once challenge progress is persisted.
-		UserChallenge: []*protobuf.USERCHALLENGEINFO{},
+		UserChallenge:    userChallenges,
 	}
 	return client.SendMessage(24005, &response)
 }
@@ -69,7 +107,82 @@ func loadActivityEventChallenge(activity activityTemplate) (activityEventChallen
 }
 
 func challengeDungeonList(config activityEventChallenge, seasonID uint32) []uint32 {
-	// TODO: Select dungeon list based on actual season/rotation rules.
-	seasonIndex := int(seasonID - 1)
-	return config.InfiniteStage[seasonIndex][0]
+	return challenge.Dungeons(config.InfiniteStage, seasonID, challenge.ModeNormal)
+}
+
+// challengeWindow returns the schedule of a challenge activity; ok is false
+// when the activity is not scheduled.
+func challengeWindow(activityID uint32) (ActivityWindow, bool) {
+	windows, err := ActivitySchedule(region.Current())
+	if err != nil {
+		return ActivityWindow{}, false
+	}
+	for _, window := range windows {
+		if window.ActivityID == activityID {
+			return window, true
+		}
+	}
+	return ActivityWindow{}, false
+}
+
+// challengeSeasonID returns the season of a challenge activity open at now.
+func challengeSeasonID(activityID uint32, now time.Time) (uint32, error) {
+	activity, err := loadActivityTemplate(activityID)
+	if err != nil {
+		return 0, err
+	}
+	config, err := loadActivityEventChallenge(activity)
+	if err != nil {
+		return 0, err
+	}
+	window, _ := challengeWindow(activityID)
+	return challenge.SeasonID(window.Start, window.End, len(config.InfiniteStage), now), nil
+}
+
+func userChallengeInfo(client *connection.Client, run *orm.ChallengeRun) (*protobuf.USERCHALLENGEINFO, error) {
+	if client.Commander.OwnedShipsMap == nil {
+		if err := client.Commander.Load(); err != nil {
+			return nil, err
+		}
+	}
+	groups := make([]*protobuf.GROUPINFOINCHALLENGE, 0, len(run.Groups))
+	for _, group := range run.Groups {
+		info := &protobuf.GROUPINFOINCHALLENGE{
+			Id:         proto.Uint32(group.ID),
+			Ships:      make([]*protobuf.SHIPINCHALLENGE, 0, len(group.Ships)),
+			Commanders: make([]*protobuf.COMMANDERINCHALLENGE, 0, len(group.Commanders)),
+		}
+		for _, ship := range group.Ships {
+			owned, ok := client.Commander.OwnedShipsMap[ship.ID]
+			if !ok {
+				continue
+			}
+			info.Ships = append(info.Ships, &protobuf.SHIPINCHALLENGE{
+				Id:       proto.Uint32(ship.ID),
+				HpRant:   proto.Uint32(ship.HP),
+				ShipInfo: orm.ToProtoOwnedShip(*owned, nil, nil),
+			})
+		}
+		for _, commander := range group.Commanders {
+			meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, commander.ID)
+			if err != nil {
+				continue
+			}
+			info.Commanders = append(info.Commanders, &protobuf.COMMANDERINCHALLENGE{
+				Pos:           proto.Uint32(commander.Pos),
+				Commanderinfo: meowfficerInfo(meowfficer, 0),
+			})
+		}
+		groups = append(groups, info)
+	}
+	return &protobuf.USERCHALLENGEINFO{
+		CurrentScore:  proto.Uint32(run.Score),
+		Level:         proto.Uint32(run.Level),
+		GroupincList:  groups,
+		Mode:          proto.Uint32(run.Mode),
+		Issl:          proto.Uint32(0),
+		SeasonId:      proto.Uint32(run.SeasonID),
+		DungeonIdList: orm.ToUint32List(run.DungeonIDs),
+		BuffList:      orm.ToUint32List(run.BuffIDs),
+	}, nil
 }
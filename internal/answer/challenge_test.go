@@ -0,0 +1,169 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/challenge"
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func setupChallengeTest(t *testing.T) *connection.Client {
+	t.Helper()
+	client := setupExerciseTest(t)
+	clearTable(t, &orm.ConfigEntry{})
+	seedConfigEntry(t, "ShareCfg/activity_template.json", "1", `{"id":1,"type":37,"config_id":1}`)
+	seedConfigEntry(t, "ShareCfg/activity_event_challenge.json", "1", `{"id":1,"buff":[9],"infinite_stage":[[[10001,10002]]]}`)
+	seedActivityAllowlist(t, []uint32{1})
+	return client
+}
+
+func startTestChallenge(t *testing.T, client *connection.Client, shipIDs []uint32) uint32 {
+	t.Helper()
+	payload := protobuf.CS_24002{
+		ActivityId: proto.Uint32(1),
+		Mode:       proto.Uint32(challenge.ModeNormal),
+		GroupList:  []*protobuf.GROUPINFO_P24{{Id: proto.Uint32(1), ShipList: shipIDs}},
+	}
+	buffer, err := proto.Marshal(&payload)
+	if err != nil {
+		t.Fatalf("marshal request failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := StartChallenge(&buffer, client); err != nil {
+		t.Fatalf("start challenge failed: %v", err)
+	}
+	var response protobuf.SC_24003
+	decodePacketMessage(t, client, 24003, &response)
+	return response.GetResult()
+}
+
+func TestChallengeRunScoresAndRanks(t *testing.T) {
+	client := setupChallengeTest(t)
+	if result := startTestChallenge(t, client, []uint32{1, 99}); result != challengeResultFailed {
+		t.Fatalf("expected a run with a foreign ship to be refused, got %d", result)
+	}
+	if result := startTestChallenge(t, client, []uint32{1, 2}); result != challengeResultOK {
+		t.Fatalf("expected run to start, got %d", result)
+	}
+	if result := startTestChallenge(t, client, []uint32{3}); result != challengeResultFailed {
+		t.Fatalf("expected a second run in the same mode to be refused, got %d", result)
+	}
+
+	if ok, err := checkChallengeStage(client, 10002, []uint32{1, 2}); err != nil || ok {
+		t.Fatalf("expected battle on a later level to be refused, got %v %v", ok, err)
+	}
+	if ok, err := checkChallengeStage(client, 10001, []uint32{1, 3}); err != nil || ok {
+		t.Fatalf("expected battle with a ship outside the run to be refused, got %v %v", ok, err)
+	}
+	if ok, err := checkChallengeStage(client, 10001, []uint32{1, 2}); err != nil || !ok {
+		t.Fatalf("expected battle on the first level to be allowed, got %v %v", ok, err)
+	}
+	seedConfigEntry(t, "sharecfgdata/ship_data_statistics.json", "1001", `{"id":1001,"attrs":[1000,0,0,0,0,0,0,0,0,0,0,0],"attrs_growth":[0,0,0,0,0,0,0,0,0,0,0,0],"attrs_growth_extra":[0,0,0,0,0,0,0,0,0,0,0,0]}`)
+	client.Buffer.Reset()
+	stats := map[uint32]*protobuf.STATISTICSINFO{
+		1: {ShipId: proto.Uint32(1), HpRest: proto.Uint32(400)},
+		2: {ShipId: proto.Uint32(2), HpRest: proto.Uint32(0)},
+	}
+	if err := finishChallengeStage(client, 10001, rankScoreS, stats, time.Now()); err != nil {
+		t.Fatalf("finish first level: %v", err)
+	}
+	var progress protobuf.SC_24100
+	decodePacketMessage(t, client, 24100, &progress)
+	if progress.GetScore() != challenge.Points(1, true) {
+		t.Fatalf("expected score %d, got %d", challenge.Points(1, true), progress.GetScore())
+	}
+
+	request, err := proto.Marshal(&protobuf.CS_24004{ActivityId: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("marshal request failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := ChallengeInfo(&request, client); err != nil {
+		t.Fatalf("challenge info failed: %v", err)
+	}
+	var info protobuf.SC_24005
+	decodePacketMessage(t, client, 24005, &info)
+	if len(info.GetUserChallenge()) != 1 {
+		t.Fatalf("expected one run in progress, got %d", len(info.GetUserChallenge()))
+	}
+	run := info.GetUserChallenge()[0]
+	if run.GetLevel() != 2 || run.GetCurrentScore() != progress.GetScore() || len(run.GetBuffList()) != 1 || run.GetBuffList()[0] != 9 {
+		t.Fatalf("unexpected run %v", run)
+	}
+	ships := run.GetGroupincList()[0].GetShips()
+	if len(ships) != 2 || ships[0].GetHpRant() != 4000 || ships[1].GetHpRant() != 0 {
+		t.Fatalf("expected the fleet to keep its hp ratio, got %v", ships)
+	}
+	if info.GetCurrentChallenge().GetSeasonMaxScore() != progress.GetScore() || info.GetCurrentChallenge().GetSeasonMaxLevel() != 1 {
+		t.Fatalf("expected season best to be recorded, got %v", info.GetCurrentChallenge())
+	}
+
+	if ok, err := checkChallengeStage(client, 10002, []uint32{1, 2}); err != nil || ok {
+		t.Fatalf("expected battle with a sunk ship to be refused, got %v %v", ok, err)
+	}
+	if ok, err := checkChallengeStage(client, 10002, []uint32{1}); err != nil || !ok {
+		t.Fatalf("expected battle with the ships afloat to be allowed, got %v %v", ok, err)
+	}
+	client.Buffer.Reset()
+	if err := finishChallengeStage(client, 10002, rankScoreWin, nil, time.Now()); err != nil {
+		t.Fatalf("finish last level: %v", err)
+	}
+	var ended protobuf.SC_24010
+	decodePacketMessage(t, client, 24010, &ended)
+	total := challenge.Points(1, true) + challenge.Points(2, false)
+	if ended.GetScore() != total {
+		t.Fatalf("expected final score %d, got %d", total, ended.GetScore())
+	}
+	if runs, err := orm.ListChallengeRuns(client.Commander.CommanderID, 1); err != nil || len(runs) != 0 {
+		t.Fatalf("expected run to end after its last level, got %v %v", runs, err)
+	}
+
+	rankRequest, err := proto.Marshal(&protobuf.CS_18203{Type: proto.Uint32(billboardRankTypeChallenge), ActId: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("marshal request failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := BillboardMyRank(&rankRequest, client); err != nil {
+		t.Fatalf("billboard my rank failed: %v", err)
+	}
+	var rank protobuf.SC_18204
+	decodePacketMessage(t, client, 18204, &rank)
+	if rank.GetPoint() != total || rank.GetRank() != 1 {
+		t.Fatalf("expected rank 1 with %d points, got %d %d", total, rank.GetRank(), rank.GetPoint())
+	}
+}
+
+func TestChallengeLossEndsRun(t *testing.T) {
+	client := setupChallengeTest(t)
+	if result := startTestChallenge(t, client, []uint32{1}); result != challengeResultOK {
+		t.Fatalf("expected run to start, got %d", result)
+	}
+	client.Buffer.Reset()
+	if err := finishChallengeStage(client, 10001, 1, nil, time.Now()); err != nil {
+		t.Fatalf("finish lost level: %v", err)
+	}
+	var ended protobuf.SC_24010
+	decodePacketMessage(t, client, 24010, &ended)
+	if ended.GetScore() != 0 {
+		t.Fatalf("expected no score, got %d", ended.GetScore())
+	}
+
+	request, err := proto.Marshal(&protobuf.CS_24011{ActivityId: proto.Uint32(1), Mode: proto.Uint32(challenge.ModeNormal)})
+	if err != nil {
+		t.Fatalf("marshal request failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := GiveUpChallenge(&request, client); err != nil {
+		t.Fatalf("give up failed: %v", err)
+	}
+	var response protobuf.SC_24012
+	decodePacketMessage(t, client, 24012, &response)
+	if response.GetResult() != challengeResultFailed {
+		t.Fatalf("expected give up without a run to fail, got %d", response.GetResult())
+	}
+}
@@ -0,0 +1,59 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+func TestRollChapterDropsUsesDropTables(t *testing.T) {
+	client := setupHandlerCommander(t)
+	clearTable(t, &orm.DropTable{})
+	clearTable(t, &orm.DropBonus{})
+	execAnswerTestSQLT(t, "DELETE FROM chapter_drops WHERE commander_id = $1", int64(client.Commander.CommanderID))
+	seedConfigEntry(t, "sharecfgdata/item_virtual_data_statistics.json", "90002", `{"id":90002,"type":99,"virtual_type":0,"display_icon":[[2,8001,1],[2,8002,1]]}`)
+
+	chapter := orm.DropTable{Kind: orm.DropKindChapter, SourceID: 204, Rolls: 2, RankSRate: 100, RankARate: 100, RankBRate: 100, RateMultiplier: 100, Enabled: true, Entries: []orm.DropTableEntry{
+		{DropType: consts.DROP_TYPE_SHIP, DropID: 101061, Count: 1, Weight: 1},
+	}}
+	item := orm.DropTable{Kind: orm.DropKindItem, SourceID: 90002, Rolls: 1, RankSRate: 100, RankARate: 100, RankBRate: 100, RateMultiplier: 100, Enabled: true, Entries: []orm.DropTableEntry{
+		{DropType: 2, DropID: 8002, Count: 5, Weight: 1},
+	}}
+	for _, table := range []*orm.DropTable{&chapter, &item} {
+		if err := orm.CreateDropTable(table); err != nil {
+			t.Fatalf("create drop table: %v", err)
+		}
+	}
+
+	// the table replaces the award list, which would grant the virtual item
+	drops, err := rollChapterDrops(client, &chapterTemplate{ID: 204, Awards: [][]uint32{{2, 8000}}}, 4, time.Now())
+	if err != nil {
+		t.Fatalf("roll chapter drops: %v", err)
+	}
+	if len(drops) != 1 || drops["4_101061"] == nil || drops["4_101061"].GetNumber() != 2 {
+		t.Fatalf("expected 2 rolled ships, got %v", drops)
+	}
+	recorded := queryAnswerTestInt64(t, "SELECT COUNT(*) FROM chapter_drops WHERE commander_id = $1 AND chapter_id = $2 AND ship_id = $3", int64(client.Commander.CommanderID), int64(204), int64(101061))
+	if recorded != 1 {
+		t.Fatalf("expected the ship to be recorded in the chapter drop list, got %d", recorded)
+	}
+
+	dropType, dropID, count, err := resolveChapterAwardDrop(consts.DROP_TYPE_ITEM, 90002)
+	if err != nil {
+		t.Fatalf("resolve virtual item: %v", err)
+	}
+	if dropType != 2 || dropID != 8002 || count != 5 {
+		t.Fatalf("expected the item table to be used, got %d:%d x%d", dropType, dropID, count)
+	}
+
+	// chapters without a table keep their award list
+	drops, err = rollChapterDrops(client, &chapterTemplate{ID: 205, Awards: [][]uint32{{2, 8000}}}, 4, time.Now())
+	if err != nil {
+		t.Fatalf("roll chapter drops: %v", err)
+	}
+	if len(drops) != 1 || drops["2_8000"] == nil {
+		t.Fatalf("expected the award list, got %v", drops)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"github.com/ggmolly/belfast/internal/rng"
@@ -436,8 +437,11 @@ func upsertChapterCell(current *protobuf.CURRENTCHAPTERINFO, cell *protobuf.CHAP
 }
 
 const (
-	shipAttrIndexAir   = 4
-	shipAttrIndexDodge = 8
+	shipAttrIndexDurability = 0
+	shipAttrIndexCannon     = 1
+	shipAttrIndexTorpedo    = 2
+	shipAttrIndexAir        = 4
+	shipAttrIndexDodge      = 8
 )
 
 // The ship stats JSON encodes attrs/attrs_growth as an array ordered by the
@@ -631,14 +635,11 @@ func shipGrowthForIndex(stats *shipDataStatisticsEntry, index int, level uint32,
 }
 
 func loadShipDataStatistics(templateID uint32) *shipDataStatisticsEntry {
-	entry, err := orm.GetConfigEntry("sharecfgdata/ship_data_statistics.json", strconv.FormatUint(uint64(templateID), 10))
-	if err != nil || entry == nil {
-		return nil
-	}
-	var stats shipDataStatisticsEntry
-	if err := json.Unmarshal(entry.Data, &stats); err != nil {
+	cached, err := gamedata.Get[shipDataStatisticsEntry](gamedata.Default, "sharecfgdata/ship_data_statistics.json", strconv.FormatUint(uint64(templateID), 10))
+	if err != nil {
 		return nil
 	}
+	stats := *cached
 	return &stats
 }
 
@@ -723,26 +724,20 @@ func calculateFleetEquipDodgeRateUp(group *protobuf.GROUPINCHAPTER_P13, client *
 }
 
 func loadEquipDataStatistics(equipID uint32) *equipDataStatisticsEntry {
-	entry, err := orm.GetConfigEntry("sharecfgdata/equip_data_statistics.json", strconv.FormatUint(uint64(equipID), 10))
-	if err != nil || entry == nil {
-		return nil
-	}
-	var stats equipDataStatisticsEntry
-	if err := json.Unmarshal(entry.Data, &stats); err != nil {
+	cached, err := gamedata.Get[equipDataStatisticsEntry](gamedata.Default, "sharecfgdata/equip_data_statistics.json", strconv.FormatUint(uint64(equipID), 10))
+	if err != nil {
 		return nil
 	}
+	stats := *cached
 	return &stats
 }
 
 func loadTransformData(transformID uint32) *transformDataEntry {
-	entry, err := orm.GetConfigEntry("ShareCfg/transform_data_template.json", strconv.FormatUint(uint64(transformID), 10))
-	if err != nil || entry == nil {
-		return nil
-	}
-	var stats transformDataEntry
-	if err := json.Unmarshal(entry.Data, &stats); err != nil {
+	cached, err := gamedata.Get[transformDataEntry](gamedata.Default, "ShareCfg/transform_data_template.json", strconv.FormatUint(uint64(transformID), 10))
+	if err != nil {
 		return nil
 	}
+	stats := *cached
 	return &stats
 }
 
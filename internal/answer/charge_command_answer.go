@@ -1,19 +1,54 @@
 package answer
 
 import (
+	"errors"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/payment"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	chargeResultOK       = 0
+	chargeResultFailed   = 1
+	chargeResultDisabled = 5002
+)
+
+// ChargeCommandAnswer starts the payment of a pay_data_display goods.
 func ChargeCommandAnswer(buffer *[]byte, client *connection.Client) (int, int, error) {
-	// Disable Charge
+	var request protobuf.CS_11501
+	if err := proto.Unmarshal(*buffer, &request); err != nil {
+		return 0, 11502, err
+	}
+	result, order, checkout, err := createChargeOrder(client, request.GetShopId(), orm.PaymentKindCharge)
+	if err != nil {
+		return 0, 11502, err
+	}
 	response := protobuf.SC_11502{
-		Result:    proto.Uint32(5002),
-		PayId:     proto.String(""),
-		Url:       proto.String(""),
-		OrderSign: proto.String(""),
+		Result:    proto.Uint32(result),
+		PayId:     proto.String(order.PayID),
+		Url:       proto.String(checkout.URL),
+		OrderSign: proto.String(checkout.Sign),
 	}
-
 	return client.SendMessage(11502, &response)
 }
+
+// createChargeOrder returns the result code of an order creation; order and
+// checkout are empty unless it is chargeResultOK.
+func createChargeOrder(client *connection.Client, shopID uint32, kind string) (uint32, orm.PaymentOrder, payment.Checkout, error) {
+	if payment.Current() == nil {
+		return chargeResultDisabled, orm.PaymentOrder{}, payment.Checkout{}, nil
+	}
+	order, checkout, err := payment.CreateOrder(client.Commander.CommanderID, shopID, kind)
+	switch {
+	case errors.Is(err, payment.ErrDisabled):
+		return chargeResultDisabled, orm.PaymentOrder{}, payment.Checkout{}, nil
+	case errors.Is(err, payment.ErrUnknownGoods), errors.Is(err, payment.ErrNoRefund), errors.Is(err, payment.ErrUnsupportedGoods):
+		return chargeResultFailed, orm.PaymentOrder{}, payment.Checkout{}, nil
+	case err != nil:
+		return 0, orm.PaymentOrder{}, payment.Checkout{}, err
+	}
+	return chargeResultOK, *order, checkout, nil
+}
@@ -0,0 +1,92 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/payment"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChargeFlowWithSandbox(t *testing.T) {
+	client := setupHandlerCommander(t)
+	clearTable(t, &orm.PaymentOrder{})
+	commanderID := client.Commander.CommanderID
+	seedConfigEntry(t, "ShareCfg/pay_data_display.json", "3", `{"id":3,"money":6,"gem":60,"extra_gem":0,"first_pay_double":1,"extra_service_item":""}`)
+	if err := payment.Configure(config.PaymentsConfig{Provider: payment.SandboxName, AutoApprove: true}); err != nil {
+		t.Fatalf("configure sandbox: %v", err)
+	}
+	t.Cleanup(func() { _ = payment.Configure(config.PaymentsConfig{}) })
+
+	charge := func() string {
+		t.Helper()
+		client.Buffer.Reset()
+		buffer, err := proto.Marshal(&protobuf.CS_11501{ShopId: proto.Uint32(3), Device: proto.Uint32(1)})
+		if err != nil {
+			t.Fatalf("marshal charge: %v", err)
+		}
+		if _, _, err := ChargeCommandAnswer(&buffer, client); err != nil {
+			t.Fatalf("charge failed: %v", err)
+		}
+		var response protobuf.SC_11502
+		decodeResponse(t, client, &response)
+		if response.GetResult() != chargeResultOK || response.GetPayId() == "" {
+			t.Fatalf("unexpected charge response %+v", &response)
+		}
+		return response.GetPayId()
+	}
+	confirm := func(payID string) *protobuf.SC_11505 {
+		t.Helper()
+		client.Buffer.Reset()
+		buffer, err := proto.Marshal(&protobuf.CS_11504{PayId: proto.String(payID), PayIdBili: proto.String("")})
+		if err != nil {
+			t.Fatalf("marshal confirm: %v", err)
+		}
+		if _, _, err := ChargeConfirmCommandAnswer(&buffer, client); err != nil {
+			t.Fatalf("confirm failed: %v", err)
+		}
+		var response protobuf.SC_11505
+		decodeResponse(t, client, &response)
+		return &response
+	}
+
+	payID := charge()
+	response := confirm(payID)
+	if response.GetResult() != chargeResultOK || response.GetShopId() != 3 || response.GetGem() != 120 {
+		t.Fatalf("expected doubled first charge, got %+v", response)
+	}
+	if gems := queryAnswerTestInt64(t, "SELECT amount FROM owned_resources WHERE commander_id = $1 AND resource_id = $2", int64(commanderID), int64(gemResourceID)); gems != 120 {
+		t.Fatalf("expected 120 gems, got %d", gems)
+	}
+	if accPayLv := queryAnswerTestInt64(t, "SELECT acc_pay_lv FROM commanders WHERE commander_id = $1", int64(commanderID)); accPayLv != 6 || client.Commander.AccPayLv != 6 {
+		t.Fatalf("expected acc_pay_lv 6, got %d (loaded %d)", accPayLv, client.Commander.AccPayLv)
+	}
+	if response := confirm(payID); response.GetResult() != chargeResultFailed {
+		t.Fatalf("expected a delivered order not to be delivered twice, got %d", response.GetResult())
+	}
+
+	response = confirm(charge())
+	if response.GetGem() != 60 {
+		t.Fatalf("expected plain second charge, got %d", response.GetGem())
+	}
+	if gems := queryAnswerTestInt64(t, "SELECT amount FROM owned_resources WHERE commander_id = $1 AND resource_id = $2", int64(commanderID), int64(gemResourceID)); gems != 180 {
+		t.Fatalf("expected 180 gems, got %d", gems)
+	}
+
+	seedConfigEntry(t, "ShareCfg/pay_data_display.json", "1", `{"id":1,"money":30,"gem":300,"extra_gem":0,"first_pay_double":0,"extra_service_item":"","genre":"monthly_card"}`)
+	client.Buffer.Reset()
+	buffer, err := proto.Marshal(&protobuf.CS_11501{ShopId: proto.Uint32(1), Device: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("marshal charge: %v", err)
+	}
+	if _, _, err := ChargeCommandAnswer(&buffer, client); err != nil {
+		t.Fatalf("charge failed: %v", err)
+	}
+	var monthly protobuf.SC_11502
+	decodeResponse(t, client, &monthly)
+	if monthly.GetResult() != chargeResultFailed {
+		t.Fatalf("expected monthly cards to be refused, got %d", monthly.GetResult())
+	}
+}
@@ -1,34 +1,165 @@
 package answer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/payment"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/proto"
 )
 
+const gemResourceID = 4
+
+var errOrderNotPaid = errors.New("order is not paid")
+
+// RefundChargeCommandAnswer starts the payment paying back a refunded goods.
 func RefundChargeCommandAnswer(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var request protobuf.CS_11513
+	if err := proto.Unmarshal(*buffer, &request); err != nil {
+		return 0, 11514, err
+	}
+	result, order, checkout, err := createChargeOrder(client, request.GetShopId(), orm.PaymentKindRepay)
+	if err != nil {
+		return 0, 11514, err
+	}
 	response := protobuf.SC_11514{
-		Result:    proto.Uint32(5002),
-		PayId:     proto.String(""),
-		Url:       proto.String(""),
-		OrderSign: proto.String(""),
+		Result:    proto.Uint32(result),
+		PayId:     proto.String(order.PayID),
+		Url:       proto.String(checkout.URL),
+		OrderSign: proto.String(checkout.Sign),
 	}
 	return client.SendMessage(11514, &response)
 }
 
+// ChargeConfirmCommandAnswer delivers a paid order.
 func ChargeConfirmCommandAnswer(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var request protobuf.CS_11504
+	if err := proto.Unmarshal(*buffer, &request); err != nil {
+		return 0, 11505, err
+	}
 	response := protobuf.SC_11505{
-		Result:  proto.Uint32(5002),
+		Result:  proto.Uint32(chargeResultDisabled),
 		ShopId:  proto.Uint32(0),
 		Gem:     proto.Uint32(0),
 		GemFree: proto.Uint32(0),
 	}
+	if payment.Current() == nil {
+		return client.SendMessage(11505, &response)
+	}
+	order, err := deliverPaymentOrder(client, request.GetPayId())
+	switch {
+	case db.IsNotFound(err), errors.Is(err, errOrderNotPaid), errors.Is(err, payment.ErrUnsupportedGoods):
+		response.Result = proto.Uint32(chargeResultFailed)
+		return client.SendMessage(11505, &response)
+	case err != nil:
+		return 0, 11505, err
+	}
+	response.Result = proto.Uint32(chargeResultOK)
+	response.ShopId = proto.Uint32(order.ShopID)
+	response.Gem = proto.Uint32(order.Gem)
+	response.GemFree = proto.Uint32(order.GemFree)
 	return client.SendMessage(11505, &response)
 }
 
+// deliverPaymentOrder grants the goods of a paid order to the commander of
+// client. Charges add to its accumulated pay level, repayments settle the
+// refunds of their goods.
+func deliverPaymentOrder(client *connection.Client, payID string) (*orm.PaymentOrder, error) {
+	ctx := context.Background()
+	commanderID := client.Commander.CommanderID
+	var order *orm.PaymentOrder
+	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		loaded, err := orm.GetCommanderPaymentOrderTx(ctx, tx, commanderID, payID)
+		if err != nil {
+			return err
+		}
+		if loaded.Status != orm.PaymentStatusPaid {
+			return errOrderNotPaid
+		}
+		order = loaded
+		if order.Kind == orm.PaymentKindRepay {
+			if err := orm.SettleRefundsTx(ctx, tx, commanderID, order.ShopID); err != nil {
+				return err
+			}
+			return orm.MarkPaymentOrderDeliveredTx(ctx, tx, order.ID)
+		}
+		goods, err := payment.LoadGoods(order.ShopID)
+		if err != nil {
+			return err
+		}
+		if !goods.Deliverable() {
+			// never mark it delivered without granting what was paid for
+			return payment.ErrUnsupportedGoods
+		}
+		if gems := order.Gem + order.GemFree; gems > 0 {
+			if err := client.Commander.AddResourceTx(ctx, tx, gemResourceID, gems); err != nil {
+				return err
+			}
+		}
+		for _, item := range goods.ExtraServiceItem {
+			if len(item) < 3 {
+				continue
+			}
+			err := grantShopCommodityTx(ctx, tx, client, item[0], item[1], item[2])
+			if errors.Is(err, errShopUnsupported) {
+				// the charge was paid, skip the drop rather than keeping the order stuck
+				logger.LogEvent("Payment", "Deliver", fmt.Sprintf("order %s: unsupported drop type %d", order.PayID, item[0]), logger.LOG_LEVEL_WARN)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := orm.AddAccPayLvTx(ctx, tx, commanderID, order.Money); err != nil {
+			return err
+		}
+		return orm.MarkPaymentOrderDeliveredTx(ctx, tx, order.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if order.Kind == orm.PaymentKindCharge {
+		client.Commander.AccPayLv += order.Money
+	}
+	return order, nil
+}
+
+// ChargeFailedCommandAnswer records that the client gave up paying an order.
 func ChargeFailedCommandAnswer(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var request protobuf.CS_11510
+	if err := proto.Unmarshal(*buffer, &request); err != nil {
+		return 0, 11511, err
+	}
+	if payment.Current() != nil && client.Commander != nil {
+		err := orm.MarkPaymentOrderFailed(client.Commander.CommanderID, request.GetPayId(), request.GetCode())
+		if err != nil && !db.IsNotFound(err) {
+			return 0, 11511, err
+		}
+	}
 	response := protobuf.SC_11511{
 		Result: proto.Uint32(0),
 	}
 	return client.SendMessage(11511, &response)
 }
+
+// NotifyPaidOrders tells a commander logging in about the orders paid while
+// it was offline, so the client confirms them.
+func NotifyPaidOrders(buffer *[]byte, client *connection.Client) (int, int, error) {
+	orders, err := orm.ListPaidPaymentOrders(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 11503, err
+	}
+	for i := range orders {
+		if _, _, err := client.SendMessage(11503, payment.PaidMessage(&orders[i])); err != nil {
+			return 0, 11503, err
+		}
+	}
+	return 0, 11503, nil
+}
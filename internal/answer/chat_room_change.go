@@ -1,6 +1,7 @@
 package answer
 
 import (
+	"github.com/ggmolly/belfast/internal/chat"
 	"github.com/ggmolly/belfast/internal/connection"
 
 	"github.com/ggmolly/belfast/internal/protobuf"
@@ -19,5 +20,17 @@ func ChatRoomChange(buffer *[]byte, client *connection.Client) (int, int, error)
 		Result: proto.Uint32(0),
 		RoomId: data.RoomId,
 	}
-	return client.SendMessage(11402, &response)
+	if _, _, err := client.SendMessage(11402, &response); err != nil {
+		return 0, 11402, err
+	}
+	history, err := chat.History(data.GetRoomId())
+	if err != nil {
+		return 0, 11402, err
+	}
+	for _, message := range history {
+		if _, _, err := client.SendMessage(50101, message); err != nil {
+			return 0, 11402, err
+		}
+	}
+	return 0, 11402, nil
 }
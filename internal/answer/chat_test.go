@@ -0,0 +1,123 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/chat"
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func setupChatTest(t *testing.T, cfg config.ChatConfig) *protobuf.CS_50102 {
+	t.Helper()
+	clearTable(t, &orm.Message{})
+	clearTable(t, &orm.ChatMute{})
+	if err := chat.Default.Configure(cfg); err != nil {
+		t.Fatalf("configure chat: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = chat.Default.Configure(config.ChatConfig{})
+	})
+	return &protobuf.CS_50102{Type: proto.Uint32(orm.MSG_TYPE_NORMAL)}
+}
+
+func sendChatMessage(t *testing.T, payload *protobuf.CS_50102, content string) []byte {
+	t.Helper()
+	payload.Content = proto.String(content)
+	data, err := proto.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal chat payload: %v", err)
+	}
+	return data
+}
+
+func TestReceiveChatMessageFiltersContent(t *testing.T) {
+	client := setupHandlerCommander(t)
+	payload := setupChatTest(t, config.ChatConfig{WordFilter: []string{"darn"}})
+	client.Server.JoinRoom(client.Commander.RoomID, client)
+	t.Cleanup(func() { client.Server.LeaveRoom(client.Commander.RoomID, client) })
+
+	data := sendChatMessage(t, payload, "well DARN it")
+	client.Buffer.Reset()
+	if _, _, err := ReceiveChatMessage(&data, client); err != nil {
+		t.Fatalf("receive chat message failed: %v", err)
+	}
+	var response protobuf.SC_50101
+	decodePacketAt(t, client, 0, 50101, &response)
+	if response.GetContent() != "well **** it" || response.GetType() != orm.MSG_TYPE_NORMAL {
+		t.Fatalf("unexpected broadcast: %+v", response.String())
+	}
+	history, err := orm.GetRoomHistory(client.Commander.RoomID, 10)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 1 || history[0].Content != "well **** it" {
+		t.Fatalf("expected the filtered message to be stored, got %+v", history)
+	}
+}
+
+func TestReceiveChatMessageMutesFlooding(t *testing.T) {
+	client := setupHandlerCommander(t)
+	payload := setupChatTest(t, config.ChatConfig{FloodMessages: 2, FloodWindowSeconds: 60, FloodMuteSeconds: 120})
+
+	for i := 0; i < 3; i++ {
+		data := sendChatMessage(t, payload, "spam")
+		client.Buffer.Reset()
+		if _, _, err := ReceiveChatMessage(&data, client); err != nil {
+			t.Fatalf("receive chat message failed: %v", err)
+		}
+	}
+	var response protobuf.SC_50101
+	decodePacketAt(t, client, 0, 50101, &response)
+	if response.GetType() != orm.MSG_TYPE_BANNED {
+		t.Fatalf("expected a mute notice, got %+v", response.String())
+	}
+	if _, err := orm.GetActiveChatMute(client.Commander.CommanderID, time.Now()); err != nil {
+		t.Fatalf("expected commander to be muted: %v", err)
+	}
+	history, err := orm.GetRoomHistory(client.Commander.RoomID, 10)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 stored messages, got %d", len(history))
+	}
+}
+
+func TestChatRoomChangeReplaysHistory(t *testing.T) {
+	client := setupHandlerCommander(t)
+	setupChatTest(t, config.ChatConfig{HistorySize: 2})
+	for _, content := range []string{"one", "two", "three"} {
+		if _, err := orm.SendMessage(7, content, client.Commander); err != nil {
+			t.Fatalf("seed message: %v", err)
+		}
+	}
+	payload := protobuf.CS_11401{RoomId: proto.Uint32(7)}
+	data, err := proto.Marshal(&payload)
+	if err != nil {
+		t.Fatalf("marshal chat room payload: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := ChatRoomChange(&data, client); err != nil {
+		t.Fatalf("chat room change failed: %v", err)
+	}
+	var response protobuf.SC_11402
+	offset := decodePacketAt(t, client, 0, 11402, &response)
+	if response.GetRoomId() != 7 {
+		t.Fatalf("expected room id 7")
+	}
+	for _, expected := range []string{"two", "three"} {
+		var message protobuf.SC_50101
+		offset = decodePacketAt(t, client, offset, 50101, &message)
+		if message.GetContent() != expected || message.GetPlayer().GetName() != client.Commander.Name {
+			t.Fatalf("expected %q from %q, got %+v", expected, client.Commander.Name, message.String())
+		}
+	}
+	if offset != client.Buffer.Len() {
+		t.Fatalf("expected only 2 history messages")
+	}
+}
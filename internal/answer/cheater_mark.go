@@ -1,11 +1,17 @@
 package answer
 
 import (
+	"fmt"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+// CheaterMark handles CS_10994, sent by the client when its own checks
+// detect tampering. The report is recorded alongside the marks raised by
+// the battle result validation.
 func CheaterMark(buffer *[]byte, client *connection.Client) (int, int, error) {
 	var protoData protobuf.CS_10994
 	err := proto.Unmarshal((*buffer), &protoData)
@@ -13,6 +19,15 @@ func CheaterMark(buffer *[]byte, client *connection.Client) (int, int, error) {
 		return 0, 10995, err
 	}
 
+	mark := orm.CheaterMark{
+		CommanderID: client.Commander.CommanderID,
+		Source:      orm.CheaterMarkSourceClient,
+		Reason:      fmt.Sprintf("client_type_%d", protoData.GetType()),
+	}
+	if err := orm.CreateCheaterMark(&mark); err != nil {
+		return 0, 10995, err
+	}
+
 	response := protobuf.SC_10995{
 		Result: proto.Uint32(protoData.GetType()),
 	}
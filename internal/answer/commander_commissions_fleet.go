@@ -1,15 +1,50 @@
 package answer
 
 import (
+	"time"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
 func CommanderCommissionsFleet(buffer *[]byte, client *connection.Client) (int, int, error) {
-	var response protobuf.SC_13201
-	response.EliteExpeditionCount = proto.Uint32(0)
-	response.EscortExpeditionCount = proto.Uint32(0)
+	now := time.Now()
+	state, err := loadDailyRaidState(client, now)
+	if err != nil {
+		return 0, 13201, err
+	}
+	counts, err := orm.ListDailyRaidCounts(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 13201, err
+	}
+	templates, err := loadDailyLevelTemplates()
+	if err != nil {
+		return 0, 13201, err
+	}
+	clears, err := orm.ListStageClears(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 13201, err
+	}
+	response := protobuf.SC_13201{
+		CountList:             make([]*protobuf.EXPEDITION_DAILY_COUNT, 0, len(counts)),
+		EliteExpeditionCount:  proto.Uint32(state.EliteExpeditionCount),
+		EscortExpeditionCount: proto.Uint32(state.EscortExpeditionCount),
+		ChapterCountList:      []*protobuf.EXPEDITION_DAILY_COUNT{},
+		QuickExpeditionList:   []uint32{},
+	}
+	for _, entry := range counts {
+		response.CountList = append(response.CountList, &protobuf.EXPEDITION_DAILY_COUNT{
+			Id:    proto.Uint32(entry.DailyID),
+			Count: proto.Uint32(entry.Count),
+		})
+	}
+	for _, entry := range clears {
+		if _, _, ok := findDailyLevelForStage(templates, entry.StageID); ok {
+			response.QuickExpeditionList = append(response.QuickExpeditionList, entry.StageID)
+		}
+	}
 	return client.SendMessage(13201, &response)
 }
@@ -19,7 +19,7 @@ func CommanderFleet(buffer *[]byte, client *connection.Client) (int, int, error)
 			Id:         proto.Uint32(fleet.GameID),
 			Name:       proto.String(fleet.Name),
 			ShipList:   shipList,
-			Commanders: []*protobuf.COMMANDERSINFO{},
+			Commanders: meowfficerPositions(fleet.MeowfficerList),
 		})
 	}
 
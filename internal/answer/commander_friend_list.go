@@ -2,10 +2,28 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 )
 
 func CommanderFriendList(buffer *[]byte, client *connection.Client) (int, int, error) {
-	var response protobuf.SC_50000 // Create an empty FriendList / RequestList
+	friends, err := orm.ListCommanderFriends(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 50000, err
+	}
+	requests, err := orm.ListIncomingFriendRequests(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 50000, err
+	}
+	response := protobuf.SC_50000{
+		FriendList:  make([]*protobuf.FRIEND_INFO, 0, len(friends)),
+		RequestList: make([]*protobuf.MSG_INFO_P50, 0, len(requests)),
+	}
+	for i := range friends {
+		response.FriendList = append(response.FriendList, buildFriendInfo(client, &friends[i]))
+	}
+	for i := range requests {
+		response.RequestList = append(response.RequestList, buildFriendRequestMessage(&requests[i]))
+	}
 	return client.SendMessage(50000, &response)
 }
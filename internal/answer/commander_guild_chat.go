@@ -20,7 +20,14 @@ func CommanderGuildChat(buffer *[]byte, client *connection.Client) (int, int, er
 	if count > chatLogMaxCount {
 		count = chatLogMaxCount
 	}
-	entries, err := orm.ListGuildChatMessages(guildChatPlaceholderID, count)
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60101, err
+	}
+	if member == nil {
+		return client.SendMessage(60101, &protobuf.SC_60101{ChatList: []*protobuf.GUIDE_CHAT{}})
+	}
+	entries, err := orm.ListGuildChatMessages(member.GuildID, count)
 	if err != nil {
 		return 0, 60101, err
 	}
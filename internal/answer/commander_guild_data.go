@@ -1,42 +1,86 @@
 package answer
 
 import (
+	"time"
+
 	"github.com/ggmolly/belfast/internal/connection"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 )
 
+func emptyGuildExpansionInfo() *protobuf.GUILD_EXPANSION_INFO {
+	return &protobuf.GUILD_EXPANSION_INFO{
+		Capital: proto.Uint32(0),
+		ThisWeeklyTasks: &protobuf.WEEKLY_TASK{
+			Id:            proto.Uint32(0),
+			Progress:      proto.Uint32(0),
+			Monday_0Clock: proto.Uint32(0),
+		},
+		BenefitFinishTime:     proto.Uint32(0),
+		RetreatCnt:            proto.Uint32(0),
+		TechCancelCnt:         proto.Uint32(0),
+		LastBenefitFinishTime: proto.Uint32(0),
+		ActiveEventCnt:        proto.Uint32(0),
+	}
+}
+
+// buildGuildExpansionInfo reports the guild funds, weekly task and research
+// state. Weekly progress from a previous week reads as zero.
+func buildGuildExpansionInfo(guild *orm.Guild, now time.Time) (*protobuf.GUILD_EXPANSION_INFO, error) {
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return nil, err
+	}
+	technologies, err := buildGuildTechnologies(guild, catalog)
+	if err != nil {
+		return nil, err
+	}
+	week := uint32(weekStartMondayUTC(now).Unix())
+	progress := uint32(0)
+	if guild.WeeklyTaskWeek == week {
+		progress = guild.WeeklyTaskProgress
+	}
+	info := emptyGuildExpansionInfo()
+	info.Capital = proto.Uint32(guild.Capital)
+	info.ThisWeeklyTasks.Progress = proto.Uint32(progress)
+	info.ThisWeeklyTasks.Monday_0Clock = proto.Uint32(week)
+	info.Technologys = technologies
+	info.TechCancelCnt = proto.Uint32(guild.TechCancelCnt)
+	info.ActiveEventCnt = proto.Uint32(guild.ActiveEventCnt)
+	return info, nil
+}
+
 func CommanderGuildData(buffer *[]byte, client *connection.Client) (int, int, error) {
 	response := protobuf.SC_60000{
 		Guild: &protobuf.GUILD_INFO{
-			Base: &protobuf.GUILD_BASE_INFO{
-				Id:              proto.Uint32(0),
-				Policy:          proto.Uint32(0),
-				Faction:         proto.Uint32(0),
-				Name:            proto.String(""),
-				Level:           proto.Uint32(0),
-				Announce:        proto.String(""),
-				Manifesto:       proto.String(""),
-				Exp:             proto.Uint32(0),
-				MemberCount:     proto.Uint32(0),
-				ChangeFactionCd: proto.Uint32(0),
-				KickLeaderCd:    proto.Uint32(0),
-			},
-			GuildEx: &protobuf.GUILD_EXPANSION_INFO{
-				Capital: proto.Uint32(0),
-				ThisWeeklyTasks: &protobuf.WEEKLY_TASK{
-					Id:            proto.Uint32(0),
-					Progress:      proto.Uint32(0),
-					Monday_0Clock: proto.Uint32(0),
-				},
-				BenefitFinishTime:     proto.Uint32(0),
-				RetreatCnt:            proto.Uint32(0),
-				TechCancelCnt:         proto.Uint32(0),
-				LastBenefitFinishTime: proto.Uint32(0),
-				ActiveEventCnt:        proto.Uint32(0),
-			},
+			Base:    emptyGuildBaseInfo(),
+			GuildEx: emptyGuildExpansionInfo(),
 		},
 	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60000, err
+	}
+	if member == nil {
+		return client.SendMessage(60000, &response)
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 60000, err
+	}
+	memberList, logList, err := buildGuildMembersAndLogs(client, guild.ID)
+	if err != nil {
+		return 0, 60000, err
+	}
+	expansion, err := buildGuildExpansionInfo(guild, time.Now())
+	if err != nil {
+		return 0, 60000, err
+	}
+	response.Guild.Base = buildGuildBaseInfo(guild)
+	response.Guild.GuildEx = expansion
+	response.Guild.Member = memberList
+	response.Guild.Log = logList
 	return client.SendMessage(60000, &response)
 }
@@ -1,11 +1,38 @@
 package answer
 
 import (
+	"time"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
 )
 
 func CommanderMissions(buffer *[]byte, client *connection.Client) (int, int, error) {
-	var response protobuf.SC_20001
+	response := protobuf.SC_20001{Info: []*protobuf.TASKINFO{}}
+	if _, err := ensureCommanderTasks(client, time.Now()); err != nil {
+		return 0, 20001, err
+	}
+	tasks, err := orm.ListCommanderTasks(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 20001, err
+	}
+	templates, err := loadCommanderTaskTemplates(tasks)
+	if err != nil {
+		return 0, 20001, err
+	}
+	for _, task := range tasks {
+		template := templates[task.TaskID]
+		if template == nil || template.Type == taskTypeWeekly || task.SubmitTime != 0 {
+			continue
+		}
+		response.Info = append(response.Info, &protobuf.TASKINFO{
+			Id:         proto.Uint32(task.TaskID),
+			Progress:   proto.Uint32(task.Progress),
+			AcceptTime: proto.Uint32(task.AcceptTime),
+			SubmitTime: proto.Uint32(task.SubmitTime),
+		})
+	}
 	return client.SendMessage(20001, &response)
 }
@@ -523,7 +523,10 @@ func TestShipyardDataUsesBlueprintConfig(t *testing.T) {
 func TestTechnologyNationProxyUsesFleetTechConfig(t *testing.T) {
 	client := setupConfigTest(t)
 	seedConfigEntry(t, "ShareCfg/fleet_tech_group.json", "1", `{"id":1}`)
-	seedConfigEntry(t, "ShareCfg/fleet_tech_template.json", "1001", `{"add":[[[1,2],3,4]]}`)
+	seedConfigEntry(t, "ShareCfg/fleet_tech_template.json", "1001", `{"id":1001,"group":1,"level":1,"add":[[[1,2],3,4]]}`)
+	if err := orm.ReplaceFleetTechSets(client.Commander.CommanderID, []orm.FleetTechSet{{ShipType: 1, AttrType: 3, Value: 2}}); err != nil {
+		t.Fatalf("seed tech sets failed: %v", err)
+	}
 
 	buffer := []byte{}
 	if _, _, err := TechnologyNationProxy(&buffer, client); err != nil {
@@ -535,11 +538,11 @@ func TestTechnologyNationProxyUsesFleetTechConfig(t *testing.T) {
 	if len(response.GetTechList()) != 1 || response.GetTechList()[0].GetGroupId() != 1 {
 		t.Fatalf("expected tech list to include group 1")
 	}
-	if len(response.GetTechsetList()) != 2 {
-		t.Fatalf("expected tech set list size 2")
+	if len(response.GetTechsetList()) != 1 {
+		t.Fatalf("expected tech set list size 1")
 	}
-	if response.GetTechsetList()[0].GetAttrType() != 3 || response.GetTechsetList()[0].GetSetValue() != 4 {
-		t.Fatalf("expected tech set to use attr 3 value 4")
+	if response.GetTechsetList()[0].GetAttrType() != 3 || response.GetTechsetList()[0].GetSetValue() != 2 {
+		t.Fatalf("expected tech set to use attr 3 value 2")
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ggmolly/belfast/internal/answer"
 	"github.com/ggmolly/belfast/internal/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/packets"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/ticket"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -324,6 +326,63 @@ func TestJoinServerResolvesServerTicket(t *testing.T) {
 	}
 }
 
+func TestJoinServerVerifiesSignedTicket(t *testing.T) {
+	loadCreatePlayerConfig(t, false, nil, "")
+	if err := orm.CreateCommanderRoot(920003, 920003, "Signed Commander", 0, 0); err != nil {
+		t.Fatalf("failed to seed commander: %v", err)
+	}
+	if err := orm.CreateYostarusMap(900012, 920003); err != nil {
+		t.Fatalf("failed to seed yostarus map: %v", err)
+	}
+	if err := ticket.Default.Configure(config.TicketConfig{ServerID: 1, Keys: []config.TicketKeyConfig{{ID: "test", Secret: "secret"}}}); err != nil {
+		t.Fatalf("failed to configure tickets: %v", err)
+	}
+	defer func() {
+		_ = ticket.Default.Configure(config.TicketConfig{})
+	}()
+	join := func(accountID uint32, serverTicket string) *protobuf.SC_10023 {
+		t.Helper()
+		client := &connection.Client{}
+		payload := &protobuf.CS_10022{
+			AccountId:    proto.Uint32(accountID),
+			ServerTicket: proto.String(serverTicket),
+			Platform:     proto.String("0"),
+			Serverid:     proto.Uint32(1),
+			CheckKey:     proto.String("check"),
+			DeviceId:     proto.String(""),
+		}
+		buf, err := proto.Marshal(payload)
+		if err != nil {
+			t.Fatalf("failed to marshal payload: %v", err)
+		}
+		if _, _, err := answer.JoinServer(&buf, client); err != nil {
+			t.Fatalf("JoinServer failed: %v", err)
+		}
+		response := &protobuf.SC_10023{}
+		decodeResponsePacket(t, client, 10023, response)
+		return response
+	}
+
+	forged := join(920003, fmt.Sprintf("%s:%d", serverTicketPrefix, 900012))
+	if forged.GetResult() != answer.USER_STATUS_INVALID_TICKET || forged.GetUserId() != 0 {
+		t.Fatalf("expected forged ticket to be refused, got %v", forged)
+	}
+	signed, err := ticket.Default.Issue(900012, 1, time.Now())
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %v", err)
+	}
+	if response := join(920004, signed); response.GetResult() != answer.USER_STATUS_INVALID_TICKET {
+		t.Fatalf("expected ticket of another account to be refused, got %v", response)
+	}
+	response := join(0, signed)
+	if response.GetResult() != answer.USER_STATUS_OK || response.GetUserId() != 920003 {
+		t.Fatalf("expected signed ticket to log in, got %v", response)
+	}
+	if _, err := ticket.Default.Verify(response.GetServerTicket(), 1, time.Now()); err != nil {
+		t.Fatalf("expected a signed ticket in the response, got %q (%v)", response.GetServerTicket(), err)
+	}
+}
+
 func TestJoinServerSkipOnboarding(t *testing.T) {
 	loadCreatePlayerConfig(t, true, nil, "")
 	client := &connection.Client{}
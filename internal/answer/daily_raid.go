@@ -0,0 +1,294 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/misc"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const dailyLevelConfigCategory = "ShareCfg/expedition_daily_template.json"
+
+const (
+	dailyRaidResultOK      = uint32(0)
+	dailyRaidResultInvalid = uint32(1)
+	dailyRaidResultLimit   = uint32(2)
+)
+
+// dailyLevelTemplate is a daily raid. weekday lists the ISO days (1 for
+// Monday) it is open on, every day when empty; limit_time is the number of
+// attempts per day shared by all of its stages, and every stage of
+// expedition_and_lv_limit_list is a [expedition_id, min_level] pair.
+type dailyLevelTemplate struct {
+	ID               uint32     `json:"id"`
+	LimitTime        uint32     `json:"limit_time"`
+	Weekday          []uint32   `json:"weekday"`
+	ExpeditionLevels [][]uint32 `json:"expedition_and_lv_limit_list"`
+}
+
+// loadDailyLevelTemplates returns the registry slice shared by every caller,
+// it must not be modified.
+func loadDailyLevelTemplates() ([]dailyLevelTemplate, error) {
+	return gamedata.List[dailyLevelTemplate](gamedata.Default, dailyLevelConfigCategory)
+}
+
+// stageLevel returns the commander level required by stageID, and whether
+// the stage belongs to this raid.
+func (t *dailyLevelTemplate) stageLevel(stageID uint32) (uint32, bool) {
+	for _, entry := range t.ExpeditionLevels {
+		if len(entry) == 0 || entry[0] != stageID {
+			continue
+		}
+		if len(entry) > 1 {
+			return entry[1], true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func (t *dailyLevelTemplate) openOn(now time.Time) bool {
+	if len(t.Weekday) == 0 {
+		return true
+	}
+	weekday := uint32(now.UTC().Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return containsUint32(t.Weekday, weekday)
+}
+
+func findDailyLevelForStage(templates []dailyLevelTemplate, stageID uint32) (*dailyLevelTemplate, uint32, bool) {
+	for i := range templates {
+		if level, ok := templates[i].stageLevel(stageID); ok {
+			return &templates[i], level, true
+		}
+	}
+	return nil, 0, false
+}
+
+// loadDailyRaidState returns the raid counters of the commander, clearing
+// them (and the raid attempts) on the first access of a new day.
+func loadDailyRaidState(client *connection.Client, now time.Time) (*orm.DailyRaidState, error) {
+	state, err := orm.GetOrCreateDailyRaidState(client.Commander.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	if !orm.ApplyDailyRaidDailyReset(state, now) {
+		return state, nil
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.ClearDailyRaidCountsTx(ctx, tx, client.Commander.CommanderID); err != nil {
+			return err
+		}
+		return orm.SaveDailyRaidStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// checkDailyRaidStage resolves the raid of stageID and checks it can be
+// fought count more times today. It returns the raid and a result code.
+func checkDailyRaidStage(client *connection.Client, stageID uint32, count uint32, now time.Time) (*dailyLevelTemplate, uint32, error) {
+	templates, err := loadDailyLevelTemplates()
+	if err != nil {
+		return nil, 0, err
+	}
+	template, level, ok := findDailyLevelForStage(templates, stageID)
+	if !ok || !template.openOn(now) || uint32(client.Commander.Level) < level {
+		return nil, dailyRaidResultInvalid, nil
+	}
+	if _, err := loadDailyRaidState(client, now); err != nil {
+		return nil, 0, err
+	}
+	counts, err := orm.ListDailyRaidCounts(client.Commander.CommanderID)
+	if err != nil {
+		return nil, 0, err
+	}
+	used := uint32(0)
+	for _, entry := range counts {
+		if entry.DailyID == template.ID {
+			used = entry.Count
+		}
+	}
+	if count > template.LimitTime || used > template.LimitTime-count {
+		return nil, dailyRaidResultLimit, nil
+	}
+	return template, dailyRaidResultOK, nil
+}
+
+// rollExpeditionDrops rolls the award_display of an expedition; virtual
+// items resolve to one entry of their drop pool.
+func rollExpeditionDrops(config *expeditionConfig) (map[string]*protobuf.DROPINFO, error) {
+	drops := make(map[string]*protobuf.DROPINFO)
+	if config == nil {
+		return drops, nil
+	}
+	for _, entry := range config.AwardDisplay {
+		if len(entry) < 2 || entry[1] == 0 {
+			continue
+		}
+		dropType, dropID, count, err := resolveChapterAwardDrop(entry[0], entry[1])
+		if err != nil {
+			return nil, err
+		}
+		if len(entry) > 2 && entry[2] > 0 {
+			count *= entry[2]
+		}
+		key := fmt.Sprintf("%d_%d", dropType, dropID)
+		if existing, ok := drops[key]; ok {
+			existing.Number = proto.Uint32(existing.GetNumber() + count)
+			continue
+		}
+		drops[key] = newDropInfo(dropType, dropID, count)
+	}
+	return drops, nil
+}
+
+// finishDailyRaid spends one attempt of the raid owning stageID and grants
+// its drops in the same transaction.
+func finishDailyRaid(client *connection.Client, stageID uint32, now time.Time) ([]*protobuf.DROPINFO, error) {
+	template, result, err := checkDailyRaidStage(client, stageID, 1, now)
+	if err != nil || result != dailyRaidResultOK {
+		return nil, err
+	}
+	config, err := loadExpeditionConfig(stageID)
+	if err != nil {
+		return nil, err
+	}
+	drops, err := rollExpeditionDrops(config)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.AddDailyRaidCountTx(ctx, tx, client.Commander.CommanderID, template.ID, 1, template.LimitTime); err != nil {
+			return err
+		}
+		return applyDropListTx(ctx, tx, client, drops)
+	})
+	if err != nil {
+		if errors.Is(err, orm.ErrDailyRaidLimit) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return dropMapToList(drops), nil
+}
+
+// recordChapterBattleCounts counts today's won battles against elite fleets
+// and on escort chapters.
+func recordChapterBattleCounts(client *connection.Client, update *chapterBattleUpdate, now time.Time) error {
+	if update == nil || update.template == nil {
+		return nil
+	}
+	elite := containsUint32(update.template.EliteExpeditions, update.expeditionID)
+	escortConfig, err := misc.GetEscortConfig()
+	if err != nil {
+		return err
+	}
+	_, escort := escortConfig.Templates[update.template.ID]
+	if !elite && !escort {
+		return nil
+	}
+	state, err := loadDailyRaidState(client, now)
+	if err != nil {
+		return err
+	}
+	if elite {
+		state.EliteExpeditionCount++
+	}
+	if escort {
+		state.EscortExpeditionCount++
+	}
+	return orm.SaveDailyRaidState(state)
+}
+
+// DailyQuickBattle handles CS_40007, sweeping a daily raid stage the
+// commander already cleared cnt times.
+func DailyQuickBattle(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_40007
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 40008, err
+	}
+	response := protobuf.SC_40008{
+		Result:     proto.Uint32(dailyRaidResultInvalid),
+		RewardList: []*protobuf.QUICK_REWARD{},
+	}
+	stageID := payload.GetId()
+	count := payload.GetCnt()
+	if payload.GetSystem() != battleSystemRoutine || count == 0 {
+		return client.SendMessage(40008, &response)
+	}
+	if _, err := orm.GetStageClear(client.Commander.CommanderID, stageID); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(40008, &response)
+		}
+		return 0, 40008, err
+	}
+	now := time.Now()
+	template, result, err := checkDailyRaidStage(client, stageID, count, now)
+	if err != nil {
+		return 0, 40008, err
+	}
+	if result != dailyRaidResultOK {
+		response.Result = proto.Uint32(result)
+		return client.SendMessage(40008, &response)
+	}
+	config, err := loadExpeditionConfig(stageID)
+	if err != nil {
+		return 0, 40008, err
+	}
+	rolls := make([]map[string]*protobuf.DROPINFO, 0, count)
+	for i := uint32(0); i < count; i++ {
+		drops, err := rollExpeditionDrops(config)
+		if err != nil {
+			return 0, 40008, err
+		}
+		rolls = append(rolls, drops)
+	}
+	// the attempts and every roll's drops are granted together
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.AddDailyRaidCountTx(ctx, tx, client.Commander.CommanderID, template.ID, count, template.LimitTime); err != nil {
+			return err
+		}
+		for _, drops := range rolls {
+			if err := applyDropListTx(ctx, tx, client, drops); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, orm.ErrDailyRaidLimit) {
+			response.Result = proto.Uint32(dailyRaidResultLimit)
+			return client.SendMessage(40008, &response)
+		}
+		return 0, 40008, err
+	}
+	for _, drops := range rolls {
+		response.RewardList = append(response.RewardList, &protobuf.QUICK_REWARD{
+			DropList:      dropMapToList(drops),
+			ExtraDropList: []*protobuf.DROPINFO{},
+		})
+	}
+	if err := recordTaskEvent(client, taskEventBattleWin, battleSystemRoutine, count); err != nil {
+		return 0, 40008, err
+	}
+	response.Result = proto.Uint32(dailyRaidResultOK)
+	return client.SendMessage(40008, &response)
+}
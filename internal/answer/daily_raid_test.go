@@ -0,0 +1,123 @@
+package answer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func seedDailyRaidConfig(t *testing.T, weekdays string) {
+	t.Helper()
+	seedConfigEntry(t, dailyLevelConfigCategory, "101", `{"id":101,"limit_time":3,"weekday":`+weekdays+`,"expedition_and_lv_limit_list":[[5001,10],[5002,50]]}`)
+	seedConfigEntry(t, "sharecfgdata/expedition_data_template.json", "5001", `{"id":5001,"exp":0,"level":1,"award_display":[[1,1,50]]}`)
+}
+
+func TestDailyQuickBattleGrantsRewardsWithinQuota(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedDailyRaidConfig(t, "[]")
+	goldBefore := client.Commander.GetResourceCount(1)
+
+	quick := func(stageID uint32, count uint32) *protobuf.SC_40008 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_40007{System: proto.Uint32(battleSystemRoutine), Id: proto.Uint32(stageID), Cnt: proto.Uint32(count)})
+		if _, _, err := DailyQuickBattle(&payload, client); err != nil {
+			t.Fatalf("daily quick battle failed: %v", err)
+		}
+		response := &protobuf.SC_40008{}
+		decodePacketMessage(t, client, 40008, response)
+		client.Buffer.Reset()
+		return response
+	}
+
+	if response := quick(5001, 1); response.GetResult() != dailyRaidResultInvalid {
+		t.Fatalf("expected uncleared stage to be rejected, got %d", response.GetResult())
+	}
+	if _, err := orm.RecordStageClear(client.Commander.CommanderID, 5001, rankScoreS); err != nil {
+		t.Fatalf("record stage clear: %v", err)
+	}
+	if _, err := orm.RecordStageClear(client.Commander.CommanderID, 5002, rankScoreS); err != nil {
+		t.Fatalf("record stage clear: %v", err)
+	}
+	if response := quick(5002, 1); response.GetResult() != dailyRaidResultInvalid {
+		t.Fatalf("expected level locked stage to be rejected, got %d", response.GetResult())
+	}
+
+	if response := quick(5001, 0xFFFFFFFF); response.GetResult() != dailyRaidResultLimit {
+		t.Fatalf("expected a count over the quota to be rejected, got %d", response.GetResult())
+	}
+	response := quick(5001, 2)
+	if response.GetResult() != dailyRaidResultOK {
+		t.Fatalf("expected result 0, got %d", response.GetResult())
+	}
+	if len(response.GetRewardList()) != 2 || len(response.GetRewardList()[0].GetDropList()) != 1 {
+		t.Fatalf("unexpected rewards: %v", response.GetRewardList())
+	}
+	if client.Commander.GetResourceCount(1) != goldBefore+100 {
+		t.Fatalf("expected 100 gold granted, got %d", client.Commander.GetResourceCount(1)-goldBefore)
+	}
+	if response := quick(5001, 2); response.GetResult() != dailyRaidResultLimit {
+		t.Fatalf("expected quota to be exhausted, got %d", response.GetResult())
+	}
+
+	empty := []byte{}
+	if _, _, err := CommanderCommissionsFleet(&empty, client); err != nil {
+		t.Fatalf("CommanderCommissionsFleet failed: %v", err)
+	}
+	fleet := &protobuf.SC_13201{}
+	decodePacketMessage(t, client, 13201, fleet)
+	client.Buffer.Reset()
+	if len(fleet.GetCountList()) != 1 || fleet.GetCountList()[0].GetId() != 101 || fleet.GetCountList()[0].GetCount() != 2 {
+		t.Fatalf("unexpected count list: %v", fleet.GetCountList())
+	}
+	if len(fleet.GetQuickExpeditionList()) != 2 {
+		t.Fatalf("expected both cleared stages to be listed, got %v", fleet.GetQuickExpeditionList())
+	}
+}
+
+func TestBeginStageRejectsClosedDailyRaid(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.BattleSession{})
+	weekday := int(time.Now().UTC().Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	seedDailyRaidConfig(t, fmt.Sprintf("[%d]", weekday%7+1))
+
+	payload := marshalPacketRequest(t, &protobuf.CS_40001{System: proto.Uint32(battleSystemRoutine), Data: proto.Uint32(5001)})
+	if _, _, err := BeginStage(&payload, client); err != nil {
+		t.Fatalf("begin stage failed: %v", err)
+	}
+	response := &protobuf.SC_40002{}
+	decodePacketMessage(t, client, 40002, response)
+	client.Buffer.Reset()
+	if response.GetResult() != dailyRaidResultInvalid {
+		t.Fatalf("expected closed raid to be rejected, got %d", response.GetResult())
+	}
+}
+
+func TestDailyQuickBattleKeepsAttemptsWhenGrantFails(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedDailyRaidConfig(t, "[]")
+	// an unsupported drop type fails the grant after the attempts are counted
+	seedConfigEntry(t, "sharecfgdata/expedition_data_template.json", "5001", `{"id":5001,"exp":0,"level":1,"award_display":[[1,1,50],[99,1,1]]}`)
+	if _, err := orm.RecordStageClear(client.Commander.CommanderID, 5001, rankScoreS); err != nil {
+		t.Fatalf("record stage clear: %v", err)
+	}
+	payload := marshalPacketRequest(t, &protobuf.CS_40007{System: proto.Uint32(battleSystemRoutine), Id: proto.Uint32(5001), Cnt: proto.Uint32(2)})
+	if _, _, err := DailyQuickBattle(&payload, client); err == nil {
+		t.Fatalf("expected the unsupported drop to fail the sweep")
+	}
+	counts, err := orm.ListDailyRaidCounts(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("list daily raid counts: %v", err)
+	}
+	for _, entry := range counts {
+		if entry.DailyID == 101 && entry.Count != 0 {
+			t.Fatalf("expected no attempt to be spent, got %d", entry.Count)
+		}
+	}
+}
@@ -63,6 +63,13 @@ func VisitBackyard19101(buffer *[]byte, client *connection.Client) (int, int, er
 		}
 		return sendVisitBackyardUnavailable(client, targetCommanderID, "target_not_found")
 	}
+	blocked, err := orm.IsBlockedBy(client.Commander.CommanderID, targetCommanderID)
+	if err != nil {
+		return 0, 19102, err
+	}
+	if blocked {
+		return sendVisitBackyardUnavailable(client, targetCommanderID, "blocked")
+	}
 
 	snapshot, err := loadDormSnapshot(targetCommanderID, targetCommander.DormName)
 	if err != nil {
@@ -40,6 +40,10 @@ func AddDormShip19002(buffer *[]byte, client *connection.Client) (int, int, erro
 	commanderID := client.Commander.CommanderID
 	shipID := request.GetShipId()
 	shipType := request.GetType()
+	// recover at the rate the ship had before entering the dorm
+	if _, err := recoverMorale(client); err != nil {
+		return 0, 19003, err
+	}
 	ctx := context.Background()
 	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
 		q := db.DefaultStore.Queries.WithTx(tx)
@@ -95,6 +99,9 @@ func ExitDormShip19004(buffer *[]byte, client *connection.Client) (int, int, err
 	}
 	commanderID := client.Commander.CommanderID
 	shipID := request.GetShipId()
+	if _, err := recoverMorale(client); err != nil {
+		return 0, 19005, err
+	}
 	ctx := context.Background()
 	gained := uint32(0)
 	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
@@ -60,11 +60,15 @@ func EquipToShip(buffer *[]byte, client *connection.Client) (int, int, error) {
 		if current.EquipID == 0 {
 			return client.SendMessage(12007, &response)
 		}
-		if client.Commander.EquipmentBagCount() >= equipBagMax {
+		bagBonus, err := guildTechBonus(client.Commander.CommanderID, guildTechEffectEquipBag)
+		if err != nil {
+			return 0, 12006, err
+		}
+		if client.Commander.EquipmentBagCount() >= equipBagMax+bagBonus {
 			response.Result = proto.Uint32(1)
 			return client.SendMessage(12007, &response)
 		}
-		err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
 			if err := client.Commander.AddOwnedEquipmentTx(ctx, tx, current.EquipID, 1); err != nil {
 				return err
 			}
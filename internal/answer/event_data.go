@@ -1,10 +1,8 @@
 package answer
 
 import (
-	"encoding/json"
-
 	"github.com/ggmolly/belfast/internal/connection"
-	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/gamedata"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
@@ -14,7 +12,7 @@ type gameRoomTemplate struct {
 }
 
 func EventData(buffer *[]byte, client *connection.Client) (int, int, error) {
-	entries, err := orm.ListConfigEntries("ShareCfg/game_room_template.json")
+	rooms, err := gamedata.List[gameRoomTemplate](gamedata.Default, "ShareCfg/game_room_template.json")
 	if err != nil {
 		return 0, 26120, err
 	}
@@ -23,13 +21,9 @@ func EventData(buffer *[]byte, client *connection.Client) (int, int, error) {
 		MonthlyTicket: proto.Uint32(0),
 		PayCoinCount:  proto.Uint32(0),
 		FirstEnter:    proto.Uint32(0),
-		Rooms:         make([]*protobuf.GAMEROOM, 0, len(entries)),
+		Rooms:         make([]*protobuf.GAMEROOM, 0, len(rooms)),
 	}
-	for _, entry := range entries {
-		var room gameRoomTemplate
-		if err := json.Unmarshal(entry.Data, &room); err != nil {
-			return 0, 26120, err
-		}
+	for _, room := range rooms {
 		response.Rooms = append(response.Rooms, &protobuf.GAMEROOM{
 			Roomid:   proto.Uint32(room.ID),
 			MaxScore: proto.Uint32(0),
@@ -143,6 +143,9 @@ WHERE commander_id = $1
 	if err != nil {
 		return 0, 13006, err
 	}
+	if err := recordTaskEvent(client, taskEventCommission, collectionID, 1); err != nil {
+		return 0, 13006, err
+	}
 
 	response.Result = proto.Uint32(0)
 	response.Exp = proto.Uint32(template.Exp)
@@ -0,0 +1,86 @@
+package answer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	exerciseResultOK     = 0
+	exerciseResultFailed = 1
+)
+
+// arenaStanding returns the score and leaderboard position of a commander in
+// the season in progress; commanders that never fought have no position.
+func arenaStanding(commanderID uint32) (uint32, uint32, error) {
+	state, err := orm.GetArenaState(commanderID)
+	if db.IsNotFound(err) {
+		return arena.InitialScore, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	rank, err := arena.Rank(state)
+	if err != nil {
+		return 0, 0, err
+	}
+	return state.Score, rank, nil
+}
+
+func buildArenaTargets(rivalIDs []uint32) ([]*protobuf.TARGETINFO, error) {
+	targets := make([]*protobuf.TARGETINFO, 0, len(rivalIDs))
+	for _, rivalID := range rivalIDs {
+		commander, err := orm.LoadCommanderWithDetails(rivalID)
+		if db.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := buildRivalTargetInfo(&commander)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, info)
+	}
+	return targets, nil
+}
+
+func arenaLeaderboardCommander(entry orm.ArenaLeaderboardEntry) *orm.Commander {
+	return &orm.Commander{
+		CommanderID:         entry.CommanderID,
+		Name:                entry.Name,
+		Level:               entry.Level,
+		DisplayIconID:       entry.DisplayIconID,
+		DisplaySkinID:       entry.DisplaySkinID,
+		SelectedIconFrameID: entry.SelectedIconFrameID,
+		SelectedChatFrameID: entry.SelectedChatFrameID,
+		DisplayIconThemeID:  entry.DisplayIconThemeID,
+	}
+}
+
+// checkExerciseDuel reports whether the commander can start a duel against
+// rivalID.
+func checkExerciseDuel(client *connection.Client, rivalID uint32) (bool, error) {
+	err := arena.CheckDuel(client.Commander.CommanderID, rivalID, time.Now())
+	if errors.Is(err, arena.ErrNoFightCount) || errors.Is(err, arena.ErrNotRival) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func finishExerciseDuel(client *connection.Client, rivalID uint32, won bool) error {
+	_, err := arena.FinishDuel(client.Commander.CommanderID, rivalID, won, time.Now())
+	if errors.Is(err, arena.ErrNoFightCount) || errors.Is(err, arena.ErrNotRival) {
+		// the fight count regenerated away or the rivals were replaced
+		// meanwhile, the duel doesn't count
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func TestExerciseEnemiesListsRivalsWithSavedFleets(t *testing.T) {
+	client := setupExerciseTest(t)
+	seedExerciseRival(t, 2, "Rival")
+	if err := orm.CreateCommanderRoot(3, 3, "No Fleet", 0, 0); err != nil {
+		t.Fatalf("seed commander: %v", err)
+	}
+
+	buf := []byte{}
+	if _, _, err := ExerciseEnemies(&buf, client); err != nil {
+		t.Fatalf("ExerciseEnemies failed: %v", err)
+	}
+	var resp protobuf.SC_18002
+	decodePacketMessage(t, client, 18002, &resp)
+	if resp.GetFightCount() != arena.MaxFightCount || resp.GetFightCountResetTime() == 0 {
+		t.Fatalf("expected a full fight count, got %d", resp.GetFightCount())
+	}
+	if resp.GetRank() != 1 {
+		t.Fatalf("expected rank 1, got %d", resp.GetRank())
+	}
+	if len(resp.GetTargetList()) != 1 || resp.GetTargetList()[0].GetId() != 2 {
+		t.Fatalf("expected only the rival with a saved fleet, got %v", resp.GetTargetList())
+	}
+}
+
+func TestExerciseDuelMovesScoresAndSpendsFight(t *testing.T) {
+	client := setupExerciseTest(t)
+	seedExerciseRival(t, 2, "Rival")
+	if _, _, err := arena.LoadState(2, time.Now()); err != nil {
+		t.Fatalf("load rival state: %v", err)
+	}
+	rival, err := orm.GetArenaState(2)
+	if err != nil {
+		t.Fatalf("get rival state: %v", err)
+	}
+	rival.Score = 100
+	if err := orm.SaveArenaState(rival); err != nil {
+		t.Fatalf("save rival state: %v", err)
+	}
+
+	ok, err := checkExerciseDuel(client, 2)
+	if err != nil || !ok {
+		t.Fatalf("expected duel to be allowed, got %v %v", ok, err)
+	}
+	if ok, err := checkExerciseDuel(client, 99); err != nil || ok {
+		t.Fatalf("expected duel against a stranger to be refused")
+	}
+	if err := finishExerciseDuel(client, 2, true); err != nil {
+		t.Fatalf("finish duel: %v", err)
+	}
+
+	state, err := orm.GetArenaState(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	gain := arena.ScoreGain(arena.InitialScore, 100)
+	if state.Score != gain || state.FightCount != arena.MaxFightCount-1 || state.Wins != 1 {
+		t.Fatalf("unexpected state after a win %+v", state)
+	}
+	rival, err = orm.GetArenaState(2)
+	if err != nil {
+		t.Fatalf("get rival state: %v", err)
+	}
+	if rival.Score != 100-gain/2 {
+		t.Fatalf("expected rival to lose %d points, got %d", gain/2, rival.Score)
+	}
+}
+
+func TestArenaSeasonSettlementPaysMerit(t *testing.T) {
+	client := setupExerciseTest(t)
+	execAnswerTestSQLT(t, "INSERT INTO resources (id, item_id, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING", int64(arena.MeritResourceID), int64(0), "Merit")
+	state, season, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	state.Score = 700
+	if err := orm.SaveArenaState(state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+
+	settlement, err := arena.SettleSeason(time.Now())
+	if err != nil {
+		t.Fatalf("settle season: %v", err)
+	}
+	if settlement.SeasonID != season.SeasonID || len(settlement.Payouts) != 1 || settlement.Payouts[0].Merit != arena.SeasonMerit(700) {
+		t.Fatalf("unexpected settlement %+v", settlement)
+	}
+	if err := client.Commander.Load(); err != nil {
+		t.Fatalf("reload commander: %v", err)
+	}
+	if client.Commander.GetResourceCount(arena.MeritResourceID) != arena.SeasonMerit(700) {
+		t.Fatalf("expected merit to be paid")
+	}
+	state, err = orm.GetArenaState(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if state.SeasonID != settlement.NextSeasonID || state.Score != arena.InitialScore {
+		t.Fatalf("expected score reset in the next season, got %+v", state)
+	}
+}
@@ -2,7 +2,9 @@ package answer
 
 import (
 	"errors"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/arena"
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/db"
 	"github.com/ggmolly/belfast/internal/orm"
@@ -16,14 +18,27 @@ func ExerciseEnemies(buffer *[]byte, client *connection.Client) (int, int, error
 	if err != nil {
 		return 0, 18002, err
 	}
+	state, _, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		return 0, 18002, err
+	}
+	rank, err := arena.Rank(state)
+	if err != nil {
+		return 0, 18002, err
+	}
+	targets, err := buildArenaTargets(orm.ToUint32List(state.RivalIDs))
+	if err != nil {
+		return 0, 18002, err
+	}
 	response := protobuf.SC_18002{
-		Score:               proto.Uint32(0),
-		Rank:                proto.Uint32(0),
-		FightCount:          proto.Uint32(0),
-		FightCountResetTime: proto.Uint32(0),
-		FlashTargetCount:    proto.Uint32(0),
+		Score:               proto.Uint32(state.Score),
+		Rank:                proto.Uint32(rank),
+		FightCount:          proto.Uint32(state.FightCount),
+		FightCountResetTime: proto.Uint32(state.FightCountResetTime),
+		FlashTargetCount:    proto.Uint32(state.FlashTargetCount),
 		VanguardShipIdList:  vanguardIDs,
 		MainShipIdList:      mainIDs,
+		TargetList:          targets,
 	}
 	return client.SendMessage(18002, &response)
 }
@@ -2,12 +2,17 @@ package answer
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/arena"
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+const exercisePowerRankCount = 100
+
 func ExercisePowerRankList(buffer *[]byte, client *connection.Client) (int, int, error) {
 	var payload protobuf.CS_18006
 	if err := proto.Unmarshal(*buffer, &payload); err != nil {
@@ -17,26 +22,22 @@ func ExercisePowerRankList(buffer *[]byte, client *connection.Client) (int, int,
 		return 0, 18007, fmt.Errorf("CS_18006 missing required field: type")
 	}
 
-	const rankCount = 5
-	ranks := make([]*protobuf.ARENARANK, 0, rankCount)
-
-	if client.Commander != nil {
-		ranks = append(ranks, &protobuf.ARENARANK{
-			Id:    proto.Uint32(client.Commander.CommanderID),
-			Level: proto.Uint32(uint32(client.Commander.Level)),
-			Name:  proto.String(client.Commander.Name),
-			Score: proto.Uint32(0),
-		})
+	_, season, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		return 0, 18007, err
 	}
-
-	for i := len(ranks); i < rankCount; i++ {
-		id := uint32(91000000 + i + 1)
-		name := fmt.Sprintf("Commander #%d", i+1)
+	entries, err := orm.ListArenaLeaderboard(season.SeasonID, 0, exercisePowerRankCount)
+	if err != nil {
+		return 0, 18007, err
+	}
+	ranks := make([]*protobuf.ARENARANK, 0, len(entries))
+	for _, entry := range entries {
 		ranks = append(ranks, &protobuf.ARENARANK{
-			Id:    proto.Uint32(id),
-			Level: proto.Uint32(1),
-			Name:  proto.String(name),
-			Score: proto.Uint32(0),
+			Id:      proto.Uint32(entry.CommanderID),
+			Level:   proto.Uint32(uint32(entry.Level)),
+			Name:    proto.String(entry.Name),
+			Score:   proto.Uint32(entry.Score),
+			Display: billboardRankDisplay(arenaLeaderboardCommander(entry)),
 		})
 	}
 
@@ -21,8 +21,8 @@ func TestExercisePowerRankList_Success_ReturnsSC18007(t *testing.T) {
 
 	var resp protobuf.SC_18007
 	decodePacketMessage(t, client, 18007, &resp)
-	if len(resp.GetArenaRankLsit()) != 5 {
-		t.Fatalf("expected 5 rank entries, got %d", len(resp.GetArenaRankLsit()))
+	if len(resp.GetArenaRankLsit()) != 1 {
+		t.Fatalf("expected 1 rank entry, got %d", len(resp.GetArenaRankLsit()))
 	}
 	first := resp.GetArenaRankLsit()[0]
 	if first.GetId() != client.Commander.CommanderID {
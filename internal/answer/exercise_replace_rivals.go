@@ -2,8 +2,11 @@ package answer
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/arena"
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
@@ -17,25 +20,20 @@ func ExerciseReplaceRivals(buffer *[]byte, client *connection.Client) (int, int,
 		return 0, 18004, fmt.Errorf("CS_18003 missing required field: type")
 	}
 
-	const rivalCount = 5
-	targets := make([]*protobuf.TARGETINFO, 0, rivalCount)
-	for i := 0; i < rivalCount; i++ {
-		id := uint32(90000000 + i + 1)
-		level := uint32(1)
-		name := fmt.Sprintf("Rival #%d", i+1)
-		score := uint32(0)
-		rank := uint32(i + 1)
-		targets = append(targets, &protobuf.TARGETINFO{
-			Id:    proto.Uint32(id),
-			Level: proto.Uint32(level),
-			Name:  proto.String(name),
-			Score: proto.Uint32(score),
-			Rank:  proto.Uint32(rank),
-		})
+	state, ok, err := arena.ReplaceRivals(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		return 0, 18004, err
+	}
+	if !ok {
+		response := protobuf.SC_18004{Result: proto.Uint32(exerciseResultFailed)}
+		return client.SendMessage(18004, &response)
+	}
+	targets, err := buildArenaTargets(orm.ToUint32List(state.RivalIDs))
+	if err != nil {
+		return 0, 18004, err
 	}
-
 	response := protobuf.SC_18004{
-		Result:     proto.Uint32(0),
+		Result:     proto.Uint32(exerciseResultOK),
 		TargetList: targets,
 	}
 	return client.SendMessage(18004, &response)
@@ -2,13 +2,17 @@ package answer
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
 func TestExerciseReplaceRivals_Success_ReturnsSC18004(t *testing.T) {
-	client := setupConfigTest(t)
+	client := setupExerciseTest(t)
+	seedExerciseRival(t, 2, "Rival")
 
 	payload := protobuf.CS_18003{Type: proto.Uint32(0)}
 	buf, err := proto.Marshal(&payload)
@@ -24,18 +28,42 @@ func TestExerciseReplaceRivals_Success_ReturnsSC18004(t *testing.T) {
 	if resp.GetResult() != 0 {
 		t.Fatalf("expected result 0, got %d", resp.GetResult())
 	}
-	if len(resp.GetTargetList()) != 5 {
-		t.Fatalf("expected 5 rivals, got %d", len(resp.GetTargetList()))
+	if len(resp.GetTargetList()) != 1 {
+		t.Fatalf("expected 1 rival, got %d", len(resp.GetTargetList()))
 	}
-	for _, rival := range resp.GetTargetList() {
-		if rival.GetId() == 0 || rival.GetLevel() == 0 || rival.GetName() == "" {
-			t.Fatalf("expected rival required fields to be set")
-		}
+	rival := resp.GetTargetList()[0]
+	if rival.GetId() != 2 || rival.GetLevel() == 0 || rival.GetName() != "Rival" {
+		t.Fatalf("expected rival required fields to be set, got %+v", rival)
+	}
+}
+
+func TestExerciseReplaceRivals_LimitReached_ReturnsFailure(t *testing.T) {
+	client := setupExerciseTest(t)
+	state, _, err := arena.LoadState(client.Commander.CommanderID, time.Now())
+	if err != nil {
+		t.Fatalf("load arena state: %v", err)
+	}
+	state.FlashTargetCount = arena.MaxFlashTargetCount
+	if err := orm.SaveArenaState(state); err != nil {
+		t.Fatalf("save arena state: %v", err)
+	}
+
+	buf, err := proto.Marshal(&protobuf.CS_18003{Type: proto.Uint32(0)})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	if _, _, err := ExerciseReplaceRivals(&buf, client); err != nil {
+		t.Fatalf("ExerciseReplaceRivals failed: %v", err)
+	}
+	var resp protobuf.SC_18004
+	decodePacketMessage(t, client, 18004, &resp)
+	if resp.GetResult() != exerciseResultFailed {
+		t.Fatalf("expected result %d, got %d", exerciseResultFailed, resp.GetResult())
 	}
 }
 
 func TestExerciseReplaceRivals_InvalidPayload_ReturnsError(t *testing.T) {
-	client := setupConfigTest(t)
+	client := setupExerciseTest(t)
 	buf := []byte{0xff}
 	if _, _, err := ExerciseReplaceRivals(&buf, client); err == nil {
 		t.Fatalf("expected error")
@@ -15,6 +15,8 @@ func setupExerciseTest(t *testing.T) *connection.Client {
 	os.Setenv("MODE", "test")
 	orm.InitDatabase()
 	clearTable(t, &orm.ExerciseFleet{})
+	clearTable(t, &orm.ArenaState{})
+	clearTable(t, &orm.ArenaSeason{})
 	clearTable(t, &orm.Fleet{})
 	clearTable(t, &orm.OwnedShip{})
 	clearTable(t, &orm.Commander{})
@@ -49,6 +51,16 @@ func setupExerciseTest(t *testing.T) *connection.Client {
 	return &connection.Client{Commander: &commander}
 }
 
+func seedExerciseRival(t *testing.T, commanderID uint32, name string) {
+	t.Helper()
+	if err := orm.CreateCommanderRoot(commanderID, commanderID, name, 0, 0); err != nil {
+		t.Fatalf("seed rival: %v", err)
+	}
+	if err := orm.UpsertExerciseFleet(commanderID, []uint32{commanderID * 10}, []uint32{commanderID*10 + 1}); err != nil {
+		t.Fatalf("seed rival fleet: %v", err)
+	}
+}
+
 func decodePacketMessage(t *testing.T, client *connection.Client, expectedPacketID int, resp proto.Message) {
 	t.Helper()
 	buffer := client.Buffer.Bytes()
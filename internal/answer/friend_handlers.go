@@ -0,0 +1,456 @@
+package answer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	friendResultSuccess      = uint32(0)
+	friendResultFailed       = uint32(1)
+	friendResultNotFound     = uint32(2)
+	friendResultFriendLimit  = uint32(3)
+	friendResultTargetLimit  = uint32(4)
+	friendResultAlreadyAdded = uint32(5)
+	friendResultBlocked      = uint32(6)
+
+	friendMaxCount          = 100
+	friendRecommendCount    = 10
+	friendRequestMaxContent = 50
+
+	friendSearchTypeID = uint32(1)
+)
+
+func friendOnlineState(client *connection.Client, commanderID uint32) uint32 {
+	if isCommanderOnline(client, commanderID) {
+		return 1
+	}
+	return 0
+}
+
+func buildFriendDisplay(profile *orm.FriendProfile) *protobuf.DISPLAYINFO {
+	return &protobuf.DISPLAYINFO{
+		Icon:          proto.Uint32(profile.DisplayIconID),
+		Skin:          proto.Uint32(profile.DisplaySkinID),
+		IconFrame:     proto.Uint32(profile.SelectedIconFrameID),
+		ChatFrame:     proto.Uint32(profile.SelectedChatFrameID),
+		IconTheme:     proto.Uint32(profile.DisplayIconThemeID),
+		MarryFlag:     proto.Uint32(0),
+		TransformFlag: proto.Uint32(0),
+	}
+}
+
+func buildFriendInfo(client *connection.Client, profile *orm.FriendProfile) *protobuf.FRIEND_INFO {
+	return &protobuf.FRIEND_INFO{
+		Id:            proto.Uint32(profile.CommanderID),
+		Name:          proto.String(profile.Name),
+		Lv:            proto.Uint32(profile.Level),
+		Adv:           proto.String(profile.Manifesto),
+		Online:        proto.Uint32(friendOnlineState(client, profile.CommanderID)),
+		PreOnlineTime: proto.Uint32(uint32(profile.LastLogin.Unix())),
+		Display:       buildFriendDisplay(profile),
+	}
+}
+
+func buildFriendPlayerInfo(profile *orm.FriendProfile) *protobuf.PLAYER_INFO_P50 {
+	return &protobuf.PLAYER_INFO_P50{
+		Id:      proto.Uint32(profile.CommanderID),
+		Name:    proto.String(profile.Name),
+		Lv:      proto.Uint32(profile.Level),
+		Display: buildFriendDisplay(profile),
+	}
+}
+
+func buildFriendRequestMessage(profile *orm.FriendProfile) *protobuf.MSG_INFO_P50 {
+	return &protobuf.MSG_INFO_P50{
+		Timestamp: proto.Uint32(uint32(profile.Since.Unix())),
+		Player:    buildFriendPlayerInfo(profile),
+		Content:   proto.String(profile.Content),
+	}
+}
+
+func buildFriendDetailInfo(client *connection.Client, profile *orm.FriendProfile) (*protobuf.DETAIL_INFO, error) {
+	shipCount, err := orm.CountOwnedShipsByOwner(profile.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	medalIDs, err := orm.ListCommanderMedalDisplay(profile.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	return &protobuf.DETAIL_INFO{
+		Id:                 proto.Uint32(profile.CommanderID),
+		Name:               proto.String(profile.Name),
+		Title:              proto.Uint32(0),
+		Lv:                 proto.Uint32(profile.Level),
+		ShipCount:          proto.Uint32(shipCount),
+		CollectionCount:    proto.Uint32(0),
+		PvpAttackCount:     proto.Uint32(0),
+		PvpWinCount:        proto.Uint32(0),
+		CollectAttackCount: proto.Uint32(0),
+		AttackCount:        proto.Uint32(0),
+		WinCount:           proto.Uint32(0),
+		Adv:                proto.String(profile.Manifesto),
+		Online:             proto.Uint32(friendOnlineState(client, profile.CommanderID)),
+		PreOnlineTime:      proto.Uint32(uint32(profile.LastLogin.Unix())),
+		Score:              proto.Uint32(0),
+		MedalId:            medalIDs,
+		Display:            buildFriendDisplay(profile),
+	}, nil
+}
+
+// resolveFriendSearchTarget looks a commander up by id or by exact name,
+// depending on the search type sent by the client.
+func resolveFriendSearchTarget(searchType uint32, keyword string) (*orm.FriendProfile, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, db.ErrNotFound
+	}
+	if searchType == friendSearchTypeID {
+		id, err := strconv.ParseUint(keyword, 10, 32)
+		if err != nil {
+			return nil, db.ErrNotFound
+		}
+		return orm.GetFriendProfile(uint32(id))
+	}
+	if id, err := strconv.ParseUint(keyword, 10, 32); err == nil {
+		profile, err := orm.GetFriendProfile(uint32(id))
+		if err == nil {
+			return profile, nil
+		}
+		if !db.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return orm.FindFriendProfileByName(keyword)
+}
+
+// FriendSearch handles CS_50001.
+func FriendSearch(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50001
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50002, err
+	}
+	profile, err := resolveFriendSearchTarget(payload.GetType(), payload.GetKeyword())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50002, &protobuf.SC_50002{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50002, err
+	}
+	if profile.CommanderID == client.Commander.CommanderID {
+		return client.SendMessage(50002, &protobuf.SC_50002{Result: proto.Uint32(friendResultNotFound)})
+	}
+	detail, err := buildFriendDetailInfo(client, profile)
+	if err != nil {
+		return 0, 50002, err
+	}
+	return client.SendMessage(50002, &protobuf.SC_50002{
+		Result: proto.Uint32(friendResultSuccess),
+		Player: detail,
+	})
+}
+
+// FriendSendRequest handles CS_50003 and notifies the target with SC_50005 when online.
+func FriendSendRequest(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50003
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50004, err
+	}
+	senderID := client.Commander.CommanderID
+	targetID := payload.GetId()
+	if targetID == 0 || targetID == senderID {
+		return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultFailed)})
+	}
+	if _, err := orm.GetFriendProfile(targetID); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50004, err
+	}
+	alreadyFriends, err := orm.AreFriends(senderID, targetID)
+	if err != nil {
+		return 0, 50004, err
+	}
+	if alreadyFriends {
+		return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultAlreadyAdded)})
+	}
+	blocked, err := orm.IsBlockedBy(senderID, targetID)
+	if err != nil {
+		return 0, 50004, err
+	}
+	blocking, err := orm.IsBlockedBy(targetID, senderID)
+	if err != nil {
+		return 0, 50004, err
+	}
+	if blocked || blocking {
+		return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultBlocked)})
+	}
+	count, err := orm.CountCommanderFriends(senderID)
+	if err != nil {
+		return 0, 50004, err
+	}
+	if count >= friendMaxCount {
+		return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultFriendLimit)})
+	}
+	content := payload.GetContent()
+	if utf8.RuneCountInString(content) > friendRequestMaxContent {
+		content = string([]rune(content)[:friendRequestMaxContent])
+	}
+	request, err := orm.CreateFriendRequest(senderID, targetID, content)
+	if err != nil {
+		if errors.Is(err, orm.ErrFriendRequestExists) {
+			return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultAlreadyAdded)})
+		}
+		return 0, 50004, err
+	}
+	if isCommanderOnline(client, targetID) {
+		senderProfile, err := orm.GetFriendProfile(senderID)
+		if err != nil {
+			return 0, 50004, err
+		}
+		senderProfile.Since = request.CreatedAt
+		senderProfile.Content = request.Content
+		pushToCommander(client, targetID, 50005, &protobuf.SC_50005{Msg: buildFriendRequestMessage(senderProfile)})
+	}
+	return client.SendMessage(50004, &protobuf.SC_50004{Result: proto.Uint32(friendResultSuccess)})
+}
+
+// FriendAcceptRequest handles CS_50006 and pushes SC_50008 to both sides.
+func FriendAcceptRequest(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50006
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50007, err
+	}
+	receiverID := client.Commander.CommanderID
+	senderID := payload.GetId()
+	exists, err := orm.FriendRequestExists(senderID, receiverID)
+	if err != nil {
+		return 0, 50007, err
+	}
+	if !exists {
+		return client.SendMessage(50007, &protobuf.SC_50007{Result: proto.Uint32(friendResultNotFound)})
+	}
+	receiverCount, err := orm.CountCommanderFriends(receiverID)
+	if err != nil {
+		return 0, 50007, err
+	}
+	if receiverCount >= friendMaxCount {
+		return client.SendMessage(50007, &protobuf.SC_50007{Result: proto.Uint32(friendResultFriendLimit)})
+	}
+	senderCount, err := orm.CountCommanderFriends(senderID)
+	if err != nil {
+		return 0, 50007, err
+	}
+	if senderCount >= friendMaxCount {
+		return client.SendMessage(50007, &protobuf.SC_50007{Result: proto.Uint32(friendResultTargetLimit)})
+	}
+	if err := orm.CreateFriendship(receiverID, senderID); err != nil {
+		if errors.Is(err, orm.ErrAlreadyFriends) {
+			return client.SendMessage(50007, &protobuf.SC_50007{Result: proto.Uint32(friendResultAlreadyAdded)})
+		}
+		return 0, 50007, err
+	}
+	senderProfile, err := orm.GetFriendProfile(senderID)
+	if err != nil {
+		return 0, 50007, err
+	}
+	if _, _, err := client.SendMessage(50007, &protobuf.SC_50007{Result: proto.Uint32(friendResultSuccess)}); err != nil {
+		return 0, 50007, err
+	}
+	if isCommanderOnline(client, senderID) {
+		receiverProfile, err := orm.GetFriendProfile(receiverID)
+		if err != nil {
+			return 0, 50007, err
+		}
+		pushToCommander(client, senderID, 50008, &protobuf.SC_50008{Player: buildFriendInfo(client, receiverProfile)})
+	}
+	return client.SendMessage(50008, &protobuf.SC_50008{Player: buildFriendInfo(client, senderProfile)})
+}
+
+// FriendRejectRequest handles CS_50009. An id of 0 rejects every pending request.
+func FriendRejectRequest(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50009
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50010, err
+	}
+	receiverID := client.Commander.CommanderID
+	if payload.GetId() == 0 {
+		if _, err := orm.DeleteIncomingFriendRequests(receiverID); err != nil {
+			return 0, 50010, err
+		}
+		return client.SendMessage(50010, &protobuf.SC_50010{Result: proto.Uint32(friendResultSuccess)})
+	}
+	if err := orm.DeleteFriendRequest(payload.GetId(), receiverID); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50010, &protobuf.SC_50010{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50010, err
+	}
+	return client.SendMessage(50010, &protobuf.SC_50010{Result: proto.Uint32(friendResultSuccess)})
+}
+
+// FriendDelete handles CS_50011 and notifies the removed friend with SC_50013.
+func FriendDelete(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50011
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50012, err
+	}
+	commanderID := client.Commander.CommanderID
+	friendID := payload.GetId()
+	if err := orm.DeleteFriendship(commanderID, friendID); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50012, &protobuf.SC_50012{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50012, err
+	}
+	pushToCommander(client, friendID, 50013, &protobuf.SC_50013{Id: proto.Uint32(commanderID)})
+	return client.SendMessage(50012, &protobuf.SC_50012{Result: proto.Uint32(friendResultSuccess)})
+}
+
+// FriendRecommendList handles CS_50014.
+func FriendRecommendList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50014
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50015, err
+	}
+	candidates, err := orm.ListFriendCandidates(client.Commander.CommanderID, friendRecommendCount)
+	if err != nil {
+		return 0, 50015, err
+	}
+	players := make([]*protobuf.PLAYER_INFO_P50, 0, len(candidates))
+	for i := range candidates {
+		players = append(players, buildFriendPlayerInfo(&candidates[i]))
+	}
+	return client.SendMessage(50015, &protobuf.SC_50015{PlayerList: players})
+}
+
+// FriendBlackList handles CS_50016.
+func FriendBlackList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50016
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50017, err
+	}
+	blocks, err := orm.ListCommanderBlocks(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 50017, err
+	}
+	players := make([]*protobuf.PLAYER_INFO_P50, 0, len(blocks))
+	for i := range blocks {
+		players = append(players, buildFriendPlayerInfo(&blocks[i]))
+	}
+	return client.SendMessage(50017, &protobuf.SC_50017{BlackList: players})
+}
+
+// FriendRefreshInfo handles CS_50018, returning up-to-date info (including
+// online state) for the requested friends only.
+func FriendRefreshInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50018
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50019, err
+	}
+	friends, err := orm.ListCommanderFriends(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 50019, err
+	}
+	requested := make(map[uint32]struct{}, len(payload.GetUserIdList()))
+	for _, id := range payload.GetUserIdList() {
+		requested[id] = struct{}{}
+	}
+	users := make([]*protobuf.FRIEND_INFO, 0, len(requested))
+	for i := range friends {
+		if _, ok := requested[friends[i].CommanderID]; !ok {
+			continue
+		}
+		users = append(users, buildFriendInfo(client, &friends[i]))
+	}
+	return client.SendMessage(50019, &protobuf.SC_50019{UserList: users})
+}
+
+// FriendBlock handles CS_50107 (add to blacklist).
+func FriendBlock(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50107
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50108, err
+	}
+	commanderID := client.Commander.CommanderID
+	targetID := payload.GetId()
+	if targetID == 0 || targetID == commanderID {
+		return client.SendMessage(50108, &protobuf.SC_50108{Result: proto.Uint32(friendResultFailed)})
+	}
+	if _, err := orm.GetFriendProfile(targetID); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50108, &protobuf.SC_50108{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50108, err
+	}
+	wasFriend, err := orm.AreFriends(commanderID, targetID)
+	if err != nil {
+		return 0, 50108, err
+	}
+	if err := orm.BlockCommander(commanderID, targetID); err != nil {
+		return 0, 50108, err
+	}
+	if wasFriend {
+		pushToCommander(client, targetID, 50013, &protobuf.SC_50013{Id: proto.Uint32(commanderID)})
+	}
+	return client.SendMessage(50108, &protobuf.SC_50108{Result: proto.Uint32(friendResultSuccess)})
+}
+
+// FriendUnblock handles CS_50109 (remove from blacklist).
+func FriendUnblock(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50109
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50110, err
+	}
+	if err := orm.UnblockCommander(client.Commander.CommanderID, payload.GetId()); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50110, &protobuf.SC_50110{Result: proto.Uint32(friendResultNotFound)})
+		}
+		return 0, 50110, err
+	}
+	return client.SendMessage(50110, &protobuf.SC_50110{Result: proto.Uint32(friendResultSuccess)})
+}
+
+// FriendVisit handles CS_50113, returning the card of a friend before the
+// client opens their backyard.
+func FriendVisit(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_50113
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 50114, err
+	}
+	targetID := payload.GetUserId()
+	response := protobuf.SC_50114{
+		Result: proto.Uint32(friendResultNotFound),
+		Player: &protobuf.PLAYER_INFO_P50{
+			Id:   proto.Uint32(targetID),
+			Name: proto.String(""),
+			Lv:   proto.Uint32(0),
+		},
+	}
+	friends, err := orm.AreFriends(client.Commander.CommanderID, targetID)
+	if err != nil {
+		return 0, 50114, err
+	}
+	if !friends {
+		return client.SendMessage(50114, &response)
+	}
+	profile, err := orm.GetFriendProfile(targetID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(50114, &response)
+		}
+		return 0, 50114, err
+	}
+	response.Result = proto.Uint32(friendResultSuccess)
+	response.Player = buildFriendPlayerInfo(profile)
+	return client.SendMessage(50114, &response)
+}
@@ -0,0 +1,149 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func setupFriendTestClients(t *testing.T) (*connection.Client, *connection.Client) {
+	t.Helper()
+	client := setupPlayerUpdateTest(t)
+	otherID := client.Commander.CommanderID + 100000
+	execAnswerTestSQLT(t, "DELETE FROM commanders WHERE commander_id = $1", int64(otherID))
+	if err := orm.CreateCommanderRoot(otherID, 2, "Friend Tester", 0, 0); err != nil {
+		t.Fatalf("create friend commander: %v", err)
+	}
+	other := orm.Commander{CommanderID: otherID}
+	if err := other.Load(); err != nil {
+		t.Fatalf("load friend commander: %v", err)
+	}
+	t.Cleanup(func() {
+		execAnswerTestSQLT(t, "DELETE FROM commanders WHERE commander_id = $1", int64(otherID))
+	})
+	return client, &connection.Client{Commander: &other}
+}
+
+func TestFriendRequestAcceptFlow(t *testing.T) {
+	sender, receiver := setupFriendTestClients(t)
+	receiverID := receiver.Commander.CommanderID
+
+	payload := marshalPacketRequest(t, &protobuf.CS_50003{Id: proto.Uint32(receiverID), Content: proto.String("hello")})
+	if _, _, err := FriendSendRequest(&payload, sender); err != nil {
+		t.Fatalf("FriendSendRequest failed: %v", err)
+	}
+	sendResponse := &protobuf.SC_50004{}
+	decodePacketMessage(t, sender, 50004, sendResponse)
+	sender.Buffer.Reset()
+	if sendResponse.GetResult() != friendResultSuccess {
+		t.Fatalf("expected result 0, got %d", sendResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_50003{Id: proto.Uint32(receiverID), Content: proto.String("again")})
+	if _, _, err := FriendSendRequest(&payload, sender); err != nil {
+		t.Fatalf("FriendSendRequest failed: %v", err)
+	}
+	decodePacketMessage(t, sender, 50004, sendResponse)
+	sender.Buffer.Reset()
+	if sendResponse.GetResult() != friendResultAlreadyAdded {
+		t.Fatalf("expected duplicate request result, got %d", sendResponse.GetResult())
+	}
+
+	empty := []byte{}
+	if _, _, err := CommanderFriendList(&empty, receiver); err != nil {
+		t.Fatalf("CommanderFriendList failed: %v", err)
+	}
+	listResponse := &protobuf.SC_50000{}
+	decodePacketMessage(t, receiver, 50000, listResponse)
+	receiver.Buffer.Reset()
+	if len(listResponse.GetRequestList()) != 1 || listResponse.GetRequestList()[0].GetContent() != "hello" {
+		t.Fatalf("unexpected request list: %+v", listResponse.GetRequestList())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_50006{Id: proto.Uint32(sender.Commander.CommanderID)})
+	if _, _, err := FriendAcceptRequest(&payload, receiver); err != nil {
+		t.Fatalf("FriendAcceptRequest failed: %v", err)
+	}
+	acceptResponse := &protobuf.SC_50007{}
+	decodePacketMessage(t, receiver, 50007, acceptResponse)
+	receiver.Buffer.Reset()
+	if acceptResponse.GetResult() != friendResultSuccess {
+		t.Fatalf("expected result 0, got %d", acceptResponse.GetResult())
+	}
+	friends, err := orm.AreFriends(sender.Commander.CommanderID, receiverID)
+	if err != nil {
+		t.Fatalf("check friendship: %v", err)
+	}
+	if !friends {
+		t.Fatalf("expected commanders to be friends")
+	}
+	pending, err := orm.FriendRequestExists(sender.Commander.CommanderID, receiverID)
+	if err != nil {
+		t.Fatalf("check request: %v", err)
+	}
+	if pending {
+		t.Fatalf("expected request to be consumed")
+	}
+}
+
+func TestFriendDeleteAndBlock(t *testing.T) {
+	client, other := setupFriendTestClients(t)
+	otherID := other.Commander.CommanderID
+	if err := orm.CreateFriendship(client.Commander.CommanderID, otherID); err != nil {
+		t.Fatalf("create friendship: %v", err)
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_50107{Id: proto.Uint32(otherID)})
+	if _, _, err := FriendBlock(&payload, client); err != nil {
+		t.Fatalf("FriendBlock failed: %v", err)
+	}
+	blockResponse := &protobuf.SC_50108{}
+	decodePacketMessage(t, client, 50108, blockResponse)
+	client.Buffer.Reset()
+	if blockResponse.GetResult() != friendResultSuccess {
+		t.Fatalf("expected result 0, got %d", blockResponse.GetResult())
+	}
+	friends, err := orm.AreFriends(client.Commander.CommanderID, otherID)
+	if err != nil {
+		t.Fatalf("check friendship: %v", err)
+	}
+	if friends {
+		t.Fatalf("expected block to remove friendship")
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_50003{Id: proto.Uint32(client.Commander.CommanderID), Content: proto.String("")})
+	if _, _, err := FriendSendRequest(&payload, other); err != nil {
+		t.Fatalf("FriendSendRequest failed: %v", err)
+	}
+	sendResponse := &protobuf.SC_50004{}
+	decodePacketMessage(t, other, 50004, sendResponse)
+	other.Buffer.Reset()
+	if sendResponse.GetResult() != friendResultBlocked {
+		t.Fatalf("expected blocked result, got %d", sendResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_50011{Id: proto.Uint32(otherID)})
+	if _, _, err := FriendDelete(&payload, client); err != nil {
+		t.Fatalf("FriendDelete failed: %v", err)
+	}
+	deleteResponse := &protobuf.SC_50012{}
+	decodePacketMessage(t, client, 50012, deleteResponse)
+	client.Buffer.Reset()
+	if deleteResponse.GetResult() != friendResultNotFound {
+		t.Fatalf("expected not found result, got %d", deleteResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_50109{Id: proto.Uint32(otherID)})
+	if _, _, err := FriendUnblock(&payload, client); err != nil {
+		t.Fatalf("FriendUnblock failed: %v", err)
+	}
+	unblockResponse := &protobuf.SC_50110{}
+	decodePacketMessage(t, client, 50110, unblockResponse)
+	client.Buffer.Reset()
+	if unblockResponse.GetResult() != friendResultSuccess {
+		t.Fatalf("expected result 0, got %d", unblockResponse.GetResult())
+	}
+}
@@ -2,11 +2,28 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ggmolly/belfast/internal/protobuf"
 )
 
+// GetChargeList lists the goods the commander already charged, the first
+// charge of a goods being the one with bonus gems.
 func GetChargeList(buffer *[]byte, client *connection.Client) (int, int, error) {
 	var response protobuf.SC_16105
+	counts, err := orm.ListChargeCounts(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 16105, err
+	}
+	response.FirstPayList = make([]uint32, 0, len(counts))
+	response.PayList = make([]*protobuf.SHOPINFO, 0, len(counts))
+	for _, count := range counts {
+		response.FirstPayList = append(response.FirstPayList, count.ShopID)
+		response.PayList = append(response.PayList, &protobuf.SHOPINFO{
+			ShopId:   proto.Uint32(count.ShopID),
+			PayCount: proto.Uint32(count.Count),
+		})
+	}
 	return client.SendMessage(16105, &response)
 }
@@ -1,29 +1,353 @@
 package answer
 
 import (
-	"fmt"
+	"context"
+	"time"
 
-	"github.com/ggmolly/belfast/internal/connection"
-	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
-	"google.golang.org/protobuf/proto"
 )
 
-// A get with a type?
+const (
+	meowfficerHomeOpClean = uint32(1)
+	meowfficerHomeOpFeed  = uint32(2)
+	meowfficerHomeOpPlay  = uint32(3)
+
+	// meowfficerHomeOpCooldown is the delay before the lodge can be cleaned
+	// again, or a meowfficer fed or played with again, in seconds.
+	meowfficerHomeOpCooldown = uint32(4 * 60 * 60)
+
+	meowfficerHomeFlagFeed = uint32(1 << 0)
+	meowfficerHomeFlagPlay = uint32(1 << 1)
+)
+
+// meowfficerHomeTemplate is a cat lodge level (its id): exp is needed to
+// reach the next one (0 at the max level) and slot is the number of
+// training slots. Meowfficers in the slots earn hour_exp per hour, and
+// feed_exp and play_exp when fed or played with; every operation gives
+// home_exp to the lodge.
+type meowfficerHomeTemplate struct {
+	ID      uint32 `json:"id"`
+	Exp     uint32 `json:"exp"`
+	Slot    uint32 `json:"slot"`
+	HourExp uint32 `json:"hour_exp"`
+	FeedExp uint32 `json:"feed_exp"`
+	PlayExp uint32 `json:"play_exp"`
+	HomeExp uint32 `json:"home_exp"`
+}
+
+// meowfficerHomeSession is the cat lodge of a commander with the training
+// slots of its level.
+type meowfficerHomeSession struct {
+	home     *orm.MeowfficerHome
+	template meowfficerHomeTemplate
+	slots    []orm.MeowfficerHomeSlot
+}
+
+func loadMeowfficerHomeSession(commanderID uint32) (*meowfficerHomeSession, error) {
+	home, err := loadMeowfficerHome(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	session := &meowfficerHomeSession{home: home}
+	if _, err := loadMeowfficerConfig(meowfficerHomeCategory, home.Level, &session.template); err != nil {
+		return nil, err
+	}
+	stored, err := orm.ListMeowfficerHomeSlots(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	session.slots = make([]orm.MeowfficerHomeSlot, session.template.Slot)
+	for i := range session.slots {
+		session.slots[i] = orm.MeowfficerHomeSlot{CommanderID: commanderID, SlotID: uint32(i + 1)}
+	}
+	for _, slot := range stored {
+		if slot.SlotID >= 1 && slot.SlotID <= session.template.Slot {
+			session.slots[slot.SlotID-1] = slot
+		}
+	}
+	return session, nil
+}
+
+// slot returns the training slot slotID, nil when it is not unlocked.
+func (session *meowfficerHomeSession) slot(slotID uint32) *orm.MeowfficerHomeSlot {
+	if slotID == 0 || slotID > uint32(len(session.slots)) {
+		return nil
+	}
+	return &session.slots[slotID-1]
+}
+
+// trainingExp is the exp earned by the meowfficer of slot since it was put
+// in training.
+func (session *meowfficerHomeSession) trainingExp(slot *orm.MeowfficerHomeSlot, now uint32) uint32 {
+	if slot.MeowfficerID == 0 || slot.ExpTime == 0 || now <= slot.ExpTime {
+		return 0
+	}
+	return (now - slot.ExpTime) / 3600 * session.template.HourExp
+}
+
+// addExp levels up the lodge with exp.
+func (session *meowfficerHomeSession) addExp(exp uint32) error {
+	home := session.home
+	home.Exp += exp
+	for session.template.Exp != 0 && home.Exp >= session.template.Exp {
+		var next meowfficerHomeTemplate
+		ok, err := loadMeowfficerConfig(meowfficerHomeCategory, home.Level+1, &next)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		home.Exp -= session.template.Exp
+		home.Level++
+		session.template = next
+	}
+	if session.template.Exp == 0 {
+		home.Exp = 0
+	}
+	return nil
+}
+
+// GetCommanderHome handles CS_25026: the cat lodge and its training slots.
+// clean is 1 while the lodge can be cleaned.
 func GetCommanderHome(buffer *[]byte, client *connection.Client) (int, int, error) {
 	var packet protobuf.CS_25026
-	err := proto.Unmarshal(*buffer, &packet)
+	if err := proto.Unmarshal(*buffer, &packet); err != nil {
+		return 0, 25027, err
+	}
+	session, err := loadMeowfficerHomeSession(client.Commander.CommanderID)
 	if err != nil {
 		return 0, 25027, err
 	}
-	logger.LogEvent("Client", "CS_25026", fmt.Sprintf("client asked for type=%d", packet.GetType()), logger.LOG_LEVEL_DEBUG)
-
+	now := uint32(time.Now().Unix())
 	response := protobuf.SC_25027{
-		Level: proto.Uint32(1),
-		Exp:   proto.Uint32(0),
-		Clean: proto.Uint32(0),
+		Level: proto.Uint32(session.home.Level),
+		Exp:   proto.Uint32(session.home.Exp),
+		Slots: make([]*protobuf.COMMANDERHOMESLOT, 0, len(session.slots)),
+		Clean: proto.Uint32(boolToUint32(now >= session.home.CleanTime+meowfficerHomeOpCooldown)),
+	}
+	for i := range session.slots {
+		slot := &session.slots[i]
+		info := &protobuf.COMMANDERHOMESLOT{
+			Id:          proto.Uint32(slot.SlotID),
+			OpFlag:      proto.Uint32(0),
+			ExpTime:     proto.Uint32(slot.ExpTime),
+			CommanderId: proto.Uint32(slot.MeowfficerID),
+			Style:       proto.Uint32(slot.Style),
+			CacheExp:    proto.Uint32(session.trainingExp(slot, now)),
+		}
+		if slot.MeowfficerID != 0 {
+			meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, slot.MeowfficerID)
+			if err != nil && !db.IsNotFound(err) {
+				return 0, 25027, err
+			}
+			if meowfficer != nil {
+				flags := uint32(0)
+				if now >= meowfficer.HomeFeedTime+meowfficerHomeOpCooldown {
+					flags |= meowfficerHomeFlagFeed
+				}
+				if now >= meowfficer.HomePlayTime+meowfficerHomeOpCooldown {
+					flags |= meowfficerHomeFlagPlay
+				}
+				info.OpFlag = proto.Uint32(flags)
+				info.CommanderLevel = proto.Uint32(meowfficer.Level)
+				info.CommanderExp = proto.Uint32(meowfficer.Exp)
+			}
+		}
+		response.Slots = append(response.Slots, info)
 	}
-	// Answer with default valid SC_25027
 	return client.SendMessage(25027, &response)
 }
+
+// MeowfficerHomeOp handles CS_25028: the lodge is cleaned, or the
+// meowfficers training in it are fed or played with.
+func MeowfficerHomeOp(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25028
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25029, err
+	}
+	commanderID := client.Commander.CommanderID
+	session, err := loadMeowfficerHomeSession(commanderID)
+	if err != nil {
+		return 0, 25029, err
+	}
+	now := uint32(time.Now().Unix())
+	failed := &protobuf.SC_25029{
+		Result: proto.Uint32(meowfficerResultFailed),
+		Level:  proto.Uint32(session.home.Level),
+		Exp:    proto.Uint32(session.home.Exp),
+		OpTime: proto.Uint32(0),
+	}
+	updated := []*orm.Meowfficer{}
+	switch payload.GetType() {
+	case meowfficerHomeOpClean:
+		if now < session.home.CleanTime+meowfficerHomeOpCooldown {
+			return client.SendMessage(25029, failed)
+		}
+		session.home.CleanTime = now
+	case meowfficerHomeOpFeed, meowfficerHomeOpPlay:
+		for _, slot := range session.slots {
+			if slot.MeowfficerID == 0 {
+				continue
+			}
+			meowfficer, err := orm.GetMeowfficer(commanderID, slot.MeowfficerID)
+			if err != nil {
+				if db.IsNotFound(err) {
+					continue
+				}
+				return 0, 25029, err
+			}
+			opTime, exp := &meowfficer.HomeFeedTime, session.template.FeedExp
+			if payload.GetType() == meowfficerHomeOpPlay {
+				opTime, exp = &meowfficer.HomePlayTime, session.template.PlayExp
+			}
+			if now < *opTime+meowfficerHomeOpCooldown {
+				continue
+			}
+			*opTime = now
+			if err := addMeowfficerExp(meowfficer, exp); err != nil {
+				return 0, 25029, err
+			}
+			updated = append(updated, meowfficer)
+		}
+		if len(updated) == 0 {
+			return client.SendMessage(25029, failed)
+		}
+	default:
+		return client.SendMessage(25029, failed)
+	}
+	if err := session.addExp(session.template.HomeExp); err != nil {
+		return 0, 25029, err
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for _, meowfficer := range updated {
+			if err := orm.SaveMeowfficerTx(ctx, tx, meowfficer); err != nil {
+				return err
+			}
+		}
+		return orm.SaveMeowfficerHomeTx(ctx, tx, session.home)
+	})
+	if err != nil {
+		return 0, 25029, err
+	}
+	return client.SendMessage(25029, &protobuf.SC_25029{
+		Result: proto.Uint32(meowfficerResultOK),
+		Level:  proto.Uint32(session.home.Level),
+		Exp:    proto.Uint32(session.home.Exp),
+		Awards: []*protobuf.DROPINFO{},
+		OpTime: proto.Uint32(now),
+	})
+}
+
+// MeowfficerHomeSetSlot handles CS_25030: a meowfficer is put in a training
+// slot (or taken out of it with 0). The meowfficer leaving the slot earns
+// its training exp, and its level is sent back; the level of the one put
+// in training otherwise.
+func MeowfficerHomeSetSlot(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25030
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25031, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_25031{
+		Result:         proto.Uint32(meowfficerResultFailed),
+		Time:           proto.Uint32(0),
+		CommanderLevel: proto.Uint32(0),
+		CommanderExp:   proto.Uint32(0),
+	}
+	session, err := loadMeowfficerHomeSession(commanderID)
+	if err != nil {
+		return 0, 25031, err
+	}
+	slot := session.slot(payload.GetSlotidx())
+	if slot == nil || slot.MeowfficerID == payload.GetCommanderId() {
+		return client.SendMessage(25031, failed)
+	}
+	now := uint32(time.Now().Unix())
+	var placed, leaving *orm.Meowfficer
+	if payload.GetCommanderId() != 0 {
+		for _, other := range session.slots {
+			if other.MeowfficerID == payload.GetCommanderId() {
+				return client.SendMessage(25031, failed)
+			}
+		}
+		placed, err = orm.GetMeowfficer(commanderID, payload.GetCommanderId())
+		if err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(25031, failed)
+			}
+			return 0, 25031, err
+		}
+	}
+	if slot.MeowfficerID != 0 {
+		leaving, err = orm.GetMeowfficer(commanderID, slot.MeowfficerID)
+		if err != nil && !db.IsNotFound(err) {
+			return 0, 25031, err
+		}
+		if leaving != nil {
+			if err := addMeowfficerExp(leaving, session.trainingExp(slot, now)); err != nil {
+				return 0, 25031, err
+			}
+		}
+	}
+	slot.MeowfficerID = payload.GetCommanderId()
+	slot.ExpTime = 0
+	if placed != nil {
+		slot.ExpTime = now
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if leaving != nil {
+			if err := orm.SaveMeowfficerTx(ctx, tx, leaving); err != nil {
+				return err
+			}
+		}
+		return orm.SaveMeowfficerHomeSlotTx(ctx, tx, slot)
+	})
+	if err != nil {
+		return 0, 25031, err
+	}
+	shown := leaving
+	if shown == nil {
+		shown = placed
+	}
+	response := protobuf.SC_25031{
+		Result:         proto.Uint32(meowfficerResultOK),
+		Time:           proto.Uint32(now),
+		CommanderLevel: proto.Uint32(0),
+		CommanderExp:   proto.Uint32(0),
+	}
+	if shown != nil {
+		response.CommanderLevel = proto.Uint32(shown.Level)
+		response.CommanderExp = proto.Uint32(shown.Exp)
+	}
+	return client.SendMessage(25031, &response)
+}
+
+// MeowfficerHomeSetStyle handles CS_25032.
+func MeowfficerHomeSetStyle(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25032
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25033, err
+	}
+	session, err := loadMeowfficerHomeSession(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 25033, err
+	}
+	slot := session.slot(payload.GetSlotidx())
+	if slot == nil {
+		return client.SendMessage(25033, &protobuf.SC_25033{Result: proto.Uint32(meowfficerResultFailed)})
+	}
+	slot.Style = payload.GetStyleidx()
+	if err := orm.SaveMeowfficerHomeSlot(slot); err != nil {
+		return 0, 25033, err
+	}
+	return client.SendMessage(25033, &protobuf.SC_25033{Result: proto.Uint32(meowfficerResultOK)})
+}
@@ -2,14 +2,36 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
-
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+// GetMyAssaultFleetCommandResponse handles CS_61009 with the ships the
+// commander lends to their guild.
 func GetMyAssaultFleetCommandResponse(buffer *[]byte, client *connection.Client) (int, int, error) {
 	response := protobuf.SC_61010{
-		Result: proto.Uint32(0),
+		Result:      proto.Uint32(0),
+		PersonShips: []*protobuf.SHIPID_POS_INFO{},
+	}
+	ships, err := orm.ListGuildAssaultShips(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61010, err
+	}
+	for _, ship := range ships {
+		owned, err := orm.GetOwnedShipByOwnerAndID(ship.CommanderID, ship.ShipID)
+		if err != nil {
+			if db.IsNotFound(err) {
+				continue
+			}
+			return 0, 61010, err
+		}
+		response.PersonShips = append(response.PersonShips, &protobuf.SHIPID_POS_INFO{
+			Pos:      proto.Uint32(ship.Pos),
+			Ship:     orm.ToProtoOwnedShip(*owned, nil, nil),
+			LastTime: proto.Uint32(uint32(ship.UpdatedAt.Unix())),
+		})
 	}
 	return client.SendMessage(61010, &response)
 }
@@ -2,16 +2,45 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+// GetRefundInfo lists the refunded charges the commander has to pay back.
 func GetRefundInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
-	// No billing support yet; return an empty refund list intentionally.
+	refunds, err := refundShopInfoList(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 11024, err
+	}
 	response := protobuf.SC_11024{
 		Result:   proto.Uint32(0),
-		ShopInfo: []*protobuf.REFUND_SHOPINFO{},
+		ShopInfo: refunds,
 	}
 
 	return client.SendMessage(11024, &response)
 }
+
+func refundShopInfoList(commanderID uint32) ([]*protobuf.REFUND_SHOPINFO, error) {
+	orders, err := orm.ListUnsettledRefunds(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	refunds := make([]*protobuf.REFUND_SHOPINFO, 0, len(orders))
+	for _, order := range orders {
+		buyTime := order.CreatedAt
+		if order.PaidAt != nil {
+			buyTime = *order.PaidAt
+		}
+		refundTime := buyTime
+		if order.RefundedAt != nil {
+			refundTime = *order.RefundedAt
+		}
+		refunds = append(refunds, &protobuf.REFUND_SHOPINFO{
+			ShopId:     proto.Uint32(order.ShopID),
+			BuyTime:    proto.Uint32(uint32(buyTime.Unix())),
+			RefundTime: proto.Uint32(uint32(refundTime.Unix())),
+		})
+	}
+	return refunds, nil
+}
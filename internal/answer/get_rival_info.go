@@ -26,7 +26,10 @@ func GetRivalInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
 		return 0, 18105, err
 	}
 
-	info := buildRivalTargetInfo(&commander)
+	info, err := buildRivalTargetInfo(&commander)
+	if err != nil {
+		return 0, 18105, err
+	}
 	return client.SendMessage(18105, &protobuf.SC_18105{Info: info})
 }
 
@@ -44,7 +47,7 @@ func rivalInfoSentinel() *protobuf.TARGETINFO {
 	}
 }
 
-func buildRivalTargetInfo(commander *orm.Commander) *protobuf.TARGETINFO {
+func buildRivalTargetInfo(commander *orm.Commander) (*protobuf.TARGETINFO, error) {
 	display := &protobuf.DISPLAYINFO{
 		Icon:          proto.Uint32(commander.DisplayIconID),
 		Skin:          proto.Uint32(commander.DisplaySkinID),
@@ -64,17 +67,21 @@ func buildRivalTargetInfo(commander *orm.Commander) *protobuf.TARGETINFO {
 	}
 
 	vanguardShips, mainShips := buildRivalDefenseShipLists(commander)
+	score, rank, err := arenaStanding(commander.CommanderID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &protobuf.TARGETINFO{
 		Id:               proto.Uint32(commander.CommanderID),
 		Level:            proto.Uint32(uint32(commander.Level)),
 		Name:             proto.String(commander.Name),
-		Score:            proto.Uint32(0),
-		Rank:             proto.Uint32(0),
+		Score:            proto.Uint32(score),
+		Rank:             proto.Uint32(rank),
 		VanguardShipList: vanguardShips,
 		MainShipList:     mainShips,
 		Display:          display,
-	}
+	}, nil
 }
 
 func buildRivalDefenseShipLists(commander *orm.Commander) ([]*protobuf.SHIPINFO, []*protobuf.SHIPINFO) {
@@ -6,8 +6,6 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-const guildChatPlaceholderID uint32 = 0
-
 func buildGuildChatPlayer(commander *orm.Commander) *protobuf.PLAYER_INFO_P60 {
 	return &protobuf.PLAYER_INFO_P60{
 		Id:   proto.Uint32(commander.CommanderID),
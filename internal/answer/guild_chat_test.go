@@ -40,6 +40,7 @@ func setupGuildChatTest(t *testing.T) (*connection.Server, *connection.Client, *
 	os.Setenv("MODE", "test")
 	orm.InitDatabase()
 	clearTable(t, &orm.GuildChatMessage{})
+	clearTable(t, &orm.Guild{})
 	clearTable(t, &orm.Commander{})
 
 	server := connection.NewServer("127.0.0.1", 0, func(pkt *[]byte, c *connection.Client, size int) {})
@@ -87,6 +88,14 @@ func setupGuildChatTest(t *testing.T) (*connection.Server, *connection.Client, *
 	listener := &connection.Client{Commander: &otherCommander, Hash: 2}
 	server.AddClient(listener)
 
+	guild := orm.Guild{Name: fmt.Sprintf("Chat Guild %d", commanderID), Faction: 1, Policy: 1}
+	if err := orm.CreateGuild(&guild, commander.CommanderID, commander.Name); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	if err := orm.AddGuildMember(guild.ID, otherCommander.CommanderID, orm.GuildDutyOrdinary, otherCommander.Name); err != nil {
+		t.Fatalf("add guild member: %v", err)
+	}
+
 	return server, client, listener
 }
 
@@ -153,12 +162,16 @@ func TestGuildSendMessageBroadcastsRegistrationPin(t *testing.T) {
 
 func TestCommanderGuildChatHistory(t *testing.T) {
 	_, client, _ := setupGuildChatTest(t)
+	member, err := orm.GetGuildMember(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("load guild member: %v", err)
+	}
 
 	base := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
-	if _, err := orm.CreateGuildChatMessage(guildChatPlaceholderID, client.Commander.CommanderID, "first", base); err != nil {
+	if _, err := orm.CreateGuildChatMessage(member.GuildID, client.Commander.CommanderID, "first", base); err != nil {
 		t.Fatalf("create message 1: %v", err)
 	}
-	if _, err := orm.CreateGuildChatMessage(guildChatPlaceholderID, client.Commander.CommanderID, "second", base.Add(2*time.Minute)); err != nil {
+	if _, err := orm.CreateGuildChatMessage(member.GuildID, client.Commander.CommanderID, "second", base.Add(2*time.Minute)); err != nil {
 		t.Fatalf("create message 2: %v", err)
 	}
 
@@ -180,3 +193,37 @@ func TestCommanderGuildChatHistory(t *testing.T) {
 		t.Fatalf("unexpected chat order")
 	}
 }
+
+func TestGuildSendMessageSkipsOtherGuilds(t *testing.T) {
+	server, client, _ := setupGuildChatTest(t)
+	outsiderID := client.Commander.CommanderID + 2
+	if err := orm.CreateCommanderRoot(outsiderID, outsiderID, "Guild Outsider", 0, 0); err != nil {
+		t.Fatalf("create outsider commander: %v", err)
+	}
+	outsiderCommander := orm.Commander{CommanderID: outsiderID}
+	if err := outsiderCommander.Load(); err != nil {
+		t.Fatalf("load outsider commander: %v", err)
+	}
+	outsider := &connection.Client{Commander: &outsiderCommander, Hash: 3}
+	server.AddClient(outsider)
+
+	payload := protobuf.CS_60007{Chat: proto.String("members only")}
+	buffer, err := proto.Marshal(&payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	if _, _, err := GuildSendMessage(&buffer, client); err != nil {
+		t.Fatalf("GuildSendMessage failed: %v", err)
+	}
+	if outsider.Buffer.Len() != 0 {
+		t.Fatalf("expected outsider to receive nothing")
+	}
+
+	if _, _, err := GuildSendMessage(&buffer, outsider); err != nil {
+		t.Fatalf("GuildSendMessage from outsider failed: %v", err)
+	}
+	count := queryAnswerTestInt64(t, "SELECT COUNT(*) FROM guild_chat_messages")
+	if count != 1 {
+		t.Fatalf("expected 1 chat message, got %d", count)
+	}
+}
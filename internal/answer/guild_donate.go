@@ -0,0 +1,315 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/rng"
+)
+
+const (
+	guildDonateConfigCategory = "ShareCfg/guild_contribution_template.json"
+
+	guildDonateTaskCount      = 3
+	guildDonateLimitFallback  = uint32(21)
+	guildCoinResource         = uint32(8)
+	guildCapitalLogCount      = 50
+	guildContributionRankType = uint32(1)
+)
+
+var guildDonateRng = rng.NewLockedRand()
+
+// guildDonateTemplate is a donation task. consume is a [type, id, count]
+// drop taken from the member; the guild receives award_capital (which also
+// feeds the running research) and the member award_contribution guild coins.
+type guildDonateTemplate struct {
+	ID                uint32   `json:"id"`
+	Consume           []uint32 `json:"consume"`
+	AwardCapital      uint32   `json:"award_capital"`
+	AwardContribution uint32   `json:"award_contribution"`
+}
+
+func loadGuildDonateTemplates() (map[uint32]guildDonateTemplate, error) {
+	entries, err := gamedata.List[guildDonateTemplate](gamedata.Default, guildDonateConfigCategory)
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[uint32]guildDonateTemplate, len(entries))
+	for _, template := range entries {
+		if template.ID == 0 || len(template.Consume) < 3 {
+			continue
+		}
+		templates[template.ID] = template
+	}
+	return templates, nil
+}
+
+// rollGuildDonateTasks picks up to guildDonateTaskCount distinct tasks,
+// avoiding exclude when enough templates exist.
+func rollGuildDonateTasks(templates map[uint32]guildDonateTemplate, exclude uint32) orm.Int64List {
+	ids := make([]uint32, 0, len(templates))
+	for id := range templates {
+		if id == exclude && len(templates) > guildDonateTaskCount {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	guildDonateRng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if len(ids) > guildDonateTaskCount {
+		ids = ids[:guildDonateTaskCount]
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return orm.ToInt64List(ids)
+}
+
+// loadCommanderGuildState returns the donation state of commanderID,
+// resetting the weekly counter and rolling tasks when needed.
+func loadCommanderGuildState(commanderID uint32, templates map[uint32]guildDonateTemplate, now time.Time) (*orm.CommanderGuildState, error) {
+	state, err := orm.GetCommanderGuildState(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	week := uint32(weekStartMondayUTC(now).Unix())
+	if state.DonateWeek == week && len(state.DonateTasks) > 0 {
+		return state, nil
+	}
+	if state.DonateWeek != week {
+		state.DonateCount = 0
+		state.DonateWeek = week
+		state.WeeklyTaskFlag = 0
+	}
+	state.DonateTasks = rollGuildDonateTasks(templates, 0)
+	if err := orm.SaveCommanderGuildState(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func guildDonateConsume(client *connection.Client, ctx context.Context, tx pgx.Tx, consume []uint32) error {
+	switch consume[0] {
+	case consts.DROP_TYPE_RESOURCE:
+		if !client.Commander.HasEnoughResource(consume[1], consume[2]) {
+			return errGuildResourcesNotEnough
+		}
+		if err := client.Commander.ConsumeResourceTx(ctx, tx, consume[1], consume[2]); err != nil {
+			return errGuildResourcesNotEnough
+		}
+	case consts.DROP_TYPE_ITEM:
+		if !client.Commander.HasEnoughItem(consume[1], consume[2]) {
+			return errGuildResourcesNotEnough
+		}
+		if err := client.Commander.ConsumeItemTx(ctx, tx, consume[1], consume[2]); err != nil {
+			return errGuildResourcesNotEnough
+		}
+	default:
+		return errGuildResourcesNotEnough
+	}
+	return nil
+}
+
+// GuildDonate handles CS_62002.
+func GuildDonate(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62002
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62003, err
+	}
+	response := protobuf.SC_62003{Result: proto.Uint32(guildResultFailed)}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62003, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(62003, &response)
+	}
+	templates, err := loadGuildDonateTemplates()
+	if err != nil {
+		return 0, 62003, err
+	}
+	now := time.Now()
+	state, err := loadCommanderGuildState(client.Commander.CommanderID, templates, now)
+	if err != nil {
+		return 0, 62003, err
+	}
+	response.DonateTasks = orm.ToUint32List(state.DonateTasks)
+	taskID := payload.GetId()
+	template, ok := templates[taskID]
+	if !ok || !containsUint32(response.DonateTasks, taskID) {
+		return client.SendMessage(62003, &response)
+	}
+	limit, err := guildSetValue("donate_weekly_limit", guildDonateLimitFallback)
+	if err != nil {
+		return 0, 62003, err
+	}
+	if state.DonateCount >= limit {
+		response.Result = proto.Uint32(guildResultCooldown)
+		return client.SendMessage(62003, &response)
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 62003, err
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, 62003, err
+	}
+	var (
+		reached  []uint32
+		progress uint32
+	)
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := guildDonateConsume(client, ctx, tx, template.Consume); err != nil {
+			return err
+		}
+		if err := orm.AddGuildCapitalTx(ctx, tx, guild.ID, int64(template.AwardCapital), client.Commander.CommanderID, client.Commander.Name, orm.GuildCapitalLogDonate, []uint32{taskID}); err != nil {
+			return err
+		}
+		if err := orm.AddGuildMemberContributionTx(ctx, tx, guild.ID, client.Commander.CommanderID, template.AwardContribution); err != nil {
+			return err
+		}
+		if template.AwardContribution > 0 {
+			if err := client.Commander.AddResourceTx(ctx, tx, guildCoinResource, template.AwardContribution); err != nil {
+				return err
+			}
+		}
+		var err error
+		if reached, err = advanceGuildResearchTx(ctx, tx, guild, catalog, template.AwardCapital); err != nil {
+			return err
+		}
+		if progress, err = orm.AdvanceGuildWeeklyTaskTx(ctx, tx, guild.ID, state.DonateWeek, 1); err != nil {
+			return err
+		}
+		state.DonateCount++
+		state.DonateTasks = rollGuildDonateTasks(templates, taskID)
+		return orm.SaveCommanderGuildStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		if errors.Is(err, errGuildResourcesNotEnough) {
+			response.Result = proto.Uint32(guildResultNotEnough)
+			return client.SendMessage(62003, &response)
+		}
+		return 0, 62003, err
+	}
+	for _, id := range reached {
+		if err := broadcastGuildMessage(client, guild.ID, 62017, &protobuf.SC_62017{Id: proto.Uint32(id)}); err != nil {
+			return 0, 62003, err
+		}
+	}
+	if err := broadcastGuildMessage(client, guild.ID, 62006, &protobuf.SC_62006{Progress: proto.Uint32(progress)}); err != nil {
+		return 0, 62003, err
+	}
+	response.Result = proto.Uint32(guildResultSuccess)
+	response.DonateTasks = orm.ToUint32List(state.DonateTasks)
+	return client.SendMessage(62003, &response)
+}
+
+// GuildGetCapital handles CS_62024.
+func GuildGetCapital(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62024
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62025, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62025, err
+	}
+	if member == nil {
+		return client.SendMessage(62025, &protobuf.SC_62025{Result: proto.Uint32(guildResultNotFound), Capital: proto.Uint32(0)})
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 62025, err
+	}
+	return client.SendMessage(62025, &protobuf.SC_62025{Result: proto.Uint32(guildResultSuccess), Capital: proto.Uint32(guild.Capital)})
+}
+
+// GuildCapitalLogs handles CS_62011. Donations are income, spending is
+// outgoing and admin adjustments go to the other bucket.
+func GuildCapitalLogs(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62011
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62012, err
+	}
+	response := protobuf.SC_62012{
+		Result:   proto.Uint32(guildResultSuccess),
+		Inclog:   []*protobuf.CAPITAL_LOG{},
+		Declog:   []*protobuf.CAPITAL_LOG{},
+		Otherlog: []*protobuf.CAPITAL_LOG{},
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62012, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(62012, &response)
+	}
+	logs, err := orm.ListGuildCapitalLogs(member.GuildID, guildCapitalLogCount)
+	if err != nil {
+		return 0, 62012, err
+	}
+	for _, log := range logs {
+		entry := &protobuf.CAPITAL_LOG{
+			MemberId:    proto.Uint32(log.CommanderID),
+			Name:        proto.String(log.Name),
+			EventType:   proto.Uint32(log.EventType),
+			EventTarget: orm.ToUint32List(log.EventTarget),
+			Time:        proto.Uint32(uint32(log.CreatedAt.Unix())),
+		}
+		switch {
+		case log.EventType == orm.GuildCapitalLogAdmin:
+			response.Otherlog = append(response.Otherlog, entry)
+		case log.Amount < 0:
+			response.Declog = append(response.Declog, entry)
+		default:
+			response.Inclog = append(response.Inclog, entry)
+		}
+	}
+	return client.SendMessage(62012, &response)
+}
+
+// GuildContributionRank handles CS_62029 with the total contribution of
+// every current member.
+func GuildContributionRank(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62029
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62030, err
+	}
+	response := protobuf.SC_62030{List: []*protobuf.RANK_INFO_P62{}}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62030, err
+	}
+	if member == nil {
+		return client.SendMessage(62030, &response)
+	}
+	members, err := orm.ListGuildMembers(member.GuildID)
+	if err != nil {
+		return 0, 62030, err
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].Contribution > members[j].Contribution })
+	rank := &protobuf.RANK_INFO_P62{
+		Period:       proto.Uint32(guildContributionRankType),
+		Rankuserinfo: make([]*protobuf.RANK_USER_INFO, 0, len(members)),
+	}
+	for _, profile := range members {
+		rank.Rankuserinfo = append(rank.Rankuserinfo, &protobuf.RANK_USER_INFO{
+			UserId: proto.Uint32(profile.CommanderID),
+			Count:  proto.Uint32(profile.Contribution),
+		})
+	}
+	response.List = append(response.List, rank)
+	return client.SendMessage(62030, &response)
+}
@@ -0,0 +1,161 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func seedGuildDonateConfig(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"1", "2", "3"} {
+		seedConfigEntry(t, guildDonateConfigCategory, key, `{"id":`+key+`,"consume":[1,1,100],"award_capital":30,"award_contribution":10}`)
+	}
+	seedConfigEntry(t, guildTechConfigCategory, "101", `{"id":101,"group":1,"level":1,"contribution_consume":50,"gold_consume":200,"effect_args":[1,5]}`)
+	seedConfigEntry(t, guildTechConfigCategory, "102", `{"id":102,"group":1,"level":2,"contribution_consume":100,"gold_consume":400,"effect_args":[[1,5],[2,10]]}`)
+}
+
+func TestGuildDonateFundsResearch(t *testing.T) {
+	leader, _ := setupGuildTestClients(t)
+	seedGuildDonateConfig(t)
+	guildID := createTestGuild(t, leader, "Donate Guild")
+	if err := leader.Commander.AddResource(1, 1000); err != nil {
+		t.Fatalf("add gold: %v", err)
+	}
+	goldBefore := leader.Commander.GetResourceCount(1)
+	coinsBefore := leader.Commander.GetResourceCount(guildCoinResource)
+
+	payload := marshalPacketRequest(t, &protobuf.CS_62013{Id: proto.Uint32(1)})
+	if _, _, err := GuildStartResearch(&payload, leader); err != nil {
+		t.Fatalf("GuildStartResearch failed: %v", err)
+	}
+	researchResponse := &protobuf.SC_62014{}
+	decodePacketMessage(t, leader, 62014, researchResponse)
+	leader.Buffer.Reset()
+	if researchResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", researchResponse.GetResult())
+	}
+
+	empty := []byte{}
+	if _, _, err := GuildGetUserInfoCommand(&empty, leader); err != nil {
+		t.Fatalf("GuildGetUserInfoCommand failed: %v", err)
+	}
+	userInfo := &protobuf.SC_60103{}
+	decodePacketMessage(t, leader, 60103, userInfo)
+	leader.Buffer.Reset()
+	tasks := userInfo.GetUserInfo().GetDonateTasks()
+	if len(tasks) != guildDonateTaskCount {
+		t.Fatalf("expected %d donate tasks, got %v", guildDonateTaskCount, tasks)
+	}
+
+	for i := 0; i < 2; i++ {
+		payload = marshalPacketRequest(t, &protobuf.CS_62002{Id: proto.Uint32(tasks[0])})
+		if _, _, err := GuildDonate(&payload, leader); err != nil {
+			t.Fatalf("GuildDonate failed: %v", err)
+		}
+		donateResponse := &protobuf.SC_62003{}
+		decodePacketMessage(t, leader, 62003, donateResponse)
+		leader.Buffer.Reset()
+		if donateResponse.GetResult() != guildResultSuccess {
+			t.Fatalf("expected result 0, got %d", donateResponse.GetResult())
+		}
+		tasks = donateResponse.GetDonateTasks()
+	}
+	if leader.Commander.GetResourceCount(1) != goldBefore-200 {
+		t.Fatalf("expected 200 gold spent, got %d left of %d", leader.Commander.GetResourceCount(1), goldBefore)
+	}
+	if leader.Commander.GetResourceCount(guildCoinResource) != coinsBefore+20 {
+		t.Fatalf("expected 20 guild coins gained, got %d", leader.Commander.GetResourceCount(guildCoinResource)-coinsBefore)
+	}
+	guild, err := orm.GetGuild(guildID)
+	if err != nil {
+		t.Fatalf("load guild: %v", err)
+	}
+	if guild.Capital != 60 || guild.WeeklyTaskProgress != 2 {
+		t.Fatalf("unexpected guild funds: capital=%d weekly=%d", guild.Capital, guild.WeeklyTaskProgress)
+	}
+	techs, err := orm.ListGuildTechnologies(guildID)
+	if err != nil {
+		t.Fatalf("list technologies: %v", err)
+	}
+	if len(techs) != 1 || techs[0].Level != 1 || techs[0].Progress != 10 {
+		t.Fatalf("unexpected guild technologies: %+v", techs)
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_62020{Id: proto.Uint32(1)})
+	if _, _, err := GuildLearnTechnology(&payload, leader); err != nil {
+		t.Fatalf("GuildLearnTechnology failed: %v", err)
+	}
+	learnResponse := &protobuf.SC_62021{}
+	decodePacketMessage(t, leader, 62021, learnResponse)
+	leader.Buffer.Reset()
+	if learnResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", learnResponse.GetResult())
+	}
+	if _, _, err := GuildLearnTechnology(&payload, leader); err != nil {
+		t.Fatalf("GuildLearnTechnology failed: %v", err)
+	}
+	decodePacketMessage(t, leader, 62021, learnResponse)
+	leader.Buffer.Reset()
+	if learnResponse.GetResult() != guildResultFailed {
+		t.Fatalf("expected level above the guild to be refused, got %d", learnResponse.GetResult())
+	}
+	bonus, err := guildTechBonus(leader.Commander.CommanderID, guildTechEffectShipBag)
+	if err != nil {
+		t.Fatalf("guildTechBonus failed: %v", err)
+	}
+	if bonus != 5 {
+		t.Fatalf("expected ship bag bonus 5, got %d", bonus)
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_62011{Type: proto.Uint32(0)})
+	if _, _, err := GuildCapitalLogs(&payload, leader); err != nil {
+		t.Fatalf("GuildCapitalLogs failed: %v", err)
+	}
+	logResponse := &protobuf.SC_62012{}
+	decodePacketMessage(t, leader, 62012, logResponse)
+	leader.Buffer.Reset()
+	if len(logResponse.GetInclog()) != 2 || len(logResponse.GetDeclog()) != 0 {
+		t.Fatalf("unexpected capital logs: %+v", logResponse)
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_62029{Type: proto.Uint32(0)})
+	if _, _, err := GuildContributionRank(&payload, leader); err != nil {
+		t.Fatalf("GuildContributionRank failed: %v", err)
+	}
+	rankResponse := &protobuf.SC_62030{}
+	decodePacketMessage(t, leader, 62030, rankResponse)
+	leader.Buffer.Reset()
+	if len(rankResponse.GetList()) != 1 || rankResponse.GetList()[0].GetRankuserinfo()[0].GetCount() != 20 {
+		t.Fatalf("unexpected contribution rank: %+v", rankResponse.GetList())
+	}
+}
+
+func TestGuildDonateRequiresTask(t *testing.T) {
+	leader, outsider := setupGuildTestClients(t)
+	seedGuildDonateConfig(t)
+	createTestGuild(t, leader, "Donate Task Guild")
+
+	payload := marshalPacketRequest(t, &protobuf.CS_62002{Id: proto.Uint32(99)})
+	if _, _, err := GuildDonate(&payload, leader); err != nil {
+		t.Fatalf("GuildDonate failed: %v", err)
+	}
+	response := &protobuf.SC_62003{}
+	decodePacketMessage(t, leader, 62003, response)
+	leader.Buffer.Reset()
+	if response.GetResult() != guildResultFailed {
+		t.Fatalf("expected unknown task to fail, got %d", response.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_62002{Id: proto.Uint32(1)})
+	if _, _, err := GuildDonate(&payload, outsider); err != nil {
+		t.Fatalf("GuildDonate failed: %v", err)
+	}
+	decodePacketMessage(t, outsider, 62003, response)
+	outsider.Buffer.Reset()
+	if response.GetResult() != guildResultNotFound {
+		t.Fatalf("expected outsider donation to fail, got %d", response.GetResult())
+	}
+}
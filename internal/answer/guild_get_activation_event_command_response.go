@@ -1,19 +1,46 @@
 package answer
 
 import (
-	"github.com/ggmolly/belfast/internal/connection"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
-var validSC61006 protobuf.SC_61006
-
+// GuildGetActivationEventCommandResponse handles CS_61005 with the operation
+// the commander's guild is running, if any.
 func GuildGetActivationEventCommandResponse(buffer *[]byte, client *connection.Client) (int, int, error) {
-	return client.SendMessage(61006, &validSC61006)
-}
-
-func init() {
-	data := []byte{0x08, 0x14}
-	proto.Unmarshal(data, &validSC61006)
+	var payload protobuf.CS_61005
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61006, err
+	}
+	response := protobuf.SC_61006{Result: proto.Uint32(guildResultSuccess)}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61006, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61006, &response)
+	}
+	op, err := orm.GetGuildOperation(member.GuildID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(61006, &response)
+		}
+		return 0, 61006, err
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 61006, err
+	}
+	operation, err := buildGuildCurrentOperation(guild, op, client.Commander.CommanderID, uint32(time.Now().Unix()))
+	if err != nil {
+		return 0, 61006, err
+	}
+	response.Operation = operation
+	return client.SendMessage(61006, &response)
 }
@@ -2,14 +2,49 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
-
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+// GuildGetAssaultFleetCommandResponse handles CS_61011 with the assault
+// ships the other members of the guild lend.
 func GuildGetAssaultFleetCommandResponse(buffer *[]byte, client *connection.Client) (int, int, error) {
 	response := protobuf.SC_61012{
 		Result: proto.Uint32(0),
+		Ships:  []*protobuf.TEAM_CHUNK{},
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61012, err
+	}
+	if member == nil {
+		return client.SendMessage(61012, &response)
+	}
+	ships, err := orm.ListGuildAssaultShipsByGuild(member.GuildID)
+	if err != nil {
+		return 0, 61012, err
+	}
+	chunks := make(map[uint32]*protobuf.TEAM_CHUNK)
+	for _, ship := range ships {
+		if ship.CommanderID == client.Commander.CommanderID {
+			continue
+		}
+		owned, err := orm.GetOwnedShipByOwnerAndID(ship.CommanderID, ship.ShipID)
+		if err != nil {
+			if db.IsNotFound(err) {
+				continue
+			}
+			return 0, 61012, err
+		}
+		chunk, ok := chunks[ship.CommanderID]
+		if !ok {
+			chunk = &protobuf.TEAM_CHUNK{UserId: proto.Uint32(ship.CommanderID)}
+			chunks[ship.CommanderID] = chunk
+			response.Ships = append(response.Ships, chunk)
+		}
+		chunk.Ships = append(chunk.Ships, orm.ToProtoOwnedShip(*owned, nil, nil))
 	}
 	return client.SendMessage(61012, &response)
 }
@@ -1,18 +1,39 @@
 package answer
 
 import (
-	"github.com/ggmolly/belfast/internal/connection"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
+// GuildGetUserInfoCommand handles CS_60102.
 func GuildGetUserInfoCommand(buffer *[]byte, client *connection.Client) (int, int, error) {
+	templates, err := loadGuildDonateTemplates()
+	if err != nil {
+		return 0, 60103, err
+	}
+	state, err := loadCommanderGuildState(client.Commander.CommanderID, templates, time.Now())
+	if err != nil {
+		return 0, 60103, err
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, 60103, err
+	}
+	techIDs, err := commanderGuildTechIDs(client.Commander.CommanderID, catalog)
+	if err != nil {
+		return 0, 60103, err
+	}
 	response := protobuf.SC_60103{
 		UserInfo: &protobuf.USER_GUILD_INFO{
-			DonateCount:    proto.Uint32(0),
+			DonateCount:    proto.Uint32(state.DonateCount),
+			DonateTasks:    orm.ToUint32List(state.DonateTasks),
 			BenefitTime:    proto.Uint32(0),
-			WeeklyTaskFlag: proto.Uint32(0),
+			TechId:         techIDs,
+			WeeklyTaskFlag: proto.Uint32(state.WeeklyTaskFlag),
 			ExtraDonate:    proto.Uint32(0),
 			ExtraOperation: proto.Uint32(0),
 		},
@@ -0,0 +1,777 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	guildResultSuccess        = uint32(0)
+	guildResultFailed         = uint32(1)
+	guildResultNotFound       = uint32(2)
+	guildResultNoPermission   = uint32(3)
+	guildResultFull           = uint32(4)
+	guildResultAlreadyInGuild = uint32(5)
+	guildResultNameTaken      = uint32(6)
+	guildResultNotEnough      = uint32(7)
+	guildResultCooldown       = uint32(8)
+
+	guildNameMaxLength      = 20
+	guildManifestoMaxLength = 100
+	guildApplyMaxLength     = 50
+	guildListCount          = 10
+	guildLogCount           = 50
+
+	guildModifyName      = uint32(1)
+	guildModifyFaction   = uint32(2)
+	guildModifyPolicy    = uint32(3)
+	guildModifyManifesto = uint32(4)
+	guildModifyAnnounce  = uint32(5)
+
+	guildSearchTypeID = uint32(1)
+
+	guildCreateCostResource  = uint32(4)
+	guildCreateCostFallback  = uint32(300)
+	guildImpeachOfflineLimit = 10 * 24 * time.Hour
+	guildImpeachCD           = 24 * time.Hour
+	guildFactionChangeCD     = 24 * time.Hour
+
+	guildSetConfigCategory = "ShareCfg/guildset.json"
+)
+
+// guildSetEntry is a guildset row, a single key_value.
+type guildSetEntry struct {
+	KeyValue uint32 `json:"key_value"`
+}
+
+// guildSetValue reads a key_value from guildset, falling back when the key
+// is not seeded.
+func guildSetValue(key string, fallback uint32) (uint32, error) {
+	value, err := gamedata.Get[guildSetEntry](gamedata.Default, guildSetConfigCategory, key)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return fallback, nil
+		}
+		return 0, err
+	}
+	return value.KeyValue, nil
+}
+
+// loadGuildMembership returns the caller's membership, or nil when they are
+// not in a guild.
+func loadGuildMembership(commanderID uint32) (*orm.GuildMember, error) {
+	member, err := orm.GetGuildMember(commanderID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func guildCanManageMembers(duty uint32) bool {
+	return duty == orm.GuildDutyCommander || duty == orm.GuildDutyDeputy
+}
+
+func buildGuildDisplay(profile *orm.GuildMemberProfile) *protobuf.DISPLAYINFO {
+	return &protobuf.DISPLAYINFO{
+		Icon:          proto.Uint32(profile.DisplayIconID),
+		Skin:          proto.Uint32(profile.DisplaySkinID),
+		IconFrame:     proto.Uint32(profile.SelectedIconFrameID),
+		ChatFrame:     proto.Uint32(profile.SelectedChatFrameID),
+		IconTheme:     proto.Uint32(profile.DisplayIconThemeID),
+		MarryFlag:     proto.Uint32(0),
+		TransformFlag: proto.Uint32(0),
+	}
+}
+
+func buildGuildPlayerInfo(profile *orm.GuildMemberProfile) *protobuf.PLAYER_INFO_P60 {
+	return &protobuf.PLAYER_INFO_P60{
+		Id:      proto.Uint32(profile.CommanderID),
+		Name:    proto.String(profile.Name),
+		Lv:      proto.Uint32(profile.Level),
+		Display: buildGuildDisplay(profile),
+	}
+}
+
+func buildGuildBaseInfo(guild *orm.Guild) *protobuf.GUILD_BASE_INFO {
+	return &protobuf.GUILD_BASE_INFO{
+		Id:              proto.Uint32(guild.ID),
+		Policy:          proto.Uint32(guild.Policy),
+		Faction:         proto.Uint32(guild.Faction),
+		Name:            proto.String(guild.Name),
+		Level:           proto.Uint32(guild.Level),
+		Announce:        proto.String(guild.Announce),
+		Manifesto:       proto.String(guild.Manifesto),
+		Exp:             proto.Uint32(guild.Exp),
+		MemberCount:     proto.Uint32(guild.MemberCount),
+		ChangeFactionCd: proto.Uint32(guild.ChangeFactionCD),
+		KickLeaderCd:    proto.Uint32(guild.KickLeaderCD),
+	}
+}
+
+func buildGuildMemberInfo(client *connection.Client, profile *orm.GuildMemberProfile) *protobuf.MEMBER_INFO {
+	online := uint32(0)
+	if isCommanderOnline(client, profile.CommanderID) {
+		online = 1
+	}
+	return &protobuf.MEMBER_INFO{
+		Liveness:      proto.Uint32(profile.Liveness),
+		Duty:          proto.Uint32(profile.Duty),
+		Id:            proto.Uint32(profile.CommanderID),
+		Name:          proto.String(profile.Name),
+		Lv:            proto.Uint32(profile.Level),
+		Adv:           proto.String(profile.Manifesto),
+		Online:        proto.Uint32(online),
+		PreOnlineTime: proto.Uint32(uint32(profile.LastLogin.Unix())),
+		Display:       buildGuildDisplay(profile),
+		JoinTime:      proto.Uint32(uint32(profile.JoinedAt.Unix())),
+	}
+}
+
+func buildGuildLogInfo(entry *orm.GuildLog) *protobuf.LOG_INFO {
+	return &protobuf.LOG_INFO{
+		Cmd:    proto.Uint32(entry.Cmd),
+		Time:   proto.Uint32(uint32(entry.CreatedAt.Unix())),
+		UserId: proto.Uint32(entry.CommanderID),
+		Name:   proto.String(entry.Name),
+		Arg1:   proto.Uint32(entry.Arg1),
+	}
+}
+
+func buildGuildMembersAndLogs(client *connection.Client, guildID uint32) ([]*protobuf.MEMBER_INFO, []*protobuf.LOG_INFO, error) {
+	members, err := orm.ListGuildMembers(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs, err := orm.ListGuildLogs(guildID, guildLogCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	memberList := make([]*protobuf.MEMBER_INFO, 0, len(members))
+	for i := range members {
+		memberList = append(memberList, buildGuildMemberInfo(client, &members[i]))
+	}
+	logList := make([]*protobuf.LOG_INFO, 0, len(logs))
+	for i := range logs {
+		logList = append(logList, buildGuildLogInfo(&logs[i]))
+	}
+	return memberList, logList, nil
+}
+
+func buildGuildSimpleInfo(guild *orm.Guild) (*protobuf.GUILD_SIMPLE_INFO, error) {
+	info := &protobuf.GUILD_SIMPLE_INFO{
+		Base:     buildGuildBaseInfo(guild),
+		TechSeat: proto.Uint32(0),
+	}
+	leader, err := orm.GetGuildLeader(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	info.Leader = buildGuildPlayerInfo(leader)
+	return info, nil
+}
+
+func buildGuildSimpleList(guilds []orm.Guild) ([]*protobuf.GUILD_SIMPLE_INFO, error) {
+	list := make([]*protobuf.GUILD_SIMPLE_INFO, 0, len(guilds))
+	for i := range guilds {
+		info, err := buildGuildSimpleInfo(&guilds[i])
+		if err != nil {
+			// Leaderless guilds cannot be rendered; the client requires a leader.
+			if db.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+func emptyGuildBaseInfo() *protobuf.GUILD_BASE_INFO {
+	return buildGuildBaseInfo(&orm.Guild{})
+}
+
+// pushGuildMembers refreshes the member list and logs of every online member.
+func pushGuildMembers(client *connection.Client, guildID uint32) error {
+	memberList, logList, err := buildGuildMembersAndLogs(client, guildID)
+	if err != nil {
+		return err
+	}
+	for _, member := range memberList {
+		pushToCommander(client, member.GetId(), 60031, &protobuf.SC_60031{MemberList: memberList, LogList: logList})
+	}
+	return nil
+}
+
+// pushGuildBase sends the updated guild header to every online member.
+func pushGuildBase(client *connection.Client, guildID uint32) error {
+	guild, err := orm.GetGuild(guildID)
+	if err != nil {
+		return err
+	}
+	memberIDs, err := orm.ListGuildMemberIDs(guildID)
+	if err != nil {
+		return err
+	}
+	base := buildGuildBaseInfo(guild)
+	for _, id := range memberIDs {
+		pushToCommander(client, id, 60030, &protobuf.SC_60030{Guild: base})
+	}
+	return nil
+}
+
+// broadcastGuildMessage sends message to every online member of guildID.
+func broadcastGuildMessage(client *connection.Client, guildID uint32, packetID int, message proto.Message) error {
+	memberIDs, err := orm.ListGuildMemberIDs(guildID)
+	if err != nil {
+		return err
+	}
+	for _, id := range memberIDs {
+		pushToCommander(client, id, packetID, message)
+	}
+	return nil
+}
+
+// pushGuildRemoved tells an online commander they no longer have a guild.
+func pushGuildRemoved(client *connection.Client, commanderID uint32) {
+	pushToCommander(client, commanderID, 60030, &protobuf.SC_60030{Guild: emptyGuildBaseInfo()})
+}
+
+// pushGuildApplicationCount notifies online officers about pending applications.
+func pushGuildApplicationCount(client *connection.Client, guildID uint32) error {
+	count, err := orm.CountGuildApplications(guildID)
+	if err != nil {
+		return err
+	}
+	members, err := orm.ListGuildMembers(guildID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if !guildCanManageMembers(member.Duty) {
+			continue
+		}
+		pushToCommander(client, member.CommanderID, 60009, &protobuf.SC_60009{Count: proto.Uint32(count)})
+	}
+	return nil
+}
+
+func validGuildText(value string, maxLength int, allowEmpty bool) bool {
+	if !allowEmpty && strings.TrimSpace(value) == "" {
+		return false
+	}
+	return utf8.RuneCountInString(value) <= maxLength
+}
+
+// GuildCreate handles CS_60001.
+func GuildCreate(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60001
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60002, err
+	}
+	name := strings.TrimSpace(payload.GetName())
+	if !validGuildText(name, guildNameMaxLength, false) || !validGuildText(payload.GetManifesto(), guildManifestoMaxLength, true) {
+		return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultFailed), Id: proto.Uint32(0)})
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60002, err
+	}
+	if member != nil {
+		return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultAlreadyInGuild), Id: proto.Uint32(0)})
+	}
+	cost, err := guildSetValue("create_guild_cost", guildCreateCostFallback)
+	if err != nil {
+		return 0, 60002, err
+	}
+	if cost > 0 && !client.Commander.HasEnoughResource(guildCreateCostResource, cost) {
+		return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultNotEnough), Id: proto.Uint32(0)})
+	}
+	guild := orm.Guild{
+		Name:      name,
+		Faction:   payload.GetFaction(),
+		Policy:    payload.GetPolicy(),
+		Manifesto: payload.GetManifesto(),
+	}
+	errNotEnough := errors.New("not enough")
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.CreateGuildTx(ctx, tx, &guild, client.Commander.CommanderID, client.Commander.Name); err != nil {
+			return err
+		}
+		if cost > 0 {
+			if err := client.Commander.ConsumeResourceTx(ctx, tx, guildCreateCostResource, cost); err != nil {
+				return errNotEnough
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, orm.ErrGuildNameTaken):
+			return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultNameTaken), Id: proto.Uint32(0)})
+		case errors.Is(err, orm.ErrAlreadyInGuild):
+			return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultAlreadyInGuild), Id: proto.Uint32(0)})
+		case errors.Is(err, errNotEnough):
+			return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultNotEnough), Id: proto.Uint32(0)})
+		}
+		return 0, 60002, err
+	}
+	return client.SendMessage(60002, &protobuf.SC_60002{Result: proto.Uint32(guildResultSuccess), Id: proto.Uint32(guild.ID)})
+}
+
+// GuildApply handles CS_60005.
+func GuildApply(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60005
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60006, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60006, err
+	}
+	if member != nil {
+		return client.SendMessage(60006, &protobuf.SC_60006{Result: proto.Uint32(guildResultAlreadyInGuild)})
+	}
+	guild, err := orm.GetGuild(payload.GetId())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(60006, &protobuf.SC_60006{Result: proto.Uint32(guildResultNotFound)})
+		}
+		return 0, 60006, err
+	}
+	if guild.MemberCount >= guild.Capacity {
+		return client.SendMessage(60006, &protobuf.SC_60006{Result: proto.Uint32(guildResultFull)})
+	}
+	content := payload.GetContent()
+	if utf8.RuneCountInString(content) > guildApplyMaxLength {
+		content = string([]rune(content)[:guildApplyMaxLength])
+	}
+	if err := orm.CreateGuildApplication(guild.ID, client.Commander.CommanderID, content); err != nil {
+		if errors.Is(err, orm.ErrGuildApplicationExists) {
+			return client.SendMessage(60006, &protobuf.SC_60006{Result: proto.Uint32(guildResultFailed)})
+		}
+		return 0, 60006, err
+	}
+	if err := pushGuildApplicationCount(client, guild.ID); err != nil {
+		return 0, 60006, err
+	}
+	return client.SendMessage(60006, &protobuf.SC_60006{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GetGuildRequestsCommandResponse handles CS_60003 (pending applications).
+func GetGuildRequestsCommandResponse(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60003
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60004, err
+	}
+	response := protobuf.SC_60004{RequestList: []*protobuf.MSG_INFO_P60{}}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60004, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(60004, &response)
+	}
+	applications, err := orm.ListGuildApplications(member.GuildID)
+	if err != nil {
+		return 0, 60004, err
+	}
+	for i := range applications {
+		response.RequestList = append(response.RequestList, &protobuf.MSG_INFO_P60{
+			Timestamp: proto.Uint32(uint32(applications[i].JoinedAt.Unix())),
+			Player:    buildGuildPlayerInfo(&applications[i]),
+			Content:   proto.String(applications[i].Content),
+		})
+	}
+	return client.SendMessage(60004, &response)
+}
+
+// GuildAcceptRequest handles CS_60016.
+func GuildAcceptRequest(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60016
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60017, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60017, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	applicantID := payload.GetPlayerId()
+	applicant, err := orm.GetCommanderCoreByID(applicantID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultNotFound)})
+		}
+		return 0, 60017, err
+	}
+	if err := orm.AcceptGuildApplication(member.GuildID, applicantID, applicant.Name); err != nil {
+		switch {
+		case db.IsNotFound(err):
+			return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultNotFound)})
+		case errors.Is(err, orm.ErrGuildFull):
+			return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultFull)})
+		case errors.Is(err, orm.ErrAlreadyInGuild):
+			return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultAlreadyInGuild)})
+		}
+		return 0, 60017, err
+	}
+	if err := pushGuildBase(client, member.GuildID); err != nil {
+		return 0, 60017, err
+	}
+	if err := pushGuildMembers(client, member.GuildID); err != nil {
+		return 0, 60017, err
+	}
+	return client.SendMessage(60017, &protobuf.SC_60017{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildRejectRequest handles CS_60020.
+func GuildRejectRequest(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60020
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60021, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60021, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(60021, &protobuf.SC_60021{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	if err := orm.DeleteGuildApplication(member.GuildID, payload.GetPlayerId()); err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(60021, &protobuf.SC_60021{Result: proto.Uint32(guildResultNotFound)})
+		}
+		return 0, 60021, err
+	}
+	return client.SendMessage(60021, &protobuf.SC_60021{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildQuit handles CS_60010. A commander can only leave once they are the
+// last member, in which case the guild is dissolved.
+func GuildQuit(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60010
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60011, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60011, err
+	}
+	if member == nil {
+		return client.SendMessage(60011, &protobuf.SC_60011{Result: proto.Uint32(guildResultNotFound)})
+	}
+	if member.Duty == orm.GuildDutyCommander {
+		guild, err := orm.GetGuild(member.GuildID)
+		if err != nil {
+			return 0, 60011, err
+		}
+		if guild.MemberCount > 1 {
+			return client.SendMessage(60011, &protobuf.SC_60011{Result: proto.Uint32(guildResultNoPermission)})
+		}
+		if err := orm.DeleteGuild(member.GuildID); err != nil {
+			return 0, 60011, err
+		}
+		return client.SendMessage(60011, &protobuf.SC_60011{Result: proto.Uint32(guildResultSuccess)})
+	}
+	if err := orm.RemoveGuildMember(member.GuildID, client.Commander.CommanderID, client.Commander.Name, orm.GuildLogQuit); err != nil {
+		return 0, 60011, err
+	}
+	if err := pushGuildBase(client, member.GuildID); err != nil {
+		return 0, 60011, err
+	}
+	if err := pushGuildMembers(client, member.GuildID); err != nil {
+		return 0, 60011, err
+	}
+	return client.SendMessage(60011, &protobuf.SC_60011{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildKick handles CS_60014. Deputies may only remove lower duties.
+func GuildKick(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60014
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60015, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60015, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(60015, &protobuf.SC_60015{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	targetID := payload.GetPlayerId()
+	target, err := loadGuildMembership(targetID)
+	if err != nil {
+		return 0, 60015, err
+	}
+	if target == nil || target.GuildID != member.GuildID {
+		return client.SendMessage(60015, &protobuf.SC_60015{Result: proto.Uint32(guildResultNotFound)})
+	}
+	if target.Duty <= member.Duty {
+		return client.SendMessage(60015, &protobuf.SC_60015{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	targetCommander, err := orm.GetCommanderCoreByID(targetID)
+	if err != nil {
+		return 0, 60015, err
+	}
+	if err := orm.RemoveGuildMember(member.GuildID, targetID, targetCommander.Name, orm.GuildLogKick); err != nil {
+		return 0, 60015, err
+	}
+	pushGuildRemoved(client, targetID)
+	if err := pushGuildBase(client, member.GuildID); err != nil {
+		return 0, 60015, err
+	}
+	if err := pushGuildMembers(client, member.GuildID); err != nil {
+		return 0, 60015, err
+	}
+	return client.SendMessage(60015, &protobuf.SC_60015{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildSetDuty handles CS_60012. Only the commander can change duties;
+// assigning the commander duty transfers leadership.
+func GuildSetDuty(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60012
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60013, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60013, err
+	}
+	if member == nil || member.Duty != orm.GuildDutyCommander {
+		return client.SendMessage(60013, &protobuf.SC_60013{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	duty := payload.GetDutyId()
+	if duty < orm.GuildDutyCommander || duty > orm.GuildDutyOrdinary {
+		return client.SendMessage(60013, &protobuf.SC_60013{Result: proto.Uint32(guildResultFailed)})
+	}
+	targetID := payload.GetPlayerId()
+	if targetID == client.Commander.CommanderID {
+		return client.SendMessage(60013, &protobuf.SC_60013{Result: proto.Uint32(guildResultFailed)})
+	}
+	target, err := loadGuildMembership(targetID)
+	if err != nil {
+		return 0, 60013, err
+	}
+	if target == nil || target.GuildID != member.GuildID {
+		return client.SendMessage(60013, &protobuf.SC_60013{Result: proto.Uint32(guildResultNotFound)})
+	}
+	targetCommander, err := orm.GetCommanderCoreByID(targetID)
+	if err != nil {
+		return 0, 60013, err
+	}
+	if err := orm.SetGuildMemberDuty(member.GuildID, targetID, duty, targetCommander.Name); err != nil {
+		return 0, 60013, err
+	}
+	if err := pushGuildMembers(client, member.GuildID); err != nil {
+		return 0, 60013, err
+	}
+	return client.SendMessage(60013, &protobuf.SC_60013{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildImpeach handles CS_60022. A deputy can take over when the commander
+// has been offline for too long.
+func GuildImpeach(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60022
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60023, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60023, err
+	}
+	if member == nil || member.Duty != orm.GuildDutyDeputy {
+		return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 60023, err
+	}
+	now := time.Now()
+	if uint32(now.Unix()) < guild.KickLeaderCD {
+		return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultCooldown)})
+	}
+	leader, err := orm.GetGuildLeader(member.GuildID)
+	if err != nil {
+		return 0, 60023, err
+	}
+	if payload.GetPlayerId() != 0 && payload.GetPlayerId() != leader.CommanderID {
+		return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultFailed)})
+	}
+	if isCommanderOnline(client, leader.CommanderID) || now.Sub(leader.LastLogin) < guildImpeachOfflineLimit {
+		return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultFailed)})
+	}
+	if err := orm.ImpeachGuildLeader(member.GuildID, leader.CommanderID, client.Commander.CommanderID, client.Commander.Name, uint32(now.Unix()), uint32(now.Add(guildImpeachCD).Unix())); err != nil {
+		if errors.Is(err, orm.ErrGuildImpeachCooldown) {
+			return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultCooldown)})
+		}
+		return 0, 60023, err
+	}
+	if err := pushGuildMembers(client, member.GuildID); err != nil {
+		return 0, 60023, err
+	}
+	return client.SendMessage(60023, &protobuf.SC_60023{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildDissolve handles CS_60018.
+func GuildDissolve(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60018
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60019, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60019, err
+	}
+	if member == nil || member.Duty != orm.GuildDutyCommander {
+		return client.SendMessage(60019, &protobuf.SC_60019{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	memberIDs, err := orm.ListGuildMemberIDs(member.GuildID)
+	if err != nil {
+		return 0, 60019, err
+	}
+	if err := orm.DeleteGuild(member.GuildID); err != nil {
+		return 0, 60019, err
+	}
+	for _, id := range memberIDs {
+		if id != client.Commander.CommanderID {
+			pushGuildRemoved(client, id)
+		}
+	}
+	return client.SendMessage(60019, &protobuf.SC_60019{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildRecommendList handles CS_60024.
+func GuildRecommendList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60024
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60025, err
+	}
+	guilds, err := orm.ListRecommendedGuilds(guildListCount)
+	if err != nil {
+		return 0, 60025, err
+	}
+	list, err := buildGuildSimpleList(guilds)
+	if err != nil {
+		return 0, 60025, err
+	}
+	return client.SendMessage(60025, &protobuf.SC_60025{GuildList: list})
+}
+
+// GuildSearch handles CS_60028, by id or by name.
+func GuildSearch(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60028
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60029, err
+	}
+	keyword := strings.TrimSpace(payload.GetKeyword())
+	if keyword == "" {
+		return client.SendMessage(60029, &protobuf.SC_60029{Result: proto.Uint32(guildResultNotFound)})
+	}
+	var (
+		guilds []orm.Guild
+		err    error
+	)
+	if id, parseErr := strconv.ParseUint(keyword, 10, 32); parseErr == nil && payload.GetType() == guildSearchTypeID {
+		guild, err := orm.GetGuild(uint32(id))
+		if err != nil && !db.IsNotFound(err) {
+			return 0, 60029, err
+		}
+		if guild != nil {
+			guilds = append(guilds, *guild)
+		}
+	} else {
+		guilds, err = orm.SearchGuildsByName(keyword, guildListCount)
+		if err != nil {
+			return 0, 60029, err
+		}
+	}
+	if len(guilds) == 0 {
+		return client.SendMessage(60029, &protobuf.SC_60029{Result: proto.Uint32(guildResultNotFound)})
+	}
+	list, err := buildGuildSimpleList(guilds)
+	if err != nil {
+		return 0, 60029, err
+	}
+	return client.SendMessage(60029, &protobuf.SC_60029{Result: proto.Uint32(guildResultSuccess), Guild: list})
+}
+
+// GuildModifyInfo handles CS_60026. Name, faction and policy are reserved
+// to the commander; officers can edit the manifesto and announcement.
+func GuildModifyInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60026
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60027, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60027, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 60027, err
+	}
+	leaderOnly := payload.GetType() == guildModifyName || payload.GetType() == guildModifyFaction || payload.GetType() == guildModifyPolicy
+	if leaderOnly && member.Duty != orm.GuildDutyCommander {
+		return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	now := time.Now()
+	switch payload.GetType() {
+	case guildModifyName:
+		name := strings.TrimSpace(payload.GetStr())
+		if !validGuildText(name, guildNameMaxLength, false) {
+			return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultFailed)})
+		}
+		guild.Name = name
+	case guildModifyFaction:
+		if uint32(now.Unix()) < guild.ChangeFactionCD {
+			return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultCooldown)})
+		}
+		guild.Faction = payload.GetInt()
+		guild.ChangeFactionCD = uint32(now.Add(guildFactionChangeCD).Unix())
+	case guildModifyPolicy:
+		guild.Policy = payload.GetInt()
+	case guildModifyManifesto:
+		if !validGuildText(payload.GetStr(), guildManifestoMaxLength, true) {
+			return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultFailed)})
+		}
+		guild.Manifesto = payload.GetStr()
+	case guildModifyAnnounce:
+		if !validGuildText(payload.GetStr(), guildManifestoMaxLength, true) {
+			return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultFailed)})
+		}
+		guild.Announce = payload.GetStr()
+	default:
+		return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultFailed)})
+	}
+	if err := orm.UpdateGuild(guild); err != nil {
+		if errors.Is(err, orm.ErrGuildNameTaken) {
+			return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultNameTaken)})
+		}
+		return 0, 60027, err
+	}
+	if err := pushGuildBase(client, guild.ID); err != nil {
+		return 0, 60027, err
+	}
+	return client.SendMessage(60027, &protobuf.SC_60027{Result: proto.Uint32(guildResultSuccess)})
+}
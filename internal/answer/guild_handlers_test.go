@@ -0,0 +1,180 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func setupGuildTestClients(t *testing.T) (*connection.Client, *connection.Client) {
+	t.Helper()
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.Guild{})
+	seedConfigEntry(t, guildSetConfigCategory, "create_guild_cost", `{"key":"create_guild_cost","key_value":0}`)
+	otherID := client.Commander.CommanderID + 100000
+	execAnswerTestSQLT(t, "DELETE FROM commanders WHERE commander_id = $1", int64(otherID))
+	if err := orm.CreateCommanderRoot(otherID, 2, "Guild Tester", 0, 0); err != nil {
+		t.Fatalf("create guild commander: %v", err)
+	}
+	other := orm.Commander{CommanderID: otherID}
+	if err := other.Load(); err != nil {
+		t.Fatalf("load guild commander: %v", err)
+	}
+	t.Cleanup(func() {
+		execAnswerTestSQLT(t, "DELETE FROM commanders WHERE commander_id = $1", int64(otherID))
+	})
+	return client, &connection.Client{Commander: &other}
+}
+
+func createTestGuild(t *testing.T, client *connection.Client, name string) uint32 {
+	t.Helper()
+	payload := marshalPacketRequest(t, &protobuf.CS_60001{
+		Faction:   proto.Uint32(1),
+		Policy:    proto.Uint32(1),
+		Name:      proto.String(name),
+		Manifesto: proto.String("welcome"),
+	})
+	if _, _, err := GuildCreate(&payload, client); err != nil {
+		t.Fatalf("GuildCreate failed: %v", err)
+	}
+	response := &protobuf.SC_60002{}
+	decodePacketMessage(t, client, 60002, response)
+	client.Buffer.Reset()
+	if response.GetResult() != guildResultSuccess || response.GetId() == 0 {
+		t.Fatalf("unexpected create response: result=%d id=%d", response.GetResult(), response.GetId())
+	}
+	return response.GetId()
+}
+
+func TestGuildApplyAcceptKickFlow(t *testing.T) {
+	leader, applicant := setupGuildTestClients(t)
+	guildID := createTestGuild(t, leader, "Answer Guild")
+
+	payload := marshalPacketRequest(t, &protobuf.CS_60005{Id: proto.Uint32(guildID), Content: proto.String("hi")})
+	if _, _, err := GuildApply(&payload, applicant); err != nil {
+		t.Fatalf("GuildApply failed: %v", err)
+	}
+	applyResponse := &protobuf.SC_60006{}
+	decodePacketMessage(t, applicant, 60006, applyResponse)
+	applicant.Buffer.Reset()
+	if applyResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", applyResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_60003{Id: proto.Uint32(guildID)})
+	if _, _, err := GetGuildRequestsCommandResponse(&payload, leader); err != nil {
+		t.Fatalf("GetGuildRequestsCommandResponse failed: %v", err)
+	}
+	requests := &protobuf.SC_60004{}
+	decodePacketMessage(t, leader, 60004, requests)
+	leader.Buffer.Reset()
+	if len(requests.GetRequestList()) != 1 || requests.GetRequestList()[0].GetContent() != "hi" {
+		t.Fatalf("unexpected request list: %+v", requests.GetRequestList())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_60016{PlayerId: proto.Uint32(applicant.Commander.CommanderID)})
+	if _, _, err := GuildAcceptRequest(&payload, applicant); err != nil {
+		t.Fatalf("GuildAcceptRequest failed: %v", err)
+	}
+	acceptResponse := &protobuf.SC_60017{}
+	decodePacketMessage(t, applicant, 60017, acceptResponse)
+	applicant.Buffer.Reset()
+	if acceptResponse.GetResult() != guildResultNoPermission {
+		t.Fatalf("expected outsider accept to be rejected, got %d", acceptResponse.GetResult())
+	}
+
+	if _, _, err := GuildAcceptRequest(&payload, leader); err != nil {
+		t.Fatalf("GuildAcceptRequest failed: %v", err)
+	}
+	decodePacketMessage(t, leader, 60017, acceptResponse)
+	leader.Buffer.Reset()
+	if acceptResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", acceptResponse.GetResult())
+	}
+	member, err := orm.GetGuildMember(applicant.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("load membership: %v", err)
+	}
+	if member.GuildID != guildID || member.Duty != orm.GuildDutyOrdinary {
+		t.Fatalf("unexpected membership: %+v", member)
+	}
+
+	empty := []byte{}
+	if _, _, err := CommanderGuildData(&empty, applicant); err != nil {
+		t.Fatalf("CommanderGuildData failed: %v", err)
+	}
+	guildData := &protobuf.SC_60000{}
+	decodePacketMessage(t, applicant, 60000, guildData)
+	applicant.Buffer.Reset()
+	if guildData.GetGuild().GetBase().GetId() != guildID || len(guildData.GetGuild().GetMember()) != 2 {
+		t.Fatalf("unexpected guild data: %+v", guildData.GetGuild())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_60014{PlayerId: proto.Uint32(applicant.Commander.CommanderID)})
+	if _, _, err := GuildKick(&payload, leader); err != nil {
+		t.Fatalf("GuildKick failed: %v", err)
+	}
+	kickResponse := &protobuf.SC_60015{}
+	decodePacketMessage(t, leader, 60015, kickResponse)
+	leader.Buffer.Reset()
+	if kickResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", kickResponse.GetResult())
+	}
+	if _, err := orm.GetGuildMember(applicant.Commander.CommanderID); !db.IsNotFound(err) {
+		t.Fatalf("expected kicked member to be removed, got %v", err)
+	}
+}
+
+func TestGuildCreateRejectsDuplicateName(t *testing.T) {
+	first, second := setupGuildTestClients(t)
+	createTestGuild(t, first, "Taken Guild")
+
+	payload := marshalPacketRequest(t, &protobuf.CS_60001{
+		Faction:   proto.Uint32(1),
+		Policy:    proto.Uint32(1),
+		Name:      proto.String("taken guild"),
+		Manifesto: proto.String(""),
+	})
+	if _, _, err := GuildCreate(&payload, second); err != nil {
+		t.Fatalf("GuildCreate failed: %v", err)
+	}
+	response := &protobuf.SC_60002{}
+	decodePacketMessage(t, second, 60002, response)
+	if response.GetResult() != guildResultNameTaken {
+		t.Fatalf("expected name taken result, got %d", response.GetResult())
+	}
+}
+
+func TestGuildQuitDissolvesWhenLeaderIsAlone(t *testing.T) {
+	leader, _ := setupGuildTestClients(t)
+	guildID := createTestGuild(t, leader, "Solo Guild")
+
+	payload := marshalPacketRequest(t, &protobuf.CS_60010{Id: proto.Uint32(guildID)})
+	if _, _, err := GuildQuit(&payload, leader); err != nil {
+		t.Fatalf("GuildQuit failed: %v", err)
+	}
+	response := &protobuf.SC_60011{}
+	decodePacketMessage(t, leader, 60011, response)
+	if response.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", response.GetResult())
+	}
+	if _, err := orm.GetGuild(guildID); !db.IsNotFound(err) {
+		t.Fatalf("expected guild to be dissolved, got %v", err)
+	}
+}
+
+func TestGuildCreateChargesCostWithTheGuild(t *testing.T) {
+	client, _ := setupGuildTestClients(t)
+	seedConfigEntry(t, guildSetConfigCategory, "create_guild_cost", `{"key":"create_guild_cost","key_value":100}`)
+	if err := client.Commander.SetResource(guildCreateCostResource, 150); err != nil {
+		t.Fatalf("seed resource: %v", err)
+	}
+	createTestGuild(t, client, "Paid Guild")
+	if client.Commander.GetResourceCount(guildCreateCostResource) != 50 {
+		t.Fatalf("expected the cost to be charged, got %d left", client.Commander.GetResourceCount(guildCreateCostResource))
+	}
+}
@@ -0,0 +1,489 @@
+package answer
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	guildOperationConfigCategory = "ShareCfg/guild_operation_template.json"
+	guildBaseEventConfigCategory = "ShareCfg/guild_base_event.json"
+	guildBossEventConfigCategory = "ShareCfg/guild_boss_event.json"
+
+	guildEventNodePending = uint32(0)
+	guildEventNodeCleared = uint32(1)
+
+	guildEventShipLimit   = 6
+	guildAssaultShipLimit = 2
+)
+
+// guildOperationTemplate describes an operation: the capital it costs, the
+// base events unlocked in order and the boss fought once they are cleared.
+type guildOperationTemplate struct {
+	ID        uint32   `json:"id"`
+	Consume   uint32   `json:"consume"`
+	EventList []uint32 `json:"event_list"`
+	BossID    uint32   `json:"boss_id"`
+}
+
+// guildBaseEventTemplate is a base event; its nodes are cleared evenly over
+// time seconds once ships are dispatched.
+type guildBaseEventTemplate struct {
+	ID   uint32   `json:"id"`
+	Node []uint32 `json:"node"`
+	Time uint32   `json:"time"`
+}
+
+type guildBossEventTemplate struct {
+	ID           uint32 `json:"id"`
+	HP           uint32 `json:"hp"`
+	ExpeditionID uint32 `json:"expedition_id"`
+}
+
+func loadGuildOperationConfig(operationID uint32) (*guildOperationTemplate, error) {
+	cached, err := gamedata.Get[guildOperationTemplate](gamedata.Default, guildOperationConfigCategory, strconv.FormatUint(uint64(operationID), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := *cached
+	return &config, nil
+}
+
+func loadGuildBaseEventConfig(eventID uint32) (*guildBaseEventTemplate, error) {
+	cached, err := gamedata.Get[guildBaseEventTemplate](gamedata.Default, guildBaseEventConfigCategory, strconv.FormatUint(uint64(eventID), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := *cached
+	return &config, nil
+}
+
+func loadGuildBossEventConfig(bossID uint32) (*guildBossEventTemplate, error) {
+	cached, err := gamedata.Get[guildBossEventTemplate](gamedata.Default, guildBossEventConfigCategory, strconv.FormatUint(uint64(bossID), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := *cached
+	return &config, nil
+}
+
+// guildEventNodes reports the node states of a base event at now.
+func guildEventNodes(event *orm.GuildOperationEvent, template *guildBaseEventTemplate, now uint32) []*protobuf.EVENT_NODE {
+	nodes := make([]*protobuf.EVENT_NODE, 0, len(template.Node))
+	cleared := 0
+	if event.StartTime != 0 {
+		switch {
+		case now >= event.CompleteTime:
+			cleared = len(template.Node)
+		case event.CompleteTime > event.StartTime:
+			cleared = int(uint64(now-event.StartTime) * uint64(len(template.Node)) / uint64(event.CompleteTime-event.StartTime))
+		}
+	}
+	for i, nodeID := range template.Node {
+		status := guildEventNodePending
+		if i < cleared {
+			status = guildEventNodeCleared
+		}
+		nodes = append(nodes, &protobuf.EVENT_NODE{
+			Position: proto.Uint32(uint32(i + 1)),
+			NodeId:   proto.Uint32(nodeID),
+			Status:   proto.Uint32(status),
+		})
+	}
+	return nodes
+}
+
+func buildGuildShipInEvent(ship orm.GuildOperationShip) *protobuf.SHIP_IN_EVENT {
+	entry := &protobuf.SHIP_IN_EVENT{
+		UserId:     proto.Uint32(ship.CommanderID),
+		ShipId:     proto.Uint32(ship.ShipID),
+		TemplateId: proto.Uint32(0),
+		Skin:       proto.Uint32(0),
+	}
+	if owned, err := orm.GetOwnedShipByOwnerAndID(ship.CommanderID, ship.ShipID); err == nil {
+		entry.TemplateId = proto.Uint32(owned.ShipID)
+		entry.Skin = proto.Uint32(owned.SkinID)
+	}
+	return entry
+}
+
+func buildGuildEventBoss(op *orm.GuildOperation) *protobuf.EVENT_BOSS {
+	return &protobuf.EVENT_BOSS{
+		BossId: proto.Uint32(op.BossID),
+		Damage: proto.Uint32(op.BossDamage),
+		Hp:     proto.Uint32(op.BossHP),
+	}
+}
+
+// buildGuildCurrentOperation assembles the operation state as seen by
+// commanderID.
+func buildGuildCurrentOperation(guild *orm.Guild, op *orm.GuildOperation, commanderID uint32, now uint32) (*protobuf.CURRENT_OPERATION, error) {
+	events, err := orm.ListGuildOperationEvents(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	ships, err := orm.ListGuildOperationShips(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	damages, err := orm.ListGuildOperationDamages(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	shipsByEvent := make(map[uint32][]*protobuf.SHIP_IN_EVENT)
+	joined := make(map[uint32]bool)
+	for _, ship := range ships {
+		shipsByEvent[ship.EventID] = append(shipsByEvent[ship.EventID], buildGuildShipInEvent(ship))
+		if ship.CommanderID == commanderID {
+			joined[ship.EventID] = true
+		}
+	}
+	participant := len(joined) > 0
+	for _, damage := range damages {
+		if damage.CommanderID == commanderID {
+			participant = true
+		}
+	}
+	baseEvents := make([]*protobuf.EVENT_BASE, 0, len(events))
+	for i := range events {
+		event := &events[i]
+		template, err := loadGuildBaseEventConfig(event.EventID)
+		if err != nil {
+			return nil, err
+		}
+		nodes := []*protobuf.EVENT_NODE{}
+		if template != nil {
+			nodes = guildEventNodes(event, template, now)
+		}
+		baseEvents = append(baseEvents, &protobuf.EVENT_BASE{
+			EventId:      proto.Uint32(event.EventID),
+			Position:     proto.Uint32(event.Position),
+			StartTime:    proto.Uint32(event.StartTime),
+			CompleteTime: proto.Uint32(event.CompleteTime),
+			Shipinevent:  shipsByEvent[event.EventID],
+			Eventnodes:   nodes,
+			Efficiency:   proto.Uint32(0),
+		})
+	}
+	operation := &protobuf.CURRENT_OPERATION{
+		OperationId:   proto.Uint32(op.OperationID),
+		StartTime:     proto.Uint32(op.StartTime),
+		BaseEvents:    baseEvents,
+		DailyCount:    proto.Uint32(guild.ActiveEventCnt),
+		JoinTimes:     proto.Uint32(uint32(len(joined))),
+		IsParticipant: proto.Uint32(boolToUint32(participant)),
+	}
+	if op.BossID != 0 {
+		operation.BossEvent = buildGuildEventBoss(op)
+	}
+	return operation, nil
+}
+
+// guildOperationFinished reports whether a new operation may replace op: the
+// boss is down, or there is no boss and every event completed.
+func guildOperationFinished(op *orm.GuildOperation, now uint32) (bool, error) {
+	if op.BossID != 0 {
+		return op.BossDamage >= op.BossHP, nil
+	}
+	events, err := orm.ListGuildOperationEvents(op.GuildID)
+	if err != nil {
+		return false, err
+	}
+	for _, event := range events {
+		if event.StartTime == 0 || event.CompleteTime > now {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GuildStartOperation handles CS_61001. Only officers can start an
+// operation and the guild pays its capital cost.
+func GuildStartOperation(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_61001
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61002, err
+	}
+	response := protobuf.SC_61002{Result: proto.Uint32(guildResultFailed)}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61002, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61002, &response)
+	}
+	if !guildCanManageMembers(member.Duty) {
+		response.Result = proto.Uint32(guildResultNoPermission)
+		return client.SendMessage(61002, &response)
+	}
+	template, err := loadGuildOperationConfig(payload.GetChapterId())
+	if err != nil {
+		return 0, 61002, err
+	}
+	if template == nil {
+		return client.SendMessage(61002, &response)
+	}
+	now := uint32(time.Now().Unix())
+	current, err := orm.GetGuildOperation(member.GuildID)
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 61002, err
+	}
+	if current != nil {
+		finished, err := guildOperationFinished(current, now)
+		if err != nil {
+			return 0, 61002, err
+		}
+		if !finished {
+			return client.SendMessage(61002, &response)
+		}
+	}
+	op := orm.GuildOperation{
+		GuildID:     member.GuildID,
+		OperationID: template.ID,
+		StartTime:   now,
+		BossID:      template.BossID,
+	}
+	if template.BossID != 0 {
+		boss, err := loadGuildBossEventConfig(template.BossID)
+		if err != nil {
+			return 0, 61002, err
+		}
+		if boss == nil {
+			return client.SendMessage(61002, &response)
+		}
+		op.BossHP = boss.HP
+	}
+	events := make([]orm.GuildOperationEvent, 0, len(template.EventList))
+	for i, eventID := range template.EventList {
+		events = append(events, orm.GuildOperationEvent{EventID: eventID, Position: uint32(i + 1)})
+	}
+	if err := orm.StartGuildOperation(&op, events, client.Commander.CommanderID, client.Commander.Name, template.Consume); err != nil {
+		if errors.Is(err, orm.ErrGuildCapitalNotEnough) {
+			response.Result = proto.Uint32(guildResultNotEnough)
+			return client.SendMessage(61002, &response)
+		}
+		return 0, 61002, err
+	}
+	response.Result = proto.Uint32(guildResultSuccess)
+	return client.SendMessage(61002, &response)
+}
+
+// GuildJoinEvent handles CS_61007, dispatching ships to a base event of the
+// running operation.
+func GuildJoinEvent(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_61007
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61008, err
+	}
+	response := protobuf.SC_61008{Result: proto.Uint32(guildResultFailed)}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61008, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61008, &response)
+	}
+	shipIDs := payload.GetShipIds()
+	if len(shipIDs) == 0 || len(shipIDs) > guildEventShipLimit {
+		return client.SendMessage(61008, &response)
+	}
+	for _, shipID := range shipIDs {
+		if _, err := orm.GetOwnedShipByOwnerAndID(client.Commander.CommanderID, shipID); err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(61008, &response)
+			}
+			return 0, 61008, err
+		}
+	}
+	template, err := loadGuildBaseEventConfig(payload.GetEventTid())
+	if err != nil {
+		return 0, 61008, err
+	}
+	if template == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61008, &response)
+	}
+	now := uint32(time.Now().Unix())
+	err = orm.JoinGuildOperationEvent(member.GuildID, template.ID, client.Commander.CommanderID, shipIDs, now, now+template.Time)
+	switch {
+	case db.IsNotFound(err):
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61008, &response)
+	case errors.Is(err, orm.ErrGuildShipDispatched):
+		return client.SendMessage(61008, &response)
+	case err != nil:
+		return 0, 61008, err
+	}
+	response.Result = proto.Uint32(guildResultSuccess)
+	return client.SendMessage(61008, &response)
+}
+
+// GuildSetAssaultFleet handles CS_61003, replacing the ships the commander
+// lends to guild members.
+func GuildSetAssaultFleet(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_61003
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61004, err
+	}
+	response := protobuf.SC_61004{Result: proto.Uint32(guildResultFailed)}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61004, err
+	}
+	if member == nil {
+		response.Result = proto.Uint32(guildResultNotFound)
+		return client.SendMessage(61004, &response)
+	}
+	if len(payload.GetShipIds()) > guildAssaultShipLimit {
+		return client.SendMessage(61004, &response)
+	}
+	ships := make([]orm.GuildAssaultShip, 0, len(payload.GetShipIds()))
+	seen := make(map[uint32]bool)
+	for _, entry := range payload.GetShipIds() {
+		if entry.GetPos() == 0 || entry.GetPos() > guildAssaultShipLimit || seen[entry.GetPos()] {
+			return client.SendMessage(61004, &response)
+		}
+		seen[entry.GetPos()] = true
+		if entry.GetShipId() == 0 {
+			continue
+		}
+		if _, err := orm.GetOwnedShipByOwnerAndID(client.Commander.CommanderID, entry.GetShipId()); err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(61004, &response)
+			}
+			return 0, 61004, err
+		}
+		ships = append(ships, orm.GuildAssaultShip{Pos: entry.GetPos(), ShipID: entry.GetShipId()})
+	}
+	if err := orm.SetGuildAssaultShips(client.Commander.CommanderID, ships); err != nil {
+		return 0, 61004, err
+	}
+	response.Result = proto.Uint32(guildResultSuccess)
+	return client.SendMessage(61004, &response)
+}
+
+// GuildGetBossInfo handles CS_61027.
+func GuildGetBossInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_61027
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61028, err
+	}
+	response := protobuf.SC_61028{
+		Result:    proto.Uint32(guildResultNotFound),
+		BossEvent: buildGuildEventBoss(&orm.GuildOperation{}),
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61028, err
+	}
+	if member == nil {
+		return client.SendMessage(61028, &response)
+	}
+	op, err := orm.GetGuildOperation(member.GuildID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(61028, &response)
+		}
+		return 0, 61028, err
+	}
+	if op.BossID == 0 {
+		return client.SendMessage(61028, &response)
+	}
+	response.Result = proto.Uint32(guildResultSuccess)
+	response.BossEvent = buildGuildEventBoss(op)
+	return client.SendMessage(61028, &response)
+}
+
+// GuildBossRank handles CS_61029 with the damage each member dealt to the
+// current boss.
+func GuildBossRank(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_61029
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 61030, err
+	}
+	response := protobuf.SC_61030{List: []*protobuf.RANK_INFO_P61{}}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 61030, err
+	}
+	if member == nil {
+		return client.SendMessage(61030, &response)
+	}
+	damages, err := orm.ListGuildOperationDamages(member.GuildID)
+	if err != nil {
+		return 0, 61030, err
+	}
+	for _, damage := range damages {
+		response.List = append(response.List, &protobuf.RANK_INFO_P61{
+			UserId: proto.Uint32(damage.CommanderID),
+			Damage: proto.Uint32(damage.Damage),
+		})
+	}
+	return client.SendMessage(61030, &response)
+}
+
+// recordGuildBossDamage adds the damage of a guild boss battle to the
+// operation of the commander's guild. Battles on another stage than the one
+// of the current boss are not credited.
+func recordGuildBossDamage(client *connection.Client, stageID uint32, stats map[uint32]*protobuf.STATISTICSINFO) error {
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil || member == nil {
+		return err
+	}
+	current, err := orm.GetGuildOperation(member.GuildID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if current.BossID == 0 {
+		return nil
+	}
+	boss, err := loadGuildBossEventConfig(current.BossID)
+	if err != nil {
+		return err
+	}
+	if boss == nil || boss.ExpeditionID == 0 || boss.ExpeditionID != stageID {
+		return nil
+	}
+	damage := uint64(0)
+	for _, entry := range stats {
+		damage += uint64(entry.GetDamageCaused())
+	}
+	if damage == 0 {
+		return nil
+	}
+	op, err := orm.AddGuildBossDamage(member.GuildID, client.Commander.CommanderID, uint32(min(damage, math.MaxUint32)))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return broadcastGuildMessage(client, member.GuildID, 61028, &protobuf.SC_61028{
+		Result:    proto.Uint32(guildResultSuccess),
+		BossEvent: buildGuildEventBoss(op),
+	})
+}
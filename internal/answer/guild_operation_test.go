@@ -0,0 +1,190 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func seedGuildOperationConfig(t *testing.T) {
+	t.Helper()
+	seedConfigEntry(t, guildOperationConfigCategory, "1", `{"id":1,"consume":40,"event_list":[11,12],"boss_id":7}`)
+	seedConfigEntry(t, guildBaseEventConfigCategory, "11", `{"id":11,"node":[101,102,103],"time":3600}`)
+	seedConfigEntry(t, guildBaseEventConfigCategory, "12", `{"id":12,"node":[201],"time":600}`)
+	seedConfigEntry(t, guildBossEventConfigCategory, "7", `{"id":7,"hp":1000,"expedition_id":7001}`)
+}
+
+func TestGuildOperationLifecycle(t *testing.T) {
+	leader, member := setupGuildTestClients(t)
+	clearTable(t, &orm.BattleSession{})
+	clearTable(t, &orm.OwnedShip{})
+	seedGuildOperationConfig(t)
+	guildID := createTestGuild(t, leader, "Operation Guild")
+	execAnswerTestSQLT(t, "INSERT INTO owned_ships (id, owner_id, ship_id, level, max_level, energy, create_time, change_name_timestamp) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())", int64(101), int64(leader.Commander.CommanderID), int64(1001), int64(1), int64(100), int64(150))
+	if err := orm.AddGuildMember(guildID, member.Commander.CommanderID, orm.GuildDutyOrdinary, member.Commander.Name); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_61001{ChapterId: proto.Uint32(1)})
+	if _, _, err := GuildStartOperation(&payload, member); err != nil {
+		t.Fatalf("GuildStartOperation failed: %v", err)
+	}
+	startResponse := &protobuf.SC_61002{}
+	decodePacketMessage(t, member, 61002, startResponse)
+	member.Buffer.Reset()
+	if startResponse.GetResult() != guildResultNoPermission {
+		t.Fatalf("expected member start to be refused, got %d", startResponse.GetResult())
+	}
+	if _, _, err := GuildStartOperation(&payload, leader); err != nil {
+		t.Fatalf("GuildStartOperation failed: %v", err)
+	}
+	decodePacketMessage(t, leader, 61002, startResponse)
+	leader.Buffer.Reset()
+	if startResponse.GetResult() != guildResultNotEnough {
+		t.Fatalf("expected capital shortage, got %d", startResponse.GetResult())
+	}
+	if err := orm.AddGuildCapital(guildID, 50, 0, "admin", orm.GuildCapitalLogAdmin, nil); err != nil {
+		t.Fatalf("add capital: %v", err)
+	}
+	if _, _, err := GuildStartOperation(&payload, leader); err != nil {
+		t.Fatalf("GuildStartOperation failed: %v", err)
+	}
+	decodePacketMessage(t, leader, 61002, startResponse)
+	leader.Buffer.Reset()
+	if startResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", startResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61007{EventTid: proto.Uint32(11), ShipIds: []uint32{101}})
+	if _, _, err := GuildJoinEvent(&payload, leader); err != nil {
+		t.Fatalf("GuildJoinEvent failed: %v", err)
+	}
+	joinResponse := &protobuf.SC_61008{}
+	decodePacketMessage(t, leader, 61008, joinResponse)
+	leader.Buffer.Reset()
+	if joinResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", joinResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61005{Type: proto.Uint32(0)})
+	if _, _, err := GuildGetActivationEventCommandResponse(&payload, leader); err != nil {
+		t.Fatalf("GuildGetActivationEventCommandResponse failed: %v", err)
+	}
+	eventResponse := &protobuf.SC_61006{}
+	decodePacketMessage(t, leader, 61006, eventResponse)
+	leader.Buffer.Reset()
+	operation := eventResponse.GetOperation()
+	if operation.GetOperationId() != 1 || len(operation.GetBaseEvents()) != 2 || operation.GetIsParticipant() != 1 {
+		t.Fatalf("unexpected operation: %+v", operation)
+	}
+	first := operation.GetBaseEvents()[0]
+	if first.GetEventId() != 11 || len(first.GetShipinevent()) != 1 || first.GetShipinevent()[0].GetTemplateId() != 1001 || len(first.GetEventnodes()) != 3 {
+		t.Fatalf("unexpected base event: %+v", first)
+	}
+	if operation.GetBossEvent().GetHp() != 1000 {
+		t.Fatalf("unexpected boss: %+v", operation.GetBossEvent())
+	}
+
+	otherStage := map[uint32]*protobuf.STATISTICSINFO{101: {ShipId: proto.Uint32(101), DamageCaused: proto.Uint32(500)}}
+	if err := recordGuildBossDamage(leader, 7002, otherStage); err != nil {
+		t.Fatalf("record damage on another stage: %v", err)
+	}
+	if op, err := orm.GetGuildOperation(guildID); err != nil || op.BossDamage != 0 {
+		t.Fatalf("expected a battle on another stage not to damage the boss, got %+v %v", op, err)
+	}
+
+	session := orm.BattleSession{CommanderID: leader.Commander.CommanderID, System: battleSystemGuild, StageID: 7001, Key: 1, ShipIDs: orm.ToInt64List([]uint32{101}), CreatedAt: time.Now().Add(-time.Minute)}
+	if err := orm.UpsertBattleSession(&session); err != nil {
+		t.Fatalf("create battle session: %v", err)
+	}
+	if err := leader.Commander.Load(); err != nil {
+		t.Fatalf("reload commander: %v", err)
+	}
+	payload = marshalPacketRequest(t, &protobuf.CS_40003{
+		System:    proto.Uint32(battleSystemGuild),
+		Data:      proto.Uint32(7001),
+		Key:       proto.Uint32(1),
+		Score:     proto.Uint32(4),
+		TotalTime: proto.Uint32(60),
+		Statistics: []*protobuf.STATISTICSINFO{
+			{ShipId: proto.Uint32(101), DamageCause: proto.Uint32(0), DamageCaused: proto.Uint32(350), HpRest: proto.Uint32(100), MaxDamageOnce: proto.Uint32(200), ShipGearScore: proto.Uint32(0)},
+		},
+		BotPercentage:  proto.Uint32(0),
+		ExtraParam:     proto.Uint32(0),
+		AutoBefore:     proto.Uint32(0),
+		AutoSwitchTime: proto.Uint32(0),
+		AutoAfter:      proto.Uint32(0),
+	})
+	if _, _, err := FinishStage(&payload, leader); err != nil {
+		t.Fatalf("FinishStage failed: %v", err)
+	}
+	leader.Buffer.Reset()
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61027{Type: proto.Uint32(0)})
+	if _, _, err := GuildGetBossInfo(&payload, member); err != nil {
+		t.Fatalf("GuildGetBossInfo failed: %v", err)
+	}
+	bossResponse := &protobuf.SC_61028{}
+	decodePacketMessage(t, member, 61028, bossResponse)
+	member.Buffer.Reset()
+	if bossResponse.GetResult() != guildResultSuccess || bossResponse.GetBossEvent().GetDamage() != 350 {
+		t.Fatalf("unexpected boss info: %+v", bossResponse)
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61029{Type: proto.Uint32(0)})
+	if _, _, err := GuildBossRank(&payload, member); err != nil {
+		t.Fatalf("GuildBossRank failed: %v", err)
+	}
+	rankResponse := &protobuf.SC_61030{}
+	decodePacketMessage(t, member, 61030, rankResponse)
+	member.Buffer.Reset()
+	if len(rankResponse.GetList()) != 1 || rankResponse.GetList()[0].GetUserId() != leader.Commander.CommanderID {
+		t.Fatalf("unexpected boss rank: %+v", rankResponse.GetList())
+	}
+}
+
+func TestGuildAssaultFleet(t *testing.T) {
+	leader, member := setupGuildTestClients(t)
+	clearTable(t, &orm.OwnedShip{})
+	guildID := createTestGuild(t, leader, "Assault Guild")
+	execAnswerTestSQLT(t, "INSERT INTO owned_ships (id, owner_id, ship_id, level, max_level, energy, create_time, change_name_timestamp) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())", int64(201), int64(leader.Commander.CommanderID), int64(1001), int64(1), int64(100), int64(150))
+	if err := orm.AddGuildMember(guildID, member.Commander.CommanderID, orm.GuildDutyOrdinary, member.Commander.Name); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_61003{ShipIds: []*protobuf.SHIPID_POS{{Pos: proto.Uint32(1), ShipId: proto.Uint32(201)}}})
+	if _, _, err := GuildSetAssaultFleet(&payload, leader); err != nil {
+		t.Fatalf("GuildSetAssaultFleet failed: %v", err)
+	}
+	setResponse := &protobuf.SC_61004{}
+	decodePacketMessage(t, leader, 61004, setResponse)
+	leader.Buffer.Reset()
+	if setResponse.GetResult() != guildResultSuccess {
+		t.Fatalf("expected result 0, got %d", setResponse.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61009{Type: proto.Uint32(0)})
+	if _, _, err := GetMyAssaultFleetCommandResponse(&payload, leader); err != nil {
+		t.Fatalf("GetMyAssaultFleetCommandResponse failed: %v", err)
+	}
+	mine := &protobuf.SC_61010{}
+	decodePacketMessage(t, leader, 61010, mine)
+	leader.Buffer.Reset()
+	if len(mine.GetPersonShips()) != 1 || mine.GetPersonShips()[0].GetShip().GetId() != 201 {
+		t.Fatalf("unexpected assault fleet: %+v", mine.GetPersonShips())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_61011{Type: proto.Uint32(0)})
+	if _, _, err := GuildGetAssaultFleetCommandResponse(&payload, member); err != nil {
+		t.Fatalf("GuildGetAssaultFleetCommandResponse failed: %v", err)
+	}
+	guildFleet := &protobuf.SC_61012{}
+	decodePacketMessage(t, member, 61012, guildFleet)
+	member.Buffer.Reset()
+	if len(guildFleet.GetShips()) != 1 || guildFleet.GetShips()[0].GetUserId() != leader.Commander.CommanderID {
+		t.Fatalf("unexpected guild assault ships: %+v", guildFleet.GetShips())
+	}
+}
@@ -14,8 +14,19 @@ func GuildSendMessage(buffer *[]byte, client *connection.Client) (int, int, erro
 	if err := proto.Unmarshal(*buffer, &payload); err != nil {
 		return 0, 60008, err
 	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 60008, err
+	}
+	if member == nil {
+		return 0, 60008, nil
+	}
+	memberIDs, err := orm.ListGuildMemberIDs(member.GuildID)
+	if err != nil {
+		return 0, 60008, err
+	}
 	now := time.Now().UTC()
-	entry, err := orm.CreateGuildChatMessage(guildChatPlaceholderID, client.Commander.CommanderID, payload.GetChat(), now)
+	entry, err := orm.CreateGuildChatMessage(member.GuildID, client.Commander.CommanderID, payload.GetChat(), now)
 	if err != nil {
 		return 0, 60008, err
 	}
@@ -25,6 +36,8 @@ func GuildSendMessage(buffer *[]byte, client *connection.Client) (int, int, erro
 		Time:    proto.Uint32(uint32(entry.SentAt.Unix())),
 	}
 	packet := &protobuf.SC_60008{Chat: chat}
-	client.Server.BroadcastGuildChat(packet)
+	if server := clientServer(client); server != nil {
+		server.BroadcastGuildChat(memberIDs, packet)
+	}
 	return 0, 60008, nil
 }
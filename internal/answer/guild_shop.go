@@ -1,12 +1,17 @@
 package answer
 
 import (
+	"context"
+	"errors"
+	"math"
 	"time"
 
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
 	"github.com/ggmolly/belfast/internal/guildshop"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -14,6 +19,12 @@ const (
 	guildShopGetShop       = 0
 	guildShopAutoRefresh   = 1
 	guildShopManualRefresh = 2
+
+	guildShopPurchaseResultOK           = uint32(0)
+	guildShopPurchaseResultInvalid      = uint32(1)
+	guildShopPurchaseResultInsufficient = uint32(2)
+	guildShopPurchaseResultStock        = uint32(3)
+	guildShopPurchaseResultDBError      = uint32(6)
 )
 
 func GetGuildShop(buffer *[]byte, client *connection.Client) (int, int, error) {
@@ -79,3 +90,131 @@ func buildGuildShopGoods(goods []orm.GuildShopGood) []*protobuf.GOODS_INFO_P60 {
 	}
 	return list
 }
+
+// GuildShopPurchase handles CS_60035. Each unit costs the entry price in
+// guild coins and the stock of the rolled slot is decremented.
+func GuildShopPurchase(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_60035
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 60036, err
+	}
+	response := protobuf.SC_60036{Result: proto.Uint32(guildShopPurchaseResultInvalid)}
+	config, err := guildshop.LoadConfig()
+	if err != nil {
+		return 0, 60036, err
+	}
+	entry, ok := config.Entry(payload.GetGoodsid())
+	if !ok || len(entry.Goods) == 0 {
+		return client.SendMessage(60036, &response)
+	}
+	selected := payload.GetSelected()
+	if len(selected) == 0 && len(entry.Goods) == 1 {
+		selected = []*protobuf.GUILD_SHOP_INFO{{Id: proto.Uint32(entry.Goods[0]), Count: proto.Uint32(1)}}
+	}
+	// a slot never holds more than the purchase limit of its entry
+	stockLimit := uint64(entry.GoodsPurchaseLimit)
+	if stockLimit == 0 {
+		stockLimit = 1
+	}
+	total := uint64(0)
+	rewards := map[uint32]uint32{}
+	for _, pick := range selected {
+		if pick.GetCount() == 0 || uint64(pick.GetCount()) > stockLimit || !containsUint32(entry.Goods, pick.GetId()) {
+			return client.SendMessage(60036, &response)
+		}
+		total += uint64(pick.GetCount())
+		if total > stockLimit {
+			return client.SendMessage(60036, &response)
+		}
+		rewards[pick.GetId()] += pick.GetCount()
+	}
+	if total == 0 {
+		return client.SendMessage(60036, &response)
+	}
+	totalUnits := uint32(total)
+	num := entry.Num
+	if num == 0 {
+		num = 1
+	}
+	dropType := entry.Type
+	if dropType == 0 {
+		dropType = consts.DROP_TYPE_ITEM
+	}
+	cost := uint64(entry.Price) * total
+	if cost > math.MaxUint32 {
+		return client.SendMessage(60036, &response)
+	}
+	totalCost := uint32(cost)
+
+	errInvalid := errors.New("invalid")
+	errInsufficient := errors.New("insufficient")
+	errStock := errors.New("stock")
+
+	commanderID := client.Commander.CommanderID
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx, `
+UPDATE guild_shop_goods
+SET count = count - $4
+WHERE commander_id = $1 AND "index" = $2 AND goods_id = $3 AND count >= $4
+`, int64(commanderID), int64(payload.GetIndex()), int64(entry.ID), int64(totalUnits))
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return errStock
+		}
+		if totalCost > 0 {
+			if !client.Commander.HasEnoughResource(guildCoinResource, totalCost) {
+				return errInsufficient
+			}
+			if err := client.Commander.ConsumeResourceTx(ctx, tx, guildCoinResource, totalCost); err != nil {
+				return errInsufficient
+			}
+		}
+		drops := make([]*protobuf.DROPINFO, 0, len(rewards))
+		for id, units := range rewards {
+			amount := num * units
+			switch dropType {
+			case consts.DROP_TYPE_RESOURCE:
+				if err := client.Commander.AddResourceTx(ctx, tx, id, amount); err != nil {
+					return err
+				}
+			case consts.DROP_TYPE_ITEM:
+				if err := client.Commander.AddItemTx(ctx, tx, id, amount); err != nil {
+					return err
+				}
+			case consts.DROP_TYPE_SHIP:
+				for i := uint32(0); i < amount; i++ {
+					if _, err := client.Commander.AddShipTx(ctx, tx, id); err != nil {
+						return err
+					}
+				}
+			default:
+				return errInvalid
+			}
+			drops = append(drops, newDropInfo(dropType, id, amount))
+		}
+		response.DropList = drops
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errInvalid):
+			response.Result = proto.Uint32(guildShopPurchaseResultInvalid)
+		case errors.Is(err, errInsufficient):
+			response.Result = proto.Uint32(guildShopPurchaseResultInsufficient)
+		case errors.Is(err, errStock):
+			response.Result = proto.Uint32(guildShopPurchaseResultStock)
+		default:
+			response.Result = proto.Uint32(guildShopPurchaseResultDBError)
+		}
+		response.DropList = nil
+		return client.SendMessage(60036, &response)
+	}
+	if err := recordTaskEvent(client, taskEventShopPurchase, 0, 1); err != nil {
+		return 0, 60036, err
+	}
+	response.Result = proto.Uint32(guildShopPurchaseResultOK)
+	return client.SendMessage(60036, &response)
+}
@@ -19,9 +19,13 @@ const (
 )
 
 type guildStoreEntry struct {
-	ID                 uint32 `json:"id"`
-	Weight             uint32 `json:"weight"`
-	GoodsPurchaseLimit uint32 `json:"goods_purchase_limit"`
+	ID                 uint32   `json:"id"`
+	Weight             uint32   `json:"weight"`
+	GoodsPurchaseLimit uint32   `json:"goods_purchase_limit"`
+	Price              uint32   `json:"price"`
+	Goods              []uint32 `json:"goods"`
+	Num                uint32   `json:"num"`
+	Type               uint32   `json:"type"`
 }
 
 type guildSetEntry struct {
@@ -32,7 +36,11 @@ type guildSetEntry struct {
 func seedGuildShopConfig(t *testing.T) {
 	execAnswerExternalTestSQLT(t, "DELETE FROM config_entries WHERE category = $1", guildStoreConfigCategory)
 	execAnswerExternalTestSQLT(t, "DELETE FROM config_entries WHERE category = $1", guildSetConfigCategory)
-	stores := []guildStoreEntry{{ID: 1, Weight: 100, GoodsPurchaseLimit: 2}, {ID: 2, Weight: 100, GoodsPurchaseLimit: 1}, {ID: 3, Weight: 100, GoodsPurchaseLimit: 5}}
+	stores := []guildStoreEntry{
+		{ID: 1, Weight: 100, GoodsPurchaseLimit: 2, Price: 20, Goods: []uint32{2001}, Num: 3, Type: 2},
+		{ID: 2, Weight: 100, GoodsPurchaseLimit: 1},
+		{ID: 3, Weight: 100, GoodsPurchaseLimit: 5},
+	}
 	for _, store := range stores {
 		payload, err := json.Marshal(store)
 		if err != nil {
@@ -67,6 +75,7 @@ func setupGuildShopCommander(t *testing.T, commanderID uint32) *orm.Commander {
 }
 
 func cleanupGuildShopData(t *testing.T, commanderID uint32) {
+	execAnswerExternalTestSQLT(t, "DELETE FROM commander_items WHERE commander_id = $1", int64(commanderID))
 	execAnswerExternalTestSQLT(t, "DELETE FROM guild_shop_states WHERE commander_id = $1", int64(commanderID))
 	execAnswerExternalTestSQLT(t, "DELETE FROM guild_shop_goods WHERE commander_id = $1", int64(commanderID))
 	execAnswerExternalTestSQLT(t, "DELETE FROM owned_resources WHERE commander_id = $1", int64(commanderID))
@@ -133,3 +142,82 @@ func TestGuildShopManualRefreshConsumesCoins(t *testing.T) {
 		t.Fatalf("expected guild coin count 150, got %d", client.Commander.GetResourceCount(8))
 	}
 }
+
+func TestGuildShopPurchaseSpendsGuildCoins(t *testing.T) {
+	commanderID := uint32(7003)
+	cleanupGuildShopData(t, commanderID)
+	seedGuildShopConfig(t)
+	client := &connection.Client{Commander: setupGuildShopCommander(t, commanderID)}
+	defer cleanupGuildShopData(t, commanderID)
+
+	execAnswerExternalTestSQLT(t, "INSERT INTO guild_shop_states (commander_id, refresh_count, next_refresh_time) VALUES ($1, $2, $3)", int64(commanderID), int64(0), int64(time.Now().Add(time.Hour).Unix()))
+	execAnswerExternalTestSQLT(t, "INSERT INTO guild_shop_goods (commander_id, index, goods_id, count) VALUES ($1, $2, $3, $4)", int64(commanderID), int64(1), int64(1), int64(2))
+
+	payload := &protobuf.CS_60035{
+		Goodsid:  proto.Uint32(1),
+		Index:    proto.Uint32(1),
+		Selected: []*protobuf.GUILD_SHOP_INFO{{Id: proto.Uint32(2001), Count: proto.Uint32(1)}},
+	}
+	buf, err := proto.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	if _, _, err := answer.GuildShopPurchase(&buf, client); err != nil {
+		t.Fatalf("GuildShopPurchase failed: %v", err)
+	}
+	response := &protobuf.SC_60036{}
+	decodeTestPacket(t, client, 60036, response)
+	if response.GetResult() != 0 {
+		t.Fatalf("expected result 0, got %d", response.GetResult())
+	}
+	if len(response.GetDropList()) != 1 || response.GetDropList()[0].GetId() != 2001 || response.GetDropList()[0].GetNumber() != 3 {
+		t.Fatalf("unexpected drop list: %+v", response.GetDropList())
+	}
+	if client.Commander.GetResourceCount(8) != 180 {
+		t.Fatalf("expected guild coin count 180, got %d", client.Commander.GetResourceCount(8))
+	}
+	goods, err := orm.LoadGuildShopGoods(commanderID)
+	if err != nil {
+		t.Fatalf("failed to load goods: %v", err)
+	}
+	if len(goods) != 1 || goods[0].Count != 1 {
+		t.Fatalf("expected remaining stock 1, got %+v", goods)
+	}
+
+	client.Buffer.Reset()
+	payload.Selected[0].Count = proto.Uint32(2)
+	buf, err = proto.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	if _, _, err := answer.GuildShopPurchase(&buf, client); err != nil {
+		t.Fatalf("GuildShopPurchase failed: %v", err)
+	}
+	decodeTestPacket(t, client, 60036, response)
+	if response.GetResult() != 3 {
+		t.Fatalf("expected stock result 3, got %d", response.GetResult())
+	}
+	if client.Commander.GetResourceCount(8) != 180 {
+		t.Fatalf("expected guild coin count to stay 180, got %d", client.Commander.GetResourceCount(8))
+	}
+
+	client.Buffer.Reset()
+	payload.Selected = []*protobuf.GUILD_SHOP_INFO{
+		{Id: proto.Uint32(2001), Count: proto.Uint32(0xFFFFFFFF)},
+		{Id: proto.Uint32(2001), Count: proto.Uint32(2)},
+	}
+	buf, err = proto.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	if _, _, err := answer.GuildShopPurchase(&buf, client); err != nil {
+		t.Fatalf("GuildShopPurchase failed: %v", err)
+	}
+	decodeTestPacket(t, client, 60036, response)
+	if response.GetResult() != 1 {
+		t.Fatalf("expected overflowing counts to be rejected, got %d", response.GetResult())
+	}
+	if client.Commander.GetResourceCount(8) != 180 {
+		t.Fatalf("expected guild coin count to stay 180, got %d", client.Commander.GetResourceCount(8))
+	}
+}
@@ -0,0 +1,371 @@
+package answer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	guildTechConfigCategory = "ShareCfg/guild_technology_template.json"
+
+	guildTechStateIdle        = uint32(0)
+	guildTechStateResearching = uint32(1)
+
+	// Effect types read from the first value of each effect_args pair.
+	guildTechEffectShipBag  = uint32(1)
+	guildTechEffectEquipBag = uint32(2)
+
+	guildTechGoldResource = uint32(1)
+)
+
+var errGuildResourcesNotEnough = errors.New("not enough resources")
+
+// guildTechTemplate is one level of a technology group. contribution_consume
+// is the research progress the guild needs to reach the level, gold_consume
+// what a member pays to learn it once the guild has it.
+type guildTechTemplate struct {
+	ID                  uint32          `json:"id"`
+	Group               uint32          `json:"group"`
+	Level               uint32          `json:"level"`
+	ContributionConsume uint32          `json:"contribution_consume"`
+	GoldConsume         uint32          `json:"gold_consume"`
+	EffectArgs          json.RawMessage `json:"effect_args"`
+}
+
+type guildTechEffect struct {
+	Type  uint32
+	Value uint32
+}
+
+// effects accepts both a single [type, value] pair and a list of pairs.
+func (template *guildTechTemplate) effects() []guildTechEffect {
+	var pairs [][]uint32
+	if err := json.Unmarshal(template.EffectArgs, &pairs); err != nil {
+		var pair []uint32
+		if err := json.Unmarshal(template.EffectArgs, &pair); err != nil {
+			return nil
+		}
+		pairs = [][]uint32{pair}
+	}
+	effects := make([]guildTechEffect, 0, len(pairs))
+	for _, pair := range pairs {
+		if len(pair) < 2 {
+			continue
+		}
+		effects = append(effects, guildTechEffect{Type: pair[0], Value: pair[1]})
+	}
+	return effects
+}
+
+type guildTechCatalog struct {
+	byGroup map[uint32][]guildTechTemplate
+	groups  []uint32
+}
+
+func loadGuildTechCatalog() (*guildTechCatalog, error) {
+	entries, err := gamedata.List[guildTechTemplate](gamedata.Default, guildTechConfigCategory)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &guildTechCatalog{byGroup: make(map[uint32][]guildTechTemplate)}
+	for _, template := range entries {
+		if template.Group == 0 || template.Level == 0 {
+			continue
+		}
+		if _, ok := catalog.byGroup[template.Group]; !ok {
+			catalog.groups = append(catalog.groups, template.Group)
+		}
+		catalog.byGroup[template.Group] = append(catalog.byGroup[template.Group], template)
+	}
+	sort.Slice(catalog.groups, func(i, j int) bool { return catalog.groups[i] < catalog.groups[j] })
+	for group := range catalog.byGroup {
+		levels := catalog.byGroup[group]
+		sort.Slice(levels, func(i, j int) bool { return levels[i].Level < levels[j].Level })
+	}
+	return catalog, nil
+}
+
+func (catalog *guildTechCatalog) level(group uint32, level uint32) (*guildTechTemplate, bool) {
+	for i := range catalog.byGroup[group] {
+		if catalog.byGroup[group][i].Level == level {
+			return &catalog.byGroup[group][i], true
+		}
+	}
+	return nil, false
+}
+
+// displayTemplate is the template a group is shown with: the level being
+// worked towards, or the last level once the group is maxed.
+func (catalog *guildTechCatalog) displayTemplate(group uint32, level uint32) (*guildTechTemplate, bool) {
+	if next, ok := catalog.level(group, level+1); ok {
+		return next, true
+	}
+	return catalog.level(group, level)
+}
+
+func buildGuildTechnologies(guild *orm.Guild, catalog *guildTechCatalog) ([]*protobuf.GUILD_TECHNOLOGY, error) {
+	techs, err := orm.ListGuildTechnologies(guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[uint32]orm.GuildTechnology, len(techs))
+	for _, tech := range techs {
+		states[tech.Group] = tech
+	}
+	list := make([]*protobuf.GUILD_TECHNOLOGY, 0, len(catalog.groups))
+	for _, group := range catalog.groups {
+		tech := states[group]
+		template, ok := catalog.displayTemplate(group, tech.Level)
+		if !ok {
+			continue
+		}
+		state := guildTechStateIdle
+		if guild.TechGroup == group {
+			state = guildTechStateResearching
+		}
+		list = append(list, &protobuf.GUILD_TECHNOLOGY{
+			Id:       proto.Uint32(template.ID),
+			State:    proto.Uint32(state),
+			Progress: proto.Uint32(tech.Progress),
+		})
+	}
+	return list, nil
+}
+
+// commanderGuildTechIDs lists the template ids of the levels commanderID
+// learned, as sent in USER_GUILD_INFO.
+func commanderGuildTechIDs(commanderID uint32, catalog *guildTechCatalog) ([]uint32, error) {
+	techs, err := orm.ListCommanderGuildTechnologies(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, 0, len(techs))
+	for _, tech := range techs {
+		if template, ok := catalog.level(tech.Group, tech.Level); ok {
+			ids = append(ids, template.ID)
+		}
+	}
+	return ids, nil
+}
+
+// guildTechBonus sums the effect values of effectType over the technology
+// levels commanderID learned. Bonuses only apply while in a guild.
+func guildTechBonus(commanderID uint32, effectType uint32) (uint32, error) {
+	member, err := loadGuildMembership(commanderID)
+	if err != nil || member == nil {
+		return 0, err
+	}
+	techs, err := orm.ListCommanderGuildTechnologies(commanderID)
+	if err != nil || len(techs) == 0 {
+		return 0, err
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, err
+	}
+	bonus := uint32(0)
+	for _, tech := range techs {
+		template, ok := catalog.level(tech.Group, tech.Level)
+		if !ok {
+			continue
+		}
+		for _, effect := range template.effects() {
+			if effect.Type == effectType {
+				bonus += effect.Value
+			}
+		}
+	}
+	return bonus, nil
+}
+
+// advanceGuildResearchTx adds progress to the group the guild researches,
+// levelling it up as long as the progress covers the next level. It returns
+// the template ids of the levels reached.
+func advanceGuildResearchTx(ctx context.Context, tx pgx.Tx, guild *orm.Guild, catalog *guildTechCatalog, amount uint32) ([]uint32, error) {
+	if guild.TechGroup == 0 || amount == 0 {
+		return nil, nil
+	}
+	tech, err := orm.GetGuildTechnologyTx(ctx, tx, guild.ID, guild.TechGroup)
+	if err != nil {
+		return nil, err
+	}
+	tech.Progress += amount
+	reached := []uint32{}
+	for {
+		next, ok := catalog.level(tech.Group, tech.Level+1)
+		if !ok {
+			tech.Progress = 0
+			break
+		}
+		if tech.Progress < next.ContributionConsume {
+			break
+		}
+		tech.Progress -= next.ContributionConsume
+		tech.Level++
+		reached = append(reached, next.ID)
+	}
+	if err := orm.SaveGuildTechnologyTx(ctx, tx, tech); err != nil {
+		return nil, err
+	}
+	return reached, nil
+}
+
+// CommanderGuildTechnologies handles CS_62100.
+func CommanderGuildTechnologies(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62100
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62101, err
+	}
+	response := protobuf.SC_62101{Technologys: []*protobuf.GUILD_TECHNOLOGY{}}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62101, err
+	}
+	if member == nil {
+		return client.SendMessage(62101, &response)
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 62101, err
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, 62101, err
+	}
+	response.Technologys, err = buildGuildTechnologies(guild, catalog)
+	if err != nil {
+		return 0, 62101, err
+	}
+	return client.SendMessage(62101, &response)
+}
+
+// GuildStartResearch handles CS_62013: an officer picks the technology group
+// donations feed into.
+func GuildStartResearch(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62013
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62014, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62014, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(62014, &protobuf.SC_62014{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, 62014, err
+	}
+	group := payload.GetId()
+	if _, ok := catalog.byGroup[group]; !ok {
+		return client.SendMessage(62014, &protobuf.SC_62014{Result: proto.Uint32(guildResultNotFound)})
+	}
+	if err := orm.SetGuildResearch(member.GuildID, group, false); err != nil {
+		return 0, 62014, err
+	}
+	return client.SendMessage(62014, &protobuf.SC_62014{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildCancelResearch handles CS_62015. Progress already made is kept.
+func GuildCancelResearch(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62015
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62016, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62016, err
+	}
+	if member == nil || !guildCanManageMembers(member.Duty) {
+		return client.SendMessage(62016, &protobuf.SC_62016{Result: proto.Uint32(guildResultNoPermission)})
+	}
+	guild, err := orm.GetGuild(member.GuildID)
+	if err != nil {
+		return 0, 62016, err
+	}
+	if guild.TechGroup == 0 {
+		return client.SendMessage(62016, &protobuf.SC_62016{Result: proto.Uint32(guildResultFailed)})
+	}
+	if err := orm.SetGuildResearch(guild.ID, 0, true); err != nil {
+		return 0, 62016, err
+	}
+	return client.SendMessage(62016, &protobuf.SC_62016{Result: proto.Uint32(guildResultSuccess)})
+}
+
+// GuildLearnTechnology handles CS_62020: a member learns the next level of a
+// group, up to the level the guild researched, paying gold_consume.
+func GuildLearnTechnology(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_62020
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 62021, err
+	}
+	member, err := loadGuildMembership(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62021, err
+	}
+	if member == nil {
+		return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultNotFound)})
+	}
+	catalog, err := loadGuildTechCatalog()
+	if err != nil {
+		return 0, 62021, err
+	}
+	group := payload.GetId()
+	if _, ok := catalog.byGroup[group]; !ok {
+		return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultNotFound)})
+	}
+	learned, err := orm.ListCommanderGuildTechnologies(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 62021, err
+	}
+	level := uint32(0)
+	for _, tech := range learned {
+		if tech.Group == group {
+			level = tech.Level
+		}
+	}
+	guildTechs, err := orm.ListGuildTechnologies(member.GuildID)
+	if err != nil {
+		return 0, 62021, err
+	}
+	guildLevel := uint32(0)
+	for _, tech := range guildTechs {
+		if tech.Group == group {
+			guildLevel = tech.Level
+		}
+	}
+	next, ok := catalog.level(group, level+1)
+	if !ok || next.Level > guildLevel {
+		return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultFailed)})
+	}
+	if !client.Commander.HasEnoughResource(guildTechGoldResource, next.GoldConsume) {
+		return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultNotEnough)})
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if next.GoldConsume > 0 {
+			if err := client.Commander.ConsumeResourceTx(ctx, tx, guildTechGoldResource, next.GoldConsume); err != nil {
+				return errGuildResourcesNotEnough
+			}
+		}
+		return orm.SetCommanderGuildTechnologyTx(ctx, tx, client.Commander.CommanderID, group, next.Level)
+	})
+	if err != nil {
+		if errors.Is(err, errGuildResourcesNotEnough) {
+			return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultNotEnough)})
+		}
+		return 0, 62021, err
+	}
+	return client.SendMessage(62021, &protobuf.SC_62021{Result: proto.Uint32(guildResultSuccess)})
+}
@@ -360,6 +360,7 @@ func TestLastLoginAndOnlineInfo(t *testing.T) {
 
 func TestChatRoomChange(t *testing.T) {
 	client := setupHandlerCommander(t)
+	clearTable(t, &orm.Message{})
 	client.Commander.RoomID = 1
 	client.Server.JoinRoom(1, client)
 	payload := protobuf.CS_11401{RoomId: proto.Uint32(2)}
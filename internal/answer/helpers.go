@@ -1,6 +1,7 @@
 package answer
 
 import (
+	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
@@ -12,6 +13,31 @@ func boolToUint32(b bool) uint32 {
 	return 0
 }
 
+func clientServer(client *connection.Client) *connection.Server {
+	if client.Server != nil {
+		return client.Server
+	}
+	return connection.BelfastInstance
+}
+
+// isCommanderOnline reports whether commanderID is connected to any node.
+func isCommanderOnline(client *connection.Client, commanderID uint32) bool {
+	server := clientServer(client)
+	if server == nil {
+		return false
+	}
+	return server.IsCommanderOnline(commanderID)
+}
+
+// pushToCommander sends a message to commanderID wherever it is connected.
+func pushToCommander(client *connection.Client, commanderID uint32, packetID int, message proto.Message) {
+	server := clientServer(client)
+	if server == nil {
+		return
+	}
+	server.PushToCommander(commanderID, packetID, message)
+}
+
 func tbInfoPlaceholder() *protobuf.TBINFO {
 	// TODO: Seed New Educate (TB) state from storage.
 	return &protobuf.TBINFO{
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"testing"
 	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/ticket"
 )
 
 func TestBoolToUint32(t *testing.T) {
@@ -27,24 +30,43 @@ func TestTBPlaceholders(t *testing.T) {
 }
 
 func TestServerTicketRoundTrip(t *testing.T) {
-	ticket := formatServerTicket(12345)
-	if parseServerTicket(ticket) != 12345 {
+	value := formatServerTicket(12345, 0)
+	if parseServerTicket(value, 1) != 12345 {
 		t.Fatalf("expected to parse arg2 from ticket")
 	}
-	if parseServerTicket(formatServerTicket(0)) != 0 {
+	if parseServerTicket(formatServerTicket(0, 0), 1) != 0 {
 		t.Fatalf("expected zero arg2 for prefix-only ticket")
 	}
 }
 
 func TestParseServerTicketInvalid(t *testing.T) {
-	if parseServerTicket("invalid") != 0 {
+	if parseServerTicket("invalid", 1) != 0 {
 		t.Fatalf("expected invalid ticket to return 0")
 	}
-	if parseServerTicket(serverTicketPrefix+":not-a-number") != 0 {
+	if parseServerTicket(serverTicketPrefix+":not-a-number", 1) != 0 {
 		t.Fatalf("expected non-numeric ticket to return 0")
 	}
 }
 
+func TestSignedServerTicket(t *testing.T) {
+	if err := ticket.Default.Configure(config.TicketConfig{ServerID: 2, Keys: []config.TicketKeyConfig{{ID: "test", Secret: "secret"}}}); err != nil {
+		t.Fatalf("configure tickets: %v", err)
+	}
+	defer func() {
+		_ = ticket.Default.Configure(config.TicketConfig{})
+	}()
+	signed := formatServerTicket(12345, 2)
+	if parseServerTicket(signed, 2) != 12345 {
+		t.Fatalf("expected signed ticket to be accepted, got %q", signed)
+	}
+	if parseServerTicket(signed, 3) != 0 {
+		t.Fatalf("expected ticket of another server to be refused")
+	}
+	if parseServerTicket(serverTicketPrefix+":12345", 2) != 0 {
+		t.Fatalf("expected unsigned ticket to be refused")
+	}
+}
+
 func TestActivityStopTimeValid(t *testing.T) {
 	raw := json.RawMessage(`["timer",0,[[2026,1,2],[3,4,5]]]`)
 	result := activityStopTime(raw)
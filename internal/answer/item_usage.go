@@ -15,6 +15,7 @@ import (
 	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -463,7 +464,39 @@ func applyDrop(client *connection.Client, dropType uint32, dropID uint32, dropCo
 		}
 		return true, nil
 	case consts.DROP_TYPE_VITEM:
+		return true, addActivityEventPt(client, dropID, dropCount)
+	case consts.DROP_TYPE_WORLD_ITEM:
+		return true, orm.AddWorldItem(client.Commander.CommanderID, dropID, dropCount)
+	default:
+		return false, nil
+	}
+}
+
+// applyDropTx is applyDrop inside tx.
+func applyDropTx(ctx context.Context, tx pgx.Tx, client *connection.Client, dropType uint32, dropID uint32, dropCount uint32) (bool, error) {
+	switch dropType {
+	case consts.DROP_TYPE_RESOURCE:
+		return true, client.Commander.AddResourceTx(ctx, tx, dropID, dropCount)
+	case consts.DROP_TYPE_ITEM:
+		return true, client.Commander.AddItemTx(ctx, tx, dropID, dropCount)
+	case consts.DROP_TYPE_SHIP:
+		for i := uint32(0); i < dropCount; i++ {
+			if _, err := client.Commander.AddShipTx(ctx, tx, dropID); err != nil {
+				return true, err
+			}
+		}
 		return true, nil
+	case consts.DROP_TYPE_SKIN:
+		for i := uint32(0); i < dropCount; i++ {
+			if err := client.Commander.GiveSkinTx(ctx, tx, dropID); err != nil {
+				return true, err
+			}
+		}
+		return true, nil
+	case consts.DROP_TYPE_VITEM:
+		return true, addActivityEventPtTx(ctx, tx, client, dropID, dropCount)
+	case consts.DROP_TYPE_WORLD_ITEM:
+		return true, orm.AddWorldItemTx(ctx, tx, client.Commander.CommanderID, dropID, dropCount)
 	default:
 		return false, nil
 	}
@@ -665,6 +698,20 @@ func applyDropList(client *connection.Client, drops map[string]*protobuf.DROPINF
 	return nil
 }
 
+// applyDropListTx is applyDropList inside tx.
+func applyDropListTx(ctx context.Context, tx pgx.Tx, client *connection.Client, drops map[string]*protobuf.DROPINFO) error {
+	for _, drop := range drops {
+		ok, err := applyDropTx(ctx, tx, client, drop.GetType(), drop.GetId(), drop.GetNumber())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("unsupported drop type %d", drop.GetType())
+		}
+	}
+	return nil
+}
+
 func dropMapToList(drops map[string]*protobuf.DROPINFO) []*protobuf.DROPINFO {
 	list := make([]*protobuf.DROPINFO, 0, len(drops))
 	for _, drop := range drops {
@@ -3,6 +3,7 @@ package answer
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ggmolly/belfast/internal/config"
 	"github.com/ggmolly/belfast/internal/connection"
@@ -11,12 +12,14 @@ import (
 	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/ticket"
 	"google.golang.org/protobuf/proto"
 )
 
 const (
-	USER_STATUS_OK     = 0
-	USER_STATUS_BANNED = 17
+	USER_STATUS_OK             = 0
+	USER_STATUS_INVALID_TICKET = 1
+	USER_STATUS_BANNED         = 17
 )
 
 func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
@@ -29,14 +32,42 @@ func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
 
 	response := protobuf.SC_10023{
 		Result:       proto.Uint32(0),
-		ServerTicket: proto.String("=*=*=*=BELFAST=*=*=*="),
+		ServerTicket: proto.String(serverTicketPrefix),
 		ServerLoad:   proto.Uint32(serverLoad),
 		DbLoad:       proto.Uint32(dbLoad),
 	}
+	serverID := protoData.GetServerid()
 
 	accountID := protoData.GetAccountId()
 	deviceID := protoData.GetDeviceId()
-	if deviceID != "" {
+	if ticket.Default.Enabled() {
+		// the account comes from the signed ticket only; device mappings and
+		// the requested account id are client-controlled
+		claims, err := ticket.Default.Verify(protoData.GetServerTicket(), serverID, time.Now())
+		if err != nil {
+			if accountID != 0 {
+				logger.LogEvent("Server", "SC_10023", fmt.Sprintf("refused ticket for account %d: %s", accountID, err.Error()), logger.LOG_LEVEL_WARN)
+				response.Result = proto.Uint32(USER_STATUS_INVALID_TICKET)
+			}
+			response.UserId = proto.Uint32(0)
+			return client.SendMessage(10023, &response)
+		}
+		client.AuthArg2 = claims.Arg2
+		mappedID := uint32(0)
+		if mapping, err := orm.GetYostarusMapByArg2(claims.Arg2); err == nil {
+			mappedID = mapping.AccountID
+		} else if !errors.Is(err, db.ErrNotFound) {
+			logger.LogEvent("Server", "SC_10023", fmt.Sprintf("failed to fetch account mapping: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+			return 0, 10023, err
+		}
+		if accountID != 0 && accountID != mappedID {
+			logger.LogEvent("Server", "SC_10023", fmt.Sprintf("ticket of arg2 %d does not own account %d", claims.Arg2, accountID), logger.LOG_LEVEL_WARN)
+			response.Result = proto.Uint32(USER_STATUS_INVALID_TICKET)
+			response.UserId = proto.Uint32(0)
+			return client.SendMessage(10023, &response)
+		}
+		accountID = mappedID
+	} else if deviceID != "" {
 		// try to recover account identity when the client sends account_id = 0
 		if deviceMapping, err := orm.GetDeviceAuthMapByDeviceID(deviceID); err == nil {
 			if client.AuthArg2 == 0 {
@@ -52,8 +83,9 @@ func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
 	}
 	if accountID == 0 {
 		if client.AuthArg2 == 0 {
-			client.AuthArg2 = parseServerTicket(protoData.GetServerTicket())
+			client.AuthArg2 = parseServerTicket(protoData.GetServerTicket(), serverID)
 		}
+		response.ServerTicket = proto.String(formatServerTicket(client.AuthArg2, serverID))
 		if client.AuthArg2 != 0 {
 			if mapping, err := orm.GetYostarusMapByArg2(client.AuthArg2); err == nil {
 				accountID = mapping.AccountID
@@ -79,6 +111,7 @@ func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
 			return client.SendMessage(10023, &response)
 		}
 	}
+	response.ServerTicket = proto.String(formatServerTicket(client.AuthArg2, serverID))
 
 	err = client.GetCommander(accountID)
 	if err != nil {
@@ -123,6 +156,9 @@ func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
 		logger.LogEvent("Database", "Punishments", fmt.Sprintf("No punishments found for uid=%d", accountID), logger.LOG_LEVEL_INFO)
 		response.Result = proto.Uint32(USER_STATUS_OK)
 		response.UserId = proto.Uint32(client.Commander.CommanderID)
+		if client.Server != nil {
+			client.Server.CommanderJoined(client)
+		}
 	}
 
 	if deviceID != "" && client.AuthArg2 != 0 {
@@ -135,24 +171,36 @@ func JoinServer(buffer *[]byte, client *connection.Client) (int, int, error) {
 }
 
 func resolveServerDBLoad() (uint32, uint32) {
+	serverLoad := resolveServerLoad()
 	if db.DefaultStore == nil || db.DefaultStore.Pool == nil {
-		return 0, 0
+		return serverLoad, 0
 	}
 	stat := db.DefaultStore.Pool.Stat()
 	if stat == nil {
-		return 0, 0
+		return serverLoad, 0
 	}
 	maxConns := stat.MaxConns()
 	if maxConns <= 0 {
-		return 0, 0
+		return serverLoad, 0
 	}
 	acquiredConns := stat.AcquiredConns()
 	if acquiredConns <= 0 {
-		return 0, 0
+		return serverLoad, 0
 	}
 	dbLoad := (int64(acquiredConns) * 100) / int64(maxConns)
 	if dbLoad > 100 {
 		dbLoad = 100
 	}
-	return 0, uint32(dbLoad)
+	return serverLoad, uint32(dbLoad)
+}
+
+// resolveServerLoad returns the connections of this node as a percentage of
+// max_clients, which the gateway uses to list the node as busy or full.
+func resolveServerLoad() uint32 {
+	maxClients := config.Current().Belfast.MaxClients
+	server := connection.BelfastInstance
+	if maxClients <= 0 || server == nil {
+		return 0
+	}
+	return uint32(min(server.ClientCount()*100/maxClients, 100))
 }
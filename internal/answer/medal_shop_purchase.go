@@ -200,6 +200,9 @@ WHERE commander_id = $1 AND "index" = $2 AND count >= $3
 		return client.SendMessage(16109, &response)
 	}
 
+	if err := recordTaskEvent(client, taskEventShopPurchase, 0, 1); err != nil {
+		return 0, 16109, err
+	}
 	response.Result = proto.Uint32(medalShopPurchaseResultOK)
 	return client.SendMessage(16109, &response)
 }
@@ -0,0 +1,217 @@
+package answer
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+// meowfficerBoxTrainingSlots is the number of boxes that can be in
+// training at the same time.
+const meowfficerBoxTrainingSlots = 4
+
+// meowfficerBoxTemplate is a box pool: its boxes train for time seconds and
+// yield one of the [template_id, weight] entries of pool.
+type meowfficerBoxTemplate struct {
+	ID   uint32     `json:"id"`
+	Time uint32     `json:"time"`
+	Pool [][]uint32 `json:"pool"`
+}
+
+func (template *meowfficerBoxTemplate) roll() uint32 {
+	total := uint32(0)
+	for _, entry := range template.Pool {
+		if len(entry) >= 2 {
+			total += entry[1]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	pick := meowfficerRng.Uint32N(total)
+	for _, entry := range template.Pool {
+		if len(entry) < 2 {
+			continue
+		}
+		if pick < entry[1] {
+			return entry[0]
+		}
+		pick -= entry[1]
+	}
+	return 0
+}
+
+func meowfficerBoxInfo(box *orm.MeowfficerBox) *protobuf.COMMANDERBOXINFO {
+	return &protobuf.COMMANDERBOXINFO{
+		Id:         proto.Uint32(box.ID),
+		PoolId:     proto.Uint32(box.PoolID),
+		FinishTime: proto.Uint32(box.FinishTime),
+		BeginTime:  proto.Uint32(box.BeginTime),
+	}
+}
+
+// NewMeowfficer builds a level 1 meowfficer of templateID with the talents
+// and skill of its template, returning db.ErrNotFound for an unknown
+// template.
+func NewMeowfficer(commanderID uint32, templateID uint32) (*orm.Meowfficer, error) {
+	var template meowfficerTemplate
+	ok, err := loadMeowfficerConfig(meowfficerTemplateCategory, templateID, &template)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	abilities, err := loadMeowfficerAbilities()
+	if err != nil {
+		return nil, err
+	}
+	return &orm.Meowfficer{
+		CommanderID:   commanderID,
+		TemplateID:    template.ID,
+		Level:         1,
+		Abilities:     orm.ToInt64List(template.Ability),
+		AbilityOrigin: orm.ToInt64List(template.Ability),
+		UsedPt:        meowfficerAbilityWorth(abilities, template.Ability),
+		SkillID:       template.SkillID,
+	}, nil
+}
+
+// MeowfficerBoxStart handles CS_25002: an idle box is put in training.
+func MeowfficerBoxStart(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25002
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25003, err
+	}
+	failed := &protobuf.SC_25003{
+		Result: proto.Uint32(meowfficerResultFailed),
+		Box:    meowfficerBoxInfo(&orm.MeowfficerBox{ID: payload.GetBoxid()}),
+	}
+	boxes, err := orm.ListMeowfficerBoxes(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 25003, err
+	}
+	var box *orm.MeowfficerBox
+	training := 0
+	for i := range boxes {
+		if boxes[i].BeginTime != 0 {
+			training++
+		}
+		if boxes[i].ID == payload.GetBoxid() {
+			box = &boxes[i]
+		}
+	}
+	if box == nil || box.BeginTime != 0 || training >= meowfficerBoxTrainingSlots {
+		return client.SendMessage(25003, failed)
+	}
+	var template meowfficerBoxTemplate
+	ok, err := loadMeowfficerConfig(meowfficerBoxCategory, box.PoolID, &template)
+	if err != nil {
+		return 0, 25003, err
+	}
+	if !ok {
+		return client.SendMessage(25003, failed)
+	}
+	now := uint32(time.Now().Unix())
+	box.BeginTime = now
+	box.FinishTime = now + template.Time
+	if err := orm.SaveMeowfficerBox(box); err != nil {
+		return 0, 25003, err
+	}
+	return client.SendMessage(25003, &protobuf.SC_25003{
+		Result: proto.Uint32(meowfficerResultOK),
+		Box:    meowfficerBoxInfo(box),
+	})
+}
+
+// MeowfficerBoxOpen handles CS_25004: a box whose training finished is
+// opened, yielding a meowfficer of its pool.
+func MeowfficerBoxOpen(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25004
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25005, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_25005{
+		Result:     proto.Uint32(meowfficerResultFailed),
+		Commander:  meowfficerInfo(&orm.Meowfficer{}, 0),
+		FinishTime: proto.Uint32(0),
+	}
+	box, err := orm.GetMeowfficerBox(commanderID, payload.GetBoxid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25005, failed)
+		}
+		return 0, 25005, err
+	}
+	now := uint32(time.Now().Unix())
+	if box.BeginTime == 0 || box.FinishTime > now {
+		return client.SendMessage(25005, failed)
+	}
+	var template meowfficerBoxTemplate
+	ok, err := loadMeowfficerConfig(meowfficerBoxCategory, box.PoolID, &template)
+	if err != nil {
+		return 0, 25005, err
+	}
+	templateID := uint32(0)
+	if ok {
+		templateID = template.roll()
+	}
+	if templateID == 0 {
+		return client.SendMessage(25005, failed)
+	}
+	meowfficer, err := NewMeowfficer(commanderID, templateID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25005, failed)
+		}
+		return 0, 25005, err
+	}
+	home, err := loadMeowfficerHome(commanderID)
+	if err != nil {
+		return 0, 25005, err
+	}
+	home.UsageCount++
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteMeowfficerBoxTx(ctx, tx, commanderID, box.ID); err != nil {
+			return err
+		}
+		if err := orm.CreateMeowfficerTx(ctx, tx, meowfficer); err != nil {
+			return err
+		}
+		return orm.SaveMeowfficerHomeTx(ctx, tx, home)
+	})
+	if err != nil {
+		return 0, 25005, err
+	}
+	return client.SendMessage(25005, &protobuf.SC_25005{
+		Result:     proto.Uint32(meowfficerResultOK),
+		Commander:  meowfficerInfo(meowfficer, home.CleanTime),
+		FinishTime: proto.Uint32(box.FinishTime),
+	})
+}
+
+// MeowfficerBoxList handles CS_25034.
+func MeowfficerBoxList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25034
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25035, err
+	}
+	boxes, err := orm.ListMeowfficerBoxes(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 25035, err
+	}
+	response := protobuf.SC_25035{BoxList: make([]*protobuf.COMMANDERBOXINFO, 0, len(boxes))}
+	for i := range boxes {
+		response.BoxList = append(response.BoxList, meowfficerBoxInfo(&boxes[i]))
+	}
+	return client.SendMessage(25035, &response)
+}
@@ -0,0 +1,150 @@
+package answer
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	// meowfficerFleetPositions is the number of meowfficers a fleet takes.
+	meowfficerFleetPositions = 2
+	// meowfficerPresetLimit is the number of fleet presets.
+	meowfficerPresetLimit = 10
+)
+
+// meowfficerPositions lists the meowfficers of a fleet or preset, list[i]
+// being the one at position i+1.
+func meowfficerPositions(list orm.Int64List) []*protobuf.COMMANDERSINFO {
+	positions := []*protobuf.COMMANDERSINFO{}
+	for i, id := range list {
+		if id == 0 {
+			continue
+		}
+		positions = append(positions, &protobuf.COMMANDERSINFO{
+			Pos: proto.Uint32(uint32(i + 1)),
+			Id:  proto.Uint32(uint32(id)),
+		})
+	}
+	return positions
+}
+
+func meowfficerSlots(list orm.Int64List) []uint32 {
+	slots := make([]uint32, meowfficerFleetPositions)
+	for i, id := range list {
+		if i < len(slots) {
+			slots[i] = uint32(id)
+		}
+	}
+	return slots
+}
+
+// MeowfficerSetFleet handles CS_25006: the meowfficer is assigned to a fleet
+// position (cleared with 0), leaving the position it had before.
+func MeowfficerSetFleet(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25006
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25007, err
+	}
+	failed := &protobuf.SC_25007{Result: proto.Uint32(meowfficerResultFailed)}
+	fleet, ok := client.Commander.FleetsMap[payload.GetGroupid()]
+	if !ok || payload.GetPos() == 0 || payload.GetPos() > meowfficerFleetPositions {
+		return client.SendMessage(25007, failed)
+	}
+	meowfficerID := payload.GetCommanderid()
+	if meowfficerID != 0 {
+		if _, err := orm.GetMeowfficer(client.Commander.CommanderID, meowfficerID); err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(25007, failed)
+			}
+			return 0, 25007, err
+		}
+		for i := range client.Commander.Fleets {
+			other := &client.Commander.Fleets[i]
+			if other.ID == fleet.ID {
+				continue
+			}
+			slots := meowfficerSlots(other.MeowfficerList)
+			if !containsUint32(slots, meowfficerID) {
+				continue
+			}
+			for pos := range slots {
+				if slots[pos] == meowfficerID {
+					slots[pos] = 0
+				}
+			}
+			if err := other.UpdateMeowfficerList(client.Commander, slots); err != nil {
+				return 0, 25007, err
+			}
+		}
+	}
+	slots := meowfficerSlots(fleet.MeowfficerList)
+	for pos := range slots {
+		if meowfficerID != 0 && slots[pos] == meowfficerID {
+			slots[pos] = 0
+		}
+	}
+	slots[payload.GetPos()-1] = meowfficerID
+	if err := fleet.UpdateMeowfficerList(client.Commander, slots); err != nil {
+		return 0, 25007, err
+	}
+	return client.SendMessage(25007, &protobuf.SC_25007{Result: proto.Uint32(meowfficerResultOK)})
+}
+
+// MeowfficerSavePreset handles CS_25022.
+func MeowfficerSavePreset(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25022
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25023, err
+	}
+	failed := &protobuf.SC_25023{Result: proto.Uint32(meowfficerResultFailed)}
+	if payload.GetId() == 0 || payload.GetId() > meowfficerPresetLimit {
+		return client.SendMessage(25023, failed)
+	}
+	slots := make([]uint32, meowfficerFleetPositions)
+	for _, member := range payload.GetCommandersid() {
+		pos := member.GetPos()
+		if pos == 0 || pos > meowfficerFleetPositions || slots[pos-1] != 0 || containsUint32(slots, member.GetId()) {
+			return client.SendMessage(25023, failed)
+		}
+		if _, err := orm.GetMeowfficer(client.Commander.CommanderID, member.GetId()); err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(25023, failed)
+			}
+			return 0, 25023, err
+		}
+		slots[pos-1] = member.GetId()
+	}
+	preset, err := orm.GetMeowfficerPreset(client.Commander.CommanderID, payload.GetId())
+	if err != nil {
+		return 0, 25023, err
+	}
+	preset.Meowfficers = orm.ToInt64List(slots)
+	if err := orm.SaveMeowfficerPreset(preset); err != nil {
+		return 0, 25023, err
+	}
+	return client.SendMessage(25023, &protobuf.SC_25023{Result: proto.Uint32(meowfficerResultOK)})
+}
+
+// MeowfficerRenamePreset handles CS_25024.
+func MeowfficerRenamePreset(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25024
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25025, err
+	}
+	if payload.GetId() == 0 || payload.GetId() > meowfficerPresetLimit || !validMeowfficerName(payload.GetName()) {
+		return client.SendMessage(25025, &protobuf.SC_25025{Result: proto.Uint32(meowfficerResultFailed)})
+	}
+	preset, err := orm.GetMeowfficerPreset(client.Commander.CommanderID, payload.GetId())
+	if err != nil {
+		return 0, 25025, err
+	}
+	preset.Name = payload.GetName()
+	if err := orm.SaveMeowfficerPreset(preset); err != nil {
+		return 0, 25025, err
+	}
+	return client.SendMessage(25025, &protobuf.SC_25025{Result: proto.Uint32(meowfficerResultOK)})
+}
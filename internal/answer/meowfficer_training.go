@@ -0,0 +1,233 @@
+package answer
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	// meowfficerAbilitySlots is the number of talents a meowfficer can have.
+	meowfficerAbilitySlots = 5
+	// meowfficerAbilityOffers is the number of talents offered by a refresh.
+	meowfficerAbilityOffers = 3
+	// meowfficerAbilityCooldown is the delay between two talent refreshes,
+	// in seconds.
+	meowfficerAbilityCooldown = uint32(24 * 60 * 60)
+)
+
+// MeowfficerUpgrade handles CS_25008: the materials are consumed, giving
+// their exp and skill exp to the target.
+func MeowfficerUpgrade(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25008
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25009, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_25009{Result: proto.Uint32(meowfficerResultFailed)}
+	materialIDs := make([]uint32, 0, len(payload.GetMaterialid()))
+	for _, id := range payload.GetMaterialid() {
+		if id == payload.GetTargetid() || containsUint32(materialIDs, id) {
+			return client.SendMessage(25009, failed)
+		}
+		materialIDs = append(materialIDs, id)
+	}
+	if len(materialIDs) == 0 {
+		return client.SendMessage(25009, failed)
+	}
+	target, err := orm.GetMeowfficer(commanderID, payload.GetTargetid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25009, failed)
+		}
+		return 0, 25009, err
+	}
+	busy, err := orm.GetBusyMeowfficerIDs(commanderID)
+	if err != nil {
+		return 0, 25009, err
+	}
+	exp, skillExp := uint32(0), uint32(0)
+	for _, id := range materialIDs {
+		material, err := orm.GetMeowfficer(commanderID, id)
+		if err != nil {
+			if db.IsNotFound(err) {
+				return client.SendMessage(25009, failed)
+			}
+			return 0, 25009, err
+		}
+		if _, ok := busy[id]; ok || material.IsLocked {
+			return client.SendMessage(25009, failed)
+		}
+		var template meowfficerTemplate
+		if _, err := loadMeowfficerConfig(meowfficerTemplateCategory, material.TemplateID, &template); err != nil {
+			return 0, 25009, err
+		}
+		exp += template.Exp + material.Exp
+		skillExp += template.SkillExp
+	}
+	if err := addMeowfficerExp(target, exp); err != nil {
+		return 0, 25009, err
+	}
+	if err := addMeowfficerSkillExp(target, skillExp); err != nil {
+		return 0, 25009, err
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteMeowfficersTx(ctx, tx, commanderID, materialIDs); err != nil {
+			return err
+		}
+		return orm.SaveMeowfficerTx(ctx, tx, target)
+	})
+	if err != nil {
+		return 0, 25009, err
+	}
+	return client.SendMessage(25009, &protobuf.SC_25009{Result: proto.Uint32(meowfficerResultOK)})
+}
+
+// MeowfficerRefreshAbilities handles CS_25010: talents of groups the
+// meowfficer doesn't have yet are offered, once per
+// meowfficerAbilityCooldown.
+func MeowfficerRefreshAbilities(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25010
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25011, err
+	}
+	failed := &protobuf.SC_25011{Result: proto.Uint32(meowfficerResultFailed)}
+	meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, payload.GetCommanderid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25011, failed)
+		}
+		return 0, 25011, err
+	}
+	now := uint32(time.Now().Unix())
+	if now < meowfficer.AbilityTime {
+		return client.SendMessage(25011, failed)
+	}
+	abilities, err := loadMeowfficerAbilities()
+	if err != nil {
+		return 0, 25011, err
+	}
+	groups := make(map[uint32]struct{}, len(meowfficer.Abilities))
+	for _, id := range meowfficer.Abilities {
+		groups[abilities[uint32(id)].Group] = struct{}{}
+	}
+	candidates := make([]uint32, 0, len(abilities))
+	for id, ability := range abilities {
+		if _, ok := groups[ability.Group]; !ok {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	meowfficerRng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	if len(candidates) > meowfficerAbilityOffers {
+		candidates = candidates[:meowfficerAbilityOffers]
+	}
+	meowfficer.AbilityCandidates = orm.ToInt64List(candidates)
+	meowfficer.AbilityTime = now + meowfficerAbilityCooldown
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		return 0, 25011, err
+	}
+	return client.SendMessage(25011, &protobuf.SC_25011{
+		Result:    proto.Uint32(meowfficerResultOK),
+		Abilityid: candidates,
+	})
+}
+
+// MeowfficerLearnAbility handles CS_25012: an offered talent is learned,
+// replacing replaceid unless it is 0, as long as the talent points of the
+// meowfficer level allow it.
+func MeowfficerLearnAbility(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25012
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25013, err
+	}
+	failed := &protobuf.SC_25013{Result: proto.Uint32(meowfficerResultFailed)}
+	meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, payload.GetCommanderid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25013, failed)
+		}
+		return 0, 25013, err
+	}
+	candidates := orm.ToUint32List(meowfficer.AbilityCandidates)
+	current := orm.ToUint32List(meowfficer.Abilities)
+	if !containsUint32(candidates, payload.GetTargetid()) {
+		return client.SendMessage(25013, failed)
+	}
+	if payload.GetReplaceid() != 0 && !containsUint32(current, payload.GetReplaceid()) {
+		return client.SendMessage(25013, failed)
+	}
+	if payload.GetReplaceid() == 0 && len(current) >= meowfficerAbilitySlots {
+		return client.SendMessage(25013, failed)
+	}
+	abilities, err := loadMeowfficerAbilities()
+	if err != nil {
+		return 0, 25013, err
+	}
+	target, ok := abilities[payload.GetTargetid()]
+	if !ok {
+		return client.SendMessage(25013, failed)
+	}
+	learned := make([]uint32, 0, len(current)+1)
+	for _, id := range current {
+		if id == payload.GetReplaceid() {
+			continue
+		}
+		if abilities[id].Group == target.Group {
+			return client.SendMessage(25013, failed)
+		}
+		learned = append(learned, id)
+	}
+	learned = append(learned, target.ID)
+	var level meowfficerLevelTemplate
+	if _, err := loadMeowfficerConfig(meowfficerLevelCategory, meowfficer.Level, &level); err != nil {
+		return 0, 25013, err
+	}
+	usedPt := meowfficerAbilityWorth(abilities, learned)
+	if usedPt > level.AbilityPt {
+		return client.SendMessage(25013, failed)
+	}
+	meowfficer.Abilities = orm.ToInt64List(learned)
+	meowfficer.AbilityCandidates = orm.Int64List{}
+	meowfficer.UsedPt = usedPt
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		return 0, 25013, err
+	}
+	return client.SendMessage(25013, &protobuf.SC_25013{Result: proto.Uint32(meowfficerResultOK)})
+}
+
+// MeowfficerResetAbilities handles CS_25014: the talents of the meowfficer
+// are reset to the ones it was obtained with.
+func MeowfficerResetAbilities(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25014
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25015, err
+	}
+	meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, payload.GetCommanderid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25015, &protobuf.SC_25015{Result: proto.Uint32(meowfficerResultFailed)})
+		}
+		return 0, 25015, err
+	}
+	abilities, err := loadMeowfficerAbilities()
+	if err != nil {
+		return 0, 25015, err
+	}
+	meowfficer.Abilities = append(orm.Int64List{}, meowfficer.AbilityOrigin...)
+	meowfficer.AbilityCandidates = orm.Int64List{}
+	meowfficer.UsedPt = meowfficerAbilityWorth(abilities, orm.ToUint32List(meowfficer.AbilityOrigin))
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		return 0, 25015, err
+	}
+	return client.SendMessage(25015, &protobuf.SC_25015{Result: proto.Uint32(meowfficerResultOK)})
+}
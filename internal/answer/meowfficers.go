@@ -1,14 +1,297 @@
 package answer
 
 import (
-	"github.com/ggmolly/belfast/internal/connection"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/rng"
 )
 
+const (
+	meowfficerTemplateCategory = "ShareCfg/commander_data_template.json"
+	meowfficerLevelCategory    = "ShareCfg/commander_level.json"
+	meowfficerSkillCategory    = "ShareCfg/commander_skill_template.json"
+	meowfficerAbilityCategory  = "ShareCfg/commander_ability_template.json"
+	meowfficerBoxCategory      = "ShareCfg/commander_data_create_material.json"
+	meowfficerHomeCategory     = "ShareCfg/commander_home.json"
+
+	meowfficerResultOK     = uint32(0)
+	meowfficerResultFailed = uint32(1)
+
+	meowfficerNameMaxLength = 12
+	// meowfficerRenameCooldown is the delay between two renames of a
+	// meowfficer, in seconds.
+	meowfficerRenameCooldown = uint32(24 * 60 * 60)
+)
+
+var meowfficerRng = rng.NewLockedRand()
+
+// meowfficerTemplate is a meowfficer. ability lists the talents it is
+// obtained with and skill_id its skill at level 1; exp and skill_exp are
+// given to the meowfficer it is fed to.
+type meowfficerTemplate struct {
+	ID       uint32   `json:"id"`
+	Rarity   uint32   `json:"rarity"`
+	Ability  []uint32 `json:"ability"`
+	SkillID  uint32   `json:"skill_id"`
+	Exp      uint32   `json:"exp"`
+	SkillExp uint32   `json:"skill_exp"`
+}
+
+// meowfficerLevelTemplate is a meowfficer level (its id): exp is needed to
+// reach the next one, 0 at the max level, and ability_pt is the number of
+// talent points available.
+type meowfficerLevelTemplate struct {
+	ID        uint32 `json:"id"`
+	Exp       uint32 `json:"exp"`
+	AbilityPt uint32 `json:"ability_pt"`
+}
+
+// meowfficerSkillTemplate is a skill level; exp is the skill exp needed to
+// reach next_id, 0 at the max level.
+type meowfficerSkillTemplate struct {
+	ID     uint32 `json:"id"`
+	Exp    uint32 `json:"exp"`
+	NextID uint32 `json:"next_id"`
+}
+
+// meowfficerAbilityTemplate is a talent costing worth talent points. A
+// meowfficer can't have two talents of the same group.
+type meowfficerAbilityTemplate struct {
+	ID    uint32 `json:"id"`
+	Group uint32 `json:"group"`
+	Worth uint32 `json:"worth"`
+}
+
+// loadMeowfficerConfig decodes the entry id of category into out, returning
+// false when it doesn't exist.
+func loadMeowfficerConfig[T any](category string, id uint32, out *T) (bool, error) {
+	cached, err := gamedata.Get[T](gamedata.Default, category, strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	*out = *cached
+	return true, nil
+}
+
+func loadMeowfficerAbilities() (map[uint32]meowfficerAbilityTemplate, error) {
+	entries, err := gamedata.List[meowfficerAbilityTemplate](gamedata.Default, meowfficerAbilityCategory)
+	if err != nil {
+		return nil, err
+	}
+	abilities := make(map[uint32]meowfficerAbilityTemplate, len(entries))
+	for _, ability := range entries {
+		if ability.ID == 0 {
+			continue
+		}
+		abilities[ability.ID] = ability
+	}
+	return abilities, nil
+}
+
+func meowfficerAbilityWorth(abilities map[uint32]meowfficerAbilityTemplate, ids []uint32) uint32 {
+	worth := uint32(0)
+	for _, id := range ids {
+		worth += abilities[id].Worth
+	}
+	return worth
+}
+
+// addMeowfficerExp levels up the meowfficer with exp, dropping the exp
+// left once the max level is reached.
+func addMeowfficerExp(meowfficer *orm.Meowfficer, exp uint32) error {
+	meowfficer.Exp += exp
+	for {
+		var level meowfficerLevelTemplate
+		ok, err := loadMeowfficerConfig(meowfficerLevelCategory, meowfficer.Level, &level)
+		if err != nil {
+			return err
+		}
+		if !ok || level.Exp == 0 {
+			meowfficer.Exp = 0
+			return nil
+		}
+		if meowfficer.Exp < level.Exp {
+			return nil
+		}
+		meowfficer.Exp -= level.Exp
+		meowfficer.Level++
+	}
+}
+
+// addMeowfficerSkillExp levels up the skill of the meowfficer with exp.
+func addMeowfficerSkillExp(meowfficer *orm.Meowfficer, exp uint32) error {
+	if meowfficer.SkillID == 0 {
+		return nil
+	}
+	meowfficer.SkillExp += exp
+	for {
+		var skill meowfficerSkillTemplate
+		ok, err := loadMeowfficerConfig(meowfficerSkillCategory, meowfficer.SkillID, &skill)
+		if err != nil {
+			return err
+		}
+		if !ok || skill.Exp == 0 || skill.NextID == 0 {
+			meowfficer.SkillExp = 0
+			return nil
+		}
+		if meowfficer.SkillExp < skill.Exp {
+			return nil
+		}
+		meowfficer.SkillExp -= skill.Exp
+		meowfficer.SkillID = skill.NextID
+	}
+}
+
+func meowfficerInfo(meowfficer *orm.Meowfficer, cleanTime uint32) *protobuf.COMMANDERINFO {
+	info := &protobuf.COMMANDERINFO{
+		Id:            proto.Uint32(meowfficer.ID),
+		TemplateId:    proto.Uint32(meowfficer.TemplateID),
+		Level:         proto.Uint32(meowfficer.Level),
+		Exp:           proto.Uint32(meowfficer.Exp),
+		IsLocked:      proto.Uint32(boolToUint32(meowfficer.IsLocked)),
+		Ability:       orm.ToUint32List(meowfficer.Abilities),
+		AbilityOrigin: orm.ToUint32List(meowfficer.AbilityOrigin),
+		AbilityTime:   proto.Uint32(meowfficer.AbilityTime),
+		Skill:         []*protobuf.SKILLINFO{},
+		UsedPt:        proto.Uint32(meowfficer.UsedPt),
+		Name:          proto.String(meowfficer.Name),
+		RenameTime:    proto.Uint32(meowfficer.RenameTime),
+		HomeCleanTime: proto.Uint32(cleanTime),
+		HomePlayTime:  proto.Uint32(meowfficer.HomePlayTime),
+		HomeFeedTime:  proto.Uint32(meowfficer.HomeFeedTime),
+	}
+	if meowfficer.SkillID != 0 {
+		info.Skill = append(info.Skill, &protobuf.SKILLINFO{
+			Id:  proto.Uint32(meowfficer.SkillID),
+			Exp: proto.Uint32(meowfficer.SkillExp),
+		})
+	}
+	return info
+}
+
+func validMeowfficerName(name string) bool {
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+	return utf8.RuneCountInString(name) <= meowfficerNameMaxLength
+}
+
+// loadMeowfficerHome returns the cat lodge of the commander once the daily
+// reset was applied.
+func loadMeowfficerHome(commanderID uint32) (*orm.MeowfficerHome, error) {
+	home, err := orm.GetOrCreateMeowfficerHome(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	if orm.ApplyMeowfficerDailyReset(home, time.Now()) {
+		if err := orm.SaveMeowfficerHome(home); err != nil {
+			return nil, err
+		}
+	}
+	return home, nil
+}
+
+// Meowfficers sends SC_25001 during login: the owned meowfficers, their
+// boxes and the fleet presets.
 func Meowfficers(buffer *[]byte, client *connection.Client) (int, int, error) {
-	var response protobuf.SC_25001
-	response.UsageCount = proto.Uint32(0)
+	commanderID := client.Commander.CommanderID
+	home, err := loadMeowfficerHome(commanderID)
+	if err != nil {
+		return 0, 25001, err
+	}
+	meowfficers, err := orm.ListMeowfficers(commanderID)
+	if err != nil {
+		return 0, 25001, err
+	}
+	boxes, err := orm.ListMeowfficerBoxes(commanderID)
+	if err != nil {
+		return 0, 25001, err
+	}
+	presets, err := orm.ListMeowfficerPresets(commanderID)
+	if err != nil {
+		return 0, 25001, err
+	}
+	response := protobuf.SC_25001{
+		Commanders: make([]*protobuf.COMMANDERINFO, 0, len(meowfficers)),
+		Box:        make([]*protobuf.COMMANDERBOXINFO, 0, len(boxes)),
+		UsageCount: proto.Uint32(home.UsageCount),
+		Presets:    make([]*protobuf.PRESETFLEET, 0, len(presets)),
+	}
+	for i := range meowfficers {
+		response.Commanders = append(response.Commanders, meowfficerInfo(&meowfficers[i], home.CleanTime))
+	}
+	for i := range boxes {
+		response.Box = append(response.Box, meowfficerBoxInfo(&boxes[i]))
+	}
+	for _, preset := range presets {
+		response.Presets = append(response.Presets, &protobuf.PRESETFLEET{
+			Id:           proto.Uint32(preset.PresetID),
+			Commandersid: meowfficerPositions(preset.Meowfficers),
+			Name:         proto.String(preset.Name),
+		})
+	}
 	return client.SendMessage(25001, &response)
 }
+
+// MeowfficerLock handles CS_25016.
+func MeowfficerLock(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25016
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25017, err
+	}
+	meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, payload.GetCommanderid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25017, &protobuf.SC_25017{Result: proto.Uint32(meowfficerResultFailed)})
+		}
+		return 0, 25017, err
+	}
+	meowfficer.IsLocked = payload.GetFlag() != 0
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		return 0, 25017, err
+	}
+	return client.SendMessage(25017, &protobuf.SC_25017{Result: proto.Uint32(meowfficerResultOK)})
+}
+
+// MeowfficerRename handles CS_25020; a meowfficer can be renamed once per
+// meowfficerRenameCooldown.
+func MeowfficerRename(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_25020
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 25021, err
+	}
+	failed := &protobuf.SC_25021{Result: proto.Uint32(meowfficerResultFailed)}
+	if !validMeowfficerName(payload.GetName()) {
+		return client.SendMessage(25021, failed)
+	}
+	meowfficer, err := orm.GetMeowfficer(client.Commander.CommanderID, payload.GetCommanderid())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(25021, failed)
+		}
+		return 0, 25021, err
+	}
+	now := uint32(time.Now().Unix())
+	if meowfficer.RenameTime != 0 && now < meowfficer.RenameTime+meowfficerRenameCooldown {
+		return client.SendMessage(25021, failed)
+	}
+	meowfficer.Name = payload.GetName()
+	meowfficer.RenameTime = now
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		return 0, 25021, err
+	}
+	return client.SendMessage(25021, &protobuf.SC_25021{Result: proto.Uint32(meowfficerResultOK)})
+}
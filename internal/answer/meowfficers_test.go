@@ -0,0 +1,199 @@
+package answer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func seedMeowfficerConfig(t *testing.T) {
+	t.Helper()
+	seedConfigEntry(t, meowfficerTemplateCategory, "101", `{"id":101,"rarity":3,"ability":[11],"skill_id":501,"exp":50,"skill_exp":10}`)
+	seedConfigEntry(t, meowfficerLevelCategory, "1", `{"id":1,"exp":100,"ability_pt":10}`)
+	seedConfigEntry(t, meowfficerLevelCategory, "2", `{"id":2,"exp":0,"ability_pt":20}`)
+	seedConfigEntry(t, meowfficerSkillCategory, "501", `{"id":501,"exp":15,"next_id":502}`)
+	seedConfigEntry(t, meowfficerSkillCategory, "502", `{"id":502,"exp":0,"next_id":0}`)
+	seedConfigEntry(t, meowfficerAbilityCategory, "11", `{"id":11,"group":1,"worth":5}`)
+	seedConfigEntry(t, meowfficerAbilityCategory, "21", `{"id":21,"group":2,"worth":5}`)
+	seedConfigEntry(t, meowfficerBoxCategory, "1", `{"id":1,"time":0,"pool":[[101,1]]}`)
+	seedConfigEntry(t, meowfficerHomeCategory, "1", `{"id":1,"exp":10,"slot":2,"hour_exp":5,"feed_exp":30,"play_exp":30,"home_exp":10}`)
+	seedConfigEntry(t, meowfficerHomeCategory, "2", `{"id":2,"exp":0,"slot":3,"hour_exp":5,"feed_exp":30,"play_exp":30,"home_exp":10}`)
+}
+
+func openMeowfficerBox(t *testing.T, client *connection.Client) *protobuf.COMMANDERINFO {
+	t.Helper()
+	box := orm.MeowfficerBox{CommanderID: client.Commander.CommanderID, PoolID: 1}
+	if err := orm.CreateMeowfficerBox(&box); err != nil {
+		t.Fatalf("create box: %v", err)
+	}
+	start := marshalPacketRequest(t, &protobuf.CS_25002{Boxid: proto.Uint32(box.ID)})
+	if _, _, err := MeowfficerBoxStart(&start, client); err != nil {
+		t.Fatalf("start box failed: %v", err)
+	}
+	started := &protobuf.SC_25003{}
+	decodePacketMessage(t, client, 25003, started)
+	client.Buffer.Reset()
+	if started.GetResult() != meowfficerResultOK {
+		t.Fatalf("expected box to start, got %d", started.GetResult())
+	}
+	open := marshalPacketRequest(t, &protobuf.CS_25004{Boxid: proto.Uint32(box.ID)})
+	if _, _, err := MeowfficerBoxOpen(&open, client); err != nil {
+		t.Fatalf("open box failed: %v", err)
+	}
+	opened := &protobuf.SC_25005{}
+	decodePacketMessage(t, client, 25005, opened)
+	client.Buffer.Reset()
+	if opened.GetResult() != meowfficerResultOK {
+		t.Fatalf("expected box to open, got %d", opened.GetResult())
+	}
+	return opened.GetCommander()
+}
+
+func TestMeowfficerBoxesAndUpgrade(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedMeowfficerConfig(t)
+
+	target := openMeowfficerBox(t, client)
+	if target.GetTemplateId() != 101 || target.GetUsedPt() != 5 || len(target.GetAbility()) != 1 || target.GetSkill()[0].GetId() != 501 {
+		t.Fatalf("unexpected meowfficer: %v", target)
+	}
+	locked := openMeowfficerBox(t, client)
+	material := openMeowfficerBox(t, client)
+
+	lock := marshalPacketRequest(t, &protobuf.CS_25016{Commanderid: proto.Uint32(locked.GetId()), Flag: proto.Uint32(1)})
+	if _, _, err := MeowfficerLock(&lock, client); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	client.Buffer.Reset()
+
+	upgrade := func(materials ...uint32) uint32 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_25008{Targetid: proto.Uint32(target.GetId()), Materialid: materials})
+		if _, _, err := MeowfficerUpgrade(&payload, client); err != nil {
+			t.Fatalf("upgrade failed: %v", err)
+		}
+		response := &protobuf.SC_25009{}
+		decodePacketMessage(t, client, 25009, response)
+		client.Buffer.Reset()
+		return response.GetResult()
+	}
+	if result := upgrade(locked.GetId()); result != meowfficerResultFailed {
+		t.Fatalf("expected locked material to be refused, got %d", result)
+	}
+	if result := upgrade(material.GetId()); result != meowfficerResultOK {
+		t.Fatalf("expected upgrade to succeed, got %d", result)
+	}
+	upgraded, err := orm.GetMeowfficer(client.Commander.CommanderID, target.GetId())
+	if err != nil {
+		t.Fatalf("get meowfficer: %v", err)
+	}
+	if upgraded.Level != 1 || upgraded.Exp != 50 || upgraded.SkillID != 501 || upgraded.SkillExp != 10 {
+		t.Fatalf("unexpected upgraded meowfficer: %+v", upgraded)
+	}
+	if _, err := orm.GetMeowfficer(client.Commander.CommanderID, material.GetId()); err == nil {
+		t.Fatalf("expected material to be consumed")
+	}
+
+	refresh := marshalPacketRequest(t, &protobuf.CS_25010{Commanderid: proto.Uint32(target.GetId())})
+	if _, _, err := MeowfficerRefreshAbilities(&refresh, client); err != nil {
+		t.Fatalf("refresh abilities failed: %v", err)
+	}
+	refreshed := &protobuf.SC_25011{}
+	decodePacketMessage(t, client, 25011, refreshed)
+	client.Buffer.Reset()
+	if refreshed.GetResult() != meowfficerResultOK || len(refreshed.GetAbilityid()) != 1 || refreshed.GetAbilityid()[0] != 21 {
+		t.Fatalf("unexpected refresh response: %v", refreshed)
+	}
+	learn := marshalPacketRequest(t, &protobuf.CS_25012{Commanderid: proto.Uint32(target.GetId()), Targetid: proto.Uint32(21), Replaceid: proto.Uint32(0)})
+	if _, _, err := MeowfficerLearnAbility(&learn, client); err != nil {
+		t.Fatalf("learn ability failed: %v", err)
+	}
+	learned := &protobuf.SC_25013{}
+	decodePacketMessage(t, client, 25013, learned)
+	client.Buffer.Reset()
+	if learned.GetResult() != meowfficerResultOK {
+		t.Fatalf("expected talent to be learned, got %d", learned.GetResult())
+	}
+	upgraded, err = orm.GetMeowfficer(client.Commander.CommanderID, target.GetId())
+	if err != nil {
+		t.Fatalf("get meowfficer: %v", err)
+	}
+	if len(upgraded.Abilities) != 2 || upgraded.UsedPt != 10 {
+		t.Fatalf("unexpected talents: %+v", upgraded)
+	}
+}
+
+func TestMeowfficerFleetAndHome(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedMeowfficerConfig(t)
+	meowfficer := openMeowfficerBox(t, client)
+
+	if err := orm.CreateFleet(client.Commander, 1, "Main", []uint32{}); err != nil {
+		t.Fatalf("create fleet: %v", err)
+	}
+	assign := marshalPacketRequest(t, &protobuf.CS_25006{Groupid: proto.Uint32(1), Pos: proto.Uint32(2), Commanderid: proto.Uint32(meowfficer.GetId())})
+	if _, _, err := MeowfficerSetFleet(&assign, client); err != nil {
+		t.Fatalf("set fleet failed: %v", err)
+	}
+	assigned := &protobuf.SC_25007{}
+	decodePacketMessage(t, client, 25007, assigned)
+	client.Buffer.Reset()
+	if assigned.GetResult() != meowfficerResultOK {
+		t.Fatalf("expected assignment to succeed, got %d", assigned.GetResult())
+	}
+	positions := meowfficerPositions(client.Commander.FleetsMap[1].MeowfficerList)
+	if len(positions) != 1 || positions[0].GetPos() != 2 || positions[0].GetId() != meowfficer.GetId() {
+		t.Fatalf("unexpected fleet meowfficers: %v", positions)
+	}
+
+	place := marshalPacketRequest(t, &protobuf.CS_25030{Slotidx: proto.Uint32(1), CommanderId: proto.Uint32(meowfficer.GetId())})
+	if _, _, err := MeowfficerHomeSetSlot(&place, client); err != nil {
+		t.Fatalf("set slot failed: %v", err)
+	}
+	placed := &protobuf.SC_25031{}
+	decodePacketMessage(t, client, 25031, placed)
+	client.Buffer.Reset()
+	if placed.GetResult() != meowfficerResultOK {
+		t.Fatalf("expected meowfficer to be placed, got %d", placed.GetResult())
+	}
+
+	feed := func() *protobuf.SC_25029 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_25028{Type: proto.Uint32(meowfficerHomeOpFeed)})
+		if _, _, err := MeowfficerHomeOp(&payload, client); err != nil {
+			t.Fatalf("home op failed: %v", err)
+		}
+		response := &protobuf.SC_25029{}
+		decodePacketMessage(t, client, 25029, response)
+		client.Buffer.Reset()
+		return response
+	}
+	if response := feed(); response.GetResult() != meowfficerResultOK || response.GetLevel() != 2 {
+		t.Fatalf("unexpected feed response: %v", response)
+	}
+	if response := feed(); response.GetResult() != meowfficerResultFailed {
+		t.Fatalf("expected feeding cooldown, got %v", response)
+	}
+	fed, err := orm.GetMeowfficer(client.Commander.CommanderID, meowfficer.GetId())
+	if err != nil {
+		t.Fatalf("get meowfficer: %v", err)
+	}
+	if fed.Exp != 30 || fed.HomeFeedTime == 0 {
+		t.Fatalf("unexpected fed meowfficer: %+v", fed)
+	}
+
+	home := marshalPacketRequest(t, &protobuf.CS_25026{Type: proto.Uint32(0)})
+	if _, _, err := GetCommanderHome(&home, client); err != nil {
+		t.Fatalf("get commander home failed: %v", err)
+	}
+	homeResponse := &protobuf.SC_25027{}
+	decodePacketMessage(t, client, 25027, homeResponse)
+	client.Buffer.Reset()
+	if homeResponse.GetLevel() != 2 || len(homeResponse.GetSlots()) != 3 || homeResponse.GetSlots()[0].GetCommanderId() != meowfficer.GetId() {
+		t.Fatalf("unexpected home response: %v", homeResponse)
+	}
+}
@@ -74,6 +74,9 @@ func ModShip(buffer *[]byte, client *connection.Client) (int, int, error) {
 
 	applyStrengthUpdates(ship, updates)
 	removeOwnedShips(client.Commander, materialIDs)
+	if err := recordTaskEvent(client, taskEventEnhance, 0, 1); err != nil {
+		return 0, 12017, err
+	}
 	response.Result = proto.Uint32(0)
 	return client.SendMessage(12018, &response)
 }
@@ -92,9 +92,7 @@ func MonthShopPurchase(buffer *[]byte, client *connection.Client) (int, int, err
 		errDBError      = 5
 	)
 
-	sentinelInsufficient := errors.New("insufficient")
 	sentinelLimit := errors.New("limit")
-	sentinelUnsupported := errors.New("unsupported")
 
 	monthKey := uint32(time.Now().Year()*100 + int(time.Now().Month()))
 	commanderID := client.Commander.CommanderID
@@ -110,65 +108,22 @@ func MonthShopPurchase(buffer *[]byte, client *connection.Client) (int, int, err
 			}
 		}
 
-		switch good.ResourceCategory {
-		case 1:
-			if !client.Commander.HasEnoughResource(good.ResourceType, totalCost) {
-				return sentinelInsufficient
-			}
-			if err := client.Commander.ConsumeResourceTx(ctx, tx, good.ResourceType, totalCost); err != nil {
-				return err
-			}
-		case 2:
-			if !client.Commander.HasEnoughItem(good.ResourceType, totalCost) {
-				return sentinelInsufficient
-			}
-			if err := client.Commander.ConsumeItemTx(ctx, tx, good.ResourceType, totalCost); err != nil {
-				return err
-			}
-		default:
-			return sentinelUnsupported
+		if err := consumeShopCostTx(ctx, tx, client, good.ResourceCategory, good.ResourceType, totalCost); err != nil {
+			return err
 		}
-
-		switch good.CommodityType {
-		case consts.DROP_TYPE_RESOURCE:
-			if err := client.Commander.AddResourceTx(ctx, tx, good.CommodityID, rewardAmount); err != nil {
-				return err
-			}
-		case consts.DROP_TYPE_ITEM:
-			if err := client.Commander.AddItemTx(ctx, tx, good.CommodityID, rewardAmount); err != nil {
-				return err
-			}
-		case consts.DROP_TYPE_SHIP:
-			for i := uint32(0); i < rewardAmount; i++ {
-				if _, err := client.Commander.AddShipTx(ctx, tx, good.CommodityID); err != nil {
-					return err
-				}
-			}
-		case consts.DROP_TYPE_SKIN:
-			for i := uint32(0); i < rewardAmount; i++ {
-				if err := client.Commander.GiveSkinTx(ctx, tx, good.CommodityID); err != nil {
-					return err
-				}
-			}
-		case consts.DROP_TYPE_FURNITURE:
-			if err := orm.AddCommanderFurnitureTx(ctx, tx, commanderID, good.CommodityID, rewardAmount, uint32(time.Now().Unix())); err != nil {
-				return err
-			}
-		case consts.DROP_TYPE_VITEM:
-			return sentinelUnsupported
-		default:
-			return sentinelUnsupported
+		if err := grantShopCommodityTx(ctx, tx, client, good.CommodityType, good.CommodityID, rewardAmount); err != nil {
+			return err
 		}
 
 		return orm.IncrementMonthShopPurchaseTx(ctx, tx, commanderID, payload.GetId(), monthKey, payload.GetCount())
 	})
 	if err != nil {
 		switch {
-		case errors.Is(err, sentinelInsufficient):
+		case errors.Is(err, errShopInsufficient):
 			response.Result = proto.Uint32(errInsufficient)
 		case errors.Is(err, sentinelLimit):
 			response.Result = proto.Uint32(errLimit)
-		case errors.Is(err, sentinelUnsupported):
+		case errors.Is(err, errShopUnsupported):
 			response.Result = proto.Uint32(errUnsupported)
 		default:
 			response.Result = proto.Uint32(errDBError)
@@ -177,9 +132,64 @@ func MonthShopPurchase(buffer *[]byte, client *connection.Client) (int, int, err
 		return client.SendMessage(16202, &response)
 	}
 
+	if err := recordTaskEvent(client, taskEventShopPurchase, 0, 1); err != nil {
+		return 0, 16202, err
+	}
 	return client.SendMessage(16202, &response)
 }
 
+var (
+	errShopInsufficient = errors.New("insufficient")
+	errShopUnsupported  = errors.New("unsupported")
+)
+
+// consumeShopCostTx takes the price of a shop good, paid in resources
+// (category 1) or items (category 2).
+func consumeShopCostTx(ctx context.Context, tx pgx.Tx, client *connection.Client, category uint32, id uint32, amount uint32) error {
+	switch category {
+	case 1:
+		if !client.Commander.HasEnoughResource(id, amount) {
+			return errShopInsufficient
+		}
+		return client.Commander.ConsumeResourceTx(ctx, tx, id, amount)
+	case 2:
+		if !client.Commander.HasEnoughItem(id, amount) {
+			return errShopInsufficient
+		}
+		return client.Commander.ConsumeItemTx(ctx, tx, id, amount)
+	default:
+		return errShopUnsupported
+	}
+}
+
+// grantShopCommodityTx gives amount of a bought good to the commander.
+func grantShopCommodityTx(ctx context.Context, tx pgx.Tx, client *connection.Client, commodityType uint32, id uint32, amount uint32) error {
+	switch commodityType {
+	case consts.DROP_TYPE_RESOURCE:
+		return client.Commander.AddResourceTx(ctx, tx, id, amount)
+	case consts.DROP_TYPE_ITEM:
+		return client.Commander.AddItemTx(ctx, tx, id, amount)
+	case consts.DROP_TYPE_SHIP:
+		for i := uint32(0); i < amount; i++ {
+			if _, err := client.Commander.AddShipTx(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	case consts.DROP_TYPE_SKIN:
+		for i := uint32(0); i < amount; i++ {
+			if err := client.Commander.GiveSkinTx(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	case consts.DROP_TYPE_FURNITURE:
+		return orm.AddCommanderFurnitureTx(ctx, tx, client.Commander.CommanderID, id, amount, uint32(time.Now().Unix()))
+	default:
+		return errShopUnsupported
+	}
+}
+
 func buildDrop(typ uint32, id uint32, amount uint32) *protobuf.DROPINFO {
 	return &protobuf.DROPINFO{Type: proto.Uint32(typ), Id: proto.Uint32(id), Number: proto.Uint32(amount)}
 }
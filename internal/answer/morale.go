@@ -0,0 +1,138 @@
+package answer
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// Morale recovers by ticks every 6 minutes, there is no passive decay. The
+// recovery rate and cap of a ship depend on where it is:
+//
+//	outside the dorm   +2 per tick, up to 119
+//	dorm 1F (training) +3 per tick, up to 150
+//	dorm 2F (resting)  +4 per tick, up to 150
+//
+// Oathed ships recover up to 200 in the dorm. Ships in the onsen recover one
+// more point per tick (+10 per hour) and at least up to 150, wherever they
+// are. The caps only stop recovery: a ship above its cap keeps its morale
+// until it fights or moves.
+const (
+	moraleTickSeconds = 6 * 60
+
+	moraleOutsideRate = 2
+	moraleOutsideCap  = 119
+	moraleDorm1FRate  = 3
+	moraleDorm2FRate  = 4
+	moraleDormCap     = 150
+	moraleOathDormCap = 200
+	moraleOnsenBonus  = 1
+	moraleOnsenCap    = 150
+
+	shipStateDormRest  = 2
+	shipStateDormTrain = 5
+)
+
+type moraleRule struct {
+	perTick uint32
+	cap     uint32
+}
+
+func moraleRuleFor(ship orm.MoraleShip) moraleRule {
+	var rule moraleRule
+	switch ship.State {
+	case shipStateDormTrain:
+		rule = moraleRule{perTick: moraleDorm1FRate, cap: moraleDormCap}
+	case shipStateDormRest:
+		rule = moraleRule{perTick: moraleDorm2FRate, cap: moraleDormCap}
+	default:
+		rule = moraleRule{perTick: moraleOutsideRate, cap: moraleOutsideCap}
+	}
+	if ship.Propose && rule.cap == moraleDormCap {
+		rule.cap = moraleOathDormCap
+	}
+	if ship.InOnsen {
+		rule.perTick += moraleOnsenBonus
+		rule.cap = max(rule.cap, moraleOnsenCap)
+	}
+	return rule
+}
+
+// regenMorale returns the morale of a ship after ticks recovery ticks.
+func regenMorale(energy uint32, rule moraleRule, ticks uint32) uint32 {
+	if energy >= rule.cap {
+		return energy
+	}
+	recovered := uint64(energy) + uint64(rule.perTick)*uint64(ticks)
+	return uint32(min(recovered, uint64(rule.cap)))
+}
+
+// moraleTickFloor returns the start of the tick containing now; ticks are
+// aligned on the clock so every commander ticks at the same time.
+func moraleTickFloor(now int64) int64 {
+	return now - now%moraleTickSeconds
+}
+
+// recoverMoraleTx applies the ticks elapsed since the last recovery of a
+// commander. It returns the new morale of the ships that recovered and the
+// time of the next tick.
+func recoverMoraleTx(ctx context.Context, tx pgx.Tx, commanderID uint32, now int64) (map[uint32]uint32, int64, error) {
+	last, err := orm.GetMoraleTickTx(ctx, tx, commanderID)
+	if err != nil {
+		return nil, 0, err
+	}
+	current := moraleTickFloor(now)
+	if last == 0 || last > current {
+		// first recovery, or the clock went back: start counting from now
+		return nil, current + moraleTickSeconds, orm.SetMoraleTickTx(ctx, tx, commanderID, current)
+	}
+	ticks := uint32((current - last) / moraleTickSeconds)
+	if ticks == 0 {
+		return nil, current + moraleTickSeconds, nil
+	}
+	ships, err := orm.ListMoraleShipsTx(ctx, tx, commanderID)
+	if err != nil {
+		return nil, 0, err
+	}
+	updates := make(map[uint32]uint32)
+	for _, ship := range ships {
+		if energy := regenMorale(ship.Energy, moraleRuleFor(ship), ticks); energy != ship.Energy {
+			updates[ship.ID] = energy
+		}
+	}
+	if len(updates) > 0 {
+		if err := orm.SaveShipEnergiesTx(ctx, tx, commanderID, updates); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := orm.SetMoraleTickTx(ctx, tx, commanderID, current); err != nil {
+		return nil, 0, err
+	}
+	return updates, current + moraleTickSeconds, nil
+}
+
+// recoverMorale applies the pending morale ticks of the client's commander,
+// keeping its loaded ships in sync, and returns the time of the next tick.
+func recoverMorale(client *connection.Client) (int64, error) {
+	ctx := context.Background()
+	var updates map[uint32]uint32
+	var next int64
+	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		updates, next, err = recoverMoraleTx(ctx, tx, client.Commander.CommanderID, time.Now().Unix())
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for shipID, energy := range updates {
+		if owned, ok := client.Commander.OwnedShipsMap[shipID]; ok {
+			owned.Energy = energy
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,98 @@
+package answer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func TestMoraleRuleFor(t *testing.T) {
+	cases := []struct {
+		name string
+		ship orm.MoraleShip
+		want moraleRule
+	}{
+		{name: "outside", ship: orm.MoraleShip{}, want: moraleRule{perTick: 2, cap: 119}},
+		{name: "oathed outside", ship: orm.MoraleShip{Propose: true}, want: moraleRule{perTick: 2, cap: 119}},
+		{name: "dorm 1F", ship: orm.MoraleShip{State: shipStateDormTrain}, want: moraleRule{perTick: 3, cap: 150}},
+		{name: "dorm 2F", ship: orm.MoraleShip{State: shipStateDormRest}, want: moraleRule{perTick: 4, cap: 150}},
+		{name: "oathed in dorm", ship: orm.MoraleShip{State: shipStateDormRest, Propose: true}, want: moraleRule{perTick: 4, cap: 200}},
+		{name: "onsen outside", ship: orm.MoraleShip{InOnsen: true}, want: moraleRule{perTick: 3, cap: 150}},
+		{name: "onsen oathed in dorm", ship: orm.MoraleShip{State: shipStateDormTrain, Propose: true, InOnsen: true}, want: moraleRule{perTick: 4, cap: 200}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := moraleRuleFor(tc.ship); got != tc.want {
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRegenMorale(t *testing.T) {
+	outside := moraleRule{perTick: 2, cap: 119}
+	if got := regenMorale(100, outside, 5); got != 110 {
+		t.Fatalf("expected 110, got %d", got)
+	}
+	if got := regenMorale(100, outside, 50); got != 119 {
+		t.Fatalf("expected recovery to stop at the cap, got %d", got)
+	}
+	if got := regenMorale(140, outside, 10); got != 140 {
+		t.Fatalf("expected morale above the cap to be kept, got %d", got)
+	}
+	if got := regenMorale(0, outside, ^uint32(0)); got != 119 {
+		t.Fatalf("expected large tick counts not to overflow, got %d", got)
+	}
+}
+
+func TestMoraleTickFloor(t *testing.T) {
+	if got := moraleTickFloor(1000); got != 720 {
+		t.Fatalf("expected 720, got %d", got)
+	}
+	if got := moraleTickFloor(720); got != 720 {
+		t.Fatalf("expected 720, got %d", got)
+	}
+}
+
+func TestFleetEnergyRecoverTimeAppliesTicks(t *testing.T) {
+	client := setupHandlerCommander(t)
+	commanderID := client.Commander.CommanderID
+	execAnswerTestSQLT(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)", int64(1001), "Test DD", "Test DD", int64(3), int64(1), int64(1), int64(1), int64(0))
+	execAnswerTestSQLT(t, "INSERT INTO owned_ships (id, owner_id, ship_id, level, max_level, energy, create_time, change_name_timestamp) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())", int64(101), int64(commanderID), int64(1001), int64(1), int64(100), int64(50))
+	execAnswerTestSQLT(t, "INSERT INTO owned_ships (id, owner_id, ship_id, level, max_level, energy, state, create_time, change_name_timestamp) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())", int64(102), int64(commanderID), int64(1001), int64(1), int64(100), int64(50), int64(shipStateDormRest))
+	if err := client.Commander.Load(); err != nil {
+		t.Fatalf("reload commander: %v", err)
+	}
+	current := moraleTickFloor(time.Now().Unix())
+	execAnswerTestSQLT(t, "UPDATE commanders SET morale_tick_at = $2 WHERE commander_id = $1", int64(commanderID), current-5*moraleTickSeconds)
+
+	buffer := []byte{}
+	if _, _, err := FleetEnergyRecoverTime(&buffer, client); err != nil {
+		t.Fatalf("fleet energy recover time failed: %v", err)
+	}
+	var response protobuf.SC_12031
+	decodeResponse(t, client, &response)
+	if next := int64(response.GetEnergyAutoIncreaseTime()); next != current+moraleTickSeconds && next != current+2*moraleTickSeconds {
+		t.Fatalf("unexpected next tick %d (current %d)", next, current)
+	}
+	if energy := queryAnswerTestInt64(t, "SELECT energy FROM owned_ships WHERE id = 101"); energy != 60 {
+		t.Fatalf("expected outside ship at 60, got %d", energy)
+	}
+	if energy := queryAnswerTestInt64(t, "SELECT energy FROM owned_ships WHERE id = 102"); energy != 70 {
+		t.Fatalf("expected resting ship at 70, got %d", energy)
+	}
+	if client.Commander.OwnedShipsMap[101].Energy != 60 {
+		t.Fatalf("expected loaded ship to be updated, got %d", client.Commander.OwnedShipsMap[101].Energy)
+	}
+
+	// a second read within the same tick changes nothing
+	client.Buffer.Reset()
+	if _, _, err := FleetEnergyRecoverTime(&buffer, client); err != nil {
+		t.Fatalf("fleet energy recover time failed: %v", err)
+	}
+	if energy := queryAnswerTestInt64(t, "SELECT energy FROM owned_ships WHERE id = 101"); energy != 60 && energy != 62 {
+		t.Fatalf("expected no extra recovery, got %d", energy)
+	}
+}
@@ -10,6 +10,9 @@ import (
 var validSC12001 protobuf.SC_12001
 
 func PlayerDock(buffer *[]byte, client *connection.Client) (int, int, error) {
+	if _, err := recoverMorale(client); err != nil {
+		return 0, 12001, err
+	}
 	// Send first 100 ships
 	maxSlice := 101
 	if len(client.Commander.Ships) < maxSlice {
@@ -17,6 +17,14 @@ func PlayerInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
 	if err := ensureGuideIndices(client.Commander); err != nil {
 		return 0, 11003, err
 	}
+	shipBagBonus, err := guildTechBonus(client.Commander.CommanderID, guildTechEffectShipBag)
+	if err != nil {
+		return 0, 11003, err
+	}
+	equipBagBonus, err := guildTechBonus(client.Commander.CommanderID, guildTechEffectEquipBag)
+	if err != nil {
+		return 0, 11003, err
+	}
 
 	response := protobuf.SC_11003{
 		Id:                 proto.Uint32(uint32(client.Commander.CommanderID)),
@@ -27,8 +35,8 @@ func PlayerInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
 		AttackCount:        proto.Uint32(0),
 		WinCount:           proto.Uint32(0),
 		Adv:                proto.String(client.Commander.Manifesto),
-		ShipBagMax:         proto.Uint32(250),
-		EquipBagMax:        proto.Uint32(250),
+		ShipBagMax:         proto.Uint32(250 + shipBagBonus),
+		EquipBagMax:        proto.Uint32(equipBagMax + equipBagBonus),
 		GmFlag:             proto.Uint32(0),
 		Rank:               proto.Uint32(0),
 		PvpAttackCount:     proto.Uint32(0),
@@ -40,7 +48,7 @@ func PlayerInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
 		MaxRank:            proto.Uint32(0),
 		RegisterTime:       proto.Uint32(0),
 		ShipCount:          proto.Uint32(uint32(len(client.Commander.Ships))),
-		AccPayLv:           proto.Uint32(0),
+		AccPayLv:           proto.Uint32(client.Commander.AccPayLv),
 		GuildWaitTime:      proto.Uint32(0),
 		ChatMsgBanTime:     proto.Uint32(0),
 		CommanderBagMax:    proto.Uint32(250),
@@ -116,6 +124,11 @@ func PlayerInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
 		return 0, 11003, err
 	}
 	response.MedalId = medalIDs
+	refunds, err := refundShopInfoList(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 11003, err
+	}
+	response.RefundShopInfoList = refunds
 	appreciationState, err := orm.GetOrCreateCommanderAppreciationState(client.Commander.CommanderID)
 	if err != nil {
 		return 0, 11003, err
@@ -1,10 +1,12 @@
 package answer
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ggmolly/belfast/internal/chat"
 	"github.com/ggmolly/belfast/internal/connection"
-	"github.com/ggmolly/belfast/internal/orm"
 
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
@@ -16,10 +18,15 @@ func ReceiveChatMessage(buffer *[]byte, client *connection.Client) (int, int, er
 	if err != nil {
 		return 0, 50101, fmt.Errorf("invalid CS_50102 packet: %s", err.Error())
 	}
-	msg, err := orm.SendMessage(client.Commander.RoomID, data.GetContent(), client.Commander)
+	now := time.Now()
+	msg, mute, err := chat.Post(client.Commander, client.Commander.RoomID, data.GetContent(), now)
+	if errors.Is(err, chat.ErrMuted) {
+		// only the sender is told, the room never sees the message
+		return client.SendMessage(50101, chat.MutedMessage(mute, now))
+	}
 	if err != nil {
 		return 0, 50101, fmt.Errorf("unable to save message: %s", err.Error())
 	}
-	client.Server.SendMessage(client, *msg)
+	client.Server.BroadcastChat(msg.RoomID, chat.MessagePacket(*msg))
 	return 0, 50101, nil
 }
@@ -22,6 +22,9 @@ func RetireShip(buffer *[]byte, client *connection.Client) (int, int, error) {
 		logger.LogEvent("RetireShip", "Fail", err.Error(), logger.LOG_LEVEL_ERROR)
 	} else {
 		answer.ShipIdList = data.ShipIdList
+		if err := recordTaskEvent(client, taskEventRetire, 0, uint32(len(data.ShipIdList))); err != nil {
+			return 0, 12005, err
+		}
 	}
 	return client.SendMessage(12005, &answer)
 }
@@ -26,6 +26,9 @@ func SendCmd(buffer *[]byte, client *connection.Client) (int, int, error) {
 		response.Msg = proto.String("CMD:into Result:ok")
 	case "world":
 		if payload.GetArg1() == "reset" {
+			if err := resetWorld(client); err != nil {
+				return 0, 11101, err
+			}
 			response.Result = proto.Uint32(0)
 			response.Msg = proto.String("CMD:world Result:ok")
 		} else {
@@ -76,6 +76,10 @@ func resolveServerStatus(server config.ServerConfig) serverStatusEntry {
 	}
 	entry.ServerLoad = probe.ServerLoad
 	entry.DBLoad = probe.DBLoad
+	if probe.ServerLoad >= 100 {
+		entry.State = SERVER_STATE_FULL
+		return entry
+	}
 	if probe.ServerLoad >= 80 || probe.DBLoad >= 80 {
 		entry.State = SERVER_STATE_BUSY
 		return entry
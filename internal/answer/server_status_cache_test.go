@@ -27,6 +27,7 @@ func TestServerStatusCacheMappingFromProbeLoads(t *testing.T) {
 		{name: "online", displayName: "Belfast", serverLoad: 10, dbLoad: 20, expected: SERVER_STATE_ONLINE},
 		{name: "busy by server load", displayName: "Suffolk", serverLoad: 80, dbLoad: 0, expected: SERVER_STATE_BUSY},
 		{name: "busy by db load", displayName: "Javelin", serverLoad: 0, dbLoad: 80, expected: SERVER_STATE_BUSY},
+		{name: "full by server load", displayName: "Laffey", serverLoad: 100, dbLoad: 0, expected: SERVER_STATE_FULL},
 	}
 
 	for _, tt := range tests {
@@ -4,27 +4,61 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/ticket"
 )
 
 const serverTicketPrefix = "=*=*=*=BELFAST=*=*=*="
 
-func formatServerTicket(arg2 uint32) string {
+// formatServerTicket returns the ticket of the account arg2 for serverID.
+// Tickets are HMAC-signed and expire once ticket keys are configured;
+// without keys the account is embedded as-is.
+func formatServerTicket(arg2 uint32, serverID uint32) string {
 	if arg2 == 0 {
 		return serverTicketPrefix
 	}
+	if ticket.Default.Enabled() {
+		value, err := ticket.Default.Issue(arg2, serverID, time.Now())
+		if err != nil {
+			logger.LogEvent("Server", "Ticket", fmt.Sprintf("failed to sign ticket: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+			return serverTicketPrefix
+		}
+		return value
+	}
 	// embed arg2 so later connections can recover account identity
 	return fmt.Sprintf("%s:%d", serverTicketPrefix, arg2)
 }
 
-func parseServerTicket(ticket string) uint32 {
-	if !strings.HasPrefix(ticket, serverTicketPrefix+":") {
+// parseServerTicket returns the account of a ticket presented to serverID,
+// or 0 when the ticket is invalid.
+func parseServerTicket(value string, serverID uint32) uint32 {
+	if ticket.Default.Enabled() {
+		claims, err := ticket.Default.Verify(value, serverID, time.Now())
+		if err != nil {
+			return 0
+		}
+		return claims.Arg2
+	}
+	if !strings.HasPrefix(value, serverTicketPrefix+":") {
 		return 0
 	}
 	// parse arg2 from the suffix, if present
-	value := strings.TrimPrefix(ticket, serverTicketPrefix+":")
-	arg2, err := strconv.ParseUint(value, 10, 32)
+	suffix := strings.TrimPrefix(value, serverTicketPrefix+":")
+	arg2, err := strconv.ParseUint(suffix, 10, 32)
 	if err != nil {
 		return 0
 	}
 	return uint32(arg2)
 }
+
+// serverTicketTarget is the server a login ticket is minted for: the only
+// listed server, or any server (0) when the client still has to pick one.
+func serverTicketTarget(servers []config.ServerConfig) uint32 {
+	if len(servers) == 1 {
+		return servers[0].ID
+	}
+	return 0
+}
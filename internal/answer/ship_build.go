@@ -71,5 +71,8 @@ func ShipBuild(buffer *[]byte, client *connection.Client) (int, int, error) {
 	if err := client.Commander.IncrementDrawCount(data.GetCount()); err != nil {
 		return 0, 12003, err
 	}
+	if err := recordTaskEvent(client, taskEventBuild, data.GetId(), data.GetCount()); err != nil {
+		return 0, 12003, err
+	}
 	return client.SendMessage(12003, &response)
 }
@@ -116,6 +116,9 @@ WHERE commander_id = $1 AND goods_id = $2 AND buy_count > 0
 		}
 		client.Commander.ConsumeResource(shopOffer.ResourceID, uint32(shopOffer.ResourceNumber))
 		logger.LogEvent("Shop", "Purchase", fmt.Sprintf("uid=%d bought #%d successfully!", client.Commander.CommanderID, shopOffer.ID), logger.LOG_LEVEL_INFO)
+		if err := recordTaskEvent(client, taskEventShopPurchase, 0, 1); err != nil {
+			return 0, 16002, err
+		}
 	}
 
 	return client.SendMessage(16002, &response)
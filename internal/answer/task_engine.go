@@ -0,0 +1,565 @@
+package answer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	taskTemplateCategory     = "sharecfgdata/task_data_template.json"
+	weeklyTaskRewardCategory = "ShareCfg/weekly_task_reward.json"
+
+	taskTypeDaily  = uint32(3)
+	taskTypeWeekly = uint32(4)
+)
+
+// Task sub types whose progress is raised by the server itself. target_id
+// narrows battle wins to a battle system, builds to a pool and commissions
+// to a collection; 0 matches any target.
+const (
+	taskEventBattleWin    = uint32(1)
+	taskEventBuild        = uint32(2)
+	taskEventRetire       = uint32(3)
+	taskEventEnhance      = uint32(4)
+	taskEventCommission   = uint32(5)
+	taskEventShopPurchase = uint32(6)
+)
+
+// taskTemplate is a task_data_template entry. target_id and next_task are
+// numbers or numeric strings depending on the data revision, award_display
+// is a list of [type, id, count] drops and weekly tasks also grant weekly_pt
+// mission points once submitted.
+type taskTemplate struct {
+	ID           uint32          `json:"id"`
+	Type         uint32          `json:"type"`
+	SubType      uint32          `json:"sub_type"`
+	TargetID     json.RawMessage `json:"target_id"`
+	TargetNum    uint32          `json:"target_num"`
+	AwardDisplay [][]uint32      `json:"award_display"`
+	NextTask     json.RawMessage `json:"next_task"`
+	Level        uint32          `json:"level"`
+	WeeklyPt     uint32          `json:"weekly_pt"`
+}
+
+func (t *taskTemplate) targetID() uint32 {
+	value, _ := parseUint32Raw(t.TargetID)
+	return value
+}
+
+func (t *taskTemplate) nextTask() uint32 {
+	value, _ := parseUint32Raw(t.NextTask)
+	return value
+}
+
+// weeklyTaskReward is a reward tier of the weekly missions, unlocked once
+// the commander collected pt points this week.
+type weeklyTaskReward struct {
+	ID           uint32     `json:"id"`
+	Pt           uint32     `json:"pt"`
+	AwardDisplay [][]uint32 `json:"award_display"`
+}
+
+func loadTaskTemplate(taskID uint32) (*taskTemplate, error) {
+	cached, err := gamedata.Get[taskTemplate](gamedata.Default, taskTemplateCategory, strconv.FormatUint(uint64(taskID), 10))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template := *cached
+	return &template, nil
+}
+
+func loadWeeklyTaskReward(id uint32) (*weeklyTaskReward, error) {
+	cached, err := gamedata.Get[weeklyTaskReward](gamedata.Default, weeklyTaskRewardCategory, strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	reward := *cached
+	return &reward, nil
+}
+
+// taskChainStarts returns the first task of every chain of taskType the
+// commander level allows, i.e. the tasks no other task points to.
+func taskChainStarts(taskType uint32, level uint32) ([]uint32, error) {
+	entries, err := gamedata.List[taskTemplate](gamedata.Default, taskTemplateCategory)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]taskTemplate, 0)
+	chained := make(map[uint32]struct{})
+	for _, template := range entries {
+		if template.Type != taskType {
+			continue
+		}
+		templates = append(templates, template)
+		if next := template.nextTask(); next != 0 {
+			chained[next] = struct{}{}
+		}
+	}
+	ids := make([]uint32, 0)
+	for _, template := range templates {
+		if _, ok := chained[template.ID]; ok || template.ID == 0 || template.Level > level {
+			continue
+		}
+		ids = append(ids, template.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// ensureCommanderTasks rebuilds the daily tasks once per UTC day and the
+// weekly tasks once per week, then returns the task state.
+func ensureCommanderTasks(client *connection.Client, now time.Time) (*orm.CommanderTaskState, error) {
+	commanderID := client.Commander.CommanderID
+	state, err := orm.GetOrCreateCommanderTaskState(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	resetDaily := orm.ApplyCommanderTaskDailyReset(state, now)
+	resetWeekly := orm.ApplyCommanderTaskWeeklyReset(state, now)
+	if !resetDaily && !resetWeekly {
+		return state, nil
+	}
+	tasks, err := orm.ListCommanderTasks(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := loadCommanderTaskTemplates(tasks)
+	if err != nil {
+		return nil, err
+	}
+	removed := make([]uint32, 0)
+	for _, task := range tasks {
+		template := templates[task.TaskID]
+		if template == nil {
+			continue
+		}
+		if (resetDaily && template.Type == taskTypeDaily) || (resetWeekly && template.Type == taskTypeWeekly) {
+			removed = append(removed, task.TaskID)
+		}
+	}
+	added := make([]uint32, 0)
+	level := uint32(client.Commander.Level)
+	if resetDaily {
+		ids, err := taskChainStarts(taskTypeDaily, level)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, ids...)
+	}
+	if resetWeekly {
+		ids, err := taskChainStarts(taskTypeWeekly, level)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, ids...)
+	}
+	acceptTime := uint32(now.Unix())
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteCommanderTasksTx(ctx, tx, commanderID, removed); err != nil {
+			return err
+		}
+		for _, id := range added {
+			task := orm.CommanderTask{CommanderID: commanderID, TaskID: id, AcceptTime: acceptTime}
+			if err := orm.UpsertCommanderTaskTx(ctx, tx, &task); err != nil {
+				return err
+			}
+		}
+		return orm.SaveCommanderTaskStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func loadCommanderTaskTemplates(tasks []orm.CommanderTask) (map[uint32]*taskTemplate, error) {
+	templates := make(map[uint32]*taskTemplate, len(tasks))
+	for _, task := range tasks {
+		template, err := loadTaskTemplate(task.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if template != nil {
+			templates[task.TaskID] = template
+		}
+	}
+	return templates, nil
+}
+
+// recordTaskEvent raises the progress of every open task tracking event on
+// target by count, and pushes the new progress to the client.
+func recordTaskEvent(client *connection.Client, event uint32, target uint32, count uint32) error {
+	if count == 0 {
+		return nil
+	}
+	if _, err := ensureCommanderTasks(client, time.Now()); err != nil {
+		return err
+	}
+	tasks, err := orm.ListCommanderTasks(client.Commander.CommanderID)
+	if err != nil {
+		return err
+	}
+	templates, err := loadCommanderTaskTemplates(tasks)
+	if err != nil {
+		return err
+	}
+	updated := make([]orm.CommanderTask, 0)
+	for _, task := range tasks {
+		template := templates[task.TaskID]
+		if template == nil || task.SubmitTime != 0 || template.SubType != event {
+			continue
+		}
+		if id := template.targetID(); id != 0 && id != target {
+			continue
+		}
+		if task.Progress >= template.TargetNum {
+			continue
+		}
+		task.Progress += count
+		if task.Progress > template.TargetNum {
+			task.Progress = template.TargetNum
+		}
+		updated = append(updated, task)
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for i := range updated {
+			if err := orm.UpsertCommanderTaskTx(ctx, tx, &updated[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	daily := protobuf.SC_20002{Info: []*protobuf.TASK_PROGRESS{}}
+	weekly := protobuf.SC_20102{Task: []*protobuf.WEEKLY_TASK_P20{}}
+	for _, task := range updated {
+		if templates[task.TaskID].Type == taskTypeWeekly {
+			weekly.Task = append(weekly.Task, &protobuf.WEEKLY_TASK_P20{Id: proto.Uint32(task.TaskID), Progress: proto.Uint32(task.Progress)})
+			continue
+		}
+		daily.Info = append(daily.Info, &protobuf.TASK_PROGRESS{Id: proto.Uint32(task.TaskID), Progress: proto.Uint32(task.Progress)})
+	}
+	if len(daily.Info) > 0 {
+		if _, _, err := client.SendMessage(20002, &daily); err != nil {
+			return err
+		}
+	}
+	if len(weekly.Task) > 0 {
+		if _, _, err := client.SendMessage(20102, &weekly); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grantTaskAwards applies [type, id, count] drops and returns the ones the
+// server could grant.
+func grantTaskAwards(client *connection.Client, awards [][]uint32) ([]*protobuf.DROPINFO, error) {
+	drops := make([]*protobuf.DROPINFO, 0, len(awards))
+	for _, award := range awards {
+		if len(award) < 3 || award[2] == 0 {
+			continue
+		}
+		ok, err := applyDrop(client, award[0], award[1], award[2])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			drops = append(drops, newDropInfo(award[0], award[1], award[2]))
+		}
+	}
+	return drops, nil
+}
+
+type taskSubmitResult struct {
+	awards []*protobuf.DROPINFO
+	next   *orm.CommanderTask
+}
+
+// submitCommanderTask claims a finished task of the daily (weekly=false) or
+// weekly list, accepting the next task of its chain. It returns nil when the
+// task cannot be submitted.
+func submitCommanderTask(client *connection.Client, state *orm.CommanderTaskState, taskID uint32, weekly bool, now time.Time) (*taskSubmitResult, error) {
+	commanderID := client.Commander.CommanderID
+	task, err := orm.GetCommanderTask(commanderID, taskID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template, err := loadTaskTemplate(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || (template.Type == taskTypeWeekly) != weekly {
+		return nil, nil
+	}
+	if task.SubmitTime != 0 || task.Progress < template.TargetNum {
+		return nil, nil
+	}
+	result := taskSubmitResult{}
+	if nextID := template.nextTask(); nextID != 0 {
+		next, err := loadTaskTemplate(nextID)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			result.next = &orm.CommanderTask{CommanderID: commanderID, TaskID: nextID, AcceptTime: uint32(now.Unix())}
+		}
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.SubmitCommanderTaskTx(ctx, tx, commanderID, taskID, uint32(now.Unix())); err != nil {
+			return err
+		}
+		if result.next != nil {
+			if err := orm.UpsertCommanderTaskTx(ctx, tx, result.next); err != nil {
+				return err
+			}
+		}
+		if weekly && template.WeeklyPt > 0 {
+			state.WeeklyPt += template.WeeklyPt
+			return orm.SaveCommanderTaskStateTx(ctx, tx, state)
+		}
+		return nil
+	})
+	if err != nil {
+		if weekly && template.WeeklyPt > 0 {
+			state.WeeklyPt -= template.WeeklyPt
+		}
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if result.awards, err = grantTaskAwards(client, template.AwardDisplay); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func taskAddInfo(task *orm.CommanderTask) *protobuf.TASK_ADD {
+	return &protobuf.TASK_ADD{
+		Id:         proto.Uint32(task.TaskID),
+		Progress:   proto.Uint32(task.Progress),
+		AcceptTime: proto.Uint32(task.AcceptTime),
+		SubmitTime: proto.Uint32(task.SubmitTime),
+	}
+}
+
+// SubmitTask handles CS_20005.
+func SubmitTask(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20005
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20006, err
+	}
+	response := protobuf.SC_20006{Result: proto.Uint32(1), AwardList: []*protobuf.DROPINFO{}}
+	now := time.Now()
+	state, err := ensureCommanderTasks(client, now)
+	if err != nil {
+		return 0, 20006, err
+	}
+	result, err := submitCommanderTask(client, state, payload.GetId(), false, now)
+	if err != nil {
+		return 0, 20006, err
+	}
+	if result == nil {
+		return client.SendMessage(20006, &response)
+	}
+	if result.next != nil {
+		if _, _, err := client.SendMessage(20003, &protobuf.SC_20003{Info: []*protobuf.TASK_ADD{taskAddInfo(result.next)}}); err != nil {
+			return 0, 20006, err
+		}
+	}
+	response.Result = proto.Uint32(0)
+	response.AwardList = result.awards
+	return client.SendMessage(20006, &response)
+}
+
+// SubmitTasks handles CS_20011, submitting every finished task of the list.
+func SubmitTasks(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20011
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20012, err
+	}
+	response := protobuf.SC_20012{IdList: []uint32{}, AwardList: []*protobuf.DROPINFO{}}
+	now := time.Now()
+	state, err := ensureCommanderTasks(client, now)
+	if err != nil {
+		return 0, 20012, err
+	}
+	added := protobuf.SC_20003{Info: []*protobuf.TASK_ADD{}}
+	for _, id := range payload.GetIdList() {
+		result, err := submitCommanderTask(client, state, id, false, now)
+		if err != nil {
+			return 0, 20012, err
+		}
+		if result == nil {
+			continue
+		}
+		response.IdList = append(response.IdList, id)
+		response.AwardList = append(response.AwardList, result.awards...)
+		if result.next != nil {
+			added.Info = append(added.Info, taskAddInfo(result.next))
+		}
+	}
+	if len(added.Info) > 0 {
+		if _, _, err := client.SendMessage(20003, &added); err != nil {
+			return 0, 20012, err
+		}
+	}
+	return client.SendMessage(20012, &response)
+}
+
+// TriggerTask handles CS_20007, accepting a story task the client unlocked.
+// Daily and weekly tasks are only handed out by the resets.
+func TriggerTask(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20007
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20008, err
+	}
+	response := protobuf.SC_20008{Result: proto.Uint32(1)}
+	template, err := loadTaskTemplate(payload.GetId())
+	if err != nil {
+		return 0, 20008, err
+	}
+	if template == nil || template.Type == taskTypeDaily || template.Type == taskTypeWeekly || template.Level > uint32(client.Commander.Level) {
+		return client.SendMessage(20008, &response)
+	}
+	if _, err := orm.GetCommanderTask(client.Commander.CommanderID, template.ID); err == nil {
+		return client.SendMessage(20008, &response)
+	} else if !db.IsNotFound(err) {
+		return 0, 20008, err
+	}
+	task := orm.CommanderTask{CommanderID: client.Commander.CommanderID, TaskID: template.ID, AcceptTime: uint32(time.Now().Unix())}
+	if err := orm.UpsertCommanderTask(&task); err != nil {
+		return 0, 20008, err
+	}
+	response.Result = proto.Uint32(0)
+	response.Task = taskAddInfo(&task)
+	return client.SendMessage(20008, &response)
+}
+
+func weeklyTaskInfo(task *orm.CommanderTask) *protobuf.WEEKLY_TASK_P20 {
+	return &protobuf.WEEKLY_TASK_P20{Id: proto.Uint32(task.TaskID), Progress: proto.Uint32(task.Progress)}
+}
+
+// SubmitWeeklyTask handles CS_20106.
+func SubmitWeeklyTask(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20106
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20107, err
+	}
+	response := protobuf.SC_20107{Result: proto.Uint32(1)}
+	now := time.Now()
+	state, err := ensureCommanderTasks(client, now)
+	if err != nil {
+		return 0, 20107, err
+	}
+	result, err := submitCommanderTask(client, state, payload.GetId(), true, now)
+	if err != nil {
+		return 0, 20107, err
+	}
+	if result == nil {
+		return client.SendMessage(20107, &response)
+	}
+	if _, _, err := client.SendMessage(20105, &protobuf.SC_20105{Pt: proto.Uint32(state.WeeklyPt)}); err != nil {
+		return 0, 20107, err
+	}
+	response.Result = proto.Uint32(0)
+	if result.next != nil {
+		response.Next = weeklyTaskInfo(result.next)
+	}
+	return client.SendMessage(20107, &response)
+}
+
+// SubmitWeeklyTasks handles CS_20108.
+func SubmitWeeklyTasks(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20108
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20109, err
+	}
+	response := protobuf.SC_20109{Result: proto.Uint32(1), Pt: proto.Uint32(0), Next: []*protobuf.WEEKLY_TASK_P20{}}
+	now := time.Now()
+	state, err := ensureCommanderTasks(client, now)
+	if err != nil {
+		return 0, 20109, err
+	}
+	for _, id := range payload.GetId() {
+		result, err := submitCommanderTask(client, state, id, true, now)
+		if err != nil {
+			return 0, 20109, err
+		}
+		if result == nil {
+			continue
+		}
+		response.Result = proto.Uint32(0)
+		if result.next != nil {
+			response.Next = append(response.Next, weeklyTaskInfo(result.next))
+		}
+	}
+	response.Pt = proto.Uint32(state.WeeklyPt)
+	return client.SendMessage(20109, &response)
+}
+
+// ClaimWeeklyTaskReward handles CS_20110. Tiers are claimed in order, each
+// one once the weekly points reach its threshold.
+func ClaimWeeklyTaskReward(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_20110
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 20111, err
+	}
+	response := protobuf.SC_20111{Result: proto.Uint32(1), AwardList: []*protobuf.DROPINFO{}}
+	state, err := ensureCommanderTasks(client, time.Now())
+	if err != nil {
+		return 0, 20111, err
+	}
+	if payload.GetId() != state.WeeklyRewardLv+1 {
+		return client.SendMessage(20111, &response)
+	}
+	reward, err := loadWeeklyTaskReward(payload.GetId())
+	if err != nil {
+		return 0, 20111, err
+	}
+	if reward == nil || state.WeeklyPt < reward.Pt {
+		return client.SendMessage(20111, &response)
+	}
+	state.WeeklyRewardLv++
+	if err := orm.SaveCommanderTaskState(state); err != nil {
+		return 0, 20111, err
+	}
+	awards, err := grantTaskAwards(client, reward.AwardDisplay)
+	if err != nil {
+		return 0, 20111, err
+	}
+	response.Result = proto.Uint32(0)
+	response.AwardList = awards
+	return client.SendMessage(20111, &response)
+}
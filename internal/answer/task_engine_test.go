@@ -0,0 +1,153 @@
+package answer
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"google.golang.org/protobuf/proto"
+)
+
+func seedTaskEngineConfig(t *testing.T) {
+	t.Helper()
+	seedConfigEntry(t, taskTemplateCategory, "1001", `{"id":1001,"type":3,"sub_type":2,"target_id":"0","target_num":2,"award_display":[[1,1,100]],"next_task":"1002","level":1}`)
+	seedConfigEntry(t, taskTemplateCategory, "1002", `{"id":1002,"type":3,"sub_type":2,"target_id":"0","target_num":5,"award_display":[[1,1,200]],"next_task":"0","level":1}`)
+	seedConfigEntry(t, taskTemplateCategory, "1003", `{"id":1003,"type":3,"sub_type":1,"target_id":"1","target_num":1,"award_display":[],"next_task":"0","level":80}`)
+	seedConfigEntry(t, taskTemplateCategory, "2001", `{"id":2001,"type":4,"sub_type":3,"target_id":0,"target_num":2,"award_display":[[1,1,10]],"next_task":0,"level":1,"weekly_pt":50}`)
+	seedConfigEntry(t, weeklyTaskRewardCategory, "1", `{"id":1,"pt":50,"award_display":[[1,1,5]]}`)
+}
+
+func TestTaskEngineDailyTaskChain(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedTaskEngineConfig(t)
+	goldBefore := client.Commander.GetResourceCount(1)
+
+	empty := []byte{}
+	if _, _, err := CommanderMissions(&empty, client); err != nil {
+		t.Fatalf("CommanderMissions failed: %v", err)
+	}
+	missions := &protobuf.SC_20001{}
+	decodePacketMessage(t, client, 20001, missions)
+	client.Buffer.Reset()
+	if len(missions.GetInfo()) != 1 || missions.GetInfo()[0].GetId() != 1001 {
+		t.Fatalf("expected only the chain start 1001, got %v", missions.GetInfo())
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_20005{Id: proto.Uint32(1001)})
+	if _, _, err := SubmitTask(&payload, client); err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+	submit := &protobuf.SC_20006{}
+	decodePacketMessage(t, client, 20006, submit)
+	client.Buffer.Reset()
+	if submit.GetResult() != 1 {
+		t.Fatalf("expected unfinished task to be rejected, got %d", submit.GetResult())
+	}
+
+	if err := recordTaskEvent(client, taskEventBuild, 2, 3); err != nil {
+		t.Fatalf("recordTaskEvent failed: %v", err)
+	}
+	progress := &protobuf.SC_20002{}
+	decodePacketMessage(t, client, 20002, progress)
+	client.Buffer.Reset()
+	if len(progress.GetInfo()) != 1 || progress.GetInfo()[0].GetProgress() != 2 {
+		t.Fatalf("expected progress capped at 2, got %v", progress.GetInfo())
+	}
+
+	if _, _, err := SubmitTask(&payload, client); err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+	added := &protobuf.SC_20003{}
+	offset := decodePacketAt(t, client, 0, 20003, added)
+	submit = &protobuf.SC_20006{}
+	decodePacketAt(t, client, offset, 20006, submit)
+	client.Buffer.Reset()
+	if submit.GetResult() != 0 || len(submit.GetAwardList()) != 1 {
+		t.Fatalf("unexpected submit response: %+v", submit)
+	}
+	if len(added.GetInfo()) != 1 || added.GetInfo()[0].GetId() != 1002 {
+		t.Fatalf("expected next task 1002, got %v", added.GetInfo())
+	}
+	if client.Commander.GetResourceCount(1) != goldBefore+100 {
+		t.Fatalf("expected 100 gold granted, got %d", client.Commander.GetResourceCount(1)-goldBefore)
+	}
+
+	if _, _, err := SubmitTask(&payload, client); err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+	submit = &protobuf.SC_20006{}
+	decodePacketMessage(t, client, 20006, submit)
+	client.Buffer.Reset()
+	if submit.GetResult() != 1 {
+		t.Fatalf("expected second submit to be rejected, got %d", submit.GetResult())
+	}
+}
+
+func TestTaskEngineWeeklyPointsAndReward(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedTaskEngineConfig(t)
+	goldBefore := client.Commander.GetResourceCount(1)
+
+	empty := []byte{}
+	if _, _, err := WeeklyMissions(&empty, client); err != nil {
+		t.Fatalf("WeeklyMissions failed: %v", err)
+	}
+	weekly := &protobuf.SC_20101{}
+	decodePacketMessage(t, client, 20101, weekly)
+	client.Buffer.Reset()
+	if len(weekly.GetInfo().GetTask()) != 1 || weekly.GetInfo().GetTask()[0].GetId() != 2001 || weekly.GetInfo().GetPt() != 0 {
+		t.Fatalf("unexpected weekly info: %+v", weekly.GetInfo())
+	}
+
+	claim := marshalPacketRequest(t, &protobuf.CS_20110{Id: proto.Uint32(1)})
+	if _, _, err := ClaimWeeklyTaskReward(&claim, client); err != nil {
+		t.Fatalf("ClaimWeeklyTaskReward failed: %v", err)
+	}
+	reward := &protobuf.SC_20111{}
+	decodePacketMessage(t, client, 20111, reward)
+	client.Buffer.Reset()
+	if reward.GetResult() != 1 {
+		t.Fatalf("expected reward locked without points, got %d", reward.GetResult())
+	}
+
+	if err := recordTaskEvent(client, taskEventRetire, 0, 2); err != nil {
+		t.Fatalf("recordTaskEvent failed: %v", err)
+	}
+	pushed := &protobuf.SC_20102{}
+	decodePacketMessage(t, client, 20102, pushed)
+	client.Buffer.Reset()
+	if len(pushed.GetTask()) != 1 || pushed.GetTask()[0].GetProgress() != 2 {
+		t.Fatalf("unexpected weekly progress: %v", pushed.GetTask())
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_20108{Id: []uint32{2001}})
+	if _, _, err := SubmitWeeklyTasks(&payload, client); err != nil {
+		t.Fatalf("SubmitWeeklyTasks failed: %v", err)
+	}
+	submit := &protobuf.SC_20109{}
+	decodePacketMessage(t, client, 20109, submit)
+	client.Buffer.Reset()
+	if submit.GetResult() != 0 || submit.GetPt() != 50 {
+		t.Fatalf("unexpected weekly submit: %+v", submit)
+	}
+
+	if _, _, err := ClaimWeeklyTaskReward(&claim, client); err != nil {
+		t.Fatalf("ClaimWeeklyTaskReward failed: %v", err)
+	}
+	reward = &protobuf.SC_20111{}
+	decodePacketMessage(t, client, 20111, reward)
+	client.Buffer.Reset()
+	if reward.GetResult() != 0 {
+		t.Fatalf("expected reward claimed, got %d", reward.GetResult())
+	}
+	if client.Commander.GetResourceCount(1) != goldBefore+15 {
+		t.Fatalf("expected 15 gold granted, got %d", client.Commander.GetResourceCount(1)-goldBefore)
+	}
+	state, err := orm.GetOrCreateCommanderTaskState(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("load task state: %v", err)
+	}
+	if state.WeeklyPt != 50 || state.WeeklyRewardLv != 1 {
+		t.Fatalf("unexpected task state: %+v", state)
+	}
+}
@@ -0,0 +1,608 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/ggmolly/belfast/internal/rng"
+)
+
+const (
+	techProjectConfigCategory = "ShareCfg/technology_data_template.json"
+	techCatchupConfigCategory = "ShareCfg/technology_catchup_template.json"
+
+	techResultOK        = uint32(0)
+	techResultFailed    = uint32(1)
+	techResultNotEnough = uint32(2)
+
+	// techOffersPerGroup is the number of projects offered in each series.
+	techOffersPerGroup = 3
+)
+
+var (
+	techRng = rng.NewLockedRand()
+
+	errTechCostNotEnough = errors.New("not enough research materials")
+)
+
+// techProjectTemplate is a research project. group is the research series
+// (the refresh_id of the client), consume and drop_client are lists of
+// [type, id, count] and blueprint_num is the number of blueprints of the
+// series the project yields.
+type techProjectTemplate struct {
+	ID           uint32     `json:"id"`
+	Group        uint32     `json:"group"`
+	Time         uint32     `json:"time"`
+	Consume      [][]uint32 `json:"consume"`
+	DropClient   [][]uint32 `json:"drop_client"`
+	BlueprintNum uint32     `json:"blueprint_num"`
+}
+
+// techCatchupTemplate is a catch-up program (id is its version): up to
+// obtain_max blueprints of the chosen ship are granted on top of the
+// research rewards.
+type techCatchupTemplate struct {
+	ID         uint32   `json:"id"`
+	ObtainMax  uint32   `json:"obtain_max"`
+	CharChoice []uint32 `json:"char_choice"`
+	URChar     []uint32 `json:"ur_char"`
+}
+
+func (template *techCatchupTemplate) allows(blueprintID uint32) bool {
+	return containsUint32(template.CharChoice, blueprintID) || containsUint32(template.URChar, blueprintID)
+}
+
+type techCatalog struct {
+	projects map[uint32]techProjectTemplate
+	byGroup  map[uint32][]uint32
+	groups   []uint32
+}
+
+func loadTechCatalog() (*techCatalog, error) {
+	entries, err := gamedata.List[techProjectTemplate](gamedata.Default, techProjectConfigCategory)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &techCatalog{
+		projects: make(map[uint32]techProjectTemplate),
+		byGroup:  make(map[uint32][]uint32),
+	}
+	for _, template := range entries {
+		if template.ID == 0 || template.Group == 0 {
+			continue
+		}
+		if _, ok := catalog.byGroup[template.Group]; !ok {
+			catalog.groups = append(catalog.groups, template.Group)
+		}
+		catalog.projects[template.ID] = template
+		catalog.byGroup[template.Group] = append(catalog.byGroup[template.Group], template.ID)
+	}
+	sort.Slice(catalog.groups, func(i, j int) bool { return catalog.groups[i] < catalog.groups[j] })
+	for group := range catalog.byGroup {
+		ids := catalog.byGroup[group]
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	return catalog, nil
+}
+
+// rollOffers picks the projects offered in group.
+func (catalog *techCatalog) rollOffers(group uint32) []uint32 {
+	ids := append([]uint32(nil), catalog.byGroup[group]...)
+	techRng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if len(ids) > techOffersPerGroup {
+		ids = ids[:techOffersPerGroup]
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func loadTechCatchupTemplate(version uint32) (*techCatchupTemplate, error) {
+	cached, err := gamedata.Get[techCatchupTemplate](gamedata.Default, techCatchupConfigCategory, strconv.FormatUint(uint64(version), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template := *cached
+	return &template, nil
+}
+
+// techSession is the research state of a commander once the daily reset
+// and the missing offers were applied.
+type techSession struct {
+	catalog  *techCatalog
+	state    *orm.TechState
+	projects []orm.TechProject
+}
+
+func (session *techSession) running() *orm.TechProject {
+	for i := range session.projects {
+		if session.projects[i].FinishTime != 0 {
+			return &session.projects[i]
+		}
+	}
+	return nil
+}
+
+func (session *techSession) offered(refreshID uint32, techID uint32) *orm.TechProject {
+	for i := range session.projects {
+		if session.projects[i].RefreshID == refreshID && session.projects[i].TechID == techID {
+			return &session.projects[i]
+		}
+	}
+	return nil
+}
+
+// loadTechSession loads the research projects of the commander. Series
+// without offers get new ones, and every series is rerolled on the first
+// access of a day unless a project is running.
+func loadTechSession(client *connection.Client, now time.Time) (*techSession, error) {
+	catalog, err := loadTechCatalog()
+	if err != nil {
+		return nil, err
+	}
+	state, err := orm.GetOrCreateTechState(client.Commander.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := orm.ListTechProjects(client.Commander.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	session := &techSession{catalog: catalog, state: state, projects: projects}
+	reset := orm.ApplyTechDailyReset(state, now)
+	offered := make(map[uint32]bool)
+	for _, project := range projects {
+		offered[project.RefreshID] = true
+	}
+	reroll := []uint32{}
+	for _, group := range catalog.groups {
+		if !offered[group] || (reset && session.running() == nil) {
+			reroll = append(reroll, group)
+		}
+	}
+	if !reset && len(reroll) == 0 {
+		return session, nil
+	}
+	if err := session.rerollTx(reroll, reset); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// rerollTx replaces the offers of groups, saving the state along when
+// saveState is set.
+func (session *techSession) rerollTx(groups []uint32, saveState bool) error {
+	ctx := context.Background()
+	commanderID := session.state.CommanderID
+	rolled := make(map[uint32][]uint32, len(groups))
+	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for _, group := range groups {
+			rolled[group] = session.catalog.rollOffers(group)
+			if err := orm.ReplaceTechProjectsTx(ctx, tx, commanderID, group, rolled[group]); err != nil {
+				return err
+			}
+		}
+		if saveState {
+			return orm.SaveTechStateTx(ctx, tx, session.state)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	projects := make([]orm.TechProject, 0, len(session.projects))
+	for _, project := range session.projects {
+		if _, ok := rolled[project.RefreshID]; !ok {
+			projects = append(projects, project)
+		}
+	}
+	for group, ids := range rolled {
+		for _, id := range ids {
+			projects = append(projects, orm.TechProject{CommanderID: commanderID, RefreshID: group, TechID: id})
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].RefreshID != projects[j].RefreshID {
+			return projects[i].RefreshID < projects[j].RefreshID
+		}
+		return projects[i].TechID < projects[j].TechID
+	})
+	session.projects = projects
+	return nil
+}
+
+func (session *techSession) refreshList() ([]*protobuf.TECHNOLOGYREFRESH, error) {
+	targets, err := orm.ListTechTargets(session.state.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	list := []*protobuf.TECHNOLOGYREFRESH{}
+	byGroup := make(map[uint32]*protobuf.TECHNOLOGYREFRESH)
+	for _, project := range session.projects {
+		refresh, ok := byGroup[project.RefreshID]
+		if !ok {
+			refresh = &protobuf.TECHNOLOGYREFRESH{
+				Id:          proto.Uint32(project.RefreshID),
+				Target:      proto.Uint32(targets[project.RefreshID]),
+				Technologys: []*protobuf.TECHNOLOGYINFO{},
+			}
+			byGroup[project.RefreshID] = refresh
+			list = append(list, refresh)
+		}
+		refresh.Technologys = append(refresh.Technologys, &protobuf.TECHNOLOGYINFO{
+			Id:   proto.Uint32(project.TechID),
+			Time: proto.Uint32(project.FinishTime),
+		})
+	}
+	return list, nil
+}
+
+// buildTechCatchup reports the catch-up blueprints obtained per version,
+// UR blueprints being counted separately.
+func buildTechCatchup(state *orm.TechState) (*protobuf.TECHNOLOGYCATCHUP, error) {
+	catchups, err := orm.ListTechCatchups(state.CommanderID)
+	if err != nil {
+		return nil, err
+	}
+	catchup := &protobuf.TECHNOLOGYCATCHUP{
+		Version:   proto.Uint32(state.CatchupVersion),
+		Target:    proto.Uint32(state.CatchupTarget),
+		Pursuings: []*protobuf.TECHPURSUING{},
+	}
+	byVersion := make(map[uint32]*protobuf.TECHPURSUING)
+	templates := make(map[uint32]*techCatchupTemplate)
+	for _, entry := range catchups {
+		template, ok := templates[entry.Version]
+		if !ok {
+			template, err = loadTechCatchupTemplate(entry.Version)
+			if err != nil {
+				return nil, err
+			}
+			templates[entry.Version] = template
+		}
+		pursuing, ok := byVersion[entry.Version]
+		if !ok {
+			pursuing = &protobuf.TECHPURSUING{
+				Version:   proto.Uint32(entry.Version),
+				Number:    proto.Uint32(0),
+				DrNumbers: []*protobuf.DR_NUMBER{},
+			}
+			byVersion[entry.Version] = pursuing
+			catchup.Pursuings = append(catchup.Pursuings, pursuing)
+		}
+		if template != nil && containsUint32(template.URChar, entry.BlueprintID) {
+			pursuing.DrNumbers = append(pursuing.DrNumbers, &protobuf.DR_NUMBER{
+				Id:     proto.Uint32(entry.BlueprintID),
+				Number: proto.Uint32(entry.Number),
+			})
+			continue
+		}
+		pursuing.Number = proto.Uint32(pursuing.GetNumber() + entry.Number)
+	}
+	return catchup, nil
+}
+
+func techConsumeTx(client *connection.Client, ctx context.Context, tx pgx.Tx, consume []uint32) error {
+	if len(consume) < 3 {
+		return nil
+	}
+	switch consume[0] {
+	case consts.DROP_TYPE_RESOURCE:
+		if !client.Commander.HasEnoughResource(consume[1], consume[2]) {
+			return errTechCostNotEnough
+		}
+		if err := client.Commander.ConsumeResourceTx(ctx, tx, consume[1], consume[2]); err != nil {
+			return errTechCostNotEnough
+		}
+	case consts.DROP_TYPE_ITEM:
+		if !client.Commander.HasEnoughItem(consume[1], consume[2]) {
+			return errTechCostNotEnough
+		}
+		if err := client.Commander.ConsumeItemTx(ctx, tx, consume[1], consume[2]); err != nil {
+			return errTechCostNotEnough
+		}
+	default:
+		return errTechCostNotEnough
+	}
+	return nil
+}
+
+// TechnologyRefreshList sends SC_63000 during login.
+func TechnologyRefreshList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	session, err := loadTechSession(client, time.Now())
+	if err != nil {
+		return 0, 63000, err
+	}
+	refreshList, err := session.refreshList()
+	if err != nil {
+		return 0, 63000, err
+	}
+	catchup, err := buildTechCatchup(session.state)
+	if err != nil {
+		return 0, 63000, err
+	}
+	response := protobuf.SC_63000{
+		RefreshList: refreshList,
+		RefreshFlag: proto.Uint32(session.state.RefreshFlag),
+		Catchup:     catchup,
+		Queue:       []*protobuf.TECHNOLOGYINFO{},
+	}
+	return client.SendMessage(63000, &response)
+}
+
+// TechnologyStart handles CS_63001. Only one project can run at a time.
+func TechnologyStart(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63001
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63002, err
+	}
+	now := time.Now()
+	session, err := loadTechSession(client, now)
+	if err != nil {
+		return 0, 63002, err
+	}
+	project := session.offered(payload.GetRefreshId(), payload.GetTechId())
+	template, ok := session.catalog.projects[payload.GetTechId()]
+	if project == nil || !ok || session.running() != nil {
+		return client.SendMessage(63002, &protobuf.SC_63002{Result: proto.Uint32(techResultFailed), Time: proto.Uint32(0)})
+	}
+	finishTime := uint32(now.Unix()) + template.Time
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for _, consume := range template.Consume {
+			if err := techConsumeTx(client, ctx, tx, consume); err != nil {
+				return err
+			}
+		}
+		return orm.SetTechProjectFinishTimeTx(ctx, tx, client.Commander.CommanderID, project.RefreshID, project.TechID, finishTime)
+	})
+	if err != nil {
+		if errors.Is(err, errTechCostNotEnough) {
+			return client.SendMessage(63002, &protobuf.SC_63002{Result: proto.Uint32(techResultNotEnough), Time: proto.Uint32(0)})
+		}
+		return 0, 63002, err
+	}
+	return client.SendMessage(63002, &protobuf.SC_63002{Result: proto.Uint32(techResultOK), Time: proto.Uint32(finishTime)})
+}
+
+// techBlueprintRewards picks the blueprint items a finished project of group
+// yields: the targeted ship's when one is set, random ones of the series
+// otherwise.
+func techBlueprintRewards(commanderID uint32, group uint32, count uint32) (map[uint32]uint32, error) {
+	rewards := make(map[uint32]uint32)
+	if count == 0 {
+		return rewards, nil
+	}
+	templates, err := loadBlueprintTemplates()
+	if err != nil {
+		return nil, err
+	}
+	series := []uint32{}
+	for _, template := range templates {
+		if template.BlueprintVersion == group && template.StrengthenItem != 0 {
+			series = append(series, template.StrengthenItem)
+		}
+	}
+	if len(series) == 0 {
+		return rewards, nil
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i] < series[j] })
+	targets, err := orm.ListTechTargets(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	if template, ok := templates[targets[group]]; ok && template.BlueprintVersion == group && template.StrengthenItem != 0 {
+		rewards[template.StrengthenItem] = count
+		return rewards, nil
+	}
+	for i := uint32(0); i < count; i++ {
+		rewards[series[techRng.IntN(len(series))]]++
+	}
+	return rewards, nil
+}
+
+// TechnologyFinish handles CS_63003: the rewards of a finished project are
+// granted and its series is rerolled.
+func TechnologyFinish(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63003
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63004, err
+	}
+	now := time.Now()
+	session, err := loadTechSession(client, now)
+	if err != nil {
+		return 0, 63004, err
+	}
+	project := session.offered(payload.GetRefreshId(), payload.GetTechId())
+	template, ok := session.catalog.projects[payload.GetTechId()]
+	if project == nil || !ok || project.FinishTime == 0 || project.FinishTime > uint32(now.Unix()) {
+		return client.SendMessage(63004, &protobuf.SC_63004{Result: proto.Uint32(techResultFailed)})
+	}
+
+	commonDrops := make(map[string]*protobuf.DROPINFO)
+	for _, drop := range template.DropClient {
+		if len(drop) < 3 {
+			continue
+		}
+		dropType, dropID, count, err := resolveChapterAwardDrop(drop[0], drop[1])
+		if err != nil {
+			return 0, 63004, err
+		}
+		accumulateDrop(commonDrops, dropType, dropID, count*drop[2])
+	}
+	blueprintDrops := make(map[string]*protobuf.DROPINFO)
+	rewards, err := techBlueprintRewards(client.Commander.CommanderID, project.RefreshID, template.BlueprintNum)
+	if err != nil {
+		return 0, 63004, err
+	}
+	for itemID, count := range rewards {
+		accumulateDrop(blueprintDrops, consts.DROP_TYPE_ITEM, itemID, count)
+	}
+	catchupDrops := make(map[string]*protobuf.DROPINFO)
+	catchup, err := loadTechCatchupTemplate(session.state.CatchupVersion)
+	if err != nil {
+		return 0, 63004, err
+	}
+	var catchupBlueprint *blueprintTemplate
+	if catchup != nil && catchup.allows(session.state.CatchupTarget) {
+		catchupBlueprint, err = loadBlueprintTemplate(session.state.CatchupTarget)
+		if err != nil {
+			return 0, 63004, err
+		}
+	}
+
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.SetTechProjectFinishTimeTx(ctx, tx, client.Commander.CommanderID, project.RefreshID, project.TechID, 0); err != nil {
+			return err
+		}
+		if catchupBlueprint == nil || catchupBlueprint.StrengthenItem == 0 {
+			return nil
+		}
+		err := orm.AddTechCatchupTx(ctx, tx, client.Commander.CommanderID, catchup.ID, catchupBlueprint.ID, 1, catchup.ObtainMax)
+		if errors.Is(err, orm.ErrTechCatchupLimit) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		accumulateDrop(catchupDrops, consts.DROP_TYPE_ITEM, catchupBlueprint.StrengthenItem, 1)
+		return nil
+	})
+	if err != nil {
+		return 0, 63004, err
+	}
+	for _, drops := range []map[string]*protobuf.DROPINFO{commonDrops, blueprintDrops, catchupDrops} {
+		if err := applyDropList(client, drops); err != nil {
+			return 0, 63004, err
+		}
+	}
+	project.FinishTime = 0
+	if err := session.rerollTx([]uint32{project.RefreshID}, false); err != nil {
+		return 0, 63004, err
+	}
+	refreshList, err := session.refreshList()
+	if err != nil {
+		return 0, 63004, err
+	}
+	response := protobuf.SC_63004{
+		Result:         proto.Uint32(techResultOK),
+		CommonList:     dropMapToSortedList(commonDrops),
+		RefreshList:    refreshList,
+		DropList:       dropMapToSortedList(blueprintDrops),
+		CatchupList:    dropMapToSortedList(catchupDrops),
+		CatchupactList: []*protobuf.DROPINFO{},
+	}
+	return client.SendMessage(63004, &response)
+}
+
+// TechnologyCancel handles CS_63005. The materials spent are not refunded.
+func TechnologyCancel(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63005
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63006, err
+	}
+	session, err := loadTechSession(client, time.Now())
+	if err != nil {
+		return 0, 63006, err
+	}
+	project := session.offered(payload.GetRefreshId(), payload.GetTechId())
+	if project == nil || project.FinishTime == 0 {
+		return client.SendMessage(63006, &protobuf.SC_63006{Result: proto.Uint32(techResultFailed)})
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return orm.SetTechProjectFinishTimeTx(ctx, tx, client.Commander.CommanderID, project.RefreshID, project.TechID, 0)
+	})
+	if err != nil {
+		return 0, 63006, err
+	}
+	return client.SendMessage(63006, &protobuf.SC_63006{Result: proto.Uint32(techResultOK)})
+}
+
+// TechnologyRefresh handles CS_63007: every series is rerolled, once a day
+// and only while no project is running.
+func TechnologyRefresh(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63007
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63008, err
+	}
+	session, err := loadTechSession(client, time.Now())
+	if err != nil {
+		return 0, 63008, err
+	}
+	if session.state.RefreshFlag != 0 || session.running() != nil {
+		return client.SendMessage(63008, &protobuf.SC_63008{Result: proto.Uint32(techResultFailed)})
+	}
+	session.state.RefreshFlag = 1
+	if err := session.rerollTx(session.catalog.groups, true); err != nil {
+		return 0, 63008, err
+	}
+	refreshList, err := session.refreshList()
+	if err != nil {
+		return 0, 63008, err
+	}
+	return client.SendMessage(63008, &protobuf.SC_63008{Result: proto.Uint32(techResultOK), RefreshList: refreshList})
+}
+
+// TechnologySetTarget handles CS_63009: the blueprints of a series are
+// focused on one of its ships, 0 clearing the target.
+func TechnologySetTarget(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63009
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63010, err
+	}
+	if payload.GetTarget() != 0 {
+		template, err := loadBlueprintTemplate(payload.GetTarget())
+		if err != nil {
+			return 0, 63010, err
+		}
+		if template == nil || template.BlueprintVersion != payload.GetId() {
+			return client.SendMessage(63010, &protobuf.SC_63010{Result: proto.Uint32(techResultFailed)})
+		}
+	}
+	if err := orm.SetTechTarget(client.Commander.CommanderID, payload.GetId(), payload.GetTarget()); err != nil {
+		return 0, 63010, err
+	}
+	return client.SendMessage(63010, &protobuf.SC_63010{Result: proto.Uint32(techResultOK)})
+}
+
+// TechnologySetCatchup handles CS_63011: the catch-up program and the ship
+// its blueprints go to are chosen.
+func TechnologySetCatchup(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_63011
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 63012, err
+	}
+	template, err := loadTechCatchupTemplate(payload.GetVersion())
+	if err != nil {
+		return 0, 63012, err
+	}
+	if template == nil || !template.allows(payload.GetTarget()) {
+		return client.SendMessage(63012, &protobuf.SC_63012{Result: proto.Uint32(techResultFailed)})
+	}
+	state, err := orm.GetOrCreateTechState(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 63012, err
+	}
+	state.CatchupVersion = payload.GetVersion()
+	state.CatchupTarget = payload.GetTarget()
+	if err := orm.SaveTechState(state); err != nil {
+		return 0, 63012, err
+	}
+	return client.SendMessage(63012, &protobuf.SC_63012{Result: proto.Uint32(techResultOK)})
+}
@@ -1,96 +1,242 @@
 package answer
 
 import (
-	"encoding/json"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
-	"google.golang.org/protobuf/proto"
 )
 
-type fleetTechGroup struct {
-	ID uint32 `json:"id"`
-}
-
-type fleetTechTemplate struct {
-	Add []any `json:"add"`
-}
+const (
+	fleetTechResultOK     = uint32(0)
+	fleetTechResultFailed = uint32(1)
+)
 
+// TechnologyNationProxy sends SC_64000 during login.
 func TechnologyNationProxy(buffer *[]byte, client *connection.Client) (int, int, error) {
-	techGroups, err := orm.ListConfigEntries("ShareCfg/fleet_tech_group.json")
+	catalog, err := orm.LoadFleetTechCatalog()
 	if err != nil {
 		return 0, 64000, err
 	}
-	techTemplates, err := orm.ListConfigEntries("ShareCfg/fleet_tech_template.json")
+	techs, err := orm.ListFleetTechs(client.Commander.CommanderID)
 	if err != nil {
 		return 0, 64000, err
 	}
+	sets, err := orm.ListFleetTechSets(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 64000, err
+	}
+	progress := make(map[uint32]orm.FleetTech, len(techs))
+	for _, tech := range techs {
+		progress[tech.GroupID] = tech
+	}
 	response := protobuf.SC_64000{
-		TechList:    make([]*protobuf.FLEETTECH, 0, len(techGroups)),
-		TechsetList: make([]*protobuf.TECHSET, 0),
+		TechList:    make([]*protobuf.FLEETTECH, 0, len(catalog.Groups)),
+		TechsetList: make([]*protobuf.TECHSET, 0, len(sets)),
 	}
-	for _, entry := range techGroups {
-		var group fleetTechGroup
-		if err := json.Unmarshal(entry.Data, &group); err != nil {
-			return 0, 64000, err
-		}
+	for _, group := range catalog.Groups {
+		tech := progress[group.ID]
 		response.TechList = append(response.TechList, &protobuf.FLEETTECH{
 			GroupId:         proto.Uint32(group.ID),
-			EffectTechId:    proto.Uint32(0),
-			StudyTechId:     proto.Uint32(0),
-			StudyFinishTime: proto.Uint32(0),
-			RewardedTech:    proto.Uint32(0),
+			EffectTechId:    proto.Uint32(tech.EffectTechID),
+			StudyTechId:     proto.Uint32(tech.StudyTechID),
+			StudyFinishTime: proto.Uint32(tech.StudyFinishTime),
+			RewardedTech:    proto.Uint32(tech.RewardedTech),
 		})
 	}
-	for _, entry := range techTemplates {
-		var template fleetTechTemplate
-		if err := json.Unmarshal(entry.Data, &template); err != nil {
-			return 0, 64000, err
-		}
-		response.TechsetList = append(response.TechsetList, buildTechSets(template.Add)...)
+	for _, set := range sets {
+		response.TechsetList = append(response.TechsetList, &protobuf.TECHSET{
+			ShipType: proto.Uint32(set.ShipType),
+			AttrType: proto.Uint32(set.AttrType),
+			SetValue: proto.Uint32(set.Value),
+		})
 	}
 	return client.SendMessage(64000, &response)
 }
 
-func buildTechSets(rawAdd []any) []*protobuf.TECHSET {
-	results := make([]*protobuf.TECHSET, 0)
-	for _, entry := range rawAdd {
-		parts, ok := entry.([]any)
-		if !ok || len(parts) < 3 {
-			continue
-		}
-		shipTypes, ok := parts[0].([]any)
-		if !ok {
-			continue
+// FleetTechStudy handles CS_64001: the next level of a group is studied once
+// the nation of the group has enough tech points.
+func FleetTechStudy(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_64001
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 64002, err
+	}
+	failed := &protobuf.SC_64002{Result: proto.Uint32(fleetTechResultFailed)}
+	catalog, err := orm.LoadFleetTechCatalog()
+	if err != nil {
+		return 0, 64002, err
+	}
+	group, ok := catalog.Group(payload.GetTechGroupId())
+	if !ok {
+		return client.SendMessage(64002, failed)
+	}
+	tech, err := orm.GetFleetTech(client.Commander.CommanderID, group.ID)
+	if err != nil {
+		return 0, 64002, err
+	}
+	next, ok := catalog.NextLevel(group.ID, tech.EffectTechID)
+	if !ok || tech.StudyTechID != 0 || next.ID != payload.GetTechId() {
+		return client.SendMessage(64002, failed)
+	}
+	collection, err := orm.LoadFleetTechCollection(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 64002, err
+	}
+	if collection.NationPoints(group.Nation) < next.Pt {
+		return client.SendMessage(64002, failed)
+	}
+	tech.StudyTechID = next.ID
+	tech.StudyFinishTime = uint32(time.Now().Unix()) + next.Time
+	if err := orm.SaveFleetTech(tech); err != nil {
+		return 0, 64002, err
+	}
+	return client.SendMessage(64002, &protobuf.SC_64002{Result: proto.Uint32(fleetTechResultOK)})
+}
+
+// FleetTechFinishStudy handles CS_64003.
+func FleetTechFinishStudy(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_64003
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 64004, err
+	}
+	tech, err := orm.GetFleetTech(client.Commander.CommanderID, payload.GetTechGroupId())
+	if err != nil {
+		return 0, 64004, err
+	}
+	if tech.StudyTechID == 0 || tech.StudyFinishTime > uint32(time.Now().Unix()) {
+		return client.SendMessage(64004, &protobuf.SC_64004{Result: proto.Uint32(fleetTechResultFailed)})
+	}
+	tech.EffectTechID = tech.StudyTechID
+	tech.StudyTechID = 0
+	tech.StudyFinishTime = 0
+	if err := orm.SaveFleetTech(tech); err != nil {
+		return 0, 64004, err
+	}
+	return client.SendMessage(64004, &protobuf.SC_64004{Result: proto.Uint32(fleetTechResultOK)})
+}
+
+// claimFleetTechRewards marks the levels of tech up to upTo (every reached
+// level for 0) as rewarded, adding their rewards to drops. It returns
+// whether a level was claimed.
+func claimFleetTechRewards(catalog *orm.FleetTechCatalog, tech *orm.FleetTech, upTo uint32, drops map[string]*protobuf.DROPINFO) bool {
+	limit := catalog.Level(tech.EffectTechID)
+	if upTo != 0 && catalog.Level(upTo) < limit {
+		limit = catalog.Level(upTo)
+	}
+	claimed := false
+	for {
+		next, ok := catalog.NextLevel(tech.GroupID, tech.RewardedTech)
+		if !ok || next.Level > limit {
+			return claimed
 		}
-		attrType, ok := parseJSONUint32(parts[1])
-		if !ok {
-			continue
+		for _, reward := range next.Rewards {
+			if len(reward) < 3 {
+				continue
+			}
+			accumulateDrop(drops, reward[0], reward[1], reward[2])
 		}
-		setValue, ok := parseJSONUint32(parts[2])
-		if !ok {
-			continue
+		tech.RewardedTech = next.ID
+		claimed = true
+	}
+}
+
+// FleetTechClaimReward handles CS_64005: the rewards of the levels reached
+// are claimed in order, up to tech_id.
+func FleetTechClaimReward(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_64005
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 64006, err
+	}
+	catalog, err := orm.LoadFleetTechCatalog()
+	if err != nil {
+		return 0, 64006, err
+	}
+	tech, err := orm.GetFleetTech(client.Commander.CommanderID, payload.GetGroupId())
+	if err != nil {
+		return 0, 64006, err
+	}
+	template, ok := catalog.Templates[payload.GetTechId()]
+	drops := make(map[string]*protobuf.DROPINFO)
+	if !ok || template.Group != tech.GroupID || !claimFleetTechRewards(catalog, tech, template.ID, drops) {
+		return client.SendMessage(64006, &protobuf.SC_64006{Result: proto.Uint32(fleetTechResultFailed)})
+	}
+	if err := orm.SaveFleetTech(tech); err != nil {
+		return 0, 64006, err
+	}
+	if err := applyDropList(client, drops); err != nil {
+		return 0, 64006, err
+	}
+	return client.SendMessage(64006, &protobuf.SC_64006{Result: proto.Uint32(fleetTechResultOK), Rewards: dropMapToSortedList(drops)})
+}
+
+// FleetTechClaimAllRewards handles CS_64007.
+func FleetTechClaimAllRewards(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_64007
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 64008, err
+	}
+	catalog, err := orm.LoadFleetTechCatalog()
+	if err != nil {
+		return 0, 64008, err
+	}
+	techs, err := orm.ListFleetTechs(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 64008, err
+	}
+	drops := make(map[string]*protobuf.DROPINFO)
+	claimed := []*orm.FleetTech{}
+	for i := range techs {
+		if claimFleetTechRewards(catalog, &techs[i], 0, drops) {
+			claimed = append(claimed, &techs[i])
 		}
-		for _, shipTypeValue := range shipTypes {
-			shipType, ok := parseJSONUint32(shipTypeValue)
-			if !ok {
-				continue
+	}
+	if len(claimed) == 0 {
+		return client.SendMessage(64008, &protobuf.SC_64008{Result: proto.Uint32(fleetTechResultFailed)})
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for _, tech := range claimed {
+			if err := orm.SaveFleetTechTx(ctx, tx, tech); err != nil {
+				return err
 			}
-			results = append(results, &protobuf.TECHSET{
-				ShipType: proto.Uint32(shipType),
-				AttrType: proto.Uint32(attrType),
-				SetValue: proto.Uint32(setValue),
-			})
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, 64008, err
+	}
+	if err := applyDropList(client, drops); err != nil {
+		return 0, 64008, err
 	}
-	return results
+	return client.SendMessage(64008, &protobuf.SC_64008{Result: proto.Uint32(fleetTechResultOK), Rewards: dropMapToSortedList(drops)})
 }
 
-func parseJSONUint32(value any) (uint32, bool) {
-	number, ok := value.(float64)
-	if !ok {
-		return 0, false
+// FleetTechSetAttrs handles CS_64009: stat bonuses are capped below the
+// values unlocked, which are the only ones accepted.
+func FleetTechSetAttrs(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_64009
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 64010, err
+	}
+	unlocked, err := orm.GetFleetTechUnlockedBonuses(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 64010, err
+	}
+	sets := make([]orm.FleetTechSet, 0, len(payload.GetTechsetList()))
+	for _, set := range payload.GetTechsetList() {
+		value, ok := unlocked[set.GetShipType()][set.GetAttrType()]
+		if !ok || set.GetSetValue() > value {
+			return client.SendMessage(64010, &protobuf.SC_64010{Result: proto.Uint32(fleetTechResultFailed)})
+		}
+		sets = append(sets, orm.FleetTechSet{ShipType: set.GetShipType(), AttrType: set.GetAttrType(), Value: set.GetSetValue()})
+	}
+	if err := orm.ReplaceFleetTechSets(client.Commander.CommanderID, sets); err != nil {
+		return 0, 64010, err
 	}
-	return uint32(number), true
+	return client.SendMessage(64010, &protobuf.SC_64010{Result: proto.Uint32(fleetTechResultOK)})
 }
@@ -0,0 +1,85 @@
+package answer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func TestFleetTechStudyAndClaimRewards(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	execAnswerTestSQLT(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (template_id) DO NOTHING", int64(99801), "Tech Ship", "Tech Ship", int64(4), int64(1), int64(1), int64(1), int64(0))
+	seedConfigEntry(t, "ShareCfg/fleet_tech_ship_template.json", "9980", `{"id":9980,"nation":1,"pt_get":10,"add_get_shiptype":[1],"add_get_attr":3,"add_get_value":2}`)
+	seedConfigEntry(t, "ShareCfg/fleet_tech_group.json", "1", `{"id":1,"nation":1}`)
+	seedConfigEntry(t, "ShareCfg/fleet_tech_template.json", "101", `{"id":101,"group":1,"level":1,"pt":10,"time":0,"add":[],"rewards":[[1,1,100]]}`)
+	seedConfigEntry(t, "ShareCfg/fleet_tech_template.json", "102", `{"id":102,"group":1,"level":2,"pt":20,"time":0,"add":[],"rewards":[[1,1,100]]}`)
+
+	study := func(techID uint32) uint32 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_64001{TechGroupId: proto.Uint32(1), TechId: proto.Uint32(techID)})
+		if _, _, err := FleetTechStudy(&payload, client); err != nil {
+			t.Fatalf("study failed: %v", err)
+		}
+		response := &protobuf.SC_64002{}
+		decodePacketMessage(t, client, 64002, response)
+		client.Buffer.Reset()
+		return response.GetResult()
+	}
+
+	if result := study(101); result != fleetTechResultFailed {
+		t.Fatalf("expected study without tech points to fail, got %d", result)
+	}
+	if _, err := client.Commander.AddShip(99801); err != nil {
+		t.Fatalf("add ship: %v", err)
+	}
+	if result := study(102); result != fleetTechResultFailed {
+		t.Fatalf("expected levels to be studied in order, got %d", result)
+	}
+	if result := study(101); result != fleetTechResultOK {
+		t.Fatalf("expected study to start, got %d", result)
+	}
+
+	finish := marshalPacketRequest(t, &protobuf.CS_64003{TechGroupId: proto.Uint32(1)})
+	if _, _, err := FleetTechFinishStudy(&finish, client); err != nil {
+		t.Fatalf("finish study failed: %v", err)
+	}
+	finished := &protobuf.SC_64004{}
+	decodePacketMessage(t, client, 64004, finished)
+	client.Buffer.Reset()
+	if finished.GetResult() != fleetTechResultOK {
+		t.Fatalf("expected study to finish, got %d", finished.GetResult())
+	}
+
+	goldBefore := client.Commander.GetResourceCount(1)
+	claim := marshalPacketRequest(t, &protobuf.CS_64007{Type: proto.Uint32(0)})
+	if _, _, err := FleetTechClaimAllRewards(&claim, client); err != nil {
+		t.Fatalf("claim rewards failed: %v", err)
+	}
+	claimed := &protobuf.SC_64008{}
+	decodePacketMessage(t, client, 64008, claimed)
+	client.Buffer.Reset()
+	if claimed.GetResult() != fleetTechResultOK || len(claimed.GetRewards()) != 1 || client.Commander.GetResourceCount(1) != goldBefore+100 {
+		t.Fatalf("unexpected claim response: %v", claimed)
+	}
+	tech, err := orm.GetFleetTech(client.Commander.CommanderID, 1)
+	if err != nil {
+		t.Fatalf("get fleet tech: %v", err)
+	}
+	if tech.EffectTechID != 101 || tech.RewardedTech != 101 {
+		t.Fatalf("unexpected fleet tech: %+v", tech)
+	}
+
+	sets := marshalPacketRequest(t, &protobuf.CS_64009{TechsetList: []*protobuf.TECHSET{{ShipType: proto.Uint32(1), AttrType: proto.Uint32(3), SetValue: proto.Uint32(5)}}})
+	if _, _, err := FleetTechSetAttrs(&sets, client); err != nil {
+		t.Fatalf("set attrs failed: %v", err)
+	}
+	setResponse := &protobuf.SC_64010{}
+	decodePacketMessage(t, client, 64010, setResponse)
+	client.Buffer.Reset()
+	if setResponse.GetResult() != fleetTechResultFailed {
+		t.Fatalf("expected value above the unlocked bonus to be rejected, got %d", setResponse.GetResult())
+	}
+}
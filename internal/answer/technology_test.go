@@ -0,0 +1,163 @@
+package answer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func seedTechnologyConfig(t *testing.T) {
+	t.Helper()
+	for _, id := range []uint32{20001, 20002, 42001} {
+		execAnswerTestSQLT(t, "INSERT INTO items (id, name, rarity, shop_id, type, virtual_type) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (id) DO NOTHING", int64(id), "Tech Item", int64(1), int64(-2), int64(1), int64(0))
+	}
+	execAnswerTestSQLT(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (template_id) DO NOTHING", int64(19901), "Research Ship", "Research Ship", int64(6), int64(1), int64(1), int64(1), int64(0))
+	seedConfigEntry(t, techProjectConfigCategory, "101", `{"id":101,"group":1,"time":0,"consume":[[2,20001,1]],"drop_client":[[2,20002,3]],"blueprint_num":2}`)
+	seedConfigEntry(t, techCatchupConfigCategory, "1", `{"id":1,"obtain_max":1,"char_choice":[10001],"ur_char":[]}`)
+	seedConfigEntry(t, blueprintConfigCategory, "10001", `{"id":10001,"ship_id":19901,"blueprint_version":1,"strengthen_item":42001,"strengthen_effect":[1,2],"fate_strengthen":[3],"time":0,"unlock_item_tasks":[[1,20002,2]]}`)
+	seedConfigEntry(t, blueprintStrengthenConfigCategory, "1", `{"id":1,"lv":1,"need_exp":2,"need_lv":1}`)
+	seedConfigEntry(t, blueprintStrengthenConfigCategory, "2", `{"id":2,"lv":2,"need_exp":2,"need_lv":100}`)
+	seedConfigEntry(t, blueprintStrengthenConfigCategory, "3", `{"id":3,"lv":3,"need_exp":5,"need_lv":1}`)
+}
+
+func TestTechnologyResearchGrantsBlueprints(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedTechnologyConfig(t)
+	if err := client.Commander.AddItem(20001, 1); err != nil {
+		t.Fatalf("add item: %v", err)
+	}
+
+	empty := []byte{}
+	if _, _, err := TechnologyRefreshList(&empty, client); err != nil {
+		t.Fatalf("refresh list failed: %v", err)
+	}
+	list := &protobuf.SC_63000{}
+	decodePacketMessage(t, client, 63000, list)
+	client.Buffer.Reset()
+	if len(list.GetRefreshList()) != 1 || len(list.GetRefreshList()[0].GetTechnologys()) != 1 {
+		t.Fatalf("unexpected refresh list: %v", list.GetRefreshList())
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_63011{Version: proto.Uint32(1), Target: proto.Uint32(10001)})
+	if _, _, err := TechnologySetCatchup(&payload, client); err != nil {
+		t.Fatalf("set catchup failed: %v", err)
+	}
+	client.Buffer.Reset()
+
+	payload = marshalPacketRequest(t, &protobuf.CS_63001{TechId: proto.Uint32(101), RefreshId: proto.Uint32(1)})
+	if _, _, err := TechnologyStart(&payload, client); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	started := &protobuf.SC_63002{}
+	decodePacketMessage(t, client, 63002, started)
+	client.Buffer.Reset()
+	if started.GetResult() != techResultOK || client.Commander.GetItemCount(20001) != 0 {
+		t.Fatalf("expected project to start, got %d", started.GetResult())
+	}
+	if _, _, err := TechnologyStart(&payload, client); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	decodePacketMessage(t, client, 63002, started)
+	client.Buffer.Reset()
+	if started.GetResult() != techResultFailed {
+		t.Fatalf("expected a second project to be rejected, got %d", started.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_63003{TechId: proto.Uint32(101), RefreshId: proto.Uint32(1)})
+	if _, _, err := TechnologyFinish(&payload, client); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+	finished := &protobuf.SC_63004{}
+	decodePacketMessage(t, client, 63004, finished)
+	client.Buffer.Reset()
+	if finished.GetResult() != techResultOK || len(finished.GetDropList()) != 1 || len(finished.GetCatchupList()) != 1 {
+		t.Fatalf("unexpected finish response: %v", finished)
+	}
+	if client.Commander.GetItemCount(20002) != 3 || client.Commander.GetItemCount(42001) != 3 {
+		t.Fatalf("unexpected rewards: %d common, %d blueprints", client.Commander.GetItemCount(20002), client.Commander.GetItemCount(42001))
+	}
+	projects, err := orm.ListTechProjects(client.Commander.CommanderID)
+	if err != nil {
+		t.Fatalf("list projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].FinishTime != 0 {
+		t.Fatalf("expected series to be rerolled, got %v", projects)
+	}
+}
+
+func TestBlueprintDevelopmentAndStrengthen(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	seedTechnologyConfig(t)
+	if err := client.Commander.AddItem(20002, 2); err != nil {
+		t.Fatalf("add item: %v", err)
+	}
+	if err := client.Commander.AddItem(42001, 6); err != nil {
+		t.Fatalf("add item: %v", err)
+	}
+
+	payload := marshalPacketRequest(t, &protobuf.CS_63200{BlueprintId: proto.Uint32(10001)})
+	if _, _, err := BlueprintStart(&payload, client); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	started := &protobuf.SC_63201{}
+	decodePacketMessage(t, client, 63201, started)
+	client.Buffer.Reset()
+	if started.GetResult() != techResultOK {
+		t.Fatalf("expected development to start, got %d", started.GetResult())
+	}
+
+	payload = marshalPacketRequest(t, &protobuf.CS_63202{BlueprintId: proto.Uint32(10001)})
+	if _, _, err := BlueprintFinish(&payload, client); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+	finished := &protobuf.SC_63203{}
+	decodePacketMessage(t, client, 63203, finished)
+	client.Buffer.Reset()
+	if finished.GetResult() != techResultFailed {
+		t.Fatalf("expected pending item task to block the build, got %d", finished.GetResult())
+	}
+
+	task := marshalPacketRequest(t, &protobuf.CS_63210{Blueprintid: proto.Uint32(10001), Itemid: proto.Uint32(20002), Number: proto.Uint32(2), TaskId: proto.Uint32(1)})
+	if _, _, err := BlueprintSubmitTask(&task, client); err != nil {
+		t.Fatalf("submit task failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if _, _, err := BlueprintFinish(&payload, client); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+	decodePacketMessage(t, client, 63203, finished)
+	client.Buffer.Reset()
+	if finished.GetResult() != techResultOK || finished.GetShip().GetTemplateId() != 19901 {
+		t.Fatalf("expected research ship to be built, got %v", finished)
+	}
+	shipID := finished.GetShip().GetId()
+
+	strengthen := marshalPacketRequest(t, &protobuf.CS_63204{ShipId: proto.Uint32(shipID), Count: proto.Uint32(3)})
+	if _, _, err := BlueprintStrengthen(&strengthen, client); err != nil {
+		t.Fatalf("strengthen failed: %v", err)
+	}
+	result := &protobuf.SC_63205{}
+	decodePacketMessage(t, client, 63205, result)
+	client.Buffer.Reset()
+	blueprint, err := orm.GetCommanderBlueprint(client.Commander.CommanderID, 10001)
+	if err != nil {
+		t.Fatalf("get blueprint: %v", err)
+	}
+	if result.GetResult() != techResultOK || blueprint.Level != 1 || blueprint.Exp != 1 {
+		t.Fatalf("expected ship level to cap development, got %d (level %d, exp %d)", result.GetResult(), blueprint.Level, blueprint.Exp)
+	}
+
+	fate := marshalPacketRequest(t, &protobuf.CS_63212{ShipId: proto.Uint32(shipID), Count: proto.Uint32(1)})
+	if _, _, err := BlueprintFateSimulation(&fate, client); err != nil {
+		t.Fatalf("fate simulation failed: %v", err)
+	}
+	fateResult := &protobuf.SC_63213{}
+	decodePacketMessage(t, client, 63213, fateResult)
+	client.Buffer.Reset()
+	if fateResult.GetResult() != techResultFailed {
+		t.Fatalf("expected fate simulation to require full development, got %d", fateResult.GetResult())
+	}
+}
@@ -1,17 +1,40 @@
 package answer
 
 import (
+	"time"
+
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
 
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
 func WeeklyMissions(buffer *[]byte, client *connection.Client) (int, int, error) {
+	state, err := ensureCommanderTasks(client, time.Now())
+	if err != nil {
+		return 0, 20101, err
+	}
+	tasks, err := orm.ListCommanderTasks(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 20101, err
+	}
+	templates, err := loadCommanderTaskTemplates(tasks)
+	if err != nil {
+		return 0, 20101, err
+	}
 	var response protobuf.SC_20101
 	response.Info = &protobuf.WEEKLY_INFO{
-		Pt:       proto.Uint32(0),
-		RewardLv: proto.Uint32(0),
+		Task:     []*protobuf.WEEKLY_TASK_P20{},
+		Pt:       proto.Uint32(state.WeeklyPt),
+		RewardLv: proto.Uint32(state.WeeklyRewardLv),
+	}
+	for i := range tasks {
+		template := templates[tasks[i].TaskID]
+		if template == nil || template.Type != taskTypeWeekly || tasks[i].SubmitTime != 0 {
+			continue
+		}
+		response.Info.Task = append(response.Info.Task, weeklyTaskInfo(&tasks[i]))
 	}
 	return client.SendMessage(20101, &response)
 }
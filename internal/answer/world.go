@@ -0,0 +1,405 @@
+package answer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	worldMapCategory   = "ShareCfg/world_chapter_template.json"
+	worldItemCategory  = "ShareCfg/world_item_data_template.json"
+	worldPortCategory  = "ShareCfg/world_port_data.json"
+	worldGoodsCategory = "ShareCfg/world_goods_data.json"
+
+	worldResultOK     = uint32(0)
+	worldResultFailed = uint32(1)
+
+	// worldOpenLevel is the commander level Operation Siren opens at.
+	worldOpenLevel = 30
+	// worldActionPowerMax is the action power regeneration stops at; points
+	// given by items go to the extra pool, which has no cap.
+	worldActionPowerMax = uint32(200)
+	// worldActionPowerRecoverInterval is the time needed to regenerate one
+	// point of action power, in seconds.
+	worldActionPowerRecoverInterval = uint32(10 * 60)
+	// worldGroupLimit and worldGroupShipLimit bound the fleets sent to the
+	// world.
+	worldGroupLimit     = 4
+	worldGroupShipLimit = 6
+	worldShipHpFull     = uint32(10000)
+)
+
+// worldMapTemplate is a map of the world. grids uses the chapter format
+// ([row, column, walkable, attachment]) and entrance is the [row, column]
+// fleets arrive at. Moving costs move_cost action power per cell; beating
+// stage_id clears the map, unlocks the maps listed in unlock and raises the
+// world progress to progress. Maps with start set are open from the
+// beginning, and port_id is the port of the map, if any.
+type worldMapTemplate struct {
+	ID       uint32   `json:"id"`
+	Grids    [][]any  `json:"grids"`
+	Entrance []uint32 `json:"entrance"`
+	MoveCost uint32   `json:"move_cost"`
+	StageID  uint32   `json:"stage_id"`
+	Unlock   []uint32 `json:"unlock"`
+	Progress uint32   `json:"progress"`
+	Start    bool     `json:"start"`
+	PortID   uint32   `json:"port_id"`
+	grids    []chapterGrid
+}
+
+func (template *worldMapTemplate) entrance() chapterPos {
+	if len(template.Entrance) < 2 {
+		return chapterPos{}
+	}
+	return chapterPos{Row: template.Entrance[0], Column: template.Entrance[1]}
+}
+
+func (template *worldMapTemplate) moveCost() uint32 {
+	if template.MoveCost == 0 {
+		return 1
+	}
+	return template.MoveCost
+}
+
+func loadWorldConfig(category string, id uint32, out any) (bool, error) {
+	entry, err := gamedata.Default.Entry(category, fmt.Sprintf("%d", id))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(entry.Data, out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func loadWorldMapTemplate(id uint32) (*worldMapTemplate, error) {
+	var template worldMapTemplate
+	ok, err := loadWorldConfig(worldMapCategory, id, &template)
+	if err != nil || !ok {
+		return nil, err
+	}
+	template.grids, err = parseChapterGrids(template.Grids)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func isWorldOpen(client *connection.Client) bool {
+	return client.Commander.Level >= worldOpenLevel
+}
+
+// recoverWorldActionPower regenerates the action power earned since the
+// last recovery, keeping the time spent on a partial point.
+func recoverWorldActionPower(world *orm.World, now uint32) bool {
+	if world.ActionPower >= worldActionPowerMax || world.LastRecoverTime == 0 || world.LastRecoverTime > now {
+		changed := world.LastRecoverTime != now
+		world.LastRecoverTime = now
+		return changed
+	}
+	points := (now - world.LastRecoverTime) / worldActionPowerRecoverInterval
+	if points == 0 {
+		return false
+	}
+	world.ActionPower += points
+	world.LastRecoverTime += points * worldActionPowerRecoverInterval
+	if world.ActionPower >= worldActionPowerMax {
+		world.ActionPower = worldActionPowerMax
+		world.LastRecoverTime = now
+	}
+	return true
+}
+
+// spendWorldActionPower takes cost action power, starting with the extra
+// pool, and reports whether the commander had enough of it.
+func spendWorldActionPower(world *orm.World, cost uint32) bool {
+	if world.ActionPower+world.ActionPowerExtra < cost {
+		return false
+	}
+	fromExtra := minUint32(cost, world.ActionPowerExtra)
+	world.ActionPowerExtra -= fromExtra
+	world.ActionPower -= cost - fromExtra
+	return true
+}
+
+// loadWorld returns the world of the commander with its action power
+// regenerated and its daily counters reset.
+func loadWorld(commanderID uint32) (*orm.World, error) {
+	world, err := orm.GetOrCreateWorld(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if _, err := orm.ApplyWorldDailyReset(world, now); err != nil {
+		return nil, err
+	}
+	if recoverWorldActionPower(world, uint32(now.Unix())) {
+		if err := orm.SaveWorld(world); err != nil {
+			return nil, err
+		}
+	}
+	return world, nil
+}
+
+func worldMapID(mapID uint32) *protobuf.WORLDMAPID {
+	return &protobuf.WORLDMAPID{
+		RandomId:   proto.Uint32(mapID),
+		TemplateId: proto.Uint32(mapID),
+	}
+}
+
+func worldPos(pos chapterPos) *protobuf.CHAPTERCELLPOS_P33 {
+	return &protobuf.CHAPTERCELLPOS_P33{
+		Row:    proto.Uint32(pos.Row),
+		Column: proto.Uint32(pos.Column),
+	}
+}
+
+func worldCountInfo(world *orm.World) *protobuf.COUNTINFO {
+	return &protobuf.COUNTINFO{
+		StepCount:     proto.Uint32(world.StepCount),
+		TreasureCount: proto.Uint32(world.TreasureCount),
+		TaskProgress:  proto.Uint32(world.Progress),
+		ActivateCount: proto.Uint32(world.ActivateCount),
+	}
+}
+
+func worldGroupInfo(group *orm.WorldGroup) *protobuf.GROUPINCHAPTER_P33 {
+	pos := worldPos(chapterPos{Row: group.Row, Column: group.Column})
+	ships := make([]*protobuf.SHIPINCHAPTER_P33, 0, len(group.ShipList))
+	for _, shipID := range group.ShipList {
+		ships = append(ships, &protobuf.SHIPINCHAPTER_P33{
+			Id:     proto.Uint32(uint32(shipID)),
+			HpRant: proto.Uint32(worldShipHpFull),
+		})
+	}
+	return &protobuf.GROUPINCHAPTER_P33{
+		Id:          proto.Uint32(group.GroupID),
+		ShipList:    ships,
+		Pos:         pos,
+		LossFlag:    proto.Uint32(0),
+		Bullet:      proto.Uint32(0),
+		StartPos:    pos,
+		DamageLevel: proto.Uint32(0),
+		KillCount:   proto.Uint32(0),
+		BulletMax:   proto.Uint32(0),
+	}
+}
+
+// worldSession gathers what the world info packets are built from.
+type worldSession struct {
+	world  *orm.World
+	maps   []orm.WorldMap
+	groups []orm.WorldGroup
+	items  []orm.WorldItem
+}
+
+func loadWorldSession(commanderID uint32) (*worldSession, error) {
+	world, err := loadWorld(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	session := worldSession{world: world}
+	if session.maps, err = orm.ListWorldMaps(commanderID); err != nil {
+		return nil, err
+	}
+	if session.groups, err = orm.ListWorldGroups(commanderID); err != nil {
+		return nil, err
+	}
+	if session.items, err = orm.ListWorldItems(commanderID); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (session *worldSession) hasMap(mapID uint32) bool {
+	for _, worldMap := range session.maps {
+		if worldMap.MapID == mapID {
+			return true
+		}
+	}
+	return false
+}
+
+// portList lists the ports of the unlocked maps.
+func (session *worldSession) portList() ([]uint32, error) {
+	ports := []uint32{}
+	for _, worldMap := range session.maps {
+		template, err := loadWorldMapTemplate(worldMap.MapID)
+		if err != nil {
+			return nil, err
+		}
+		if template != nil && template.PortID != 0 && !containsUint32(ports, template.PortID) {
+			ports = append(ports, template.PortID)
+		}
+	}
+	return ports, nil
+}
+
+func (session *worldSession) info() *protobuf.WORLDINFO {
+	world := session.world
+	info := protobuf.WORLDINFO{
+		MapId:                    proto.Uint32(world.MapID),
+		GroupList:                make([]*protobuf.GROUPINCHAPTER_P33, 0, len(session.groups)),
+		SubmarineState:           proto.Uint32(0),
+		ItemList:                 make([]*protobuf.WORLD_ITEM_INFO, 0, len(session.items)),
+		ActionPower:              proto.Uint32(world.ActionPower),
+		ActionPowerExtra:         proto.Uint32(world.ActionPowerExtra),
+		LastRecoverTimestamp:     proto.Uint32(world.LastRecoverTime),
+		ActionPowerFetchCount:    proto.Uint32(world.ActionPowerFetchCount),
+		LastChangeGroupTimestamp: proto.Uint32(0),
+		EnterMapId:               proto.Uint32(world.MapID),
+		ChapterList:              make([]*protobuf.WORLDMAPID, 0, len(session.maps)),
+	}
+	for i := range session.groups {
+		info.GroupList = append(info.GroupList, worldGroupInfo(&session.groups[i]))
+	}
+	for _, item := range session.items {
+		info.ItemList = append(info.ItemList, &protobuf.WORLD_ITEM_INFO{
+			Id:    proto.Uint32(item.ItemID),
+			Count: proto.Uint32(item.Count),
+		})
+	}
+	for _, worldMap := range session.maps {
+		info.ChapterList = append(info.ChapterList, worldMapID(worldMap.MapID))
+	}
+	return &info
+}
+
+func (session *worldSession) fleetList() []*protobuf.FLEETINFO {
+	fleets := make([]*protobuf.FLEETINFO, 0, len(session.groups))
+	for _, group := range session.groups {
+		fleets = append(fleets, &protobuf.FLEETINFO{
+			Id:       proto.Uint32(group.GroupID),
+			ShipList: orm.ToUint32List(group.ShipList),
+		})
+	}
+	return fleets
+}
+
+// WorldEnter handles CS_33101: the elite fleets are sent to the entrance of
+// an unlocked map, joining the camp picked on the first entry.
+func WorldEnter(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33101
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33102, err
+	}
+	commanderID := client.Commander.CommanderID
+	session, err := loadWorldSession(commanderID)
+	if err != nil {
+		return 0, 33102, err
+	}
+	failed := &protobuf.SC_33102{
+		Result:    proto.Uint32(worldResultFailed),
+		CountInfo: worldCountInfo(session.world),
+	}
+	fleets := payload.GetEliteFleetList()
+	if !isWorldOpen(client) || len(fleets) == 0 || len(fleets) > worldGroupLimit {
+		return client.SendMessage(33102, failed)
+	}
+	camp := session.world.Camp
+	if camp == 0 {
+		camp = payload.GetCamp()
+	}
+	if camp == 0 {
+		return client.SendMessage(33102, failed)
+	}
+	template, err := loadWorldMapTemplate(payload.GetEnterMapId())
+	if err != nil {
+		return 0, 33102, err
+	}
+	if template == nil || (!template.Start && !session.hasMap(template.ID)) {
+		return client.SendMessage(33102, failed)
+	}
+	if client.Commander.OwnedShipsMap == nil {
+		if err := client.Commander.Load(); err != nil {
+			return 0, 33102, err
+		}
+	}
+	entrance := template.entrance()
+	groups := make([]orm.WorldGroup, 0, len(fleets))
+	seen := []uint32{}
+	for i, fleet := range fleets {
+		ships := fleet.GetShipIdList()
+		if len(ships) == 0 || len(ships) > worldGroupShipLimit {
+			return client.SendMessage(33102, failed)
+		}
+		for _, shipID := range ships {
+			if _, ok := client.Commander.OwnedShipsMap[shipID]; !ok || containsUint32(seen, shipID) {
+				return client.SendMessage(33102, failed)
+			}
+			seen = append(seen, shipID)
+		}
+		groups = append(groups, orm.WorldGroup{
+			CommanderID: commanderID,
+			GroupID:     uint32(i + 1),
+			ShipList:    orm.ToInt64List(ships),
+			Row:         entrance.Row,
+			Column:      entrance.Column,
+		})
+	}
+	world := session.world
+	if world.Camp == 0 {
+		world.ActionPower = worldActionPowerMax
+		world.LastRecoverTime = uint32(time.Now().Unix())
+	}
+	world.Camp = camp
+	world.MapID = template.ID
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.DeleteWorldGroupsTx(ctx, tx, commanderID); err != nil {
+			return err
+		}
+		for i := range groups {
+			if err := orm.SaveWorldGroupTx(ctx, tx, &groups[i]); err != nil {
+				return err
+			}
+		}
+		if !session.hasMap(template.ID) {
+			if err := orm.SaveWorldMapTx(ctx, tx, &orm.WorldMap{CommanderID: commanderID, MapID: template.ID}); err != nil {
+				return err
+			}
+		}
+		return orm.SaveWorldTx(ctx, tx, world)
+	})
+	if err != nil {
+		return 0, 33102, err
+	}
+	if session, err = loadWorldSession(commanderID); err != nil {
+		return 0, 33102, err
+	}
+	ports, err := session.portList()
+	if err != nil {
+		return 0, 33102, err
+	}
+	return client.SendMessage(33102, &protobuf.SC_33102{
+		Result:    proto.Uint32(worldResultOK),
+		World:     session.info(),
+		CountInfo: worldCountInfo(session.world),
+		PortList:  ports,
+	})
+}
+
+// resetWorld wipes the world state of the commander for the "world reset"
+// command.
+func resetWorld(client *connection.Client) error {
+	if client.Commander == nil {
+		return nil
+	}
+	return orm.ResetWorld(client.Commander.CommanderID)
+}
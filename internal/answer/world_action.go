@@ -0,0 +1,230 @@
+package answer
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	// worldActMove moves a fleet to [act_arg_1, act_arg_2] of the current
+	// map.
+	worldActMove = 1
+	// worldActTransport sends every fleet to the entrance of the unlocked
+	// map act_arg_1.
+	worldActTransport = 2
+)
+
+func findWorldGroup(groups []orm.WorldGroup, groupID uint32) *orm.WorldGroup {
+	for i := range groups {
+		if groups[i].GroupID == groupID {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// WorldAction handles CS_33103, the operations done by the fleets on the
+// world grid. Every move costs action power.
+func WorldAction(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33103
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33104, err
+	}
+	failed := &protobuf.SC_33104{
+		Result:  proto.Uint32(worldResultFailed),
+		EventId: proto.Uint32(0),
+	}
+	session, err := loadWorldSession(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33104, err
+	}
+	world := session.world
+	if world.Camp == 0 {
+		return client.SendMessage(33104, failed)
+	}
+	switch payload.GetAct() {
+	case worldActMove:
+		group := findWorldGroup(session.groups, payload.GetGroupId())
+		if group == nil {
+			return client.SendMessage(33104, failed)
+		}
+		template, err := loadWorldMapTemplate(world.MapID)
+		if err != nil {
+			return 0, 33104, err
+		}
+		if template == nil {
+			return client.SendMessage(33104, failed)
+		}
+		start := chapterPos{Row: group.Row, Column: group.Column}
+		end := chapterPos{Row: payload.GetActArg_1(), Column: payload.GetActArg_2()}
+		path := findMovePath(template.grids, start, end)
+		if len(path) < 2 {
+			return client.SendMessage(33104, failed)
+		}
+		steps := uint32(len(path) - 1)
+		if !spendWorldActionPower(world, steps*template.moveCost()) {
+			return client.SendMessage(33104, failed)
+		}
+		group.Row, group.Column = end.Row, end.Column
+		world.StepCount += steps
+		ctx := context.Background()
+		err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+			if err := orm.SaveWorldGroupTx(ctx, tx, group); err != nil {
+				return err
+			}
+			return orm.SaveWorldTx(ctx, tx, world)
+		})
+		if err != nil {
+			return 0, 33104, err
+		}
+		movePath := make([]*protobuf.CHAPTERCELLPOS_P33, 0, len(path))
+		for _, pos := range path {
+			movePath = append(movePath, worldPos(pos))
+		}
+		return client.SendMessage(33104, &protobuf.SC_33104{
+			Result:           proto.Uint32(worldResultOK),
+			MovePath:         movePath,
+			EventId:          proto.Uint32(0),
+			ActionPower:      proto.Uint32(world.ActionPower),
+			ActionPowerExtra: proto.Uint32(world.ActionPowerExtra),
+		})
+	case worldActTransport:
+		mapID := payload.GetActArg_1()
+		if !session.hasMap(mapID) {
+			return client.SendMessage(33104, failed)
+		}
+		template, err := loadWorldMapTemplate(mapID)
+		if err != nil {
+			return 0, 33104, err
+		}
+		if template == nil {
+			return client.SendMessage(33104, failed)
+		}
+		entrance := template.entrance()
+		world.MapID = mapID
+		ctx := context.Background()
+		err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+			for i := range session.groups {
+				session.groups[i].Row, session.groups[i].Column = entrance.Row, entrance.Column
+				if err := orm.SaveWorldGroupTx(ctx, tx, &session.groups[i]); err != nil {
+					return err
+				}
+			}
+			return orm.SaveWorldTx(ctx, tx, world)
+		})
+		if err != nil {
+			return 0, 33104, err
+		}
+		return client.SendMessage(33104, &protobuf.SC_33104{
+			Result:           proto.Uint32(worldResultOK),
+			EnterMapId:       proto.Uint32(mapID),
+			Id:               worldMapID(mapID),
+			EventId:          proto.Uint32(0),
+			ActionPower:      proto.Uint32(world.ActionPower),
+			ActionPowerExtra: proto.Uint32(world.ActionPowerExtra),
+		})
+	default:
+		return client.SendMessage(33104, failed)
+	}
+}
+
+// WorldMapFetch handles CS_33106: the cells of an unlocked map.
+func WorldMapFetch(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33106
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33107, err
+	}
+	failed := &protobuf.SC_33107{Result: proto.Uint32(worldResultFailed)}
+	maps, err := orm.ListWorldMaps(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33107, err
+	}
+	session := worldSession{maps: maps}
+	if !session.hasMap(payload.GetId()) {
+		return client.SendMessage(33107, failed)
+	}
+	template, err := loadWorldMapTemplate(payload.GetId())
+	if err != nil {
+		return 0, 33107, err
+	}
+	if template == nil {
+		return client.SendMessage(33107, failed)
+	}
+	cells := make([]*protobuf.CHAPTERCELLINFO_P33, 0, len(template.grids))
+	for _, grid := range template.grids {
+		if !grid.Walkable {
+			continue
+		}
+		cells = append(cells, &protobuf.CHAPTERCELLINFO_P33{
+			Pos:        worldPos(chapterPos{Row: grid.Row, Column: grid.Column}),
+			Discovered: proto.Uint32(1),
+		})
+	}
+	return client.SendMessage(33107, &protobuf.SC_33107{
+		Result: proto.Uint32(worldResultOK),
+		Map: &protobuf.MAPINFO{
+			Id:       worldMapID(template.ID),
+			CellList: cells,
+		},
+		IsReset: proto.Uint32(0),
+	})
+}
+
+// checkWorldStage reports whether the commander can start a world battle,
+// which requires fleets in the world.
+func checkWorldStage(client *connection.Client) (bool, error) {
+	world, err := loadWorld(client.Commander.CommanderID)
+	if err != nil {
+		return false, err
+	}
+	return world.Camp != 0 && world.MapID != 0, nil
+}
+
+// finishWorldStage records a won world battle: beating the stage of the
+// current map clears it and unlocks the maps that follow it.
+func finishWorldStage(client *connection.Client, stageID uint32) error {
+	commanderID := client.Commander.CommanderID
+	world, err := loadWorld(commanderID)
+	if err != nil {
+		return err
+	}
+	if world.MapID == 0 {
+		return nil
+	}
+	template, err := loadWorldMapTemplate(world.MapID)
+	if err != nil {
+		return err
+	}
+	if template == nil || template.StageID == 0 || template.StageID != stageID {
+		return nil
+	}
+	maps, err := orm.ListWorldMaps(commanderID)
+	if err != nil {
+		return err
+	}
+	session := worldSession{maps: maps}
+	if template.Progress > world.Progress {
+		world.Progress = template.Progress
+	}
+	ctx := context.Background()
+	return orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.SaveWorldMapTx(ctx, tx, &orm.WorldMap{CommanderID: commanderID, MapID: template.ID, IsCleared: true}); err != nil {
+			return err
+		}
+		for _, mapID := range template.Unlock {
+			if session.hasMap(mapID) {
+				continue
+			}
+			if err := orm.SaveWorldMapTx(ctx, tx, &orm.WorldMap{CommanderID: commanderID, MapID: mapID}); err != nil {
+				return err
+			}
+		}
+		return orm.SaveWorldTx(ctx, tx, world)
+	})
+}
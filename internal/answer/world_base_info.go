@@ -2,14 +2,26 @@ package answer
 
 import (
 	"github.com/ggmolly/belfast/internal/connection"
-
+	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
 	"google.golang.org/protobuf/proto"
 )
 
 func WorldBaseInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	world, err := loadWorld(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33114, err
+	}
+	groups, err := orm.ListWorldGroups(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33114, err
+	}
 	var response protobuf.SC_33114
-	response.IsWorldOpen = proto.Uint32(0)
-	response.Progress = proto.Uint32(0)
+	response.IsWorldOpen = proto.Uint32(boolToUint32(isWorldOpen(client)))
+	response.Progress = proto.Uint32(world.Progress)
+	response.ShipIdList = []uint32{}
+	for _, group := range groups {
+		response.ShipIdList = append(response.ShipIdList, orm.ToUint32List(group.ShipList)...)
+	}
 	return client.SendMessage(33114, &response)
 }
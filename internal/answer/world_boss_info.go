@@ -1,39 +1,691 @@
 package answer
 
 import (
-	"github.com/ggmolly/belfast/internal/connection"
+	"context"
+	"math"
+	"time"
 
-	"github.com/ggmolly/belfast/internal/protobuf"
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
 )
 
+const (
+	worldBossCategory = "ShareCfg/world_joint_boss_template.json"
+
+	worldBossResultOK     = uint32(0)
+	worldBossResultFailed = uint32(1)
+
+	// worldBossFightCountMax is the number of fights a commander can store;
+	// one more is earned every worldBossFightCountInterval seconds.
+	worldBossFightCountMax      = uint32(3)
+	worldBossFightCountInterval = uint32(60 * 60)
+	// worldBossSummonPtPerBattle is earned by winning a world battle, up to
+	// worldBossSummonPtDailyMax points a day.
+	worldBossSummonPtPerBattle = uint32(10)
+	worldBossSummonPtDailyMax  = uint32(200)
+	// worldBossAutoFightDuration is the time an auto fight takes, in
+	// seconds.
+	worldBossAutoFightDuration = uint32(30 * 60)
+
+	worldBossSupportFriend = 1
+	worldBossSupportGuild  = 2
+	worldBossSupportWorld  = 3
+)
+
+// worldBossTemplate is a summonable boss: summoning one costs summon_pt
+// points, and it can be fought for time seconds. oil is the cost of each
+// auto fight, and award the drops ([type, id, count]) of every commander
+// that damaged it once it is killed.
+type worldBossTemplate struct {
+	ID       uint32     `json:"id"`
+	HP       uint32     `json:"hp"`
+	Level    uint32     `json:"level"`
+	SummonPt uint32     `json:"summon_pt"`
+	Time     uint32     `json:"time"`
+	Oil      uint32     `json:"oil"`
+	Award    [][]uint32 `json:"award"`
+}
+
+func loadWorldBossTemplate(id uint32) (*worldBossTemplate, error) {
+	var template worldBossTemplate
+	ok, err := loadWorldConfig(worldBossCategory, id, &template)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// recoverWorldBossFightCount regenerates the fights earned since the last
+// update, keeping the time spent on a partial fight.
+func recoverWorldBossFightCount(state *orm.WorldBossState, now uint32) bool {
+	if state.FightCountUpdateTime == 0 || state.FightCountUpdateTime > now {
+		state.FightCount = worldBossFightCountMax
+		state.FightCountUpdateTime = now
+		return true
+	}
+	if state.FightCount >= worldBossFightCountMax {
+		changed := state.FightCountUpdateTime != now
+		state.FightCountUpdateTime = now
+		return changed
+	}
+	fights := (now - state.FightCountUpdateTime) / worldBossFightCountInterval
+	if fights == 0 {
+		return false
+	}
+	state.FightCount += fights
+	state.FightCountUpdateTime += fights * worldBossFightCountInterval
+	if state.FightCount >= worldBossFightCountMax {
+		state.FightCount = worldBossFightCountMax
+		state.FightCountUpdateTime = now
+	}
+	return true
+}
+
+func loadWorldBossState(commanderID uint32) (*orm.WorldBossState, error) {
+	state, err := orm.GetOrCreateWorldBossState(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	reset := orm.ApplyWorldBossDailyReset(state, now)
+	if recoverWorldBossFightCount(state, uint32(now.Unix())) || reset {
+		if err := orm.SaveWorldBossState(state); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func worldBossInfo(boss *orm.WorldBoss) *protobuf.WORLDBOSS_INFO_P34 {
+	return &protobuf.WORLDBOSS_INFO_P34{
+		Id:         proto.Uint32(boss.ID),
+		TemplateId: proto.Uint32(boss.TemplateID),
+		Lv:         proto.Uint32(boss.Level),
+		Hp:         proto.Uint32(boss.HP),
+		Owner:      proto.Uint32(boss.OwnerID),
+		LastTime:   proto.Uint32(boss.ExpireTime),
+		KillTime:   proto.Uint32(boss.KillTime),
+		FightCount: proto.Uint32(boss.FightCount),
+		RankCount:  proto.Uint32(boss.RankCount),
+	}
+}
+
+// worldBossVisible reports whether commanderID may fight the boss: its
+// owner always can, others once the owner asked them for support.
+func worldBossVisible(commanderID uint32, boss *orm.WorldBoss) (bool, error) {
+	if boss.OwnerID == commanderID || boss.WorldSupport {
+		return true, nil
+	}
+	if boss.FriendSupport {
+		friends, err := orm.AreFriends(boss.OwnerID, commanderID)
+		if err != nil || friends {
+			return friends, err
+		}
+	}
+	if boss.GuildSupport {
+		owner, err := loadGuildMembership(boss.OwnerID)
+		if err != nil || owner == nil {
+			return false, err
+		}
+		member, err := loadGuildMembership(commanderID)
+		if err != nil || member == nil {
+			return false, err
+		}
+		return owner.GuildID == member.GuildID, nil
+	}
+	return false, nil
+}
+
+// loadFightableWorldBoss returns the boss if commanderID can fight it now.
+func loadFightableWorldBoss(commanderID uint32, bossID uint32) (*orm.WorldBoss, error) {
+	boss, err := orm.GetWorldBoss(bossID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !boss.Alive(uint32(time.Now().Unix())) {
+		return nil, nil
+	}
+	visible, err := worldBossVisible(commanderID, boss)
+	if err != nil || !visible {
+		return nil, err
+	}
+	return boss, nil
+}
+
+// pushWorldBossHp sends the HP of a boss to its online owner and fighters.
+func pushWorldBossHp(client *connection.Client, boss *orm.WorldBoss) error {
+	damages, err := orm.ListWorldBossDamages(boss.ID)
+	if err != nil {
+		return err
+	}
+	targets := []uint32{boss.OwnerID}
+	for _, damage := range damages {
+		if !containsUint32(targets, damage.CommanderID) {
+			targets = append(targets, damage.CommanderID)
+		}
+	}
+	message := &protobuf.SC_34508{BossId: proto.Uint32(boss.ID), Hp: proto.Uint32(boss.HP)}
+	for _, id := range targets {
+		pushToCommander(client, id, 34508, message)
+	}
+	return nil
+}
+
+// WorldBossInfo handles CS_34501: the summon points and fights of the
+// commander, with the boss they summoned.
 func WorldBossInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	commanderID := client.Commander.CommanderID
+	state, err := loadWorldBossState(commanderID)
+	if err != nil {
+		return 0, 34502, err
+	}
 	response := protobuf.SC_34502{
-		FightCount:           proto.Uint32(0),
-		FightCountUpdateTime: proto.Uint32(0),
-		SelfBoss: &protobuf.WORLDBOSS_INFO_P34{
-			Id:         proto.Uint32(0),
-			TemplateId: proto.Uint32(0),
-			Lv:         proto.Uint32(0),
-			Hp:         proto.Uint32(0),
-			Owner:      proto.Uint32(0),
-			LastTime:   proto.Uint32(0),
-			KillTime:   proto.Uint32(0),
-			FightCount: proto.Uint32(0),
-			RankCount:  proto.Uint32(0),
-		},
-		SummonPt:            proto.Uint32(0),
-		SummonPtOld:         proto.Uint32(0),
-		SummonPtDailyAcc:    proto.Uint32(0),
-		SummonPtOldDailyAcc: proto.Uint32(0),
-		SummonFree:          proto.Uint32(0),
-		AutoFightFinishTime: proto.Uint32(0),
-		DefaultBossId:       proto.Uint32(0),
-		AutoFightMaxDamage:  proto.Uint32(0),
-		GuildSupport:        proto.Uint32(0),
-		FriendSupport:       proto.Uint32(0),
-		WorldSupport:        proto.Uint32(0),
-		SelfBossLv:          proto.Uint32(0),
+		FightCount:           proto.Uint32(state.FightCount),
+		FightCountUpdateTime: proto.Uint32(state.FightCountUpdateTime),
+		SummonPt:             proto.Uint32(state.SummonPt),
+		SummonPtOld:          proto.Uint32(0),
+		SummonPtDailyAcc:     proto.Uint32(state.SummonPtDailyAcc),
+		SummonPtOldDailyAcc:  proto.Uint32(0),
+		SummonFree:           proto.Uint32(0),
+		AutoFightFinishTime:  proto.Uint32(state.AutoFightFinishTime),
+		DefaultBossId:        proto.Uint32(0),
+		AutoFightMaxDamage:   proto.Uint32(0),
+		GuildSupport:         proto.Uint32(0),
+		FriendSupport:        proto.Uint32(0),
+		WorldSupport:         proto.Uint32(0),
+		SelfBossLv:           proto.Uint32(0),
+	}
+	boss, err := orm.GetActiveWorldBoss(commanderID, uint32(time.Now().Unix()))
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 34502, err
+	}
+	if boss != nil {
+		response.SelfBoss = worldBossInfo(boss)
+		response.SelfBossLv = proto.Uint32(boss.Level)
+		response.DefaultBossId = proto.Uint32(boss.TemplateID)
+		response.GuildSupport = proto.Uint32(boolToUint32(boss.GuildSupport))
+		response.FriendSupport = proto.Uint32(boolToUint32(boss.FriendSupport))
+		response.WorldSupport = proto.Uint32(boolToUint32(boss.WorldSupport))
+	}
+	if state.AutoFightBossID != 0 {
+		damage, err := orm.GetWorldBossDamage(state.AutoFightBossID, commanderID)
+		if err != nil && !db.IsNotFound(err) {
+			return 0, 34502, err
+		}
+		if damage != nil {
+			response.AutoFightMaxDamage = proto.Uint32(damage.MaxDamage)
+		}
 	}
 	return client.SendMessage(34502, &response)
 }
+
+// WorldBossOtherList handles CS_34503: the bosses of other commanders that
+// asked the commander for support.
+func WorldBossOtherList(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34503
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34504, err
+	}
+	commanderID := client.Commander.CommanderID
+	bosses, err := orm.ListActiveWorldBossesByOwners(payload.GetUserIdList(), uint32(time.Now().Unix()))
+	if err != nil {
+		return 0, 34504, err
+	}
+	response := protobuf.SC_34504{BossList: []*protobuf.WORLDBOSS_INFO_P34{}}
+	for i := range bosses {
+		if bosses[i].OwnerID == commanderID {
+			continue
+		}
+		visible, err := worldBossVisible(commanderID, &bosses[i])
+		if err != nil {
+			return 0, 34504, err
+		}
+		if visible {
+			response.BossList = append(response.BossList, worldBossInfo(&bosses[i]))
+		}
+	}
+	return client.SendMessage(34504, &response)
+}
+
+// WorldBossRank handles CS_34505: the damage ranking of a boss.
+func WorldBossRank(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34505
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34506, err
+	}
+	damages, err := orm.ListWorldBossDamages(payload.GetBossId())
+	if err != nil {
+		return 0, 34506, err
+	}
+	response := protobuf.SC_34506{RankList: make([]*protobuf.WORLDBOSS_RANK_P34, 0, len(damages))}
+	for _, damage := range damages {
+		response.RankList = append(response.RankList, &protobuf.WORLDBOSS_RANK_P34{
+			Id:     proto.Uint32(damage.CommanderID),
+			Name:   proto.String(damage.Name),
+			Damage: proto.Uint32(damage.Damage),
+		})
+	}
+	return client.SendMessage(34506, &response)
+}
+
+// WorldBossRefresh handles CS_34517: the HP of a list of bosses.
+func WorldBossRefresh(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34517
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34518, err
+	}
+	bosses, err := orm.ListWorldBossesByIDs(payload.GetBossId())
+	if err != nil {
+		return 0, 34518, err
+	}
+	response := protobuf.SC_34518{List: make([]*protobuf.WORLDBOSS_SIMPLE, 0, len(bosses))}
+	for _, boss := range bosses {
+		response.List = append(response.List, &protobuf.WORLDBOSS_SIMPLE{
+			Id:        proto.Uint32(boss.ID),
+			Hp:        proto.Uint32(boss.HP),
+			RankCount: proto.Uint32(boss.RankCount),
+		})
+	}
+	return client.SendMessage(34518, &response)
+}
+
+// WorldBossSummon handles CS_34521: a boss is summoned with summon points,
+// one at a time.
+func WorldBossSummon(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34521
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34522, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_34522{Result: proto.Uint32(worldBossResultFailed)}
+	template, err := loadWorldBossTemplate(payload.GetTemplateId())
+	if err != nil {
+		return 0, 34522, err
+	}
+	if template == nil || template.HP == 0 {
+		return client.SendMessage(34522, failed)
+	}
+	now := uint32(time.Now().Unix())
+	if _, err := orm.GetActiveWorldBoss(commanderID, now); err == nil {
+		return client.SendMessage(34522, failed)
+	} else if !db.IsNotFound(err) {
+		return 0, 34522, err
+	}
+	state, err := loadWorldBossState(commanderID)
+	if err != nil {
+		return 0, 34522, err
+	}
+	if state.SummonPt < template.SummonPt {
+		return client.SendMessage(34522, failed)
+	}
+	state.SummonPt -= template.SummonPt
+	level := template.Level
+	if level == 0 {
+		level = 1
+	}
+	boss := orm.WorldBoss{
+		OwnerID:    commanderID,
+		TemplateID: template.ID,
+		Level:      level,
+		HP:         template.HP,
+		MaxHP:      template.HP,
+		ExpireTime: now + template.Time,
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.CreateWorldBossTx(ctx, tx, &boss); err != nil {
+			return err
+		}
+		return orm.SaveWorldBossStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		return 0, 34522, err
+	}
+	return client.SendMessage(34522, &protobuf.SC_34522{
+		Result: proto.Uint32(worldBossResultOK),
+		Boss:   worldBossInfo(&boss),
+	})
+}
+
+// WorldBossSupport handles CS_34509: the owner asks friends, guild members
+// or every commander to help against their boss. Online commanders it is
+// shared with are notified.
+func WorldBossSupport(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34509
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34510, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_34510{Result: proto.Uint32(worldBossResultFailed)}
+	boss, err := orm.GetActiveWorldBoss(commanderID, uint32(time.Now().Unix()))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(34510, failed)
+		}
+		return 0, 34510, err
+	}
+	var targets []uint32
+	switch payload.GetType() {
+	case worldBossSupportFriend:
+		boss.FriendSupport = true
+		friends, err := orm.ListCommanderFriends(commanderID)
+		if err != nil {
+			return 0, 34510, err
+		}
+		for _, friend := range friends {
+			targets = append(targets, friend.CommanderID)
+		}
+	case worldBossSupportGuild:
+		member, err := loadGuildMembership(commanderID)
+		if err != nil {
+			return 0, 34510, err
+		}
+		if member == nil {
+			return client.SendMessage(34510, failed)
+		}
+		boss.GuildSupport = true
+		if targets, err = orm.ListGuildMemberIDs(member.GuildID); err != nil {
+			return 0, 34510, err
+		}
+	case worldBossSupportWorld:
+		boss.WorldSupport = true
+		if server := clientServer(client); server != nil {
+			for _, other := range server.ListClients() {
+				if other.Commander != nil {
+					targets = append(targets, other.Commander.CommanderID)
+				}
+			}
+		}
+	default:
+		return client.SendMessage(34510, failed)
+	}
+	if err := orm.SaveWorldBossSupport(boss); err != nil {
+		return 0, 34510, err
+	}
+	profile, err := orm.GetFriendProfile(commanderID)
+	if err != nil {
+		return 0, 34510, err
+	}
+	message := &protobuf.SC_34507{
+		BossInfo: worldBossInfo(boss),
+		UserInfo: &protobuf.USERSIMPLEINFO{
+			Id:      proto.Uint32(profile.CommanderID),
+			Name:    proto.String(profile.Name),
+			Lv:      proto.Uint32(profile.Level),
+			Display: buildFriendDisplay(profile),
+		},
+		Type: proto.Uint32(payload.GetType()),
+	}
+	for _, id := range targets {
+		if id == commanderID {
+			continue
+		}
+		pushToCommander(client, id, 34507, message)
+	}
+	return client.SendMessage(34510, &protobuf.SC_34510{Result: proto.Uint32(worldBossResultOK)})
+}
+
+// WorldBossCheck handles CS_34515, sent before fighting a boss.
+func WorldBossCheck(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34515
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34516, err
+	}
+	ok, err := checkWorldBossStage(client, payload.GetBossId(), false)
+	if err != nil {
+		return 0, 34516, err
+	}
+	result := worldBossResultOK
+	if !ok {
+		result = worldBossResultFailed
+	}
+	return client.SendMessage(34516, &protobuf.SC_34516{Result: proto.Uint32(result)})
+}
+
+// checkWorldBossStage reports whether the commander can fight the boss,
+// spending one fight when consume is set.
+func checkWorldBossStage(client *connection.Client, bossID uint32, consume bool) (bool, error) {
+	commanderID := client.Commander.CommanderID
+	boss, err := loadFightableWorldBoss(commanderID, bossID)
+	if err != nil || boss == nil {
+		return false, err
+	}
+	state, err := loadWorldBossState(commanderID)
+	if err != nil {
+		return false, err
+	}
+	if state.FightCount == 0 || state.AutoFightBossID != 0 {
+		return false, nil
+	}
+	if !consume {
+		return true, nil
+	}
+	if state.FightCount == worldBossFightCountMax {
+		state.FightCountUpdateTime = uint32(time.Now().Unix())
+	}
+	state.FightCount--
+	return true, orm.SaveWorldBossState(state)
+}
+
+// finishWorldBossStage credits the damage of a boss fight; the boss id is
+// the stage of the battle session.
+func finishWorldBossStage(client *connection.Client, bossID uint32, stats map[uint32]*protobuf.STATISTICSINFO) error {
+	damage := uint64(0)
+	for _, entry := range stats {
+		damage += uint64(entry.GetDamageCaused())
+	}
+	damage = min(damage, math.MaxUint32)
+	boss, err := orm.DamageWorldBoss(bossID, client.Commander.CommanderID, uint32(damage), 1, uint32(time.Now().Unix()))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return pushWorldBossHp(client, boss)
+}
+
+// grantWorldBossSummonPt gives the summon points of a won world battle.
+func grantWorldBossSummonPt(client *connection.Client) error {
+	state, err := loadWorldBossState(client.Commander.CommanderID)
+	if err != nil {
+		return err
+	}
+	if state.SummonPtDailyAcc >= worldBossSummonPtDailyMax {
+		return nil
+	}
+	points := minUint32(worldBossSummonPtPerBattle, worldBossSummonPtDailyMax-state.SummonPtDailyAcc)
+	state.SummonPt += points
+	state.SummonPtDailyAcc += points
+	return orm.SaveWorldBossState(state)
+}
+
+// WorldBossAward handles CS_34511: every commander that damaged a killed
+// boss can claim its reward once.
+func WorldBossAward(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34511
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34512, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_34512{Result: proto.Uint32(worldBossResultFailed)}
+	boss, err := orm.GetWorldBoss(payload.GetBossId())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(34512, failed)
+		}
+		return 0, 34512, err
+	}
+	if boss.KillTime == 0 {
+		return client.SendMessage(34512, failed)
+	}
+	template, err := loadWorldBossTemplate(boss.TemplateID)
+	if err != nil {
+		return 0, 34512, err
+	}
+	var awards [][]uint32
+	if template != nil {
+		awards = template.Award
+	}
+	var drops []*protobuf.DROPINFO
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.MarkWorldBossAwardedTx(ctx, tx, boss.ID, commanderID); err != nil {
+			return err
+		}
+		drops, err = grantActivityAwardsTx(ctx, tx, client, awards)
+		return err
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(34512, failed)
+		}
+		return 0, 34512, err
+	}
+	return client.SendMessage(34512, &protobuf.SC_34512{
+		Result: proto.Uint32(worldBossResultOK),
+		Drops:  drops,
+	})
+}
+
+// WorldBossAutoFight handles CS_34523: every stored fight is spent on a boss
+// the commander already fought, each dealing their best damage once the
+// auto fight finishes.
+func WorldBossAutoFight(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34523
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34524, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_34524{Result: proto.Uint32(worldBossResultFailed), AutoFightFinishTime: proto.Uint32(0)}
+	boss, err := loadFightableWorldBoss(commanderID, payload.GetBossId())
+	if err != nil {
+		return 0, 34524, err
+	}
+	if boss == nil {
+		return client.SendMessage(34524, failed)
+	}
+	damage, err := orm.GetWorldBossDamage(boss.ID, commanderID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(34524, failed)
+		}
+		return 0, 34524, err
+	}
+	state, err := loadWorldBossState(commanderID)
+	if err != nil {
+		return 0, 34524, err
+	}
+	if damage.MaxDamage == 0 || state.FightCount == 0 || state.AutoFightBossID != 0 {
+		return client.SendMessage(34524, failed)
+	}
+	template, err := loadWorldBossTemplate(boss.TemplateID)
+	if err != nil {
+		return 0, 34524, err
+	}
+	oil := uint32(0)
+	if template != nil {
+		oil = template.Oil * state.FightCount
+	}
+	if oil > 0 && !client.Commander.HasEnoughResource(2, oil) {
+		return client.SendMessage(34524, failed)
+	}
+	now := uint32(time.Now().Unix())
+	state.AutoFightBossID = boss.ID
+	state.AutoFightCount = state.FightCount
+	state.AutoFightFinishTime = now + worldBossAutoFightDuration
+	state.FightCount = 0
+	state.FightCountUpdateTime = now
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if oil > 0 {
+			if err := client.Commander.ConsumeResourceTx(ctx, tx, 2, oil); err != nil {
+				return err
+			}
+		}
+		return orm.SaveWorldBossStateTx(ctx, tx, state)
+	})
+	if err != nil {
+		return 0, 34524, err
+	}
+	return client.SendMessage(34524, &protobuf.SC_34524{
+		Result:              proto.Uint32(worldBossResultOK),
+		AutoFightFinishTime: proto.Uint32(state.AutoFightFinishTime),
+	})
+}
+
+// WorldBossAutoFightSettle handles CS_34525: a finished auto fight deals
+// its damage, unless the boss was killed or fled in the meantime.
+func WorldBossAutoFightSettle(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_34525
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 34526, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_34526{
+		Result: proto.Uint32(worldBossResultFailed),
+		Count:  proto.Uint32(0),
+		Damage: proto.Uint32(0),
+		Oil:    proto.Uint32(0),
+	}
+	state, err := loadWorldBossState(commanderID)
+	if err != nil {
+		return 0, 34526, err
+	}
+	now := uint32(time.Now().Unix())
+	if state.AutoFightBossID == 0 || state.AutoFightBossID != payload.GetBossId() || state.AutoFightFinishTime > now {
+		return client.SendMessage(34526, failed)
+	}
+	count := state.AutoFightCount
+	total := uint32(0)
+	oil := uint32(0)
+	damage, err := orm.GetWorldBossDamage(state.AutoFightBossID, commanderID)
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 34526, err
+	}
+	boss, err := orm.GetWorldBoss(state.AutoFightBossID)
+	if err != nil && !db.IsNotFound(err) {
+		return 0, 34526, err
+	}
+	if boss != nil {
+		template, err := loadWorldBossTemplate(boss.TemplateID)
+		if err != nil {
+			return 0, 34526, err
+		}
+		if template != nil {
+			oil = template.Oil * count
+		}
+	}
+	if damage != nil && boss != nil {
+		total = damage.MaxDamage * count
+		updated, err := orm.DamageWorldBoss(boss.ID, commanderID, total, count, state.AutoFightFinishTime)
+		if err != nil && !db.IsNotFound(err) {
+			return 0, 34526, err
+		}
+		if updated == nil {
+			total = 0
+		} else if err := pushWorldBossHp(client, updated); err != nil {
+			return 0, 34526, err
+		}
+	}
+	state.AutoFightBossID = 0
+	state.AutoFightCount = 0
+	state.AutoFightFinishTime = 0
+	if err := orm.SaveWorldBossState(state); err != nil {
+		return 0, 34526, err
+	}
+	return client.SendMessage(34526, &protobuf.SC_34526{
+		Result: proto.Uint32(worldBossResultOK),
+		Count:  proto.Uint32(count),
+		Damage: proto.Uint32(total),
+		Oil:    proto.Uint32(oil),
+	})
+}
@@ -0,0 +1,135 @@
+package answer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func TestRecoverWorldBossFightCount(t *testing.T) {
+	state := orm.WorldBossState{FightCount: 0, FightCountUpdateTime: 1000}
+	if recoverWorldBossFightCount(&state, 1000+worldBossFightCountInterval-1) {
+		t.Fatalf("expected no fight before a full interval")
+	}
+	if !recoverWorldBossFightCount(&state, 1000+worldBossFightCountInterval*2+5) || state.FightCount != 2 {
+		t.Fatalf("unexpected recovered fights: %+v", state)
+	}
+	recoverWorldBossFightCount(&state, 1000+worldBossFightCountInterval*100)
+	if state.FightCount != worldBossFightCountMax {
+		t.Fatalf("expected fights to be capped, got %d", state.FightCount)
+	}
+}
+
+func TestWorldBossSummonFightAndAward(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.BattleSession{})
+	clearTable(t, &orm.WorldBoss{})
+	commanderID := client.Commander.CommanderID
+	execAnswerTestSQLT(t, "DELETE FROM commander_world_boss_states WHERE commander_id = $1", int64(commanderID))
+	seedConfigEntry(t, worldBossCategory, "1", `{"id":1,"hp":500,"level":2,"summon_pt":100,"time":3600,"oil":10,"award":[[1,1,300]]}`)
+
+	summon := func() *protobuf.SC_34522 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_34521{TemplateId: proto.Uint32(1)})
+		if _, _, err := WorldBossSummon(&payload, client); err != nil {
+			t.Fatalf("world boss summon failed: %v", err)
+		}
+		response := &protobuf.SC_34522{}
+		decodePacketMessage(t, client, 34522, response)
+		client.Buffer.Reset()
+		return response
+	}
+	if response := summon(); response.GetResult() != worldBossResultFailed {
+		t.Fatalf("expected summon without points to fail, got %v", response)
+	}
+	state, err := orm.GetOrCreateWorldBossState(commanderID)
+	if err != nil {
+		t.Fatalf("get world boss state: %v", err)
+	}
+	state.SummonPt = 150
+	if err := orm.SaveWorldBossState(state); err != nil {
+		t.Fatalf("save world boss state: %v", err)
+	}
+	summoned := summon()
+	if summoned.GetResult() != worldBossResultOK || summoned.GetBoss().GetHp() != 500 || summoned.GetBoss().GetLv() != 2 {
+		t.Fatalf("unexpected summon response: %v", summoned)
+	}
+	if response := summon(); response.GetResult() != worldBossResultFailed {
+		t.Fatalf("expected a second active boss to be refused, got %v", response)
+	}
+	bossID := summoned.GetBoss().GetId()
+
+	begin := marshalPacketRequest(t, &protobuf.CS_40001{System: proto.Uint32(battleSystemWorldBoss), ShipIdList: []uint32{101}, Data: proto.Uint32(bossID)})
+	if _, _, err := BeginStage(&begin, client); err != nil {
+		t.Fatalf("begin stage failed: %v", err)
+	}
+	started := &protobuf.SC_40002{}
+	decodePacketMessage(t, client, 40002, started)
+	client.Buffer.Reset()
+	if started.GetResult() != 0 {
+		t.Fatalf("expected boss battle to start, got %d", started.GetResult())
+	}
+	finish := marshalPacketRequest(t, &protobuf.CS_40003{
+		System:    proto.Uint32(battleSystemWorldBoss),
+		Data:      proto.Uint32(bossID),
+		Key:       proto.Uint32(started.GetKey()),
+		Score:     proto.Uint32(rankScoreS),
+		TotalTime: proto.Uint32(60),
+		Statistics: []*protobuf.STATISTICSINFO{
+			{ShipId: proto.Uint32(101), DamageCause: proto.Uint32(0), DamageCaused: proto.Uint32(600), HpRest: proto.Uint32(100), MaxDamageOnce: proto.Uint32(200), ShipGearScore: proto.Uint32(0)},
+		},
+		BotPercentage:  proto.Uint32(0),
+		ExtraParam:     proto.Uint32(0),
+		AutoBefore:     proto.Uint32(0),
+		AutoSwitchTime: proto.Uint32(0),
+		AutoAfter:      proto.Uint32(0),
+	})
+	backdateBattleSession(t, commanderID)
+	if _, _, err := FinishStage(&finish, client); err != nil {
+		t.Fatalf("finish stage failed: %v", err)
+	}
+	client.Buffer.Reset()
+
+	info := []byte{}
+	if _, _, err := WorldBossInfo(&info, client); err != nil {
+		t.Fatalf("world boss info failed: %v", err)
+	}
+	infoResponse := &protobuf.SC_34502{}
+	decodePacketMessage(t, client, 34502, infoResponse)
+	client.Buffer.Reset()
+	if infoResponse.GetSummonPt() != 50 || infoResponse.GetFightCount() != worldBossFightCountMax-1 || infoResponse.SelfBoss != nil {
+		t.Fatalf("unexpected world boss info: %v", infoResponse)
+	}
+
+	rank := marshalPacketRequest(t, &protobuf.CS_34505{BossId: proto.Uint32(bossID)})
+	if _, _, err := WorldBossRank(&rank, client); err != nil {
+		t.Fatalf("world boss rank failed: %v", err)
+	}
+	rankResponse := &protobuf.SC_34506{}
+	decodePacketMessage(t, client, 34506, rankResponse)
+	client.Buffer.Reset()
+	if len(rankResponse.GetRankList()) != 1 || rankResponse.GetRankList()[0].GetDamage() != 500 {
+		t.Fatalf("unexpected world boss rank: %v", rankResponse)
+	}
+
+	claim := func() *protobuf.SC_34512 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_34511{BossId: proto.Uint32(bossID)})
+		if _, _, err := WorldBossAward(&payload, client); err != nil {
+			t.Fatalf("world boss award failed: %v", err)
+		}
+		response := &protobuf.SC_34512{}
+		decodePacketMessage(t, client, 34512, response)
+		client.Buffer.Reset()
+		return response
+	}
+	if response := claim(); response.GetResult() != worldBossResultOK || len(response.GetDrops()) != 1 {
+		t.Fatalf("unexpected award response: %v", response)
+	}
+	if response := claim(); response.GetResult() != worldBossResultFailed {
+		t.Fatalf("expected award to be claimed once, got %v", response)
+	}
+}
@@ -7,16 +7,26 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// WorldCheckInfo handles CS_33000: the world is only sent once the commander
+// entered it.
 func WorldCheckInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	session, err := loadWorldSession(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33001, err
+	}
+	ports, err := session.portList()
+	if err != nil {
+		return 0, 33001, err
+	}
 	response := protobuf.SC_33001{
-		IsWorldOpen: proto.Uint32(0),
-		Camp:        proto.Uint32(0),
-		CountInfo: &protobuf.COUNTINFO{
-			StepCount:     proto.Uint32(0),
-			TreasureCount: proto.Uint32(0),
-			TaskProgress:  proto.Uint32(0),
-			ActivateCount: proto.Uint32(0),
-		},
+		IsWorldOpen: proto.Uint32(boolToUint32(isWorldOpen(client))),
+		PortList:    ports,
+		Camp:        proto.Uint32(session.world.Camp),
+		CountInfo:   worldCountInfo(session.world),
+		FleetList:   session.fleetList(),
+	}
+	if session.world.Camp != 0 {
+		response.World = session.info()
 	}
 	return client.SendMessage(33001, &response)
 }
@@ -0,0 +1,285 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/consts"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+// worldItemTemplate is an item of the world bag; using one gives
+// action_power points to the extra action power pool.
+type worldItemTemplate struct {
+	ID          uint32 `json:"id"`
+	ActionPower uint32 `json:"action_power"`
+}
+
+// worldPortTemplate is a port and the goods its shop sells.
+type worldPortTemplate struct {
+	ID    uint32   `json:"id"`
+	Goods []uint32 `json:"goods"`
+}
+
+// worldGoodsTemplate is a goods of a port shop: item and price are
+// [drop_type, id, count] and frequency is the number of times it can be
+// bought per day. Prices are paid with resources or world items.
+type worldGoodsTemplate struct {
+	ID        uint32   `json:"id"`
+	Item      []uint32 `json:"item"`
+	Price     []uint32 `json:"price"`
+	Frequency uint32   `json:"frequency"`
+}
+
+// WorldItemUse handles CS_33301: count items of the bag are used.
+func WorldItemUse(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33301
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33302, err
+	}
+	failed := &protobuf.SC_33302{Result: proto.Uint32(worldResultFailed)}
+	if payload.GetCount() == 0 {
+		return client.SendMessage(33302, failed)
+	}
+	var template worldItemTemplate
+	ok, err := loadWorldConfig(worldItemCategory, payload.GetId(), &template)
+	if err != nil {
+		return 0, 33302, err
+	}
+	if !ok || template.ActionPower == 0 {
+		return client.SendMessage(33302, failed)
+	}
+	world, err := loadWorld(client.Commander.CommanderID)
+	if err != nil {
+		return 0, 33302, err
+	}
+	gain := uint64(template.ActionPower) * uint64(payload.GetCount())
+	if uint64(world.ActionPowerExtra)+gain > math.MaxUint32 {
+		return client.SendMessage(33302, failed)
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := orm.ConsumeWorldItemTx(ctx, tx, world.CommanderID, template.ID, payload.GetCount()); err != nil {
+			return err
+		}
+		world.ActionPowerExtra += uint32(gain)
+		return orm.SaveWorldTx(ctx, tx, world)
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			return client.SendMessage(33302, failed)
+		}
+		return 0, 33302, err
+	}
+	return client.SendMessage(33302, &protobuf.SC_33302{Result: proto.Uint32(worldResultOK)})
+}
+
+// worldPortRefreshTime is when the port purchases are reset, at the next
+// UTC midnight.
+func worldPortRefreshTime(now time.Time) uint32 {
+	day := now.UTC().Truncate(24 * time.Hour)
+	return uint32(day.Add(24 * time.Hour).Unix())
+}
+
+// unlockedWorldPort loads a port if one of the unlocked maps has it.
+func unlockedWorldPort(commanderID uint32, portID uint32) (*worldPortTemplate, error) {
+	maps, err := orm.ListWorldMaps(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	session := worldSession{maps: maps}
+	ports, err := session.portList()
+	if err != nil {
+		return nil, err
+	}
+	if portID == 0 || !containsUint32(ports, portID) {
+		return nil, nil
+	}
+	var port worldPortTemplate
+	ok, err := loadWorldConfig(worldPortCategory, portID, &port)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &port, nil
+}
+
+// WorldPortInfo handles CS_33401: the shop of the port of an unlocked map,
+// with the stock left for the day.
+func WorldPortInfo(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33401
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33402, err
+	}
+	commanderID := client.Commander.CommanderID
+	template, err := loadWorldMapTemplate(payload.GetMapId())
+	if err != nil {
+		return 0, 33402, err
+	}
+	if template == nil {
+		return client.SendMessage(33402, &protobuf.SC_33402{})
+	}
+	port, err := unlockedWorldPort(commanderID, template.PortID)
+	if err != nil {
+		return 0, 33402, err
+	}
+	if port == nil {
+		return client.SendMessage(33402, &protobuf.SC_33402{})
+	}
+	if _, err := loadWorld(commanderID); err != nil {
+		return 0, 33402, err
+	}
+	purchases, err := orm.ListWorldPortPurchases(commanderID)
+	if err != nil {
+		return 0, 33402, err
+	}
+	goods := make([]*protobuf.GOODS_INFO_P33, 0, len(port.Goods))
+	for _, goodsID := range port.Goods {
+		var entry worldGoodsTemplate
+		ok, err := loadWorldConfig(worldGoodsCategory, goodsID, &entry)
+		if err != nil {
+			return 0, 33402, err
+		}
+		if !ok {
+			continue
+		}
+		left := uint32(0)
+		if entry.Frequency > purchases[goodsID] {
+			left = entry.Frequency - purchases[goodsID]
+		}
+		goods = append(goods, &protobuf.GOODS_INFO_P33{
+			GoodsId: proto.Uint32(goodsID),
+			Count:   proto.Uint32(left),
+		})
+	}
+	return client.SendMessage(33402, &protobuf.SC_33402{
+		Port: &protobuf.PORT_INFO{
+			PortId:          proto.Uint32(port.ID),
+			GoodsList:       goods,
+			NextRefreshTime: proto.Uint32(worldPortRefreshTime(time.Now())),
+		},
+	})
+}
+
+// WorldPortShopBuy handles CS_33403: goods of an unlocked port are bought
+// within their daily stock.
+func WorldPortShopBuy(buffer *[]byte, client *connection.Client) (int, int, error) {
+	var payload protobuf.CS_33403
+	if err := proto.Unmarshal(*buffer, &payload); err != nil {
+		return 0, 33404, err
+	}
+	commanderID := client.Commander.CommanderID
+	failed := &protobuf.SC_33404{Result: proto.Uint32(worldResultFailed)}
+	count := payload.GetCount()
+	if count == 0 {
+		return client.SendMessage(33404, failed)
+	}
+	var goods worldGoodsTemplate
+	ok, err := loadWorldConfig(worldGoodsCategory, payload.GetShopId(), &goods)
+	if err != nil {
+		return 0, 33404, err
+	}
+	if !ok || len(goods.Item) < 3 || len(goods.Price) < 3 {
+		return client.SendMessage(33404, failed)
+	}
+	maps, err := orm.ListWorldMaps(commanderID)
+	if err != nil {
+		return 0, 33404, err
+	}
+	session := worldSession{maps: maps}
+	ports, err := session.portList()
+	if err != nil {
+		return 0, 33404, err
+	}
+	sold := false
+	for _, portID := range ports {
+		var port worldPortTemplate
+		ok, err := loadWorldConfig(worldPortCategory, portID, &port)
+		if err != nil {
+			return 0, 33404, err
+		}
+		if ok && containsUint32(port.Goods, goods.ID) {
+			sold = true
+			break
+		}
+	}
+	if !sold {
+		return client.SendMessage(33404, failed)
+	}
+	if _, err := loadWorld(commanderID); err != nil {
+		return 0, 33404, err
+	}
+	purchases, err := orm.ListWorldPortPurchases(commanderID)
+	if err != nil {
+		return 0, 33404, err
+	}
+	purchased := purchases[goods.ID]
+	if purchased > goods.Frequency || count > goods.Frequency-purchased {
+		return client.SendMessage(33404, failed)
+	}
+	totalItems := uint64(goods.Item[2]) * uint64(count)
+	totalPrice := uint64(goods.Price[2]) * uint64(count)
+	if totalItems > math.MaxUint32 || totalPrice > math.MaxUint32 {
+		return client.SendMessage(33404, failed)
+	}
+	itemType, itemID, itemCount := goods.Item[0], goods.Item[1], uint32(totalItems)
+	priceType, priceID, priceCount := goods.Price[0], goods.Price[1], uint32(totalPrice)
+
+	errInsufficient := errors.New("insufficient")
+	errInvalid := errors.New("invalid")
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		switch priceType {
+		case consts.DROP_TYPE_RESOURCE:
+			if !client.Commander.HasEnoughResource(priceID, priceCount) {
+				return errInsufficient
+			}
+			if err := client.Commander.ConsumeResourceTx(ctx, tx, priceID, priceCount); err != nil {
+				return errInsufficient
+			}
+		case consts.DROP_TYPE_WORLD_ITEM:
+			if err := orm.ConsumeWorldItemTx(ctx, tx, commanderID, priceID, priceCount); err != nil {
+				if db.IsNotFound(err) {
+					return errInsufficient
+				}
+				return err
+			}
+		default:
+			return errInvalid
+		}
+		switch itemType {
+		case consts.DROP_TYPE_RESOURCE:
+			if err := client.Commander.AddResourceTx(ctx, tx, itemID, itemCount); err != nil {
+				return err
+			}
+		case consts.DROP_TYPE_ITEM:
+			if err := client.Commander.AddItemTx(ctx, tx, itemID, itemCount); err != nil {
+				return err
+			}
+		case consts.DROP_TYPE_WORLD_ITEM:
+			if err := orm.AddWorldItemTx(ctx, tx, commanderID, itemID, itemCount); err != nil {
+				return err
+			}
+		default:
+			return errInvalid
+		}
+		return orm.AddWorldPortPurchaseTx(ctx, tx, commanderID, goods.ID, count)
+	})
+	if err != nil {
+		if errors.Is(err, errInsufficient) || errors.Is(err, errInvalid) {
+			return client.SendMessage(33404, failed)
+		}
+		return 0, 33404, err
+	}
+	return client.SendMessage(33404, &protobuf.SC_33404{
+		Result:   proto.Uint32(worldResultOK),
+		DropList: []*protobuf.DROPINFO{newDropInfo(itemType, itemID, itemCount)},
+	})
+}
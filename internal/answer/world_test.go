@@ -0,0 +1,237 @@
+package answer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func TestRecoverWorldActionPower(t *testing.T) {
+	world := orm.World{ActionPower: 10, LastRecoverTime: 1000}
+	if recoverWorldActionPower(&world, 1000+worldActionPowerRecoverInterval-1) {
+		t.Fatalf("expected no point before a full interval")
+	}
+	if !recoverWorldActionPower(&world, 1000+worldActionPowerRecoverInterval*3+5) || world.ActionPower != 13 {
+		t.Fatalf("unexpected recovered action power: %+v", world)
+	}
+	if world.LastRecoverTime != 1000+worldActionPowerRecoverInterval*3 {
+		t.Fatalf("expected partial point to be kept, got %d", world.LastRecoverTime)
+	}
+	recoverWorldActionPower(&world, 1000+worldActionPowerRecoverInterval*1000)
+	if world.ActionPower != worldActionPowerMax {
+		t.Fatalf("expected action power to be capped, got %d", world.ActionPower)
+	}
+
+	world.ActionPowerExtra = 5
+	if !spendWorldActionPower(&world, 8) || world.ActionPowerExtra != 0 || world.ActionPower != worldActionPowerMax-3 {
+		t.Fatalf("expected extra pool to be spent first: %+v", world)
+	}
+	if spendWorldActionPower(&world, worldActionPowerMax) {
+		t.Fatalf("expected spending more than available to fail")
+	}
+}
+
+func seedWorldConfig(t *testing.T) {
+	t.Helper()
+	grids := `[[1,1,true,0],[1,2,true,0],[1,3,true,0]]`
+	seedConfigEntry(t, worldMapCategory, "1", `{"id":1,"grids":`+grids+`,"entrance":[1,1],"move_cost":2,"stage_id":900001,"unlock":[2],"progress":5,"start":true,"port_id":1}`)
+	seedConfigEntry(t, worldMapCategory, "2", `{"id":2,"grids":`+grids+`,"entrance":[1,2]}`)
+	seedConfigEntry(t, worldPortCategory, "1", `{"id":1,"goods":[1]}`)
+	seedConfigEntry(t, worldGoodsCategory, "1", `{"id":1,"item":[12,50,1],"price":[1,1,10],"frequency":1}`)
+	seedConfigEntry(t, worldItemCategory, "50", `{"id":50,"action_power":20}`)
+}
+
+func worldActionResult(t *testing.T, client *connection.Client, payload *protobuf.CS_33103) *protobuf.SC_33104 {
+	t.Helper()
+	buffer := marshalPacketRequest(t, payload)
+	if _, _, err := WorldAction(&buffer, client); err != nil {
+		t.Fatalf("world action failed: %v", err)
+	}
+	response := &protobuf.SC_33104{}
+	decodePacketMessage(t, client, 33104, response)
+	client.Buffer.Reset()
+	return response
+}
+
+func TestWorldEnterMoveAndPorts(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.BattleSession{})
+	clearTable(t, &orm.Ship{})
+	seedWorldConfig(t)
+	commanderID := client.Commander.CommanderID
+	execAnswerTestSQLT(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)", int64(1001), "Test DD", "Test DD", int64(3), int64(1), int64(1), int64(1), int64(0))
+	execAnswerTestSQLT(t, "INSERT INTO owned_ships (id, owner_id, ship_id, level, max_level, energy, create_time, change_name_timestamp) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())", int64(101), int64(commanderID), int64(1001), int64(1), int64(100), int64(150))
+	if err := client.Commander.Load(); err != nil {
+		t.Fatalf("reload commander: %v", err)
+	}
+
+	enter := marshalPacketRequest(t, &protobuf.CS_33101{
+		Id:             proto.Uint32(0),
+		EnterMapId:     proto.Uint32(1),
+		EliteFleetList: []*protobuf.ELITEFLEETINFO{{ShipIdList: []uint32{101}}},
+		Camp:           proto.Uint32(1),
+	})
+	if _, _, err := WorldEnter(&enter, client); err != nil {
+		t.Fatalf("world enter failed: %v", err)
+	}
+	entered := &protobuf.SC_33102{}
+	decodePacketMessage(t, client, 33102, entered)
+	client.Buffer.Reset()
+	if entered.GetResult() != worldResultOK || entered.GetWorld().GetActionPower() != worldActionPowerMax || len(entered.GetWorld().GetGroupList()) != 1 {
+		t.Fatalf("unexpected enter response: %v", entered)
+	}
+	if len(entered.GetPortList()) != 1 || entered.GetPortList()[0] != 1 {
+		t.Fatalf("expected port of the start map, got %v", entered.GetPortList())
+	}
+
+	moved := worldActionResult(t, client, &protobuf.CS_33103{Act: proto.Uint32(worldActMove), GroupId: proto.Uint32(1), ActArg_1: proto.Uint32(1), ActArg_2: proto.Uint32(3)})
+	if moved.GetResult() != worldResultOK || len(moved.GetMovePath()) != 3 || moved.GetActionPower() != worldActionPowerMax-4 {
+		t.Fatalf("unexpected move response: %v", moved)
+	}
+	if transport := worldActionResult(t, client, &protobuf.CS_33103{Act: proto.Uint32(worldActTransport), GroupId: proto.Uint32(1), ActArg_1: proto.Uint32(2)}); transport.GetResult() != worldResultFailed {
+		t.Fatalf("expected locked map to be refused, got %v", transport)
+	}
+
+	if err := client.Commander.SetResource(1, 100); err != nil {
+		t.Fatalf("seed gold: %v", err)
+	}
+	buy := func(count uint32) uint32 {
+		t.Helper()
+		payload := marshalPacketRequest(t, &protobuf.CS_33403{ShopId: proto.Uint32(1), ShopType: proto.Uint32(0), Count: proto.Uint32(count)})
+		if _, _, err := WorldPortShopBuy(&payload, client); err != nil {
+			t.Fatalf("port buy failed: %v", err)
+		}
+		response := &protobuf.SC_33404{}
+		decodePacketMessage(t, client, 33404, response)
+		client.Buffer.Reset()
+		return response.GetResult()
+	}
+	if result := buy(0xFFFFFFFF); result != worldResultFailed {
+		t.Fatalf("expected a count above the daily stock to be refused, got %d", result)
+	}
+	if result := buy(1); result != worldResultOK {
+		t.Fatalf("expected purchase to succeed, got %d", result)
+	}
+	if result := buy(1); result != worldResultFailed {
+		t.Fatalf("expected daily stock to be exhausted, got %d", result)
+	}
+	port := marshalPacketRequest(t, &protobuf.CS_33401{MapId: proto.Uint32(1)})
+	if _, _, err := WorldPortInfo(&port, client); err != nil {
+		t.Fatalf("port info failed: %v", err)
+	}
+	portResponse := &protobuf.SC_33402{}
+	decodePacketMessage(t, client, 33402, portResponse)
+	client.Buffer.Reset()
+	if goods := portResponse.GetPort().GetGoodsList(); len(goods) != 1 || goods[0].GetCount() != 0 {
+		t.Fatalf("unexpected port goods: %v", portResponse)
+	}
+
+	use := marshalPacketRequest(t, &protobuf.CS_33301{Id: proto.Uint32(50), Count: proto.Uint32(1)})
+	if _, _, err := WorldItemUse(&use, client); err != nil {
+		t.Fatalf("item use failed: %v", err)
+	}
+	used := &protobuf.SC_33302{}
+	decodePacketMessage(t, client, 33302, used)
+	client.Buffer.Reset()
+	if used.GetResult() != worldResultOK {
+		t.Fatalf("expected item use to succeed, got %d", used.GetResult())
+	}
+	world, err := orm.GetOrCreateWorld(commanderID)
+	if err != nil {
+		t.Fatalf("get world: %v", err)
+	}
+	if world.ActionPowerExtra != 20 || world.StepCount != 2 {
+		t.Fatalf("unexpected world: %+v", world)
+	}
+}
+
+func TestWorldBattleUnlocksMaps(t *testing.T) {
+	client := setupPlayerUpdateTest(t)
+	clearTable(t, &orm.BattleSession{})
+	seedWorldConfig(t)
+	commanderID := client.Commander.CommanderID
+
+	begin := marshalPacketRequest(t, &protobuf.CS_40001{System: proto.Uint32(battleSystemWorld), Data: proto.Uint32(900001)})
+	if _, _, err := BeginStage(&begin, client); err != nil {
+		t.Fatalf("begin stage failed: %v", err)
+	}
+	refused := &protobuf.SC_40002{}
+	decodePacketMessage(t, client, 40002, refused)
+	client.Buffer.Reset()
+	if refused.GetResult() != worldResultFailed {
+		t.Fatalf("expected world battle outside the world to be refused, got %d", refused.GetResult())
+	}
+
+	world, err := orm.GetOrCreateWorld(commanderID)
+	if err != nil {
+		t.Fatalf("get world: %v", err)
+	}
+	world.Camp, world.MapID = 1, 1
+	if err := orm.SaveWorld(world); err != nil {
+		t.Fatalf("save world: %v", err)
+	}
+	if err := orm.SaveWorldMap(&orm.WorldMap{CommanderID: commanderID, MapID: 1}); err != nil {
+		t.Fatalf("save world map: %v", err)
+	}
+	if _, _, err := BeginStage(&begin, client); err != nil {
+		t.Fatalf("begin stage failed: %v", err)
+	}
+	started := &protobuf.SC_40002{}
+	decodePacketMessage(t, client, 40002, started)
+	client.Buffer.Reset()
+	if started.GetResult() != 0 {
+		t.Fatalf("expected world battle to start, got %d", started.GetResult())
+	}
+	finish := marshalPacketRequest(t, &protobuf.CS_40003{
+		System:         proto.Uint32(battleSystemWorld),
+		Data:           proto.Uint32(900001),
+		Key:            proto.Uint32(started.GetKey()),
+		Score:          proto.Uint32(rankScoreS),
+		TotalTime:      proto.Uint32(1),
+		BotPercentage:  proto.Uint32(0),
+		ExtraParam:     proto.Uint32(0),
+		AutoBefore:     proto.Uint32(0),
+		AutoSwitchTime: proto.Uint32(0),
+		AutoAfter:      proto.Uint32(0),
+	})
+	backdateBattleSession(t, commanderID)
+	if _, _, err := FinishStage(&finish, client); err != nil {
+		t.Fatalf("finish stage failed: %v", err)
+	}
+	client.Buffer.Reset()
+
+	maps, err := orm.ListWorldMaps(commanderID)
+	if err != nil {
+		t.Fatalf("list world maps: %v", err)
+	}
+	if len(maps) != 2 || !maps[0].IsCleared || maps[1].MapID != 2 {
+		t.Fatalf("unexpected world maps: %+v", maps)
+	}
+	if transport := worldActionResult(t, client, &protobuf.CS_33103{Act: proto.Uint32(worldActTransport), GroupId: proto.Uint32(0), ActArg_1: proto.Uint32(2)}); transport.GetResult() != worldResultOK {
+		t.Fatalf("expected unlocked map to be reachable, got %v", transport)
+	}
+
+	check := []byte{}
+	if _, _, err := WorldCheckInfo(&check, client); err != nil {
+		t.Fatalf("world check info failed: %v", err)
+	}
+	checked := &protobuf.SC_33001{}
+	decodePacketMessage(t, client, 33001, checked)
+	client.Buffer.Reset()
+	if checked.GetIsWorldOpen() != 1 || checked.GetWorld().GetMapId() != 2 || checked.GetCountInfo().GetTaskProgress() != 5 {
+		t.Fatalf("unexpected world check info: %v", checked)
+	}
+
+	reset := marshalPacketRequest(t, &protobuf.CS_11100{Cmd: proto.String("world"), Arg1: proto.String("reset")})
+	if _, _, err := SendCmd(&reset, client); err != nil {
+		t.Fatalf("send cmd failed: %v", err)
+	}
+	client.Buffer.Reset()
+	if maps, err := orm.ListWorldMaps(commanderID); err != nil || len(maps) != 0 {
+		t.Fatalf("expected world reset to clear maps, got %v (%v)", maps, err)
+	}
+}
@@ -48,9 +48,16 @@ func NewApp(cfg Config) *iris.Application {
 	routes.RegisterShop(app)
 	routes.RegisterNotices(app)
 	routes.RegisterExchangeCodes(app)
+	routes.RegisterGuilds(app)
+	routes.RegisterBuildBanners(app)
 	routes.RegisterDorm3d(app)
 	routes.RegisterJuustagram(app)
 	routes.RegisterActivities(app)
+	routes.RegisterArena(app)
+	routes.RegisterChat(app)
+	routes.RegisterPayments(app)
+	routes.RegisterDropTables(app)
+	routes.RegisterThemePreviews(app)
 
 	swaggerOnce.Do(func() {
 		swag.Register("doc", docs.SwaggerInfo)
@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/kataras/iris/v12"
 
+	"github.com/ggmolly/belfast/internal/answer"
 	"github.com/ggmolly/belfast/internal/api/response"
 	"github.com/ggmolly/belfast/internal/api/types"
 	"github.com/ggmolly/belfast/internal/db"
 	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/region"
 )
 
 const (
@@ -28,6 +31,10 @@ func RegisterActivityRoutes(party iris.Party, handler *ActivityHandler) {
 	party.Get("/allowlist", handler.GetAllowlist)
 	party.Put("/allowlist", handler.ReplaceAllowlist)
 	party.Patch("/allowlist", handler.UpdateAllowlist)
+	party.Get("/calendar", handler.Calendar)
+	party.Get("/overrides", handler.ListOverrides)
+	party.Post("/overrides", handler.CreateOverride)
+	party.Delete("/overrides/{id:uint}", handler.DeleteOverride)
 }
 
 // GetAllowlist godoc
@@ -155,7 +162,12 @@ func saveActivityAllowlist(ids []uint32) error {
 	if err != nil {
 		return err
 	}
-	return orm.UpsertConfigEntry(activityAllowlistCategory, activityAllowlistKey, payload)
+	if err := orm.UpsertConfigEntry(activityAllowlistCategory, activityAllowlistKey, payload); err != nil {
+		return err
+	}
+	reloadGameData(activityAllowlistCategory)
+	answer.RefreshActivitySchedule()
+	return nil
 }
 
 func validateActivityIDs(ids []uint32) ([]uint32, error) {
@@ -190,3 +202,229 @@ func writeActivityError(ctx iris.Context, err error, resource string) {
 	ctx.StatusCode(iris.StatusInternalServerError)
 	_ = ctx.JSON(response.Error("internal_error", fmt.Sprintf("failed to load %s", resource), nil))
 }
+
+// Calendar godoc
+// @Summary     Get activity calendar
+// @Description Lists the activities open at some point between from and to (RFC3339, defaults to 30 days around now).
+// @Tags        Activities
+// @Produce     json
+// @Param       from    query     string  false  "Start of the range (RFC3339)"
+// @Param       to      query     string  false  "End of the range (RFC3339)"
+// @Param       region  query     string  false  "Region (defaults to the server region)"
+// @Success     200  {object}  ActivityCalendarResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/activities/calendar [get]
+func (handler *ActivityHandler) Calendar(ctx iris.Context) {
+	now := time.Now().UTC()
+	from, err := parseCalendarTime(ctx.URLParamDefault("from", ""), now.AddDate(0, 0, -activityCalendarDays))
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid from", nil))
+		return
+	}
+	to, err := parseCalendarTime(ctx.URLParamDefault("to", ""), now.AddDate(0, 0, activityCalendarDays))
+	if err != nil || !to.After(from) {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid to", nil))
+		return
+	}
+	regionName := ctx.URLParamDefault("region", region.Current())
+	if err := region.Validate(regionName); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	windows, err := answer.ActivitySchedule(regionName)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load activity schedule", nil))
+		return
+	}
+	payload := types.ActivityCalendarResponse{
+		Region: regionName,
+		From:   from.Format(time.RFC3339),
+		To:     to.Format(time.RFC3339),
+		Events: []types.ActivityCalendarEntry{},
+	}
+	for _, window := range windows {
+		if !window.Overlaps(from, to) {
+			continue
+		}
+		payload.Events = append(payload.Events, types.ActivityCalendarEntry{
+			ActivityID: window.ActivityID,
+			Type:       window.Type,
+			StartsAt:   formatCalendarTime(window.Start),
+			EndsAt:     formatCalendarTime(window.End),
+			Source:     window.Source,
+			Open:       window.OpenAt(now),
+			Extended:   window.Extended,
+			ForceEnded: window.ForceEnded,
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ListOverrides godoc
+// @Summary     List activity schedule overrides
+// @Tags        Activities
+// @Produce     json
+// @Success     200  {object}  ActivityOverrideListResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/activities/overrides [get]
+func (handler *ActivityHandler) ListOverrides(ctx iris.Context) {
+	overrides, err := orm.ListActivityScheduleOverrides()
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load overrides", nil))
+		return
+	}
+	payload := types.ActivityOverrideListResponse{Overrides: make([]types.ActivityOverridePayload, 0, len(overrides))}
+	for _, override := range overrides {
+		payload.Overrides = append(payload.Overrides, activityOverridePayload(override))
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// CreateOverride godoc
+// @Summary     Create activity schedule override
+// @Description Extends, previews or force-ends an activity. Online players are notified when the activity opens or closes.
+// @Tags        Activities
+// @Accept      json
+// @Produce     json
+// @Param       payload  body      types.ActivityOverrideRequest  true  "Override"
+// @Success     200  {object}  ActivityOverrideResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/activities/overrides [post]
+func (handler *ActivityHandler) CreateOverride(ctx iris.Context) {
+	var req types.ActivityOverrideRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	override, err := parseActivityOverride(req)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CreateActivityScheduleOverride(override); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to save override", nil))
+		return
+	}
+	answer.RefreshActivitySchedule()
+	_ = ctx.JSON(response.Success(activityOverridePayload(*override)))
+}
+
+// DeleteOverride godoc
+// @Summary     Delete activity schedule override
+// @Tags        Activities
+// @Produce     json
+// @Param       id   path      int  true  "Override ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/activities/overrides/{id} [delete]
+func (handler *ActivityHandler) DeleteOverride(ctx iris.Context) {
+	overrideID, err := parsePathUint64(ctx.Params().Get("id"), "override id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteActivityScheduleOverride(overrideID); err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "override not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to delete override", nil))
+		return
+	}
+	answer.RefreshActivitySchedule()
+	_ = ctx.JSON(response.Success(nil))
+}
+
+const activityCalendarDays = 30
+
+func parseCalendarTime(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func formatCalendarTime(value time.Time) *string {
+	if value.IsZero() {
+		return nil
+	}
+	formatted := value.UTC().Format(time.RFC3339)
+	return &formatted
+}
+
+func parseActivityOverride(req types.ActivityOverrideRequest) (*orm.ActivityScheduleOverride, error) {
+	if req.ActivityID == 0 {
+		return nil, fmt.Errorf("activity_id is required")
+	}
+	if req.Region != "" {
+		if err := region.Validate(req.Region); err != nil {
+			return nil, err
+		}
+	}
+	startsAt, err := parseOptionalRFC3339(req.StartsAt, "starts_at")
+	if err != nil {
+		return nil, err
+	}
+	endsAt, err := parseOptionalRFC3339(req.EndsAt, "ends_at")
+	if err != nil {
+		return nil, err
+	}
+	override := orm.ActivityScheduleOverride{ActivityID: req.ActivityID, Region: req.Region, Action: req.Action, StartsAt: startsAt, EndsAt: endsAt, Note: req.Note}
+	switch req.Action {
+	case orm.ActivityOverrideExtend:
+		if override.EndsAt == nil {
+			return nil, fmt.Errorf("ends_at is required to extend an activity")
+		}
+	case orm.ActivityOverridePreview, orm.ActivityOverrideForceEnd:
+	default:
+		return nil, fmt.Errorf("action must be one of extend, preview, force_end")
+	}
+	if override.StartsAt != nil && override.EndsAt != nil && !override.EndsAt.After(*override.StartsAt) {
+		return nil, fmt.Errorf("ends_at must be after starts_at")
+	}
+	return &override, nil
+}
+
+func parseOptionalRFC3339(value string, name string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	return &parsed, nil
+}
+
+func activityOverridePayload(override orm.ActivityScheduleOverride) types.ActivityOverridePayload {
+	payload := types.ActivityOverridePayload{
+		ID:         override.ID,
+		ActivityID: override.ActivityID,
+		Region:     override.Region,
+		Action:     override.Action,
+		Note:       override.Note,
+		CreatedAt:  override.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if override.StartsAt != nil {
+		payload.StartsAt = formatCalendarTime(*override.StartsAt)
+	}
+	if override.EndsAt != nil {
+		payload.EndsAt = formatCalendarTime(*override.EndsAt)
+	}
+	return payload
+}
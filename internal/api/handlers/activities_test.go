@@ -130,3 +130,91 @@ func TestActivityAllowlistEndpoints(t *testing.T) {
 
 	clearActivityAllowlist(t)
 }
+
+func TestActivityCalendarAndOverrides(t *testing.T) {
+	app := newActivityHandlerTestApp(t)
+	clearConfigEntries(t)
+	execTestSQL(t, "DELETE FROM activity_schedule_overrides")
+	entry := orm.ConfigEntry{Category: "ShareCfg/activity_template.json", Key: "10", Data: json.RawMessage(`{"id":10,"type":5,"time":["timer",[[2030,1,1],[0,0,0]],[[2030,1,8],[0,0,0]]]}`)}
+	if err := orm.CreateConfigEntryRecord(&entry); err != nil {
+		t.Fatalf("seed activity template: %v", err)
+	}
+	seedActivityTemplate(t, 11)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/activities/overrides", strings.NewReader(`{"activity_id":10,"action":"extend"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	if response.Code != http.StatusBadRequest {
+		t.Fatalf("expected extend without ends_at to be refused, got %d", response.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/api/v1/activities/overrides", strings.NewReader(`{"activity_id":10,"action":"extend","ends_at":"2030-01-15T00:00:00Z"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+	var created struct {
+		Data struct {
+			ID uint64 `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&created); err != nil || created.Data.ID == 0 {
+		t.Fatalf("decode override: %v", err)
+	}
+	request = httptest.NewRequest(http.MethodPost, "/api/v1/activities/overrides", strings.NewReader(`{"activity_id":11,"action":"preview","region":"JP","starts_at":"2030-01-02T00:00:00Z","ends_at":"2030-01-03T00:00:00Z"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v1/activities/calendar?from=2030-01-01T00:00:00Z&to=2030-02-01T00:00:00Z&region=EN", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+	var calendar struct {
+		Data struct {
+			Events []struct {
+				ActivityID uint32 `json:"activity_id"`
+				EndsAt     string `json:"ends_at"`
+				Extended   bool   `json:"extended"`
+			} `json:"events"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&calendar); err != nil {
+		t.Fatalf("decode calendar: %v", err)
+	}
+	if len(calendar.Data.Events) != 1 || calendar.Data.Events[0].ActivityID != 10 || !calendar.Data.Events[0].Extended || calendar.Data.Events[0].EndsAt != "2030-01-15T00:00:00Z" {
+		t.Fatalf("unexpected EN calendar %+v", calendar.Data.Events)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v1/activities/calendar?from=2030-01-01T00:00:00Z&to=2030-02-01T00:00:00Z&region=JP", nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	calendar.Data.Events = nil
+	if err := json.NewDecoder(response.Body).Decode(&calendar); err != nil {
+		t.Fatalf("decode calendar: %v", err)
+	}
+	if len(calendar.Data.Events) != 2 || calendar.Data.Events[1].ActivityID != 11 {
+		t.Fatalf("expected the JP preview in the JP calendar, got %+v", calendar.Data.Events)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/v1/activities/overrides/"+strconv.FormatUint(created.Data.ID, 10), nil)
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+	response = httptest.NewRecorder()
+	app.ServeHTTP(response, httptest.NewRequest(http.MethodDelete, "/api/v1/activities/overrides/"+strconv.FormatUint(created.Data.ID, 10), nil))
+	if response.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", response.Code)
+	}
+	execTestSQL(t, "DELETE FROM activity_schedule_overrides")
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/answer"
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerActivityProgress godoc
+// @Summary     List player event progress
+// @Description PT, claimed milestones, login sign-in days, event shop purchases and claimed tasks of every activity the player took part in.
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerActivityProgressResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/activities/progress [get]
+func (handler *PlayerHandler) PlayerActivityProgress(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	progressList, err := orm.ListActivityEventProgress(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load activity progress", nil))
+		return
+	}
+	payload := types.PlayerActivityProgressResponse{Activities: make([]types.PlayerActivityProgress, 0, len(progressList))}
+	for _, progress := range progressList {
+		payload.Activities = append(payload.Activities, playerActivityProgressPayload(progress))
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ResetPlayerActivityProgress godoc
+// @Summary     Reset player event progress
+// @Description Clears the progress of the player in an activity. The tasks of a task list activity are reset too.
+// @Tags        Players
+// @Produce     json
+// @Param       id           path  int  true  "Player ID"
+// @Param       activity_id  path  int  true  "Activity ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/activities/progress/{activity_id} [delete]
+func (handler *PlayerHandler) ResetPlayerActivityProgress(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	activityID, err := parsePathUint64(ctx.Params().Get("activity_id"), "activity_id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := answer.ResetActivityEventProgress(commanderID, uint32(activityID)); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to reset activity progress", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+func playerActivityProgressPayload(progress orm.ActivityEventProgress) types.PlayerActivityProgress {
+	payload := types.PlayerActivityProgress{
+		ActivityID:     progress.ActivityID,
+		Pt:             progress.Pt,
+		ClaimedTargets: orm.ToUint32List(progress.ClaimedTargets),
+		SignDays:       progress.SignDays,
+		ShopPurchases:  make([]types.PlayerActivityShopPurchase, 0, len(progress.ShopPurchases)),
+		ClaimedTasks:   orm.ToUint32List(progress.ClaimedTasks),
+		UpdatedAt:      progress.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if progress.LastSignAt != 0 {
+		lastSignAt := time.Unix(int64(progress.LastSignAt), 0).UTC().Format(time.RFC3339)
+		payload.LastSignAt = &lastSignAt
+	}
+	for goodID, count := range progress.ShopPurchases {
+		payload.ShopPurchases = append(payload.ShopPurchases, types.PlayerActivityShopPurchase{GoodID: goodID, Count: count})
+	}
+	sort.Slice(payload.ShopPurchases, func(i, j int) bool {
+		return payload.ShopPurchases[i].GoodID < payload.ShopPurchases[j].GoodID
+	})
+	return payload
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type playerActivityProgressResponse struct {
+	OK   bool                                 `json:"ok"`
+	Data types.PlayerActivityProgressResponse `json:"data"`
+}
+
+func TestPlayerActivityProgressEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9374))
+	seedCommander(t, 9374, "Event Tester")
+	progress := orm.ActivityEventProgress{CommanderID: 9374, ActivityID: 70, Pt: 150, ClaimedTargets: orm.Int64List{100}, ShopPurchases: map[uint32]uint32{900: 2}}
+	if err := orm.SaveActivityEventProgress(&progress); err != nil {
+		t.Fatalf("seed activity progress: %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/players/9374/activities/progress", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var payload playerActivityProgressResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data.Activities) != 1 {
+		t.Fatalf("unexpected activity progress: %+v", payload.Data)
+	}
+	activity := payload.Data.Activities[0]
+	if activity.ActivityID != 70 || activity.Pt != 150 || len(activity.ClaimedTargets) != 1 || len(activity.ShopPurchases) != 1 || activity.ShopPurchases[0].Count != 2 {
+		t.Fatalf("unexpected activity progress: %+v", activity)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/v1/players/9374/activities/progress/70", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	list, err := orm.ListActivityEventProgress(9374)
+	if err != nil || len(list) != 0 {
+		t.Fatalf("expected progress to be reset, got %v (%v)", list, err)
+	}
+}
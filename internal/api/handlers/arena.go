@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const arenaLeaderboardDefaultLimit = 50
+
+type ArenaHandler struct{}
+
+func NewArenaHandler() *ArenaHandler {
+	return &ArenaHandler{}
+}
+
+func RegisterArenaRoutes(party iris.Party, handler *ArenaHandler) {
+	party.Get("/season", handler.Season)
+	party.Post("/season/settle", handler.SettleSeason)
+	party.Get("/leaderboard", handler.Leaderboard)
+}
+
+// Season godoc
+// @Summary     Get exercise season
+// @Description Returns the exercise season in progress, opening the first one if needed.
+// @Tags        Arena
+// @Produce     json
+// @Success     200  {object}  ArenaSeasonResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/arena/season [get]
+func (handler *ArenaHandler) Season(ctx iris.Context) {
+	season, err := arena.OpenSeason(time.Now())
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena season", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(types.ArenaSeasonResponse{Season: arenaSeasonPayload(season)}))
+}
+
+// SettleSeason godoc
+// @Summary     Settle exercise season
+// @Description Ends the season in progress now: merit is paid by final score, scores are reset and a new season starts.
+// @Tags        Arena
+// @Produce     json
+// @Success     200  {object}  ArenaSettlementResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/arena/season/settle [post]
+func (handler *ArenaHandler) SettleSeason(ctx iris.Context) {
+	settlement, err := arena.SettleSeason(time.Now())
+	if err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "no arena season in progress", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to settle arena season", nil))
+		return
+	}
+	payload := types.ArenaSettlementResponse{
+		SeasonID:     settlement.SeasonID,
+		NextSeasonID: settlement.NextSeasonID,
+		Payouts:      make([]types.ArenaPayout, 0, len(settlement.Payouts)),
+	}
+	for _, payout := range settlement.Payouts {
+		payload.Payouts = append(payload.Payouts, types.ArenaPayout{
+			CommanderID: payout.CommanderID,
+			Rank:        payout.Rank,
+			Score:       payout.Score,
+			Merit:       payout.Merit,
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// Leaderboard godoc
+// @Summary     Get exercise leaderboard
+// @Tags        Arena
+// @Produce     json
+// @Param       offset  query  int  false  "Pagination offset"
+// @Param       limit   query  int  false  "Pagination limit"
+// @Success     200  {object}  ArenaLeaderboardResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/arena/leaderboard [get]
+func (handler *ArenaHandler) Leaderboard(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = arenaLeaderboardDefaultLimit
+	}
+	season, err := arena.OpenSeason(time.Now())
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena season", nil))
+		return
+	}
+	entries, err := orm.ListArenaLeaderboard(season.SeasonID, pagination.Offset, pagination.Limit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena leaderboard", nil))
+		return
+	}
+	total, err := orm.CountArenaStates(season.SeasonID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena leaderboard", nil))
+		return
+	}
+	payload := types.ArenaLeaderboardResponse{
+		SeasonID: season.SeasonID,
+		Entries:  make([]types.ArenaLeaderboardEntry, 0, len(entries)),
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	for _, entry := range entries {
+		payload.Entries = append(payload.Entries, types.ArenaLeaderboardEntry{
+			Rank:        entry.Rank,
+			CommanderID: entry.CommanderID,
+			Name:        entry.Name,
+			Level:       entry.Level,
+			Score:       entry.Score,
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// PlayerArena godoc
+// @Summary     Get player exercise state
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerArenaResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/arena [get]
+func (handler *PlayerHandler) PlayerArena(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	state, err := orm.GetArenaState(commanderID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "player never opened the exercise", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena state", nil))
+		return
+	}
+	rank, err := arena.Rank(state)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load arena state", nil))
+		return
+	}
+	payload := types.PlayerArenaState{
+		SeasonID:            state.SeasonID,
+		Score:               state.Score,
+		Rank:                rank,
+		FightCount:          state.FightCount,
+		FightCountResetTime: time.Unix(int64(state.FightCountResetTime), 0).UTC().Format(time.RFC3339),
+		FlashTargetCount:    state.FlashTargetCount,
+		RivalIDs:            orm.ToUint32List(state.RivalIDs),
+		Wins:                state.Wins,
+		Losses:              state.Losses,
+		UpdatedAt:           state.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+func arenaSeasonPayload(season *orm.ArenaSeason) types.ArenaSeason {
+	return types.ArenaSeason{
+		SeasonID: season.SeasonID,
+		StartsAt: season.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:   season.EndsAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type arenaLeaderboardResponse struct {
+	OK   bool                           `json:"ok"`
+	Data types.ArenaLeaderboardResponse `json:"data"`
+}
+
+type arenaSettlementResponse struct {
+	OK   bool                          `json:"ok"`
+	Data types.ArenaSettlementResponse `json:"data"`
+}
+
+type playerArenaResponse struct {
+	OK   bool                   `json:"ok"`
+	Data types.PlayerArenaState `json:"data"`
+}
+
+func newArenaHandlerTestApp(t *testing.T) *iris.Application {
+	initPlayerHandlerTestDB(t)
+	app := iris.New()
+	RegisterArenaRoutes(app.Party("/api/v1/arena"), NewArenaHandler())
+	RegisterPlayerRoutes(app.Party("/api/v1/players"), NewPlayerHandler())
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func TestArenaEndpoints(t *testing.T) {
+	app := newArenaHandlerTestApp(t)
+	execTestSQL(t, "INSERT INTO resources (id, item_id, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING", int64(arena.MeritResourceID), int64(0), "Merit")
+	execTestSQL(t, "DELETE FROM arena_states")
+	execTestSQL(t, "DELETE FROM arena_seasons")
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id IN ($1, $2)", int64(9380), int64(9381))
+	seedCommander(t, 9380, "Arena Top")
+	seedCommander(t, 9381, "Arena Second")
+	for commanderID, score := range map[uint32]uint32{9380: 300, 9381: 120} {
+		state, _, err := arena.LoadState(commanderID, time.Now())
+		if err != nil {
+			t.Fatalf("load arena state: %v", err)
+		}
+		state.Score = score
+		if err := orm.SaveArenaState(state); err != nil {
+			t.Fatalf("save arena state: %v", err)
+		}
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/arena/leaderboard?limit=10", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var leaderboard arenaLeaderboardResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &leaderboard); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if leaderboard.Data.Meta.Total != 2 || len(leaderboard.Data.Entries) != 2 || leaderboard.Data.Entries[0].CommanderID != 9380 || leaderboard.Data.Entries[1].Rank != 2 {
+		t.Fatalf("unexpected leaderboard: %+v", leaderboard.Data)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v1/players/9381/arena", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var player playerArenaResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &player); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if player.Data.Score != 120 || player.Data.Rank != 2 || player.Data.FightCount != arena.MaxFightCount {
+		t.Fatalf("unexpected player arena state: %+v", player.Data)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/api/v1/arena/season/settle", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var settlement arenaSettlementResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &settlement); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(settlement.Data.Payouts) != 2 || settlement.Data.Payouts[0].Merit != arena.SeasonMerit(300) || settlement.Data.NextSeasonID == settlement.Data.SeasonID {
+		t.Fatalf("unexpected settlement: %+v", settlement.Data)
+	}
+}
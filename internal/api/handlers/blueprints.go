@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerBlueprints godoc
+// @Summary     Get player research projects and blueprint progress
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerBlueprintsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/blueprints [get]
+func (handler *PlayerHandler) PlayerBlueprints(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	payload, err := loadPlayerBlueprints(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load blueprints", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// UpdatePlayerBlueprint godoc
+// @Summary     Grant or overwrite the progress of a player blueprint
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id            path  int  true  "Player ID"
+// @Param       blueprint_id  path  int  true  "Blueprint ID"
+// @Param       payload       body  types.PlayerBlueprintUpdateRequest  true  "Blueprint progress"
+// @Success     200  {object}  PlayerBlueprintsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/blueprints/{blueprint_id} [put]
+func (handler *PlayerHandler) UpdatePlayerBlueprint(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	blueprintID, err := parsePathUint32(ctx.Params().Get("blueprint_id"), "blueprint id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerBlueprintUpdateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.ShipID != 0 {
+		if _, err := orm.GetOwnedShipByOwnerAndID(commanderID, req.ShipID); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				ctx.StatusCode(iris.StatusNotFound)
+				_ = ctx.JSON(response.Error("not_found", "ship not found", nil))
+				return
+			}
+			ctx.StatusCode(iris.StatusInternalServerError)
+			_ = ctx.JSON(response.Error("internal_error", "failed to load ship", nil))
+			return
+		}
+	}
+	blueprint := orm.CommanderBlueprint{
+		CommanderID:    commanderID,
+		BlueprintID:    blueprintID,
+		ShipID:         req.ShipID,
+		StartTime:      req.StartTime,
+		Duration:       req.Duration,
+		Level:          req.Level,
+		Exp:            req.Exp,
+		SubmittedTasks: orm.ToInt64List(req.SubmittedTasks),
+	}
+	if err := orm.SaveCommanderBlueprint(&blueprint); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to save blueprint", nil))
+		return
+	}
+	payload, err := loadPlayerBlueprints(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load blueprints", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+func loadPlayerBlueprints(commanderID uint32) (types.PlayerBlueprintsResponse, error) {
+	state, err := orm.GetOrCreateTechState(commanderID)
+	if err != nil {
+		return types.PlayerBlueprintsResponse{}, err
+	}
+	blueprints, err := orm.ListCommanderBlueprints(commanderID)
+	if err != nil {
+		return types.PlayerBlueprintsResponse{}, err
+	}
+	projects, err := orm.ListTechProjects(commanderID)
+	if err != nil {
+		return types.PlayerBlueprintsResponse{}, err
+	}
+	payload := types.PlayerBlueprintsResponse{
+		Blueprints:     make([]types.PlayerBlueprintEntry, 0, len(blueprints)),
+		Projects:       make([]types.PlayerTechProjectEntry, 0, len(projects)),
+		RefreshFlag:    state.RefreshFlag,
+		ColdTime:       state.ColdTime,
+		CatchupVersion: state.CatchupVersion,
+		CatchupTarget:  state.CatchupTarget,
+	}
+	for _, blueprint := range blueprints {
+		payload.Blueprints = append(payload.Blueprints, types.PlayerBlueprintEntry{
+			BlueprintID:    blueprint.BlueprintID,
+			ShipID:         blueprint.ShipID,
+			StartTime:      blueprint.StartTime,
+			Duration:       blueprint.Duration,
+			Level:          blueprint.Level,
+			Exp:            blueprint.Exp,
+			SubmittedTasks: orm.ToUint32List(blueprint.SubmittedTasks),
+		})
+	}
+	for _, project := range projects {
+		payload.Projects = append(payload.Projects, types.PlayerTechProjectEntry{
+			RefreshID:  project.RefreshID,
+			TechID:     project.TechID,
+			FinishTime: project.FinishTime,
+		})
+	}
+	return payload, nil
+}
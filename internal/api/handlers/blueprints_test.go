@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type playerBlueprintsResponse struct {
+	OK   bool                           `json:"ok"`
+	Data types.PlayerBlueprintsResponse `json:"data"`
+}
+
+func TestPlayerBlueprintsEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9370))
+	seedCommander(t, 9370, "Blueprint Tester")
+
+	updateRequest := httptest.NewRequest(http.MethodPut, "/api/v1/players/9370/blueprints/10001", strings.NewReader(`{"start_time":100,"duration":60,"level":2,"exp":5,"submitted_tasks":[1]}`))
+	updateRequest.Header.Set("Content-Type", "application/json")
+	updateResponse := httptest.NewRecorder()
+	app.ServeHTTP(updateResponse, updateRequest)
+	if updateResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", updateResponse.Code)
+	}
+
+	missingShipRequest := httptest.NewRequest(http.MethodPut, "/api/v1/players/9370/blueprints/10001", strings.NewReader(`{"ship_id":999999}`))
+	missingShipRequest.Header.Set("Content-Type", "application/json")
+	missingShipResponse := httptest.NewRecorder()
+	app.ServeHTTP(missingShipResponse, missingShipRequest)
+	if missingShipResponse.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingShipResponse.Code)
+	}
+
+	getRequest := httptest.NewRequest(http.MethodGet, "/api/v1/players/9370/blueprints", nil)
+	getResponse := httptest.NewRecorder()
+	app.ServeHTTP(getResponse, getRequest)
+	if getResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", getResponse.Code)
+	}
+	var payload playerBlueprintsResponse
+	if err := json.Unmarshal(getResponse.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data.Blueprints) != 1 {
+		t.Fatalf("expected one blueprint, got %+v", payload.Data)
+	}
+	blueprint := payload.Data.Blueprints[0]
+	if blueprint.BlueprintID != 10001 || blueprint.Level != 2 || blueprint.Exp != 5 || len(blueprint.SubmittedTasks) != 1 {
+		t.Fatalf("unexpected blueprint: %+v", blueprint)
+	}
+}
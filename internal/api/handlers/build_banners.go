@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type BuildBannerHandler struct {
+	Validate *validator.Validate
+}
+
+func NewBuildBannerHandler() *BuildBannerHandler {
+	return &BuildBannerHandler{Validate: validator.New(validator.WithRequiredStructEnabled())}
+}
+
+func RegisterBuildBannerRoutes(party iris.Party, handler *BuildBannerHandler) {
+	party.Get("", handler.ListBuildBanners)
+	party.Post("", handler.CreateBuildBanner)
+	party.Get("/{id:uint}", handler.BuildBannerDetail)
+	party.Put("/{id:uint}", handler.UpdateBuildBanner)
+	party.Delete("/{id:uint}", handler.DeleteBuildBanner)
+	party.Get("/{id:uint}/rolls", handler.ListBuildBannerRolls)
+	party.Get("/{id:uint}/stats", handler.BuildBannerStats)
+}
+
+// ListBuildBanners godoc
+// @Summary     List build banners
+// @Tags        Build Banners
+// @Produce     json
+// @Param       offset  query  int  false  "Pagination offset"
+// @Param       limit   query  int  false  "Pagination limit"
+// @Success     200  {object}  BuildBannerListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners [get]
+func (handler *BuildBannerHandler) ListBuildBanners(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	banners, total, err := orm.ListBuildBanners(pagination.Offset, pagination.Limit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list build banners", nil))
+		return
+	}
+	results := make([]types.BuildBannerSummary, 0, len(banners))
+	for i := range banners {
+		results = append(results, buildBannerSummary(&banners[i]))
+	}
+	payload := types.BuildBannerListResponse{
+		Banners: results,
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// BuildBannerDetail godoc
+// @Summary     Get build banner
+// @Tags        Build Banners
+// @Produce     json
+// @Param       id   path  int  true  "Build banner ID"
+// @Success     200  {object}  BuildBannerSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners/{id} [get]
+func (handler *BuildBannerHandler) BuildBannerDetail(ctx iris.Context) {
+	bannerID, err := parsePathUint32(ctx.Params().Get("id"), "build banner id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	banner, err := orm.GetBuildBanner(bannerID)
+	if err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(buildBannerSummary(banner)))
+}
+
+// CreateBuildBanner godoc
+// @Summary     Create build banner
+// @Tags        Build Banners
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.BuildBannerRequest  true  "Build banner"
+// @Success     200  {object}  BuildBannerSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners [post]
+func (handler *BuildBannerHandler) CreateBuildBanner(ctx iris.Context) {
+	banner := orm.BuildBanner{}
+	if !handler.readBuildBannerRequest(ctx, &banner) {
+		return
+	}
+	if err := orm.CreateBuildBanner(&banner); err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	created, err := orm.GetBuildBanner(banner.ID)
+	if err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(buildBannerSummary(created)))
+}
+
+// UpdateBuildBanner godoc
+// @Summary     Replace build banner
+// @Tags        Build Banners
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int                       true  "Build banner ID"
+// @Param       payload  body  types.BuildBannerRequest  true  "Build banner"
+// @Success     200  {object}  BuildBannerSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners/{id} [put]
+func (handler *BuildBannerHandler) UpdateBuildBanner(ctx iris.Context) {
+	bannerID, err := parsePathUint32(ctx.Params().Get("id"), "build banner id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	banner := orm.BuildBanner{ID: bannerID}
+	if !handler.readBuildBannerRequest(ctx, &banner) {
+		return
+	}
+	if err := orm.UpdateBuildBanner(&banner); err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	updated, err := orm.GetBuildBanner(bannerID)
+	if err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(buildBannerSummary(updated)))
+}
+
+// DeleteBuildBanner godoc
+// @Summary     Delete build banner
+// @Tags        Build Banners
+// @Produce     json
+// @Param       id   path  int  true  "Build banner ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners/{id} [delete]
+func (handler *BuildBannerHandler) DeleteBuildBanner(ctx iris.Context) {
+	bannerID, err := parsePathUint32(ctx.Params().Get("id"), "build banner id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteBuildBanner(bannerID); err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// ListBuildBannerRolls godoc
+// @Summary     List build banner rolls
+// @Tags        Build Banners
+// @Produce     json
+// @Param       id      path   int  true   "Build banner ID"
+// @Param       offset  query  int  false  "Pagination offset"
+// @Param       limit   query  int  false  "Pagination limit"
+// @Success     200  {object}  BuildRollLogListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners/{id}/rolls [get]
+func (handler *BuildBannerHandler) ListBuildBannerRolls(ctx iris.Context) {
+	bannerID, err := parsePathUint32(ctx.Params().Get("id"), "build banner id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if _, err := orm.GetBuildBanner(bannerID); err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	logs, total, err := orm.ListBuildRollLogs(bannerID, pagination.Offset, pagination.Limit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list build rolls", nil))
+		return
+	}
+	results := make([]types.BuildRollLogEntry, 0, len(logs))
+	for _, entry := range logs {
+		results = append(results, types.BuildRollLogEntry{
+			ID:          entry.ID,
+			CommanderID: entry.CommanderID,
+			PoolID:      entry.PoolID,
+			ShipID:      entry.ShipID,
+			RarityID:    entry.RarityID,
+			RateUp:      entry.RateUp,
+			Pity:        entry.Pity,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	payload := types.BuildRollLogListResponse{
+		Rolls: results,
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// BuildBannerStats godoc
+// @Summary     Get observed build banner rates
+// @Tags        Build Banners
+// @Produce     json
+// @Param       id   path  int  true  "Build banner ID"
+// @Success     200  {object}  BuildBannerStatsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/build-banners/{id}/stats [get]
+func (handler *BuildBannerHandler) BuildBannerStats(ctx iris.Context) {
+	bannerID, err := parsePathUint32(ctx.Params().Get("id"), "build banner id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if _, err := orm.GetBuildBanner(bannerID); err != nil {
+		writeBuildBannerError(ctx, err)
+		return
+	}
+	stats, err := orm.GetBuildRollStats(bannerID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load build roll stats", nil))
+		return
+	}
+	rate := func(count uint32) float64 {
+		if stats.Total == 0 {
+			return 0
+		}
+		return float64(count) / float64(stats.Total)
+	}
+	payload := types.BuildBannerStatsResponse{
+		Total:    stats.Total,
+		Pity:     stats.Pity,
+		Rarities: make([]types.BuildRollRarityCount, 0, len(stats.Rarities)),
+		RateUps:  make([]types.BuildRollRateUpCount, 0, len(stats.RateUps)),
+	}
+	for rarityID, count := range stats.Rarities {
+		payload.Rarities = append(payload.Rarities, types.BuildRollRarityCount{RarityID: rarityID, Count: count, Rate: rate(count)})
+	}
+	for shipID, count := range stats.RateUps {
+		payload.RateUps = append(payload.RateUps, types.BuildRollRateUpCount{ShipID: shipID, Count: count, Rate: rate(count)})
+	}
+	sort.Slice(payload.Rarities, func(i, j int) bool { return payload.Rarities[i].RarityID < payload.Rarities[j].RarityID })
+	sort.Slice(payload.RateUps, func(i, j int) bool { return payload.RateUps[i].ShipID < payload.RateUps[j].ShipID })
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// readBuildBannerRequest fills banner from the request body, writing the
+// error response and returning false when it is invalid.
+func (handler *BuildBannerHandler) readBuildBannerRequest(ctx iris.Context, banner *orm.BuildBanner) bool {
+	var req types.BuildBannerRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return false
+	}
+	if err := validateBuildBannerRequest(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return false
+	}
+	weight := func(value *uint32, fallback uint32) uint32 {
+		if value == nil {
+			return fallback
+		}
+		return *value
+	}
+	banner.PoolID = req.PoolID
+	banner.Name = req.Name
+	banner.Region = req.Region
+	banner.CommonWeight = weight(req.CommonWeight, 30)
+	banner.RareWeight = weight(req.RareWeight, 51)
+	banner.EliteWeight = weight(req.EliteWeight, 12)
+	banner.SuperRareWeight = weight(req.SuperRareWeight, 7)
+	banner.UltraRareWeight = weight(req.UltraRareWeight, 0)
+	banner.PityThreshold = req.PityThreshold
+	banner.StartsAt = req.StartsAt
+	banner.EndsAt = req.EndsAt
+	banner.Enabled = req.Enabled == nil || *req.Enabled
+	banner.RateUps = make([]orm.BuildBannerRateUp, 0, len(req.RateUps))
+	for _, rateUp := range req.RateUps {
+		banner.RateUps = append(banner.RateUps, orm.BuildBannerRateUp{ShipID: rateUp.ShipID, Rate: rateUp.Rate})
+	}
+	return true
+}
+
+func validateBuildBannerRequest(req *types.BuildBannerRequest) error {
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	seen := make(map[uint32]struct{}, len(req.RateUps))
+	total := uint32(0)
+	for _, rateUp := range req.RateUps {
+		if _, ok := seen[rateUp.ShipID]; ok {
+			return fmt.Errorf("duplicate rate-up ship %d", rateUp.ShipID)
+		}
+		seen[rateUp.ShipID] = struct{}{}
+		total += rateUp.Rate
+	}
+	if total > orm.BuildBannerRateScale {
+		return fmt.Errorf("rate-up rates must not exceed %d", orm.BuildBannerRateScale)
+	}
+	return nil
+}
+
+func buildBannerSummary(banner *orm.BuildBanner) types.BuildBannerSummary {
+	rateUps := make([]types.BuildBannerRateUp, 0, len(banner.RateUps))
+	for _, rateUp := range banner.RateUps {
+		rateUps = append(rateUps, types.BuildBannerRateUp{ShipID: rateUp.ShipID, Rate: rateUp.Rate})
+	}
+	return types.BuildBannerSummary{
+		ID:              banner.ID,
+		PoolID:          banner.PoolID,
+		Name:            banner.Name,
+		Region:          banner.Region,
+		CommonWeight:    banner.CommonWeight,
+		RareWeight:      banner.RareWeight,
+		EliteWeight:     banner.EliteWeight,
+		SuperRareWeight: banner.SuperRareWeight,
+		UltraRareWeight: banner.UltraRareWeight,
+		PityThreshold:   banner.PityThreshold,
+		StartsAt:        banner.StartsAt,
+		EndsAt:          banner.EndsAt,
+		Enabled:         banner.Enabled,
+		RateUps:         rateUps,
+		CreatedAt:       banner.CreatedAt,
+		UpdatedAt:       banner.UpdatedAt,
+	}
+}
+
+func writeBuildBannerError(ctx iris.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "build banner not found", nil))
+	case errors.Is(err, orm.ErrBuildBannerUnknownShip), errors.Is(err, orm.ErrBuildBannerMissingRarity):
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+	default:
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to process build banner", nil))
+	}
+}
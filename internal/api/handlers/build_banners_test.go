@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type buildBannerSummaryResponse struct {
+	OK   bool                     `json:"ok"`
+	Data types.BuildBannerSummary `json:"data"`
+}
+
+type buildBannerStatsResponse struct {
+	OK   bool                           `json:"ok"`
+	Data types.BuildBannerStatsResponse `json:"data"`
+}
+
+func newBuildBannerTestApp(t *testing.T) *iris.Application {
+	initPlayerHandlerTestDB(t)
+	app := iris.New()
+	RegisterBuildBannerRoutes(app.Party("/api/v1/build-banners"), NewBuildBannerHandler())
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func TestBuildBannerEndpoints(t *testing.T) {
+	app := newBuildBannerTestApp(t)
+	execTestSQL(t, "DELETE FROM build_banners WHERE name = $1", "API Banner")
+	execTestSQL(t, "DELETE FROM ships WHERE template_id = $1", int64(9960))
+	execTestSQL(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time) VALUES ($1, 'Limited', 'Limited', 6, 1, 1, 1, 60)", int64(9960))
+	// pool 9970 has a ship of every standard rarity, but no Ultra Rare yet
+	execTestSQL(t, "DELETE FROM ships WHERE pool_id = $1", int64(9970))
+	for rarity := int64(2); rarity <= 5; rarity++ {
+		execTestSQL(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time, pool_id) VALUES ($1, 'Pool', 'Pool', $2, 1, 1, 1, 60, $3)", 9970+rarity, rarity, int64(9970))
+	}
+
+	for _, body := range []string{
+		`{"pool_id":9970,"name":"API Banner","common_weight":4294967295,"rare_weight":1}`,
+		`{"pool_id":9970,"name":"API Banner","ultra_rare_weight":2}`,
+	} {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/build-banners", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		app.ServeHTTP(response, request)
+		if response.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %s, got %d", body, response.Code)
+		}
+	}
+
+	invalidRequest := httptest.NewRequest(http.MethodPost, "/api/v1/build-banners", strings.NewReader(`{"pool_id":9970,"name":"API Banner","rate_ups":[{"ship_id":9960,"rate":6000},{"ship_id":9961,"rate":6000}]}`))
+	invalidRequest.Header.Set("Content-Type", "application/json")
+	invalidResponse := httptest.NewRecorder()
+	app.ServeHTTP(invalidResponse, invalidRequest)
+	if invalidResponse.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", invalidResponse.Code)
+	}
+
+	createRequest := httptest.NewRequest(http.MethodPost, "/api/v1/build-banners", strings.NewReader(`{"pool_id":9970,"name":"API Banner","region":"JP","pity_threshold":200,"rate_ups":[{"ship_id":9960,"rate":120}]}`))
+	createRequest.Header.Set("Content-Type", "application/json")
+	createResponse := httptest.NewRecorder()
+	app.ServeHTTP(createResponse, createRequest)
+	if createResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", createResponse.Code)
+	}
+	var created buildBannerSummaryResponse
+	if err := json.NewDecoder(createResponse.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if created.Data.ID == 0 || created.Data.RareWeight != 51 || !created.Data.Enabled || len(created.Data.RateUps) != 1 || created.Data.Region == nil || *created.Data.Region != "JP" {
+		t.Fatalf("unexpected created banner: %+v", created.Data)
+	}
+
+	bannerPath := fmt.Sprintf("/api/v1/build-banners/%d", created.Data.ID)
+	execTestSQL(t, "INSERT INTO ships (template_id, name, english_name, rarity_id, star, type, nationality, build_time, pool_id) VALUES ($1, 'Pool', 'Pool', 6, 1, 1, 1, 60, $2)", int64(9976), int64(9970))
+	updateRequest := httptest.NewRequest(http.MethodPut, bannerPath, strings.NewReader(`{"pool_id":9970,"name":"API Banner","ultra_rare_weight":2,"enabled":false}`))
+	updateRequest.Header.Set("Content-Type", "application/json")
+	updateResponse := httptest.NewRecorder()
+	app.ServeHTTP(updateResponse, updateRequest)
+	if updateResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", updateResponse.Code)
+	}
+	var updated buildBannerSummaryResponse
+	if err := json.NewDecoder(updateResponse.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if updated.Data.Enabled || updated.Data.UltraRareWeight != 2 || len(updated.Data.RateUps) != 0 || updated.Data.Region != nil {
+		t.Fatalf("unexpected updated banner: %+v", updated.Data)
+	}
+
+	statsRequest := httptest.NewRequest(http.MethodGet, bannerPath+"/stats", nil)
+	statsResponse := httptest.NewRecorder()
+	app.ServeHTTP(statsResponse, statsRequest)
+	if statsResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", statsResponse.Code)
+	}
+	var stats buildBannerStatsResponse
+	if err := json.NewDecoder(statsResponse.Body).Decode(&stats); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if stats.Data.Total != 0 || len(stats.Data.Rarities) != 0 {
+		t.Fatalf("unexpected stats: %+v", stats.Data)
+	}
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, bannerPath, nil)
+	deleteResponse := httptest.NewRecorder()
+	app.ServeHTTP(deleteResponse, deleteRequest)
+	if deleteResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", deleteResponse.Code)
+	}
+
+	missingRequest := httptest.NewRequest(http.MethodGet, bannerPath, nil)
+	missingResponse := httptest.NewRecorder()
+	app.ServeHTTP(missingResponse, missingRequest)
+	if missingResponse.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingResponse.Code)
+	}
+}
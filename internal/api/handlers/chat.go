@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/chat"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const chatMessageDefaultLimit = 50
+
+type ChatHandler struct {
+	Validate *validator.Validate
+}
+
+func NewChatHandler() *ChatHandler {
+	return &ChatHandler{Validate: validator.New(validator.WithRequiredStructEnabled())}
+}
+
+func RegisterChatRoutes(party iris.Party, handler *ChatHandler) {
+	party.Get("/messages", handler.SearchMessages)
+	party.Delete("/messages/{id:uint}", handler.DeleteMessage)
+	party.Get("/mutes", handler.ListMutes)
+	party.Post("/mutes", handler.MuteCommander)
+	party.Delete("/mutes/{commander_id:uint}", handler.UnmuteCommander)
+	party.Post("/broadcast", handler.Broadcast)
+}
+
+// SearchMessages godoc
+// @Summary     Search chat messages
+// @Description Returns public chat messages, newest first.
+// @Tags        Chat
+// @Produce     json
+// @Param       room_id    query  int     false  "Room ID"
+// @Param       sender_id  query  int     false  "Sender commander ID"
+// @Param       q          query  string  false  "Text contained in the message (case-insensitive)"
+// @Param       offset     query  int     false  "Pagination offset"
+// @Param       limit      query  int     false  "Pagination limit"
+// @Success     200  {object}  ChatMessageListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/messages [get]
+func (handler *ChatHandler) SearchMessages(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = chatMessageDefaultLimit
+	}
+	search := orm.MessageSearch{
+		Content: strings.TrimSpace(ctx.URLParam("q")),
+		Offset:  pagination.Offset,
+		Limit:   pagination.Limit,
+	}
+	if value := strings.TrimSpace(ctx.URLParam("room_id")); value != "" {
+		// room 0 is the default room, parseOptionalUint32 would refuse it
+		roomID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			_ = ctx.JSON(response.Error("bad_request", "invalid room_id", nil))
+			return
+		}
+		room := uint32(roomID)
+		search.RoomID = &room
+	}
+	senderID, err := parseOptionalUint32(ctx.URLParam("sender_id"), "sender_id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if senderID != nil {
+		search.SenderID = *senderID
+	}
+	messages, total, err := orm.SearchMessages(search)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to search chat messages", nil))
+		return
+	}
+	payload := types.ChatMessageListResponse{
+		Messages: make([]types.ChatMessage, 0, len(messages)),
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	for _, message := range messages {
+		payload.Messages = append(payload.Messages, types.ChatMessage{
+			ID:          message.ID,
+			RoomID:      message.RoomID,
+			SenderID:    message.SenderID,
+			SenderName:  message.Sender.Name,
+			SenderLevel: message.Sender.Level,
+			Content:     message.Content,
+			SentAt:      message.SentAt.UTC().Format(time.RFC3339),
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeleteMessage godoc
+// @Summary     Delete chat message
+// @Description Removes a message from the room history. Clients that already received it keep showing it.
+// @Tags        Chat
+// @Produce     json
+// @Param       id   path  int  true  "Message ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/messages/{id} [delete]
+func (handler *ChatHandler) DeleteMessage(ctx iris.Context) {
+	id, err := parsePathUint32(ctx.Params().Get("id"), "id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteMessage(id); err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "message not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to delete message", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// ListMutes godoc
+// @Summary     List chat mutes
+// @Description Returns the mutes still running, the ones ending first first.
+// @Tags        Chat
+// @Produce     json
+// @Success     200  {object}  ChatMuteListResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/mutes [get]
+func (handler *ChatHandler) ListMutes(ctx iris.Context) {
+	mutes, err := orm.ListActiveChatMutes(time.Now())
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list chat mutes", nil))
+		return
+	}
+	payload := types.ChatMuteListResponse{Mutes: make([]types.ChatMute, 0, len(mutes))}
+	for _, mute := range mutes {
+		payload.Mutes = append(payload.Mutes, chatMutePayload(&mute))
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// MuteCommander godoc
+// @Summary     Mute commander in chat
+// @Description Keeps a commander from posting in public chat, replacing any running mute. The mute is announced in every room.
+// @Tags        Chat
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.ChatMuteRequest  true  "Mute"
+// @Success     200  {object}  ChatMuteResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/mutes [post]
+func (handler *ChatHandler) MuteCommander(ctx iris.Context) {
+	var req types.ChatMuteRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if err := orm.CommanderExists(req.CommanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	mute, err := chat.Mute(req.CommanderID, time.Duration(req.DurationSeconds)*time.Second, strings.TrimSpace(req.Reason), time.Now())
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to mute commander", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(chatMutePayload(mute)))
+}
+
+// UnmuteCommander godoc
+// @Summary     Unmute commander in chat
+// @Tags        Chat
+// @Produce     json
+// @Param       commander_id  path  int  true  "Commander ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/mutes/{commander_id} [delete]
+func (handler *ChatHandler) UnmuteCommander(ctx iris.Context) {
+	commanderID, err := parsePathUint32(ctx.Params().Get("commander_id"), "commander_id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := chat.Unmute(commanderID); err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "commander is not muted", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to unmute commander", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// Broadcast godoc
+// @Summary     Broadcast system chat message
+// @Description Sends a system notice to the online commanders of a room, or of every room when room_id is omitted. System notices are not stored.
+// @Tags        Chat
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.ChatBroadcastRequest  true  "Message"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Router      /api/v1/chat/broadcast [post]
+func (handler *ChatHandler) Broadcast(ctx iris.Context) {
+	var req types.ChatBroadcastRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "content is required", nil))
+		return
+	}
+	packet := chat.SystemMessage(content)
+	if req.RoomID == nil {
+		chat.BroadcastAll(packet)
+	} else {
+		chat.Broadcast(*req.RoomID, packet)
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+func chatMutePayload(mute *orm.ChatMute) types.ChatMute {
+	return types.ChatMute{
+		CommanderID: mute.CommanderID,
+		Reason:      mute.Reason,
+		MutedUntil:  mute.MutedUntil.UTC().Format(time.RFC3339),
+		CreatedAt:   mute.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
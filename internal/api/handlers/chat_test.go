@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type chatMessageListResponse struct {
+	OK   bool                          `json:"ok"`
+	Data types.ChatMessageListResponse `json:"data"`
+}
+
+type chatMuteListResponse struct {
+	OK   bool                       `json:"ok"`
+	Data types.ChatMuteListResponse `json:"data"`
+}
+
+func newChatHandlerTestApp(t *testing.T) *iris.Application {
+	initPlayerHandlerTestDB(t)
+	app := iris.New()
+	RegisterChatRoutes(app.Party("/api/v1/chat"), NewChatHandler())
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func TestChatMessageEndpoints(t *testing.T) {
+	app := newChatHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id IN ($1, $2)", int64(9390), int64(9391))
+	seedCommander(t, 9390, "Chat One")
+	seedCommander(t, 9391, "Chat Two")
+	sender := orm.Commander{CommanderID: 9390}
+	other := orm.Commander{CommanderID: 9391}
+	first, err := orm.SendMessage(0, "Hello harbor", &sender)
+	if err != nil {
+		t.Fatalf("seed message: %v", err)
+	}
+	if _, err := orm.SendMessage(0, "goodbye", &sender); err != nil {
+		t.Fatalf("seed message: %v", err)
+	}
+	if _, err := orm.SendMessage(3, "hello again", &other); err != nil {
+		t.Fatalf("seed message: %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/chat/messages?room_id=0&sender_id=9390&q=HELLO", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var list chatMessageListResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if list.Data.Meta.Total != 1 || len(list.Data.Messages) != 1 || list.Data.Messages[0].ID != first.ID || list.Data.Messages[0].SenderName != "Chat One" {
+		t.Fatalf("unexpected messages: %+v", list.Data)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v1/chat/messages?room_id=nope", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", recorder.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/v1/chat/messages/"+strconv.FormatUint(uint64(first.ID), 10), nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	if _, err := orm.GetMessage(first.ID); !db.IsNotFound(err) {
+		t.Fatalf("expected message to be deleted, got %v", err)
+	}
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/chat/messages/"+strconv.FormatUint(uint64(first.ID), 10), nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", recorder.Code)
+	}
+}
+
+func TestChatMuteEndpoints(t *testing.T) {
+	app := newChatHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM chat_mutes")
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9392))
+	seedCommander(t, 9392, "Chat Muted")
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/chat/mutes", strings.NewReader(`{"commander_id":9392,"duration_seconds":3600,"reason":"spam"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if _, err := orm.GetActiveChatMute(9392, time.Now()); err != nil {
+		t.Fatalf("expected commander to be muted: %v", err)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/api/v1/chat/mutes", strings.NewReader(`{"commander_id":9392}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/chat/mutes", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var mutes chatMuteListResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &mutes); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(mutes.Data.Mutes) != 1 || mutes.Data.Mutes[0].CommanderID != 9392 || mutes.Data.Mutes[0].Reason != "spam" {
+		t.Fatalf("unexpected mutes: %+v", mutes.Data)
+	}
+
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/chat/mutes/9392", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/chat/mutes/9392", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", recorder.Code)
+	}
+}
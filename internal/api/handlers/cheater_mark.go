@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerCheaterMarks godoc
+// @Summary     List player cheater marks
+// @Description Marks are reported by the client or raised when a battle result fails validation, newest first.
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerCheaterMarksResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/cheater-marks [get]
+func (handler *PlayerHandler) PlayerCheaterMarks(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	marks, err := orm.ListCheaterMarks(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load cheater marks", nil))
+		return
+	}
+	payload := types.PlayerCheaterMarksResponse{Marks: make([]types.PlayerCheaterMark, 0, len(marks))}
+	for _, mark := range marks {
+		payload.Marks = append(payload.Marks, types.PlayerCheaterMark{
+			ID:        mark.ID,
+			Source:    mark.Source,
+			Reason:    mark.Reason,
+			System:    mark.System,
+			StageID:   mark.StageID,
+			Detail:    mark.Detail,
+			CreatedAt: mark.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ClearPlayerCheaterMarks godoc
+// @Summary     Clear player cheater marks
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/cheater-marks [delete]
+func (handler *PlayerHandler) ClearPlayerCheaterMarks(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.DeleteCheaterMarks(commanderID); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to clear cheater marks", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
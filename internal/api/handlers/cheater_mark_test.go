@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type playerCheaterMarksResponse struct {
+	OK   bool                             `json:"ok"`
+	Data types.PlayerCheaterMarksResponse `json:"data"`
+}
+
+func TestPlayerCheaterMarksEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9373))
+	seedCommander(t, 9373, "Cheater Tester")
+	mark := orm.CheaterMark{CommanderID: 9373, Source: orm.CheaterMarkSourceBattle, Reason: "battle_too_short", System: 1, StageID: 101010}
+	if err := orm.CreateCheaterMark(&mark); err != nil {
+		t.Fatalf("seed cheater mark: %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/players/9373/cheater-marks", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var payload playerCheaterMarksResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data.Marks) != 1 || payload.Data.Marks[0].Reason != "battle_too_short" || payload.Data.Marks[0].StageID != 101010 {
+		t.Fatalf("unexpected cheater marks: %+v", payload.Data)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/v1/players/9373/cheater-marks", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	marks, err := orm.ListCheaterMarks(9373)
+	if err != nil || len(marks) != 0 {
+		t.Fatalf("expected marks to be cleared, got %v (%v)", marks, err)
+	}
+}
@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/loot"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type DropTableHandler struct {
+	Validate *validator.Validate
+}
+
+func NewDropTableHandler() *DropTableHandler {
+	return &DropTableHandler{Validate: validator.New(validator.WithRequiredStructEnabled())}
+}
+
+func RegisterDropTableRoutes(party iris.Party, handler *DropTableHandler) {
+	party.Get("", handler.ListDropTables)
+	party.Post("", handler.CreateDropTable)
+	party.Post("/simulate", handler.SimulateDropTable)
+	party.Get("/{id:uint}", handler.DropTableDetail)
+	party.Put("/{id:uint}", handler.UpdateDropTable)
+	party.Delete("/{id:uint}", handler.DeleteDropTable)
+}
+
+func RegisterDropBonusRoutes(party iris.Party, handler *DropTableHandler) {
+	party.Get("", handler.ListDropBonuses)
+	party.Post("", handler.CreateDropBonus)
+	party.Delete("/{id:uint}", handler.DeleteDropBonus)
+}
+
+// ListDropTables godoc
+// @Summary     List drop tables
+// @Tags        Drop Tables
+// @Produce     json
+// @Param       kind    query  string  false  "Table kind (chapter, event, item)"
+// @Param       offset  query  int     false  "Pagination offset"
+// @Param       limit   query  int     false  "Pagination limit"
+// @Success     200  {object}  DropTableListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables [get]
+func (handler *DropTableHandler) ListDropTables(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	kind := strings.TrimSpace(ctx.URLParam("kind"))
+	switch kind {
+	case "", orm.DropKindChapter, orm.DropKindEvent, orm.DropKindItem:
+	default:
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid kind", nil))
+		return
+	}
+	tables, total, err := orm.ListDropTables(kind, pagination.Offset, pagination.Limit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list drop tables", nil))
+		return
+	}
+	results := make([]types.DropTableSummary, 0, len(tables))
+	for i := range tables {
+		results = append(results, dropTableSummary(&tables[i]))
+	}
+	payload := types.DropTableListResponse{
+		Tables: results,
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DropTableDetail godoc
+// @Summary     Get drop table
+// @Tags        Drop Tables
+// @Produce     json
+// @Param       id   path  int  true  "Drop table ID"
+// @Success     200  {object}  DropTableSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables/{id} [get]
+func (handler *DropTableHandler) DropTableDetail(ctx iris.Context) {
+	tableID, err := parsePathUint32(ctx.Params().Get("id"), "drop table id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	table, err := orm.GetDropTable(tableID)
+	if err != nil {
+		writeDropTableError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(dropTableSummary(table)))
+}
+
+// CreateDropTable godoc
+// @Summary     Create drop table
+// @Description Sets the drop weights of a chapter, an event or a virtual item. A source has at most one table.
+// @Tags        Drop Tables
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.DropTableRequest  true  "Drop table"
+// @Success     200  {object}  DropTableSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables [post]
+func (handler *DropTableHandler) CreateDropTable(ctx iris.Context) {
+	table := orm.DropTable{}
+	if !handler.readDropTableRequest(ctx, &table) {
+		return
+	}
+	if err := orm.CreateDropTable(&table); err != nil {
+		writeDropTableError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(dropTableSummary(&table)))
+}
+
+// UpdateDropTable godoc
+// @Summary     Replace drop table
+// @Tags        Drop Tables
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int                     true  "Drop table ID"
+// @Param       payload  body  types.DropTableRequest  true  "Drop table"
+// @Success     200  {object}  DropTableSummaryResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables/{id} [put]
+func (handler *DropTableHandler) UpdateDropTable(ctx iris.Context) {
+	tableID, err := parsePathUint32(ctx.Params().Get("id"), "drop table id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	table := orm.DropTable{ID: tableID}
+	if !handler.readDropTableRequest(ctx, &table) {
+		return
+	}
+	if err := orm.UpdateDropTable(&table); err != nil {
+		writeDropTableError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(dropTableSummary(&table)))
+}
+
+// DeleteDropTable godoc
+// @Summary     Delete drop table
+// @Description The source goes back to its default drops.
+// @Tags        Drop Tables
+// @Produce     json
+// @Param       id   path  int  true  "Drop table ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables/{id} [delete]
+func (handler *DropTableHandler) DeleteDropTable(ctx iris.Context) {
+	tableID, err := parsePathUint32(ctx.Params().Get("id"), "drop table id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteDropTable(tableID); err != nil {
+		writeDropTableError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// SimulateDropTable godoc
+// @Summary     Simulate drop table
+// @Description Rolls the table of a source, a chapter by default, with the drop bonuses running now and compares the observed rates with the expected ones. Nothing is granted.
+// @Tags        Drop Tables
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.DropTableSimulationRequest  true  "Simulation"
+// @Success     200  {object}  DropTableSimulationResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-tables/simulate [post]
+func (handler *DropTableHandler) SimulateDropTable(ctx iris.Context) {
+	var req types.DropTableSimulationRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = orm.DropKindChapter
+	}
+	rank, _ := loot.ParseRank(req.Rank)
+	table, err := orm.GetDropTableBySource(req.Kind, req.SourceID)
+	if err != nil {
+		writeDropTableError(ctx, err)
+		return
+	}
+	simulation, err := loot.SimulateTable(table, rank, req.Rolls, time.Now())
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to simulate drop table", nil))
+		return
+	}
+	rate := func(hits uint32) float64 {
+		if simulation.Rolls == 0 {
+			return 0
+		}
+		return float64(hits) / float64(simulation.Rolls)
+	}
+	payload := types.DropTableSimulationResponse{
+		TableID:           table.ID,
+		Rank:              rank.String(),
+		Rolls:             simulation.Rolls,
+		BonusRate:         simulation.Bonus,
+		Entries:           make([]types.DropTableSimulationEntry, 0, len(table.Entries)),
+		Empty:             simulation.Empty,
+		EmptyRate:         rate(simulation.Empty),
+		ExpectedEmptyRate: simulation.EmptyOdds,
+	}
+	for i, entry := range table.Entries {
+		payload.Entries = append(payload.Entries, types.DropTableSimulationEntry{
+			DropType:     entry.DropType,
+			DropID:       entry.DropID,
+			Count:        entry.Count,
+			Hits:         simulation.Hits[i],
+			Rate:         rate(simulation.Hits[i]),
+			ExpectedRate: simulation.Odds[i],
+		})
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ListDropBonuses godoc
+// @Summary     List drop bonuses
+// @Description Returns every drop bonus, past ones included, the most recent first.
+// @Tags        Drop Tables
+// @Produce     json
+// @Success     200  {object}  DropBonusListResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-bonuses [get]
+func (handler *DropTableHandler) ListDropBonuses(ctx iris.Context) {
+	bonuses, err := orm.ListDropBonuses()
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list drop bonuses", nil))
+		return
+	}
+	payload := types.DropBonusListResponse{Bonuses: make([]types.DropBonus, 0, len(bonuses))}
+	for i := range bonuses {
+		payload.Bonuses = append(payload.Bonuses, dropBonusPayload(&bonuses[i]))
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// CreateDropBonus godoc
+// @Summary     Create drop bonus
+// @Description Scales the drop rates of the matching tables while the bonus runs. Running bonuses multiply.
+// @Tags        Drop Tables
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.DropBonusRequest  true  "Drop bonus"
+// @Success     200  {object}  DropBonusResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-bonuses [post]
+func (handler *DropTableHandler) CreateDropBonus(ctx iris.Context) {
+	var req types.DropBonusRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "ends_at must be after starts_at", nil))
+		return
+	}
+	if req.SourceID != nil && req.Kind == nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "source_id requires kind", nil))
+		return
+	}
+	bonus := orm.DropBonus{
+		Name:     req.Name,
+		Kind:     req.Kind,
+		SourceID: req.SourceID,
+		Rate:     req.Rate,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	}
+	if err := orm.CreateDropBonus(&bonus); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to create drop bonus", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(dropBonusPayload(&bonus)))
+}
+
+// DeleteDropBonus godoc
+// @Summary     Delete drop bonus
+// @Tags        Drop Tables
+// @Produce     json
+// @Param       id   path  int  true  "Drop bonus ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/drop-bonuses/{id} [delete]
+func (handler *DropTableHandler) DeleteDropBonus(ctx iris.Context) {
+	bonusID, err := parsePathUint32(ctx.Params().Get("id"), "drop bonus id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteDropBonus(bonusID); err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "drop bonus not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to delete drop bonus", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// readDropTableRequest fills table from the request body, writing the error
+// response and returning false when it is invalid.
+func (handler *DropTableHandler) readDropTableRequest(ctx iris.Context, table *orm.DropTable) bool {
+	var req types.DropTableRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return false
+	}
+	if err := validateDropTableRequest(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return false
+	}
+	rate := func(value *uint32) uint32 {
+		if value == nil {
+			return orm.DropRateScale
+		}
+		return *value
+	}
+	table.Kind = req.Kind
+	table.SourceID = req.SourceID
+	table.Name = req.Name
+	table.Rolls = 1
+	if req.Rolls != nil {
+		table.Rolls = *req.Rolls
+	}
+	table.EmptyWeight = req.EmptyWeight
+	table.RankSRate = rate(req.RankSRate)
+	table.RankARate = rate(req.RankARate)
+	table.RankBRate = rate(req.RankBRate)
+	table.RateMultiplier = rate(req.RateMultiplier)
+	table.Enabled = req.Enabled == nil || *req.Enabled
+	table.Entries = make([]orm.DropTableEntry, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		table.Entries = append(table.Entries, orm.DropTableEntry{
+			DropType: entry.DropType,
+			DropID:   entry.DropID,
+			Count:    max(entry.Count, 1),
+			Weight:   entry.Weight,
+		})
+	}
+	return true
+}
+
+func validateDropTableRequest(req *types.DropTableRequest) error {
+	seen := make(map[[2]uint32]struct{}, len(req.Entries))
+	for _, entry := range req.Entries {
+		key := [2]uint32{entry.DropType, entry.DropID}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate drop %d:%d", entry.DropType, entry.DropID)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+func dropTableSummary(table *orm.DropTable) types.DropTableSummary {
+	entries := make([]types.DropTableEntry, 0, len(table.Entries))
+	for _, entry := range table.Entries {
+		entries = append(entries, types.DropTableEntry{DropType: entry.DropType, DropID: entry.DropID, Count: entry.Count, Weight: entry.Weight})
+	}
+	return types.DropTableSummary{
+		ID:             table.ID,
+		Kind:           table.Kind,
+		SourceID:       table.SourceID,
+		Name:           table.Name,
+		Rolls:          table.Rolls,
+		EmptyWeight:    table.EmptyWeight,
+		RankSRate:      table.RankSRate,
+		RankARate:      table.RankARate,
+		RankBRate:      table.RankBRate,
+		RateMultiplier: table.RateMultiplier,
+		Enabled:        table.Enabled,
+		Entries:        entries,
+		CreatedAt:      table.CreatedAt,
+		UpdatedAt:      table.UpdatedAt,
+	}
+}
+
+func dropBonusPayload(bonus *orm.DropBonus) types.DropBonus {
+	return types.DropBonus{
+		ID:        bonus.ID,
+		Name:      bonus.Name,
+		Kind:      bonus.Kind,
+		SourceID:  bonus.SourceID,
+		Rate:      bonus.Rate,
+		StartsAt:  bonus.StartsAt,
+		EndsAt:    bonus.EndsAt,
+		CreatedAt: bonus.CreatedAt,
+	}
+}
+
+func writeDropTableError(ctx iris.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "drop table not found", nil))
+	case errors.Is(err, orm.ErrDropTableExists):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", err.Error(), nil))
+	default:
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to process drop table", nil))
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type dropTableSummaryResponse struct {
+	OK   bool                   `json:"ok"`
+	Data types.DropTableSummary `json:"data"`
+}
+
+type dropTableSimulationResponse struct {
+	OK   bool                              `json:"ok"`
+	Data types.DropTableSimulationResponse `json:"data"`
+}
+
+func newDropTableTestApp(t *testing.T) *iris.Application {
+	initPlayerHandlerTestDB(t)
+	app := iris.New()
+	handler := NewDropTableHandler()
+	RegisterDropTableRoutes(app.Party("/api/v1/drop-tables"), handler)
+	RegisterDropBonusRoutes(app.Party("/api/v1/drop-bonuses"), handler)
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func serveDropTableRequest(t *testing.T, app *iris.Application, method string, path string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestDropTableEndpoints(t *testing.T) {
+	app := newDropTableTestApp(t)
+	execTestSQL(t, "DELETE FROM drop_tables")
+	execTestSQL(t, "DELETE FROM drop_bonuses")
+
+	duplicate := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-tables", `{"kind":"chapter","source_id":101,"entries":[{"drop_type":4,"drop_id":101,"weight":1},{"drop_type":4,"drop_id":101,"weight":2}]}`)
+	if duplicate.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", duplicate.Code)
+	}
+
+	body := `{"kind":"chapter","source_id":101,"name":"1-1","empty_weight":50,"rank_s_rate":200,"entries":[{"drop_type":4,"drop_id":101,"weight":10},{"drop_type":2,"drop_id":20001,"count":3,"weight":40}]}`
+	createResponse := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-tables", body)
+	if createResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", createResponse.Code)
+	}
+	var created dropTableSummaryResponse
+	if err := json.NewDecoder(createResponse.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if created.Data.ID == 0 || created.Data.Rolls != 1 || created.Data.RankARate != 100 || !created.Data.Enabled || len(created.Data.Entries) != 2 || created.Data.Entries[0].Count != 1 {
+		t.Fatalf("unexpected created table: %+v", created.Data)
+	}
+	if conflict := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-tables", body); conflict.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for a second table of the source, got %d", conflict.Code)
+	}
+
+	bonusResponse := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-bonuses", `{"name":"Double drops","kind":"chapter","rate":200}`)
+	if bonusResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", bonusResponse.Code)
+	}
+
+	simulateResponse := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-tables/simulate", `{"source_id":101,"rank":"S","rolls":2000}`)
+	if simulateResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", simulateResponse.Code)
+	}
+	var simulation dropTableSimulationResponse
+	if err := json.NewDecoder(simulateResponse.Body).Decode(&simulation); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	// rank S and the bonus quadruple the entries: 40 and 160 against 50
+	if simulation.Data.Rolls != 2000 || simulation.Data.BonusRate != 200 || simulation.Data.Rank != "S" {
+		t.Fatalf("unexpected simulation: %+v", simulation.Data)
+	}
+	if expected := simulation.Data.ExpectedEmptyRate; expected < 0.199 || expected > 0.201 {
+		t.Fatalf("expected an empty rate of 0.2, got %f", expected)
+	}
+	hits := simulation.Data.Empty
+	for _, entry := range simulation.Data.Entries {
+		hits += entry.Hits
+	}
+	if hits != 2000 {
+		t.Fatalf("expected every roll to be counted, got %d", hits)
+	}
+
+	tablePath := fmt.Sprintf("/api/v1/drop-tables/%d", created.Data.ID)
+	updateResponse := serveDropTableRequest(t, app, http.MethodPut, tablePath, `{"kind":"chapter","source_id":101,"enabled":false,"entries":[{"drop_type":4,"drop_id":102,"weight":1}]}`)
+	if updateResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", updateResponse.Code)
+	}
+	detailResponse := serveDropTableRequest(t, app, http.MethodGet, tablePath, "")
+	var detail dropTableSummaryResponse
+	if err := json.NewDecoder(detailResponse.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if detail.Data.Enabled || len(detail.Data.Entries) != 1 || detail.Data.Entries[0].DropID != 102 {
+		t.Fatalf("unexpected updated table: %+v", detail.Data)
+	}
+
+	if missing := serveDropTableRequest(t, app, http.MethodPost, "/api/v1/drop-tables/simulate", `{"kind":"item","source_id":101,"rolls":10}`); missing.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missing.Code)
+	}
+	if deleted := serveDropTableRequest(t, app, http.MethodDelete, tablePath, ""); deleted.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", deleted.Code)
+	}
+	if deleted := serveDropTableRequest(t, app, http.MethodDelete, tablePath, ""); deleted.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", deleted.Code)
+	}
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerFriends godoc
+// @Summary     Get player friends, pending requests and blacklist
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerFriendsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/friends [get]
+func (handler *PlayerHandler) PlayerFriends(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	payload, err := loadPlayerFriends(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load friends", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// AddPlayerFriend godoc
+// @Summary     Add a friend to a player
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Player ID"
+// @Param       payload  body  types.PlayerFriendAddRequest  true  "Friend to add"
+// @Success     200  {object}  PlayerFriendsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/friends [post]
+func (handler *PlayerHandler) AddPlayerFriend(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerFriendAddRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.FriendID == commanderID {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "cannot befriend self", nil))
+		return
+	}
+	if err := orm.CommanderExists(req.FriendID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "friend not found", nil))
+			return
+		}
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.CreateFriendship(commanderID, req.FriendID); err != nil {
+		if errors.Is(err, orm.ErrAlreadyFriends) {
+			ctx.StatusCode(iris.StatusConflict)
+			_ = ctx.JSON(response.Error("conflict", "already friends", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to add friend", nil))
+		return
+	}
+	payload, err := loadPlayerFriends(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load friends", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeletePlayerFriend godoc
+// @Summary     Remove a friend from a player
+// @Tags        Players
+// @Produce     json
+// @Param       id         path  int  true  "Player ID"
+// @Param       friend_id  path  int  true  "Friend commander ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/friends/{friend_id} [delete]
+func (handler *PlayerHandler) DeletePlayerFriend(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	friendID, err := parsePathUint32(ctx.Params().Get("friend_id"), "friend id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.DeleteFriendship(commanderID, friendID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "friend not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to delete friend", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// AddPlayerFriendBlock godoc
+// @Summary     Add a commander to a player's blacklist
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Player ID"
+// @Param       payload  body  types.PlayerFriendBlockRequest  true  "Commander to block"
+// @Success     200  {object}  PlayerFriendsResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/friends/blocks [post]
+func (handler *PlayerHandler) AddPlayerFriendBlock(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerFriendBlockRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.BlockedID == commanderID {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "cannot block self", nil))
+		return
+	}
+	if err := orm.CommanderExists(req.BlockedID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "blocked player not found", nil))
+			return
+		}
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.BlockCommander(commanderID, req.BlockedID); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to block player", nil))
+		return
+	}
+	payload, err := loadPlayerFriends(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load friends", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeletePlayerFriendBlock godoc
+// @Summary     Remove a commander from a player's blacklist
+// @Tags        Players
+// @Produce     json
+// @Param       id          path  int  true  "Player ID"
+// @Param       blocked_id  path  int  true  "Blocked commander ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/friends/blocks/{blocked_id} [delete]
+func (handler *PlayerHandler) DeletePlayerFriendBlock(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	blockedID, err := parsePathUint32(ctx.Params().Get("blocked_id"), "blocked id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.UnblockCommander(commanderID, blockedID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "block not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to unblock player", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+func loadPlayerFriends(commanderID uint32) (types.PlayerFriendsResponse, error) {
+	var payload types.PlayerFriendsResponse
+	friends, err := orm.ListCommanderFriends(commanderID)
+	if err != nil {
+		return payload, err
+	}
+	incoming, err := orm.ListIncomingFriendRequests(commanderID)
+	if err != nil {
+		return payload, err
+	}
+	outgoing, err := orm.ListOutgoingFriendRequests(commanderID)
+	if err != nil {
+		return payload, err
+	}
+	blocks, err := orm.ListCommanderBlocks(commanderID)
+	if err != nil {
+		return payload, err
+	}
+	online := onlineCommanderIDs()
+	payload.Friends = friendEntries(friends, online)
+	payload.IncomingRequests = friendEntries(incoming, online)
+	payload.OutgoingRequests = friendEntries(outgoing, online)
+	payload.Blocks = friendEntries(blocks, online)
+	return payload, nil
+}
+
+func friendEntries(profiles []orm.FriendProfile, online map[uint32]bool) []types.PlayerFriendEntry {
+	entries := make([]types.PlayerFriendEntry, 0, len(profiles))
+	for _, profile := range profiles {
+		entries = append(entries, types.PlayerFriendEntry{
+			CommanderID: profile.CommanderID,
+			Name:        profile.Name,
+			Level:       profile.Level,
+			Online:      online[profile.CommanderID],
+			LastLogin:   profile.LastLogin,
+			Since:       profile.Since,
+			Content:     profile.Content,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type playerFriendsResponse struct {
+	OK   bool                        `json:"ok"`
+	Data types.PlayerFriendsResponse `json:"data"`
+}
+
+func TestPlayerFriendsEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id IN ($1, $2)", int64(9360), int64(9361))
+	seedCommander(t, 9360, "Friends Tester")
+	seedCommander(t, 9361, "Friends Target")
+
+	addRequest := httptest.NewRequest(http.MethodPost, "/api/v1/players/9360/friends", strings.NewReader(`{"friend_id":9361}`))
+	addRequest.Header.Set("Content-Type", "application/json")
+	addResponse := httptest.NewRecorder()
+	app.ServeHTTP(addResponse, addRequest)
+	if addResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", addResponse.Code)
+	}
+	var friendsResponse playerFriendsResponse
+	if err := json.Unmarshal(addResponse.Body.Bytes(), &friendsResponse); err != nil {
+		t.Fatalf("decode add response: %v", err)
+	}
+	if !friendsResponse.OK || len(friendsResponse.Data.Friends) != 1 || friendsResponse.Data.Friends[0].CommanderID != 9361 {
+		t.Fatalf("unexpected add payload: %+v", friendsResponse)
+	}
+
+	duplicateRequest := httptest.NewRequest(http.MethodPost, "/api/v1/players/9360/friends", strings.NewReader(`{"friend_id":9361}`))
+	duplicateRequest.Header.Set("Content-Type", "application/json")
+	duplicateResponse := httptest.NewRecorder()
+	app.ServeHTTP(duplicateResponse, duplicateRequest)
+	if duplicateResponse.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", duplicateResponse.Code)
+	}
+
+	getRequest := httptest.NewRequest(http.MethodGet, "/api/v1/players/9361/friends", nil)
+	getResponse := httptest.NewRecorder()
+	app.ServeHTTP(getResponse, getRequest)
+	if getResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", getResponse.Code)
+	}
+	friendsResponse = playerFriendsResponse{}
+	if err := json.Unmarshal(getResponse.Body.Bytes(), &friendsResponse); err != nil {
+		t.Fatalf("decode get response: %v", err)
+	}
+	if len(friendsResponse.Data.Friends) != 1 || friendsResponse.Data.Friends[0].CommanderID != 9360 {
+		t.Fatalf("unexpected get payload: %+v", friendsResponse)
+	}
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, "/api/v1/players/9360/friends/9361", nil)
+	deleteResponse := httptest.NewRecorder()
+	app.ServeHTTP(deleteResponse, deleteRequest)
+	if deleteResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", deleteResponse.Code)
+	}
+
+	missingRequest := httptest.NewRequest(http.MethodDelete, "/api/v1/players/9360/friends/9361", nil)
+	missingResponse := httptest.NewRecorder()
+	app.ServeHTTP(missingResponse, missingRequest)
+	if missingResponse.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingResponse.Code)
+	}
+
+	blockRequest := httptest.NewRequest(http.MethodPost, "/api/v1/players/9360/friends/blocks", strings.NewReader(`{"blocked_id":9361}`))
+	blockRequest.Header.Set("Content-Type", "application/json")
+	blockResponse := httptest.NewRecorder()
+	app.ServeHTTP(blockResponse, blockRequest)
+	if blockResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", blockResponse.Code)
+	}
+	friendsResponse = playerFriendsResponse{}
+	if err := json.Unmarshal(blockResponse.Body.Bytes(), &friendsResponse); err != nil {
+		t.Fatalf("decode block response: %v", err)
+	}
+	if len(friendsResponse.Data.Blocks) != 1 {
+		t.Fatalf("unexpected block payload: %+v", friendsResponse)
+	}
+
+	unblockRequest := httptest.NewRequest(http.MethodDelete, "/api/v1/players/9360/friends/blocks/9361", nil)
+	unblockResponse := httptest.NewRecorder()
+	app.ServeHTTP(unblockResponse, unblockRequest)
+	if unblockResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", unblockResponse.Code)
+	}
+}
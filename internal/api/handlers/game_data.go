@@ -10,7 +10,10 @@ import (
 
 	"github.com/ggmolly/belfast/internal/api/response"
 	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/orm"
 )
 
@@ -3015,6 +3018,7 @@ func (handler *GameDataHandler) CreateConfigEntry(ctx iris.Context) {
 		_ = ctx.JSON(response.Error("internal_error", "failed to create config entry", nil))
 		return
 	}
+	reloadGameData(entry.Category)
 
 	_ = ctx.JSON(response.Success(nil))
 }
@@ -3070,6 +3074,7 @@ func (handler *GameDataHandler) UpdateConfigEntry(ctx iris.Context) {
 		return
 	}
 
+	previousCategory := entry.Category
 	entry.Category = category
 	entry.Key = key
 	entry.Data = req.Data.Value
@@ -3079,6 +3084,11 @@ func (handler *GameDataHandler) UpdateConfigEntry(ctx iris.Context) {
 		_ = ctx.JSON(response.Error("internal_error", "failed to update config entry", nil))
 		return
 	}
+	if previousCategory != entry.Category {
+		reloadGameData(previousCategory, entry.Category)
+	} else {
+		reloadGameData(entry.Category)
+	}
 
 	_ = ctx.JSON(response.Success(nil))
 }
@@ -3101,14 +3111,32 @@ func (handler *GameDataHandler) DeleteConfigEntry(ctx iris.Context) {
 		return
 	}
 
+	entry, err := orm.GetConfigEntryByID(entryID)
+	if err != nil {
+		writeGameDataError(ctx, err, "config entry")
+		return
+	}
 	if err := orm.DeleteConfigEntryByID(entryID); err != nil {
 		writeGameDataError(ctx, err, "config entry")
 		return
 	}
+	reloadGameData(entry.Category)
 
 	_ = ctx.JSON(response.Success(nil))
 }
 
+// reloadGameData refreshes the in-memory copy of edited config categories so
+// packet handlers see the change right away, here and on the other nodes of
+// the cluster.
+func reloadGameData(categories ...string) {
+	if err := gamedata.Default.Reload(categories...); err != nil {
+		logger.LogEvent("API", "GameData", fmt.Sprintf("failed to reload %v: %v", categories, err), logger.LOG_LEVEL_ERROR)
+	}
+	if connection.BelfastInstance != nil {
+		connection.BelfastInstance.GameDataChanged(categories...)
+	}
+}
+
 // ListLivingAreaCovers godoc
 // @Summary     List living area covers
 // @Tags        GameData
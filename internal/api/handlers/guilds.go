@@ -0,0 +1,498 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const guildDetailLogCount = 100
+
+type GuildHandler struct {
+	Validate *validator.Validate
+}
+
+func NewGuildHandler() *GuildHandler {
+	return &GuildHandler{Validate: validator.New(validator.WithRequiredStructEnabled())}
+}
+
+func RegisterGuildRoutes(party iris.Party, handler *GuildHandler) {
+	party.Get("", handler.ListGuilds)
+	party.Post("", handler.CreateGuild)
+	party.Get("/{id:uint}", handler.GuildDetail)
+	party.Patch("/{id:uint}", handler.UpdateGuild)
+	party.Delete("/{id:uint}", handler.DeleteGuild)
+	party.Post("/{id:uint}/members", handler.AddGuildMember)
+	party.Patch("/{id:uint}/members/{commander_id:uint}", handler.UpdateGuildMember)
+	party.Delete("/{id:uint}/members/{commander_id:uint}", handler.DeleteGuildMember)
+}
+
+// ListGuilds godoc
+// @Summary     List guilds
+// @Tags        Guilds
+// @Produce     json
+// @Param       offset  query  int  false  "Pagination offset"
+// @Param       limit   query  int  false  "Pagination limit"
+// @Success     200  {object}  GuildListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds [get]
+func (handler *GuildHandler) ListGuilds(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	guilds, total, err := orm.ListGuilds(pagination.Offset, pagination.Limit)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to list guilds", nil))
+		return
+	}
+	results := make([]types.GuildSummary, 0, len(guilds))
+	for i := range guilds {
+		results = append(results, guildSummary(&guilds[i]))
+	}
+	payload := types.GuildListResponse{
+		Guilds: results,
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// GuildDetail godoc
+// @Summary     Get guild with members, applications and logs
+// @Tags        Guilds
+// @Produce     json
+// @Param       id   path  int  true  "Guild ID"
+// @Success     200  {object}  GuildDetailResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id} [get]
+func (handler *GuildHandler) GuildDetail(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	payload, err := loadGuildDetail(guildID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// CreateGuild godoc
+// @Summary     Create guild
+// @Tags        Guilds
+// @Accept      json
+// @Produce     json
+// @Param       payload  body  types.GuildCreateRequest  true  "Guild"
+// @Success     200  {object}  GuildDetailResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds [post]
+func (handler *GuildHandler) CreateGuild(ctx iris.Context) {
+	var req types.GuildCreateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	leader, err := orm.GetCommanderCoreByID(req.LeaderID)
+	if err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	guild := orm.Guild{
+		Name:      req.Name,
+		Faction:   req.Faction,
+		Policy:    req.Policy,
+		Capacity:  req.Capacity,
+		Announce:  req.Announce,
+		Manifesto: req.Manifesto,
+	}
+	if err := orm.CreateGuild(&guild, leader.CommanderID, leader.Name); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	payload, err := loadGuildDetail(guild.ID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// UpdateGuild godoc
+// @Summary     Update guild
+// @Tags        Guilds
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Guild ID"
+// @Param       payload  body  types.GuildUpdateRequest  true  "Guild update"
+// @Success     200  {object}  GuildDetailResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id} [patch]
+func (handler *GuildHandler) UpdateGuild(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	var req types.GuildUpdateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	guild, err := orm.GetGuild(guildID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	if req.Name != nil {
+		guild.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Faction != nil {
+		guild.Faction = *req.Faction
+	}
+	if req.Policy != nil {
+		guild.Policy = *req.Policy
+	}
+	if req.Level != nil {
+		guild.Level = *req.Level
+	}
+	if req.Exp != nil {
+		guild.Exp = *req.Exp
+	}
+	if req.Capacity != nil {
+		guild.Capacity = *req.Capacity
+	}
+	if req.Announce != nil {
+		guild.Announce = *req.Announce
+	}
+	if req.Manifesto != nil {
+		guild.Manifesto = *req.Manifesto
+	}
+	if req.ChangeFactionCD != nil {
+		guild.ChangeFactionCD = *req.ChangeFactionCD
+	}
+	if req.KickLeaderCD != nil {
+		guild.KickLeaderCD = *req.KickLeaderCD
+	}
+	if err := orm.UpdateGuild(guild); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	if req.CapitalDelta != nil && *req.CapitalDelta != 0 {
+		if err := orm.AddGuildCapital(guildID, *req.CapitalDelta, 0, "admin", orm.GuildCapitalLogAdmin, nil); err != nil {
+			writeGuildError(ctx, err)
+			return
+		}
+	}
+	payload, err := loadGuildDetail(guildID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeleteGuild godoc
+// @Summary     Dissolve guild
+// @Tags        Guilds
+// @Produce     json
+// @Param       id   path  int  true  "Guild ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id} [delete]
+func (handler *GuildHandler) DeleteGuild(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.DeleteGuild(guildID); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// AddGuildMember godoc
+// @Summary     Add guild member
+// @Tags        Guilds
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Guild ID"
+// @Param       payload  body  types.GuildMemberAddRequest  true  "Member"
+// @Success     200  {object}  GuildDetailResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id}/members [post]
+func (handler *GuildHandler) AddGuildMember(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	var req types.GuildMemberAddRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if req.Duty == 0 {
+		req.Duty = orm.GuildDutyOrdinary
+	}
+	if req.Duty == orm.GuildDutyCommander {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "use member update to transfer leadership", nil))
+		return
+	}
+	commander, err := orm.GetCommanderCoreByID(req.CommanderID)
+	if err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.AddGuildMember(guildID, commander.CommanderID, req.Duty, commander.Name); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	payload, err := loadGuildDetail(guildID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// UpdateGuildMember godoc
+// @Summary     Update guild member duty
+// @Tags        Guilds
+// @Accept      json
+// @Produce     json
+// @Param       id            path  int  true  "Guild ID"
+// @Param       commander_id  path  int  true  "Commander ID"
+// @Param       payload       body  types.GuildMemberUpdateRequest  true  "Duty"
+// @Success     200  {object}  GuildDetailResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id}/members/{commander_id} [patch]
+func (handler *GuildHandler) UpdateGuildMember(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	commanderID, err := parsePathUint32(ctx.Params().Get("commander_id"), "commander id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	var req types.GuildMemberUpdateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	commander, err := orm.GetCommanderCoreByID(commanderID)
+	if err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.SetGuildMemberDuty(guildID, commanderID, req.Duty, commander.Name); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	payload, err := loadGuildDetail(guildID)
+	if err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeleteGuildMember godoc
+// @Summary     Remove guild member
+// @Tags        Guilds
+// @Produce     json
+// @Param       id            path  int  true  "Guild ID"
+// @Param       commander_id  path  int  true  "Commander ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/guilds/{id}/members/{commander_id} [delete]
+func (handler *GuildHandler) DeleteGuildMember(ctx iris.Context) {
+	guildID, err := parsePathUint32(ctx.Params().Get("id"), "guild id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	commanderID, err := parsePathUint32(ctx.Params().Get("commander_id"), "commander id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	commander, err := orm.GetCommanderCoreByID(commanderID)
+	if err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.RemoveGuildMember(guildID, commanderID, commander.Name, orm.GuildLogKick); err != nil {
+		writeGuildError(ctx, err)
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+func guildSummary(guild *orm.Guild) types.GuildSummary {
+	return types.GuildSummary{
+		ID:              guild.ID,
+		Name:            guild.Name,
+		Faction:         guild.Faction,
+		Policy:          guild.Policy,
+		Level:           guild.Level,
+		Exp:             guild.Exp,
+		Capacity:        guild.Capacity,
+		Capital:         guild.Capital,
+		TechGroup:       guild.TechGroup,
+		MemberCount:     guild.MemberCount,
+		Announce:        guild.Announce,
+		Manifesto:       guild.Manifesto,
+		ChangeFactionCD: guild.ChangeFactionCD,
+		KickLeaderCD:    guild.KickLeaderCD,
+		CreatedAt:       guild.CreatedAt,
+	}
+}
+
+func guildMemberEntries(profiles []orm.GuildMemberProfile, online map[uint32]bool) []types.GuildMemberEntry {
+	entries := make([]types.GuildMemberEntry, 0, len(profiles))
+	for _, profile := range profiles {
+		entries = append(entries, types.GuildMemberEntry{
+			CommanderID:  profile.CommanderID,
+			Name:         profile.Name,
+			Level:        profile.Level,
+			Duty:         profile.Duty,
+			Liveness:     profile.Liveness,
+			Contribution: profile.Contribution,
+			Online:       online[profile.CommanderID],
+			JoinedAt:     profile.JoinedAt,
+			Content:      profile.Content,
+		})
+	}
+	return entries
+}
+
+func loadGuildDetail(guildID uint32) (types.GuildDetailResponse, error) {
+	var payload types.GuildDetailResponse
+	guild, err := orm.GetGuild(guildID)
+	if err != nil {
+		return payload, err
+	}
+	members, err := orm.ListGuildMembers(guildID)
+	if err != nil {
+		return payload, err
+	}
+	applications, err := orm.ListGuildApplications(guildID)
+	if err != nil {
+		return payload, err
+	}
+	logs, err := orm.ListGuildLogs(guildID, guildDetailLogCount)
+	if err != nil {
+		return payload, err
+	}
+	online := onlineCommanderIDs()
+	payload.Guild = guildSummary(guild)
+	payload.Members = guildMemberEntries(members, online)
+	payload.Applications = guildMemberEntries(applications, online)
+	payload.Logs = make([]types.GuildLogEntry, 0, len(logs))
+	for _, entry := range logs {
+		payload.Logs = append(payload.Logs, types.GuildLogEntry{
+			Cmd:         entry.Cmd,
+			CommanderID: entry.CommanderID,
+			Name:        entry.Name,
+			Arg1:        entry.Arg1,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	return payload, nil
+}
+
+func writeGuildError(ctx iris.Context, err error) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "guild not found", nil))
+	case errors.Is(err, orm.ErrGuildNameTaken):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "guild name already exists", nil))
+	case errors.Is(err, orm.ErrAlreadyInGuild):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "commander already in a guild", nil))
+	case errors.Is(err, orm.ErrGuildCapitalNotEnough):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "guild capital cannot go below zero", nil))
+	case errors.Is(err, orm.ErrGuildFull):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "guild is full", nil))
+	default:
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to process guild", nil))
+	}
+}
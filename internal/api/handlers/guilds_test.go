@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type guildDetailResponse struct {
+	OK   bool                      `json:"ok"`
+	Data types.GuildDetailResponse `json:"data"`
+}
+
+func newGuildTestApp(t *testing.T) *iris.Application {
+	initPlayerHandlerTestDB(t)
+	app := iris.New()
+	RegisterGuildRoutes(app.Party("/api/v1/guilds"), NewGuildHandler())
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func TestGuildEndpoints(t *testing.T) {
+	app := newGuildTestApp(t)
+	execTestSQL(t, "DELETE FROM guilds WHERE name = $1", "API Guild")
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id IN ($1, $2)", int64(9370), int64(9371))
+	seedCommander(t, 9370, "Guild Leader")
+	seedCommander(t, 9371, "Guild Member")
+
+	createRequest := httptest.NewRequest(http.MethodPost, "/api/v1/guilds", strings.NewReader(`{"name":"API Guild","leader_id":9370,"manifesto":"hello"}`))
+	createRequest.Header.Set("Content-Type", "application/json")
+	createResponse := httptest.NewRecorder()
+	app.ServeHTTP(createResponse, createRequest)
+	if createResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", createResponse.Code)
+	}
+	var created guildDetailResponse
+	if err := json.NewDecoder(createResponse.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	guildID := created.Data.Guild.ID
+	if guildID == 0 || len(created.Data.Members) != 1 || created.Data.Members[0].CommanderID != 9370 {
+		t.Fatalf("unexpected created guild: %+v", created.Data)
+	}
+
+	duplicateRequest := httptest.NewRequest(http.MethodPost, "/api/v1/guilds", strings.NewReader(`{"name":"api guild","leader_id":9371}`))
+	duplicateRequest.Header.Set("Content-Type", "application/json")
+	duplicateResponse := httptest.NewRecorder()
+	app.ServeHTTP(duplicateResponse, duplicateRequest)
+	if duplicateResponse.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", duplicateResponse.Code)
+	}
+
+	guildPath := fmt.Sprintf("/api/v1/guilds/%d", guildID)
+	addRequest := httptest.NewRequest(http.MethodPost, guildPath+"/members", strings.NewReader(`{"commander_id":9371}`))
+	addRequest.Header.Set("Content-Type", "application/json")
+	addResponse := httptest.NewRecorder()
+	app.ServeHTTP(addResponse, addRequest)
+	if addResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", addResponse.Code)
+	}
+
+	updateRequest := httptest.NewRequest(http.MethodPatch, guildPath, strings.NewReader(`{"announce":"news","capital_delta":300}`))
+	updateRequest.Header.Set("Content-Type", "application/json")
+	updateResponse := httptest.NewRecorder()
+	app.ServeHTTP(updateResponse, updateRequest)
+	if updateResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", updateResponse.Code)
+	}
+
+	detailRequest := httptest.NewRequest(http.MethodGet, guildPath, nil)
+	detailResponse := httptest.NewRecorder()
+	app.ServeHTTP(detailResponse, detailRequest)
+	if detailResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", detailResponse.Code)
+	}
+	var detail guildDetailResponse
+	if err := json.NewDecoder(detailResponse.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode response failed: %v", err)
+	}
+	if detail.Data.Guild.Announce != "news" || detail.Data.Guild.Capital != 300 || detail.Data.Guild.MemberCount != 2 || len(detail.Data.Members) != 2 {
+		t.Fatalf("unexpected guild detail: %+v", detail.Data)
+	}
+
+	overdrawRequest := httptest.NewRequest(http.MethodPatch, guildPath, strings.NewReader(`{"capital_delta":-500}`))
+	overdrawRequest.Header.Set("Content-Type", "application/json")
+	overdrawResponse := httptest.NewRecorder()
+	app.ServeHTTP(overdrawResponse, overdrawRequest)
+	if overdrawResponse.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", overdrawResponse.Code)
+	}
+
+	removeRequest := httptest.NewRequest(http.MethodDelete, guildPath+"/members/9371", nil)
+	removeResponse := httptest.NewRecorder()
+	app.ServeHTTP(removeResponse, removeRequest)
+	if removeResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", removeResponse.Code)
+	}
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, guildPath, nil)
+	deleteResponse := httptest.NewRecorder()
+	app.ServeHTTP(deleteResponse, deleteRequest)
+	if deleteResponse.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", deleteResponse.Code)
+	}
+
+	missingRequest := httptest.NewRequest(http.MethodGet, guildPath, nil)
+	missingResponse := httptest.NewRecorder()
+	app.ServeHTTP(missingResponse, missingRequest)
+	if missingResponse.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", missingResponse.Code)
+	}
+}
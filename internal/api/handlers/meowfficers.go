@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/answer"
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerMeowfficers godoc
+// @Summary     Get player meowfficers, meowfficer boxes and cat lodge
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerMeowfficersResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/meowfficers [get]
+func (handler *PlayerHandler) PlayerMeowfficers(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	payload, err := loadPlayerMeowfficers(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficers", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// CreatePlayerMeowfficer godoc
+// @Summary     Grant a meowfficer to a player
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Player ID"
+// @Param       payload  body  types.PlayerMeowfficerCreateRequest  true  "Meowfficer template"
+// @Success     200  {object}  PlayerMeowfficersResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/meowfficers [post]
+func (handler *PlayerHandler) CreatePlayerMeowfficer(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerMeowfficerCreateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	meowfficer, err := answer.NewMeowfficer(commanderID, req.TemplateID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "meowfficer template not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficer template", nil))
+		return
+	}
+	meowfficer.Name = req.Name
+	if err := orm.CreateMeowfficer(meowfficer); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to create meowfficer", nil))
+		return
+	}
+	payload, err := loadPlayerMeowfficers(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficers", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// UpdatePlayerMeowfficer godoc
+// @Summary     Update a player meowfficer
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id             path  int  true  "Player ID"
+// @Param       meowfficer_id  path  int  true  "Meowfficer ID"
+// @Param       payload        body  types.PlayerMeowfficerUpdateRequest  true  "Meowfficer fields"
+// @Success     200  {object}  PlayerMeowfficersResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/meowfficers/{meowfficer_id} [patch]
+func (handler *PlayerHandler) UpdatePlayerMeowfficer(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	meowfficerID, err := parsePathUint32(ctx.Params().Get("meowfficer_id"), "meowfficer id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerMeowfficerUpdateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	meowfficer, err := orm.GetMeowfficer(commanderID, meowfficerID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "meowfficer not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficer", nil))
+		return
+	}
+	if req.Level != nil {
+		meowfficer.Level = *req.Level
+	}
+	if req.Exp != nil {
+		meowfficer.Exp = *req.Exp
+	}
+	if req.IsLocked != nil {
+		meowfficer.IsLocked = *req.IsLocked
+	}
+	if req.Name != nil {
+		meowfficer.Name = *req.Name
+	}
+	if req.Abilities != nil {
+		meowfficer.Abilities = orm.ToInt64List(req.Abilities)
+	}
+	if req.UsedPt != nil {
+		meowfficer.UsedPt = *req.UsedPt
+	}
+	if req.SkillID != nil {
+		meowfficer.SkillID = *req.SkillID
+	}
+	if req.SkillExp != nil {
+		meowfficer.SkillExp = *req.SkillExp
+	}
+	if err := orm.SaveMeowfficer(meowfficer); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to save meowfficer", nil))
+		return
+	}
+	payload, err := loadPlayerMeowfficers(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficers", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// DeletePlayerMeowfficer godoc
+// @Summary     Delete a player meowfficer
+// @Description Meowfficers assigned to a fleet or training in the cat lodge can't be deleted.
+// @Tags        Players
+// @Produce     json
+// @Param       id             path  int  true  "Player ID"
+// @Param       meowfficer_id  path  int  true  "Meowfficer ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/meowfficers/{meowfficer_id} [delete]
+func (handler *PlayerHandler) DeletePlayerMeowfficer(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	meowfficerID, err := parsePathUint32(ctx.Params().Get("meowfficer_id"), "meowfficer id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	busy, err := orm.GetBusyMeowfficerIDs(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficers", nil))
+		return
+	}
+	if _, ok := busy[meowfficerID]; ok {
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "meowfficer is assigned", nil))
+		return
+	}
+	if err := orm.DeleteMeowfficer(commanderID, meowfficerID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			_ = ctx.JSON(response.Error("not_found", "meowfficer not found", nil))
+			return
+		}
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to delete meowfficer", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+// CreatePlayerMeowfficerBox godoc
+// @Summary     Grant a meowfficer box to a player
+// @Tags        Players
+// @Accept      json
+// @Produce     json
+// @Param       id       path  int  true  "Player ID"
+// @Param       payload  body  types.PlayerMeowfficerBoxRequest  true  "Box pool"
+// @Success     200  {object}  PlayerMeowfficersResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/meowfficers/boxes [post]
+func (handler *PlayerHandler) CreatePlayerMeowfficerBox(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	var req types.PlayerMeowfficerBoxRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid request", nil))
+		return
+	}
+	if err := handler.Validate.Struct(req); err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "validation failed", validationErrors(err)))
+		return
+	}
+	if err := orm.CreateMeowfficerBox(&orm.MeowfficerBox{CommanderID: commanderID, PoolID: req.PoolID}); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to create meowfficer box", nil))
+		return
+	}
+	payload, err := loadPlayerMeowfficers(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load meowfficers", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+func loadPlayerMeowfficers(commanderID uint32) (types.PlayerMeowfficersResponse, error) {
+	meowfficers, err := orm.ListMeowfficers(commanderID)
+	if err != nil {
+		return types.PlayerMeowfficersResponse{}, err
+	}
+	boxes, err := orm.ListMeowfficerBoxes(commanderID)
+	if err != nil {
+		return types.PlayerMeowfficersResponse{}, err
+	}
+	home, err := orm.GetOrCreateMeowfficerHome(commanderID)
+	if err != nil {
+		return types.PlayerMeowfficersResponse{}, err
+	}
+	slots, err := orm.ListMeowfficerHomeSlots(commanderID)
+	if err != nil {
+		return types.PlayerMeowfficersResponse{}, err
+	}
+	payload := types.PlayerMeowfficersResponse{
+		Meowfficers: make([]types.PlayerMeowfficerEntry, 0, len(meowfficers)),
+		Boxes:       make([]types.PlayerMeowfficerBoxEntry, 0, len(boxes)),
+		Home: types.PlayerMeowfficerHome{
+			Level:      home.Level,
+			Exp:        home.Exp,
+			CleanTime:  home.CleanTime,
+			UsageCount: home.UsageCount,
+			Slots:      make([]types.PlayerMeowfficerHomeSlot, 0, len(slots)),
+		},
+	}
+	for _, meowfficer := range meowfficers {
+		payload.Meowfficers = append(payload.Meowfficers, types.PlayerMeowfficerEntry{
+			ID:            meowfficer.ID,
+			TemplateID:    meowfficer.TemplateID,
+			Level:         meowfficer.Level,
+			Exp:           meowfficer.Exp,
+			IsLocked:      meowfficer.IsLocked,
+			Name:          meowfficer.Name,
+			Abilities:     orm.ToUint32List(meowfficer.Abilities),
+			AbilityOrigin: orm.ToUint32List(meowfficer.AbilityOrigin),
+			UsedPt:        meowfficer.UsedPt,
+			SkillID:       meowfficer.SkillID,
+			SkillExp:      meowfficer.SkillExp,
+		})
+	}
+	for _, box := range boxes {
+		payload.Boxes = append(payload.Boxes, types.PlayerMeowfficerBoxEntry{
+			ID:         box.ID,
+			PoolID:     box.PoolID,
+			BeginTime:  box.BeginTime,
+			FinishTime: box.FinishTime,
+		})
+	}
+	for _, slot := range slots {
+		payload.Home.Slots = append(payload.Home.Slots, types.PlayerMeowfficerHomeSlot{
+			SlotID:       slot.SlotID,
+			MeowfficerID: slot.MeowfficerID,
+			Style:        slot.Style,
+			ExpTime:      slot.ExpTime,
+		})
+	}
+	return payload, nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+)
+
+type playerMeowfficersResponse struct {
+	OK   bool                            `json:"ok"`
+	Data types.PlayerMeowfficersResponse `json:"data"`
+}
+
+func TestPlayerMeowfficersEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9371))
+	seedCommander(t, 9371, "Meowfficer Tester")
+	seedConfigEntry(t, "ShareCfg/commander_data_template.json", "101", `{"id":101,"ability":[11],"skill_id":501}`)
+
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		var request *http.Request
+		if body == "" {
+			request = httptest.NewRequest(method, path, nil)
+		} else {
+			request = httptest.NewRequest(method, path, strings.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if response := serve(http.MethodPost, "/api/v1/players/9371/meowfficers", `{"template_id":999}`); response.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", response.Code)
+	}
+	created := serve(http.MethodPost, "/api/v1/players/9371/meowfficers", `{"template_id":101,"name":"Tiger"}`)
+	if created.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", created.Code)
+	}
+	var payload playerMeowfficersResponse
+	if err := json.Unmarshal(created.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data.Meowfficers) != 1 || payload.Data.Meowfficers[0].SkillID != 501 || payload.Data.Meowfficers[0].Name != "Tiger" {
+		t.Fatalf("unexpected meowfficers: %+v", payload.Data)
+	}
+	path := strconv.FormatUint(uint64(payload.Data.Meowfficers[0].ID), 10)
+
+	if response := serve(http.MethodPost, "/api/v1/players/9371/meowfficers/boxes", `{"pool_id":3}`); response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+	updated := serve(http.MethodPatch, "/api/v1/players/9371/meowfficers/"+path, `{"level":5,"is_locked":true}`)
+	if updated.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", updated.Code)
+	}
+	payload = playerMeowfficersResponse{}
+	if err := json.Unmarshal(updated.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Data.Meowfficers[0].Level != 5 || !payload.Data.Meowfficers[0].IsLocked || len(payload.Data.Boxes) != 1 || payload.Data.Boxes[0].PoolID != 3 {
+		t.Fatalf("unexpected meowfficers: %+v", payload.Data)
+	}
+
+	if response := serve(http.MethodDelete, "/api/v1/players/9371/meowfficers/"+path, ""); response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", response.Code)
+	}
+	if response := serve(http.MethodDelete, "/api/v1/players/9371/meowfficers/"+path, ""); response.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", response.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/payment"
+)
+
+const paymentOrderDefaultLimit = 50
+
+type PaymentHandler struct{}
+
+func NewPaymentHandler() *PaymentHandler {
+	return &PaymentHandler{}
+}
+
+func RegisterPaymentRoutes(party iris.Party, handler *PaymentHandler) {
+	party.Get("/orders", handler.SearchOrders)
+	party.Post("/orders/{id:uint}/approve", handler.ApproveOrder)
+	party.Post("/orders/{id:uint}/refund", handler.RefundOrder)
+}
+
+// SearchOrders godoc
+// @Summary     Search charge orders
+// @Description Returns the payment ledger, newest orders first.
+// @Tags        Payments
+// @Produce     json
+// @Param       commander_id  query  int     false  "Commander ID"
+// @Param       status        query  string  false  "Order status (pending, paid, delivered, failed, refunded)"
+// @Param       offset        query  int     false  "Pagination offset"
+// @Param       limit         query  int     false  "Pagination limit"
+// @Success     200  {object}  PaymentOrderListResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/payments/orders [get]
+func (handler *PaymentHandler) SearchOrders(ctx iris.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = paymentOrderDefaultLimit
+	}
+	search := orm.PaymentOrderSearch{
+		Status: strings.TrimSpace(ctx.URLParam("status")),
+		Offset: pagination.Offset,
+		Limit:  pagination.Limit,
+	}
+	switch search.Status {
+	case "", orm.PaymentStatusPending, orm.PaymentStatusPaid, orm.PaymentStatusDelivered, orm.PaymentStatusFailed, orm.PaymentStatusRefunded:
+	default:
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid status", nil))
+		return
+	}
+	commanderID, err := parseOptionalUint32(ctx.URLParam("commander_id"), "commander_id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if commanderID != nil {
+		search.CommanderID = *commanderID
+	}
+	orders, total, err := orm.SearchPaymentOrders(search)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to search payment orders", nil))
+		return
+	}
+	payload := types.PaymentOrderListResponse{
+		Orders: make([]types.PaymentOrder, 0, len(orders)),
+		Meta: types.PaginationMeta{
+			Offset: pagination.Offset,
+			Limit:  pagination.Limit,
+			Total:  total,
+		},
+	}
+	for i := range orders {
+		payload.Orders = append(payload.Orders, paymentOrderPayload(&orders[i]))
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ApproveOrder godoc
+// @Summary     Approve charge order
+// @Description Marks a pending order as paid, as a payment provider would. The commander gets the goods once the client confirms the order.
+// @Tags        Payments
+// @Produce     json
+// @Param       id   path  int  true  "Order ID"
+// @Success     200  {object}  PaymentOrderResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/payments/orders/{id}/approve [post]
+func (handler *PaymentHandler) ApproveOrder(ctx iris.Context) {
+	id, err := parsePathUint32(ctx.Params().Get("id"), "id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if _, err := orm.GetPaymentOrder(id); err != nil {
+		writePaymentOrderError(ctx, err, "failed to load payment order")
+		return
+	}
+	order, err := payment.Approve(id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusConflict)
+			_ = ctx.JSON(response.Error("conflict", "order is not pending", nil))
+			return
+		}
+		writePaymentOrderError(ctx, err, "failed to approve payment order")
+		return
+	}
+	_ = ctx.JSON(response.Success(paymentOrderPayload(order)))
+}
+
+// RefundOrder godoc
+// @Summary     Refund charge order
+// @Description Refunds a delivered order. The commander keeps the goods but has to pay the refund back before charging them again.
+// @Tags        Payments
+// @Produce     json
+// @Param       id   path  int  true  "Order ID"
+// @Success     200  {object}  PaymentOrderResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     409  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/payments/orders/{id}/refund [post]
+func (handler *PaymentHandler) RefundOrder(ctx iris.Context) {
+	id, err := parsePathUint32(ctx.Params().Get("id"), "id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return
+	}
+	if _, err := orm.GetPaymentOrder(id); err != nil {
+		writePaymentOrderError(ctx, err, "failed to load payment order")
+		return
+	}
+	order, err := payment.Refund(id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			ctx.StatusCode(iris.StatusConflict)
+			_ = ctx.JSON(response.Error("conflict", "order is not delivered", nil))
+			return
+		}
+		writePaymentOrderError(ctx, err, "failed to refund payment order")
+		return
+	}
+	_ = ctx.JSON(response.Success(paymentOrderPayload(order)))
+}
+
+func writePaymentOrderError(ctx iris.Context, err error, message string) {
+	switch {
+	case db.IsNotFound(err):
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "payment order not found", nil))
+	case errors.Is(err, payment.ErrUnknownGoods):
+		ctx.StatusCode(iris.StatusConflict)
+		_ = ctx.JSON(response.Error("conflict", "goods of the order no longer exist", nil))
+	default:
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", message, nil))
+	}
+}
+
+func paymentOrderPayload(order *orm.PaymentOrder) types.PaymentOrder {
+	return types.PaymentOrder{
+		ID:          order.ID,
+		PayID:       order.PayID,
+		CommanderID: order.CommanderID,
+		ShopID:      order.ShopID,
+		Kind:        order.Kind,
+		Provider:    order.Provider,
+		Status:      order.Status,
+		Money:       order.Money,
+		Gem:         order.Gem,
+		GemFree:     order.GemFree,
+		FailCode:    order.FailCode,
+		CreatedAt:   order.CreatedAt.UTC().Format(time.RFC3339),
+		PaidAt:      formatOptionalTime(order.PaidAt),
+		DeliveredAt: formatOptionalTime(order.DeliveredAt),
+		RefundedAt:  formatOptionalTime(order.RefundedAt),
+		RepaidAt:    formatOptionalTime(order.RepaidAt),
+	}
+}
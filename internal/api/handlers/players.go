@@ -75,6 +75,25 @@ func RegisterPlayerRoutes(party iris.Party, handler *PlayerHandler) {
 	party.Get("/{id:uint}/love-letter", handler.PlayerLoveLetterState)
 	party.Patch("/{id:uint}/love-letter", handler.UpdatePlayerLoveLetterState)
 	party.Delete("/{id:uint}/love-letter", handler.DeletePlayerLoveLetterState)
+	party.Get("/{id:uint}/friends", handler.PlayerFriends)
+	party.Post("/{id:uint}/friends", handler.AddPlayerFriend)
+	party.Delete("/{id:uint}/friends/{friend_id:uint}", handler.DeletePlayerFriend)
+	party.Post("/{id:uint}/friends/blocks", handler.AddPlayerFriendBlock)
+	party.Delete("/{id:uint}/friends/blocks/{blocked_id:uint}", handler.DeletePlayerFriendBlock)
+	party.Get("/{id:uint}/blueprints", handler.PlayerBlueprints)
+	party.Put("/{id:uint}/blueprints/{blueprint_id:uint}", handler.UpdatePlayerBlueprint)
+	party.Get("/{id:uint}/meowfficers", handler.PlayerMeowfficers)
+	party.Post("/{id:uint}/meowfficers", handler.CreatePlayerMeowfficer)
+	party.Post("/{id:uint}/meowfficers/boxes", handler.CreatePlayerMeowfficerBox)
+	party.Patch("/{id:uint}/meowfficers/{meowfficer_id:uint}", handler.UpdatePlayerMeowfficer)
+	party.Delete("/{id:uint}/meowfficers/{meowfficer_id:uint}", handler.DeletePlayerMeowfficer)
+	party.Get("/{id:uint}/world", handler.PlayerWorld)
+	party.Delete("/{id:uint}/world", handler.ResetPlayerWorld)
+	party.Get("/{id:uint}/cheater-marks", handler.PlayerCheaterMarks)
+	party.Delete("/{id:uint}/cheater-marks", handler.ClearPlayerCheaterMarks)
+	party.Get("/{id:uint}/activities/progress", handler.PlayerActivityProgress)
+	party.Delete("/{id:uint}/activities/progress/{activity_id:uint}", handler.ResetPlayerActivityProgress)
+	party.Get("/{id:uint}/arena", handler.PlayerArena)
 	party.Get("/{id:uint}/remaster", handler.PlayerRemasterState)
 	party.Patch("/{id:uint}/remaster", handler.UpdatePlayerRemasterState)
 	party.Get("/{id:uint}/remaster/progress", handler.PlayerRemasterProgress)
@@ -1988,21 +2007,35 @@ func (handler *PlayerHandler) UpdatePlayerShip(ctx iris.Context) {
 		owned.SecretaryPhantomID = *req.SecretaryPhantomID
 		updated = true
 	}
-	if !updated {
+	if !updated && req.InOnsen == nil {
 		ctx.StatusCode(iris.StatusBadRequest)
 		_ = ctx.JSON(response.Error("bad_request", "no updates provided", nil))
 		return
 	}
 
-	if err := owned.Update(); err != nil {
-		if errors.Is(err, db.ErrNotFound) {
-			ctx.StatusCode(iris.StatusNotFound)
-			_ = ctx.JSON(response.Error("not_found", "ship not owned", nil))
+	if updated {
+		if err := owned.Update(); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				ctx.StatusCode(iris.StatusNotFound)
+				_ = ctx.JSON(response.Error("not_found", "ship not owned", nil))
+				return
+			}
+			ctx.StatusCode(iris.StatusInternalServerError)
+			_ = ctx.JSON(response.Error("internal_error", "failed to update ship", nil))
+			return
+		}
+	}
+	if req.InOnsen != nil {
+		if err := orm.SetOwnedShipOnsen(commander.CommanderID, ownedID, *req.InOnsen); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				ctx.StatusCode(iris.StatusNotFound)
+				_ = ctx.JSON(response.Error("not_found", "ship not owned", nil))
+				return
+			}
+			ctx.StatusCode(iris.StatusInternalServerError)
+			_ = ctx.JSON(response.Error("internal_error", "failed to update ship", nil))
 			return
 		}
-		ctx.StatusCode(iris.StatusInternalServerError)
-		_ = ctx.JSON(response.Error("internal_error", "failed to update ship", nil))
-		return
 	}
 
 	payload := buildOwnedShipEntry(*owned)
@@ -5933,7 +5966,7 @@ func (handler *PlayerHandler) KickPlayer(ctx iris.Context) {
 	}
 
 	client := findCommanderClient(commanderID)
-	if client == nil {
+	if client == nil && (connection.BelfastInstance == nil || !connection.BelfastInstance.IsCommanderOnline(commanderID)) {
 		ctx.StatusCode(iris.StatusNotFound)
 		_ = ctx.JSON(response.Error("not_found", "player not online", nil))
 		return
@@ -5957,6 +5990,12 @@ func (handler *PlayerHandler) KickPlayer(ctx iris.Context) {
 		}
 	}
 
+	if client == nil {
+		// connected to another node of the cluster
+		connection.BelfastInstance.DisconnectCommander(commanderID, reason, nil)
+		_ = ctx.JSON(response.Success(types.KickPlayerResponse{Disconnected: true}))
+		return
+	}
 	if err := client.Disconnect(reason); err != nil {
 		logger.LogEvent("API", "Kick", fmt.Sprintf("failed to send disconnect: %v", err), logger.LOG_LEVEL_ERROR)
 	}
@@ -6360,6 +6399,7 @@ func (handler *PlayerHandler) SendMail(ctx iris.Context) {
 		_ = ctx.JSON(response.Error("internal_error", "failed to send mail", nil))
 		return
 	}
+	notifyMailboxUpdate(commander.CommanderID)
 
 	_ = ctx.JSON(response.Success(nil))
 }
@@ -300,8 +300,7 @@ func notifyMailboxUpdate(commanderID uint32) {
 	if server == nil {
 		return
 	}
-	client, ok := server.FindClientByCommander(commanderID)
-	if !ok {
+	if !server.IsCommanderOnline(commanderID) {
 		return
 	}
 	totalCount, unreadCount, err := orm.GetMailboxCounts(commanderID)
@@ -312,5 +311,5 @@ func notifyMailboxUpdate(commanderID uint32) {
 		UnreadNumber: proto.Uint32(unreadCount),
 		TotalNumber:  proto.Uint32(totalCount),
 	}
-	_, _, _ = client.SendMessage(30001, &payload)
+	server.PushToCommander(commanderID, 30001, &payload)
 }
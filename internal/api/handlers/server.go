@@ -16,6 +16,7 @@ import (
 	"github.com/ggmolly/belfast/internal/buildinfo"
 	"github.com/ggmolly/belfast/internal/config"
 	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/gamedata"
 	"github.com/ggmolly/belfast/internal/region"
 )
 
@@ -214,6 +215,7 @@ func (handler *ServerHandler) Metrics(ctx iris.Context) {
 		HandlerErrors: metrics.HandlerErrors,
 		WriteErrors:   metrics.WriteErrors,
 		PacketsPerSec: metrics.PacketsPerSec,
+		GameDataCache: gameDataCacheMetrics(gamedata.Default.Stats()),
 	}
 	_ = ctx.JSON(response.Success(payload))
 }
@@ -343,6 +345,21 @@ func commanderID(client *connection.Client) uint32 {
 	return client.Commander.CommanderID
 }
 
+func gameDataCacheMetrics(stats gamedata.Stats) types.GameDataCacheMetrics {
+	metrics := types.GameDataCacheMetrics{
+		Loaded:     stats.Loaded,
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Reloads:    stats.Reloads,
+		Categories: stats.Categories,
+		Entries:    stats.Entries,
+	}
+	if stats.Loaded {
+		metrics.LoadedAt = stats.LoadedAt.Format(time.RFC3339)
+	}
+	return metrics
+}
+
 type metricsTotals struct {
 	QueueMax      int
 	QueueBlocks   uint64
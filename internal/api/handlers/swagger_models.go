@@ -363,6 +363,41 @@ type PlayerLoveLetterStateResponseDoc struct {
 	Data types.PlayerLoveLetterStateResponse `json:"data"`
 }
 
+type PlayerFriendsResponseDoc struct {
+	OK   bool                        `json:"ok"`
+	Data types.PlayerFriendsResponse `json:"data"`
+}
+
+type PlayerBlueprintsResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.PlayerBlueprintsResponse `json:"data"`
+}
+
+type PlayerMeowfficersResponseDoc struct {
+	OK   bool                            `json:"ok"`
+	Data types.PlayerMeowfficersResponse `json:"data"`
+}
+
+type PlayerWorldResponseDoc struct {
+	OK   bool                      `json:"ok"`
+	Data types.PlayerWorldResponse `json:"data"`
+}
+
+type PlayerCheaterMarksResponseDoc struct {
+	OK   bool                             `json:"ok"`
+	Data types.PlayerCheaterMarksResponse `json:"data"`
+}
+
+type PlayerActivityProgressResponseDoc struct {
+	OK   bool                                 `json:"ok"`
+	Data types.PlayerActivityProgressResponse `json:"data"`
+}
+
+type PlayerArenaResponseDoc struct {
+	OK   bool                   `json:"ok"`
+	Data types.PlayerArenaState `json:"data"`
+}
+
 type PlayerRemasterStateResponseDoc struct {
 	OK   bool                              `json:"ok"`
 	Data types.PlayerRemasterStateResponse `json:"data"`
@@ -557,6 +592,21 @@ type ActivityAllowlistResponseDoc struct {
 	Data types.ActivityAllowlistPayload `json:"data"`
 }
 
+type ActivityCalendarResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.ActivityCalendarResponse `json:"data"`
+}
+
+type ActivityOverrideListResponseDoc struct {
+	OK   bool                               `json:"ok"`
+	Data types.ActivityOverrideListResponse `json:"data"`
+}
+
+type ActivityOverrideResponseDoc struct {
+	OK   bool                          `json:"ok"`
+	Data types.ActivityOverridePayload `json:"data"`
+}
+
 type PlayerShoppingStreetResponseDoc struct {
 	OK   bool                         `json:"ok"`
 	Data types.ShoppingStreetResponse `json:"data"`
@@ -631,6 +681,16 @@ type NoticeActiveResponseDoc struct {
 	Data []types.NoticeSummary `json:"data"`
 }
 
+type GuildListResponseDoc struct {
+	OK   bool                    `json:"ok"`
+	Data types.GuildListResponse `json:"data"`
+}
+
+type GuildDetailResponseDoc struct {
+	OK   bool                      `json:"ok"`
+	Data types.GuildDetailResponse `json:"data"`
+}
+
 type ExchangeCodeListResponseDoc struct {
 	OK   bool                           `json:"ok"`
 	Data types.ExchangeCodeListResponse `json:"data"`
@@ -646,6 +706,26 @@ type ExchangeCodeRedeemListResponseDoc struct {
 	Data types.ExchangeCodeRedeemListResponse `json:"data"`
 }
 
+type BuildBannerListResponseDoc struct {
+	OK   bool                          `json:"ok"`
+	Data types.BuildBannerListResponse `json:"data"`
+}
+
+type BuildBannerSummaryResponseDoc struct {
+	OK   bool                     `json:"ok"`
+	Data types.BuildBannerSummary `json:"data"`
+}
+
+type BuildRollLogListResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.BuildRollLogListResponse `json:"data"`
+}
+
+type BuildBannerStatsResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.BuildBannerStatsResponse `json:"data"`
+}
+
 type ServerStatusResponseDoc struct {
 	OK   bool                       `json:"ok"`
 	Data types.ServerStatusResponse `json:"data"`
@@ -817,3 +897,73 @@ type KickPlayerResponseDoc struct {
 	OK   bool                     `json:"ok"`
 	Data types.KickPlayerResponse `json:"data"`
 }
+
+type ArenaSeasonResponseDoc struct {
+	OK   bool                      `json:"ok"`
+	Data types.ArenaSeasonResponse `json:"data"`
+}
+
+type ArenaSettlementResponseDoc struct {
+	OK   bool                          `json:"ok"`
+	Data types.ArenaSettlementResponse `json:"data"`
+}
+
+type ArenaLeaderboardResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.ArenaLeaderboardResponse `json:"data"`
+}
+
+type ChatMessageListResponseDoc struct {
+	OK   bool                          `json:"ok"`
+	Data types.ChatMessageListResponse `json:"data"`
+}
+
+type ChatMuteResponseDoc struct {
+	OK   bool           `json:"ok"`
+	Data types.ChatMute `json:"data"`
+}
+
+type ChatMuteListResponseDoc struct {
+	OK   bool                       `json:"ok"`
+	Data types.ChatMuteListResponse `json:"data"`
+}
+
+type PaymentOrderResponseDoc struct {
+	OK   bool               `json:"ok"`
+	Data types.PaymentOrder `json:"data"`
+}
+
+type PaymentOrderListResponseDoc struct {
+	OK   bool                           `json:"ok"`
+	Data types.PaymentOrderListResponse `json:"data"`
+}
+
+type DropTableSummaryResponseDoc struct {
+	OK   bool                   `json:"ok"`
+	Data types.DropTableSummary `json:"data"`
+}
+
+type DropTableListResponseDoc struct {
+	OK   bool                        `json:"ok"`
+	Data types.DropTableListResponse `json:"data"`
+}
+
+type DropTableSimulationResponseDoc struct {
+	OK   bool                              `json:"ok"`
+	Data types.DropTableSimulationResponse `json:"data"`
+}
+
+type DropBonusResponseDoc struct {
+	OK   bool            `json:"ok"`
+	Data types.DropBonus `json:"data"`
+}
+
+type DropBonusListResponseDoc struct {
+	OK   bool                        `json:"ok"`
+	Data types.DropBonusListResponse `json:"data"`
+}
+
+type ThemePreviewResponseDoc struct {
+	OK   bool               `json:"ok"`
+	Data types.ThemePreview `json:"data"`
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/objectstore"
+)
+
+// Header carrying the SecurityToken of SC_19104, as sent by OSS clients.
+const themePreviewTokenHeader = "X-Oss-Security-Token"
+
+type ThemePreviewHandler struct{}
+
+func NewThemePreviewHandler() *ThemePreviewHandler {
+	return &ThemePreviewHandler{}
+}
+
+func RegisterThemePreviewRoutes(party iris.Party, handler *ThemePreviewHandler) {
+	party.Get("/{commander_id:uint}/{md5:string}", handler.Download)
+	party.Put("/{commander_id:uint}/{md5:string}", handler.Upload)
+}
+
+// Download godoc
+// @Summary     Download backyard theme preview
+// @Description Serves a preview kept by the local storage provider. Other providers serve previews from their bucket.
+// @Tags        ThemePreviews
+// @Produce     octet-stream
+// @Param       commander_id  path  int     true  "Commander ID"
+// @Param       md5           path  string  true  "Preview md5"
+// @Success     200
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Router      /api/v1/theme-previews/{commander_id}/{md5} [get]
+func (handler *ThemePreviewHandler) Download(ctx iris.Context) {
+	local, key, ok := themePreviewTarget(ctx)
+	if !ok {
+		return
+	}
+	file, err := local.Open(key)
+	if err != nil {
+		writeThemePreviewError(ctx, err)
+		return
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		writeThemePreviewError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(ctx.ResponseWriter(), ctx.Request(), "", stat.ModTime(), file)
+}
+
+// Upload godoc
+// @Summary     Upload backyard theme preview
+// @Description Stores a preview with the local storage provider. The request carries the SecurityToken of SC_19104 in the X-Oss-Security-Token header (or the token query parameter), and the body must hash to the md5 of the path.
+// @Tags        ThemePreviews
+// @Accept      octet-stream
+// @Produce     json
+// @Param       commander_id  path    int     true   "Commander ID"
+// @Param       md5           path    string  true   "Preview md5"
+// @Param       token         query   string  false  "Upload token"
+// @Success     200  {object}  ThemePreviewResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     403  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     413  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/theme-previews/{commander_id}/{md5} [put]
+func (handler *ThemePreviewHandler) Upload(ctx iris.Context) {
+	local, key, ok := themePreviewTarget(ctx)
+	if !ok {
+		return
+	}
+	token := ctx.GetHeader(themePreviewTokenHeader)
+	if token == "" {
+		token = ctx.URLParam("token")
+	}
+	if err := local.Authorize(token, key, time.Now()); err != nil {
+		ctx.StatusCode(iris.StatusForbidden)
+		_ = ctx.JSON(response.Error("forbidden", err.Error(), nil))
+		return
+	}
+	md5 := strings.ToLower(ctx.Params().Get("md5"))
+	info, err := local.Put(key, ctx.Request().Body, md5)
+	if err != nil {
+		writeThemePreviewError(ctx, err)
+		return
+	}
+	payload := types.ThemePreview{Key: key, Size: info.Size, MD5: info.MD5}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// themePreviewTarget resolves the local store and the key of the request,
+// writing the error response when there is none.
+func themePreviewTarget(ctx iris.Context) (*objectstore.Local, string, bool) {
+	local, ok := objectstore.Current().(*objectstore.Local)
+	if !ok {
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "local storage is disabled", nil))
+		return nil, "", false
+	}
+	commanderID, err := parsePathUint32(ctx.Params().Get("commander_id"), "commander_id")
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+		return nil, "", false
+	}
+	md5 := strings.ToLower(ctx.Params().Get("md5"))
+	if !objectstore.ValidMd5(md5) {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid md5", nil))
+		return nil, "", false
+	}
+	return local, objectstore.PreviewKey(commanderID, md5), true
+}
+
+func writeThemePreviewError(ctx iris.Context, err error) {
+	switch {
+	case errors.Is(err, objectstore.ErrNotFound):
+		ctx.StatusCode(iris.StatusNotFound)
+		_ = ctx.JSON(response.Error("not_found", "preview not found", nil))
+	case errors.Is(err, objectstore.ErrMd5Mismatch), errors.Is(err, objectstore.ErrInvalidKey):
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", err.Error(), nil))
+	case errors.Is(err, objectstore.ErrObjectTooLarge):
+		ctx.StatusCode(iris.StatusRequestEntityTooLarge)
+		_ = ctx.JSON(response.Error("too_large", err.Error(), nil))
+	default:
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to access preview", nil))
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/objectstore"
+)
+
+func newThemePreviewTestApp(t *testing.T) *iris.Application {
+	if err := objectstore.Configure(config.StorageConfig{Provider: objectstore.LocalName, Dir: t.TempDir(), Secret: "test"}); err != nil {
+		t.Fatalf("configure storage: %v", err)
+	}
+	t.Cleanup(func() { _ = objectstore.Configure(config.StorageConfig{}) })
+	app := iris.New()
+	RegisterThemePreviewRoutes(app.Party("/api/v1/theme-previews"), NewThemePreviewHandler())
+	if err := app.Build(); err != nil {
+		t.Fatalf("build app: %v", err)
+	}
+	return app
+}
+
+func serveThemePreviewRequest(app *iris.Application, method string, path string, token string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		request.Header.Set(themePreviewTokenHeader, token)
+	}
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestThemePreviewEndpoints(t *testing.T) {
+	app := newThemePreviewTestApp(t)
+	creds, err := objectstore.Current().Credentials(t.Context(), 42, time.Now())
+	if err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	sum := md5.Sum([]byte("preview"))
+	path := "/api/v1/theme-previews/42/" + hex.EncodeToString(sum[:])
+
+	if response := serveThemePreviewRequest(app, http.MethodPut, path, "", "preview"); response.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 without token, got %d", response.Code)
+	}
+	if response := serveThemePreviewRequest(app, http.MethodPut, "/api/v1/theme-previews/43/"+hex.EncodeToString(sum[:]), creds.SecurityToken, "preview"); response.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403 for another commander, got %d", response.Code)
+	}
+	if response := serveThemePreviewRequest(app, http.MethodPut, path, creds.SecurityToken, "tampered"); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 on md5 mismatch, got %d", response.Code)
+	}
+	if response := serveThemePreviewRequest(app, http.MethodGet, path, "", ""); response.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 before upload, got %d", response.Code)
+	}
+	if response := serveThemePreviewRequest(app, http.MethodPut, path, creds.SecurityToken, "preview"); response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", response.Code, response.Body.String())
+	}
+	response := serveThemePreviewRequest(app, http.MethodGet, path, "", "")
+	if response.Code != http.StatusOK || response.Body.String() != "preview" {
+		t.Fatalf("expected preview content, got %d %q", response.Code, response.Body.String())
+	}
+	if response := serveThemePreviewRequest(app, http.MethodGet, "/api/v1/theme-previews/42/nope", "", ""); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for invalid md5, got %d", response.Code)
+	}
+}
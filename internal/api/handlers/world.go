@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/response"
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// PlayerWorld godoc
+// @Summary     Get player Operation Siren state
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  PlayerWorldResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/world [get]
+func (handler *PlayerHandler) PlayerWorld(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	payload, err := loadPlayerWorld(commanderID)
+	if err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to load world", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(payload))
+}
+
+// ResetPlayerWorld godoc
+// @Summary     Reset player Operation Siren state
+// @Description Maps, fleets, items and port purchases are wiped; the player has to enter the world again.
+// @Tags        Players
+// @Produce     json
+// @Param       id   path  int  true  "Player ID"
+// @Success     200  {object}  OKResponseDoc
+// @Failure     400  {object}  APIErrorResponseDoc
+// @Failure     404  {object}  APIErrorResponseDoc
+// @Failure     500  {object}  APIErrorResponseDoc
+// @Router      /api/v1/players/{id}/world [delete]
+func (handler *PlayerHandler) ResetPlayerWorld(ctx iris.Context) {
+	commanderID, err := parseCommanderID(ctx)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		_ = ctx.JSON(response.Error("bad_request", "invalid id", nil))
+		return
+	}
+	if err := orm.CommanderExists(commanderID); err != nil {
+		writeCommanderError(ctx, err)
+		return
+	}
+	if err := orm.ResetWorld(commanderID); err != nil {
+		ctx.StatusCode(iris.StatusInternalServerError)
+		_ = ctx.JSON(response.Error("internal_error", "failed to reset world", nil))
+		return
+	}
+	_ = ctx.JSON(response.Success(nil))
+}
+
+func loadPlayerWorld(commanderID uint32) (types.PlayerWorldResponse, error) {
+	world, err := orm.GetOrCreateWorld(commanderID)
+	if err != nil {
+		return types.PlayerWorldResponse{}, err
+	}
+	maps, err := orm.ListWorldMaps(commanderID)
+	if err != nil {
+		return types.PlayerWorldResponse{}, err
+	}
+	groups, err := orm.ListWorldGroups(commanderID)
+	if err != nil {
+		return types.PlayerWorldResponse{}, err
+	}
+	items, err := orm.ListWorldItems(commanderID)
+	if err != nil {
+		return types.PlayerWorldResponse{}, err
+	}
+	payload := types.PlayerWorldResponse{
+		Camp:             world.Camp,
+		MapID:            world.MapID,
+		Progress:         world.Progress,
+		ActionPower:      world.ActionPower,
+		ActionPowerExtra: world.ActionPowerExtra,
+		LastRecoverTime:  world.LastRecoverTime,
+		StepCount:        world.StepCount,
+		Maps:             make([]types.PlayerWorldMap, 0, len(maps)),
+		Groups:           make([]types.PlayerWorldGroup, 0, len(groups)),
+		Items:            make([]types.PlayerWorldItem, 0, len(items)),
+	}
+	for _, worldMap := range maps {
+		payload.Maps = append(payload.Maps, types.PlayerWorldMap{MapID: worldMap.MapID, IsCleared: worldMap.IsCleared})
+	}
+	for _, group := range groups {
+		payload.Groups = append(payload.Groups, types.PlayerWorldGroup{
+			GroupID:  group.GroupID,
+			ShipList: orm.ToUint32List(group.ShipList),
+			Row:      group.Row,
+			Column:   group.Column,
+		})
+	}
+	for _, item := range items {
+		payload.Items = append(payload.Items, types.PlayerWorldItem{ItemID: item.ItemID, Count: item.Count})
+	}
+	return payload, nil
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/api/types"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type playerWorldResponse struct {
+	OK   bool                      `json:"ok"`
+	Data types.PlayerWorldResponse `json:"data"`
+}
+
+func TestPlayerWorldEndpoints(t *testing.T) {
+	app := newPlayerHandlerTestApp(t)
+	execTestSQL(t, "DELETE FROM commanders WHERE commander_id = $1", int64(9372))
+	seedCommander(t, 9372, "World Tester")
+	if err := orm.SaveWorld(&orm.World{CommanderID: 9372, Camp: 1, MapID: 3, ActionPower: 120}); err != nil {
+		t.Fatalf("seed world: %v", err)
+	}
+	if err := orm.AddWorldItem(9372, 50, 2); err != nil {
+		t.Fatalf("seed world item: %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/players/9372/world", nil)
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var payload playerWorldResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Data.Camp != 1 || payload.Data.ActionPower != 120 || len(payload.Data.Items) != 1 {
+		t.Fatalf("unexpected world: %+v", payload.Data)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/v1/players/9372/world", nil)
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	items, err := orm.ListWorldItems(9372)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected reset to clear items, got %v (%v)", items, err)
+	}
+}
@@ -110,6 +110,7 @@ func GetSession(ctx iris.Context) (*orm.Session, bool) {
 var publicRoutePrefixes = []string{
 	"/swagger",
 	"/api/v1/registration/",
+	"/api/v1/theme-previews/",
 }
 
 var publicRouteMethods = map[string]map[string]struct{}{
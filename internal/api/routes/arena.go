@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+	"github.com/ggmolly/belfast/internal/api/middleware"
+	"github.com/ggmolly/belfast/internal/authz"
+)
+
+func RegisterArena(app *iris.Application) {
+	party := app.Party("/api/v1/arena")
+	party.Use(middleware.RequirePermissionAny(authz.PermArena))
+	handler := handlers.NewArenaHandler()
+	handlers.RegisterArenaRoutes(party, handler)
+}
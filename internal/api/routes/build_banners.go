@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+	"github.com/ggmolly/belfast/internal/api/middleware"
+	"github.com/ggmolly/belfast/internal/authz"
+)
+
+func RegisterBuildBanners(app *iris.Application) {
+	party := app.Party("/api/v1/build-banners")
+	party.Use(middleware.RequirePermissionAny(authz.PermBuildBanners))
+	handler := handlers.NewBuildBannerHandler()
+	handlers.RegisterBuildBannerRoutes(party, handler)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+	"github.com/ggmolly/belfast/internal/api/middleware"
+	"github.com/ggmolly/belfast/internal/authz"
+)
+
+func RegisterChat(app *iris.Application) {
+	party := app.Party("/api/v1/chat")
+	party.Use(middleware.RequirePermissionAny(authz.PermChat))
+	handler := handlers.NewChatHandler()
+	handlers.RegisterChatRoutes(party, handler)
+}
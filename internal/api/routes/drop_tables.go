@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+	"github.com/ggmolly/belfast/internal/api/middleware"
+	"github.com/ggmolly/belfast/internal/authz"
+)
+
+func RegisterDropTables(app *iris.Application) {
+	handler := handlers.NewDropTableHandler()
+	tables := app.Party("/api/v1/drop-tables")
+	tables.Use(middleware.RequirePermissionAny(authz.PermDropTables))
+	handlers.RegisterDropTableRoutes(tables, handler)
+	bonuses := app.Party("/api/v1/drop-bonuses")
+	bonuses.Use(middleware.RequirePermissionAny(authz.PermDropTables))
+	handlers.RegisterDropBonusRoutes(bonuses, handler)
+}
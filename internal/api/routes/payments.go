@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+	"github.com/ggmolly/belfast/internal/api/middleware"
+	"github.com/ggmolly/belfast/internal/authz"
+)
+
+func RegisterPayments(app *iris.Application) {
+	party := app.Party("/api/v1/payments")
+	party.Use(middleware.RequirePermissionAny(authz.PermPayments))
+	handler := handlers.NewPaymentHandler()
+	handlers.RegisterPaymentRoutes(party, handler)
+}
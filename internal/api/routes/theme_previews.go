@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/kataras/iris/v12"
+
+	"github.com/ggmolly/belfast/internal/api/handlers"
+)
+
+// RegisterThemePreviews serves the previews of the local storage provider.
+// The routes are public: uploads are authorized by the token of SC_19104.
+func RegisterThemePreviews(app *iris.Application) {
+	party := app.Party("/api/v1/theme-previews")
+	handler := handlers.NewThemePreviewHandler()
+	handlers.RegisterThemePreviewRoutes(party, handler)
+}
@@ -8,3 +8,47 @@ type ActivityAllowlistPatchPayload struct {
 	Add    []uint32 `json:"add"`
 	Remove []uint32 `json:"remove"`
 }
+
+type ActivityCalendarEntry struct {
+	ActivityID uint32  `json:"activity_id"`
+	Type       uint32  `json:"type"`
+	StartsAt   *string `json:"starts_at,omitempty"`
+	EndsAt     *string `json:"ends_at,omitempty"`
+	Source     string  `json:"source"`
+	Open       bool    `json:"open"`
+	Extended   bool    `json:"extended"`
+	ForceEnded bool    `json:"force_ended"`
+}
+
+type ActivityCalendarResponse struct {
+	Region string                  `json:"region"`
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Events []ActivityCalendarEntry `json:"events"`
+}
+
+type ActivityOverridePayload struct {
+	ID         uint64  `json:"id"`
+	ActivityID uint32  `json:"activity_id"`
+	Region     string  `json:"region"`
+	Action     string  `json:"action"`
+	StartsAt   *string `json:"starts_at,omitempty"`
+	EndsAt     *string `json:"ends_at,omitempty"`
+	Note       string  `json:"note"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type ActivityOverrideListResponse struct {
+	Overrides []ActivityOverridePayload `json:"overrides"`
+}
+
+// ActivityOverrideRequest adjusts the schedule of an activity. action is one
+// of extend (requires ends_at), preview or force_end. Times are RFC3339.
+type ActivityOverrideRequest struct {
+	ActivityID uint32 `json:"activity_id"`
+	Region     string `json:"region"`
+	Action     string `json:"action"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+	Note       string `json:"note"`
+}
@@ -0,0 +1,21 @@
+package types
+
+type PlayerActivityShopPurchase struct {
+	GoodID uint32 `json:"good_id"`
+	Count  uint32 `json:"count"`
+}
+
+type PlayerActivityProgress struct {
+	ActivityID     uint32                       `json:"activity_id"`
+	Pt             uint32                       `json:"pt"`
+	ClaimedTargets []uint32                     `json:"claimed_targets"`
+	SignDays       uint32                       `json:"sign_days"`
+	LastSignAt     *string                      `json:"last_sign_at,omitempty"`
+	ShopPurchases  []PlayerActivityShopPurchase `json:"shop_purchases"`
+	ClaimedTasks   []uint32                     `json:"claimed_tasks"`
+	UpdatedAt      string                       `json:"updated_at"`
+}
+
+type PlayerActivityProgressResponse struct {
+	Activities []PlayerActivityProgress `json:"activities"`
+}
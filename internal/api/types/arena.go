@@ -0,0 +1,51 @@
+package types
+
+type ArenaSeason struct {
+	SeasonID uint32 `json:"season_id"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+type ArenaSeasonResponse struct {
+	Season ArenaSeason `json:"season"`
+}
+
+type ArenaPayout struct {
+	CommanderID uint32 `json:"commander_id"`
+	Rank        uint32 `json:"rank"`
+	Score       uint32 `json:"score"`
+	Merit       uint32 `json:"merit"`
+}
+
+type ArenaSettlementResponse struct {
+	SeasonID     uint32        `json:"season_id"`
+	NextSeasonID uint32        `json:"next_season_id"`
+	Payouts      []ArenaPayout `json:"payouts"`
+}
+
+type ArenaLeaderboardEntry struct {
+	Rank        uint32 `json:"rank"`
+	CommanderID uint32 `json:"commander_id"`
+	Name        string `json:"name"`
+	Level       int    `json:"level"`
+	Score       uint32 `json:"score"`
+}
+
+type ArenaLeaderboardResponse struct {
+	SeasonID uint32                  `json:"season_id"`
+	Entries  []ArenaLeaderboardEntry `json:"entries"`
+	Meta     PaginationMeta          `json:"meta"`
+}
+
+type PlayerArenaState struct {
+	SeasonID            uint32   `json:"season_id"`
+	Score               uint32   `json:"score"`
+	Rank                uint32   `json:"rank"`
+	FightCount          uint32   `json:"fight_count"`
+	FightCountResetTime string   `json:"fight_count_reset_time"`
+	FlashTargetCount    uint32   `json:"flash_target_count"`
+	RivalIDs            []uint32 `json:"rival_ids"`
+	Wins                uint32   `json:"wins"`
+	Losses              uint32   `json:"losses"`
+	UpdatedAt           string   `json:"updated_at"`
+}
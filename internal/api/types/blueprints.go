@@ -0,0 +1,35 @@
+package types
+
+type PlayerBlueprintEntry struct {
+	BlueprintID    uint32   `json:"blueprint_id"`
+	ShipID         uint32   `json:"ship_id"`
+	StartTime      uint32   `json:"start_time"`
+	Duration       uint32   `json:"duration"`
+	Level          uint32   `json:"level"`
+	Exp            uint32   `json:"exp"`
+	SubmittedTasks []uint32 `json:"submitted_tasks"`
+}
+
+type PlayerTechProjectEntry struct {
+	RefreshID  uint32 `json:"refresh_id"`
+	TechID     uint32 `json:"tech_id"`
+	FinishTime uint32 `json:"finish_time"`
+}
+
+type PlayerBlueprintsResponse struct {
+	Blueprints     []PlayerBlueprintEntry   `json:"blueprints"`
+	Projects       []PlayerTechProjectEntry `json:"projects"`
+	RefreshFlag    uint32                   `json:"refresh_flag"`
+	ColdTime       uint32                   `json:"cold_time"`
+	CatchupVersion uint32                   `json:"catchup_version"`
+	CatchupTarget  uint32                   `json:"catchup_target"`
+}
+
+type PlayerBlueprintUpdateRequest struct {
+	ShipID         uint32   `json:"ship_id"`
+	StartTime      uint32   `json:"start_time"`
+	Duration       uint32   `json:"duration"`
+	Level          uint32   `json:"level"`
+	Exp            uint32   `json:"exp"`
+	SubmittedTasks []uint32 `json:"submitted_tasks"`
+}
@@ -0,0 +1,88 @@
+package types
+
+import "time"
+
+type BuildBannerRateUp struct {
+	ShipID uint32 `json:"ship_id" validate:"required,gt=0"`
+	Rate   uint32 `json:"rate" validate:"max=10000"`
+}
+
+type BuildBannerSummary struct {
+	ID              uint32              `json:"id"`
+	PoolID          uint32              `json:"pool_id"`
+	Name            string              `json:"name"`
+	Region          *string             `json:"region"`
+	CommonWeight    uint32              `json:"common_weight"`
+	RareWeight      uint32              `json:"rare_weight"`
+	EliteWeight     uint32              `json:"elite_weight"`
+	SuperRareWeight uint32              `json:"super_rare_weight"`
+	UltraRareWeight uint32              `json:"ultra_rare_weight"`
+	PityThreshold   uint32              `json:"pity_threshold"`
+	StartsAt        *time.Time          `json:"starts_at"`
+	EndsAt          *time.Time          `json:"ends_at"`
+	Enabled         bool                `json:"enabled"`
+	RateUps         []BuildBannerRateUp `json:"rate_ups"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+type BuildBannerListResponse struct {
+	Banners []BuildBannerSummary `json:"banners"`
+	Meta    PaginationMeta       `json:"meta"`
+}
+
+// BuildBannerRequest creates or replaces a banner. Omitted weights default
+// to the standard 30/51/12/7/0 split, rate-up rates are out of 10000. A
+// rarity can only be weighted when the pool has ships of it.
+type BuildBannerRequest struct {
+	PoolID          uint32              `json:"pool_id" validate:"required,gt=0"`
+	Name            string              `json:"name" validate:"required,max=64"`
+	Region          *string             `json:"region" validate:"omitempty,oneof=CN EN JP KR TW"`
+	CommonWeight    *uint32             `json:"common_weight" validate:"omitempty,max=1000000"`
+	RareWeight      *uint32             `json:"rare_weight" validate:"omitempty,max=1000000"`
+	EliteWeight     *uint32             `json:"elite_weight" validate:"omitempty,max=1000000"`
+	SuperRareWeight *uint32             `json:"super_rare_weight" validate:"omitempty,max=1000000"`
+	UltraRareWeight *uint32             `json:"ultra_rare_weight" validate:"omitempty,max=1000000"`
+	PityThreshold   uint32              `json:"pity_threshold" validate:"max=400"`
+	StartsAt        *time.Time          `json:"starts_at"`
+	EndsAt          *time.Time          `json:"ends_at"`
+	Enabled         *bool               `json:"enabled"`
+	RateUps         []BuildBannerRateUp `json:"rate_ups" validate:"dive"`
+}
+
+type BuildRollLogEntry struct {
+	ID          uint32    `json:"id"`
+	CommanderID uint32    `json:"commander_id"`
+	PoolID      uint32    `json:"pool_id"`
+	ShipID      uint32    `json:"ship_id"`
+	RarityID    uint32    `json:"rarity_id"`
+	RateUp      bool      `json:"rate_up"`
+	Pity        bool      `json:"pity"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type BuildRollLogListResponse struct {
+	Rolls []BuildRollLogEntry `json:"rolls"`
+	Meta  PaginationMeta      `json:"meta"`
+}
+
+type BuildRollRarityCount struct {
+	RarityID uint32  `json:"rarity_id"`
+	Count    uint32  `json:"count"`
+	Rate     float64 `json:"rate"`
+}
+
+type BuildRollRateUpCount struct {
+	ShipID uint32  `json:"ship_id"`
+	Count  uint32  `json:"count"`
+	Rate   float64 `json:"rate"`
+}
+
+// BuildBannerStatsResponse reports the observed rates of a banner, rates
+// being fractions of all its rolls.
+type BuildBannerStatsResponse struct {
+	Total    uint32                 `json:"total"`
+	Pity     uint32                 `json:"pity"`
+	Rarities []BuildRollRarityCount `json:"rarities"`
+	RateUps  []BuildRollRateUpCount `json:"rate_ups"`
+}
@@ -0,0 +1,39 @@
+package types
+
+type ChatMessage struct {
+	ID          uint32 `json:"id"`
+	RoomID      uint32 `json:"room_id"`
+	SenderID    uint32 `json:"sender_id"`
+	SenderName  string `json:"sender_name"`
+	SenderLevel int    `json:"sender_level"`
+	Content     string `json:"content"`
+	SentAt      string `json:"sent_at"`
+}
+
+type ChatMessageListResponse struct {
+	Messages []ChatMessage  `json:"messages"`
+	Meta     PaginationMeta `json:"meta"`
+}
+
+type ChatMute struct {
+	CommanderID uint32 `json:"commander_id"`
+	Reason      string `json:"reason"`
+	MutedUntil  string `json:"muted_until"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ChatMuteListResponse struct {
+	Mutes []ChatMute `json:"mutes"`
+}
+
+type ChatMuteRequest struct {
+	CommanderID     uint32 `json:"commander_id" validate:"required,gt=0"`
+	DurationSeconds int64  `json:"duration_seconds" validate:"required,gt=0"`
+	Reason          string `json:"reason" validate:"max=200"`
+}
+
+type ChatBroadcastRequest struct {
+	// Omitted to broadcast to every room.
+	RoomID  *uint32 `json:"room_id"`
+	Content string  `json:"content" validate:"required,max=512"`
+}
@@ -0,0 +1,15 @@
+package types
+
+type PlayerCheaterMark struct {
+	ID        uint64 `json:"id"`
+	Source    string `json:"source"`
+	Reason    string `json:"reason"`
+	System    uint32 `json:"system"`
+	StageID   uint32 `json:"stage_id"`
+	Detail    string `json:"detail"`
+	CreatedAt string `json:"created_at"`
+}
+
+type PlayerCheaterMarksResponse struct {
+	Marks []PlayerCheaterMark `json:"marks"`
+}
@@ -0,0 +1,109 @@
+package types
+
+import "time"
+
+type DropTableEntry struct {
+	DropType uint32 `json:"drop_type" validate:"required,gt=0"`
+	DropID   uint32 `json:"drop_id" validate:"required,gt=0"`
+	// Omitted or 0 drops one.
+	Count  uint32 `json:"count" validate:"max=100000"`
+	Weight uint32 `json:"weight" validate:"required,gt=0,max=1000000"`
+}
+
+type DropTableSummary struct {
+	ID             uint32           `json:"id"`
+	Kind           string           `json:"kind"`
+	SourceID       uint32           `json:"source_id"`
+	Name           string           `json:"name"`
+	Rolls          uint32           `json:"rolls"`
+	EmptyWeight    uint32           `json:"empty_weight"`
+	RankSRate      uint32           `json:"rank_s_rate"`
+	RankARate      uint32           `json:"rank_a_rate"`
+	RankBRate      uint32           `json:"rank_b_rate"`
+	RateMultiplier uint32           `json:"rate_multiplier"`
+	Enabled        bool             `json:"enabled"`
+	Entries        []DropTableEntry `json:"entries"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type DropTableListResponse struct {
+	Tables []DropTableSummary `json:"tables"`
+	Meta   PaginationMeta     `json:"meta"`
+}
+
+// DropTableRequest creates or replaces a drop table. The source is a chapter
+// id, an activity id for event tables or a virtual item id. Rates are
+// percents, omitted ones default to 100.
+type DropTableRequest struct {
+	Kind           string           `json:"kind" validate:"required,oneof=chapter event item"`
+	SourceID       uint32           `json:"source_id" validate:"required,gt=0"`
+	Name           string           `json:"name" validate:"max=64"`
+	Rolls          *uint32          `json:"rolls" validate:"omitempty,min=1,max=10"`
+	EmptyWeight    uint32           `json:"empty_weight" validate:"max=1000000"`
+	RankSRate      *uint32          `json:"rank_s_rate" validate:"omitempty,max=1000"`
+	RankARate      *uint32          `json:"rank_a_rate" validate:"omitempty,max=1000"`
+	RankBRate      *uint32          `json:"rank_b_rate" validate:"omitempty,max=1000"`
+	RateMultiplier *uint32          `json:"rate_multiplier" validate:"omitempty,min=1,max=1000"`
+	Enabled        *bool            `json:"enabled"`
+	Entries        []DropTableEntry `json:"entries" validate:"required,min=1,max=100,dive"`
+}
+
+// DropTableSimulationRequest rolls the table of a source Rolls times. Rank
+// only applies to chapter and event tables.
+type DropTableSimulationRequest struct {
+	Kind     string `json:"kind" validate:"omitempty,oneof=chapter event item"`
+	SourceID uint32 `json:"source_id" validate:"required,gt=0"`
+	Rank     string `json:"rank" validate:"omitempty,oneof=S A B"`
+	Rolls    uint32 `json:"rolls" validate:"required,min=1,max=100000"`
+}
+
+// DropTableSimulationEntry compares the observed rate of an outcome with its
+// expected rate, both being fractions of all rolls.
+type DropTableSimulationEntry struct {
+	DropType     uint32  `json:"drop_type"`
+	DropID       uint32  `json:"drop_id"`
+	Count        uint32  `json:"count"`
+	Hits         uint32  `json:"hits"`
+	Rate         float64 `json:"rate"`
+	ExpectedRate float64 `json:"expected_rate"`
+}
+
+type DropTableSimulationResponse struct {
+	TableID uint32 `json:"table_id"`
+	Rank    string `json:"rank"`
+	Rolls   uint32 `json:"rolls"`
+	// Combined rate of the bonuses running, in percent.
+	BonusRate         uint32                     `json:"bonus_rate"`
+	Entries           []DropTableSimulationEntry `json:"entries"`
+	Empty             uint32                     `json:"empty"`
+	EmptyRate         float64                    `json:"empty_rate"`
+	ExpectedEmptyRate float64                    `json:"expected_empty_rate"`
+}
+
+type DropBonus struct {
+	ID        uint32     `json:"id"`
+	Name      string     `json:"name"`
+	Kind      *string    `json:"kind"`
+	SourceID  *uint32    `json:"source_id"`
+	Rate      uint32     `json:"rate"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type DropBonusListResponse struct {
+	Bonuses []DropBonus `json:"bonuses"`
+}
+
+// DropBonusRequest schedules a drop bonus. Without kind it applies to every
+// table, without source_id to every table of its kind. Rate is a percent,
+// 200 doubling the drop rates.
+type DropBonusRequest struct {
+	Name     string     `json:"name" validate:"max=64"`
+	Kind     *string    `json:"kind" validate:"omitempty,oneof=chapter event item"`
+	SourceID *uint32    `json:"source_id" validate:"omitempty,gt=0"`
+	Rate     uint32     `json:"rate" validate:"required,min=1,max=10000"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+type PlayerFriendEntry struct {
+	CommanderID uint32    `json:"commander_id"`
+	Name        string    `json:"name"`
+	Level       uint32    `json:"level"`
+	Online      bool      `json:"online"`
+	LastLogin   time.Time `json:"last_login"`
+	Since       time.Time `json:"since"`
+	Content     string    `json:"content,omitempty"`
+}
+
+type PlayerFriendsResponse struct {
+	Friends          []PlayerFriendEntry `json:"friends"`
+	IncomingRequests []PlayerFriendEntry `json:"incoming_requests"`
+	OutgoingRequests []PlayerFriendEntry `json:"outgoing_requests"`
+	Blocks           []PlayerFriendEntry `json:"blocks"`
+}
+
+type PlayerFriendAddRequest struct {
+	FriendID uint32 `json:"friend_id" validate:"required,gt=0"`
+}
+
+type PlayerFriendBlockRequest struct {
+	BlockedID uint32 `json:"blocked_id" validate:"required,gt=0"`
+}
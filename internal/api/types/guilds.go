@@ -0,0 +1,86 @@
+package types
+
+import "time"
+
+type GuildSummary struct {
+	ID              uint32    `json:"id"`
+	Name            string    `json:"name"`
+	Faction         uint32    `json:"faction"`
+	Policy          uint32    `json:"policy"`
+	Level           uint32    `json:"level"`
+	Exp             uint32    `json:"exp"`
+	Capacity        uint32    `json:"capacity"`
+	Capital         uint32    `json:"capital"`
+	TechGroup       uint32    `json:"tech_group"`
+	MemberCount     uint32    `json:"member_count"`
+	Announce        string    `json:"announce"`
+	Manifesto       string    `json:"manifesto"`
+	ChangeFactionCD uint32    `json:"change_faction_cd"`
+	KickLeaderCD    uint32    `json:"kick_leader_cd"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GuildListResponse struct {
+	Guilds []GuildSummary `json:"guilds"`
+	Meta   PaginationMeta `json:"meta"`
+}
+
+type GuildMemberEntry struct {
+	CommanderID  uint32    `json:"commander_id"`
+	Name         string    `json:"name"`
+	Level        uint32    `json:"level"`
+	Duty         uint32    `json:"duty"`
+	Liveness     uint32    `json:"liveness"`
+	Contribution uint32    `json:"contribution"`
+	Online       bool      `json:"online"`
+	JoinedAt     time.Time `json:"joined_at"`
+	Content      string    `json:"content,omitempty"`
+}
+
+type GuildLogEntry struct {
+	Cmd         uint32    `json:"cmd"`
+	CommanderID uint32    `json:"commander_id"`
+	Name        string    `json:"name"`
+	Arg1        uint32    `json:"arg1"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GuildDetailResponse struct {
+	Guild        GuildSummary       `json:"guild"`
+	Members      []GuildMemberEntry `json:"members"`
+	Applications []GuildMemberEntry `json:"applications"`
+	Logs         []GuildLogEntry    `json:"logs"`
+}
+
+type GuildCreateRequest struct {
+	Name      string `json:"name" validate:"required,max=20"`
+	LeaderID  uint32 `json:"leader_id" validate:"required,gt=0"`
+	Faction   uint32 `json:"faction"`
+	Policy    uint32 `json:"policy"`
+	Capacity  uint32 `json:"capacity"`
+	Announce  string `json:"announce" validate:"max=100"`
+	Manifesto string `json:"manifesto" validate:"max=100"`
+}
+
+type GuildUpdateRequest struct {
+	Name            *string `json:"name" validate:"omitempty,min=1,max=20"`
+	Faction         *uint32 `json:"faction"`
+	Policy          *uint32 `json:"policy"`
+	Level           *uint32 `json:"level"`
+	Exp             *uint32 `json:"exp"`
+	Capacity        *uint32 `json:"capacity" validate:"omitempty,gt=0"`
+	Announce        *string `json:"announce" validate:"omitempty,max=100"`
+	Manifesto       *string `json:"manifesto" validate:"omitempty,max=100"`
+	ChangeFactionCD *uint32 `json:"change_faction_cd"`
+	KickLeaderCD    *uint32 `json:"kick_leader_cd"`
+	CapitalDelta    *int64  `json:"capital_delta"`
+}
+
+type GuildMemberAddRequest struct {
+	CommanderID uint32 `json:"commander_id" validate:"required,gt=0"`
+	Duty        uint32 `json:"duty" validate:"omitempty,min=1,max=4"`
+}
+
+type GuildMemberUpdateRequest struct {
+	Duty uint32 `json:"duty" validate:"required,min=1,max=4"`
+}
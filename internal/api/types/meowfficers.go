@@ -0,0 +1,63 @@
+package types
+
+type PlayerMeowfficerEntry struct {
+	ID            uint32   `json:"id"`
+	TemplateID    uint32   `json:"template_id"`
+	Level         uint32   `json:"level"`
+	Exp           uint32   `json:"exp"`
+	IsLocked      bool     `json:"is_locked"`
+	Name          string   `json:"name"`
+	Abilities     []uint32 `json:"abilities"`
+	AbilityOrigin []uint32 `json:"ability_origin"`
+	UsedPt        uint32   `json:"used_pt"`
+	SkillID       uint32   `json:"skill_id"`
+	SkillExp      uint32   `json:"skill_exp"`
+}
+
+type PlayerMeowfficerBoxEntry struct {
+	ID         uint32 `json:"id"`
+	PoolID     uint32 `json:"pool_id"`
+	BeginTime  uint32 `json:"begin_time"`
+	FinishTime uint32 `json:"finish_time"`
+}
+
+type PlayerMeowfficerHomeSlot struct {
+	SlotID       uint32 `json:"slot_id"`
+	MeowfficerID uint32 `json:"meowfficer_id"`
+	Style        uint32 `json:"style"`
+	ExpTime      uint32 `json:"exp_time"`
+}
+
+type PlayerMeowfficerHome struct {
+	Level      uint32                     `json:"level"`
+	Exp        uint32                     `json:"exp"`
+	CleanTime  uint32                     `json:"clean_time"`
+	UsageCount uint32                     `json:"usage_count"`
+	Slots      []PlayerMeowfficerHomeSlot `json:"slots"`
+}
+
+type PlayerMeowfficersResponse struct {
+	Meowfficers []PlayerMeowfficerEntry    `json:"meowfficers"`
+	Boxes       []PlayerMeowfficerBoxEntry `json:"boxes"`
+	Home        PlayerMeowfficerHome       `json:"home"`
+}
+
+type PlayerMeowfficerCreateRequest struct {
+	TemplateID uint32 `json:"template_id" validate:"required,gt=0"`
+	Name       string `json:"name" validate:"max=12"`
+}
+
+type PlayerMeowfficerUpdateRequest struct {
+	Level     *uint32  `json:"level" validate:"omitempty,gt=0"`
+	Exp       *uint32  `json:"exp"`
+	IsLocked  *bool    `json:"is_locked"`
+	Name      *string  `json:"name" validate:"omitempty,max=12"`
+	Abilities []uint32 `json:"abilities"`
+	UsedPt    *uint32  `json:"used_pt"`
+	SkillID   *uint32  `json:"skill_id"`
+	SkillExp  *uint32  `json:"skill_exp"`
+}
+
+type PlayerMeowfficerBoxRequest struct {
+	PoolID uint32 `json:"pool_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,25 @@
+package types
+
+type PaymentOrder struct {
+	ID          uint32 `json:"id"`
+	PayID       string `json:"pay_id"`
+	CommanderID uint32 `json:"commander_id"`
+	ShopID      uint32 `json:"shop_id"`
+	Kind        string `json:"kind"`
+	Provider    string `json:"provider"`
+	Status      string `json:"status"`
+	Money       uint32 `json:"money"`
+	Gem         uint32 `json:"gem"`
+	GemFree     uint32 `json:"gem_free"`
+	FailCode    uint32 `json:"fail_code"`
+	CreatedAt   string `json:"created_at"`
+	PaidAt      string `json:"paid_at,omitempty"`
+	DeliveredAt string `json:"delivered_at,omitempty"`
+	RefundedAt  string `json:"refunded_at,omitempty"`
+	RepaidAt    string `json:"repaid_at,omitempty"`
+}
+
+type PaymentOrderListResponse struct {
+	Orders []PaymentOrder `json:"orders"`
+	Meta   PaginationMeta `json:"meta"`
+}
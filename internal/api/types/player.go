@@ -591,6 +591,7 @@ type PlayerShipUpdateRequest struct {
 	IsSecretary         *bool   `json:"is_secretary"`
 	SecretaryPosition   *uint32 `json:"secretary_position" validate:"omitempty"`
 	SecretaryPhantomID  *uint32 `json:"secretary_phantom_id" validate:"omitempty"`
+	InOnsen             *bool   `json:"in_onsen"`
 }
 
 type PlayerBuildEntry struct {
@@ -36,12 +36,23 @@ type ServerStatsResponse struct {
 }
 
 type ServerMetricsResponse struct {
-	ClientCount   int     `json:"client_count"`
-	QueueMax      int     `json:"queue_max"`
-	QueueBlocks   uint64  `json:"queue_blocks"`
-	HandlerErrors uint64  `json:"handler_errors"`
-	WriteErrors   uint64  `json:"write_errors"`
-	PacketsPerSec float64 `json:"pps"`
+	ClientCount   int                  `json:"client_count"`
+	QueueMax      int                  `json:"queue_max"`
+	QueueBlocks   uint64               `json:"queue_blocks"`
+	HandlerErrors uint64               `json:"handler_errors"`
+	WriteErrors   uint64               `json:"write_errors"`
+	PacketsPerSec float64              `json:"pps"`
+	GameDataCache GameDataCacheMetrics `json:"game_data_cache"`
+}
+
+type GameDataCacheMetrics struct {
+	Loaded     bool   `json:"loaded"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Reloads    uint64 `json:"reloads"`
+	Categories int    `json:"categories"`
+	Entries    int    `json:"entries"`
+	LoadedAt   string `json:"loaded_at,omitempty"`
 }
 
 type ServerUptimeResponse struct {
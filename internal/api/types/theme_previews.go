@@ -0,0 +1,7 @@
+package types
+
+type ThemePreview struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+	MD5  string `json:"md5"`
+}
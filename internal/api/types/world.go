@@ -0,0 +1,31 @@
+package types
+
+type PlayerWorldMap struct {
+	MapID     uint32 `json:"map_id"`
+	IsCleared bool   `json:"is_cleared"`
+}
+
+type PlayerWorldGroup struct {
+	GroupID  uint32   `json:"group_id"`
+	ShipList []uint32 `json:"ship_list"`
+	Row      uint32   `json:"row"`
+	Column   uint32   `json:"column"`
+}
+
+type PlayerWorldItem struct {
+	ItemID uint32 `json:"item_id"`
+	Count  uint32 `json:"count"`
+}
+
+type PlayerWorldResponse struct {
+	Camp             uint32             `json:"camp"`
+	MapID            uint32             `json:"map_id"`
+	Progress         uint32             `json:"progress"`
+	ActionPower      uint32             `json:"action_power"`
+	ActionPowerExtra uint32             `json:"action_power_extra"`
+	LastRecoverTime  uint32             `json:"last_recover_time"`
+	StepCount        uint32             `json:"step_count"`
+	Maps             []PlayerWorldMap   `json:"maps"`
+	Groups           []PlayerWorldGroup `json:"groups"`
+	Items            []PlayerWorldItem  `json:"items"`
+}
@@ -0,0 +1,133 @@
+package arena
+
+import (
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const (
+	// MeritResourceID is the resource spent in the arena shop.
+	MeritResourceID = 3
+
+	RivalCount      = 5
+	MaxFightCount   = 10
+	FightCountRegen = 5
+	// MaxFlashTargetCount is the number of rival list replacements allowed
+	// between two fight count regenerations.
+	MaxFlashTargetCount = 5
+	InitialScore        = 0
+	SeasonLength        = 14 * 24 * time.Hour
+
+	minScoreGain  = 2
+	maxScoreGain  = 20
+	baseScoreGain = 10
+	scoreGainStep = 25
+)
+
+// fightCountResetHours are the UTC hours at which fight counts regenerate.
+var fightCountResetHours = []int{0, 12, 18}
+
+// rivalScoreBands are tried in order until enough rivals are found, the last
+// pass being unbounded.
+var rivalScoreBands = []uint32{50, 150, 400}
+
+type SeasonReward struct {
+	MinScore uint32
+	Merit    uint32
+}
+
+// SeasonRewards is the merit paid at the end of a season, by final score,
+// highest band first.
+var SeasonRewards = []SeasonReward{
+	{MinScore: 1500, Merit: 1500},
+	{MinScore: 1000, Merit: 1100},
+	{MinScore: 600, Merit: 800},
+	{MinScore: 300, Merit: 550},
+	{MinScore: 100, Merit: 350},
+	{MinScore: 0, Merit: 200},
+}
+
+// SeasonMerit returns the merit paid for finishing a season with score.
+func SeasonMerit(score uint32) uint32 {
+	for _, reward := range SeasonRewards {
+		if score >= reward.MinScore {
+			return reward.Merit
+		}
+	}
+	return 0
+}
+
+// NextFightCountReset returns the first fight count regeneration after now.
+func NextFightCountReset(now time.Time) time.Time {
+	utc := now.UTC()
+	day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	for _, hour := range fightCountResetHours {
+		reset := day.Add(time.Duration(hour) * time.Hour)
+		if reset.After(utc) {
+			return reset
+		}
+	}
+	return day.Add(24*time.Hour + time.Duration(fightCountResetHours[0])*time.Hour)
+}
+
+// RegenerateFightCount applies the regenerations that happened since the
+// last one and reports whether the state changed. Rival replacements are
+// reset along with it.
+func RegenerateFightCount(state *orm.ArenaState, now time.Time) bool {
+	if int64(state.FightCountResetTime) > now.Unix() {
+		return false
+	}
+	for state.FightCountResetTime != 0 && int64(state.FightCountResetTime) <= now.Unix() && state.FightCount < MaxFightCount {
+		state.FightCount = min(state.FightCount+FightCountRegen, MaxFightCount)
+		state.FightCountResetTime = uint32(NextFightCountReset(time.Unix(int64(state.FightCountResetTime), 0)).Unix())
+	}
+	state.FlashTargetCount = 0
+	state.FightCountResetTime = uint32(NextFightCountReset(now).Unix())
+	return true
+}
+
+// ScoreGain returns the score won by beating a rival; stronger rivals are
+// worth more. The defender loses half of it.
+func ScoreGain(attackerScore uint32, defenderScore uint32) uint32 {
+	gain := baseScoreGain + (int(defenderScore)-int(attackerScore))/scoreGainStep
+	return uint32(max(minScoreGain, min(gain, maxScoreGain)))
+}
+
+// ApplyDuel spends a fight of attacker and moves the scores. defender may be
+// nil when the rival never fought in the season.
+func ApplyDuel(attacker *orm.ArenaState, defender *orm.ArenaState, defenderScore uint32, won bool) uint32 {
+	if attacker.FightCount > 0 {
+		attacker.FightCount--
+	}
+	if !won {
+		attacker.Losses++
+		return 0
+	}
+	gain := ScoreGain(attacker.Score, defenderScore)
+	attacker.Score += gain
+	attacker.Wins++
+	if defender != nil {
+		defender.Score -= min(gain/2, defender.Score)
+	}
+	return gain
+}
+
+func scoreBand(score uint32, width uint32) (uint32, uint32) {
+	low := uint32(0)
+	if score > width {
+		low = score - width
+	}
+	return low, score + width
+}
+
+// RankTier returns the military rank shown next to a commander, 1 being the
+// lowest band of SeasonRewards.
+func RankTier(score uint32) uint32 {
+	for i, reward := range SeasonRewards {
+		if score >= reward.MinScore {
+			return uint32(len(SeasonRewards) - i)
+		}
+	}
+	return 1
+}
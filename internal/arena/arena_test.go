@@ -0,0 +1,99 @@
+package arena
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+func TestNextFightCountReset(t *testing.T) {
+	cases := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		{time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC), time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		if reset := NextFightCountReset(c.now); !reset.Equal(c.expected) {
+			t.Fatalf("expected %s after %s, got %s", c.expected, c.now, reset)
+		}
+	}
+}
+
+func TestRegenerateFightCount(t *testing.T) {
+	now := time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+	state := orm.ArenaState{
+		FightCount:          2,
+		FlashTargetCount:    3,
+		FightCountResetTime: uint32(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC).Unix()),
+	}
+	if !RegenerateFightCount(&state, now) {
+		t.Fatalf("expected a regeneration")
+	}
+	if state.FightCount != 7 || state.FlashTargetCount != 0 {
+		t.Fatalf("expected 7 fights and no replacement used, got %d/%d", state.FightCount, state.FlashTargetCount)
+	}
+	if state.FightCountResetTime != uint32(time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC).Unix()) {
+		t.Fatalf("expected next regeneration at 18:00, got %d", state.FightCountResetTime)
+	}
+	if RegenerateFightCount(&state, now) {
+		t.Fatalf("expected no regeneration before the next reset")
+	}
+
+	// several regenerations missed while offline are capped
+	later := time.Date(2026, 1, 3, 1, 0, 0, 0, time.UTC)
+	if !RegenerateFightCount(&state, later) || state.FightCount != MaxFightCount {
+		t.Fatalf("expected fight count to be capped at %d, got %d", MaxFightCount, state.FightCount)
+	}
+}
+
+func TestScoreGain(t *testing.T) {
+	if gain := ScoreGain(100, 100); gain != baseScoreGain {
+		t.Fatalf("expected %d for an even duel, got %d", baseScoreGain, gain)
+	}
+	if gain := ScoreGain(0, 2000); gain != maxScoreGain {
+		t.Fatalf("expected gain to be capped, got %d", gain)
+	}
+	if gain := ScoreGain(2000, 0); gain != minScoreGain {
+		t.Fatalf("expected minimum gain, got %d", gain)
+	}
+}
+
+func TestApplyDuel(t *testing.T) {
+	attacker := orm.ArenaState{Score: 100, FightCount: 3}
+	defender := orm.ArenaState{Score: 3}
+	if gain := ApplyDuel(&attacker, &defender, defender.Score, true); gain != 7 {
+		t.Fatalf("expected 7 points, got %d", gain)
+	}
+	if attacker.Score != 107 || attacker.FightCount != 2 || attacker.Wins != 1 {
+		t.Fatalf("unexpected attacker %+v", attacker)
+	}
+	if defender.Score != 0 {
+		t.Fatalf("expected defender score to stop at 0, got %d", defender.Score)
+	}
+
+	if gain := ApplyDuel(&attacker, nil, InitialScore, false); gain != 0 {
+		t.Fatalf("expected no points for a loss, got %d", gain)
+	}
+	if attacker.Score != 107 || attacker.FightCount != 1 || attacker.Losses != 1 {
+		t.Fatalf("unexpected attacker after a loss %+v", attacker)
+	}
+}
+
+func TestSeasonMeritAndRankTier(t *testing.T) {
+	if merit := SeasonMerit(0); merit != 200 {
+		t.Fatalf("expected 200 merit at 0 points, got %d", merit)
+	}
+	if merit := SeasonMerit(1499); merit != 1100 {
+		t.Fatalf("expected 1100 merit at 1499 points, got %d", merit)
+	}
+	if tier := RankTier(0); tier != 1 {
+		t.Fatalf("expected lowest tier, got %d", tier)
+	}
+	if tier := RankTier(5000); tier != uint32(len(SeasonRewards)) {
+		t.Fatalf("expected highest tier, got %d", tier)
+	}
+}
@@ -0,0 +1,317 @@
+package arena
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+const seasonTickInterval = time.Minute
+
+var (
+	ErrNoFightCount = errors.New("no fight left")
+	ErrNotRival     = errors.New("commander is not a rival")
+)
+
+// Payout is the merit paid to a commander when a season was settled.
+type Payout struct {
+	CommanderID uint32
+	Rank        uint32
+	Score       uint32
+	Merit       uint32
+}
+
+type Settlement struct {
+	SeasonID     uint32
+	NextSeasonID uint32
+	Payouts      []Payout
+}
+
+// OpenSeason returns the season in progress, opening the first one when
+// needed.
+func OpenSeason(now time.Time) (*orm.ArenaSeason, error) {
+	season, err := orm.GetOpenArenaSeason()
+	if err == nil || !db.IsNotFound(err) {
+		return season, err
+	}
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		season, err = orm.GetOpenArenaSeasonTx(ctx, tx)
+		if !db.IsNotFound(err) {
+			return err
+		}
+		season, err = orm.CreateArenaSeasonTx(ctx, tx, now.UTC(), now.UTC().Add(SeasonLength))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// LoadState returns the state of a commander in the season in progress,
+// settling the previous season first when it is over.
+func LoadState(commanderID uint32, now time.Time) (*orm.ArenaState, *orm.ArenaSeason, error) {
+	season, err := currentSeason(now)
+	if err != nil {
+		return nil, nil, err
+	}
+	state, err := orm.GetArenaState(commanderID)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, nil, err
+	}
+	changed := false
+	if state == nil {
+		state = newState(commanderID, season.SeasonID, now)
+		changed = true
+	} else if state.SeasonID != season.SeasonID {
+		// the state was created while the season was being settled
+		resetState(state, season.SeasonID)
+		changed = true
+	}
+	if RegenerateFightCount(state, now) {
+		changed = true
+	}
+	if len(state.RivalIDs) == 0 {
+		rivals, err := PickRivals(commanderID, season.SeasonID, state.Score)
+		if err != nil {
+			return nil, nil, err
+		}
+		state.RivalIDs = orm.ToInt64List(rivals)
+		changed = len(rivals) > 0 || changed
+	}
+	if changed {
+		if err := orm.SaveArenaState(state); err != nil {
+			return nil, nil, err
+		}
+	}
+	return state, season, nil
+}
+
+// ReplaceRivals draws a new rival list, spending one of the replacements of
+// the commander. It reports false when none is left.
+func ReplaceRivals(commanderID uint32, now time.Time) (*orm.ArenaState, bool, error) {
+	state, season, err := LoadState(commanderID, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if state.FlashTargetCount >= MaxFlashTargetCount {
+		return state, false, nil
+	}
+	rivals, err := PickRivals(commanderID, season.SeasonID, state.Score)
+	if err != nil {
+		return nil, false, err
+	}
+	state.RivalIDs = orm.ToInt64List(rivals)
+	state.FlashTargetCount++
+	if err := orm.SaveArenaState(state); err != nil {
+		return nil, false, err
+	}
+	return state, true, nil
+}
+
+// PickRivals returns up to RivalCount commanders with a saved exercise fleet,
+// widening the score band around score until enough are found.
+func PickRivals(commanderID uint32, seasonID uint32, score uint32) ([]uint32, error) {
+	rivals := make([]uint32, 0, RivalCount)
+	seen := map[uint32]struct{}{}
+	widths := append(append([]uint32{}, rivalScoreBands...), math.MaxUint32/2)
+	for _, width := range widths {
+		low, high := scoreBand(score, width)
+		candidates, err := orm.ListArenaRivalCandidates(commanderID, seasonID, low, high, InitialScore, RivalCount*2)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if _, ok := seen[candidate.CommanderID]; ok {
+				continue
+			}
+			seen[candidate.CommanderID] = struct{}{}
+			rivals = append(rivals, candidate.CommanderID)
+			if len(rivals) == RivalCount {
+				return rivals, nil
+			}
+		}
+	}
+	return rivals, nil
+}
+
+// CheckDuel reports whether the commander can fight rivalID now.
+func CheckDuel(commanderID uint32, rivalID uint32, now time.Time) error {
+	state, _, err := LoadState(commanderID, now)
+	if err != nil {
+		return err
+	}
+	return checkDuel(state, rivalID)
+}
+
+func checkDuel(state *orm.ArenaState, rivalID uint32) error {
+	if state.FightCount == 0 {
+		return ErrNoFightCount
+	}
+	for _, id := range state.RivalIDs {
+		if uint32(id) == rivalID {
+			return nil
+		}
+	}
+	return ErrNotRival
+}
+
+// FinishDuel records the outcome of a duel against rivalID, moves the scores
+// of both commanders and draws a new rival list. It returns the score won.
+func FinishDuel(commanderID uint32, rivalID uint32, won bool, now time.Time) (uint32, error) {
+	state, season, err := LoadState(commanderID, now)
+	if err != nil {
+		return 0, err
+	}
+	rivals, err := PickRivals(commanderID, season.SeasonID, state.Score)
+	if err != nil {
+		return 0, err
+	}
+	var gain uint32
+	ctx := context.Background()
+	err = orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		// lock in id order so two commanders fighting each other can't deadlock
+		states := map[uint32]*orm.ArenaState{}
+		for _, id := range []uint32{min(commanderID, rivalID), max(commanderID, rivalID)} {
+			locked, err := orm.GetArenaStateTx(ctx, tx, id)
+			if err != nil && !db.IsNotFound(err) {
+				return err
+			}
+			if locked != nil && locked.SeasonID == season.SeasonID {
+				states[id] = locked
+			}
+		}
+		attacker := states[commanderID]
+		if attacker == nil {
+			return fmt.Errorf("arena state of %d vanished", commanderID)
+		}
+		if err := checkDuel(attacker, rivalID); err != nil {
+			return err
+		}
+		defender := states[rivalID]
+		defenderScore := uint32(InitialScore)
+		if defender != nil {
+			defenderScore = defender.Score
+		}
+		gain = ApplyDuel(attacker, defender, defenderScore, won)
+		attacker.RivalIDs = orm.ToInt64List(rivals)
+		if err := orm.SaveArenaStateTx(ctx, tx, attacker); err != nil {
+			return err
+		}
+		if defender != nil && won {
+			return orm.SaveArenaStateTx(ctx, tx, defender)
+		}
+		return nil
+	})
+	return gain, err
+}
+
+// Rank returns the leaderboard position of a commander in a season.
+func Rank(state *orm.ArenaState) (uint32, error) {
+	return orm.GetArenaRank(state.SeasonID, state.CommanderID, state.Score)
+}
+
+// SettleSeason ends the season in progress: merit is paid by final score,
+// every state moves to a new season and scores are reset.
+func SettleSeason(now time.Time) (*Settlement, error) {
+	return settleSeason(now, true)
+}
+
+func settleSeason(now time.Time, force bool) (*Settlement, error) {
+	var settlement *Settlement
+	ctx := context.Background()
+	err := orm.WithPGXTx(ctx, func(tx pgx.Tx) error {
+		season, err := orm.GetOpenArenaSeasonTx(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if !force && now.Before(season.EndsAt) {
+			// settled by someone else in the meantime
+			return nil
+		}
+		states, err := orm.ListArenaStatesTx(ctx, tx, season.SeasonID)
+		if err != nil {
+			return err
+		}
+		settlement = &Settlement{SeasonID: season.SeasonID, Payouts: make([]Payout, 0, len(states))}
+		for i, state := range states {
+			payout := Payout{CommanderID: state.CommanderID, Rank: uint32(i + 1), Score: state.Score, Merit: SeasonMerit(state.Score)}
+			if payout.Merit > 0 {
+				commander := orm.Commander{CommanderID: state.CommanderID}
+				if err := commander.AddResourceTx(ctx, tx, MeritResourceID, payout.Merit); err != nil {
+					return err
+				}
+			}
+			settlement.Payouts = append(settlement.Payouts, payout)
+		}
+		if err := orm.SettleArenaSeasonTx(ctx, tx, season.SeasonID, now.UTC()); err != nil {
+			return err
+		}
+		next, err := orm.CreateArenaSeasonTx(ctx, tx, now.UTC(), now.UTC().Add(SeasonLength))
+		if err != nil {
+			return err
+		}
+		settlement.NextSeasonID = next.SeasonID
+		return orm.ResetArenaStatesTx(ctx, tx, next.SeasonID, InitialScore)
+	})
+	if err != nil || settlement == nil {
+		return nil, err
+	}
+	logger.LogEvent("Arena", "Season", fmt.Sprintf("settled season %d, %d commanders paid", settlement.SeasonID, len(settlement.Payouts)), logger.LOG_LEVEL_INFO)
+	return settlement, nil
+}
+
+// RunSeasons settles seasons as they end.
+func RunSeasons() {
+	ticker := time.NewTicker(seasonTickInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := currentSeason(time.Now()); err != nil {
+			logger.LogEvent("Arena", "Season", err.Error(), logger.LOG_LEVEL_ERROR)
+		}
+		<-ticker.C
+	}
+}
+
+func currentSeason(now time.Time) (*orm.ArenaSeason, error) {
+	season, err := OpenSeason(now)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(season.EndsAt) {
+		return season, nil
+	}
+	if _, err := settleSeason(now, false); err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	return OpenSeason(now)
+}
+
+func newState(commanderID uint32, seasonID uint32, now time.Time) *orm.ArenaState {
+	return &orm.ArenaState{
+		CommanderID:         commanderID,
+		SeasonID:            seasonID,
+		Score:               InitialScore,
+		FightCount:          MaxFightCount,
+		FightCountResetTime: uint32(NextFightCountReset(now).Unix()),
+		RivalIDs:            orm.Int64List{},
+	}
+}
+
+func resetState(state *orm.ArenaState, seasonID uint32) {
+	state.SeasonID = seasonID
+	state.Score = InitialScore
+	state.FlashTargetCount = 0
+	state.RivalIDs = orm.Int64List{}
+	state.Wins = 0
+	state.Losses = 0
+}
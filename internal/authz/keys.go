@@ -14,8 +14,14 @@ const (
 	PermShop            = "shop"
 	PermNotices         = "notices"
 	PermExchangeCodes   = "exchange_codes"
+	PermGuilds          = "guilds"
+	PermBuildBanners    = "build_banners"
 	PermDorm3D          = "dorm3d"
 	PermActivities      = "activities"
+	PermArena           = "arena"
+	PermChat            = "chat"
+	PermPayments        = "payments"
+	PermDropTables      = "drop_tables"
 	PermJuustagram      = "juustagram"
 	PermServer          = "server"
 	PermMeResources     = "me.resources"
@@ -36,8 +42,14 @@ func KnownPermissions() map[string]string {
 		PermShop:            "Manage shop offers",
 		PermNotices:         "Manage notices",
 		PermExchangeCodes:   "Manage exchange codes",
+		PermGuilds:          "Manage guilds",
+		PermBuildBanners:    "Manage build banners",
 		PermDorm3D:          "Manage Dorm3D",
 		PermActivities:      "Manage activities",
+		PermArena:           "Manage exercise seasons",
+		PermChat:            "Moderate public chat",
+		PermPayments:        "Manage charge orders",
+		PermDropTables:      "Manage drop tables",
 		PermJuustagram:      "Manage Juustagram",
 		PermServer:          "Manage server",
 		PermMeResources:     "Self resources read/update",
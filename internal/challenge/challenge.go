@@ -0,0 +1,85 @@
+// Package challenge holds the rules of challenge mode: seasons rotate over
+// the activity window, each season has its own dungeon list, and a run earns
+// points for every level cleared until it is finished, lost or given up.
+package challenge
+
+import "time"
+
+const (
+	ModeNormal   = 0
+	ModeInfinite = 1
+
+	// DefaultSeasonLength is used when the activity has no end date.
+	DefaultSeasonLength = 7 * 24 * time.Hour
+
+	// FullHP is the hp ratio of an undamaged ship, in 1/10000.
+	FullHP = 10000
+
+	levelPoints = 100
+	// rankSBonus is the extra share of points of an S rank, in percent.
+	rankSBonus = 50
+)
+
+// ValidMode reports whether the client may start a run in mode.
+func ValidMode(mode uint32) bool {
+	return mode == ModeNormal || mode == ModeInfinite
+}
+
+// SeasonID returns the 1-based season open at now. The activity window is
+// split evenly between the seasons; with no end date each season lasts
+// DefaultSeasonLength. Seasons start over once they all ran.
+func SeasonID(start time.Time, end time.Time, seasons int, now time.Time) uint32 {
+	if seasons <= 1 || start.IsZero() || !now.After(start) {
+		return 1
+	}
+	length := DefaultSeasonLength
+	if !end.IsZero() && end.After(start) {
+		length = end.Sub(start) / time.Duration(seasons)
+	}
+	if length <= 0 {
+		return 1
+	}
+	return uint32(int64(now.Sub(start)/length)%int64(seasons)) + 1
+}
+
+// Dungeons returns the dungeon list of a season for mode. infiniteStage
+// lists, per season, the dungeons of each mode; a mode without its own list
+// uses the first one.
+func Dungeons(infiniteStage [][][]uint32, seasonID uint32, mode uint32) []uint32 {
+	if seasonID == 0 || int(seasonID) > len(infiniteStage) {
+		return []uint32{}
+	}
+	lists := infiniteStage[seasonID-1]
+	if len(lists) == 0 {
+		return []uint32{}
+	}
+	if int(mode) < len(lists) {
+		return lists[mode]
+	}
+	return lists[0]
+}
+
+// DungeonAt returns the dungeon of a 1-based level. Infinite runs loop over
+// the list; normal runs end after its last dungeon.
+func DungeonAt(dungeons []uint32, level uint32, mode uint32) (uint32, bool) {
+	if len(dungeons) == 0 || level == 0 {
+		return 0, false
+	}
+	index := int(level - 1)
+	if mode == ModeInfinite {
+		index %= len(dungeons)
+	}
+	if index >= len(dungeons) {
+		return 0, false
+	}
+	return dungeons[index], true
+}
+
+// Points returns the score of clearing a 1-based level.
+func Points(level uint32, rankS bool) uint32 {
+	points := level * levelPoints
+	if rankS {
+		points += points * rankSBonus / 100
+	}
+	return points
+}
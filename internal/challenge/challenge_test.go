@@ -0,0 +1,75 @@
+package challenge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeasonID(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(28 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		end     time.Time
+		seasons int
+		now     time.Time
+		want    uint32
+	}{
+		{name: "single season", end: end, seasons: 1, now: start.Add(20 * 24 * time.Hour), want: 1},
+		{name: "before start", end: end, seasons: 4, now: start.Add(-time.Hour), want: 1},
+		{name: "first week", end: end, seasons: 4, now: start.Add(time.Hour), want: 1},
+		{name: "third week", end: end, seasons: 4, now: start.Add(15 * 24 * time.Hour), want: 3},
+		{name: "no end date", seasons: 2, now: start.Add(8 * 24 * time.Hour), want: 2},
+		{name: "seasons start over", seasons: 2, now: start.Add(15 * 24 * time.Hour), want: 1},
+	}
+	for _, test := range tests {
+		if got := SeasonID(start, test.end, test.seasons, test.now); got != test.want {
+			t.Fatalf("%s: expected season %d, got %d", test.name, test.want, got)
+		}
+	}
+	if got := SeasonID(time.Time{}, time.Time{}, 3, start); got != 1 {
+		t.Fatalf("expected season 1 without a window, got %d", got)
+	}
+}
+
+func TestDungeons(t *testing.T) {
+	stages := [][][]uint32{
+		{{101, 102, 103}, {111, 112}},
+		{{201, 202}},
+	}
+	if got := Dungeons(stages, 1, ModeInfinite); len(got) != 2 || got[0] != 111 {
+		t.Fatalf("expected infinite dungeons of season 1, got %v", got)
+	}
+	if got := Dungeons(stages, 2, ModeInfinite); len(got) != 2 || got[0] != 201 {
+		t.Fatalf("expected season 2 to fall back to its first list, got %v", got)
+	}
+	if got := Dungeons(stages, 3, ModeNormal); len(got) != 0 {
+		t.Fatalf("expected no dungeons for an unknown season, got %v", got)
+	}
+}
+
+func TestDungeonAt(t *testing.T) {
+	dungeons := []uint32{101, 102, 103}
+	if id, ok := DungeonAt(dungeons, 3, ModeNormal); !ok || id != 103 {
+		t.Fatalf("expected last dungeon, got %d %v", id, ok)
+	}
+	if _, ok := DungeonAt(dungeons, 4, ModeNormal); ok {
+		t.Fatalf("expected normal run to end after its last dungeon")
+	}
+	if id, ok := DungeonAt(dungeons, 5, ModeInfinite); !ok || id != 102 {
+		t.Fatalf("expected infinite run to loop, got %d %v", id, ok)
+	}
+	if _, ok := DungeonAt(dungeons, 0, ModeNormal); ok {
+		t.Fatalf("expected level 0 to have no dungeon")
+	}
+}
+
+func TestPoints(t *testing.T) {
+	if got := Points(3, false); got != 300 {
+		t.Fatalf("expected 300 points, got %d", got)
+	}
+	if got := Points(3, true); got != 450 {
+		t.Fatalf("expected 450 points with an S rank, got %d", got)
+	}
+}
@@ -0,0 +1,197 @@
+// Package chat moderates the public chat rooms: messages are filtered,
+// flooding commanders are muted, and rooms replay their history to
+// commanders joining them.
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+const (
+	DefaultHistorySize   = 50
+	DefaultFloodMessages = 5
+	DefaultFloodWindow   = 10 * time.Second
+	DefaultFloodMute     = 5 * time.Minute
+	// MaxHistorySize caps history_size, every message being its own packet.
+	MaxHistorySize = 200
+
+	mask = '*'
+)
+
+// Moderator holds the chat moderation settings and the recent messages of
+// each commander. It is safe for concurrent use and can be reconfigured at
+// any time.
+type Moderator struct {
+	mu            sync.Mutex
+	words         []string
+	pattern       *regexp.Regexp
+	historySize   int
+	floodMessages int
+	floodWindow   time.Duration
+	floodMute     time.Duration
+	sent          map[uint32][]time.Time
+	// lastSweep is when commanders with no message left in the flood window
+	// were last dropped from sent.
+	lastSweep time.Time
+}
+
+// Default is the moderator used by the packet handlers.
+var Default = NewModerator()
+
+func NewModerator() *Moderator {
+	return &Moderator{
+		historySize:   DefaultHistorySize,
+		floodMessages: DefaultFloodMessages,
+		floodWindow:   DefaultFloodWindow,
+		floodMute:     DefaultFloodMute,
+		sent:          map[uint32][]time.Time{},
+	}
+}
+
+// Configure replaces the settings of the moderator. The previous settings
+// are kept when cfg is invalid.
+func (moderator *Moderator) Configure(cfg config.ChatConfig) error {
+	var pattern *regexp.Regexp
+	if cfg.FilterPattern != "" {
+		compiled, err := regexp.Compile(cfg.FilterPattern)
+		if err != nil {
+			return fmt.Errorf("invalid chat filter_pattern: %w", err)
+		}
+		pattern = compiled
+	}
+	if cfg.HistorySize < 0 || cfg.FloodMessages < 0 || cfg.FloodWindowSeconds < 0 || cfg.FloodMuteSeconds < 0 {
+		return fmt.Errorf("chat limits must not be negative")
+	}
+	words := make([]string, 0, len(cfg.WordFilter))
+	for _, word := range cfg.WordFilter {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	moderator.mu.Lock()
+	defer moderator.mu.Unlock()
+	moderator.words = words
+	moderator.pattern = pattern
+	moderator.historySize = orDefault(min(cfg.HistorySize, MaxHistorySize), DefaultHistorySize)
+	moderator.floodMessages = orDefault(cfg.FloodMessages, DefaultFloodMessages)
+	moderator.floodWindow = time.Duration(orDefault(cfg.FloodWindowSeconds, int(DefaultFloodWindow/time.Second))) * time.Second
+	moderator.floodMute = time.Duration(orDefault(cfg.FloodMuteSeconds, int(DefaultFloodMute/time.Second))) * time.Second
+	return nil
+}
+
+func orDefault(value int, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// HistorySize is the number of messages replayed when joining a room.
+func (moderator *Moderator) HistorySize() int {
+	moderator.mu.Lock()
+	defer moderator.mu.Unlock()
+	return moderator.historySize
+}
+
+// FloodMute is how long a flooding commander is muted for.
+func (moderator *Moderator) FloodMute() time.Duration {
+	moderator.mu.Lock()
+	defer moderator.mu.Unlock()
+	return moderator.floodMute
+}
+
+// Filter masks the filtered words and pattern matches of content, reporting
+// whether anything was masked.
+func (moderator *Moderator) Filter(content string) (string, bool) {
+	moderator.mu.Lock()
+	words := moderator.words
+	pattern := moderator.pattern
+	moderator.mu.Unlock()
+
+	runes := []rune(content)
+	masked := make([]bool, len(runes))
+	// lowering can change byte lengths, match rune by rune instead
+	lower := []rune(strings.ToLower(content))
+	if len(lower) == len(runes) {
+		for _, word := range words {
+			needle := []rune(word)
+			for i := 0; i+len(needle) <= len(lower); i++ {
+				if string(lower[i:i+len(needle)]) == word {
+					for j := i; j < i+len(needle); j++ {
+						masked[j] = true
+					}
+				}
+			}
+		}
+	}
+	if pattern != nil {
+		for _, match := range pattern.FindAllStringIndex(content, -1) {
+			start := len([]rune(content[:match[0]]))
+			end := start + len([]rune(content[match[0]:match[1]]))
+			for j := start; j < end; j++ {
+				masked[j] = true
+			}
+		}
+	}
+	changed := false
+	for i, hit := range masked {
+		if hit {
+			runes[i] = mask
+			changed = true
+		}
+	}
+	if !changed {
+		return content, false
+	}
+	return string(runes), true
+}
+
+// Allow records a message of a commander and reports whether it stays
+// within the flood limit.
+func (moderator *Moderator) Allow(commanderID uint32, now time.Time) bool {
+	moderator.mu.Lock()
+	defer moderator.mu.Unlock()
+	cutoff := now.Add(-moderator.floodWindow)
+	if now.Sub(moderator.lastSweep) >= moderator.floodWindow {
+		moderator.sweepLocked(cutoff)
+		moderator.lastSweep = now
+	}
+	recent := moderator.sent[commanderID][:0]
+	for _, sentAt := range moderator.sent[commanderID] {
+		if sentAt.After(cutoff) {
+			recent = append(recent, sentAt)
+		}
+	}
+	recent = append(recent, now)
+	if len(recent) > moderator.floodMessages {
+		delete(moderator.sent, commanderID)
+		return false
+	}
+	moderator.sent[commanderID] = recent
+	return true
+}
+
+// sweepLocked drops the commanders whose messages all left the flood window,
+// so commanders who stopped chatting are not kept around. mu must be held.
+func (moderator *Moderator) sweepLocked(cutoff time.Time) {
+	for commanderID, sent := range moderator.sent {
+		if len(sent) == 0 || !sent[len(sent)-1].After(cutoff) {
+			delete(moderator.sent, commanderID)
+		}
+	}
+}
+
+// Forget drops the recent messages of a commander, e.g. when the commander
+// is muted or unmuted.
+func (moderator *Moderator) Forget(commanderID uint32) {
+	moderator.mu.Lock()
+	defer moderator.mu.Unlock()
+	delete(moderator.sent, commanderID)
+}
@@ -0,0 +1,145 @@
+package chat
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/connection"
+)
+
+func TestFilterMasksWordsAndPattern(t *testing.T) {
+	moderator := NewModerator()
+	err := moderator.Configure(config.ChatConfig{
+		WordFilter:    []string{"Darn", " "},
+		FilterPattern: `\d{3,}`,
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	cases := []struct {
+		content  string
+		expected string
+		changed  bool
+	}{
+		{content: "hello", expected: "hello", changed: false},
+		{content: "darn DARN", expected: "**** ****", changed: true},
+		{content: "call 5551234 now", expected: "call ******* now", changed: true},
+		{content: "éclair darn", expected: "éclair ****", changed: true},
+	}
+	for _, tc := range cases {
+		got, changed := moderator.Filter(tc.content)
+		if got != tc.expected || changed != tc.changed {
+			t.Fatalf("Filter(%q) = %q, %v; expected %q, %v", tc.content, got, changed, tc.expected, tc.changed)
+		}
+	}
+}
+
+func TestConfigureKeepsSettingsOnError(t *testing.T) {
+	moderator := NewModerator()
+	if err := moderator.Configure(config.ChatConfig{WordFilter: []string{"bad"}, HistorySize: 10}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if err := moderator.Configure(config.ChatConfig{FilterPattern: "("}); err == nil {
+		t.Fatalf("expected invalid pattern error")
+	}
+	if got, _ := moderator.Filter("bad"); got != "***" {
+		t.Fatalf("expected previous filter to be kept, got %q", got)
+	}
+	if moderator.HistorySize() != 10 {
+		t.Fatalf("expected history size 10, got %d", moderator.HistorySize())
+	}
+}
+
+func TestConfigureDefaults(t *testing.T) {
+	moderator := NewModerator()
+	if err := moderator.Configure(config.ChatConfig{HistorySize: 10000}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	if moderator.HistorySize() != MaxHistorySize {
+		t.Fatalf("expected history size to be capped, got %d", moderator.HistorySize())
+	}
+	if moderator.FloodMute() != DefaultFloodMute {
+		t.Fatalf("expected default flood mute, got %s", moderator.FloodMute())
+	}
+}
+
+func TestAllowSlidingWindow(t *testing.T) {
+	moderator := NewModerator()
+	if err := moderator.Configure(config.ChatConfig{FloodMessages: 2, FloodWindowSeconds: 10}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	if !moderator.Allow(1, now) || !moderator.Allow(1, now.Add(time.Second)) {
+		t.Fatalf("expected first messages to be allowed")
+	}
+	if !moderator.Allow(2, now.Add(time.Second)) {
+		t.Fatalf("expected other commanders to be tracked separately")
+	}
+	if moderator.Allow(1, now.Add(2*time.Second)) {
+		t.Fatalf("expected third message within the window to be refused")
+	}
+	// refusing resets the window, the mute takes over from there
+	if !moderator.Allow(1, now.Add(3*time.Second)) {
+		t.Fatalf("expected window to be reset after refusal")
+	}
+	if !moderator.Allow(2, now.Add(20*time.Second)) || !moderator.Allow(2, now.Add(21*time.Second)) {
+		t.Fatalf("expected old messages to leave the window")
+	}
+}
+
+func TestAllowForgetsIdleCommanders(t *testing.T) {
+	moderator := NewModerator()
+	if err := moderator.Configure(config.ChatConfig{FloodMessages: 2, FloodWindowSeconds: 10}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	for commanderID := uint32(1); commanderID <= 3; commanderID++ {
+		moderator.Allow(commanderID, now)
+	}
+	moderator.Allow(4, now.Add(11*time.Second))
+	if len(moderator.sent) != 1 {
+		t.Fatalf("expected idle commanders to be dropped, got %d tracked", len(moderator.sent))
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		2 * time.Hour:    "2h",
+		5 * time.Minute:  "5m",
+		90 * time.Second: "1m30s",
+		0:                "1s",
+	}
+	for duration, expected := range cases {
+		if got := formatDuration(duration); got != expected {
+			t.Fatalf("formatDuration(%s) = %q, expected %q", duration, got, expected)
+		}
+	}
+}
+
+func TestBroadcastAllPushesNotices(t *testing.T) {
+	previous := connection.BelfastInstance
+	t.Cleanup(func() {
+		connection.BelfastInstance = previous
+	})
+	server := connection.NewServer("127.0.0.1", 0, func(*[]byte, *connection.Client, int) {})
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+	client := &connection.Client{Connection: &local}
+	server.JoinRoom(3, client)
+
+	go BroadcastAll(SystemMessage("notice"))
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(remote, header); err != nil {
+		t.Fatalf("expected the notice to be written to the connection: %v", err)
+	}
+	if packetID := int(header[3])<<8 | int(header[4]); packetID != 50101 {
+		t.Fatalf("expected SC_50101, got %d", packetID)
+	}
+	if client.Buffer.Len() != 0 {
+		t.Fatalf("expected the handler buffer to be left alone")
+	}
+}
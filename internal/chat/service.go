@@ -0,0 +1,153 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	// SystemName is the sender name of system messages.
+	SystemName = "System"
+
+	floodReason = "flooding"
+)
+
+var ErrMuted = errors.New("commander is muted")
+
+// Post moderates a message sent by commander to roomID and stores it. When
+// the commander is muted, or gets muted for flooding, nothing is stored and
+// the mute is returned along with ErrMuted.
+func Post(commander *orm.Commander, roomID uint32, content string, now time.Time) (*orm.Message, *orm.ChatMute, error) {
+	mute, err := orm.GetActiveChatMute(commander.CommanderID, now)
+	if err == nil {
+		return nil, mute, ErrMuted
+	}
+	if !db.IsNotFound(err) {
+		return nil, nil, err
+	}
+	if !Default.Allow(commander.CommanderID, now) {
+		mute, err := Mute(commander.CommanderID, Default.FloodMute(), floodReason, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, mute, ErrMuted
+	}
+	content, _ = Default.Filter(content)
+	message, err := orm.SendMessage(roomID, content, commander)
+	if err != nil {
+		return nil, nil, err
+	}
+	message.Sender = *commander
+	return message, nil, nil
+}
+
+// Mute keeps a commander from posting for duration and announces it in
+// every room.
+func Mute(commanderID uint32, duration time.Duration, reason string, now time.Time) (*orm.ChatMute, error) {
+	mute, err := orm.UpsertChatMute(commanderID, reason, now.Add(duration))
+	if err != nil {
+		return nil, err
+	}
+	Default.Forget(commanderID)
+	name := fmt.Sprintf("#%d", commanderID)
+	if commander, err := orm.GetCommanderCoreByID(commanderID); err == nil {
+		name = commander.Name
+	}
+	logger.LogEvent("Chat", "Mute", fmt.Sprintf("muted %d until %s (%s)", commanderID, mute.MutedUntil.UTC().Format(time.RFC3339), reason), logger.LOG_LEVEL_INFO)
+	BroadcastAll(SystemMessage(fmt.Sprintf("%s has been muted for %s.", name, formatDuration(duration))))
+	return mute, nil
+}
+
+// Unmute lifts the mute of a commander, returning db.ErrNotFound when there
+// was none.
+func Unmute(commanderID uint32) error {
+	if err := orm.DeleteChatMute(commanderID); err != nil {
+		return err
+	}
+	Default.Forget(commanderID)
+	return nil
+}
+
+// History returns the messages replayed to a commander joining roomID,
+// oldest first.
+func History(roomID uint32) ([]*protobuf.SC_50101, error) {
+	messages, err := orm.GetRoomHistory(roomID, Default.HistorySize())
+	if err != nil {
+		return nil, err
+	}
+	packets := make([]*protobuf.SC_50101, 0, len(messages))
+	for _, message := range messages {
+		packets = append(packets, MessagePacket(message))
+	}
+	return packets, nil
+}
+
+// MessagePacket builds the SC_50101 of a stored message; Sender must be
+// loaded.
+func MessagePacket(message orm.Message) *protobuf.SC_50101 {
+	return &protobuf.SC_50101{
+		Player: &protobuf.PLAYER_INFO_P50{
+			Id:   proto.Uint32(message.SenderID),
+			Name: proto.String(message.Sender.Name),
+			Lv:   proto.Uint32(uint32(message.Sender.Level)),
+		},
+		Type:    proto.Uint32(orm.MSG_TYPE_NORMAL),
+		Content: proto.String(message.Content),
+	}
+}
+
+// SystemMessage builds a SC_50101 shown as a system notice.
+func SystemMessage(content string) *protobuf.SC_50101 {
+	return &protobuf.SC_50101{
+		Player: &protobuf.PLAYER_INFO_P50{
+			Id:   proto.Uint32(0),
+			Name: proto.String(SystemName),
+			Lv:   proto.Uint32(0),
+		},
+		Type:    proto.Uint32(orm.MSG_TYPE_BANNED),
+		Content: proto.String(content),
+	}
+}
+
+// MutedMessage builds the notice sent to a muted commander trying to post.
+func MutedMessage(mute *orm.ChatMute, now time.Time) *protobuf.SC_50101 {
+	return SystemMessage(fmt.Sprintf("You are muted for %s.", formatDuration(mute.MutedUntil.Sub(now))))
+}
+
+// Broadcast sends a chat message to the online commanders in roomID.
+func Broadcast(roomID uint32, packet *protobuf.SC_50101) {
+	if connection.BelfastInstance == nil {
+		return
+	}
+	connection.BelfastInstance.BroadcastChat(roomID, packet)
+}
+
+// BroadcastAll sends a chat message to the online commanders of every room.
+// It is pushed right away, so it may be called from outside a dispatcher
+// (e.g. the admin API).
+func BroadcastAll(packet *protobuf.SC_50101) {
+	if connection.BelfastInstance == nil {
+		return
+	}
+	connection.BelfastInstance.BroadcastChatAll(packet)
+}
+
+func formatDuration(duration time.Duration) string {
+	duration = max(duration.Round(time.Second), time.Second)
+	switch {
+	case duration >= time.Hour && duration%time.Hour == 0:
+		return fmt.Sprintf("%dh", duration/time.Hour)
+	case duration >= time.Minute && duration%time.Minute == 0:
+		return fmt.Sprintf("%dm", duration/time.Minute)
+	}
+	return duration.String()
+}
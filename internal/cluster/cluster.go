@@ -0,0 +1,305 @@
+// Package cluster lets several game servers share one database. Each node
+// records which commanders are connected to it and relays kicks, chat, pushes
+// and game data edits to the other nodes over Postgres LISTEN/NOTIFY.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	// Channel is the NOTIFY channel shared by the nodes.
+	Channel           = "belfast_cluster"
+	HeartbeatInterval = 10 * time.Second
+	// NodeTimeout is how long a node can miss heartbeats before its
+	// commanders are considered offline.
+	NodeTimeout = 3 * HeartbeatInterval
+
+	reconnectDelay = 2 * time.Second
+	// NOTIFY payloads are limited to 8000 bytes
+	maxPayloadSize = 7900
+)
+
+type eventKind string
+
+const (
+	eventKick      eventKind = "kick"
+	eventPush      eventKind = "push"
+	eventChat      eventKind = "chat"
+	eventChatAll   eventKind = "chat_all"
+	eventGuildChat eventKind = "guild_chat"
+	eventGameData  eventKind = "game_data"
+)
+
+var ErrPayloadTooLarge = errors.New("cluster event too large")
+
+var _ connection.Relay = (*Node)(nil)
+
+// event is the JSON payload of a notification. Payload holds a marshalled
+// protobuf message.
+type event struct {
+	Origin      string    `json:"origin"`
+	Kind        eventKind `json:"kind"`
+	CommanderID uint32    `json:"commander_id,omitempty"`
+	RoomID      uint32    `json:"room_id,omitempty"`
+	MemberIDs   []uint32  `json:"member_ids,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	Reason      uint8     `json:"reason,omitempty"`
+	PacketID    int       `json:"packet_id,omitempty"`
+	Payload     []byte    `json:"payload,omitempty"`
+}
+
+// Node is this game server as seen by the rest of the cluster. It implements
+// connection.Relay.
+type Node struct {
+	id        string
+	server    *connection.Server
+	startedAt time.Time
+	// publish sends an encoded event to the other nodes
+	publish func(payload string) error
+}
+
+// DefaultNodeID returns the node id used when node_id is not configured.
+func DefaultNodeID(port int) string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "belfast"
+	}
+	return fmt.Sprintf("%s:%d", hostname, port)
+}
+
+func NewNode(id string, server *connection.Server) *Node {
+	node := &Node{
+		id:        id,
+		server:    server,
+		startedAt: time.Now().UTC(),
+	}
+	node.publish = node.notify
+	return node
+}
+
+func (node *Node) ID() string {
+	return node.id
+}
+
+// Start forgets the commanders left over from a previous run of the node,
+// registers it and starts listening to the other nodes.
+func (node *Node) Start(ctx context.Context) error {
+	if err := orm.ClearNodePresence(node.id); err != nil {
+		return err
+	}
+	if err := node.heartbeat(); err != nil {
+		return err
+	}
+	go node.listen(ctx)
+	go node.runHeartbeats(ctx)
+	logger.LogEvent("Cluster", "Start", fmt.Sprintf("node %s joined the cluster", node.id), logger.LOG_LEVEL_INFO)
+	return nil
+}
+
+func (node *Node) heartbeat() error {
+	return orm.UpsertClusterNode(&orm.ClusterNode{
+		NodeID:    node.id,
+		StartedAt: node.startedAt,
+	})
+}
+
+func (node *Node) runHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := node.heartbeat(); err != nil {
+			logger.LogEvent("Cluster", "Heartbeat", err.Error(), logger.LOG_LEVEL_ERROR)
+		}
+	}
+}
+
+// listen receives the events of the other nodes, reconnecting when the
+// connection drops. Events sent while disconnected are lost.
+func (node *Node) listen(ctx context.Context) {
+	for {
+		err := node.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.LogEvent("Cluster", "Listen", fmt.Sprintf("connection lost: %v", err), logger.LOG_LEVEL_WARN)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (node *Node) listenOnce(ctx context.Context) error {
+	pooled, err := db.DefaultStore.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// a listening connection must never go back to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		node.handle(notification.Payload)
+	}
+}
+
+func (node *Node) send(ev event) {
+	ev.Origin = node.id
+	data, err := json.Marshal(ev)
+	if err == nil && len(data) > maxPayloadSize {
+		err = ErrPayloadTooLarge
+	}
+	if err == nil {
+		err = node.publish(string(data))
+	}
+	if err != nil {
+		logger.LogEvent("Cluster", "Publish", fmt.Sprintf("dropped %s event: %v", ev.Kind, err), logger.LOG_LEVEL_ERROR)
+	}
+}
+
+func (node *Node) notify(payload string) error {
+	_, err := db.DefaultStore.Pool.Exec(context.Background(), "SELECT pg_notify($1, $2)", Channel, payload)
+	return err
+}
+
+// handle applies an event of another node to the clients of this one.
+func (node *Node) handle(payload string) {
+	var ev event
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		logger.LogEvent("Cluster", "Receive", fmt.Sprintf("invalid event: %v", err), logger.LOG_LEVEL_WARN)
+		return
+	}
+	if ev.Origin == node.id {
+		return
+	}
+	switch ev.Kind {
+	case eventKick:
+		node.server.DisconnectLocalCommander(ev.CommanderID, ev.Reason, nil)
+	case eventPush:
+		node.server.DeliverToCommander(ev.CommanderID, ev.PacketID, ev.Payload)
+	case eventChat, eventChatAll:
+		var message protobuf.SC_50101
+		if err := proto.Unmarshal(ev.Payload, &message); err != nil {
+			logger.LogEvent("Cluster", "Receive", fmt.Sprintf("invalid chat message: %v", err), logger.LOG_LEVEL_WARN)
+			return
+		}
+		if ev.Kind == eventChatAll {
+			node.server.DeliverChatAll(&message)
+		} else {
+			node.server.DeliverChat(ev.RoomID, &message)
+		}
+	case eventGuildChat:
+		var message protobuf.SC_60008
+		if err := proto.Unmarshal(ev.Payload, &message); err != nil {
+			logger.LogEvent("Cluster", "Receive", fmt.Sprintf("invalid guild chat message: %v", err), logger.LOG_LEVEL_WARN)
+			return
+		}
+		node.server.DeliverGuildChat(ev.MemberIDs, &message)
+	case eventGameData:
+		reloadGameData(ev.Categories)
+	default:
+		logger.LogEvent("Cluster", "Receive", fmt.Sprintf("unknown event kind %q", ev.Kind), logger.LOG_LEVEL_WARN)
+	}
+}
+
+func (node *Node) Kick(commanderID uint32, reason uint8) {
+	node.send(event{Kind: eventKick, CommanderID: commanderID, Reason: reason})
+}
+
+func (node *Node) Push(commanderID uint32, packetID int, payload []byte) {
+	node.send(event{Kind: eventPush, CommanderID: commanderID, PacketID: packetID, Payload: payload})
+}
+
+func (node *Node) Chat(roomID uint32, message *protobuf.SC_50101) {
+	node.sendMessage(event{Kind: eventChat, RoomID: roomID}, message)
+}
+
+func (node *Node) ChatAll(message *protobuf.SC_50101) {
+	node.sendMessage(event{Kind: eventChatAll}, message)
+}
+
+func (node *Node) GuildChat(memberIDs []uint32, message *protobuf.SC_60008) {
+	node.sendMessage(event{Kind: eventGuildChat, MemberIDs: memberIDs}, message)
+}
+
+// GameDataChanged asks the other nodes to refresh their copy of the game
+// data. An empty list refreshes every category.
+func (node *Node) GameDataChanged(categories []string) {
+	node.send(event{Kind: eventGameData, Categories: categories})
+}
+
+func (node *Node) sendMessage(ev event, message proto.Message) {
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Cluster", "Publish", fmt.Sprintf("dropped %s event: %v", ev.Kind, err), logger.LOG_LEVEL_ERROR)
+		return
+	}
+	ev.Payload = payload
+	node.send(ev)
+}
+
+func (node *Node) Online(commanderID uint32) bool {
+	nodeID, err := orm.GetCommanderNode(commanderID, time.Now().Add(-NodeTimeout))
+	if err != nil {
+		if !db.IsNotFound(err) {
+			logger.LogEvent("Cluster", "Presence", err.Error(), logger.LOG_LEVEL_ERROR)
+		}
+		return false
+	}
+	return nodeID != node.id
+}
+
+func (node *Node) Joined(commanderID uint32) {
+	if err := orm.ClaimCommanderPresence(commanderID, node.id); err != nil {
+		logger.LogEvent("Cluster", "Presence", err.Error(), logger.LOG_LEVEL_ERROR)
+	}
+}
+
+func (node *Node) Left(commanderID uint32) {
+	if err := orm.ReleaseCommanderPresence(commanderID, node.id); err != nil {
+		logger.LogEvent("Cluster", "Presence", err.Error(), logger.LOG_LEVEL_ERROR)
+	}
+}
+
+// reloadGameData refreshes the game data edited on another node. Until the
+// registry is loaded, reads still go to the database and nothing is kept.
+func reloadGameData(categories []string) {
+	var err error
+	if len(categories) == 0 {
+		if gamedata.Default.Loaded() {
+			err = gamedata.Default.Load()
+		}
+	} else {
+		err = gamedata.Default.Reload(categories...)
+	}
+	if err != nil {
+		logger.LogEvent("Cluster", "GameData", fmt.Sprintf("failed to reload %v: %v", categories, err), logger.LOG_LEVEL_ERROR)
+	}
+}
@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+func newTestNode(t *testing.T, id string) (*Node, *[]string) {
+	t.Helper()
+	server := connection.NewServer("127.0.0.1", 0, func(pkt *[]byte, c *connection.Client, size int) {})
+	t.Cleanup(func() {
+		connection.BelfastInstance = nil
+	})
+	node := NewNode(id, server)
+	published := []string{}
+	node.publish = func(payload string) error {
+		published = append(published, payload)
+		return nil
+	}
+	return node, &published
+}
+
+func testChatMessage(content string) *protobuf.SC_50101 {
+	return &protobuf.SC_50101{
+		Player: &protobuf.PLAYER_INFO_P50{
+			Id:   proto.Uint32(1),
+			Name: proto.String("Tester"),
+			Lv:   proto.Uint32(1),
+		},
+		Type:    proto.Uint32(orm.MSG_TYPE_NORMAL),
+		Content: proto.String(content),
+	}
+}
+
+func TestChatIsRelayedBetweenNodes(t *testing.T) {
+	sender, published := newTestNode(t, "a")
+	receiver, _ := newTestNode(t, "b")
+
+	client := &connection.Client{Hash: 1, Commander: &orm.Commander{CommanderID: 10}}
+	receiver.server.JoinRoom(3, client)
+
+	sender.Chat(3, testChatMessage("hello"))
+	if len(*published) != 1 {
+		t.Fatalf("expected 1 published event, got %d", len(*published))
+	}
+	var ev event
+	if err := json.Unmarshal([]byte((*published)[0]), &ev); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if ev.Origin != "a" || ev.Kind != eventChat || ev.RoomID != 3 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	receiver.handle((*published)[0])
+	if client.Buffer.Len() == 0 {
+		t.Fatalf("expected chat to be delivered to the receiving node")
+	}
+}
+
+func TestOwnEventsAreIgnored(t *testing.T) {
+	node, published := newTestNode(t, "a")
+	client := &connection.Client{Hash: 1, Commander: &orm.Commander{CommanderID: 10}}
+	node.server.JoinRoom(0, client)
+
+	node.ChatAll(testChatMessage("hello"))
+	node.handle((*published)[0])
+	if client.Buffer.Len() != 0 {
+		t.Fatalf("expected a node to ignore its own events")
+	}
+}
+
+func TestPushIsDeliveredToLocalCommander(t *testing.T) {
+	node, _ := newTestNode(t, "b")
+	client := &connection.Client{Hash: 1, Commander: &orm.Commander{CommanderID: 10}}
+	node.server.AddClient(client)
+
+	payload, err := proto.Marshal(&protobuf.SC_30001{UnreadNumber: proto.Uint32(1), TotalNumber: proto.Uint32(1)})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	data, err := json.Marshal(event{Origin: "a", Kind: eventPush, CommanderID: 10, PacketID: 30001, Payload: payload})
+	if err != nil {
+		t.Fatalf("failed to encode event: %v", err)
+	}
+	node.handle(string(data))
+	if client.Buffer.Len() == 0 {
+		t.Fatalf("expected push to be delivered")
+	}
+}
+
+func TestOversizedEventsAreDropped(t *testing.T) {
+	node, published := newTestNode(t, "a")
+	node.ChatAll(testChatMessage(strings.Repeat("x", maxPayloadSize)))
+	if len(*published) != 0 {
+		t.Fatalf("expected oversized event to be dropped")
+	}
+}
+
+func TestGameDataChangesAreRelayed(t *testing.T) {
+	node, published := newTestNode(t, "a")
+	node.GameDataChanged([]string{"ShareCfg/activity_template.json"})
+	if len(*published) != 1 {
+		t.Fatalf("expected 1 published event, got %d", len(*published))
+	}
+	var ev event
+	if err := json.Unmarshal([]byte((*published)[0]), &ev); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if ev.Kind != eventGameData || len(ev.Categories) != 1 || ev.Categories[0] != "ShareCfg/activity_template.json" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+}
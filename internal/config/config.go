@@ -17,6 +17,12 @@ type Config struct {
 	DB           DatabaseConfig     `toml:"database"`
 	Region       RegionConfig       `toml:"region"`
 	CreatePlayer CreatePlayerConfig `toml:"create_player"`
+	Tickets      TicketConfig       `toml:"tickets"`
+	Chat         ChatConfig         `toml:"chat"`
+	Cluster      ClusterConfig      `toml:"cluster"`
+	Payments     PaymentsConfig     `toml:"payments"`
+	Storage      StorageConfig      `toml:"storage"`
+	GameData     GameDataConfig     `toml:"game_data"`
 	Servers      []ServerConfig     `toml:"servers"`
 	Path         string             `toml:"-"`
 }
@@ -30,6 +36,7 @@ type GatewayConfig struct {
 	ProxyDialTimeoutMS int `toml:"proxy_dial_timeout_ms"`
 	// When nil, defaults to true.
 	RequirePrivateClients *bool          `toml:"require_private_clients"`
+	Tickets               TicketConfig   `toml:"tickets"`
 	Servers               []ServerConfig `toml:"servers"`
 	Path                  string         `toml:"-"`
 }
@@ -41,6 +48,9 @@ type BelfastConfig struct {
 	Name        string `toml:"name"`
 	// When nil, defaults to true.
 	RequirePrivateClients *bool `toml:"require_private_clients"`
+	// Connections at which the server reports a full load to the gateway,
+	// 0 to always report an idle server.
+	MaxClients int `toml:"max_clients"`
 }
 
 type ServerConfig struct {
@@ -98,6 +108,90 @@ type DatabaseConfig struct {
 	MigrationsEnabled bool `toml:"migrations_enabled"`
 }
 
+// TicketConfig holds the HMAC keys server tickets are signed with. The
+// gateway signs with active_key, game servers accept any listed key so keys
+// can be rotated by adding the new one everywhere before switching
+// active_key. Tickets are unsigned when no key is configured.
+type TicketConfig struct {
+	// Lifetime of a ticket, in seconds.
+	TTLSeconds int    `toml:"ttl_seconds"`
+	ActiveKey  string `toml:"active_key"`
+	// Game servers only: tickets minted for another server id are refused,
+	// and so are all server-bound tickets when left at 0.
+	ServerID uint32            `toml:"server_id"`
+	Keys     []TicketKeyConfig `toml:"keys"`
+}
+
+type TicketKeyConfig struct {
+	ID     string `toml:"id"`
+	Secret string `toml:"secret"`
+}
+
+// ChatConfig tunes public chat moderation. Words of word_filter (case
+// insensitive) and matches of filter_pattern are masked in messages. A
+// commander sending more than flood_messages messages within
+// flood_window_seconds is muted for flood_mute_seconds. Zero values fall back
+// to the defaults of the chat package.
+type ChatConfig struct {
+	// Number of messages replayed when joining a room.
+	HistorySize        int      `toml:"history_size"`
+	WordFilter         []string `toml:"word_filter"`
+	FilterPattern      string   `toml:"filter_pattern"`
+	FloodMessages      int      `toml:"flood_messages"`
+	FloodWindowSeconds int      `toml:"flood_window_seconds"`
+	FloodMuteSeconds   int      `toml:"flood_mute_seconds"`
+}
+
+// ClusterConfig lets several game servers share one database behind the same
+// gateway. Nodes track which commander is connected where and relay kicks,
+// chat and pushes to each other over Postgres LISTEN/NOTIFY. node_id must be
+// unique and stable across restarts; it defaults to the hostname and port.
+type ClusterConfig struct {
+	Enabled bool   `toml:"enabled"`
+	NodeID  string `toml:"node_id"`
+}
+
+// PaymentsConfig selects the payment provider of the charge shop. With no
+// provider (or "disabled") every charge is refused. The "sandbox" provider
+// takes no real payment: orders are approved right away when auto_approve
+// is set, otherwise from the admin API.
+type PaymentsConfig struct {
+	Provider    string `toml:"provider"`
+	AutoApprove bool   `toml:"auto_approve"`
+}
+
+// StorageConfig selects where backyard theme previews are uploaded. With no
+// provider (or "disabled") previews are not stored. "local" keeps them in dir
+// and serves them from the REST API; "s3" uses any S3-compatible bucket, and
+// clients get short-lived STS credentials (assume_role is required).
+type StorageConfig struct {
+	Provider string `toml:"provider"`
+	// Lifetime of the upload credentials handed to clients, 900 by default.
+	CredentialsTTLSeconds int `toml:"credentials_ttl_seconds"`
+
+	Dir            string `toml:"dir"`
+	Secret         string `toml:"secret"`
+	MaxObjectBytes int64  `toml:"max_object_bytes"`
+
+	Endpoint           string `toml:"endpoint"`
+	Region             string `toml:"region"`
+	Bucket             string `toml:"bucket"`
+	AccessKey          string `toml:"access_key"`
+	SecretKey          string `toml:"secret_key"`
+	VirtualHostedStyle bool   `toml:"virtual_hosted_style"`
+	AssumeRole         bool   `toml:"assume_role"`
+	RoleARN            string `toml:"role_arn"`
+	STSEndpoint        string `toml:"sts_endpoint"`
+}
+
+// GameDataConfig selects where game data is imported from: "http" (the
+// belfast-data repository, default), "dir" (a local checkout) or "archive"
+// (a .zip or .tar.gz bundle).
+type GameDataConfig struct {
+	Source string `toml:"source"`
+	Path   string `toml:"path"`
+}
+
 type RegionConfig struct {
 	Default string `toml:"default"`
 }
@@ -193,12 +287,57 @@ func LoadGateway(path string) (GatewayConfig, error) {
 			BindAddress: cfg.BindAddress,
 			Port:        cfg.Port,
 		},
+		Tickets: cfg.Tickets,
 		Servers: cfg.Servers,
 		Path:    cfg.Path,
 	}
 	return cfg, nil
 }
 
+// LoadTickets reads the [tickets] section of a server or gateway config
+// file, leaving the current config untouched.
+func LoadTickets(path string) (TicketConfig, error) {
+	var cfg struct {
+		Tickets TicketConfig `toml:"tickets"`
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return TicketConfig{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return cfg.Tickets, nil
+}
+
+// LoadChat reads the [chat] section of a server config file, leaving the
+// current config untouched.
+func LoadChat(path string) (ChatConfig, error) {
+	var cfg struct {
+		Chat ChatConfig `toml:"chat"`
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return ChatConfig{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return cfg.Chat, nil
+}
+
+func LoadPayments(path string) (PaymentsConfig, error) {
+	var cfg struct {
+		Payments PaymentsConfig `toml:"payments"`
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return PaymentsConfig{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return cfg.Payments, nil
+}
+
+func LoadStorage(path string) (StorageConfig, error) {
+	var cfg struct {
+		Storage StorageConfig `toml:"storage"`
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return StorageConfig{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return cfg.Storage, nil
+}
+
 func (cfg *Config) PersistMaintenance(enabled bool) error {
 	cfg.Belfast.Maintenance = enabled
 	return updateMaintenanceFlag(cfg.Path, enabled)
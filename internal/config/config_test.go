@@ -892,3 +892,182 @@ func TestToSnakeCase(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadTickets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "server.toml")
+	configContent := `[belfast]
+port = 7000
+
+[tickets]
+ttl_seconds = 600
+active_key = "new"
+server_id = 2
+
+[[tickets.keys]]
+id = "old"
+secret = "one"
+
+[[tickets.keys]]
+id = "new"
+secret = "two"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	before := Current()
+
+	tickets, err := LoadTickets(configPath)
+	if err != nil {
+		t.Fatalf("failed to load tickets: %v", err)
+	}
+	if tickets.TTLSeconds != 600 || tickets.ActiveKey != "new" || tickets.ServerID != 2 {
+		t.Fatalf("unexpected tickets config: %+v", tickets)
+	}
+	if len(tickets.Keys) != 2 || tickets.Keys[1].Secret != "two" {
+		t.Fatalf("unexpected ticket keys: %+v", tickets.Keys)
+	}
+	if Current().Belfast.Port != before.Belfast.Port {
+		t.Fatalf("expected current config to be left untouched")
+	}
+}
+
+func TestLoadChat(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "server.toml")
+	configContent := `[belfast]
+port = 7000
+
+[chat]
+history_size = 30
+word_filter = ["foo", "bar"]
+filter_pattern = "b[a4]z"
+flood_messages = 3
+flood_window_seconds = 5
+flood_mute_seconds = 120
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	chat, err := LoadChat(configPath)
+	if err != nil {
+		t.Fatalf("failed to load chat: %v", err)
+	}
+	if chat.HistorySize != 30 || chat.FilterPattern != "b[a4]z" || len(chat.WordFilter) != 2 {
+		t.Fatalf("unexpected chat config: %+v", chat)
+	}
+	if chat.FloodMessages != 3 || chat.FloodWindowSeconds != 5 || chat.FloodMuteSeconds != 120 {
+		t.Fatalf("unexpected flood config: %+v", chat)
+	}
+}
+
+func TestLoadGameDataSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	configContent := `[belfast]
+port = 7000
+
+[database]
+path = "test.db"
+
+[region]
+default = "EN"
+
+[game_data]
+source = "archive"
+path = "belfast-data.tar.gz"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.GameData.Source != "archive" || cfg.GameData.Path != "belfast-data.tar.gz" {
+		t.Fatalf("unexpected game data config: %+v", cfg.GameData)
+	}
+}
+
+func TestLoadCluster(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	configContent := `[belfast]
+port = 7000
+max_clients = 500
+
+[database]
+path = "test.db"
+
+[region]
+default = "EN"
+
+[cluster]
+enabled = true
+node_id = "node-1"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Belfast.MaxClients != 500 {
+		t.Fatalf("expected max_clients 500, got %d", cfg.Belfast.MaxClients)
+	}
+	if !cfg.Cluster.Enabled || cfg.Cluster.NodeID != "node-1" {
+		t.Fatalf("unexpected cluster config: %+v", cfg.Cluster)
+	}
+}
+
+func TestLoadPayments(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "server.toml")
+	configContent := `[belfast]
+port = 7000
+
+[payments]
+provider = "sandbox"
+auto_approve = true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	payments, err := LoadPayments(configPath)
+	if err != nil {
+		t.Fatalf("failed to load payments: %v", err)
+	}
+	if payments.Provider != "sandbox" || !payments.AutoApprove {
+		t.Fatalf("unexpected payments config: %+v", payments)
+	}
+}
+
+func TestLoadStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "server.toml")
+	configContent := `[belfast]
+port = 7000
+
+[storage]
+provider = "s3"
+endpoint = "http://127.0.0.1:9000"
+bucket = "previews"
+assume_role = true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	storage, err := LoadStorage(configPath)
+	if err != nil {
+		t.Fatalf("failed to load storage: %v", err)
+	}
+	if storage.Provider != "s3" || storage.Bucket != "previews" || !storage.AssumeRole {
+		t.Fatalf("unexpected storage config: %+v", storage)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	queue "gopkg.in/eapache/queue.v1"
 
+	"github.com/ggmolly/belfast/internal/debug"
 	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/protobuf"
@@ -52,6 +53,9 @@ type Client struct {
 	ConnectedAt     time.Time
 	PreviousLoginAt time.Time
 
+	// writeMu serializes writes to Connection, as pushes are sent from other
+	// goroutines than the dispatcher of the client.
+	writeMu      sync.Mutex
 	packetQueue  *queue.Queue
 	queueMu      sync.Mutex
 	queueCond    *sync.Cond
@@ -326,7 +330,9 @@ func (client *Client) Disconnect(reason uint8) error {
 
 // Sends the content of the buffer to the client via TCP
 func (client *Client) Flush() error {
+	client.writeMu.Lock()
 	_, err := (*client.Connection).Write(client.Buffer.Bytes())
+	client.writeMu.Unlock()
 	if err != nil {
 		client.recordWriteError()
 		logger.LogEvent("Client", "Flush", fmt.Sprintf("%s:%d -> %v", client.IP, client.Port, err), logger.LOG_LEVEL_ERROR)
@@ -342,6 +348,41 @@ func (client *Client) SendMessage(packetId int, message any) (int, int, error) {
 	return SendProtoMessage(packetId, client, message)
 }
 
+// Push writes a message to the client right away, without going through the
+// buffer of the packet being handled. Unlike SendMessage, it is safe to call
+// from any goroutine.
+func (client *Client) Push(packetId int, message proto.Message) error {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Client", "Push", fmt.Sprintf("SC_%d -> %v", packetId, err), logger.LOG_LEVEL_ERROR)
+		return err
+	}
+	return client.PushRaw(packetId, data)
+}
+
+// PushRaw is Push for an already marshalled message.
+func (client *Client) PushRaw(packetId int, data []byte) error {
+	debug.InsertPacket(packetId, &data)
+	// pushes answer no request, they carry no packet index
+	InjectPacketHeader(packetId, &data, 0)
+	client.writeMu.Lock()
+	if client.Connection == nil {
+		// not backed by a socket, e.g. in tests: keep it buffered
+		client.Buffer.Write(data)
+		client.writeMu.Unlock()
+		return nil
+	}
+	_, err := (*client.Connection).Write(data)
+	client.writeMu.Unlock()
+	if err != nil {
+		client.recordWriteError()
+		logger.LogEvent("Client", "Push", fmt.Sprintf("%s:%d -> %v", client.IP, client.Port, err), logger.LOG_LEVEL_ERROR)
+		client.CloseWithError(err)
+		return err
+	}
+	return nil
+}
+
 type MetricsSnapshot struct {
 	QueueMax      int
 	QueueBlocks   uint64
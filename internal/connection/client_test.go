@@ -667,6 +667,51 @@ func TestClientFlushErrorClosesClient(t *testing.T) {
 	}
 }
 
+type recordConn struct {
+	mockConn
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (c *recordConn) Write(b []byte) (n int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = append(c.writes, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func TestClientPushWritesFramesFromOtherGoroutines(t *testing.T) {
+	recorder := &recordConn{}
+	var conn net.Conn = recorder
+	client := &Client{Connection: &conn}
+	client.initQueues()
+	client.Buffer.Write([]byte{1, 2, 3})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.PushRaw(11201, []byte{4, 5}); err != nil {
+				t.Errorf("push failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if client.Buffer.Len() != 3 {
+		t.Fatalf("expected pushes to leave the handler buffer alone, got %d bytes", client.Buffer.Len())
+	}
+	if len(recorder.writes) != 8 {
+		t.Fatalf("expected 8 frames, got %d", len(recorder.writes))
+	}
+	for _, frame := range recorder.writes {
+		if len(frame) != 7+2 || frame[3] != byte(11201>>8) || frame[4] != byte(11201&0xff) {
+			t.Fatalf("unexpected frame %v", frame)
+		}
+	}
+}
+
 func TestClientCreateCommander(t *testing.T) {
 	withTestDB(t)
 
@@ -0,0 +1,109 @@
+package connection
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+// Relay forwards deliveries to the other nodes of a cluster and tracks which
+// node each commander is connected to. Implementations must not deliver back
+// to the node they are called from.
+type Relay interface {
+	Kick(commanderID uint32, reason uint8)
+	Push(commanderID uint32, packetID int, payload []byte)
+	Chat(roomID uint32, message *protobuf.SC_50101)
+	ChatAll(message *protobuf.SC_50101)
+	GuildChat(memberIDs []uint32, message *protobuf.SC_60008)
+	// GameDataChanged asks the other nodes to reload the given config
+	// categories, or all of them when the list is empty.
+	GameDataChanged(categories []string)
+	// Online reports whether the commander is connected to another node.
+	Online(commanderID uint32) bool
+	Joined(commanderID uint32)
+	Left(commanderID uint32)
+}
+
+// SetRelay connects the server to a cluster. It must be called before Run.
+func (server *Server) SetRelay(relay Relay) {
+	server.relay = relay
+}
+
+// GameDataChanged tells the other nodes of the cluster that config entries
+// were edited, so they refresh their copy of the game data.
+func (server *Server) GameDataChanged(categories ...string) {
+	if server.relay == nil {
+		return
+	}
+	server.relay.GameDataChanged(categories)
+}
+
+// CommanderJoined records that the commander of client logged in on this
+// node.
+func (server *Server) CommanderJoined(client *Client) {
+	if server.relay == nil || client.Commander == nil {
+		return
+	}
+	server.relay.Joined(client.Commander.CommanderID)
+}
+
+// IsCommanderOnline reports whether a commander is connected to any node of
+// the cluster.
+func (server *Server) IsCommanderOnline(commanderID uint32) bool {
+	if _, ok := server.FindClientByCommander(commanderID); ok {
+		return true
+	}
+	return server.relay != nil && server.relay.Online(commanderID)
+}
+
+// PushToCommander sends a message to a commander, wherever the commander is
+// connected. Offline commanders are skipped.
+func (server *Server) PushToCommander(commanderID uint32, packetID int, message proto.Message) {
+	if client, ok := server.FindClientByCommander(commanderID); ok {
+		client.Push(packetID, message)
+		return
+	}
+	if server.relay == nil || !server.relay.Online(commanderID) {
+		return
+	}
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Server", "Push", fmt.Sprintf("SC_%d -> %v", packetID, err), logger.LOG_LEVEL_ERROR)
+		return
+	}
+	server.relay.Push(commanderID, packetID, payload)
+}
+
+// DeliverToCommander sends a marshalled message to a commander connected to
+// this node, reporting whether it was delivered.
+func (server *Server) DeliverToCommander(commanderID uint32, packetID int, payload []byte) bool {
+	client, ok := server.FindClientByCommander(commanderID)
+	if !ok {
+		return false
+	}
+	return client.PushRaw(packetID, payload) == nil
+}
+
+// departedLocked returns the commander of a removed client when no other
+// session of it is left on this node. clientsMutex must be held.
+func (server *Server) departedLocked(client *Client) []uint32 {
+	if server.relay == nil || client.Commander == nil {
+		return nil
+	}
+	commanderID := client.Commander.CommanderID
+	for _, other := range server.clients {
+		if other.Commander != nil && other.Commander.CommanderID == commanderID {
+			return nil
+		}
+	}
+	return []uint32{commanderID}
+}
+
+func (server *Server) releasePresence(commanderIDs []uint32) {
+	for _, commanderID := range commanderIDs {
+		server.relay.Left(commanderID)
+	}
+}
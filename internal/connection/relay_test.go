@@ -0,0 +1,140 @@
+package connection
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+type fakeRelay struct {
+	online  map[uint32]bool
+	kicks   []uint32
+	pushes  []uint32
+	chats   []uint32
+	chatAll int
+	guild   [][]uint32
+	joined  []uint32
+	left    []uint32
+	reloads [][]string
+}
+
+func (relay *fakeRelay) Kick(commanderID uint32, reason uint8) {
+	relay.kicks = append(relay.kicks, commanderID)
+}
+
+func (relay *fakeRelay) Push(commanderID uint32, packetID int, payload []byte) {
+	relay.pushes = append(relay.pushes, commanderID)
+}
+
+func (relay *fakeRelay) Chat(roomID uint32, message *protobuf.SC_50101) {
+	relay.chats = append(relay.chats, roomID)
+}
+
+func (relay *fakeRelay) ChatAll(message *protobuf.SC_50101) {
+	relay.chatAll++
+}
+
+func (relay *fakeRelay) GuildChat(memberIDs []uint32, message *protobuf.SC_60008) {
+	relay.guild = append(relay.guild, memberIDs)
+}
+
+func (relay *fakeRelay) GameDataChanged(categories []string) {
+	relay.reloads = append(relay.reloads, categories)
+}
+
+func (relay *fakeRelay) Online(commanderID uint32) bool {
+	return relay.online[commanderID]
+}
+
+func (relay *fakeRelay) Joined(commanderID uint32) {
+	relay.joined = append(relay.joined, commanderID)
+}
+
+func (relay *fakeRelay) Left(commanderID uint32) {
+	relay.left = append(relay.left, commanderID)
+}
+
+func relayTestMessage() *protobuf.SC_50101 {
+	return &protobuf.SC_50101{
+		Player: &protobuf.PLAYER_INFO_P50{
+			Id:   proto.Uint32(1),
+			Name: proto.String("Tester"),
+			Lv:   proto.Uint32(1),
+		},
+		Type:    proto.Uint32(orm.MSG_TYPE_NORMAL),
+		Content: proto.String("hi"),
+	}
+}
+
+func TestRelayPushToCommander(t *testing.T) {
+	server, _ := initServerTest(t)
+	relay := &fakeRelay{online: map[uint32]bool{200: true}}
+	server.SetRelay(relay)
+
+	local := &Client{Hash: 1, Commander: &orm.Commander{CommanderID: 100}}
+	local.initQueues()
+	server.clients[local.Hash] = local
+
+	server.PushToCommander(100, 50101, relayTestMessage())
+	server.PushToCommander(200, 50101, relayTestMessage())
+	server.PushToCommander(300, 50101, relayTestMessage())
+
+	if local.Buffer.Len() == 0 {
+		t.Fatalf("expected local commander to receive the push")
+	}
+	if len(relay.pushes) != 1 || relay.pushes[0] != 200 {
+		t.Fatalf("expected only the remote commander to be relayed, got %v", relay.pushes)
+	}
+	if !server.IsCommanderOnline(100) || !server.IsCommanderOnline(200) || server.IsCommanderOnline(300) {
+		t.Fatalf("unexpected online states")
+	}
+}
+
+func TestRelayBroadcasts(t *testing.T) {
+	server, _ := initServerTest(t)
+	relay := &fakeRelay{}
+	server.SetRelay(relay)
+
+	server.BroadcastChat(0, relayTestMessage())
+	server.BroadcastChatAll(relayTestMessage())
+	server.BroadcastGuildChat([]uint32{1, 2}, &protobuf.SC_60008{})
+	server.DisconnectCommander(42, 0, nil)
+
+	if len(relay.chats) != 1 || relay.chats[0] != 0 {
+		t.Fatalf("expected room chat to be relayed, got %v", relay.chats)
+	}
+	if relay.chatAll != 1 {
+		t.Fatalf("expected global chat to be relayed")
+	}
+	if len(relay.guild) != 1 || len(relay.guild[0]) != 2 {
+		t.Fatalf("expected guild chat to be relayed, got %v", relay.guild)
+	}
+	if len(relay.kicks) != 1 || relay.kicks[0] != 42 {
+		t.Fatalf("expected kick to be relayed, got %v", relay.kicks)
+	}
+}
+
+func TestRelayPresence(t *testing.T) {
+	server, _ := initServerTest(t)
+	relay := &fakeRelay{}
+	server.SetRelay(relay)
+
+	first := &Client{Hash: 1, Commander: &orm.Commander{CommanderID: 100}, Connection: mockToNetConn(&mockConn{})}
+	second := &Client{Hash: 2, Commander: &orm.Commander{CommanderID: 100}, Connection: mockToNetConn(&mockConn{})}
+	server.clients[first.Hash] = first
+	server.clients[second.Hash] = second
+	server.CommanderJoined(first)
+
+	server.RemoveClient(first)
+	if len(relay.left) != 0 {
+		t.Fatalf("expected presence to be kept while another session is connected")
+	}
+	server.RemoveClient(second)
+	server.RemoveClient(second)
+	if len(relay.joined) != 1 || len(relay.left) != 1 || relay.left[0] != 100 {
+		t.Fatalf("expected one join and one leave, got %v / %v", relay.joined, relay.left)
+	}
+}
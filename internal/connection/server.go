@@ -47,6 +47,9 @@ type Server struct {
 	rooms        map[uint32][]*Client // Game chat rooms
 	clientsMutex sync.RWMutex
 	clients      map[uint32]*Client // Socket hash -> Client
+
+	// Forwards deliveries to the other nodes when running in a cluster
+	relay Relay
 }
 
 var (
@@ -78,10 +81,17 @@ func (server *Server) AddClient(client *Client) {
 
 func (server *Server) RemoveClient(client *Client) {
 	server.clientsMutex.Lock()
-	defer server.clientsMutex.Unlock()
 	logger.LogEvent("Server", "Goodbye", fmt.Sprintf("%s:%d", client.IP, client.Port), logger.LOG_LEVEL_DEBUG)
 	client.Close()
+	// kicked clients were already removed, and their presence released
+	registered := server.clients[client.Hash] == client
 	delete(server.clients, client.Hash)
+	var left []uint32
+	if registered {
+		left = server.departedLocked(client)
+	}
+	server.clientsMutex.Unlock()
+	server.releasePresence(left)
 }
 
 func (server *Server) HandleConnection(conn net.Conn) {
@@ -264,9 +274,20 @@ func (server *Server) FindClientByCommander(commanderID uint32) (*Client, bool)
 	return nil, false
 }
 
+// DisconnectCommander kicks the sessions of a commander, on this node and
+// on the other nodes of the cluster, except excludeClient. It reports whether
+// a session was kicked on this node.
 func (server *Server) DisconnectCommander(commanderID uint32, reason uint8, excludeClient *Client) bool {
+	kicked := server.DisconnectLocalCommander(commanderID, reason, excludeClient)
+	if relay := server.relay; relay != nil {
+		relay.Kick(commanderID, reason)
+	}
+	return kicked
+}
+
+// DisconnectLocalCommander is DisconnectCommander for this node only.
+func (server *Server) DisconnectLocalCommander(commanderID uint32, reason uint8, excludeClient *Client) bool {
 	server.clientsMutex.Lock()
-	defer server.clientsMutex.Unlock()
 
 	var existingClient *Client
 	for _, client := range server.clients {
@@ -276,9 +297,11 @@ func (server *Server) DisconnectCommander(commanderID uint32, reason uint8, excl
 		}
 	}
 	if existingClient == nil {
+		server.clientsMutex.Unlock()
 		return false
 	}
 	if excludeClient != nil && existingClient == excludeClient {
+		server.clientsMutex.Unlock()
 		return false
 	}
 
@@ -297,6 +320,9 @@ func (server *Server) DisconnectCommander(commanderID uint32, reason uint8, excl
 	}
 	existingClient.Close()
 	delete(server.clients, existingClient.Hash)
+	left := server.departedLocked(existingClient)
+	server.clientsMutex.Unlock()
+	server.releasePresence(left)
 	return true
 }
 
@@ -334,6 +360,10 @@ func (server *Server) MaintenanceEnabled() bool {
 
 // Sends SC_10999 (disconnected from server) message to every connected clients, reasons are defined in consts/disconnect_reasons.go
 func (server *Server) DisconnectAll(reason uint8) {
+	var left []uint32
+	defer func() {
+		server.releasePresence(left)
+	}()
 	server.clientsMutex.Lock()
 	defer server.clientsMutex.Unlock()
 	for _, client := range server.clients {
@@ -344,6 +374,7 @@ func (server *Server) DisconnectAll(reason uint8) {
 		}
 		client.Close()
 		delete(server.clients, client.Hash)
+		left = append(left, server.departedLocked(client)...)
 	}
 }
 
@@ -387,18 +418,88 @@ func (server *Server) SendMessage(sender *Client, message orm.Message) {
 		Type:    proto.Uint32(orm.MSG_TYPE_NORMAL),
 		Content: proto.String(message.Content),
 	}
+	server.BroadcastChat(message.RoomID, &msgPacket)
+}
+
+// BroadcastChat sends a chat message to the clients of a room, on every
+// node of the cluster.
+func (server *Server) BroadcastChat(roomID uint32, message *protobuf.SC_50101) {
+	server.DeliverChat(roomID, message)
+	if relay := server.relay; relay != nil {
+		relay.Chat(roomID, message)
+	}
+}
+
+// DeliverChat is BroadcastChat for this node only. Messages are pushed, as
+// they are delivered outside of the recipients' dispatchers.
+func (server *Server) DeliverChat(roomID uint32, message *protobuf.SC_50101) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Server", "Chat", fmt.Sprintf("failed to marshal message: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+		return
+	}
 	server.roomsMutex.RLock()
 	defer server.roomsMutex.RUnlock()
-	for _, client := range server.rooms[message.RoomID] {
-		client.SendMessage(50101, &msgPacket)
+	for _, client := range server.rooms[roomID] {
+		client.PushRaw(50101, data)
 	}
 }
 
-func (server *Server) BroadcastGuildChat(message *protobuf.SC_60008) {
+// BroadcastChatAll sends a chat message to the clients of every room, on
+// every node of the cluster.
+func (server *Server) BroadcastChatAll(message *protobuf.SC_50101) {
+	server.DeliverChatAll(message)
+	if relay := server.relay; relay != nil {
+		relay.ChatAll(message)
+	}
+}
+
+// DeliverChatAll is BroadcastChatAll for this node only.
+func (server *Server) DeliverChatAll(message *protobuf.SC_50101) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Server", "Chat", fmt.Sprintf("failed to marshal message: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+		return
+	}
+	server.roomsMutex.RLock()
+	defer server.roomsMutex.RUnlock()
+	for _, clients := range server.rooms {
+		for _, client := range clients {
+			client.PushRaw(50101, data)
+		}
+	}
+}
+
+// BroadcastGuildChat sends message to the connected clients whose commander
+// is listed in memberIDs, on every node of the cluster.
+func (server *Server) BroadcastGuildChat(memberIDs []uint32, message *protobuf.SC_60008) {
+	server.DeliverGuildChat(memberIDs, message)
+	if relay := server.relay; relay != nil {
+		relay.GuildChat(memberIDs, message)
+	}
+}
+
+// DeliverGuildChat is BroadcastGuildChat for this node only.
+func (server *Server) DeliverGuildChat(memberIDs []uint32, message *protobuf.SC_60008) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		logger.LogEvent("Server", "GuildChat", fmt.Sprintf("failed to marshal message: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+		return
+	}
+	members := make(map[uint32]struct{}, len(memberIDs))
+	for _, id := range memberIDs {
+		members[id] = struct{}{}
+	}
 	server.clientsMutex.RLock()
 	defer server.clientsMutex.RUnlock()
 	for _, client := range server.clients {
-		client.SendMessage(60008, message)
+		if client.Commander == nil {
+			continue
+		}
+		if _, ok := members[client.Commander.CommanderID]; !ok {
+			continue
+		}
+		client.PushRaw(60008, data)
 	}
 }
 
@@ -434,6 +535,11 @@ func SendProtoMessage(packetId int, client *Client, message any) (int, int, erro
 		client.CloseWithError(err)
 		return 0, packetId, err
 	}
+	return SendRawMessage(packetId, client, data)
+}
+
+// SendRawMessage buffers an already marshalled message.
+func SendRawMessage(packetId int, client *Client, data []byte) (int, int, error) {
 	debug.InsertPacket(packetId, &data)
 	InjectPacketHeader(packetId, &data, client.PacketIndex)
 	n, err := client.Buffer.Write(data)
@@ -685,6 +685,47 @@ func TestSendMessagePayload(t *testing.T) {
 	}
 }
 
+func TestBroadcastGuildChatOnlyReachesMembers(t *testing.T) {
+	server, _ := initServerTest(t)
+
+	member := &Client{Hash: 1, Commander: &orm.Commander{CommanderID: 100}}
+	outsider := &Client{Hash: 2, Commander: &orm.Commander{CommanderID: 200}}
+	anonymous := &Client{Hash: 3}
+	member.initQueues()
+	outsider.initQueues()
+	anonymous.initQueues()
+	server.clients[member.Hash] = member
+	server.clients[outsider.Hash] = outsider
+	server.clients[anonymous.Hash] = anonymous
+
+	message := &protobuf.SC_60008{Chat: &protobuf.GUIDE_CHAT{
+		Player: &protobuf.PLAYER_INFO_P60{
+			Id:   proto.Uint32(100),
+			Name: proto.String("Member"),
+			Lv:   proto.Uint32(1),
+			Display: &protobuf.DISPLAYINFO{
+				Icon:          proto.Uint32(0),
+				Skin:          proto.Uint32(0),
+				IconFrame:     proto.Uint32(0),
+				ChatFrame:     proto.Uint32(0),
+				IconTheme:     proto.Uint32(0),
+				MarryFlag:     proto.Uint32(0),
+				TransformFlag: proto.Uint32(0),
+			},
+		},
+		Content: proto.String("hi"),
+		Time:    proto.Uint32(0),
+	}}
+	server.BroadcastGuildChat([]uint32{100, 300}, message)
+
+	if member.Buffer.Len() == 0 {
+		t.Fatalf("expected guild member to receive chat")
+	}
+	if outsider.Buffer.Len() != 0 || anonymous.Buffer.Len() != 0 {
+		t.Fatalf("expected non-members to receive nothing")
+	}
+}
+
 func TestGeneratePacketHeader(t *testing.T) {
 	payload := []byte{0x01, 0x02}
 	header := GeneratePacketHeader(0x1234, &payload, 0x0001)
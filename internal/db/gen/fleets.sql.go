@@ -76,3 +76,19 @@ func (q *Queries) UpdateFleetShipList(ctx context.Context, arg UpdateFleetShipLi
 	_, err := q.db.Exec(ctx, updateFleetShipList, arg.ID, arg.ShipList)
 	return err
 }
+
+const updateFleetMeowfficerList = `-- name: UpdateFleetMeowfficerList :exec
+UPDATE fleets
+SET meowfficer_list = $2
+WHERE id = $1
+`
+
+type UpdateFleetMeowfficerListParams struct {
+	ID             int64
+	MeowfficerList []byte
+}
+
+func (q *Queries) UpdateFleetMeowfficerList(ctx context.Context, arg UpdateFleetMeowfficerListParams) error {
+	_, err := q.db.Exec(ctx, updateFleetMeowfficerList, arg.ID, arg.MeowfficerList)
+	return err
+}
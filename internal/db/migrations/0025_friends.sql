@@ -0,0 +1,30 @@
+-- 0025_friends.sql
+
+CREATE TABLE IF NOT EXISTS commander_friends (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  friend_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, friend_id),
+  CONSTRAINT commander_friends_not_self CHECK (commander_id <> friend_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commander_friends_friend_id ON commander_friends (friend_id);
+
+CREATE TABLE IF NOT EXISTS commander_friend_requests (
+  sender_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  receiver_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  content text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (sender_id, receiver_id),
+  CONSTRAINT commander_friend_requests_not_self CHECK (sender_id <> receiver_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commander_friend_requests_receiver_id ON commander_friend_requests (receiver_id);
+
+CREATE TABLE IF NOT EXISTS commander_friend_blocks (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  blocked_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, blocked_id),
+  CONSTRAINT commander_friend_blocks_not_self CHECK (commander_id <> blocked_id)
+);
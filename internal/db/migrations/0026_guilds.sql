@@ -0,0 +1,50 @@
+-- 0026_guilds.sql
+
+CREATE TABLE IF NOT EXISTS guilds (
+  id bigserial PRIMARY KEY,
+  name text NOT NULL,
+  faction bigint NOT NULL DEFAULT 1,
+  policy bigint NOT NULL DEFAULT 1,
+  level bigint NOT NULL DEFAULT 1,
+  exp bigint NOT NULL DEFAULT 0,
+  capacity bigint NOT NULL DEFAULT 30,
+  announce text NOT NULL DEFAULT '',
+  manifesto text NOT NULL DEFAULT '',
+  change_faction_cd bigint NOT NULL DEFAULT 0,
+  kick_leader_cd bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guilds_name ON guilds (lower(name));
+
+CREATE TABLE IF NOT EXISTS guild_members (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  duty bigint NOT NULL,
+  liveness bigint NOT NULL DEFAULT 0,
+  joined_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_members_guild_id ON guild_members (guild_id);
+
+CREATE TABLE IF NOT EXISTS guild_applications (
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  content text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (guild_id, commander_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_applications_commander_id ON guild_applications (commander_id);
+
+CREATE TABLE IF NOT EXISTS guild_logs (
+  id bigserial PRIMARY KEY,
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  cmd bigint NOT NULL,
+  commander_id bigint NOT NULL,
+  name text NOT NULL DEFAULT '',
+  arg1 bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_logs_guild_time ON guild_logs (guild_id, created_at DESC);
@@ -0,0 +1,89 @@
+-- 0027_guild_operations.sql
+
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS capital bigint NOT NULL DEFAULT 0;
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS tech_group bigint NOT NULL DEFAULT 0;
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS tech_cancel_cnt bigint NOT NULL DEFAULT 0;
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS weekly_task_progress bigint NOT NULL DEFAULT 0;
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS weekly_task_week bigint NOT NULL DEFAULT 0;
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS active_event_cnt bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE guild_members ADD COLUMN IF NOT EXISTS contribution bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS guild_technologies (
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  tech_group bigint NOT NULL,
+  level bigint NOT NULL DEFAULT 0,
+  progress bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (guild_id, tech_group)
+);
+
+CREATE TABLE IF NOT EXISTS guild_capital_logs (
+  id bigserial PRIMARY KEY,
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  commander_id bigint NOT NULL,
+  name text NOT NULL DEFAULT '',
+  event_type bigint NOT NULL,
+  event_target jsonb NOT NULL DEFAULT '[]'::jsonb,
+  amount bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_capital_logs_guild_time ON guild_capital_logs (guild_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS commander_guild_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  donate_count bigint NOT NULL DEFAULT 0,
+  donate_week bigint NOT NULL DEFAULT 0,
+  donate_tasks jsonb NOT NULL DEFAULT '[]'::jsonb,
+  weekly_task_flag bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS commander_guild_technologies (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  tech_group bigint NOT NULL,
+  level bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, tech_group)
+);
+
+CREATE TABLE IF NOT EXISTS guild_operations (
+  guild_id bigint PRIMARY KEY REFERENCES guilds(id) ON DELETE CASCADE,
+  operation_id bigint NOT NULL,
+  start_time bigint NOT NULL,
+  boss_id bigint NOT NULL DEFAULT 0,
+  boss_hp bigint NOT NULL DEFAULT 0,
+  boss_damage bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS guild_operation_events (
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  event_id bigint NOT NULL,
+  position bigint NOT NULL,
+  start_time bigint NOT NULL DEFAULT 0,
+  complete_time bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (guild_id, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS guild_operation_event_ships (
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  event_id bigint NOT NULL,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  ship_id bigint NOT NULL,
+  PRIMARY KEY (guild_id, commander_id, ship_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_guild_operation_event_ships_event ON guild_operation_event_ships (guild_id, event_id);
+
+CREATE TABLE IF NOT EXISTS guild_operation_damages (
+  guild_id bigint NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  damage bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (guild_id, commander_id)
+);
+
+CREATE TABLE IF NOT EXISTS guild_assault_ships (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  pos bigint NOT NULL,
+  ship_id bigint NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, pos)
+);
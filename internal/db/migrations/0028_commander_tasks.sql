@@ -0,0 +1,20 @@
+-- 0028_commander_tasks.sql
+
+CREATE TABLE IF NOT EXISTS commander_tasks (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  task_id bigint NOT NULL,
+  progress bigint NOT NULL DEFAULT 0,
+  accept_time bigint NOT NULL DEFAULT 0,
+  submit_time bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_task_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  weekly_pt bigint NOT NULL DEFAULT 0,
+  weekly_reward_lv bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+  last_weekly_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,26 @@
+-- 0029_daily_raids.sql
+
+CREATE TABLE IF NOT EXISTS commander_daily_raid_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  elite_expedition_count bigint NOT NULL DEFAULT 0,
+  escort_expedition_count bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS commander_daily_raid_counts (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  daily_id bigint NOT NULL,
+  count bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, daily_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_stage_clears (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  stage_id bigint NOT NULL,
+  best_score bigint NOT NULL DEFAULT 0,
+  clear_count bigint NOT NULL DEFAULT 0,
+  first_cleared_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, stage_id)
+);
@@ -0,0 +1,43 @@
+-- 0030_build_banners.sql
+
+CREATE TABLE IF NOT EXISTS build_banners (
+  id bigserial PRIMARY KEY,
+  pool_id bigint NOT NULL,
+  name text NOT NULL,
+  region text,
+  common_weight bigint NOT NULL DEFAULT 30,
+  rare_weight bigint NOT NULL DEFAULT 51,
+  elite_weight bigint NOT NULL DEFAULT 12,
+  super_rare_weight bigint NOT NULL DEFAULT 7,
+  ultra_rare_weight bigint NOT NULL DEFAULT 0,
+  pity_threshold bigint NOT NULL DEFAULT 0,
+  starts_at timestamptz,
+  ends_at timestamptz,
+  enabled boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_build_banners_pool_id ON build_banners (pool_id);
+
+CREATE TABLE IF NOT EXISTS build_banner_rate_ups (
+  banner_id bigint NOT NULL REFERENCES build_banners(id) ON DELETE CASCADE,
+  ship_id bigint NOT NULL REFERENCES ships(template_id) ON DELETE CASCADE,
+  rate bigint NOT NULL,
+  PRIMARY KEY (banner_id, ship_id)
+);
+
+CREATE TABLE IF NOT EXISTS build_roll_logs (
+  id bigserial PRIMARY KEY,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  banner_id bigint REFERENCES build_banners(id) ON DELETE SET NULL,
+  pool_id bigint NOT NULL,
+  ship_id bigint NOT NULL,
+  rarity_id bigint NOT NULL,
+  rate_up boolean NOT NULL DEFAULT false,
+  pity boolean NOT NULL DEFAULT false,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_build_roll_logs_banner_id ON build_roll_logs (banner_id);
+CREATE INDEX IF NOT EXISTS idx_build_roll_logs_commander_id ON build_roll_logs (commander_id);
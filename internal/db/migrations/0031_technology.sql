@@ -0,0 +1,47 @@
+-- 0031_technology.sql
+
+CREATE TABLE IF NOT EXISTS commander_tech_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  refresh_flag bigint NOT NULL DEFAULT 0,
+  cold_time bigint NOT NULL DEFAULT 0,
+  catchup_version bigint NOT NULL DEFAULT 0,
+  catchup_target bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS commander_tech_projects (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  refresh_id bigint NOT NULL,
+  tech_id bigint NOT NULL,
+  finish_time bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, refresh_id, tech_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_tech_targets (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  refresh_id bigint NOT NULL,
+  target bigint NOT NULL,
+  PRIMARY KEY (commander_id, refresh_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_tech_catchups (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  version bigint NOT NULL,
+  blueprint_id bigint NOT NULL,
+  number bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, version, blueprint_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_blueprints (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  blueprint_id bigint NOT NULL,
+  ship_id bigint NOT NULL DEFAULT 0,
+  start_time bigint NOT NULL DEFAULT 0,
+  duration bigint NOT NULL DEFAULT 0,
+  level bigint NOT NULL DEFAULT 0,
+  exp bigint NOT NULL DEFAULT 0,
+  submitted_tasks jsonb NOT NULL DEFAULT '[]'::jsonb,
+  PRIMARY KEY (commander_id, blueprint_id)
+);
@@ -0,0 +1,19 @@
+-- 0032_fleet_tech.sql
+
+CREATE TABLE IF NOT EXISTS commander_fleet_techs (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  group_id bigint NOT NULL,
+  effect_tech_id bigint NOT NULL DEFAULT 0,
+  study_tech_id bigint NOT NULL DEFAULT 0,
+  study_finish_time bigint NOT NULL DEFAULT 0,
+  rewarded_tech bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, group_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_fleet_tech_sets (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  ship_type bigint NOT NULL,
+  attr_type bigint NOT NULL,
+  set_value bigint NOT NULL,
+  PRIMARY KEY (commander_id, ship_type, attr_type)
+);
@@ -0,0 +1,62 @@
+-- 0033_meowfficers.sql
+
+CREATE TABLE IF NOT EXISTS commander_meowfficers (
+  id bigserial PRIMARY KEY,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  template_id bigint NOT NULL,
+  level bigint NOT NULL DEFAULT 1,
+  exp bigint NOT NULL DEFAULT 0,
+  is_locked boolean NOT NULL DEFAULT false,
+  name text NOT NULL DEFAULT '',
+  rename_time bigint NOT NULL DEFAULT 0,
+  abilities jsonb NOT NULL DEFAULT '[]'::jsonb,
+  ability_origin jsonb NOT NULL DEFAULT '[]'::jsonb,
+  ability_candidates jsonb NOT NULL DEFAULT '[]'::jsonb,
+  ability_time bigint NOT NULL DEFAULT 0,
+  used_pt bigint NOT NULL DEFAULT 0,
+  skill_id bigint NOT NULL DEFAULT 0,
+  skill_exp bigint NOT NULL DEFAULT 0,
+  home_feed_time bigint NOT NULL DEFAULT 0,
+  home_play_time bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_commander_meowfficers_commander_id
+  ON commander_meowfficers (commander_id);
+
+CREATE TABLE IF NOT EXISTS commander_meowfficer_boxes (
+  id bigserial PRIMARY KEY,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  pool_id bigint NOT NULL,
+  begin_time bigint NOT NULL DEFAULT 0,
+  finish_time bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_commander_meowfficer_boxes_commander_id
+  ON commander_meowfficer_boxes (commander_id);
+
+CREATE TABLE IF NOT EXISTS commander_meowfficer_homes (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  level bigint NOT NULL DEFAULT 1,
+  exp bigint NOT NULL DEFAULT 0,
+  clean_time bigint NOT NULL DEFAULT 0,
+  usage_count bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00'
+);
+
+CREATE TABLE IF NOT EXISTS commander_meowfficer_home_slots (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  slot_id bigint NOT NULL,
+  meowfficer_id bigint NOT NULL DEFAULT 0,
+  style bigint NOT NULL DEFAULT 0,
+  exp_time bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, slot_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_meowfficer_presets (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  preset_id bigint NOT NULL,
+  name text NOT NULL DEFAULT '',
+  meowfficers jsonb NOT NULL DEFAULT '[]'::jsonb,
+  PRIMARY KEY (commander_id, preset_id)
+);
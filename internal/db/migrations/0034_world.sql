@@ -0,0 +1,46 @@
+-- 0034_world.sql
+
+CREATE TABLE IF NOT EXISTS commander_worlds (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  camp bigint NOT NULL DEFAULT 0,
+  map_id bigint NOT NULL DEFAULT 0,
+  progress bigint NOT NULL DEFAULT 0,
+  action_power bigint NOT NULL DEFAULT 0,
+  action_power_extra bigint NOT NULL DEFAULT 0,
+  last_recover_time bigint NOT NULL DEFAULT 0,
+  action_power_fetch_count bigint NOT NULL DEFAULT 0,
+  step_count bigint NOT NULL DEFAULT 0,
+  treasure_count bigint NOT NULL DEFAULT 0,
+  activate_count bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00'
+);
+
+CREATE TABLE IF NOT EXISTS commander_world_maps (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  map_id bigint NOT NULL,
+  is_cleared boolean NOT NULL DEFAULT false,
+  PRIMARY KEY (commander_id, map_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_world_groups (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  group_id bigint NOT NULL,
+  ship_list jsonb NOT NULL DEFAULT '[]'::jsonb,
+  pos_row bigint NOT NULL DEFAULT 0,
+  pos_column bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, group_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_world_items (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  item_id bigint NOT NULL,
+  count bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, item_id)
+);
+
+CREATE TABLE IF NOT EXISTS commander_world_port_goods (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  goods_id bigint NOT NULL,
+  bought bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (commander_id, goods_id)
+);
@@ -0,0 +1,45 @@
+-- 0035_world_bosses.sql
+
+CREATE TABLE IF NOT EXISTS world_bosses (
+  id bigserial PRIMARY KEY,
+  owner_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  template_id bigint NOT NULL,
+  level bigint NOT NULL DEFAULT 1,
+  hp bigint NOT NULL,
+  max_hp bigint NOT NULL,
+  expire_time bigint NOT NULL,
+  kill_time bigint NOT NULL DEFAULT 0,
+  fight_count bigint NOT NULL DEFAULT 0,
+  friend_support boolean NOT NULL DEFAULT false,
+  guild_support boolean NOT NULL DEFAULT false,
+  world_support boolean NOT NULL DEFAULT false,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_world_bosses_owner_id
+  ON world_bosses (owner_id);
+
+CREATE TABLE IF NOT EXISTS world_boss_damages (
+  boss_id bigint NOT NULL REFERENCES world_bosses(id) ON DELETE CASCADE,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  damage bigint NOT NULL DEFAULT 0,
+  max_damage bigint NOT NULL DEFAULT 0,
+  fight_count bigint NOT NULL DEFAULT 0,
+  awarded boolean NOT NULL DEFAULT false,
+  PRIMARY KEY (boss_id, commander_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_world_boss_damages_commander_id
+  ON world_boss_damages (commander_id);
+
+CREATE TABLE IF NOT EXISTS commander_world_boss_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  summon_pt bigint NOT NULL DEFAULT 0,
+  summon_pt_daily_acc bigint NOT NULL DEFAULT 0,
+  fight_count bigint NOT NULL DEFAULT 0,
+  fight_count_update_time bigint NOT NULL DEFAULT 0,
+  auto_fight_boss_id bigint NOT NULL DEFAULT 0,
+  auto_fight_count bigint NOT NULL DEFAULT 0,
+  auto_fight_finish_time bigint NOT NULL DEFAULT 0,
+  last_daily_reset_at timestamptz NOT NULL DEFAULT '1970-01-01 00:00:00+00'
+);
@@ -0,0 +1,15 @@
+-- 0036_cheater_marks.sql
+
+CREATE TABLE IF NOT EXISTS cheater_marks (
+  id bigserial PRIMARY KEY,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  source text NOT NULL,
+  reason text NOT NULL,
+  system bigint NOT NULL DEFAULT 0,
+  stage_id bigint NOT NULL DEFAULT 0,
+  detail text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cheater_marks_commander_id
+  ON cheater_marks (commander_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- 0037_game_data_checksums.sql
+
+CREATE TABLE IF NOT EXISTS game_data_checksums (
+  table_name text PRIMARY KEY,
+  checksum text NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+-- 0038_activity_schedule_overrides.sql
+
+CREATE TABLE IF NOT EXISTS activity_schedule_overrides (
+  id bigserial PRIMARY KEY,
+  activity_id bigint NOT NULL,
+  region text NOT NULL DEFAULT '',
+  action text NOT NULL,
+  starts_at timestamptz,
+  ends_at timestamptz,
+  note text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_schedule_overrides_activity_id
+  ON activity_schedule_overrides (activity_id);
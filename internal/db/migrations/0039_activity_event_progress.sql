@@ -0,0 +1,14 @@
+-- 0039_activity_event_progress.sql
+
+CREATE TABLE IF NOT EXISTS activity_event_progress (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  activity_id bigint NOT NULL,
+  pt bigint NOT NULL DEFAULT 0,
+  claimed_targets jsonb NOT NULL DEFAULT '[]'::jsonb,
+  sign_days bigint NOT NULL DEFAULT 0,
+  last_sign_at bigint NOT NULL DEFAULT 0,
+  shop_purchases jsonb NOT NULL DEFAULT '{}'::jsonb,
+  claimed_tasks jsonb NOT NULL DEFAULT '[]'::jsonb,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, activity_id)
+);
@@ -0,0 +1,24 @@
+-- 0040_arena.sql
+
+CREATE TABLE IF NOT EXISTS arena_seasons (
+  season_id bigserial PRIMARY KEY,
+  starts_at timestamptz NOT NULL,
+  ends_at timestamptz NOT NULL,
+  settled_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS arena_states (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  season_id bigint NOT NULL,
+  score bigint NOT NULL DEFAULT 0,
+  fight_count bigint NOT NULL DEFAULT 0,
+  fight_count_reset_time bigint NOT NULL DEFAULT 0,
+  flash_target_count bigint NOT NULL DEFAULT 0,
+  rival_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  wins bigint NOT NULL DEFAULT 0,
+  losses bigint NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_arena_states_season_score
+  ON arena_states (season_id, score DESC, commander_id);
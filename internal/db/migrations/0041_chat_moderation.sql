@@ -0,0 +1,10 @@
+-- 0041_chat_moderation.sql
+
+CREATE TABLE IF NOT EXISTS chat_mutes (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  reason text NOT NULL DEFAULT '',
+  muted_until timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_sender_id_sent_at ON messages (sender_id, sent_at DESC);
@@ -0,0 +1,15 @@
+-- 0042_cluster.sql
+
+CREATE TABLE IF NOT EXISTS cluster_nodes (
+  node_id text PRIMARY KEY,
+  started_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  heartbeat_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS commander_presence (
+  commander_id bigint PRIMARY KEY REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  node_id text NOT NULL,
+  connected_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_commander_presence_node_id ON commander_presence (node_id);
@@ -0,0 +1,6 @@
+-- 0043_morale.sql
+
+-- unix time of the last morale tick applied to the commander's ships
+ALTER TABLE commanders ADD COLUMN IF NOT EXISTS morale_tick_at bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE owned_ships ADD COLUMN IF NOT EXISTS in_onsen boolean NOT NULL DEFAULT false;
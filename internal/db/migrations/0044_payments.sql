@@ -0,0 +1,26 @@
+-- 0044_payments.sql
+
+-- Ledger of the charges made in the shop. An order goes pending -> paid
+-- (approved by the payment provider) -> delivered (rewards granted), or
+-- pending -> failed. A delivered order can be refunded, and the refund is
+-- settled (repaid_at) once the commander pays for it again.
+CREATE TABLE IF NOT EXISTS payment_orders (
+  id bigserial PRIMARY KEY,
+  pay_id text NOT NULL UNIQUE,
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  shop_id bigint NOT NULL,
+  kind text NOT NULL DEFAULT 'charge',
+  provider text NOT NULL,
+  status text NOT NULL DEFAULT 'pending',
+  money bigint NOT NULL DEFAULT 0,
+  gem bigint NOT NULL DEFAULT 0,
+  gem_free bigint NOT NULL DEFAULT 0,
+  fail_code bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  paid_at timestamptz,
+  delivered_at timestamptz,
+  refunded_at timestamptz,
+  repaid_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_orders_commander_id_status ON payment_orders (commander_id, status);
@@ -0,0 +1,39 @@
+-- 0045_drop_tables.sql
+
+CREATE TABLE IF NOT EXISTS drop_tables (
+  id bigserial PRIMARY KEY,
+  kind text NOT NULL CHECK (kind IN ('chapter', 'event', 'item')),
+  source_id bigint NOT NULL,
+  name text NOT NULL DEFAULT '',
+  rolls bigint NOT NULL DEFAULT 1,
+  empty_weight bigint NOT NULL DEFAULT 0,
+  rank_s_rate bigint NOT NULL DEFAULT 100,
+  rank_a_rate bigint NOT NULL DEFAULT 100,
+  rank_b_rate bigint NOT NULL DEFAULT 100,
+  rate_multiplier bigint NOT NULL DEFAULT 100,
+  enabled boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (kind, source_id)
+);
+
+CREATE TABLE IF NOT EXISTS drop_table_entries (
+  table_id bigint NOT NULL REFERENCES drop_tables(id) ON DELETE CASCADE,
+  position bigint NOT NULL,
+  drop_type bigint NOT NULL,
+  drop_id bigint NOT NULL,
+  count bigint NOT NULL DEFAULT 1,
+  weight bigint NOT NULL,
+  PRIMARY KEY (table_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS drop_bonuses (
+  id bigserial PRIMARY KEY,
+  name text NOT NULL DEFAULT '',
+  kind text CHECK (kind IN ('chapter', 'event', 'item')),
+  source_id bigint,
+  rate bigint NOT NULL,
+  starts_at timestamptz,
+  ends_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
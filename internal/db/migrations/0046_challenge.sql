@@ -0,0 +1,27 @@
+-- 0046_challenge.sql
+
+CREATE TABLE IF NOT EXISTS challenge_runs (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  activity_id bigint NOT NULL,
+  mode bigint NOT NULL,
+  season_id bigint NOT NULL,
+  level bigint NOT NULL DEFAULT 1,
+  score bigint NOT NULL DEFAULT 0,
+  dungeon_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  buff_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  groups jsonb NOT NULL DEFAULT '[]'::jsonb,
+  started_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, activity_id, mode)
+);
+
+CREATE TABLE IF NOT EXISTS challenge_scores (
+  commander_id bigint NOT NULL REFERENCES commanders(commander_id) ON DELETE CASCADE,
+  activity_id bigint NOT NULL,
+  season_id bigint NOT NULL,
+  max_score bigint NOT NULL DEFAULT 0,
+  max_level bigint NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (commander_id, activity_id, season_id)
+);
+CREATE INDEX IF NOT EXISTS idx_challenge_scores_activity ON challenge_scores(activity_id, max_score DESC);
@@ -0,0 +1,5 @@
+-- 0047_theme_previews.sql
+
+-- set when the previews of a published theme were found in the object store
+-- at publish time, so preview md5s are served without checking it again
+ALTER TABLE backyard_published_theme_versions ADD COLUMN IF NOT EXISTS previews_verified boolean NOT NULL DEFAULT false;
@@ -21,3 +21,8 @@ WHERE id = $1;
 UPDATE fleets
 SET ship_list = $2
 WHERE id = $1;
+
+-- name: UpdateFleetMeowfficerList :exec
+UPDATE fleets
+SET meowfficer_list = $2
+WHERE id = $1;
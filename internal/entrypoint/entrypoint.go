@@ -9,16 +9,23 @@ import (
 	"sync"
 
 	"github.com/akamensky/argparse"
+	"github.com/ggmolly/belfast/internal/answer"
 	"github.com/ggmolly/belfast/internal/api"
+	"github.com/ggmolly/belfast/internal/arena"
+	"github.com/ggmolly/belfast/internal/chat"
+	"github.com/ggmolly/belfast/internal/cluster"
 	"github.com/ggmolly/belfast/internal/config"
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/consts"
 	"github.com/ggmolly/belfast/internal/db"
 	"github.com/ggmolly/belfast/internal/debug"
+	"github.com/ggmolly/belfast/internal/gamedata"
 	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/misc"
+	"github.com/ggmolly/belfast/internal/objectstore"
 	"github.com/ggmolly/belfast/internal/orm"
 	"github.com/ggmolly/belfast/internal/packets"
+	"github.com/ggmolly/belfast/internal/payment"
 	"github.com/ggmolly/belfast/internal/region"
 	"github.com/mattn/go-tty"
 )
@@ -53,6 +60,14 @@ func Run(opts Options) {
 		Help:     "Forces the reseed of the database with the latest data",
 		Default:  false,
 	})
+	dataSource := parser.String("", "data-source", &argparse.Options{
+		Required: false,
+		Help:     "Game data source: http, dir or archive (overrides [game_data] source)",
+	})
+	dataPath := parser.String("", "data-path", &argparse.Options{
+		Required: false,
+		Help:     "Game data location: base URL, belfast-data checkout or .zip/.tar.gz bundle (overrides [game_data] path)",
+	})
 	adb := parser.Flag("a", "adb", &argparse.Options{
 		Required: false,
 		Help:     "Parse ADB logs for debugging purposes (experimental -- tested on Linux only)",
@@ -81,6 +96,52 @@ func Run(opts Options) {
 		logger.LogEvent("Config", "Region", err.Error(), logger.LOG_LEVEL_ERROR)
 		os.Exit(1)
 	}
+	if err := configureTickets("Server", loadedConfig.Tickets); err != nil {
+		os.Exit(1)
+	}
+	if err := configureChat(loadedConfig.Chat); err != nil {
+		os.Exit(1)
+	}
+	if err := configurePayments(loadedConfig.Payments); err != nil {
+		os.Exit(1)
+	}
+	if err := configureStorage(loadedConfig.Storage); err != nil {
+		os.Exit(1)
+	}
+	go watchConfigFile("Server", *configPath, func() {
+		tickets, err := config.LoadTickets(*configPath)
+		if err != nil {
+			logger.LogEvent("Server", "Config", fmt.Sprintf("failed to reload tickets: %s", err.Error()), logger.LOG_LEVEL_WARN)
+			return
+		}
+		if configureTickets("Server", tickets) == nil {
+			logger.LogEvent("Server", "Config", "ticket keys reloaded", logger.LOG_LEVEL_INFO)
+		}
+		chatConfig, err := config.LoadChat(*configPath)
+		if err != nil {
+			logger.LogEvent("Server", "Config", fmt.Sprintf("failed to reload chat: %s", err.Error()), logger.LOG_LEVEL_WARN)
+			return
+		}
+		if configureChat(chatConfig) == nil {
+			logger.LogEvent("Server", "Config", "chat moderation reloaded", logger.LOG_LEVEL_INFO)
+		}
+		payments, err := config.LoadPayments(*configPath)
+		if err != nil {
+			logger.LogEvent("Server", "Config", fmt.Sprintf("failed to reload payments: %s", err.Error()), logger.LOG_LEVEL_WARN)
+			return
+		}
+		if configurePayments(payments) == nil {
+			logger.LogEvent("Server", "Config", "payment provider reloaded", logger.LOG_LEVEL_INFO)
+		}
+		storage, err := config.LoadStorage(*configPath)
+		if err != nil {
+			logger.LogEvent("Server", "Config", fmt.Sprintf("failed to reload storage: %s", err.Error()), logger.LOG_LEVEL_WARN)
+			return
+		}
+		if configureStorage(storage) == nil {
+			logger.LogEvent("Server", "Config", "preview storage reloaded", logger.LOG_LEVEL_INFO)
+		}
+	})
 	store, err := db.InitDefaultStore(context.Background(), loadedConfig.DB.DSN, loadedConfig.DB.SchemaName)
 	if err != nil {
 		logger.LogEvent("DB", "Init", err.Error(), logger.LOG_LEVEL_ERROR)
@@ -90,23 +151,56 @@ func Run(opts Options) {
 		logger.LogEvent("DB", "Seed", err.Error(), logger.LOG_LEVEL_ERROR)
 		os.Exit(1)
 	}
+	if *dataSource != "" {
+		loadedConfig.GameData.Source = *dataSource
+	}
+	if *dataPath != "" {
+		loadedConfig.GameData.Path = *dataPath
+	}
+	source, err := misc.NewDataSource(loadedConfig.GameData.Source, loadedConfig.GameData.Path)
+	if err != nil {
+		logger.LogEvent("GameData", "Source", err.Error(), logger.LOG_LEVEL_ERROR)
+		os.Exit(1)
+	}
+	misc.SetDataSource(source)
 	hasData, err := db.HasGameData(context.Background(), store)
 	if err != nil {
 		logger.LogEvent("DB", "Probe", err.Error(), logger.LOG_LEVEL_ERROR)
 		os.Exit(1)
 	}
-	if !hasData {
-		misc.UpdateAllData(region.Current())
+	if !hasData || *reseed {
+		if *reseed {
+			logger.LogEvent("Reseed", "Forced", "Forcing reseed of the database...", logger.LOG_LEVEL_INFO)
+		}
+		if _, err := misc.UpdateAllData(region.Current()); err != nil && !hasData {
+			os.Exit(1)
+		}
 	}
-	if *reseed {
-		logger.LogEvent("Reseed", "Forced", "Forcing reseed of the database...", logger.LOG_LEVEL_INFO)
-		misc.UpdateAllData(region.Current())
+	if err := gamedata.Default.Load(); err != nil {
+		logger.LogEvent("GameData", "Cache", fmt.Sprintf("failed to load game data cache, reading from the database: %s", err.Error()), logger.LOG_LEVEL_WARN)
+	} else {
+		stats := gamedata.Default.Stats()
+		logger.LogEvent("GameData", "Cache", fmt.Sprintf("loaded %d config entries in %d categories", stats.Entries, stats.Categories), logger.LOG_LEVEL_INFO)
 	}
 	server := connection.NewServer(loadedConfig.Belfast.BindAddress, loadedConfig.Belfast.Port, packets.Dispatch)
 	server.SetMaintenance(loadedConfig.Belfast.Maintenance)
 	if loadedConfig.Belfast.RequirePrivateClients != nil {
 		server.SetRequirePrivateClients(*loadedConfig.Belfast.RequirePrivateClients)
 	}
+	if loadedConfig.Cluster.Enabled {
+		nodeID := loadedConfig.Cluster.NodeID
+		if nodeID == "" {
+			nodeID = cluster.DefaultNodeID(loadedConfig.Belfast.Port)
+		}
+		node := cluster.NewNode(nodeID, server)
+		if err := node.Start(context.Background()); err != nil {
+			logger.LogEvent("Cluster", "Start", err.Error(), logger.LOG_LEVEL_ERROR)
+			os.Exit(1)
+		}
+		server.SetRelay(node)
+	}
+	go answer.RunActivityScheduler()
+	go arena.RunSeasons()
 	if !*noAPI {
 		cfg := api.LoadConfig(loadedConfig)
 		go func() {
@@ -164,3 +258,27 @@ func initRuntime() {
 		registerPackets()
 	})
 }
+
+func configurePayments(cfg config.PaymentsConfig) error {
+	if err := payment.Configure(cfg); err != nil {
+		logger.LogEvent("Server", "Payments", fmt.Sprintf("invalid payments config: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return err
+	}
+	return nil
+}
+
+func configureStorage(cfg config.StorageConfig) error {
+	if err := objectstore.Configure(cfg); err != nil {
+		logger.LogEvent("Server", "Storage", fmt.Sprintf("invalid storage config: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return err
+	}
+	return nil
+}
+
+func configureChat(cfg config.ChatConfig) error {
+	if err := chat.Default.Configure(cfg); err != nil {
+		logger.LogEvent("Server", "Chat", fmt.Sprintf("invalid chat config: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return err
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ggmolly/belfast/internal/connection"
 	"github.com/ggmolly/belfast/internal/logger"
 	"github.com/ggmolly/belfast/internal/packets"
+	"github.com/ggmolly/belfast/internal/ticket"
 )
 
 var gatewayOnce sync.Once
@@ -50,6 +51,9 @@ func RunGateway() {
 		}
 		return
 	}
+	if err := configureTickets("Gateway", loadedConfig.Tickets); err != nil {
+		os.Exit(1)
+	}
 	server := connection.NewServer(loadedConfig.BindAddress, loadedConfig.Port, packets.Dispatch)
 	if loadedConfig.RequirePrivateClients != nil {
 		server.SetRequirePrivateClients(*loadedConfig.RequirePrivateClients)
@@ -58,6 +62,7 @@ func RunGateway() {
 		if updated.Mode != "serve" {
 			return
 		}
+		_ = configureTickets("Gateway", updated.Tickets)
 		if updated.RequirePrivateClients == nil {
 			return
 		}
@@ -76,22 +81,7 @@ func initGatewayRuntime() {
 }
 
 func watchGatewayConfig(path string, currentConfig config.GatewayConfig, onReload func(config.GatewayConfig)) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		logger.LogEvent("Gateway", "Config", fmt.Sprintf("failed to init watcher: %s", err.Error()), logger.LOG_LEVEL_WARN)
-		return
-	}
-	defer watcher.Close()
-
-	configDir := filepath.Dir(path)
-	configBase := filepath.Base(path)
-	if err := watcher.Add(configDir); err != nil {
-		logger.LogEvent("Gateway", "Config", fmt.Sprintf("failed to watch config dir: %s", err.Error()), logger.LOG_LEVEL_WARN)
-		return
-	}
-
-	var reloadTimer *time.Timer
-	reloadConfig := func() {
+	watchConfigFile("Gateway", path, func() {
 		updatedConfig, err := config.LoadGateway(path)
 		if err != nil {
 			logger.LogEvent("Gateway", "Config", fmt.Sprintf("failed to reload config: %s", err.Error()), logger.LOG_LEVEL_WARN)
@@ -108,10 +98,30 @@ func watchGatewayConfig(path string, currentConfig config.GatewayConfig, onReloa
 			onReload(updatedConfig)
 		}
 		currentConfig = updatedConfig
+	})
+}
+
+// watchConfigFile calls reload whenever the file at path changes, once
+// writes settle for gatewayConfigReloadDelay.
+func watchConfigFile(component string, path string, reload func()) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.LogEvent(component, "Config", fmt.Sprintf("failed to init watcher: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return
 	}
+	defer watcher.Close()
+
+	configDir := filepath.Dir(path)
+	configBase := filepath.Base(path)
+	if err := watcher.Add(configDir); err != nil {
+		logger.LogEvent(component, "Config", fmt.Sprintf("failed to watch config dir: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return
+	}
+
+	var reloadTimer *time.Timer
 	scheduleReload := func() {
 		if reloadTimer == nil {
-			reloadTimer = time.AfterFunc(gatewayConfigReloadDelay, reloadConfig)
+			reloadTimer = time.AfterFunc(gatewayConfigReloadDelay, reload)
 			return
 		}
 		reloadTimer.Reset(gatewayConfigReloadDelay)
@@ -133,7 +143,20 @@ func watchGatewayConfig(path string, currentConfig config.GatewayConfig, onReloa
 			if !ok {
 				return
 			}
-			logger.LogEvent("Gateway", "Config", fmt.Sprintf("watcher error: %s", err.Error()), logger.LOG_LEVEL_WARN)
+			logger.LogEvent(component, "Config", fmt.Sprintf("watcher error: %s", err.Error()), logger.LOG_LEVEL_WARN)
 		}
 	}
 }
+
+// configureTickets applies the ticket keys of a (re)loaded config, keeping
+// the previous keys when they are invalid.
+func configureTickets(component string, cfg config.TicketConfig) error {
+	if err := ticket.Default.Configure(cfg); err != nil {
+		logger.LogEvent(component, "Tickets", fmt.Sprintf("invalid ticket keys: %s", err.Error()), logger.LOG_LEVEL_WARN)
+		return err
+	}
+	if !ticket.Default.Enabled() {
+		logger.LogEvent(component, "Tickets", "no ticket keys configured, server tickets are not signed", logger.LOG_LEVEL_WARN)
+	}
+	return nil
+}
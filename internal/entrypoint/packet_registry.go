@@ -63,6 +63,7 @@ func registerPackets() {
 		answer.DormData,
 		answer.FleetEnergyRecoverTime,
 		answer.GameMailbox,
+		answer.NotifyPaidOrders,
 		answer.CompensateNotification,
 		answer.CommanderFriendList,
 		answer.Activities,
@@ -71,6 +72,21 @@ func registerPackets() {
 		answer.SendPlayerShipCount,
 	})
 	packets.RegisterPacketHandler(25026, []packets.PacketHandler{answer.GetCommanderHome})
+	packets.RegisterPacketHandler(25002, []packets.PacketHandler{answer.MeowfficerBoxStart})
+	packets.RegisterPacketHandler(25004, []packets.PacketHandler{answer.MeowfficerBoxOpen})
+	packets.RegisterPacketHandler(25006, []packets.PacketHandler{answer.MeowfficerSetFleet})
+	packets.RegisterPacketHandler(25008, []packets.PacketHandler{answer.MeowfficerUpgrade})
+	packets.RegisterPacketHandler(25010, []packets.PacketHandler{answer.MeowfficerRefreshAbilities})
+	packets.RegisterPacketHandler(25012, []packets.PacketHandler{answer.MeowfficerLearnAbility})
+	packets.RegisterPacketHandler(25014, []packets.PacketHandler{answer.MeowfficerResetAbilities})
+	packets.RegisterPacketHandler(25016, []packets.PacketHandler{answer.MeowfficerLock})
+	packets.RegisterPacketHandler(25020, []packets.PacketHandler{answer.MeowfficerRename})
+	packets.RegisterPacketHandler(25022, []packets.PacketHandler{answer.MeowfficerSavePreset})
+	packets.RegisterPacketHandler(25024, []packets.PacketHandler{answer.MeowfficerRenamePreset})
+	packets.RegisterPacketHandler(25028, []packets.PacketHandler{answer.MeowfficerHomeOp})
+	packets.RegisterPacketHandler(25030, []packets.PacketHandler{answer.MeowfficerHomeSetSlot})
+	packets.RegisterPacketHandler(25032, []packets.PacketHandler{answer.MeowfficerHomeSetStyle})
+	packets.RegisterPacketHandler(25034, []packets.PacketHandler{answer.MeowfficerBoxList})
 	packets.RegisterPacketHandler(34501, []packets.PacketHandler{answer.WorldBossInfo})
 	packets.RegisterPacketHandler(63317, []packets.PacketHandler{answer.MetaCharacterTacticsInfoRequestCommandResponse})
 	packets.RegisterPacketHandler(34001, []packets.PacketHandler{answer.GetMetaShipsPointsResponse})
@@ -93,6 +109,8 @@ func registerPackets() {
 	packets.RegisterPacketHandler(26101, []packets.PacketHandler{answer.MiniGameHubData})
 	packets.RegisterPacketHandler(24020, []packets.PacketHandler{answer.LimitChallengeInfo})
 	packets.RegisterPacketHandler(24004, []packets.PacketHandler{answer.ChallengeInfo})
+	packets.RegisterPacketHandler(24002, []packets.PacketHandler{answer.StartChallenge})
+	packets.RegisterPacketHandler(24011, []packets.PacketHandler{answer.GiveUpChallenge})
 	packets.RegisterPacketHandler(26051, []packets.PacketHandler{answer.AtelierRequest})
 	packets.RegisterPacketHandler(11601, []packets.PacketHandler{answer.EmojiInfoRequest})
 	packets.RegisterPacketHandler(11603, []packets.PacketHandler{answer.FetchSecondaryPasswordCommandResponse})
@@ -110,6 +128,48 @@ func registerPackets() {
 	packets.RegisterPacketHandler(61011, []packets.PacketHandler{answer.GuildGetAssaultFleetCommandResponse})
 	packets.RegisterPacketHandler(61005, []packets.PacketHandler{answer.GuildGetActivationEventCommandResponse})
 	packets.RegisterPacketHandler(60003, []packets.PacketHandler{answer.GetGuildRequestsCommandResponse})
+	packets.RegisterPacketHandler(60001, []packets.PacketHandler{answer.GuildCreate})
+	packets.RegisterPacketHandler(60005, []packets.PacketHandler{answer.GuildApply})
+	packets.RegisterPacketHandler(60010, []packets.PacketHandler{answer.GuildQuit})
+	packets.RegisterPacketHandler(60012, []packets.PacketHandler{answer.GuildSetDuty})
+	packets.RegisterPacketHandler(60014, []packets.PacketHandler{answer.GuildKick})
+	packets.RegisterPacketHandler(60016, []packets.PacketHandler{answer.GuildAcceptRequest})
+	packets.RegisterPacketHandler(60018, []packets.PacketHandler{answer.GuildDissolve})
+	packets.RegisterPacketHandler(60020, []packets.PacketHandler{answer.GuildRejectRequest})
+	packets.RegisterPacketHandler(60022, []packets.PacketHandler{answer.GuildImpeach})
+	packets.RegisterPacketHandler(60024, []packets.PacketHandler{answer.GuildRecommendList})
+	packets.RegisterPacketHandler(60026, []packets.PacketHandler{answer.GuildModifyInfo})
+	packets.RegisterPacketHandler(60028, []packets.PacketHandler{answer.GuildSearch})
+	packets.RegisterPacketHandler(60035, []packets.PacketHandler{answer.GuildShopPurchase})
+	packets.RegisterPacketHandler(61001, []packets.PacketHandler{answer.GuildStartOperation})
+	packets.RegisterPacketHandler(61003, []packets.PacketHandler{answer.GuildSetAssaultFleet})
+	packets.RegisterPacketHandler(61007, []packets.PacketHandler{answer.GuildJoinEvent})
+	packets.RegisterPacketHandler(61027, []packets.PacketHandler{answer.GuildGetBossInfo})
+	packets.RegisterPacketHandler(61029, []packets.PacketHandler{answer.GuildBossRank})
+	packets.RegisterPacketHandler(62002, []packets.PacketHandler{answer.GuildDonate})
+	packets.RegisterPacketHandler(62011, []packets.PacketHandler{answer.GuildCapitalLogs})
+	packets.RegisterPacketHandler(62013, []packets.PacketHandler{answer.GuildStartResearch})
+	packets.RegisterPacketHandler(62015, []packets.PacketHandler{answer.GuildCancelResearch})
+	packets.RegisterPacketHandler(62020, []packets.PacketHandler{answer.GuildLearnTechnology})
+	packets.RegisterPacketHandler(62024, []packets.PacketHandler{answer.GuildGetCapital})
+	packets.RegisterPacketHandler(62029, []packets.PacketHandler{answer.GuildContributionRank})
+	packets.RegisterPacketHandler(63001, []packets.PacketHandler{answer.TechnologyStart})
+	packets.RegisterPacketHandler(63003, []packets.PacketHandler{answer.TechnologyFinish})
+	packets.RegisterPacketHandler(63005, []packets.PacketHandler{answer.TechnologyCancel})
+	packets.RegisterPacketHandler(63007, []packets.PacketHandler{answer.TechnologyRefresh})
+	packets.RegisterPacketHandler(63009, []packets.PacketHandler{answer.TechnologySetTarget})
+	packets.RegisterPacketHandler(63011, []packets.PacketHandler{answer.TechnologySetCatchup})
+	packets.RegisterPacketHandler(63200, []packets.PacketHandler{answer.BlueprintStart})
+	packets.RegisterPacketHandler(63202, []packets.PacketHandler{answer.BlueprintFinish})
+	packets.RegisterPacketHandler(63204, []packets.PacketHandler{answer.BlueprintStrengthen})
+	packets.RegisterPacketHandler(63206, []packets.PacketHandler{answer.BlueprintStop})
+	packets.RegisterPacketHandler(63210, []packets.PacketHandler{answer.BlueprintSubmitTask})
+	packets.RegisterPacketHandler(63212, []packets.PacketHandler{answer.BlueprintFateSimulation})
+	packets.RegisterPacketHandler(64001, []packets.PacketHandler{answer.FleetTechStudy})
+	packets.RegisterPacketHandler(64003, []packets.PacketHandler{answer.FleetTechFinishStudy})
+	packets.RegisterPacketHandler(64005, []packets.PacketHandler{answer.FleetTechClaimReward})
+	packets.RegisterPacketHandler(64007, []packets.PacketHandler{answer.FleetTechClaimAllRewards})
+	packets.RegisterPacketHandler(64009, []packets.PacketHandler{answer.FleetTechSetAttrs})
 	packets.RegisterPacketHandler(13501, []packets.PacketHandler{answer.RemasterSetActiveChapter})
 	packets.RegisterPacketHandler(13503, []packets.PacketHandler{answer.RemasterTickets})
 	packets.RegisterPacketHandler(13505, []packets.PacketHandler{answer.RemasterInfo})
@@ -146,6 +206,21 @@ func registerPackets() {
 	packets.RegisterPacketHandler(15008, []packets.PacketHandler{answer.SellItem})
 	packets.RegisterPacketHandler(15010, []packets.PacketHandler{answer.ProposeExchangeRing})
 	packets.RegisterPacketHandler(33000, []packets.PacketHandler{answer.WorldCheckInfo})
+	packets.RegisterPacketHandler(33101, []packets.PacketHandler{answer.WorldEnter})
+	packets.RegisterPacketHandler(33103, []packets.PacketHandler{answer.WorldAction})
+	packets.RegisterPacketHandler(33106, []packets.PacketHandler{answer.WorldMapFetch})
+	packets.RegisterPacketHandler(33301, []packets.PacketHandler{answer.WorldItemUse})
+	packets.RegisterPacketHandler(33401, []packets.PacketHandler{answer.WorldPortInfo})
+	packets.RegisterPacketHandler(33403, []packets.PacketHandler{answer.WorldPortShopBuy})
+	packets.RegisterPacketHandler(34503, []packets.PacketHandler{answer.WorldBossOtherList})
+	packets.RegisterPacketHandler(34505, []packets.PacketHandler{answer.WorldBossRank})
+	packets.RegisterPacketHandler(34509, []packets.PacketHandler{answer.WorldBossSupport})
+	packets.RegisterPacketHandler(34511, []packets.PacketHandler{answer.WorldBossAward})
+	packets.RegisterPacketHandler(34515, []packets.PacketHandler{answer.WorldBossCheck})
+	packets.RegisterPacketHandler(34517, []packets.PacketHandler{answer.WorldBossRefresh})
+	packets.RegisterPacketHandler(34521, []packets.PacketHandler{answer.WorldBossSummon})
+	packets.RegisterPacketHandler(34523, []packets.PacketHandler{answer.WorldBossAutoFight})
+	packets.RegisterPacketHandler(34525, []packets.PacketHandler{answer.WorldBossAutoFightSettle})
 	packets.RegisterPacketHandler(10994, []packets.PacketHandler{answer.CheaterMark})
 	packets.RegisterPacketHandler(10996, []packets.PacketHandler{answer.VersionCheck})
 	packets.RegisterPacketHandler(29001, []packets.PacketHandler{answer.NewEducateRequest})
@@ -240,12 +315,24 @@ func registerPackets() {
 	packets.RegisterPacketHandler(11401, []packets.PacketHandler{answer.ChatRoomChange})
 	packets.RegisterPacketHandler(50102, []packets.PacketHandler{answer.ReceiveChatMessage})
 	packets.RegisterPacketHandler(12032, []packets.PacketHandler{answer.ProposeShip})
-	packets.RegisterPacketHandler(20007, []packets.PacketHandler{func(b *[]byte, c *connection.Client) (int, int, error) {
-		response := protobuf.SC_20008{
-			Result: proto.Uint32(1),
-		}
-		return c.SendMessage(20008, &response)
-	}})
+	// Friends (500xx)
+	packets.RegisterPacketHandler(50001, []packets.PacketHandler{answer.FriendSearch})
+	packets.RegisterPacketHandler(50003, []packets.PacketHandler{answer.FriendSendRequest})
+	packets.RegisterPacketHandler(50006, []packets.PacketHandler{answer.FriendAcceptRequest})
+	packets.RegisterPacketHandler(50009, []packets.PacketHandler{answer.FriendRejectRequest})
+	packets.RegisterPacketHandler(50011, []packets.PacketHandler{answer.FriendDelete})
+	packets.RegisterPacketHandler(50014, []packets.PacketHandler{answer.FriendRecommendList})
+	packets.RegisterPacketHandler(50016, []packets.PacketHandler{answer.FriendBlackList})
+	packets.RegisterPacketHandler(50018, []packets.PacketHandler{answer.FriendRefreshInfo})
+	packets.RegisterPacketHandler(50107, []packets.PacketHandler{answer.FriendBlock})
+	packets.RegisterPacketHandler(50109, []packets.PacketHandler{answer.FriendUnblock})
+	packets.RegisterPacketHandler(50113, []packets.PacketHandler{answer.FriendVisit})
+	packets.RegisterPacketHandler(20005, []packets.PacketHandler{answer.SubmitTask})
+	packets.RegisterPacketHandler(20007, []packets.PacketHandler{answer.TriggerTask})
+	packets.RegisterPacketHandler(20011, []packets.PacketHandler{answer.SubmitTasks})
+	packets.RegisterPacketHandler(20106, []packets.PacketHandler{answer.SubmitWeeklyTask})
+	packets.RegisterPacketHandler(20108, []packets.PacketHandler{answer.SubmitWeeklyTasks})
+	packets.RegisterPacketHandler(20110, []packets.PacketHandler{answer.ClaimWeeklyTaskReward})
 	packets.RegisterPacketHandler(11011, []packets.PacketHandler{answer.UpdateSecretaries})
 	packets.RegisterPacketHandler(12038, []packets.PacketHandler{answer.UpgradeShipMaxLevel})
 	packets.RegisterPacketHandler(12040, []packets.PacketHandler{answer.SetFavoriteShip})
@@ -21,3 +21,93 @@ func TestRegisterPacketsIncludesLoveLetterGetAll(t *testing.T) {
 		t.Fatalf("expected handler for CS_12406 to be registered")
 	}
 }
+
+func TestRegisterPacketsIncludesFriendHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{50001, 50003, 50006, 50009, 50011, 50014, 50016, 50018, 50107, 50109, 50113} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesGuildLifecycleHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{60001, 60003, 60005, 60010, 60012, 60014, 60016, 60018, 60020, 60022, 60024, 60026, 60028} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesGuildOperationHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{60035, 61001, 61003, 61007, 61027, 61029, 62002, 62011, 62013, 62015, 62020, 62024, 62029} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesTaskHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{20005, 20007, 20011, 20106, 20108, 20110} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesTechnologyHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{63001, 63003, 63005, 63007, 63009, 63011, 63200, 63202, 63204, 63206, 63210, 63212} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesFleetTechHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{64001, 64003, 64005, 64007, 64009} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesMeowfficerHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{25002, 25004, 25006, 25008, 25010, 25012, 25014, 25016, 25020, 25022, 25024, 25026, 25028, 25030, 25032, 25034} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesWorldHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{33000, 33101, 33103, 33106, 33301, 33401, 33403} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
+
+func TestRegisterPacketsIncludesWorldBossHandlers(t *testing.T) {
+	packets.PacketDecisionFn = make(map[int][]packets.PacketHandler)
+	registerPackets()
+	for _, id := range []int{34501, 34503, 34505, 34509, 34511, 34515, 34517, 34521, 34523, 34525} {
+		if _, ok := packets.PacketDecisionFn[id]; !ok {
+			t.Fatalf("expected handler for CS_%d to be registered", id)
+		}
+	}
+}
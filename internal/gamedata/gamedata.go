@@ -0,0 +1,281 @@
+// Package gamedata keeps the game data config entries (ShareCfg, GameCfg...)
+// in memory so packet handlers can read them without a database round trip.
+//
+// Entries are held in immutable snapshots which are swapped atomically when
+// the data is reloaded, after a reseed or an edit through the admin API.
+// Until the registry is loaded, reads go straight to the database.
+package gamedata
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// Stats are the counters exposed on the server metrics endpoint.
+type Stats struct {
+	Loaded     bool
+	Hits       uint64
+	Misses     uint64
+	Reloads    uint64
+	Categories int
+	Entries    int
+	LoadedAt   time.Time
+}
+
+// snapshot is never modified once published, a reload builds a new one.
+type snapshot struct {
+	categories map[string]*category
+	entries    int
+	loadedAt   time.Time
+}
+
+type category struct {
+	entries []orm.ConfigEntry
+	index   map[string]int
+	// decoded memoizes typed values, keyed by typedKey. Values are only
+	// added, so readers of an older snapshot are never affected.
+	decoded sync.Map
+}
+
+type typedKey struct {
+	typ reflect.Type
+	key string
+	all bool
+}
+
+// Registry is an in-memory, indexed copy of the config entries.
+type Registry struct {
+	loadAll      func() ([]orm.ConfigEntry, error)
+	loadCategory func(string) ([]orm.ConfigEntry, error)
+
+	// reloadMu serializes reloads so a partial reload never drops the
+	// result of a concurrent one.
+	reloadMu sync.Mutex
+	current  atomic.Pointer[snapshot]
+
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	reloads atomic.Uint64
+}
+
+// Default is the registry used by the packet handlers.
+var Default = NewRegistry(
+	func() ([]orm.ConfigEntry, error) { return orm.ListConfigEntriesFiltered("", "") },
+	orm.ListConfigEntries,
+)
+
+// NewRegistry creates an unloaded registry. loadAll returns every config
+// entry, loadCategory the entries of a single category.
+func NewRegistry(loadAll func() ([]orm.ConfigEntry, error), loadCategory func(string) ([]orm.ConfigEntry, error)) *Registry {
+	return &Registry{loadAll: loadAll, loadCategory: loadCategory}
+}
+
+func newCategory(entries []orm.ConfigEntry) *category {
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		index[entry.Key] = i
+	}
+	return &category{entries: entries, index: index}
+}
+
+// Load replaces the registry with a fresh copy of every config entry.
+func (registry *Registry) Load() error {
+	registry.reloadMu.Lock()
+	defer registry.reloadMu.Unlock()
+	entries, err := registry.loadAll()
+	if err != nil {
+		return err
+	}
+	grouped := map[string][]orm.ConfigEntry{}
+	for _, entry := range entries {
+		grouped[entry.Category] = append(grouped[entry.Category], entry)
+	}
+	next := &snapshot{categories: make(map[string]*category, len(grouped)), entries: len(entries), loadedAt: time.Now()}
+	for name, categoryEntries := range grouped {
+		next.categories[name] = newCategory(categoryEntries)
+	}
+	registry.current.Store(next)
+	registry.reloads.Add(1)
+	return nil
+}
+
+// Reload refreshes the given categories. It does nothing until the registry
+// was loaded, as reads still go to the database.
+func (registry *Registry) Reload(categories ...string) error {
+	registry.reloadMu.Lock()
+	defer registry.reloadMu.Unlock()
+	previous := registry.current.Load()
+	if previous == nil {
+		return nil
+	}
+	next := &snapshot{categories: make(map[string]*category, len(previous.categories)), entries: previous.entries, loadedAt: time.Now()}
+	for name, loaded := range previous.categories {
+		next.categories[name] = loaded
+	}
+	for _, name := range categories {
+		entries, err := registry.loadCategory(name)
+		if err != nil {
+			return err
+		}
+		if loaded, ok := next.categories[name]; ok {
+			next.entries -= len(loaded.entries)
+		}
+		if len(entries) == 0 {
+			delete(next.categories, name)
+			continue
+		}
+		next.categories[name] = newCategory(entries)
+		next.entries += len(entries)
+	}
+	registry.current.Store(next)
+	registry.reloads.Add(1)
+	return nil
+}
+
+// Loaded reports whether reads are served from memory.
+func (registry *Registry) Loaded() bool {
+	return registry.current.Load() != nil
+}
+
+// lookup returns the category from the current snapshot. A loaded registry
+// holds every category, so a missing one has no entries. Reads falling back
+// to the database count as misses.
+func (registry *Registry) lookup(name string) (*category, bool) {
+	current := registry.current.Load()
+	if current == nil {
+		registry.misses.Add(1)
+		return nil, false
+	}
+	loaded, ok := current.categories[name]
+	if !ok {
+		return newCategory(nil), true
+	}
+	return loaded, true
+}
+
+// record counts a read served from the snapshot as a hit when it found
+// something, and as a miss otherwise.
+func (registry *Registry) record(found bool) {
+	if found {
+		registry.hits.Add(1)
+	} else {
+		registry.misses.Add(1)
+	}
+}
+
+// Entry mirrors orm.GetConfigEntry and returns db.ErrNotFound for unknown
+// keys.
+func (registry *Registry) Entry(name string, key string) (*orm.ConfigEntry, error) {
+	loaded, ok := registry.lookup(name)
+	if !ok {
+		return orm.GetConfigEntry(name, key)
+	}
+	i, ok := loaded.index[key]
+	registry.record(ok)
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	entry := loaded.entries[i]
+	return &entry, nil
+}
+
+// Entries mirrors orm.ListConfigEntries, entries are sorted by key. The
+// returned slice must not be modified.
+func (registry *Registry) Entries(name string) ([]orm.ConfigEntry, error) {
+	loaded, ok := registry.lookup(name)
+	if !ok {
+		return orm.ListConfigEntries(name)
+	}
+	registry.record(len(loaded.entries) > 0)
+	return loaded.entries, nil
+}
+
+func (registry *Registry) Stats() Stats {
+	stats := Stats{Hits: registry.hits.Load(), Misses: registry.misses.Load(), Reloads: registry.reloads.Load()}
+	if current := registry.current.Load(); current != nil {
+		stats.Loaded = true
+		stats.Categories = len(current.categories)
+		stats.Entries = current.entries
+		stats.LoadedAt = current.loadedAt
+	}
+	return stats
+}
+
+// Get decodes the entry category/key into a T. Decoded values are cached
+// with the snapshot, so callers must not modify what they point to.
+func Get[T any](registry *Registry, name string, key string) (*T, error) {
+	loaded, ok := registry.lookup(name)
+	if !ok {
+		entry, err := orm.GetConfigEntry(name, key)
+		if err != nil {
+			return nil, err
+		}
+		return decode[T](entry.Data)
+	}
+	cacheKey := typedKey{typ: reflect.TypeFor[T](), key: key}
+	if value, ok := loaded.decoded.Load(cacheKey); ok {
+		registry.record(true)
+		return value.(*T), nil
+	}
+	i, ok := loaded.index[key]
+	registry.record(ok)
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	value, err := decode[T](loaded.entries[i].Data)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := loaded.decoded.LoadOrStore(cacheKey, value)
+	return actual.(*T), nil
+}
+
+// List decodes every entry of a category into a T, sorted by key. The
+// returned slice is shared and must not be modified.
+func List[T any](registry *Registry, name string) ([]T, error) {
+	loaded, ok := registry.lookup(name)
+	if !ok {
+		entries, err := orm.ListConfigEntries(name)
+		if err != nil {
+			return nil, err
+		}
+		return decodeAll[T](entries)
+	}
+	registry.record(len(loaded.entries) > 0)
+	cacheKey := typedKey{typ: reflect.TypeFor[T](), all: true}
+	if values, ok := loaded.decoded.Load(cacheKey); ok {
+		return values.([]T), nil
+	}
+	values, err := decodeAll[T](loaded.entries)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := loaded.decoded.LoadOrStore(cacheKey, values)
+	return actual.([]T), nil
+}
+
+func decode[T any](data json.RawMessage) (*T, error) {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func decodeAll[T any](entries []orm.ConfigEntry) ([]T, error) {
+	values := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		var value T
+		if err := json.Unmarshal(entry.Data, &value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
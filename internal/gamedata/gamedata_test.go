@@ -0,0 +1,148 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+type fakeConfigStore struct {
+	mu      sync.Mutex
+	entries map[string]map[string]string
+	loads   int
+}
+
+func (store *fakeConfigStore) set(category string, key string, data string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.entries[category] == nil {
+		store.entries[category] = map[string]string{}
+	}
+	store.entries[category][key] = data
+}
+
+func (store *fakeConfigStore) category(name string) ([]orm.ConfigEntry, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.loads++
+	keys := make([]string, 0, len(store.entries[name]))
+	for key := range store.entries[name] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]orm.ConfigEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, orm.ConfigEntry{Category: name, Key: key, Data: json.RawMessage(store.entries[name][key])})
+	}
+	return entries, nil
+}
+
+func (store *fakeConfigStore) all() ([]orm.ConfigEntry, error) {
+	store.mu.Lock()
+	names := make([]string, 0, len(store.entries))
+	for name := range store.entries {
+		names = append(names, name)
+	}
+	store.mu.Unlock()
+	sort.Strings(names)
+	all := []orm.ConfigEntry{}
+	for _, name := range names {
+		entries, _ := store.category(name)
+		all = append(all, entries...)
+	}
+	return all, nil
+}
+
+type testShip struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+func newTestRegistry() (*Registry, *fakeConfigStore) {
+	store := &fakeConfigStore{entries: map[string]map[string]string{}}
+	store.set("ships.json", "1", `{"id":1,"name":"Belfast"}`)
+	store.set("ships.json", "2", `{"id":2,"name":"Edinburgh"}`)
+	store.set("rooms.json", "7", `{"id":7}`)
+	return NewRegistry(store.all, store.category), store
+}
+
+func TestRegistryServesLoadedSnapshot(t *testing.T) {
+	registry, store := newTestRegistry()
+	if err := registry.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	loads := store.loads
+
+	ship, err := Get[testShip](registry, "ships.json", "2")
+	if err != nil || ship.Name != "Edinburgh" {
+		t.Fatalf("unexpected ship %+v (%v)", ship, err)
+	}
+	again, err := Get[testShip](registry, "ships.json", "2")
+	if err != nil || again != ship {
+		t.Fatalf("expected decoded value to be memoized")
+	}
+	ships, err := List[testShip](registry, "ships.json")
+	if err != nil || len(ships) != 2 || ships[0].Name != "Belfast" {
+		t.Fatalf("unexpected ships %+v (%v)", ships, err)
+	}
+	if _, err := registry.Entry("ships.json", "3"); !db.IsNotFound(err) {
+		t.Fatalf("expected missing key to be not found, got %v", err)
+	}
+	if entries, err := registry.Entries("unknown.json"); err != nil || len(entries) != 0 {
+		t.Fatalf("expected unknown category to be empty, got %v (%v)", entries, err)
+	}
+	if store.loads != loads {
+		t.Fatalf("expected reads to be served from memory")
+	}
+
+	stats := registry.Stats()
+	if !stats.Loaded || stats.Hits != 3 || stats.Misses != 2 || stats.Categories != 2 || stats.Entries != 3 || stats.Reloads != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRegistryReloadSwapsSnapshot(t *testing.T) {
+	registry, store := newTestRegistry()
+	if err := registry.Reload("ships.json"); err != nil || registry.Loaded() {
+		t.Fatalf("expected reload of an unloaded registry to be a no-op (%v)", err)
+	}
+	if err := registry.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	before, err := Get[testShip](registry, "ships.json", "1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	store.set("ships.json", "1", `{"id":1,"name":"Belfast META"}`)
+	store.set("ships.json", "3", `{"id":3,"name":"Sheffield"}`)
+	store.set("extra.json", "1", `{}`)
+	if err := registry.Reload("ships.json", "extra.json"); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	after, err := Get[testShip](registry, "ships.json", "1")
+	if err != nil || after.Name != "Belfast META" {
+		t.Fatalf("expected reloaded entry, got %+v (%v)", after, err)
+	}
+	if before.Name != "Belfast" {
+		t.Fatalf("expected values of the previous snapshot to stay untouched")
+	}
+	if rooms, err := registry.Entries("rooms.json"); err != nil || len(rooms) != 1 {
+		t.Fatalf("expected other categories to be kept, got %v (%v)", rooms, err)
+	}
+	if stats := registry.Stats(); stats.Categories != 3 || stats.Entries != 5 {
+		t.Fatalf("unexpected stats after reload %+v", stats)
+	}
+
+	delete(store.entries, "extra.json")
+	if err := registry.Reload("extra.json"); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if stats := registry.Stats(); stats.Categories != 2 || stats.Entries != 4 {
+		t.Fatalf("expected emptied category to be dropped, got %+v", stats)
+	}
+}
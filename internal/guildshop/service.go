@@ -14,10 +14,17 @@ const (
 	guildSetConfigCategory   = "ShareCfg/guildset.json"
 )
 
+// StoreEntry is a guild_store row. Buying one unit costs price guild coins
+// and grants num of the picked goods, dropped as type.
 type StoreEntry struct {
-	ID                 uint32 `json:"id"`
-	Weight             uint32 `json:"weight"`
-	GoodsPurchaseLimit uint32 `json:"goods_purchase_limit"`
+	ID                 uint32   `json:"id"`
+	Weight             uint32   `json:"weight"`
+	GoodsPurchaseLimit uint32   `json:"goods_purchase_limit"`
+	Price              uint32   `json:"price"`
+	Goods              []uint32 `json:"goods"`
+	GoodsType          uint32   `json:"goods_type"`
+	Num                uint32   `json:"num"`
+	Type               uint32   `json:"type"`
 }
 
 type SetEntry struct {
@@ -66,6 +73,16 @@ func LoadConfig() (*Config, error) {
 	}, nil
 }
 
+// Entry returns the store entry with the given id.
+func (config *Config) Entry(id uint32) (*StoreEntry, bool) {
+	for i := range config.StoreEntries {
+		if config.StoreEntries[i].ID == id {
+			return &config.StoreEntries[i], true
+		}
+	}
+	return nil, false
+}
+
 func EnsureState(commanderID uint32, now time.Time, config *Config) (*orm.GuildShopState, []orm.GuildShopGood, error) {
 	state, err := orm.GetGuildShopState(commanderID)
 	if err != nil {
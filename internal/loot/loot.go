@@ -0,0 +1,204 @@
+// Package loot rolls the drops of chapters, events and virtual items from
+// the weighted drop tables managed through the admin API.
+package loot
+
+import (
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// Rank is the battle rank a chapter drop is rolled for.
+type Rank uint8
+
+const (
+	// RankNone rolls with the plain weights, for drops not earned in battle.
+	RankNone Rank = iota
+	RankB
+	RankA
+	RankS
+)
+
+// MaxBonusRate caps the combined rate of the running drop bonuses.
+const MaxBonusRate = 100 * orm.DropRateScale
+
+// Source is the randomness drops are rolled with.
+type Source interface {
+	Uint64N(n uint64) uint64
+}
+
+type Drop struct {
+	Type  uint32
+	ID    uint32
+	Count uint32
+}
+
+// RankFromScore returns the rank of a battle score, RankNone for defeats.
+func RankFromScore(score uint32) Rank {
+	switch {
+	case score >= 4:
+		return RankS
+	case score == 3:
+		return RankA
+	case score == 2:
+		return RankB
+	}
+	return RankNone
+}
+
+func (rank Rank) String() string {
+	switch rank {
+	case RankS:
+		return "S"
+	case RankA:
+		return "A"
+	case RankB:
+		return "B"
+	}
+	return ""
+}
+
+// ParseRank parses "S", "A" or "B"; an empty string is RankNone.
+func ParseRank(value string) (Rank, bool) {
+	switch value {
+	case "":
+		return RankNone, true
+	case "S", "s":
+		return RankS, true
+	case "A", "a":
+		return RankA, true
+	case "B", "b":
+		return RankB, true
+	}
+	return RankNone, false
+}
+
+func rankRate(table *orm.DropTable, rank Rank) uint32 {
+	switch rank {
+	case RankS:
+		return table.RankSRate
+	case RankA:
+		return table.RankARate
+	case RankB:
+		return table.RankBRate
+	}
+	return orm.DropRateScale
+}
+
+// CombineBonuses multiplies the rates of the bonuses applying to a table.
+func CombineBonuses(bonuses []orm.DropBonus, kind string, sourceID uint32) uint32 {
+	rate := uint64(orm.DropRateScale)
+	for i := range bonuses {
+		if !bonuses[i].AppliesTo(kind, sourceID) {
+			continue
+		}
+		rate = min(rate*uint64(bonuses[i].Rate)/uint64(orm.DropRateScale), uint64(MaxBonusRate))
+	}
+	return uint32(rate)
+}
+
+// weights returns the weight of every entry of table and the empty weight,
+// on the same scale. Rates are applied by multiplying instead of dividing so
+// small weights keep their odds.
+func weights(table *orm.DropTable, rank Rank, bonus uint32) ([]uint64, uint64) {
+	scale := uint64(rankRate(table, rank)) * uint64(table.RateMultiplier) * uint64(bonus)
+	unit := uint64(orm.DropRateScale) * uint64(orm.DropRateScale) * uint64(orm.DropRateScale)
+	entries := make([]uint64, len(table.Entries))
+	for i, entry := range table.Entries {
+		entries[i] = uint64(entry.Weight) * scale
+	}
+	return entries, uint64(table.EmptyWeight) * unit
+}
+
+// pick returns the index of the entry rolled, -1 for the empty outcome.
+func pick(entries []uint64, empty uint64, src Source) int {
+	total := empty
+	for _, weight := range entries {
+		total += weight
+	}
+	if total == 0 {
+		return -1
+	}
+	roll := src.Uint64N(total)
+	for i, weight := range entries {
+		if roll < weight {
+			return i
+		}
+		roll -= weight
+	}
+	return -1
+}
+
+func entryDrop(entry orm.DropTableEntry) Drop {
+	return Drop{Type: entry.DropType, ID: entry.DropID, Count: max(entry.Count, 1)}
+}
+
+// Roll rolls table Rolls times for rank with bonus, the combined rate of the
+// running bonuses.
+func Roll(table *orm.DropTable, rank Rank, bonus uint32, src Source) []Drop {
+	entries, empty := weights(table, rank, bonus)
+	drops := []Drop{}
+	for range table.Rolls {
+		if i := pick(entries, empty, src); i >= 0 {
+			drops = append(drops, entryDrop(table.Entries[i]))
+		}
+	}
+	return drops
+}
+
+// Pick picks one entry of table by weight, ignoring the empty weight and the
+// rates: a virtual item always turns into something.
+func Pick(table *orm.DropTable, src Source) (Drop, bool) {
+	entries := make([]uint64, len(table.Entries))
+	for i, entry := range table.Entries {
+		entries[i] = uint64(entry.Weight)
+	}
+	i := pick(entries, 0, src)
+	if i < 0 {
+		return Drop{}, false
+	}
+	return entryDrop(table.Entries[i]), true
+}
+
+// Odds returns the chance of each entry of table, then of the empty outcome,
+// for a single roll.
+func Odds(table *orm.DropTable, rank Rank, bonus uint32) ([]float64, float64) {
+	entries, empty := weights(table, rank, bonus)
+	total := float64(empty)
+	for _, weight := range entries {
+		total += float64(weight)
+	}
+	odds := make([]float64, len(entries))
+	if total == 0 {
+		return odds, 0
+	}
+	for i, weight := range entries {
+		odds[i] = float64(weight) / total
+	}
+	return odds, float64(empty) / total
+}
+
+// Simulation counts the outcomes of rolling a table many times, next to the
+// expected chance of each outcome. Hits and Odds have one value per entry of
+// the table.
+type Simulation struct {
+	Rolls     uint32
+	Bonus     uint32
+	Hits      []uint32
+	Odds      []float64
+	Empty     uint32
+	EmptyOdds float64
+}
+
+// Simulate rolls table n times, each time for every one of its Rolls.
+func Simulate(table *orm.DropTable, rank Rank, bonus uint32, n uint32, src Source) Simulation {
+	entries, empty := weights(table, rank, bonus)
+	result := Simulation{Rolls: n * table.Rolls, Bonus: bonus, Hits: make([]uint32, len(entries))}
+	result.Odds, result.EmptyOdds = Odds(table, rank, bonus)
+	for range result.Rolls {
+		if i := pick(entries, empty, src); i >= 0 {
+			result.Hits[i]++
+		} else {
+			result.Empty++
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package loot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/rng"
+)
+
+// fixedSource returns its rolls in order.
+type fixedSource struct {
+	rolls []uint64
+}
+
+func (src *fixedSource) Uint64N(n uint64) uint64 {
+	roll := src.rolls[0]
+	src.rolls = src.rolls[1:]
+	return roll % n
+}
+
+func testTable() *orm.DropTable {
+	return &orm.DropTable{
+		Kind:           orm.DropKindChapter,
+		Rolls:          1,
+		EmptyWeight:    50,
+		RankSRate:      200,
+		RankARate:      100,
+		RankBRate:      50,
+		RateMultiplier: 100,
+		Entries: []orm.DropTableEntry{
+			{DropType: 4, DropID: 101, Count: 1, Weight: 10},
+			{DropType: 2, DropID: 20001, Count: 3, Weight: 40},
+		},
+	}
+}
+
+func TestRankFromScore(t *testing.T) {
+	cases := map[uint32]Rank{0: RankNone, 1: RankNone, 2: RankB, 3: RankA, 4: RankS}
+	for score, want := range cases {
+		if got := RankFromScore(score); got != want {
+			t.Fatalf("score %d: expected %v, got %v", score, want, got)
+		}
+	}
+	if rank, ok := ParseRank("a"); !ok || rank != RankA {
+		t.Fatalf("expected rank A, got %v", rank)
+	}
+	if _, ok := ParseRank("C"); ok {
+		t.Fatalf("expected rank C to be refused")
+	}
+}
+
+func TestOddsFollowRankAndBonus(t *testing.T) {
+	table := testTable()
+	odds, empty := Odds(table, RankA, orm.DropRateScale)
+	if math.Abs(odds[0]-0.1) > 1e-9 || math.Abs(odds[1]-0.4) > 1e-9 || math.Abs(empty-0.5) > 1e-9 {
+		t.Fatalf("unexpected rank A odds %v, empty %v", odds, empty)
+	}
+	// S doubles the entry weights: 20 and 80 against 50
+	odds, empty = Odds(table, RankS, orm.DropRateScale)
+	if math.Abs(empty-50.0/150) > 1e-9 || math.Abs(odds[0]-20.0/150) > 1e-9 {
+		t.Fatalf("unexpected rank S odds %v, empty %v", odds, empty)
+	}
+	// B halves them, a 200% bonus brings them back
+	odds, _ = Odds(table, RankB, 2*orm.DropRateScale)
+	if math.Abs(odds[0]-0.1) > 1e-9 {
+		t.Fatalf("expected the bonus to offset rank B, got %v", odds)
+	}
+	table.EmptyWeight = 0
+	odds, empty = Odds(table, RankS, 5*orm.DropRateScale)
+	if empty != 0 || math.Abs(odds[0]-0.2) > 1e-9 {
+		t.Fatalf("expected rates not to matter without empty weight, got %v", odds)
+	}
+}
+
+func TestRoll(t *testing.T) {
+	table := testTable()
+	table.Rolls = 3
+	// weights at rank A are 10*1e6, 40*1e6 and 50*1e6 for nothing
+	src := &fixedSource{rolls: []uint64{5_000_000, 30_000_000, 70_000_000}}
+	drops := Roll(table, RankA, orm.DropRateScale, src)
+	if len(drops) != 2 {
+		t.Fatalf("expected 2 drops out of 3 rolls, got %v", drops)
+	}
+	if drops[0] != (Drop{Type: 4, ID: 101, Count: 1}) || drops[1] != (Drop{Type: 2, ID: 20001, Count: 3}) {
+		t.Fatalf("unexpected drops %v", drops)
+	}
+}
+
+func TestPickIgnoresEmptyWeight(t *testing.T) {
+	table := testTable()
+	table.Entries[0].Count = 0
+	drop, ok := Pick(table, &fixedSource{rolls: []uint64{9}})
+	if !ok || drop != (Drop{Type: 4, ID: 101, Count: 1}) {
+		t.Fatalf("unexpected pick %v", drop)
+	}
+	drop, ok = Pick(table, &fixedSource{rolls: []uint64{49}})
+	if !ok || drop.ID != 20001 {
+		t.Fatalf("unexpected pick %v", drop)
+	}
+	if _, ok := Pick(&orm.DropTable{}, &fixedSource{}); ok {
+		t.Fatalf("expected an empty table to pick nothing")
+	}
+}
+
+func TestCombineBonuses(t *testing.T) {
+	chapter := orm.DropKindChapter
+	item := orm.DropKindItem
+	source := uint32(7)
+	other := uint32(8)
+	bonuses := []orm.DropBonus{
+		{Rate: 150},
+		{Kind: &chapter, Rate: 200},
+		{Kind: &chapter, SourceID: &source, Rate: 200},
+		{Kind: &chapter, SourceID: &other, Rate: 500},
+		{Kind: &item, Rate: 300},
+	}
+	if rate := CombineBonuses(bonuses, chapter, source); rate != 600 {
+		t.Fatalf("expected 600, got %d", rate)
+	}
+	if rate := CombineBonuses(nil, chapter, source); rate != orm.DropRateScale {
+		t.Fatalf("expected no bonus, got %d", rate)
+	}
+	huge := []orm.DropBonus{{Rate: 10000}, {Rate: 10000}}
+	if rate := CombineBonuses(huge, chapter, source); rate != MaxBonusRate {
+		t.Fatalf("expected the bonus to be capped, got %d", rate)
+	}
+}
+
+func TestSimulateMatchesOdds(t *testing.T) {
+	table := testTable()
+	simulation := Simulate(table, RankS, orm.DropRateScale, 30000, rng.NewLockedRandFromSeed(1))
+	if simulation.Rolls != 30000 {
+		t.Fatalf("expected 30000 rolls, got %d", simulation.Rolls)
+	}
+	total := simulation.Empty
+	for i, hits := range simulation.Hits {
+		total += hits
+		rate := float64(hits) / float64(simulation.Rolls)
+		if math.Abs(rate-simulation.Odds[i]) > 0.02 {
+			t.Fatalf("entry %d: observed %.3f, expected %.3f", i, rate, simulation.Odds[i])
+		}
+	}
+	if total != simulation.Rolls {
+		t.Fatalf("expected every roll to be counted, got %d", total)
+	}
+}
@@ -0,0 +1,83 @@
+package loot
+
+import (
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/rng"
+)
+
+var lootRng = rng.NewLockedRand()
+
+// RollSource rolls the enabled table of a source at now. It reports false
+// when the source has no table, so callers keep their own drops.
+func RollSource(kind string, sourceID uint32, rank Rank, now time.Time) ([]Drop, bool, error) {
+	table, err := orm.GetDropTableBySource(kind, sourceID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if !table.Enabled {
+		return nil, false, nil
+	}
+	bonuses, err := orm.ListActiveDropBonuses(now)
+	if err != nil {
+		return nil, false, err
+	}
+	return Roll(table, rank, CombineBonuses(bonuses, kind, sourceID), lootRng), true, nil
+}
+
+// RollEvents rolls the event tables of the open activities at now.
+func RollEvents(activityIDs []uint32, rank Rank, now time.Time) ([]Drop, error) {
+	tables, err := orm.ListEnabledDropTables(orm.DropKindEvent, activityIDs)
+	if err != nil || len(tables) == 0 {
+		return nil, err
+	}
+	bonuses, err := orm.ListActiveDropBonuses(now)
+	if err != nil {
+		return nil, err
+	}
+	drops := []Drop{}
+	for i := range tables {
+		bonus := CombineBonuses(bonuses, orm.DropKindEvent, tables[i].SourceID)
+		drops = append(drops, Roll(&tables[i], rank, bonus, lootRng)...)
+	}
+	return drops, nil
+}
+
+// PickItem picks what a virtual item turns into. It reports false when the
+// item has no table.
+func PickItem(itemID uint32) (Drop, bool, error) {
+	table, err := orm.GetDropTableBySource(orm.DropKindItem, itemID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return Drop{}, false, nil
+		}
+		return Drop{}, false, err
+	}
+	if !table.Enabled {
+		return Drop{}, false, nil
+	}
+	drop, ok := Pick(table, lootRng)
+	return drop, ok, nil
+}
+
+// SimulateTable rolls a table n times with the bonuses running at now. Item
+// tables are simulated the way they are picked: once per roll, never empty.
+func SimulateTable(table *orm.DropTable, rank Rank, n uint32, now time.Time) (Simulation, error) {
+	if table.Kind == orm.DropKindItem {
+		picked := *table
+		picked.Rolls = 1
+		picked.EmptyWeight = 0
+		return Simulate(&picked, RankNone, orm.DropRateScale, n, lootRng), nil
+	}
+	bonuses, err := orm.ListActiveDropBonuses(now)
+	if err != nil {
+		return Simulation{}, err
+	}
+	bonus := CombineBonuses(bonuses, table.Kind, table.SourceID)
+	return Simulate(table, rank, bonus, n, lootRng), nil
+}
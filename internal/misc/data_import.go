@@ -0,0 +1,228 @@
+package misc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/gamedata"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// DataImportReport lists the game data tables whose source files changed
+// since the previous import.
+type DataImportReport struct {
+	Source    string
+	Changed   []string
+	Unchanged []string
+}
+
+// importState tracks the files read by an import, keyed by the table
+// (importer) that read them.
+type importState struct {
+	source DataSource
+	table  string
+	files  map[string]map[string]string
+
+	manifestOnce sync.Once
+	manifest     map[string]string
+	manifestErr  error
+}
+
+var (
+	// importMu serializes imports, activeImport is only set while one runs.
+	importMu     sync.Mutex
+	activeMu     sync.Mutex
+	activeImport *importState
+)
+
+func newImportState(source DataSource) *importState {
+	return &importState{source: source, files: map[string]map[string]string{}}
+}
+
+func currentImport() *importState {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if activeImport != nil {
+		return activeImport
+	}
+	return newImportState(CurrentDataSource())
+}
+
+func setActiveImport(state *importState) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	activeImport = state
+}
+
+func (state *importState) setTable(table string) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	state.table = table
+}
+
+func (state *importState) record(name string, digest string) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	files, ok := state.files[state.table]
+	if !ok {
+		files = map[string]string{}
+		state.files[state.table] = files
+	}
+	files[name] = digest
+}
+
+// verify checks a file against the SHA256SUMS manifest of the source. Sources
+// without a manifest are not verified.
+func (state *importState) verify(name string, digest string) error {
+	state.manifestOnce.Do(func() {
+		state.manifest, state.manifestErr = loadChecksumManifest(state.source)
+		if state.manifestErr == nil && state.manifest == nil {
+			logger.LogEvent("GameData", "Checksum", fmt.Sprintf("%s has no %s, skipping checksum verification", state.source, checksumManifest), logger.LOG_LEVEL_WARN)
+		}
+	})
+	if state.manifestErr != nil {
+		return state.manifestErr
+	}
+	if state.manifest == nil {
+		return nil
+	}
+	expected, ok := state.manifest[name]
+	if !ok {
+		return fmt.Errorf("%s is not listed in %s", name, checksumManifest)
+	}
+	if !strings.EqualFold(expected, digest) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, digest)
+	}
+	return nil
+}
+
+// tableChecksums returns a digest per table over the paths and hashes of the
+// files it was imported from.
+func (state *importState) tableChecksums() map[string]string {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	checksums := make(map[string]string, len(state.files))
+	for table, files := range state.files {
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		hash := sha256.New()
+		for _, name := range names {
+			fmt.Fprintf(hash, "%s  %s\n", files[name], name)
+		}
+		checksums[table] = hex.EncodeToString(hash.Sum(nil))
+	}
+	return checksums
+}
+
+// loadChecksumManifest parses the SHA256SUMS file of a source, in the output
+// format of sha256sum. It returns a nil map when the source has none.
+func loadChecksumManifest(source DataSource) (map[string]string, error) {
+	reader, err := source.Open(checksumManifest)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	manifest := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		digest, name, ok := strings.Cut(line, " ")
+		if !ok || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid %s line %q", checksumManifest, line)
+		}
+		name = strings.TrimPrefix(strings.TrimLeft(name, " *"), "./")
+		manifest[name] = strings.ToLower(digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// compareChecksums splits the tables of an import between the ones whose
+// checksum differs from the stored one and the ones left untouched.
+func compareChecksums(previous map[string]string, current map[string]string) ([]string, []string) {
+	changed := []string{}
+	unchanged := []string{}
+	for _, table := range order {
+		checksum, ok := current[table]
+		if !ok {
+			continue
+		}
+		if previous[table] == checksum {
+			unchanged = append(unchanged, table)
+		} else {
+			changed = append(changed, table)
+		}
+	}
+	return changed, unchanged
+}
+
+func UpdateAllData(region string) (DataImportReport, error) {
+	importMu.Lock()
+	defer importMu.Unlock()
+	state := newImportState(CurrentDataSource())
+	setActiveImport(state)
+	defer setActiveImport(nil)
+	defer func() {
+		if err := state.source.Close(); err != nil {
+			logger.LogEvent("GameData", "Source", fmt.Sprintf("failed to close %s: %s", state.source.String(), err.Error()), logger.LOG_LEVEL_WARN)
+		}
+	}()
+
+	report := DataImportReport{Source: state.source.String()}
+	logger.LogEvent("GameData", "Updating", fmt.Sprintf("Updating all game data from %s.. this may take a while.", report.Source), logger.LOG_LEVEL_INFO)
+	if err := updateAllDataSQLC(region, state); err != nil {
+		logger.LogEvent("GameData", "Updating", fmt.Sprintf("failed to update game data: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+		return report, err
+	}
+
+	if gamedata.Default.Loaded() {
+		if err := gamedata.Default.Load(); err != nil {
+			logger.LogEvent("GameData", "Cache", fmt.Sprintf("failed to reload game data cache: %s", err.Error()), logger.LOG_LEVEL_ERROR)
+		}
+	}
+	if connection.BelfastInstance != nil {
+		connection.BelfastInstance.GameDataChanged()
+	}
+
+	stored, err := orm.ListGameDataChecksums()
+	if err != nil {
+		logger.LogEvent("GameData", "Checksum", fmt.Sprintf("failed to load previous checksums: %s", err.Error()), logger.LOG_LEVEL_WARN)
+	}
+	previous := make(map[string]string, len(stored))
+	for _, checksum := range stored {
+		previous[checksum.TableName] = checksum.Checksum
+	}
+	current := state.tableChecksums()
+	report.Changed, report.Unchanged = compareChecksums(previous, current)
+	for _, table := range report.Changed {
+		if err := orm.SaveGameDataChecksum(table, current[table]); err != nil {
+			logger.LogEvent("GameData", "Checksum", fmt.Sprintf("failed to save checksum of %s: %s", table, err.Error()), logger.LOG_LEVEL_WARN)
+		}
+	}
+	if len(report.Changed) == 0 {
+		logger.LogEvent("GameData", "Updated", "game data is up to date, no table changed", logger.LOG_LEVEL_INFO)
+	} else {
+		logger.LogEvent("GameData", "Updated", fmt.Sprintf("changed tables: %s", strings.Join(report.Changed, ", ")), logger.LOG_LEVEL_INFO)
+	}
+	return report, nil
+}
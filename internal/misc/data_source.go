@@ -0,0 +1,418 @@
+package misc
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DataSourceHTTP    = "http"
+	DataSourceDir     = "dir"
+	DataSourceArchive = "archive"
+
+	// checksumManifest lists the sha256 of every file of a data source, in
+	// the format of sha256sum: "<hex>  <path>" per line.
+	checksumManifest = "SHA256SUMS"
+)
+
+// DataSource provides the files of a belfast-data checkout. Paths are
+// relative to its root, e.g. "EN/ShareCfg/ship_skin_template.json".
+type DataSource interface {
+	Open(name string) (io.ReadCloser, error)
+	// List returns the .json files directly under directory, relative to the
+	// root of the source.
+	List(directory string) ([]string, error)
+	// Close releases what the source holds open once an import is done. The
+	// source can still be read afterwards, reopening what it needs.
+	Close() error
+	String() string
+}
+
+var (
+	dataSourceMu sync.RWMutex
+	dataSource   DataSource = NewHTTPDataSource()
+)
+
+// CurrentDataSource returns the source game data is imported from.
+func CurrentDataSource() DataSource {
+	dataSourceMu.RLock()
+	defer dataSourceMu.RUnlock()
+	return dataSource
+}
+
+// SetDataSource replaces the source game data is imported from.
+func SetDataSource(source DataSource) {
+	dataSourceMu.Lock()
+	defer dataSourceMu.Unlock()
+	dataSource = source
+}
+
+// NewDataSource builds a source from its kind (http, dir or archive) and
+// location: the base URL of the http source, a local checkout of
+// belfast-data, or a .zip/.tar.gz bundle of one.
+func NewDataSource(kind string, location string) (DataSource, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", DataSourceHTTP:
+		source := NewHTTPDataSource()
+		if location != "" {
+			source.RawBase = strings.TrimSuffix(location, "/")
+		}
+		return source, nil
+	case DataSourceDir:
+		if location == "" {
+			return nil, errors.New("dir data source requires a path")
+		}
+		info, err := os.Stat(location)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", location)
+		}
+		return &dirDataSource{root: location}, nil
+	case DataSourceArchive:
+		if location == "" {
+			return nil, errors.New("archive data source requires a path")
+		}
+		if !supportedArchive(location) {
+			return nil, fmt.Errorf("unsupported archive %s, expected .zip, .tar.gz or .tgz", location)
+		}
+		if _, err := os.Stat(location); err != nil {
+			return nil, err
+		}
+		return &archiveDataSource{archive: location}, nil
+	default:
+		return nil, fmt.Errorf("unknown data source %q", kind)
+	}
+}
+
+// HTTPDataSource reads files from the belfast-data GitHub repository.
+type HTTPDataSource struct {
+	RawBase string
+	APIBase string
+	Client  *http.Client
+}
+
+func NewHTTPDataSource() *HTTPDataSource {
+	return &HTTPDataSource{
+		RawBase: "https://raw.githubusercontent.com/ggmolly/belfast-data/main",
+		APIBase: "https://api.github.com/repos/ggmolly/belfast-data/contents",
+		Client:  http.DefaultClient,
+	}
+}
+
+func (source *HTTPDataSource) Open(name string) (io.ReadCloser, error) {
+	url := fmt.Sprintf("%s/%s", source.RawBase, name)
+	resp, err := source.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch data from %s: %w", url, os.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch data from %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+type githubContent struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (source *HTTPDataSource) List(directory string) ([]string, error) {
+	url := fmt.Sprintf("%s/%s", source.APIBase, directory)
+	resp, err := source.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list data from %s: %s", url, resp.Status)
+	}
+	var contents []githubContent
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&contents); err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(contents))
+	for _, entry := range contents {
+		if entry.Type != "file" || !strings.HasSuffix(entry.Name, ".json") {
+			continue
+		}
+		files = append(files, path.Join(directory, entry.Name))
+	}
+	return files, nil
+}
+
+func (source *HTTPDataSource) Close() error {
+	return nil
+}
+
+func (source *HTTPDataSource) String() string {
+	return source.RawBase
+}
+
+type dirDataSource struct {
+	root string
+}
+
+func (source *dirDataSource) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(source.root, filepath.FromSlash(name)))
+}
+
+func (source *dirDataSource) List(directory string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(source.root, filepath.FromSlash(directory)))
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		files = append(files, path.Join(directory, entry.Name()))
+	}
+	return files, nil
+}
+
+func (source *dirDataSource) Close() error {
+	return nil
+}
+
+func (source *dirDataSource) String() string {
+	return source.root
+}
+
+// archiveDataSource opens its bundle on first read and lets go of it on
+// Close, so an archive is only held open while an import runs.
+type archiveDataSource struct {
+	archive string
+
+	mu     sync.Mutex
+	opened DataSource
+}
+
+func (source *archiveDataSource) current() (DataSource, error) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.opened == nil {
+		opened, err := openArchiveDataSource(source.archive)
+		if err != nil {
+			return nil, err
+		}
+		source.opened = opened
+	}
+	return source.opened, nil
+}
+
+func (source *archiveDataSource) Open(name string) (io.ReadCloser, error) {
+	opened, err := source.current()
+	if err != nil {
+		return nil, err
+	}
+	return opened.Open(name)
+}
+
+func (source *archiveDataSource) List(directory string) ([]string, error) {
+	opened, err := source.current()
+	if err != nil {
+		return nil, err
+	}
+	return opened.List(directory)
+}
+
+func (source *archiveDataSource) Close() error {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.opened == nil {
+		return nil
+	}
+	err := source.opened.Close()
+	source.opened = nil
+	return err
+}
+
+func (source *archiveDataSource) String() string {
+	return source.archive
+}
+
+// zipDataSource reads a zip bundle in place. Bundles made from a checkout
+// usually hold a single top-level directory, which is stripped.
+type zipDataSource struct {
+	archive string
+	reader  *zip.ReadCloser
+	files   map[string]*zip.File
+}
+
+func (source *zipDataSource) Open(name string) (io.ReadCloser, error) {
+	file, ok := source.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return file.Open()
+}
+
+func (source *zipDataSource) List(directory string) ([]string, error) {
+	files := []string{}
+	for name := range source.files {
+		if path.Dir(name) == directory && strings.HasSuffix(name, ".json") {
+			files = append(files, name)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: %w", directory, os.ErrNotExist)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (source *zipDataSource) Close() error {
+	return source.reader.Close()
+}
+
+func (source *zipDataSource) String() string {
+	return source.archive
+}
+
+// tarballDataSource reads a tarball extracted to a temporary directory,
+// which Close removes.
+type tarballDataSource struct {
+	dirDataSource
+	tempDir string
+}
+
+func (source *tarballDataSource) Close() error {
+	return os.RemoveAll(source.tempDir)
+}
+
+func supportedArchive(archive string) bool {
+	lower := strings.ToLower(archive)
+	return strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+func openArchiveDataSource(archive string) (DataSource, error) {
+	lower := strings.ToLower(archive)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		reader, err := zip.OpenReader(archive)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(reader.File))
+		for _, file := range reader.File {
+			if !file.FileInfo().IsDir() {
+				names = append(names, file.Name)
+			}
+		}
+		prefix := archiveRootPrefix(names)
+		files := make(map[string]*zip.File, len(reader.File))
+		for _, file := range reader.File {
+			if !file.FileInfo().IsDir() {
+				files[strings.TrimPrefix(file.Name, prefix)] = file
+			}
+		}
+		return &zipDataSource{archive: archive, reader: reader, files: files}, nil
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		// tarballs cannot be read at random, extract them once
+		tempDir, err := os.MkdirTemp("", "belfast-data-")
+		if err != nil {
+			return nil, err
+		}
+		root, err := extractTarball(archive, tempDir)
+		if err != nil {
+			os.RemoveAll(tempDir)
+			return nil, err
+		}
+		return &tarballDataSource{dirDataSource: dirDataSource{root: root}, tempDir: tempDir}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive %s, expected .zip, .tar.gz or .tgz", archive)
+	}
+}
+
+// archiveRootPrefix returns the top-level directory shared by every entry of
+// an archive, if any.
+func archiveRootPrefix(names []string) string {
+	prefix := ""
+	for i, name := range names {
+		first, _, ok := strings.Cut(name, "/")
+		if !ok {
+			return ""
+		}
+		if i == 0 {
+			prefix = first
+		} else if first != prefix {
+			return ""
+		}
+	}
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// extractTarball extracts archive under root and returns the directory
+// holding the data, past the top-level directory of the bundle if any.
+func extractTarball(archive string, root string) (string, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return "", err
+	}
+	defer gzipReader.Close()
+	names := []string{}
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == "." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return "", fmt.Errorf("invalid archive entry %s", header.Name)
+		}
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return "", err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(out, reader); err != nil {
+			out.Close()
+			return "", err
+		}
+		if err := out.Close(); err != nil {
+			return "", err
+		}
+		names = append(names, name)
+	}
+	prefix := archiveRootPrefix(names)
+	return filepath.Join(root, filepath.FromSlash(strings.TrimSuffix(prefix, "/"))), nil
+}
@@ -0,0 +1,204 @@
+package misc
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testDataFiles = map[string]string{
+	"EN/ShareCfg/gameset.json":     `{"a":{"id":1}}`,
+	"EN/ShareCfg/guildset.json":    `[{"id":2}]`,
+	"EN/ShareCfg/readme.txt":       "not data",
+	"build_pools.json":             `{}`,
+	"EN/GameCfg/dorm.json":         `{}`,
+	"EN/sharecfgdata/skills.json":  `[]`,
+	"EN/ShareCfg/nested/deep.json": `{}`,
+}
+
+func testDataChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeTestDataDir(t *testing.T, root string) {
+	t.Helper()
+	for name, content := range testDataFiles {
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func withTestDataSource(t *testing.T, source DataSource) {
+	t.Helper()
+	previous := CurrentDataSource()
+	SetDataSource(source)
+	t.Cleanup(func() { SetDataSource(previous) })
+}
+
+func assertTestDataSource(t *testing.T, source DataSource) {
+	t.Helper()
+	withTestDataSource(t, source)
+	files, err := listBelfastDataFiles("EN", "ShareCfg")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	expected := []string{"ShareCfg/gameset.json", "ShareCfg/guildset.json"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	decoder, err := getBelfastData("EN", "ShareCfg/guildset.json")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var entries []map[string]int
+	if err := decoder.Decode(&entries); err != nil || len(entries) != 1 || entries[0]["id"] != 2 {
+		t.Fatalf("unexpected content %v (%v)", entries, err)
+	}
+	if _, err := getBelfastData("", "build_pools.json"); err != nil {
+		t.Fatalf("get regionless: %v", err)
+	}
+	if _, err := getBelfastData("EN", "ShareCfg/missing.json"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing file error, got %v", err)
+	}
+}
+
+func TestDirDataSource(t *testing.T) {
+	root := t.TempDir()
+	writeTestDataDir(t, root)
+	source, err := NewDataSource(DataSourceDir, root)
+	if err != nil {
+		t.Fatalf("new source: %v", err)
+	}
+	assertTestDataSource(t, source)
+
+	if _, err := NewDataSource(DataSourceDir, filepath.Join(root, "missing")); err == nil {
+		t.Fatalf("expected a missing directory to be refused")
+	}
+	if _, err := NewDataSource("ftp", root); err == nil {
+		t.Fatalf("expected an unknown source to be refused")
+	}
+}
+
+func TestZipDataSource(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "belfast-data.zip")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	writer := zip.NewWriter(file)
+	for name, content := range testDataFiles {
+		entry, err := writer.Create("belfast-data-main/" + name)
+		if err != nil {
+			t.Fatalf("zip entry: %v", err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	file.Close()
+	source, err := NewDataSource(DataSourceArchive, archive)
+	if err != nil {
+		t.Fatalf("new source: %v", err)
+	}
+	assertTestDataSource(t, source)
+	if err := source.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestTarballDataSource(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "belfast-data.tar.gz")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	gzipWriter := gzip.NewWriter(file)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range testDataFiles {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatalf("tar write: %v", err)
+		}
+	}
+	writer.Close()
+	gzipWriter.Close()
+	file.Close()
+	source, err := NewDataSource(DataSourceArchive, archive)
+	if err != nil {
+		t.Fatalf("new source: %v", err)
+	}
+	assertTestDataSource(t, source)
+
+	extracted := source.(*archiveDataSource).opened.(*tarballDataSource).tempDir
+	if err := source.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(extracted); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the extracted tarball to be removed, got %v", err)
+	}
+	// a closed source is extracted again on the next import
+	assertTestDataSource(t, source)
+	t.Cleanup(func() { source.Close() })
+}
+
+func TestChecksumVerification(t *testing.T) {
+	root := t.TempDir()
+	writeTestDataDir(t, root)
+	manifest := testDataChecksum(testDataFiles["EN/ShareCfg/gameset.json"]) + "  ./EN/ShareCfg/gameset.json\n" +
+		strings.Repeat("0", 64) + " *EN/ShareCfg/guildset.json\n"
+	if err := os.WriteFile(filepath.Join(root, checksumManifest), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	source, err := NewDataSource(DataSourceDir, root)
+	if err != nil {
+		t.Fatalf("new source: %v", err)
+	}
+	withTestDataSource(t, source)
+	state := newImportState(source)
+	setActiveImport(state)
+	t.Cleanup(func() { setActiveImport(nil) })
+
+	state.setTable("Configs")
+	if _, err := getBelfastData("EN", "ShareCfg/gameset.json"); err != nil {
+		t.Fatalf("expected listed file to verify, got %v", err)
+	}
+	if _, err := getBelfastData("EN", "ShareCfg/guildset.json"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := getBelfastData("EN", "GameCfg/dorm.json"); err == nil || !strings.Contains(err.Error(), "not listed") {
+		t.Fatalf("expected unlisted file to be refused, got %v", err)
+	}
+	checksums := state.tableChecksums()
+	if len(checksums) != 1 || checksums["Configs"] == "" {
+		t.Fatalf("expected a checksum for Configs, got %v", checksums)
+	}
+}
+
+func TestCompareChecksums(t *testing.T) {
+	previous := map[string]string{"Items": "a", "Ships": "b"}
+	current := map[string]string{"Items": "a", "Ships": "c", "Skins": "d"}
+	changed, unchanged := compareChecksums(previous, current)
+	if !reflect.DeepEqual(changed, []string{"Ships", "Skins"}) || !reflect.DeepEqual(unchanged, []string{"Items"}) {
+		t.Fatalf("unexpected report changed=%v unchanged=%v", changed, unchanged)
+	}
+}
@@ -1,19 +1,14 @@
 package misc
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
-	"net/http"
+	"io"
+	"path"
 	"strconv"
 	"strings"
-
-	"github.com/ggmolly/belfast/internal/logger"
-)
-
-const (
-	// region / file
-	URL_BASE            = "https://raw.githubusercontent.com/ggmolly/belfast-data/main/%s/%s"
-	REGIONLESS_URL_BASE = "https://raw.githubusercontent.com/ggmolly/belfast-data/main/%s"
 )
 
 var (
@@ -21,49 +16,42 @@ var (
 	order = []string{"Items", "Buffs", "Ships", "Skins", "Resources", "Pools", "Requisition", "BuildTimes", "ShopOffers", "Weapons", "Equipments", "Skills", "Configs", "JuustagramTemplates", "JuustagramNpcTemplates", "JuustagramLanguage", "JuustagramShipGroups"}
 )
 
-func getBelfastData(region string, file string) (*json.Decoder, *http.Response, error) {
-	var url string
-	if region == "" {
-		url = fmt.Sprintf(REGIONLESS_URL_BASE, file)
-	} else {
-		url = fmt.Sprintf(URL_BASE, region, file)
+// getBelfastData reads a file of the current data source, verifies it against
+// the checksum manifest and records its hash for the change report.
+func getBelfastData(region string, file string) (*json.Decoder, error) {
+	name := file
+	if region != "" {
+		name = path.Join(region, file)
 	}
-	resp, err := http.Get(url)
+	state := currentImport()
+	source := state.source
+	reader, err := source.Open(name)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("failed to fetch data from %s: %s", url, resp.Status)
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
 	}
-	return json.NewDecoder(resp.Body), resp, nil
-}
-
-type githubContent struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if err := state.verify(name, digest); err != nil {
+		return nil, err
+	}
+	state.record(name, digest)
+	return json.NewDecoder(bytes.NewReader(data)), nil
 }
 
+// listBelfastDataFiles returns the .json files of a directory, relative to the
+// region, e.g. "ShareCfg/gameset.json".
 func listBelfastDataFiles(region string, directory string) ([]string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/ggmolly/belfast-data/contents/%s/%s", region, directory)
-	resp, err := http.Get(url)
+	files, err := currentImport().source.List(path.Join(region, directory))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list data from %s: %s", url, resp.Status)
-	}
-	var contents []githubContent
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&contents); err != nil {
-		return nil, err
-	}
-	files := make([]string, 0, len(contents))
-	for _, entry := range contents {
-		if entry.Type != "file" || !strings.HasSuffix(entry.Name, ".json") {
-			continue
-		}
-		files = append(files, fmt.Sprintf("%s/%s", directory, entry.Name))
+	for i, file := range files {
+		files[i] = strings.TrimPrefix(file, region+"/")
 	}
 	return files, nil
 }
@@ -122,8 +110,3 @@ func parseConfigKey(value json.RawMessage) (string, bool) {
 	}
 	return "", false
 }
-
-func UpdateAllData(region string) {
-	logger.LogEvent("GameData", "Updating", "Updating all game data.. this may take a while.", logger.LOG_LEVEL_INFO)
-	updateAllDataSQLC(region)
-}
@@ -34,32 +34,29 @@ var dataFnSQLC = map[string]func(context.Context, string, *gen.Queries) error{
 	"JuustagramShipGroups":   importJuustagramShipGroupsSQLC,
 }
 
-func updateAllDataSQLC(region string) {
+func updateAllDataSQLC(region string, state *importState) error {
 	ctx := context.Background()
-	err := db.DefaultStore.WithTx(ctx, func(q *gen.Queries) error {
+	return db.DefaultStore.WithTx(ctx, func(q *gen.Queries) error {
 		for _, key := range order {
 			fn := dataFnSQLC[key]
 			if fn == nil {
 				return fmt.Errorf("missing sqlc importer for %s", key)
 			}
 			logger.LogEvent("GameData", "Updating", fmt.Sprintf("Updating %s (region=%s)", key, region), logger.LOG_LEVEL_INFO)
+			state.setTable(key)
 			if err := fn(ctx, region, q); err != nil {
 				return err
 			}
 		}
 		return nil
 	})
-	if err != nil {
-		logger.LogEvent("GameData", "Updating", fmt.Sprintf("failed to update game data: %s", err.Error()), logger.LOG_LEVEL_ERROR)
-	}
 }
 
 func importItemsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "sharecfgdata/item_data_statistics.json")
+	decoder, err := getBelfastData(region, "sharecfgdata/item_data_statistics.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var item orm.Item
@@ -81,11 +78,10 @@ func importItemsSQLC(ctx context.Context, region string, q *gen.Queries) error {
 }
 
 func importBuffsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/benefit_buff_template.json")
+	decoder, err := getBelfastData(region, "ShareCfg/benefit_buff_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var buff orm.Buff
@@ -106,11 +102,10 @@ func importBuffsSQLC(ctx context.Context, region string, q *gen.Queries) error {
 }
 
 func importShipsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "sharecfgdata/ship_data_statistics.json")
+	decoder, err := getBelfastData(region, "sharecfgdata/ship_data_statistics.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var ship orm.Ship
@@ -135,11 +130,10 @@ func importShipsSQLC(ctx context.Context, region string, q *gen.Queries) error {
 }
 
 func importSkinsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/ship_skin_template.json")
+	decoder, err := getBelfastData(region, "ShareCfg/ship_skin_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var skin orm.Skin
@@ -206,11 +200,10 @@ func importSkinsSQLC(ctx context.Context, region string, q *gen.Queries) error {
 }
 
 func importResourcesSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/player_resource.json")
+	decoder, err := getBelfastData(region, "ShareCfg/player_resource.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var resource orm.Resource
@@ -229,11 +222,10 @@ func importResourcesSQLC(ctx context.Context, region string, q *gen.Queries) err
 }
 
 func importPoolsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData("", "build_pools.json")
+	decoder, err := getBelfastData("", "build_pools.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var pool struct {
@@ -258,11 +250,10 @@ func importPoolsSQLC(ctx context.Context, region string, q *gen.Queries) error {
 }
 
 func importRequisitionShipsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData("", "requisition_ships.json")
+	decoder, err := getBelfastData("", "requisition_ships.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	var shipIDs []uint32
 	if err := decoder.Decode(&shipIDs); err != nil {
 		return err
@@ -276,11 +267,10 @@ func importRequisitionShipsSQLC(ctx context.Context, region string, q *gen.Queri
 }
 
 func importBuildTimesSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData("", "build_times.json")
+	decoder, err := getBelfastData("", "build_times.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	var buildTimes map[string]uint32
 	if err := decoder.Decode(&buildTimes); err != nil {
 		return err
@@ -305,11 +295,10 @@ func importBuildTimesSQLC(ctx context.Context, region string, q *gen.Queries) er
 }
 
 func importShopOffersSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "sharecfgdata/shop_template.json")
+	decoder, err := getBelfastData(region, "sharecfgdata/shop_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var offer orm.ShopOffer
@@ -342,11 +331,10 @@ func importShopOffersSQLC(ctx context.Context, region string, q *gen.Queries) er
 }
 
 func importWeaponsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "sharecfgdata/weapon_property.json")
+	decoder, err := getBelfastData(region, "sharecfgdata/weapon_property.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var weapon orm.Weapon
@@ -395,11 +383,10 @@ func importWeaponsSQLC(ctx context.Context, region string, q *gen.Queries) error
 }
 
 func importEquipmentsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "sharecfgdata/equip_data_template.json")
+	decoder, err := getBelfastData(region, "sharecfgdata/equip_data_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var equip orm.Equipment
@@ -432,11 +419,10 @@ func importEquipmentsSQLC(ctx context.Context, region string, q *gen.Queries) er
 }
 
 func importSkillsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "GameCfg/skill.json")
+	decoder, err := getBelfastData(region, "GameCfg/skill.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	var skillMap map[string]orm.Skill
 	if err := decoder.Decode(&skillMap); err != nil {
 		return err
@@ -482,6 +468,9 @@ func importConfigEntriesSQLC(ctx context.Context, region string, q *gen.Queries)
 			"ship_meta_",
 			"technology_",
 			"shop_",
+			"guild_",
+			"commander_",
+			"world_",
 		},
 		[]string{
 			"ShareCfg/tutorial_handbook.json",
@@ -512,6 +501,7 @@ func importConfigEntriesSQLC(ctx context.Context, region string, q *gen.Queries)
 			"ShareCfg/compose_data_template.json",
 			"ShareCfg/equip_upgrade_data.json",
 			"ShareCfg/month_shop_template.json",
+			"ShareCfg/pay_data_display.json",
 			"ShareCfg/medal_template.json",
 			"ShareCfg/newserver_shop_template.json",
 			"ShareCfg/blackfriday_shop_template.json",
@@ -523,10 +513,13 @@ func importConfigEntriesSQLC(ctx context.Context, region string, q *gen.Queries)
 			"ShareCfg/re_map_template.json",
 			"ShareCfg/escort_template.json",
 			"ShareCfg/escort_map_template.json",
+			"ShareCfg/expedition_daily_template.json",
 			"ShareCfg/shop_banner_template.json",
 			"ShareCfg/shop_discount_coupon_template.json",
 			"ShareCfg/emoji_template.json",
 			"sharecfgdata/expedition_data_template.json",
+			"sharecfgdata/task_data_template.json",
+			"ShareCfg/weekly_task_reward.json",
 			"ShareCfg/skill_data_template.json",
 			"ShareCfg/ship_level.json",
 			"ShareCfg/user_level.json",
@@ -544,11 +537,10 @@ func importConfigEntriesSQLC(ctx context.Context, region string, q *gen.Queries)
 }
 
 func importConfigEntriesFromFileSQLC(ctx context.Context, region string, file string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, file)
+	decoder, err := getBelfastData(region, file)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	firstToken, err := decoder.Token()
 	if err != nil {
 		return err
@@ -596,11 +588,10 @@ func importConfigEntriesFromFileSQLC(ctx context.Context, region string, file st
 }
 
 func importJuustagramTemplatesSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/activity_ins_template.json")
+	decoder, err := getBelfastData(region, "ShareCfg/activity_ins_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var template orm.JuustagramTemplate
@@ -639,11 +630,10 @@ func importJuustagramTemplatesSQLC(ctx context.Context, region string, q *gen.Qu
 }
 
 func importJuustagramNpcTemplatesSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/activity_ins_npc_template.json")
+	decoder, err := getBelfastData(region, "ShareCfg/activity_ins_npc_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var template orm.JuustagramNpcTemplate
@@ -672,11 +662,10 @@ func importJuustagramNpcTemplatesSQLC(ctx context.Context, region string, q *gen
 }
 
 func importJuustagramLanguageSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/activity_ins_language.json")
+	decoder, err := getBelfastData(region, "ShareCfg/activity_ins_language.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	var entries map[string]struct {
 		Value string `json:"value"`
 	}
@@ -692,11 +681,10 @@ func importJuustagramLanguageSQLC(ctx context.Context, region string, q *gen.Que
 }
 
 func importJuustagramShipGroupsSQLC(ctx context.Context, region string, q *gen.Queries) error {
-	decoder, resp, err := getBelfastData(region, "ShareCfg/activity_ins_ship_group_template.json")
+	decoder, err := getBelfastData(region, "ShareCfg/activity_ins_ship_group_template.json")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	decoder.Token()
 	for decoder.More() {
 		var template orm.JuustagramShipGroupTemplate
@@ -0,0 +1,191 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+const (
+	LocalName             = "local"
+	defaultLocalDir       = "data/previews"
+	defaultMaxObjectBytes = 4 << 20
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid upload token")
+	ErrObjectTooLarge = errors.New("object too large")
+)
+
+// Local keeps objects under Dir. Uploads and downloads go through the REST
+// API; the upload token is an HMAC of the commander prefix and its expiry.
+type Local struct {
+	Dir            string
+	MaxObjectBytes int64
+	ttl            time.Duration
+	secret         []byte
+}
+
+// NewLocal builds a local store. Without a secret, a random one is drawn and
+// tokens issued before a restart stop working.
+func NewLocal(cfg config.StorageConfig) (*Local, error) {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = defaultLocalDir
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	maxBytes := cfg.MaxObjectBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxObjectBytes
+	}
+	return &Local{Dir: dir, MaxObjectBytes: maxBytes, ttl: credentialsTTL(cfg), secret: secret}, nil
+}
+
+func (local *Local) Name() string {
+	return LocalName
+}
+
+func (local *Local) Credentials(ctx context.Context, commanderID uint32, now time.Time) (Credentials, error) {
+	prefix := CommanderPrefix(commanderID)
+	expires := now.Add(local.ttl).Truncate(time.Second)
+	token := fmt.Sprintf("%d.%d.%s", commanderID, expires.Unix(), local.sign(prefix, expires.Unix()))
+	return Credentials{
+		AccessID:      strconv.FormatUint(uint64(commanderID), 10),
+		SecurityToken: token,
+		Prefix:        prefix,
+		Expires:       expires,
+	}, nil
+}
+
+// Authorize checks that token allows uploading key at now.
+func (local *Local) Authorize(token string, key string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidToken
+	}
+	commanderID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	prefix := CommanderPrefix(uint32(commanderID))
+	if !hmac.Equal([]byte(parts[2]), []byte(local.sign(prefix, expires))) {
+		return ErrInvalidToken
+	}
+	if now.Unix() > expires || !strings.HasPrefix(key, prefix) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// Put stores the content of r under key. The content must not exceed
+// MaxObjectBytes and, when md5 is set, must match it.
+func (local *Local) Put(key string, r io.Reader, md5Hex string) (ObjectInfo, error) {
+	path, err := local.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ObjectInfo{}, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer os.Remove(tmp.Name())
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, local.MaxObjectBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if written > local.MaxObjectBytes {
+		return ObjectInfo{}, ErrObjectTooLarge
+	}
+	info := ObjectInfo{Size: written, MD5: hex.EncodeToString(hash.Sum(nil))}
+	if md5Hex != "" && !strings.EqualFold(md5Hex, info.MD5) {
+		return ObjectInfo{}, ErrMd5Mismatch
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return ObjectInfo{}, err
+	}
+	return info, nil
+}
+
+// Open returns the content of key; the caller closes it.
+func (local *Local) Open(key string) (*os.File, error) {
+	path, err := local.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (local *Local) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	file, err := local.Open(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer file.Close()
+	hash := md5.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: size, MD5: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func (local *Local) Delete(ctx context.Context, key string) error {
+	path, err := local.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (local *Local) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(local.Dir, filepath.FromSlash(key)), nil
+}
+
+func (local *Local) sign(prefix string, expires int64) string {
+	mac := hmac.New(sha256.New, local.secret)
+	fmt.Fprintf(mac, "%s\n%d", prefix, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,176 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { setCurrent(nil) })
+
+	if err := Configure(config.StorageConfig{Provider: LocalName, Dir: t.TempDir()}); err != nil {
+		t.Fatalf("configure local: %v", err)
+	}
+	local, ok := Current().(*Local)
+	if !ok {
+		t.Fatalf("expected local store, got %#v", Current())
+	}
+	if err := Configure(config.StorageConfig{Provider: S3Name, Endpoint: "http://127.0.0.1:9000", Bucket: "previews"}); err == nil {
+		t.Fatalf("expected s3 without assume_role to be refused")
+	}
+	if Current() != local {
+		t.Fatalf("expected invalid config to keep the previous store")
+	}
+	if err := Configure(config.StorageConfig{Provider: "disabled"}); err != nil {
+		t.Fatalf("configure disabled: %v", err)
+	}
+	if Current() != nil {
+		t.Fatalf("expected storage to be disabled")
+	}
+}
+
+func TestLocalUploadFlow(t *testing.T) {
+	local, err := NewLocal(config.StorageConfig{Dir: t.TempDir(), Secret: "secret", MaxObjectBytes: 16})
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	creds, err := local.Credentials(context.Background(), 42, now)
+	if err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	if creds.Prefix != "backyard/42/" || creds.Expires != now.Add(defaultCredentialsTTL) {
+		t.Fatalf("unexpected credentials %+v", creds)
+	}
+
+	content := []byte("preview")
+	key := PreviewKey(42, md5Hex(content))
+	if err := local.Authorize(creds.SecurityToken, key, now); err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if err := local.Authorize(creds.SecurityToken, PreviewKey(43, md5Hex(content)), now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected another commander's key to be refused, got %v", err)
+	}
+	if err := local.Authorize(creds.SecurityToken, key, creds.Expires.Add(time.Second)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected expired token to be refused, got %v", err)
+	}
+	if err := local.Authorize(creds.SecurityToken+"0", key, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected tampered token to be refused, got %v", err)
+	}
+
+	if _, err := local.Put(key, bytes.NewReader(content), md5Hex([]byte("other"))); !errors.Is(err, ErrMd5Mismatch) {
+		t.Fatalf("expected md5 mismatch, got %v", err)
+	}
+	if _, err := local.Put(key, bytes.NewReader(bytes.Repeat([]byte("x"), 17)), ""); !errors.Is(err, ErrObjectTooLarge) {
+		t.Fatalf("expected too large object, got %v", err)
+	}
+	if err := Verify(context.Background(), local, 42, md5Hex(content)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected refused uploads to leave nothing, got %v", err)
+	}
+	if _, err := local.Put(key, bytes.NewReader(content), md5Hex(content)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := Verify(context.Background(), local, 42, strings.ToUpper(md5Hex(content))); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := local.Delete(context.Background(), key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := local.Delete(context.Background(), key); err != nil {
+		t.Fatalf("delete twice: %v", err)
+	}
+	if _, err := local.Stat(context.Background(), "backyard/../../etc/passwd"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected escaping key to be refused, got %v", err)
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	// Example of the AWS Signature Version 4 documentation.
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Fatalf("unexpected signing key %s", got)
+	}
+}
+
+func TestS3StatDeleteAndAssumeRole(t *testing.T) {
+	content := []byte("preview")
+	objects := map[string]string{
+		"/previews/backyard/42/" + md5Hex(content): `"` + md5Hex(content) + `"`,
+		"/previews/backyard/42/multipart":          `"0123-2"`,
+	}
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=server/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodPost {
+			_ = r.ParseForm()
+			if r.Form.Get("Action") != "AssumeRole" || !strings.Contains(r.Form.Get("Policy"), "previews/backyard/42/*") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>tmp</AccessKeyId><SecretAccessKey>tmp-secret</SecretAccessKey><SessionToken>tmp-token</SessionToken><Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+			return
+		}
+		etag, ok := objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write(content)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewS3(config.StorageConfig{Endpoint: server.URL, Bucket: "previews", AccessKey: "server", SecretKey: "secret", AssumeRole: true})
+	if err != nil {
+		t.Fatalf("new s3: %v", err)
+	}
+	ctx := context.Background()
+	if err := Verify(ctx, store, 42, md5Hex(content)); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	info, err := store.Stat(ctx, "backyard/42/multipart")
+	if err != nil || info.MD5 != md5Hex(content) {
+		t.Fatalf("expected multipart object to be hashed, got %+v %v", info, err)
+	}
+	if _, err := store.Stat(ctx, "backyard/42/missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected missing object, got %v", err)
+	}
+	if err := store.Delete(ctx, "backyard/42/missing"); err != nil {
+		t.Fatalf("delete missing: %v", err)
+	}
+	if err := store.Delete(ctx, "backyard/42/multipart"); err != nil || len(deleted) != 1 {
+		t.Fatalf("delete: %v %v", err, deleted)
+	}
+
+	creds, err := store.Credentials(ctx, 42, time.Now())
+	if err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	if creds.AccessID != "tmp" || creds.AccessSecret != "tmp-secret" || creds.SecurityToken != "tmp-token" || creds.Expires.Year() != 2030 {
+		t.Fatalf("unexpected credentials %+v", creds)
+	}
+}
@@ -0,0 +1,283 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+const (
+	S3Name           = "s3"
+	defaultS3Region  = "us-east-1"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3 stores objects in a bucket of any S3-compatible endpoint. The server
+// signs its own requests with AccessKey; clients get short-lived STS
+// credentials restricted to their prefix.
+type S3 struct {
+	Endpoint           *url.URL
+	STSEndpoint        *url.URL
+	Region             string
+	Bucket             string
+	AccessKey          string
+	SecretKey          string
+	VirtualHostedStyle bool
+	RoleARN            string
+	Client             *http.Client
+	ttl                time.Duration
+}
+
+// NewS3 builds an S3 store. Clients never get a long-lived key pair, so
+// assume_role is required.
+func NewS3(cfg config.StorageConfig) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 storage needs an endpoint and a bucket")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	stsEndpoint := endpoint
+	if cfg.STSEndpoint != "" {
+		stsEndpoint, err = url.Parse(strings.TrimRight(cfg.STSEndpoint, "/"))
+		if err != nil || stsEndpoint.Host == "" {
+			return nil, fmt.Errorf("invalid sts endpoint %q", cfg.STSEndpoint)
+		}
+	}
+	if !cfg.AssumeRole {
+		return nil, errors.New("s3 storage needs assume_role to hand out scoped credentials")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	return &S3{
+		Endpoint:           endpoint,
+		STSEndpoint:        stsEndpoint,
+		Region:             region,
+		Bucket:             cfg.Bucket,
+		AccessKey:          cfg.AccessKey,
+		SecretKey:          cfg.SecretKey,
+		VirtualHostedStyle: cfg.VirtualHostedStyle,
+		RoleARN:            cfg.RoleARN,
+		Client:             &http.Client{Timeout: 10 * time.Second},
+		ttl:                credentialsTTL(cfg),
+	}, nil
+}
+
+func (store *S3) Name() string {
+	return S3Name
+}
+
+func (store *S3) Credentials(ctx context.Context, commanderID uint32, now time.Time) (Credentials, error) {
+	return store.assumeRole(ctx, commanderID, CommanderPrefix(commanderID), now)
+}
+
+func (store *S3) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := store.do(ctx, http.MethodHead, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	etag := strings.ToLower(strings.Trim(resp.Header.Get("ETag"), `"`))
+	if ValidMd5(etag) {
+		return ObjectInfo{Size: resp.ContentLength, MD5: etag}, nil
+	}
+	// Multipart and encrypted objects have no md5 ETag; hash the content.
+	resp, err = store.do(ctx, http.MethodGet, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	hash := md5.New()
+	size, err := io.Copy(hash, resp.Body)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: size, MD5: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func (store *S3) Delete(ctx context.Context, key string) error {
+	resp, err := store.do(ctx, http.MethodDelete, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ObjectURL returns the URL of key in the bucket.
+func (store *S3) ObjectURL(key string) string {
+	u := *store.Endpoint
+	if store.VirtualHostedStyle {
+		u.Host = store.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	} else {
+		u.Path = u.Path + "/" + store.Bucket + "/" + key
+	}
+	return u.String()
+}
+
+func (store *S3) do(ctx context.Context, method string, key string) (*http.Response, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	req, err := http.NewRequestWithContext(ctx, method, store.ObjectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	signRequest(req, emptyPayloadHash, "s3", store.Region, store.AccessKey, store.SecretKey, time.Now())
+	resp, err := store.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s", method, key, resp.Status)
+	}
+	return resp, nil
+}
+
+type assumeRoleResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleResult>Credentials"`
+}
+
+// assumeRole asks the STS endpoint for credentials that may only put
+// objects under prefix.
+func (store *S3) assumeRole(ctx context.Context, commanderID uint32, prefix string, now time.Time) (Credentials, error) {
+	policy, err := json.Marshal(map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{{
+			"Effect":   "Allow",
+			"Action":   []string{"s3:PutObject"},
+			"Resource": []string{fmt.Sprintf("arn:aws:s3:::%s/%s*", store.Bucket, prefix)},
+		}},
+	})
+	if err != nil {
+		return Credentials{}, err
+	}
+	form := url.Values{}
+	form.Set("Action", "AssumeRole")
+	form.Set("Version", "2011-06-15")
+	form.Set("DurationSeconds", strconv.Itoa(int(store.ttl/time.Second)))
+	form.Set("Policy", string(policy))
+	form.Set("RoleSessionName", fmt.Sprintf("belfast-%d", commanderID))
+	if store.RoleARN != "" {
+		form.Set("RoleArn", store.RoleARN)
+	}
+	body := form.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, store.STSEndpoint.String()+"/", strings.NewReader(body))
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	signRequest(req, hashHex([]byte(body)), "sts", store.Region, store.AccessKey, store.SecretKey, now)
+	resp, err := store.Client.Do(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("sts assume role: %s", resp.Status)
+	}
+	var payload assumeRoleResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return Credentials{}, fmt.Errorf("sts assume role: %w", err)
+	}
+	expires := payload.Credentials.Expiration
+	if expires.IsZero() {
+		expires = now.Add(store.ttl)
+	}
+	return Credentials{
+		AccessID:      payload.Credentials.AccessKeyID,
+		AccessSecret:  payload.Credentials.SecretAccessKey,
+		SecurityToken: payload.Credentials.SessionToken,
+		Prefix:        prefix,
+		Expires:       expires,
+	}, nil
+}
+
+// signRequest adds an AWS Signature Version 4 Authorization header to req.
+func signRequest(req *http.Request, payloadHash string, service string, region string, accessKey string, secretKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	query := strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20")
+	canonicalRequest := strings.Join([]string{req.Method, path, query, canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+	signature := hex.EncodeToString(hmacSHA256(signingKey(secretKey, day, region, service), stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func signingKey(secretKey string, day string, region string, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,164 @@
+// Package objectstore keeps the backyard theme previews uploaded by clients,
+// either on the local filesystem or in an S3-compatible bucket. Clients get
+// temporary upload credentials from the store, and previews are named after
+// their md5 so the server can check what was uploaded.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+const defaultCredentialsTTL = 15 * time.Minute
+
+var (
+	ErrNotFound    = errors.New("object not found")
+	ErrMd5Mismatch = errors.New("object md5 mismatch")
+	ErrInvalidKey  = errors.New("invalid object key")
+)
+
+var md5Pattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Credentials are handed to the client in SC_19104. They only allow uploads
+// under Prefix until Expires.
+type Credentials struct {
+	AccessID      string
+	AccessSecret  string
+	SecurityToken string
+	Prefix        string
+	Expires       time.Time
+}
+
+// ObjectInfo describes a stored object. MD5 is the lowercase hex digest of
+// its content.
+type ObjectInfo struct {
+	Size int64
+	MD5  string
+}
+
+// Store is an object storage backend.
+type Store interface {
+	Name() string
+	Credentials(ctx context.Context, commanderID uint32, now time.Time) (Credentials, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Factory builds a store from the [storage] section.
+type Factory func(cfg config.StorageConfig) (Store, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		LocalName: func(cfg config.StorageConfig) (Store, error) {
+			return NewLocal(cfg)
+		},
+		S3Name: func(cfg config.StorageConfig) (Store, error) {
+			return NewS3(cfg)
+		},
+	}
+
+	currentMu sync.RWMutex
+	current   Store
+)
+
+// RegisterStore makes a store selectable by name in the config.
+func RegisterStore(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// Configure selects the store named in cfg. The previous store is kept when
+// cfg is invalid.
+func Configure(cfg config.StorageConfig) error {
+	name := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if name == "" || name == "disabled" {
+		setCurrent(nil)
+		return nil
+	}
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown storage provider %q", cfg.Provider)
+	}
+	store, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+	setCurrent(store)
+	return nil
+}
+
+// Current returns the configured store, nil when storage is disabled.
+func Current() Store {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+func setCurrent(store Store) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = store
+}
+
+// CommanderPrefix is the key prefix a commander may upload to.
+func CommanderPrefix(commanderID uint32) string {
+	return fmt.Sprintf("backyard/%d/", commanderID)
+}
+
+// PreviewKey returns the key of a theme preview uploaded by a commander.
+func PreviewKey(commanderID uint32, md5 string) string {
+	return CommanderPrefix(commanderID) + strings.ToLower(md5)
+}
+
+// ValidMd5 reports whether s is a lowercase hex md5 digest.
+func ValidMd5(s string) bool {
+	return md5Pattern.MatchString(s)
+}
+
+// Verify checks that the preview of md5 was uploaded by the commander and
+// matches its digest.
+func Verify(ctx context.Context, store Store, commanderID uint32, md5 string) error {
+	md5 = strings.ToLower(md5)
+	if !ValidMd5(md5) {
+		return ErrInvalidKey
+	}
+	info, err := store.Stat(ctx, PreviewKey(commanderID, md5))
+	if err != nil {
+		return err
+	}
+	if info.MD5 != md5 {
+		return ErrMd5Mismatch
+	}
+	return nil
+}
+
+// validKey refuses keys escaping the store, such as "../x" or "/x".
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func credentialsTTL(cfg config.StorageConfig) time.Duration {
+	if cfg.CredentialsTTLSeconds <= 0 {
+		return defaultCredentialsTTL
+	}
+	return time.Duration(cfg.CredentialsTTLSeconds) * time.Second
+}
@@ -0,0 +1,217 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// ActivityEventProgress is the progress of a commander in a PT, event shop,
+// login sign-in or task list activity. Only the fields matching the type of
+// the activity are used: ShopPurchases maps an activity_shop_template id to
+// the number of times it was bought, LastSignAt is a unix time.
+type ActivityEventProgress struct {
+	CommanderID    uint32
+	ActivityID     uint32
+	Pt             uint32
+	ClaimedTargets Int64List
+	SignDays       uint32
+	LastSignAt     uint32
+	ShopPurchases  map[uint32]uint32
+	ClaimedTasks   Int64List
+	UpdatedAt      time.Time
+}
+
+func (ActivityEventProgress) TableName() string {
+	return "activity_event_progress"
+}
+
+const activityEventProgressColumns = `commander_id, activity_id, pt, claimed_targets, sign_days, last_sign_at, shop_purchases, claimed_tasks, updated_at`
+
+func scanActivityEventProgress(scanner rowScanner) (*ActivityEventProgress, error) {
+	progress := ActivityEventProgress{}
+	var purchases []byte
+	err := scanner.Scan(
+		&progress.CommanderID,
+		&progress.ActivityID,
+		&progress.Pt,
+		&progress.ClaimedTargets,
+		&progress.SignDays,
+		&progress.LastSignAt,
+		&purchases,
+		&progress.ClaimedTasks,
+		&progress.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(purchases) > 0 {
+		if err := json.Unmarshal(purchases, &progress.ShopPurchases); err != nil {
+			return nil, err
+		}
+	}
+	progress.normalize()
+	return &progress, nil
+}
+
+func (progress *ActivityEventProgress) normalize() {
+	if progress.ClaimedTargets == nil {
+		progress.ClaimedTargets = Int64List{}
+	}
+	if progress.ShopPurchases == nil {
+		progress.ShopPurchases = map[uint32]uint32{}
+	}
+	if progress.ClaimedTasks == nil {
+		progress.ClaimedTasks = Int64List{}
+	}
+}
+
+// GetActivityEventProgress returns the stored progress, or an empty one
+// when the commander never took part in the activity.
+func GetActivityEventProgress(commanderID uint32, activityID uint32) (*ActivityEventProgress, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+activityEventProgressColumns+`
+FROM activity_event_progress
+WHERE commander_id = $1 AND activity_id = $2
+`, int64(commanderID), int64(activityID))
+	return activityEventProgressOrEmpty(row, commanderID, activityID)
+}
+
+// GetActivityEventProgressTx is GetActivityEventProgress, locking the row
+// until tx ends.
+func GetActivityEventProgressTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32) (*ActivityEventProgress, error) {
+	row := tx.QueryRow(ctx, `
+SELECT `+activityEventProgressColumns+`
+FROM activity_event_progress
+WHERE commander_id = $1 AND activity_id = $2
+FOR UPDATE
+`, int64(commanderID), int64(activityID))
+	return activityEventProgressOrEmpty(row, commanderID, activityID)
+}
+
+func activityEventProgressOrEmpty(row rowScanner, commanderID uint32, activityID uint32) (*ActivityEventProgress, error) {
+	progress, err := scanActivityEventProgress(row)
+	err = db.MapNotFound(err)
+	if db.IsNotFound(err) {
+		progress = &ActivityEventProgress{CommanderID: commanderID, ActivityID: activityID}
+		progress.normalize()
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// ListActivityEventProgress returns the progress of a commander in every
+// activity, ordered by activity id.
+func ListActivityEventProgress(commanderID uint32) ([]ActivityEventProgress, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+activityEventProgressColumns+`
+FROM activity_event_progress
+WHERE commander_id = $1
+ORDER BY activity_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []ActivityEventProgress{}
+	for rows.Next() {
+		progress, err := scanActivityEventProgress(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *progress)
+	}
+	return list, rows.Err()
+}
+
+func SaveActivityEventProgressTx(ctx context.Context, tx pgx.Tx, progress *ActivityEventProgress) error {
+	progress.normalize()
+	purchases, err := json.Marshal(progress.ShopPurchases)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow(ctx, `
+INSERT INTO activity_event_progress (commander_id, activity_id, pt, claimed_targets, sign_days, last_sign_at, shop_purchases, claimed_tasks, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+ON CONFLICT (commander_id, activity_id)
+DO UPDATE SET
+  pt = EXCLUDED.pt,
+  claimed_targets = EXCLUDED.claimed_targets,
+  sign_days = EXCLUDED.sign_days,
+  last_sign_at = EXCLUDED.last_sign_at,
+  shop_purchases = EXCLUDED.shop_purchases,
+  claimed_tasks = EXCLUDED.claimed_tasks,
+  updated_at = NOW()
+RETURNING updated_at
+`, int64(progress.CommanderID), int64(progress.ActivityID), int64(progress.Pt), progress.ClaimedTargets, int64(progress.SignDays), int64(progress.LastSignAt), purchases, progress.ClaimedTasks).Scan(&progress.UpdatedAt)
+}
+
+func SaveActivityEventProgress(progress *ActivityEventProgress) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveActivityEventProgressTx(ctx, tx, progress)
+	})
+}
+
+// AddActivityEventPt raises the PT of a commander in an activity and
+// returns the new total.
+func AddActivityEventPt(commanderID uint32, activityID uint32, amount uint32) (uint32, error) {
+	ctx := context.Background()
+	var pt uint32
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		pt, err = AddActivityEventPtTx(ctx, tx, commanderID, activityID, amount)
+		return err
+	})
+	return pt, err
+}
+
+func AddActivityEventPtTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32, amount uint32) (uint32, error) {
+	var pt int64
+	err := tx.QueryRow(ctx, `
+INSERT INTO activity_event_progress (commander_id, activity_id, pt, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (commander_id, activity_id)
+DO UPDATE SET
+  pt = activity_event_progress.pt + EXCLUDED.pt,
+  updated_at = NOW()
+RETURNING pt
+`, int64(commanderID), int64(activityID), int64(amount)).Scan(&pt)
+	return uint32(pt), err
+}
+
+// DeleteActivityEventProgressTx resets the progress of a commander in an
+// activity.
+func DeleteActivityEventProgressTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32) error {
+	_, err := tx.Exec(ctx, `
+DELETE FROM activity_event_progress
+WHERE commander_id = $1 AND activity_id = $2
+`, int64(commanderID), int64(activityID))
+	return err
+}
+
+func (progress *ActivityEventProgress) HasClaimedTarget(target uint32) bool {
+	return containsInt64(progress.ClaimedTargets, int64(target))
+}
+
+func (progress *ActivityEventProgress) HasClaimedTask(taskID uint32) bool {
+	return containsInt64(progress.ClaimedTasks, int64(taskID))
+}
+
+func containsInt64(list Int64List, value int64) bool {
+	for _, entry := range list {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
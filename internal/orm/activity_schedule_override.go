@@ -0,0 +1,75 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	// ActivityOverrideExtend keeps an activity open until EndsAt.
+	ActivityOverrideExtend = "extend"
+	// ActivityOverridePreview opens an activity between StartsAt and EndsAt,
+	// whatever its template says.
+	ActivityOverridePreview = "preview"
+	// ActivityOverrideForceEnd closes an activity at EndsAt, or right away.
+	ActivityOverrideForceEnd = "force_end"
+)
+
+// ActivityScheduleOverride is an admin adjustment of the time window of an
+// activity. An empty Region applies to every region.
+type ActivityScheduleOverride struct {
+	ID         uint64
+	ActivityID uint32
+	Region     string
+	Action     string
+	StartsAt   *time.Time
+	EndsAt     *time.Time
+	Note       string
+	CreatedAt  time.Time
+}
+
+func CreateActivityScheduleOverride(override *ActivityScheduleOverride) error {
+	ctx := context.Background()
+	return db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO activity_schedule_overrides (activity_id, region, action, starts_at, ends_at, note)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`, int64(override.ActivityID), override.Region, override.Action, override.StartsAt, override.EndsAt, override.Note).Scan(&override.ID, &override.CreatedAt)
+}
+
+// ListActivityScheduleOverrides returns every override, oldest first.
+func ListActivityScheduleOverrides() ([]ActivityScheduleOverride, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT id, activity_id, region, action, starts_at, ends_at, note, created_at
+FROM activity_schedule_overrides
+ORDER BY id ASC
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	overrides := []ActivityScheduleOverride{}
+	for rows.Next() {
+		var override ActivityScheduleOverride
+		if err := rows.Scan(&override.ID, &override.ActivityID, &override.Region, &override.Action, &override.StartsAt, &override.EndsAt, &override.Note, &override.CreatedAt); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, rows.Err()
+}
+
+func DeleteActivityScheduleOverride(id uint64) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM activity_schedule_overrides WHERE id = $1`, int64(id))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
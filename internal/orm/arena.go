@@ -0,0 +1,338 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// ArenaSeason is an exercise season. The open season is the latest one
+// without a SettledAt.
+type ArenaSeason struct {
+	SeasonID  uint32
+	StartsAt  time.Time
+	EndsAt    time.Time
+	SettledAt *time.Time
+}
+
+func (ArenaSeason) TableName() string {
+	return "arena_seasons"
+}
+
+// ArenaState is the exercise state of a commander in a season.
+// FightCountResetTime is the unix time of the next fight count regeneration,
+// RivalIDs the commanders currently offered as rivals.
+type ArenaState struct {
+	CommanderID         uint32
+	SeasonID            uint32
+	Score               uint32
+	FightCount          uint32
+	FightCountResetTime uint32
+	FlashTargetCount    uint32
+	RivalIDs            Int64List
+	Wins                uint32
+	Losses              uint32
+	UpdatedAt           time.Time
+}
+
+func (ArenaState) TableName() string {
+	return "arena_states"
+}
+
+// ArenaRivalCandidate is a commander with a saved exercise fleet that can be
+// offered as a rival.
+type ArenaRivalCandidate struct {
+	CommanderID uint32
+	Score       uint32
+}
+
+// ArenaLeaderboardEntry is a row of the exercise leaderboard of a season.
+type ArenaLeaderboardEntry struct {
+	Rank                uint32
+	CommanderID         uint32
+	Name                string
+	Level               int
+	Score               uint32
+	DisplayIconID       uint32
+	DisplaySkinID       uint32
+	SelectedIconFrameID uint32
+	SelectedChatFrameID uint32
+	DisplayIconThemeID  uint32
+}
+
+const arenaSeasonColumns = `season_id, starts_at, ends_at, settled_at`
+
+const arenaStateColumns = `commander_id, season_id, score, fight_count, fight_count_reset_time, flash_target_count, rival_ids, wins, losses, updated_at`
+
+func scanArenaSeason(scanner rowScanner) (*ArenaSeason, error) {
+	season := ArenaSeason{}
+	if err := scanner.Scan(&season.SeasonID, &season.StartsAt, &season.EndsAt, &season.SettledAt); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+func scanArenaState(scanner rowScanner) (*ArenaState, error) {
+	state := ArenaState{}
+	err := scanner.Scan(
+		&state.CommanderID,
+		&state.SeasonID,
+		&state.Score,
+		&state.FightCount,
+		&state.FightCountResetTime,
+		&state.FlashTargetCount,
+		&state.RivalIDs,
+		&state.Wins,
+		&state.Losses,
+		&state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if state.RivalIDs == nil {
+		state.RivalIDs = Int64List{}
+	}
+	return &state, nil
+}
+
+// GetOpenArenaSeason returns the season in progress, or db.ErrNotFound when
+// none was opened yet.
+func GetOpenArenaSeason() (*ArenaSeason, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+arenaSeasonColumns+`
+FROM arena_seasons
+WHERE settled_at IS NULL
+ORDER BY season_id DESC
+LIMIT 1
+`)
+	season, err := scanArenaSeason(row)
+	return season, db.MapNotFound(err)
+}
+
+// GetOpenArenaSeasonTx is GetOpenArenaSeason, locking the season until tx
+// ends.
+func GetOpenArenaSeasonTx(ctx context.Context, tx pgx.Tx) (*ArenaSeason, error) {
+	row := tx.QueryRow(ctx, `
+SELECT `+arenaSeasonColumns+`
+FROM arena_seasons
+WHERE settled_at IS NULL
+ORDER BY season_id DESC
+LIMIT 1
+FOR UPDATE
+`)
+	season, err := scanArenaSeason(row)
+	return season, db.MapNotFound(err)
+}
+
+func CreateArenaSeasonTx(ctx context.Context, tx pgx.Tx, startsAt time.Time, endsAt time.Time) (*ArenaSeason, error) {
+	row := tx.QueryRow(ctx, `
+INSERT INTO arena_seasons (starts_at, ends_at)
+VALUES ($1, $2)
+RETURNING `+arenaSeasonColumns+`
+`, startsAt, endsAt)
+	return scanArenaSeason(row)
+}
+
+func SettleArenaSeasonTx(ctx context.Context, tx pgx.Tx, seasonID uint32, settledAt time.Time) error {
+	_, err := tx.Exec(ctx, `
+UPDATE arena_seasons
+SET settled_at = $2
+WHERE season_id = $1
+`, int64(seasonID), settledAt)
+	return err
+}
+
+func GetArenaState(commanderID uint32) (*ArenaState, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+arenaStateColumns+`
+FROM arena_states
+WHERE commander_id = $1
+`, int64(commanderID))
+	state, err := scanArenaState(row)
+	return state, db.MapNotFound(err)
+}
+
+// GetArenaStateTx is GetArenaState, locking the row until tx ends.
+func GetArenaStateTx(ctx context.Context, tx pgx.Tx, commanderID uint32) (*ArenaState, error) {
+	row := tx.QueryRow(ctx, `
+SELECT `+arenaStateColumns+`
+FROM arena_states
+WHERE commander_id = $1
+FOR UPDATE
+`, int64(commanderID))
+	state, err := scanArenaState(row)
+	return state, db.MapNotFound(err)
+}
+
+func SaveArenaStateTx(ctx context.Context, tx pgx.Tx, state *ArenaState) error {
+	if state.RivalIDs == nil {
+		state.RivalIDs = Int64List{}
+	}
+	return tx.QueryRow(ctx, `
+INSERT INTO arena_states (commander_id, season_id, score, fight_count, fight_count_reset_time, flash_target_count, rival_ids, wins, losses, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  season_id = EXCLUDED.season_id,
+  score = EXCLUDED.score,
+  fight_count = EXCLUDED.fight_count,
+  fight_count_reset_time = EXCLUDED.fight_count_reset_time,
+  flash_target_count = EXCLUDED.flash_target_count,
+  rival_ids = EXCLUDED.rival_ids,
+  wins = EXCLUDED.wins,
+  losses = EXCLUDED.losses,
+  updated_at = NOW()
+RETURNING updated_at
+`, int64(state.CommanderID), int64(state.SeasonID), int64(state.Score), int64(state.FightCount), int64(state.FightCountResetTime), int64(state.FlashTargetCount), state.RivalIDs, int64(state.Wins), int64(state.Losses)).Scan(&state.UpdatedAt)
+}
+
+func SaveArenaState(state *ArenaState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveArenaStateTx(ctx, tx, state)
+	})
+}
+
+// ListArenaStatesTx returns the states of a season, best score first, locking
+// them until tx ends.
+func ListArenaStatesTx(ctx context.Context, tx pgx.Tx, seasonID uint32) ([]ArenaState, error) {
+	rows, err := tx.Query(ctx, `
+SELECT `+arenaStateColumns+`
+FROM arena_states
+WHERE season_id = $1
+ORDER BY score DESC, commander_id ASC
+FOR UPDATE
+`, int64(seasonID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	states := []ArenaState{}
+	for rows.Next() {
+		state, err := scanArenaState(rows)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, *state)
+	}
+	return states, rows.Err()
+}
+
+// ResetArenaStatesTx moves every state to a new season, clearing scores and
+// rivals. Fight counts are kept.
+func ResetArenaStatesTx(ctx context.Context, tx pgx.Tx, seasonID uint32, score uint32) error {
+	_, err := tx.Exec(ctx, `
+UPDATE arena_states
+SET season_id = $1,
+    score = $2,
+    flash_target_count = 0,
+    rival_ids = '[]'::jsonb,
+    wins = 0,
+    losses = 0,
+    updated_at = NOW()
+`, int64(seasonID), int64(score))
+	return err
+}
+
+// ListArenaRivalCandidates returns up to limit random commanders with a saved
+// exercise fleet whose score in the season is within [minScore, maxScore].
+// Commanders without a state in the season count as defaultScore.
+func ListArenaRivalCandidates(commanderID uint32, seasonID uint32, minScore uint32, maxScore uint32, defaultScore uint32, limit int) ([]ArenaRivalCandidate, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT f.commander_id, COALESCE(s.score, $5) AS score
+FROM exercise_fleets f
+LEFT JOIN arena_states s ON s.commander_id = f.commander_id AND s.season_id = $2
+WHERE f.commander_id <> $1
+  AND jsonb_array_length(f.vanguard_ship_ids) > 0
+  AND jsonb_array_length(f.main_ship_ids) > 0
+  AND COALESCE(s.score, $5) BETWEEN $3 AND $4
+ORDER BY random()
+LIMIT $6
+`, int64(commanderID), int64(seasonID), int64(minScore), int64(maxScore), int64(defaultScore), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	candidates := []ArenaRivalCandidate{}
+	for rows.Next() {
+		var candidate ArenaRivalCandidate
+		if err := rows.Scan(&candidate.CommanderID, &candidate.Score); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
+
+// GetArenaRank returns the leaderboard position a score would have in a
+// season; ties are broken by commander id.
+func GetArenaRank(seasonID uint32, commanderID uint32, score uint32) (uint32, error) {
+	ctx := context.Background()
+	var ahead int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM arena_states
+WHERE season_id = $1
+  AND commander_id <> $2
+  AND (score > $3 OR (score = $3 AND commander_id < $2))
+`, int64(seasonID), int64(commanderID), int64(score)).Scan(&ahead)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(ahead) + 1, nil
+}
+
+// ListArenaLeaderboard returns a page of the leaderboard of a season.
+func ListArenaLeaderboard(seasonID uint32, offset int, limit int) ([]ArenaLeaderboardEntry, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT s.commander_id, c.name, c.level, s.score, c.display_icon_id, c.display_skin_id, c.selected_icon_frame_id, c.selected_chat_frame_id, c.display_icon_theme_id
+FROM arena_states s
+JOIN commanders c ON c.commander_id = s.commander_id
+WHERE s.season_id = $1
+ORDER BY s.score DESC, s.commander_id ASC
+OFFSET $2
+LIMIT $3
+`, int64(seasonID), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := []ArenaLeaderboardEntry{}
+	for rows.Next() {
+		entry := ArenaLeaderboardEntry{Rank: uint32(offset + len(entries) + 1)}
+		err := rows.Scan(
+			&entry.CommanderID,
+			&entry.Name,
+			&entry.Level,
+			&entry.Score,
+			&entry.DisplayIconID,
+			&entry.DisplaySkinID,
+			&entry.SelectedIconFrameID,
+			&entry.SelectedChatFrameID,
+			&entry.DisplayIconThemeID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func CountArenaStates(seasonID uint32) (int64, error) {
+	ctx := context.Background()
+	var total int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM arena_states
+WHERE season_id = $1
+`, int64(seasonID)).Scan(&total)
+	return total, err
+}
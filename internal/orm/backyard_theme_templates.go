@@ -113,9 +113,12 @@ type BackyardPublishedThemeVersion struct {
 	ImageMd5         string          `json:"image_md5"`
 	LikeCount        uint32          `json:"like_count"`
 	FavCount         uint32          `json:"fav_count"`
+	// PreviewsVerified is set when the previews were found in the object
+	// store when the version was published.
+	PreviewsVerified bool `json:"previews_verified"`
 }
 
-func CreateBackyardPublishedThemeVersionTx(ctx context.Context, tx pgx.Tx, commanderID uint32, pos uint32, name string, furniturePutList json.RawMessage, iconMd5, imageMd5 string) (BackyardPublishedThemeVersion, error) {
+func CreateBackyardPublishedThemeVersionTx(ctx context.Context, tx pgx.Tx, commanderID uint32, pos uint32, name string, furniturePutList json.RawMessage, iconMd5, imageMd5 string, previewsVerified bool) (BackyardPublishedThemeVersion, error) {
 	uploadTime := uint32(time.Now().Unix())
 	entry := BackyardPublishedThemeVersion{
 		ThemeID:          BackyardThemeID(commanderID, pos),
@@ -128,6 +131,7 @@ func CreateBackyardPublishedThemeVersionTx(ctx context.Context, tx pgx.Tx, comma
 		ImageMd5:         imageMd5,
 		LikeCount:        0,
 		FavCount:         0,
+		PreviewsVerified: previewsVerified,
 	}
 	_, err := tx.Exec(ctx, `
 INSERT INTO backyard_published_theme_versions (
@@ -140,11 +144,12 @@ INSERT INTO backyard_published_theme_versions (
   icon_image_md5,
   image_md5,
   like_count,
-  fav_count
+  fav_count,
+  previews_verified
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, 0, 0
+  $1, $2, $3, $4, $5, $6, $7, $8, 0, 0, $9
 )
-`, entry.ThemeID, int64(entry.UploadTime), int64(entry.OwnerID), int64(entry.Pos), entry.Name, entry.FurniturePutList, entry.IconImageMd5, entry.ImageMd5)
+`, entry.ThemeID, int64(entry.UploadTime), int64(entry.OwnerID), int64(entry.Pos), entry.Name, entry.FurniturePutList, entry.IconImageMd5, entry.ImageMd5, entry.PreviewsVerified)
 	if err != nil {
 		return BackyardPublishedThemeVersion{}, err
 	}
@@ -162,7 +167,7 @@ func LatestBackyardPublishedThemeVersion(themeID string) (*BackyardPublishedThem
 	}
 	ctx := context.Background()
 	row := db.DefaultStore.Pool.QueryRow(ctx, `
-SELECT theme_id, upload_time, owner_id, pos, name, furniture_put_list, icon_image_md5, image_md5, like_count, fav_count
+SELECT theme_id, upload_time, owner_id, pos, name, furniture_put_list, icon_image_md5, image_md5, like_count, fav_count, previews_verified
 FROM backyard_published_theme_versions
 WHERE theme_id = $1
 ORDER BY upload_time DESC
@@ -170,7 +175,7 @@ LIMIT 1
 `, themeID)
 	var entry BackyardPublishedThemeVersion
 	var uploadTime int64
-	if err := row.Scan(&entry.ThemeID, &uploadTime, &entry.OwnerID, &entry.Pos, &entry.Name, &entry.FurniturePutList, &entry.IconImageMd5, &entry.ImageMd5, &entry.LikeCount, &entry.FavCount); err != nil {
+	if err := row.Scan(&entry.ThemeID, &uploadTime, &entry.OwnerID, &entry.Pos, &entry.Name, &entry.FurniturePutList, &entry.IconImageMd5, &entry.ImageMd5, &entry.LikeCount, &entry.FavCount, &entry.PreviewsVerified); err != nil {
 		return nil, err
 	}
 	entry.UploadTime = uint32(uploadTime)
@@ -282,3 +287,45 @@ type BackyardThemeInform struct {
 	Reason     uint32 `json:"reason"`
 	CreatedAt  uint32 `json:"created_at"`
 }
+
+// ListBackyardPublishedPreviewMd5s returns the preview md5s still referenced
+// by the published theme versions of a commander.
+func ListBackyardPublishedPreviewMd5s(ownerID uint32) (map[string]struct{}, error) {
+	return queryBackyardPreviewMd5s(`
+SELECT icon_image_md5, image_md5
+FROM backyard_published_theme_versions
+WHERE owner_id = $1
+`, int64(ownerID))
+}
+
+// ListBackyardThemePreviewMd5s returns the preview md5s of every published
+// version of a theme.
+func ListBackyardThemePreviewMd5s(themeID string) (map[string]struct{}, error) {
+	return queryBackyardPreviewMd5s(`
+SELECT icon_image_md5, image_md5
+FROM backyard_published_theme_versions
+WHERE theme_id = $1
+`, themeID)
+}
+
+func queryBackyardPreviewMd5s(query string, arg any) (map[string]struct{}, error) {
+	if db.DefaultStore == nil {
+		return nil, errors.New("db not initialized")
+	}
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	md5s := make(map[string]struct{})
+	for rows.Next() {
+		var iconMd5, imageMd5 string
+		if err := rows.Scan(&iconMd5, &imageMd5); err != nil {
+			return nil, err
+		}
+		md5s[iconMd5] = struct{}{}
+		md5s[imageMd5] = struct{}{}
+	}
+	return md5s, rows.Err()
+}
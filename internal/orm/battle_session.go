@@ -69,6 +69,7 @@ DO UPDATE SET
   stage_id = EXCLUDED.stage_id,
   key = EXCLUDED.key,
   ship_ids = EXCLUDED.ship_ids,
+  created_at = EXCLUDED.created_at,
   updated_at = EXCLUDED.updated_at
 `, int64(session.CommanderID), int64(session.System), int64(session.StageID), int64(session.Key), shipIDsRaw, session.CreatedAt, session.UpdatedAt)
 	return err
@@ -146,7 +146,6 @@ func (b *Build) Consume(shipId uint32, commander *Commander) (*OwnedShip, error)
 	if err != nil {
 		return nil, err
 	}
-	commander.IncrementExchangeCount(uint32(len(commander.Builds)))
 	return ship, nil
 }
 
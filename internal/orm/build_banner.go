@@ -0,0 +1,556 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/region"
+)
+
+// BuildBannerRateScale is the denominator of BuildBannerRateUp.Rate, a rate
+// of 200 being a 2% chance per roll.
+const BuildBannerRateScale = uint32(10000)
+
+const (
+	buildRarityUltraRare = uint32(6)
+	maxExchangeCount     = uint32(400)
+)
+
+var (
+	ErrBuildBannerUnknownShip   = errors.New("rate-up ship does not exist")
+	ErrBuildBannerMissingRarity = errors.New("weighted rarity has no ship in the pool")
+)
+
+// BuildBanner overrides the rates of a build pool while it is active. A
+// banner without region applies to every region, but a banner of the server
+// region takes precedence over it.
+//
+// Rate-up ships are rolled first, each with Rate chances out of
+// BuildBannerRateScale, then the rarity weights pick a ship of the pool. When
+// PityThreshold is set, the roll bringing the commander's build points
+// (ExchangeCount) to the threshold spends them on a guaranteed Ultra Rare.
+type BuildBanner struct {
+	ID              uint32
+	PoolID          uint32
+	Name            string
+	Region          *string
+	CommonWeight    uint32
+	RareWeight      uint32
+	EliteWeight     uint32
+	SuperRareWeight uint32
+	UltraRareWeight uint32
+	PityThreshold   uint32
+	StartsAt        *time.Time
+	EndsAt          *time.Time
+	Enabled         bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	RateUps         []BuildBannerRateUp
+}
+
+type BuildBannerRateUp struct {
+	ShipID   uint32
+	Rate     uint32
+	RarityID uint32
+}
+
+// BuildRollLog records the outcome of a single build, for rate audits.
+type BuildRollLog struct {
+	ID          uint32
+	CommanderID uint32
+	BannerID    *uint32
+	PoolID      uint32
+	ShipID      uint32
+	RarityID    uint32
+	RateUp      bool
+	Pity        bool
+	CreatedAt   time.Time
+}
+
+// BuildRollStats aggregates the rolls made on a banner.
+type BuildRollStats struct {
+	Total    uint32
+	Pity     uint32
+	Rarities map[uint32]uint32
+	RateUps  map[uint32]uint32
+}
+
+// BuildRoll is the ship picked for a new build.
+type BuildRoll struct {
+	Ship     Ship
+	BannerID *uint32
+	RateUp   bool
+	Pity     bool
+}
+
+const buildBannerColumns = `id, pool_id, name, region, common_weight, rare_weight, elite_weight, super_rare_weight, ultra_rare_weight, pity_threshold, starts_at, ends_at, enabled, created_at, updated_at`
+
+func (b *BuildBanner) rarityWeights() []buildRarityWeight {
+	return []buildRarityWeight{
+		{RarityID: buildRarityUltraRare, Weight: b.UltraRareWeight},
+		{RarityID: 5, Weight: b.SuperRareWeight},
+		{RarityID: 4, Weight: b.EliteWeight},
+		{RarityID: 3, Weight: b.RareWeight},
+		{RarityID: 2, Weight: b.CommonWeight},
+	}
+}
+
+func scanBuildBanner(scanner rowScanner) (BuildBanner, error) {
+	var banner BuildBanner
+	err := scanner.Scan(
+		&banner.ID,
+		&banner.PoolID,
+		&banner.Name,
+		&banner.Region,
+		&banner.CommonWeight,
+		&banner.RareWeight,
+		&banner.EliteWeight,
+		&banner.SuperRareWeight,
+		&banner.UltraRareWeight,
+		&banner.PityThreshold,
+		&banner.StartsAt,
+		&banner.EndsAt,
+		&banner.Enabled,
+		&banner.CreatedAt,
+		&banner.UpdatedAt,
+	)
+	return banner, err
+}
+
+func loadBuildBannerRateUps(ctx context.Context, banners []BuildBanner) error {
+	if len(banners) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(banners))
+	index := make(map[uint32]int, len(banners))
+	for i := range banners {
+		banners[i].RateUps = []BuildBannerRateUp{}
+		ids = append(ids, int64(banners[i].ID))
+		index[banners[i].ID] = i
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT r.banner_id, r.ship_id, r.rate, s.rarity_id
+FROM build_banner_rate_ups r
+JOIN ships s ON s.template_id = r.ship_id
+WHERE r.banner_id = ANY($1)
+ORDER BY r.banner_id ASC, r.ship_id ASC
+`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var bannerID uint32
+		var rateUp BuildBannerRateUp
+		if err := rows.Scan(&bannerID, &rateUp.ShipID, &rateUp.Rate, &rateUp.RarityID); err != nil {
+			return err
+		}
+		banner := &banners[index[bannerID]]
+		banner.RateUps = append(banner.RateUps, rateUp)
+	}
+	return rows.Err()
+}
+
+func insertBuildBannerRateUpsTx(ctx context.Context, tx pgx.Tx, banner *BuildBanner) error {
+	for _, rateUp := range banner.RateUps {
+		_, err := tx.Exec(ctx, `
+INSERT INTO build_banner_rate_ups (banner_id, ship_id, rate)
+VALUES ($1, $2, $3)
+ON CONFLICT (banner_id, ship_id) DO UPDATE SET rate = EXCLUDED.rate
+`, int64(banner.ID), int64(rateUp.ShipID), int64(rateUp.Rate))
+		if err != nil {
+			return mapBuildBannerForeignKey(err)
+		}
+	}
+	return nil
+}
+
+// checkBuildBannerPoolTx refuses banners weighting a rarity their pool has
+// no ship of, as the builds landing on it could not pick a ship.
+func checkBuildBannerPoolTx(ctx context.Context, tx pgx.Tx, banner *BuildBanner) error {
+	rows, err := tx.Query(ctx, `SELECT DISTINCT rarity_id FROM ships WHERE pool_id = $1`, int64(banner.PoolID))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	rarities := make(map[uint32]struct{})
+	for rows.Next() {
+		var rarityID uint32
+		if err := rows.Scan(&rarityID); err != nil {
+			return err
+		}
+		rarities[rarityID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, weight := range banner.rarityWeights() {
+		if weight.Weight == 0 {
+			continue
+		}
+		if _, ok := rarities[weight.RarityID]; !ok {
+			return fmt.Errorf("%w: rarity %d, pool %d", ErrBuildBannerMissingRarity, weight.RarityID, banner.PoolID)
+		}
+	}
+	return nil
+}
+
+func mapBuildBannerForeignKey(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrBuildBannerUnknownShip
+	}
+	return err
+}
+
+func CreateBuildBanner(banner *BuildBanner) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := checkBuildBannerPoolTx(ctx, tx, banner); err != nil {
+			return err
+		}
+		err := tx.QueryRow(ctx, `
+INSERT INTO build_banners (pool_id, name, region, common_weight, rare_weight, elite_weight, super_rare_weight, ultra_rare_weight, pity_threshold, starts_at, ends_at, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, created_at, updated_at
+`, int64(banner.PoolID), banner.Name, banner.Region, int64(banner.CommonWeight), int64(banner.RareWeight), int64(banner.EliteWeight), int64(banner.SuperRareWeight), int64(banner.UltraRareWeight), int64(banner.PityThreshold), banner.StartsAt, banner.EndsAt, banner.Enabled).Scan(&banner.ID, &banner.CreatedAt, &banner.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return insertBuildBannerRateUpsTx(ctx, tx, banner)
+	})
+}
+
+// UpdateBuildBanner saves banner and replaces its rate-up ships.
+func UpdateBuildBanner(banner *BuildBanner) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := checkBuildBannerPoolTx(ctx, tx, banner); err != nil {
+			return err
+		}
+		err := tx.QueryRow(ctx, `
+UPDATE build_banners
+SET pool_id = $2,
+	name = $3,
+	region = $4,
+	common_weight = $5,
+	rare_weight = $6,
+	elite_weight = $7,
+	super_rare_weight = $8,
+	ultra_rare_weight = $9,
+	pity_threshold = $10,
+	starts_at = $11,
+	ends_at = $12,
+	enabled = $13,
+	updated_at = NOW()
+WHERE id = $1
+RETURNING updated_at
+`, int64(banner.ID), int64(banner.PoolID), banner.Name, banner.Region, int64(banner.CommonWeight), int64(banner.RareWeight), int64(banner.EliteWeight), int64(banner.SuperRareWeight), int64(banner.UltraRareWeight), int64(banner.PityThreshold), banner.StartsAt, banner.EndsAt, banner.Enabled).Scan(&banner.UpdatedAt)
+		if err := db.MapNotFound(err); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM build_banner_rate_ups WHERE banner_id = $1`, int64(banner.ID)); err != nil {
+			return err
+		}
+		return insertBuildBannerRateUpsTx(ctx, tx, banner)
+	})
+}
+
+func GetBuildBanner(bannerID uint32) (*BuildBanner, error) {
+	ctx := context.Background()
+	banner, err := scanBuildBanner(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+buildBannerColumns+`
+FROM build_banners
+WHERE id = $1
+`, int64(bannerID)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	banners := []BuildBanner{banner}
+	if err := loadBuildBannerRateUps(ctx, banners); err != nil {
+		return nil, err
+	}
+	return &banners[0], nil
+}
+
+func ListBuildBanners(offset int, limit int) ([]BuildBanner, int64, error) {
+	ctx := context.Background()
+	offset, limit, unlimited := normalizePagination(offset, limit)
+
+	var total int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM build_banners`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	query := `
+SELECT ` + buildBannerColumns + `
+FROM build_banners
+ORDER BY id ASC
+OFFSET $1
+`
+	args := []any{int64(offset)}
+	if !unlimited {
+		query += `LIMIT $2`
+		args = append(args, int64(limit))
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	banners := make([]BuildBanner, 0)
+	for rows.Next() {
+		banner, err := scanBuildBanner(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		banners = append(banners, banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+	if err := loadBuildBannerRateUps(ctx, banners); err != nil {
+		return nil, 0, err
+	}
+	return banners, total, nil
+}
+
+func DeleteBuildBanner(bannerID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM build_banners WHERE id = $1`, int64(bannerID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// GetActiveBuildBanner returns the enabled banner of poolID running at now,
+// preferring banners of regionName over region-less ones and the most
+// recently started one otherwise. It returns db.ErrNotFound when the pool
+// uses the standard rates.
+func GetActiveBuildBanner(poolID uint32, regionName string, now time.Time) (*BuildBanner, error) {
+	ctx := context.Background()
+	banner, err := scanBuildBanner(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+buildBannerColumns+`
+FROM build_banners
+WHERE pool_id = $1
+  AND enabled
+  AND (region IS NULL OR region = $2)
+  AND (starts_at IS NULL OR starts_at <= $3)
+  AND (ends_at IS NULL OR ends_at > $3)
+ORDER BY region IS NULL ASC, starts_at DESC NULLS LAST, id DESC
+LIMIT 1
+`, int64(poolID), regionName, now))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	banners := []BuildBanner{banner}
+	if err := loadBuildBannerRateUps(ctx, banners); err != nil {
+		return nil, err
+	}
+	return &banners[0], nil
+}
+
+// RollBuildShip picks the ship of a new build in poolID with the banner
+// active at now, if any, and records the roll. Every roll earns one build
+// point, up to the exchange cap.
+func (c *Commander) RollBuildShip(poolID uint32, now time.Time) (*BuildRoll, error) {
+	banner, err := GetActiveBuildBanner(poolID, region.Current(), now)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	points := c.ExchangeCount + 1
+	if points > maxExchangeCount {
+		points = maxExchangeCount
+	}
+	var roll *BuildRoll
+	if err == nil {
+		roll, err = rollBannerShip(banner, poolID, points)
+	} else {
+		var ship Ship
+		ship, err = GetRandomPoolShip(poolID)
+		roll = &BuildRoll{Ship: ship}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if roll.Pity {
+		points -= banner.PityThreshold
+	}
+	ctx := context.Background()
+	err = WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE commanders SET exchange_count = $2 WHERE commander_id = $1`, int64(c.CommanderID), int64(points)); err != nil {
+			return err
+		}
+		var bannerID *int64
+		if roll.BannerID != nil {
+			id := int64(*roll.BannerID)
+			bannerID = &id
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO build_roll_logs (commander_id, banner_id, pool_id, ship_id, rarity_id, rate_up, pity, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`, int64(c.CommanderID), bannerID, int64(poolID), int64(roll.Ship.TemplateID), int64(roll.Ship.RarityID), roll.RateUp, roll.Pity, now.UTC())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.ExchangeCount = points
+	return roll, nil
+}
+
+// rollBannerShip rolls a ship on banner, forcing an Ultra Rare once points
+// reach the pity threshold. Pity is not spent when neither the rate-ups nor
+// the pool have an Ultra Rare.
+func rollBannerShip(banner *BuildBanner, poolID uint32, points uint32) (*BuildRoll, error) {
+	roll := &BuildRoll{BannerID: &banner.ID}
+	if banner.PityThreshold > 0 && points >= banner.PityThreshold {
+		urRateUps := make([]BuildBannerRateUp, 0, len(banner.RateUps))
+		for _, rateUp := range banner.RateUps {
+			if rateUp.RarityID == buildRarityUltraRare {
+				urRateUps = append(urRateUps, rateUp)
+			}
+		}
+		if len(urRateUps) > 0 {
+			roll.Ship = Ship{TemplateID: pickWeightedRateUp(urRateUps)}
+			if err := roll.Ship.Retrieve(false); err != nil {
+				return nil, err
+			}
+			roll.Pity = true
+			roll.RateUp = true
+			return roll, nil
+		}
+		ship, err := getRandomPoolShipByRarity(poolID, buildRarityUltraRare)
+		if err == nil {
+			roll.Pity = true
+			roll.Ship = ship
+			return roll, nil
+		}
+		if !db.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	randomN := shipRng.Uint32N(BuildBannerRateScale)
+	for _, rateUp := range banner.RateUps {
+		if randomN < rateUp.Rate {
+			roll.Ship = Ship{TemplateID: rateUp.ShipID}
+			if err := roll.Ship.Retrieve(false); err != nil {
+				return nil, err
+			}
+			roll.RateUp = true
+			return roll, nil
+		}
+		randomN -= rateUp.Rate
+	}
+	ship, err := getRandomPoolShipByRarity(poolID, rollBuildRarity(banner.rarityWeights()))
+	if err != nil {
+		return nil, err
+	}
+	roll.Ship = ship
+	return roll, nil
+}
+
+func pickWeightedRateUp(rateUps []BuildBannerRateUp) uint32 {
+	total := uint32(0)
+	for _, rateUp := range rateUps {
+		total += rateUp.Rate
+	}
+	if total == 0 {
+		return rateUps[shipRng.Uint32N(uint32(len(rateUps)))].ShipID
+	}
+	randomN := shipRng.Uint32N(total)
+	for _, rateUp := range rateUps {
+		if randomN < rateUp.Rate {
+			return rateUp.ShipID
+		}
+		randomN -= rateUp.Rate
+	}
+	return rateUps[len(rateUps)-1].ShipID
+}
+
+func ListBuildRollLogs(bannerID uint32, offset int, limit int) ([]BuildRollLog, int64, error) {
+	ctx := context.Background()
+	offset, limit, unlimited := normalizePagination(offset, limit)
+
+	var total int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM build_roll_logs WHERE banner_id = $1`, int64(bannerID)).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	query := `
+SELECT id, commander_id, banner_id, pool_id, ship_id, rarity_id, rate_up, pity, created_at
+FROM build_roll_logs
+WHERE banner_id = $1
+ORDER BY id DESC
+OFFSET $2
+`
+	args := []any{int64(bannerID), int64(offset)}
+	if !unlimited {
+		query += `LIMIT $3`
+		args = append(args, int64(limit))
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	logs := make([]BuildRollLog, 0)
+	for rows.Next() {
+		var entry BuildRollLog
+		if err := rows.Scan(&entry.ID, &entry.CommanderID, &entry.BannerID, &entry.PoolID, &entry.ShipID, &entry.RarityID, &entry.RateUp, &entry.Pity, &entry.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// GetBuildRollStats counts the rolls made on a banner by rarity and by
+// rate-up ship.
+func GetBuildRollStats(bannerID uint32) (*BuildRollStats, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT rarity_id, ship_id, rate_up, pity, COUNT(*)
+FROM build_roll_logs
+WHERE banner_id = $1
+GROUP BY rarity_id, ship_id, rate_up, pity
+`, int64(bannerID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := BuildRollStats{Rarities: make(map[uint32]uint32), RateUps: make(map[uint32]uint32)}
+	for rows.Next() {
+		var rarityID, shipID, count uint32
+		var rateUp, pity bool
+		if err := rows.Scan(&rarityID, &shipID, &rateUp, &pity, &count); err != nil {
+			return nil, err
+		}
+		stats.Total += count
+		stats.Rarities[rarityID] += count
+		if rateUp {
+			stats.RateUps[shipID] += count
+		}
+		if pity {
+			stats.Pity += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,124 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/rng"
+)
+
+func TestGetActiveBuildBannerPrefersRegion(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearTable(t, &BuildBanner{})
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-48 * time.Hour)
+	ended := now.Add(-24 * time.Hour)
+	jp := "JP"
+	banners := []BuildBanner{
+		{PoolID: 7, Name: "Global", Enabled: true},
+		{PoolID: 7, Name: "Japan", Region: &jp, Enabled: true},
+		{PoolID: 7, Name: "Ended", StartsAt: &past, EndsAt: &ended, Enabled: true},
+		{PoolID: 8, Name: "Disabled", Enabled: false},
+	}
+	for i := range banners {
+		if err := CreateBuildBanner(&banners[i]); err != nil {
+			t.Fatalf("create banner: %v", err)
+		}
+	}
+
+	active, err := GetActiveBuildBanner(7, "EN", now)
+	if err != nil {
+		t.Fatalf("get active banner: %v", err)
+	}
+	if active.ID != banners[0].ID {
+		t.Fatalf("expected global banner, got %s", active.Name)
+	}
+	active, err = GetActiveBuildBanner(7, "JP", now)
+	if err != nil {
+		t.Fatalf("get active banner: %v", err)
+	}
+	if active.ID != banners[1].ID {
+		t.Fatalf("expected region banner, got %s", active.Name)
+	}
+	if _, err := GetActiveBuildBanner(8, "EN", now); !db.IsNotFound(err) {
+		t.Fatalf("expected disabled banner to be ignored, got %v", err)
+	}
+}
+
+func TestRollBuildShipPityAndLogs(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearTable(t, &BuildBanner{})
+	clearTable(t, &Ship{})
+	seedFriendTestCommander(t, 9941, "Banner Roll")
+
+	originalRng := shipRng
+	shipRng = rng.NewLockedRandFromSeed(3)
+	defer func() { shipRng = originalRng }()
+
+	poolID := uint32(7)
+	for _, rarity := range []uint32{2, 3, 4, 5} {
+		ship := Ship{TemplateID: rarity + 9900, Name: "Ship", EnglishName: "Ship", RarityID: rarity, Star: 1, Type: 1, Nationality: 1, BuildTime: 10, PoolID: &poolID}
+		if err := ship.Create(); err != nil {
+			t.Fatalf("seed pool ship: %v", err)
+		}
+	}
+	limited := Ship{TemplateID: 9906, Name: "Limited", EnglishName: "Limited", RarityID: 6, Star: 1, Type: 1, Nationality: 1, BuildTime: 20}
+	if err := limited.Create(); err != nil {
+		t.Fatalf("seed rate-up ship: %v", err)
+	}
+	banner := BuildBanner{
+		PoolID:          poolID,
+		Name:            "Limited",
+		CommonWeight:    30,
+		RareWeight:      51,
+		EliteWeight:     12,
+		SuperRareWeight: 7,
+		PityThreshold:   2,
+		Enabled:         true,
+		RateUps:         []BuildBannerRateUp{{ShipID: 9906, Rate: 0}},
+	}
+	if err := CreateBuildBanner(&banner); err != nil {
+		t.Fatalf("create banner: %v", err)
+	}
+	if err := CreateBuildBanner(&BuildBanner{PoolID: poolID, Name: "Broken", RateUps: []BuildBannerRateUp{{ShipID: 1}}}); err != ErrBuildBannerUnknownShip {
+		t.Fatalf("expected unknown ship error, got %v", err)
+	}
+	if err := CreateBuildBanner(&BuildBanner{PoolID: poolID, Name: "No UR", UltraRareWeight: 1}); !errors.Is(err, ErrBuildBannerMissingRarity) {
+		t.Fatalf("expected missing rarity error, got %v", err)
+	}
+
+	commander := Commander{CommanderID: 9941}
+	now := time.Now()
+	first, err := commander.RollBuildShip(poolID, now)
+	if err != nil {
+		t.Fatalf("first roll: %v", err)
+	}
+	if first.Pity || first.RateUp || first.Ship.RarityID == 6 || commander.ExchangeCount != 1 {
+		t.Fatalf("unexpected first roll: %+v (points %d)", first, commander.ExchangeCount)
+	}
+	second, err := commander.RollBuildShip(poolID, now)
+	if err != nil {
+		t.Fatalf("second roll: %v", err)
+	}
+	if !second.Pity || second.Ship.TemplateID != 9906 || second.Ship.BuildTime != 20 || commander.ExchangeCount != 0 {
+		t.Fatalf("expected pity on rate-up ship: %+v (points %d)", second, commander.ExchangeCount)
+	}
+
+	stats, err := GetBuildRollStats(banner.ID)
+	if err != nil {
+		t.Fatalf("roll stats: %v", err)
+	}
+	if stats.Total != 2 || stats.Pity != 1 || stats.Rarities[6] != 1 || stats.RateUps[9906] != 1 {
+		t.Fatalf("unexpected roll stats: %+v", stats)
+	}
+	logs, total, err := ListBuildRollLogs(banner.ID, 0, 10)
+	if err != nil {
+		t.Fatalf("list roll logs: %v", err)
+	}
+	if total != 2 || len(logs) != 2 || logs[0].ShipID != 9906 || logs[0].CommanderID != 9941 {
+		t.Fatalf("unexpected roll logs: %+v", logs)
+	}
+}
@@ -0,0 +1,353 @@
+package orm
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+var ErrChallengeRunExists = errors.New("challenge run already started")
+
+// ChallengeShip is a ship of a challenge fleet; HP is its hp ratio in
+// 1/10000, carried over from one level to the next.
+type ChallengeShip struct {
+	ID uint32 `json:"id"`
+	HP uint32 `json:"hp"`
+}
+
+type ChallengeCommander struct {
+	Pos uint32 `json:"pos"`
+	ID  uint32 `json:"id"`
+}
+
+type ChallengeGroup struct {
+	ID         uint32               `json:"id"`
+	Ships      []ChallengeShip      `json:"ships"`
+	Commanders []ChallengeCommander `json:"commanders"`
+}
+
+type ChallengeGroups []ChallengeGroup
+
+func (groups ChallengeGroups) Value() (driver.Value, error) {
+	if groups == nil {
+		groups = ChallengeGroups{}
+	}
+	payload, err := json.Marshal(groups)
+	if err != nil {
+		return nil, err
+	}
+	return string(payload), nil
+}
+
+func (groups *ChallengeGroups) Scan(value any) error {
+	if value == nil {
+		*groups = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), groups)
+	case []byte:
+		return json.Unmarshal(v, groups)
+	default:
+		return fmt.Errorf("unsupported ChallengeGroups type: %T", value)
+	}
+}
+
+// ChallengeRun is a challenge in progress. Level is the 1-based level to
+// fight next; the dungeons and buffs are those of the season it started in.
+type ChallengeRun struct {
+	CommanderID uint32
+	ActivityID  uint32
+	Mode        uint32
+	SeasonID    uint32
+	Level       uint32
+	Score       uint32
+	DungeonIDs  Int64List
+	BuffIDs     Int64List
+	Groups      ChallengeGroups
+	StartedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (ChallengeRun) TableName() string {
+	return "challenge_runs"
+}
+
+// ChallengeScore is the best run of a commander in a season of a challenge
+// activity. MaxLevel counts the levels cleared.
+type ChallengeScore struct {
+	CommanderID uint32
+	ActivityID  uint32
+	SeasonID    uint32
+	MaxScore    uint32
+	MaxLevel    uint32
+	UpdatedAt   time.Time
+}
+
+func (ChallengeScore) TableName() string {
+	return "challenge_scores"
+}
+
+// ChallengeLeaderboardEntry is a row of the leaderboard of a challenge
+// activity, ranked by best score over its seasons.
+type ChallengeLeaderboardEntry struct {
+	Rank                uint32
+	CommanderID         uint32
+	Name                string
+	Level               int
+	Score               uint32
+	DisplayIconID       uint32
+	DisplaySkinID       uint32
+	SelectedIconFrameID uint32
+	SelectedChatFrameID uint32
+	DisplayIconThemeID  uint32
+}
+
+const challengeRunColumns = `commander_id, activity_id, mode, season_id, level, score, dungeon_ids, buff_ids, groups, started_at, updated_at`
+
+func scanChallengeRun(scanner rowScanner) (*ChallengeRun, error) {
+	run := ChallengeRun{}
+	err := scanner.Scan(
+		&run.CommanderID,
+		&run.ActivityID,
+		&run.Mode,
+		&run.SeasonID,
+		&run.Level,
+		&run.Score,
+		&run.DungeonIDs,
+		&run.BuffIDs,
+		&run.Groups,
+		&run.StartedAt,
+		&run.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if run.DungeonIDs == nil {
+		run.DungeonIDs = Int64List{}
+	}
+	if run.BuffIDs == nil {
+		run.BuffIDs = Int64List{}
+	}
+	if run.Groups == nil {
+		run.Groups = ChallengeGroups{}
+	}
+	return &run, nil
+}
+
+// ListChallengeRuns returns the runs in progress of a commander, in every
+// activity when activityID is 0.
+func ListChallengeRuns(commanderID uint32, activityID uint32) ([]ChallengeRun, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+challengeRunColumns+`
+FROM challenge_runs
+WHERE commander_id = $1
+  AND ($2 = 0 OR activity_id = $2)
+ORDER BY activity_id ASC, mode ASC
+`, int64(commanderID), int64(activityID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	runs := []ChallengeRun{}
+	for rows.Next() {
+		run, err := scanChallengeRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+// GetChallengeRunTx returns a run in progress, locking it until tx ends.
+func GetChallengeRunTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32, mode uint32) (*ChallengeRun, error) {
+	row := tx.QueryRow(ctx, `
+SELECT `+challengeRunColumns+`
+FROM challenge_runs
+WHERE commander_id = $1 AND activity_id = $2 AND mode = $3
+FOR UPDATE
+`, int64(commanderID), int64(activityID), int64(mode))
+	run, err := scanChallengeRun(row)
+	return run, db.MapNotFound(err)
+}
+
+// CreateChallengeRun starts a run, or returns ErrChallengeRunExists when one
+// is already in progress in the same mode.
+func CreateChallengeRun(run *ChallengeRun) error {
+	if run.DungeonIDs == nil {
+		run.DungeonIDs = Int64List{}
+	}
+	if run.BuffIDs == nil {
+		run.BuffIDs = Int64List{}
+	}
+	ctx := context.Background()
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO challenge_runs (commander_id, activity_id, mode, season_id, level, score, dungeon_ids, buff_ids, groups, started_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+RETURNING started_at, updated_at
+`, int64(run.CommanderID), int64(run.ActivityID), int64(run.Mode), int64(run.SeasonID), int64(run.Level), int64(run.Score), run.DungeonIDs, run.BuffIDs, run.Groups).Scan(&run.StartedAt, &run.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrChallengeRunExists
+	}
+	return err
+}
+
+func SaveChallengeRunTx(ctx context.Context, tx pgx.Tx, run *ChallengeRun) error {
+	return tx.QueryRow(ctx, `
+UPDATE challenge_runs
+SET level = $4, score = $5, groups = $6, updated_at = NOW()
+WHERE commander_id = $1 AND activity_id = $2 AND mode = $3
+RETURNING updated_at
+`, int64(run.CommanderID), int64(run.ActivityID), int64(run.Mode), int64(run.Level), int64(run.Score), run.Groups).Scan(&run.UpdatedAt)
+}
+
+// DeleteChallengeRun ends a run and reports whether there was one.
+func DeleteChallengeRun(commanderID uint32, activityID uint32, mode uint32) (bool, error) {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM challenge_runs
+WHERE commander_id = $1 AND activity_id = $2 AND mode = $3
+`, int64(commanderID), int64(activityID), int64(mode))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func DeleteChallengeRunTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32, mode uint32) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+DELETE FROM challenge_runs
+WHERE commander_id = $1 AND activity_id = $2 AND mode = $3
+`, int64(commanderID), int64(activityID), int64(mode))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecordChallengeScoreTx keeps the best score and level of a season.
+func RecordChallengeScoreTx(ctx context.Context, tx pgx.Tx, commanderID uint32, activityID uint32, seasonID uint32, score uint32, level uint32) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO challenge_scores (commander_id, activity_id, season_id, max_score, max_level, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (commander_id, activity_id, season_id)
+DO UPDATE SET
+  max_score = GREATEST(challenge_scores.max_score, EXCLUDED.max_score),
+  max_level = GREATEST(challenge_scores.max_level, EXCLUDED.max_level),
+  updated_at = NOW()
+`, int64(commanderID), int64(activityID), int64(seasonID), int64(score), int64(level))
+	return err
+}
+
+// ListChallengeScores returns the best runs of a commander in each season of
+// a challenge activity.
+func ListChallengeScores(commanderID uint32, activityID uint32) ([]ChallengeScore, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, activity_id, season_id, max_score, max_level, updated_at
+FROM challenge_scores
+WHERE commander_id = $1 AND activity_id = $2
+ORDER BY season_id ASC
+`, int64(commanderID), int64(activityID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	scores := []ChallengeScore{}
+	for rows.Next() {
+		var score ChallengeScore
+		if err := rows.Scan(&score.CommanderID, &score.ActivityID, &score.SeasonID, &score.MaxScore, &score.MaxLevel, &score.UpdatedAt); err != nil {
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+	return scores, rows.Err()
+}
+
+// ListChallengeLeaderboard returns a page of the leaderboard of a challenge
+// activity.
+func ListChallengeLeaderboard(activityID uint32, offset int, limit int) ([]ChallengeLeaderboardEntry, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT s.commander_id, c.name, c.level, s.score, c.display_icon_id, c.display_skin_id, c.selected_icon_frame_id, c.selected_chat_frame_id, c.display_icon_theme_id
+FROM (
+  SELECT commander_id, MAX(max_score) AS score
+  FROM challenge_scores
+  WHERE activity_id = $1
+  GROUP BY commander_id
+) s
+JOIN commanders c ON c.commander_id = s.commander_id
+WHERE s.score > 0
+ORDER BY s.score DESC, s.commander_id ASC
+OFFSET $2
+LIMIT $3
+`, int64(activityID), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := []ChallengeLeaderboardEntry{}
+	for rows.Next() {
+		entry := ChallengeLeaderboardEntry{Rank: uint32(offset + len(entries) + 1)}
+		err := rows.Scan(
+			&entry.CommanderID,
+			&entry.Name,
+			&entry.Level,
+			&entry.Score,
+			&entry.DisplayIconID,
+			&entry.DisplaySkinID,
+			&entry.SelectedIconFrameID,
+			&entry.SelectedChatFrameID,
+			&entry.DisplayIconThemeID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetChallengeRank returns the best score of a commander in a challenge
+// activity and its leaderboard position; ties are broken by commander id.
+// Both are 0 when the commander has no score.
+func GetChallengeRank(activityID uint32, commanderID uint32) (uint32, uint32, error) {
+	ctx := context.Background()
+	var score int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COALESCE(MAX(max_score), 0)
+FROM challenge_scores
+WHERE activity_id = $1 AND commander_id = $2
+`, int64(activityID), int64(commanderID)).Scan(&score)
+	if err != nil || score == 0 {
+		return 0, 0, err
+	}
+	var ahead int64
+	err = db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM (
+  SELECT commander_id, MAX(max_score) AS score
+  FROM challenge_scores
+  WHERE activity_id = $1
+  GROUP BY commander_id
+) s
+WHERE s.commander_id <> $2
+  AND (s.score > $3 OR (s.score = $3 AND s.commander_id < $2))
+`, int64(activityID), int64(commanderID), score).Scan(&ahead)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(score), uint32(ahead) + 1, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"github.com/ggmolly/belfast/internal/db"
 	"github.com/ggmolly/belfast/internal/db/gen"
 )
@@ -14,6 +16,28 @@ const (
 	MSG_TYPE_NORMAL  = 1
 )
 
+// MessageSearch filters SearchMessages; nil and zero values match
+// everything. Content matches messages containing it, case-insensitively.
+type MessageSearch struct {
+	RoomID   *uint32
+	SenderID uint32
+	Content  string
+	Offset   int
+	Limit    int
+}
+
+// ChatMute keeps a commander from posting in public chat until MutedUntil.
+type ChatMute struct {
+	CommanderID uint32
+	Reason      string
+	MutedUntil  time.Time
+	CreatedAt   time.Time
+}
+
+func (ChatMute) TableName() string {
+	return "chat_mutes"
+}
+
 type Message struct {
 	ID       uint32    `gorm:"primary_key"`
 	SenderID uint32    `gorm:"not_null"`
@@ -24,6 +48,34 @@ type Message struct {
 	Sender Commander `gorm:"foreignkey:SenderID;references:CommanderID"`
 }
 
+func (Message) TableName() string {
+	return "messages"
+}
+
+const messageColumns = `m.id, m.sender_id, m.room_id, m.sent_at, m.content, c.name, c.level`
+
+func scanMessages(rows pgx.Rows) ([]Message, error) {
+	messages := []Message{}
+	for rows.Next() {
+		var message Message
+		err := rows.Scan(
+			&message.ID,
+			&message.SenderID,
+			&message.RoomID,
+			&message.SentAt,
+			&message.Content,
+			&message.Sender.Name,
+			&message.Sender.Level,
+		)
+		if err != nil {
+			return nil, err
+		}
+		message.Sender.CommanderID = message.SenderID
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
 func (m *Message) Create() error {
 	ctx := context.Background()
 	row, err := db.DefaultStore.Queries.CreateMessage(ctx, gen.CreateMessageParams{SenderID: int64(m.SenderID), RoomID: int64(m.RoomID), Content: m.Content})
@@ -46,18 +98,27 @@ func (m *Message) Delete() error {
 	return err
 }
 
-// Returns the last 50 messages from a room
-func GetRoomHistory(roomID uint32) ([]Message, error) {
+// GetRoomHistory returns the last limit messages of a room, oldest first,
+// with the name and level of their sender.
+func GetRoomHistory(roomID uint32, limit int) ([]Message, error) {
 	ctx := context.Background()
-	rows, err := db.DefaultStore.Queries.ListRoomHistory(ctx, int64(roomID))
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+messageColumns+`
+FROM (
+	SELECT id, sender_id, room_id, sent_at, content
+	FROM messages
+	WHERE room_id = $1
+	ORDER BY sent_at DESC, id DESC
+	LIMIT $2
+) m
+JOIN commanders c ON c.commander_id = m.sender_id
+ORDER BY m.sent_at ASC, m.id ASC
+`, int64(roomID), limit)
 	if err != nil {
 		return nil, err
 	}
-	var messages []Message
-	for _, r := range rows {
-		messages = append(messages, Message{ID: uint32(r.ID), SenderID: uint32(r.SenderID), RoomID: uint32(r.RoomID), SentAt: r.SentAt.Time, Content: r.Content})
-	}
-	return messages, nil
+	defer rows.Close()
+	return scanMessages(rows)
 }
 
 // Inserts a message in the database
@@ -70,3 +131,165 @@ func SendMessage(roomID uint32, content string, sender *Commander) (*Message, er
 	err := message.Create()
 	return &message, err
 }
+
+// GetMessage returns a message with the name and level of its sender.
+func GetMessage(id uint32) (*Message, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+messageColumns+`
+FROM messages m
+JOIN commanders c ON c.commander_id = m.sender_id
+WHERE m.id = $1
+`, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return &messages[0], nil
+}
+
+// SearchMessages returns a page of the messages matching search, newest
+// first, and the number of matches.
+func SearchMessages(search MessageSearch) ([]Message, int64, error) {
+	ctx := context.Background()
+	where := `
+WHERE ($1::bigint IS NULL OR m.room_id = $1)
+  AND ($2 = 0 OR m.sender_id = $2)
+  AND ($3 = '' OR m.content ILIKE '%' || $3 || '%')
+`
+	var roomID *int64
+	if search.RoomID != nil {
+		id := int64(*search.RoomID)
+		roomID = &id
+	}
+	args := []any{roomID, int64(search.SenderID), search.Content}
+	var total int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM messages m
+`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+messageColumns+`
+FROM messages m
+JOIN commanders c ON c.commander_id = m.sender_id
+`+where+`
+ORDER BY m.sent_at DESC, m.id DESC
+OFFSET $4
+LIMIT $5
+`, append(args, search.Offset, search.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	messages, err := scanMessages(rows)
+	return messages, total, err
+}
+
+// DeleteMessage removes a message, returning db.ErrNotFound when it does not
+// exist.
+func DeleteMessage(id uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM messages
+WHERE id = $1
+`, int64(id))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+const chatMuteColumns = `commander_id, reason, muted_until, created_at`
+
+func scanChatMute(scanner rowScanner) (*ChatMute, error) {
+	mute := ChatMute{}
+	if err := scanner.Scan(&mute.CommanderID, &mute.Reason, &mute.MutedUntil, &mute.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &mute, nil
+}
+
+// GetActiveChatMute returns the mute of a commander, or db.ErrNotFound when
+// the commander is not muted at now.
+func GetActiveChatMute(commanderID uint32, now time.Time) (*ChatMute, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+chatMuteColumns+`
+FROM chat_mutes
+WHERE commander_id = $1
+  AND muted_until > $2
+`, int64(commanderID), now)
+	mute, err := scanChatMute(row)
+	return mute, db.MapNotFound(err)
+}
+
+// UpsertChatMute mutes a commander, replacing any previous mute.
+func UpsertChatMute(commanderID uint32, reason string, mutedUntil time.Time) (*ChatMute, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO chat_mutes (commander_id, reason, muted_until, created_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  reason = EXCLUDED.reason,
+  muted_until = EXCLUDED.muted_until,
+  created_at = NOW()
+RETURNING `+chatMuteColumns+`
+`, int64(commanderID), reason, mutedUntil)
+	return scanChatMute(row)
+}
+
+// DeleteChatMute lifts the mute of a commander, returning db.ErrNotFound when
+// there was none.
+func DeleteChatMute(commanderID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM chat_mutes
+WHERE commander_id = $1
+`, int64(commanderID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ListActiveChatMutes returns the mutes still running at now, the ones
+// ending first first.
+func ListActiveChatMutes(now time.Time) ([]ChatMute, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+chatMuteColumns+`
+FROM chat_mutes
+WHERE muted_until > $1
+ORDER BY muted_until ASC, commander_id ASC
+`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	mutes := []ChatMute{}
+	for rows.Next() {
+		mute, err := scanChatMute(rows)
+		if err != nil {
+			return nil, err
+		}
+		mutes = append(mutes, *mute)
+	}
+	return mutes, rows.Err()
+}
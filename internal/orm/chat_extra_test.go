@@ -35,13 +35,50 @@ func TestMessageCRUDAndHistory(t *testing.T) {
 			t.Fatalf("seed message: %v", err)
 		}
 	}
-	history, err := GetRoomHistory(2)
+	history, err := GetRoomHistory(2, 50)
 	if err != nil {
 		t.Fatalf("get history: %v", err)
 	}
 	if len(history) != 3 {
 		t.Fatalf("expected 3 messages, got %d", len(history))
 	}
+	if history[0].Sender.Name != "Chatter" {
+		t.Fatalf("expected sender name, got %q", history[0].Sender.Name)
+	}
+	history, err = GetRoomHistory(2, 2)
+	if err != nil {
+		t.Fatalf("get limited history: %v", err)
+	}
+	if len(history) != 2 || history[0].ID >= history[1].ID {
+		t.Fatalf("expected the last 2 messages oldest first, got %+v", history)
+	}
+}
+
+func TestChatMutes(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearTable(t, &ChatMute{})
+	clearTable(t, &Commander{})
+
+	if _, err := db.DefaultStore.Pool.Exec(context.Background(), `INSERT INTO commanders (commander_id, account_id, name) VALUES (42, 42, 'Muted')`); err != nil {
+		t.Fatalf("seed commander: %v", err)
+	}
+	now := time.Now()
+	if _, err := UpsertChatMute(42, "spam", now.Add(time.Hour)); err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+	mute, err := GetActiveChatMute(42, now)
+	if err != nil || mute.Reason != "spam" {
+		t.Fatalf("expected active mute, got %+v, %v", mute, err)
+	}
+	if _, err := GetActiveChatMute(42, now.Add(2*time.Hour)); !db.IsNotFound(err) {
+		t.Fatalf("expected expired mute, got %v", err)
+	}
+	if err := DeleteChatMute(42); err != nil {
+		t.Fatalf("unmute: %v", err)
+	}
+	if err := DeleteChatMute(42); !db.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
 }
 
 func TestSendMessage(t *testing.T) {
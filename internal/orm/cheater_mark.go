@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	// CheaterMarkSourceClient marks reported by the client itself (CS_10994).
+	CheaterMarkSourceClient = "client"
+	// CheaterMarkSourceBattle marks raised while validating a battle result.
+	CheaterMarkSourceBattle = "battle"
+)
+
+// CheaterMark records a commander suspected of cheating, either reported by
+// the client or found by the battle result validation.
+type CheaterMark struct {
+	ID          uint64
+	CommanderID uint32
+	Source      string
+	Reason      string
+	System      uint32
+	StageID     uint32
+	Detail      string
+	CreatedAt   time.Time
+}
+
+func CreateCheaterMark(mark *CheaterMark) error {
+	ctx := context.Background()
+	return db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO cheater_marks (commander_id, source, reason, system, stage_id, detail)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`, int64(mark.CommanderID), mark.Source, mark.Reason, int64(mark.System), int64(mark.StageID), mark.Detail).Scan(&mark.ID, &mark.CreatedAt)
+}
+
+// ListCheaterMarks returns the marks of a commander, newest first.
+func ListCheaterMarks(commanderID uint32) ([]CheaterMark, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT id, commander_id, source, reason, system, stage_id, detail, created_at
+FROM cheater_marks
+WHERE commander_id = $1
+ORDER BY created_at DESC, id DESC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	marks := []CheaterMark{}
+	for rows.Next() {
+		var mark CheaterMark
+		if err := rows.Scan(&mark.ID, &mark.CommanderID, &mark.Source, &mark.Reason, &mark.System, &mark.StageID, &mark.Detail, &mark.CreatedAt); err != nil {
+			return nil, err
+		}
+		marks = append(marks, mark)
+	}
+	return marks, rows.Err()
+}
+
+func DeleteCheaterMarks(commanderID uint32) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM cheater_marks WHERE commander_id = $1`, int64(commanderID))
+	return err
+}
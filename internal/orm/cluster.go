@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// ClusterNode is a game server sharing the database. A node whose
+// HeartbeatAt is too old is considered down.
+type ClusterNode struct {
+	NodeID      string
+	StartedAt   time.Time
+	HeartbeatAt time.Time
+}
+
+func (ClusterNode) TableName() string {
+	return "cluster_nodes"
+}
+
+// CommanderPresence records the node a commander is connected to.
+type CommanderPresence struct {
+	CommanderID uint32
+	NodeID      string
+	ConnectedAt time.Time
+}
+
+func (CommanderPresence) TableName() string {
+	return "commander_presence"
+}
+
+// UpsertClusterNode records a heartbeat of a node.
+func UpsertClusterNode(node *ClusterNode) error {
+	ctx := context.Background()
+	return db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO cluster_nodes (node_id, started_at, heartbeat_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (node_id)
+DO UPDATE SET
+  started_at = EXCLUDED.started_at,
+  heartbeat_at = NOW()
+RETURNING heartbeat_at
+`, node.NodeID, node.StartedAt).Scan(&node.HeartbeatAt)
+}
+
+// ClaimCommanderPresence records that a commander is connected to nodeID,
+// replacing any previous node.
+func ClaimCommanderPresence(commanderID uint32, nodeID string) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+INSERT INTO commander_presence (commander_id, node_id, connected_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  node_id = EXCLUDED.node_id,
+  connected_at = NOW()
+`, int64(commanderID), nodeID)
+	return err
+}
+
+// ReleaseCommanderPresence forgets the presence of a commander, unless the
+// commander connected to another node in the meantime.
+func ReleaseCommanderPresence(commanderID uint32, nodeID string) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM commander_presence
+WHERE commander_id = $1
+  AND node_id = $2
+`, int64(commanderID), nodeID)
+	return err
+}
+
+// ClearNodePresence forgets every commander connected to a node, e.g. when
+// it restarts.
+func ClearNodePresence(nodeID string) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM commander_presence
+WHERE node_id = $1
+`, nodeID)
+	return err
+}
+
+// GetCommanderNode returns the node a commander is connected to, or
+// db.ErrNotFound when the commander is offline or its node is down.
+func GetCommanderNode(commanderID uint32, liveSince time.Time) (string, error) {
+	ctx := context.Background()
+	var nodeID string
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT p.node_id
+FROM commander_presence p
+JOIN cluster_nodes n ON n.node_id = p.node_id
+WHERE p.commander_id = $1
+  AND n.heartbeat_at >= $2
+`, int64(commanderID), liveSince).Scan(&nodeID)
+	return nodeID, db.MapNotFound(err)
+}
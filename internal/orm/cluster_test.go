@@ -0,0 +1,50 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestCommanderPresence(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearTable(t, &CommanderPresence{})
+	clearTable(t, &ClusterNode{})
+	clearTable(t, &Commander{})
+
+	if _, err := db.DefaultStore.Pool.Exec(context.Background(), `INSERT INTO commanders (commander_id, account_id, name) VALUES (42, 42, 'Roaming')`); err != nil {
+		t.Fatalf("seed commander: %v", err)
+	}
+	now := time.Now()
+	for _, nodeID := range []string{"a", "b"} {
+		if err := UpsertClusterNode(&ClusterNode{NodeID: nodeID, StartedAt: now}); err != nil {
+			t.Fatalf("heartbeat %s: %v", nodeID, err)
+		}
+	}
+
+	if err := ClaimCommanderPresence(42, "a"); err != nil {
+		t.Fatalf("claim a: %v", err)
+	}
+	if err := ClaimCommanderPresence(42, "b"); err != nil {
+		t.Fatalf("claim b: %v", err)
+	}
+	// node a releasing late must not hide the session on node b
+	if err := ReleaseCommanderPresence(42, "a"); err != nil {
+		t.Fatalf("release a: %v", err)
+	}
+	nodeID, err := GetCommanderNode(42, now.Add(-time.Minute))
+	if err != nil || nodeID != "b" {
+		t.Fatalf("expected commander on node b, got %q, %v", nodeID, err)
+	}
+	if _, err := GetCommanderNode(42, now.Add(time.Minute)); !db.IsNotFound(err) {
+		t.Fatalf("expected stale node to be ignored, got %v", err)
+	}
+	if err := ClearNodePresence("b"); err != nil {
+		t.Fatalf("clear b: %v", err)
+	}
+	if _, err := GetCommanderNode(42, now.Add(-time.Minute)); !db.IsNotFound(err) {
+		t.Fatalf("expected commander offline, got %v", err)
+	}
+}
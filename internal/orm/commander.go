@@ -101,15 +101,17 @@ func (c *Commander) HasEnoughResource(resourceId uint32, n uint32) bool {
 }
 
 func (c *Commander) CreateBuild(poolId uint32, runningBuilds *int) (*Build, uint32, error) {
-	ship, err := GetRandomPoolShip(poolId)
+	now := time.Now()
+	roll, err := c.RollBuildShip(poolId, now)
 	if err != nil {
 		return nil, 0, err
 	}
+	ship := roll.Ship
 	newBuild := Build{
 		BuilderID:  c.CommanderID,
 		ShipID:     ship.TemplateID,
 		PoolID:     poolId,
-		FinishesAt: time.Now().Add(time.Second * time.Duration(ship.BuildTime)),
+		FinishesAt: now.Add(time.Second * time.Duration(ship.BuildTime)),
 	}
 	if err := newBuild.Create(); err != nil {
 		return nil, 0, err
@@ -1168,12 +1170,9 @@ WHERE owner_id = $1
 	})
 }
 
-// Add n exchange count to the commander, n represents the number of built ships, caps at 400
+// Add n exchange count to the commander, n represents the number of built ships, caps at maxExchangeCount
 func (c *Commander) IncrementExchangeCount(n uint32) error {
-	c.ExchangeCount += n
-	if c.ExchangeCount > 400 {
-		c.ExchangeCount = 400
-	}
+	c.ExchangeCount = uint32(min(uint64(c.ExchangeCount)+uint64(n), uint64(maxExchangeCount)))
 	ctx := context.Background()
 	_, err := db.DefaultStore.Pool.Exec(ctx, `UPDATE commanders SET exchange_count = $2 WHERE commander_id = $1`, int64(c.CommanderID), int64(c.ExchangeCount))
 	return err
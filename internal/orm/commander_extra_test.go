@@ -419,8 +419,8 @@ func TestCommanderCountersAndLike(t *testing.T) {
 	if err := commander.IncrementExchangeCount(20); err != nil {
 		t.Fatalf("increment exchange: %v", err)
 	}
-	if commander.ExchangeCount != 400 {
-		t.Fatalf("expected exchange count capped at 400")
+	if commander.ExchangeCount != maxExchangeCount {
+		t.Fatalf("expected exchange count capped at %d", maxExchangeCount)
 	}
 	if err := commander.IncrementDrawCount(1); err != nil {
 		t.Fatalf("increment draw 1: %v", err)
@@ -0,0 +1,215 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// CommanderTask is the progress of one accepted daily, weekly or story task.
+// SubmitTime stays 0 until the rewards were claimed.
+type CommanderTask struct {
+	CommanderID uint32
+	TaskID      uint32
+	Progress    uint32
+	AcceptTime  uint32
+	SubmitTime  uint32
+}
+
+// CommanderTaskState holds the weekly mission points and the last times the
+// daily and weekly task lists were rebuilt.
+type CommanderTaskState struct {
+	CommanderID       uint32
+	WeeklyPt          uint32
+	WeeklyRewardLv    uint32
+	LastDailyResetAt  time.Time
+	LastWeeklyResetAt time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func GetOrCreateCommanderTaskState(commanderID uint32) (*CommanderTaskState, error) {
+	ctx := context.Background()
+	var state CommanderTaskState
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT commander_id, weekly_pt, weekly_reward_lv, last_daily_reset_at, last_weekly_reset_at, created_at, updated_at
+FROM commander_task_states
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&state.CommanderID, &state.WeeklyPt, &state.WeeklyRewardLv, &state.LastDailyResetAt, &state.LastWeeklyResetAt, &state.CreatedAt, &state.UpdatedAt)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &state, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	state = CommanderTaskState{CommanderID: commanderID, LastDailyResetAt: time.Unix(0, 0), LastWeeklyResetAt: time.Unix(0, 0)}
+	if err := SaveCommanderTaskState(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func SaveCommanderTaskStateTx(ctx context.Context, tx pgx.Tx, state *CommanderTaskState) error {
+	now := time.Now().UTC()
+	if state.LastDailyResetAt.IsZero() {
+		state.LastDailyResetAt = time.Unix(0, 0)
+	}
+	if state.LastWeeklyResetAt.IsZero() {
+		state.LastWeeklyResetAt = time.Unix(0, 0)
+	}
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
+	state.UpdatedAt = now
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_task_states (
+  commander_id,
+  weekly_pt,
+  weekly_reward_lv,
+  last_daily_reset_at,
+  last_weekly_reset_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  weekly_pt = EXCLUDED.weekly_pt,
+  weekly_reward_lv = EXCLUDED.weekly_reward_lv,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at,
+  last_weekly_reset_at = EXCLUDED.last_weekly_reset_at,
+  updated_at = EXCLUDED.updated_at
+`, int64(state.CommanderID), int64(state.WeeklyPt), int64(state.WeeklyRewardLv), state.LastDailyResetAt, state.LastWeeklyResetAt, state.CreatedAt, state.UpdatedAt)
+	return err
+}
+
+func SaveCommanderTaskState(state *CommanderTaskState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveCommanderTaskStateTx(ctx, tx, state)
+	})
+}
+
+// ApplyCommanderTaskDailyReset moves the daily reset marker to the start of
+// the current UTC day and reports whether the daily tasks must be rebuilt.
+func ApplyCommanderTaskDailyReset(state *CommanderTaskState, now time.Time) bool {
+	resetAt := startOfDay(now.UTC())
+	if state.LastDailyResetAt.Before(resetAt) {
+		state.LastDailyResetAt = resetAt
+		return true
+	}
+	return false
+}
+
+// ApplyCommanderTaskWeeklyReset clears the weekly points once a new week
+// (starting on Monday, UTC) began and reports whether the weekly tasks must
+// be rebuilt.
+func ApplyCommanderTaskWeeklyReset(state *CommanderTaskState, now time.Time) bool {
+	day := startOfDay(now.UTC())
+	resetAt := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	if state.LastWeeklyResetAt.Before(resetAt) {
+		state.WeeklyPt = 0
+		state.WeeklyRewardLv = 0
+		state.LastWeeklyResetAt = resetAt
+		return true
+	}
+	return false
+}
+
+func ListCommanderTasks(commanderID uint32) ([]CommanderTask, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, task_id, progress, accept_time, submit_time
+FROM commander_tasks
+WHERE commander_id = $1
+ORDER BY task_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tasks := make([]CommanderTask, 0)
+	for rows.Next() {
+		var task CommanderTask
+		if err := rows.Scan(&task.CommanderID, &task.TaskID, &task.Progress, &task.AcceptTime, &task.SubmitTime); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func GetCommanderTask(commanderID uint32, taskID uint32) (*CommanderTask, error) {
+	ctx := context.Background()
+	task := CommanderTask{CommanderID: commanderID, TaskID: taskID}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT progress, accept_time, submit_time
+FROM commander_tasks
+WHERE commander_id = $1 AND task_id = $2
+`, int64(commanderID), int64(taskID)).Scan(&task.Progress, &task.AcceptTime, &task.SubmitTime)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func UpsertCommanderTaskTx(ctx context.Context, tx pgx.Tx, task *CommanderTask) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_tasks (commander_id, task_id, progress, accept_time, submit_time)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (commander_id, task_id) DO UPDATE
+SET progress = EXCLUDED.progress,
+	accept_time = EXCLUDED.accept_time,
+	submit_time = EXCLUDED.submit_time
+`, int64(task.CommanderID), int64(task.TaskID), int64(task.Progress), int64(task.AcceptTime), int64(task.SubmitTime))
+	return err
+}
+
+func UpsertCommanderTask(task *CommanderTask) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return UpsertCommanderTaskTx(ctx, tx, task)
+	})
+}
+
+// SubmitCommanderTaskTx marks a task as claimed. It returns db.ErrNotFound
+// when the task is missing or was already submitted, so concurrent claims
+// only grant rewards once.
+func SubmitCommanderTaskTx(ctx context.Context, tx pgx.Tx, commanderID uint32, taskID uint32, submitTime uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE commander_tasks
+SET submit_time = $3
+WHERE commander_id = $1 AND task_id = $2 AND submit_time = 0
+`, int64(commanderID), int64(taskID), int64(submitTime))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func DeleteCommanderTasksTx(ctx context.Context, tx pgx.Tx, commanderID uint32, taskIDs []uint32) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(taskIDs))
+	for i, id := range taskIDs {
+		ids[i] = int64(id)
+	}
+	_, err := tx.Exec(ctx, `
+DELETE FROM commander_tasks
+WHERE commander_id = $1 AND task_id = ANY($2)
+`, int64(commanderID), ids)
+	return err
+}
@@ -0,0 +1,99 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestCommanderTaskStateResets(t *testing.T) {
+	state := CommanderTaskState{WeeklyPt: 120, WeeklyRewardLv: 2, LastDailyResetAt: time.Unix(0, 0), LastWeeklyResetAt: time.Unix(0, 0)}
+	// Wednesday; the week started on Monday 2026-03-02.
+	now := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	if !ApplyCommanderTaskDailyReset(&state, now) {
+		t.Fatalf("expected daily reset")
+	}
+	if ApplyCommanderTaskDailyReset(&state, now.Add(time.Hour)) {
+		t.Fatalf("expected no daily reset for same day")
+	}
+	if !ApplyCommanderTaskWeeklyReset(&state, now) {
+		t.Fatalf("expected weekly reset")
+	}
+	if state.WeeklyPt != 0 || state.WeeklyRewardLv != 0 {
+		t.Fatalf("expected weekly points cleared, got %+v", state)
+	}
+	if !state.LastWeeklyResetAt.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weekly reset time %v", state.LastWeeklyResetAt)
+	}
+	state.WeeklyPt = 40
+	if ApplyCommanderTaskWeeklyReset(&state, time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected no weekly reset before monday")
+	}
+	if !ApplyCommanderTaskWeeklyReset(&state, time.Date(2026, 3, 9, 0, 0, 1, 0, time.UTC)) || state.WeeklyPt != 0 {
+		t.Fatalf("expected weekly reset on monday")
+	}
+}
+
+func TestCommanderTasksSubmitOnce(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9931, "Task ORM")
+
+	state, err := GetOrCreateCommanderTaskState(9931)
+	if err != nil {
+		t.Fatalf("get or create task state: %v", err)
+	}
+	state.WeeklyPt = 30
+	if err := SaveCommanderTaskState(state); err != nil {
+		t.Fatalf("save task state: %v", err)
+	}
+	if err := UpsertCommanderTask(&CommanderTask{CommanderID: 9931, TaskID: 10, Progress: 3, AcceptTime: 100}); err != nil {
+		t.Fatalf("upsert task: %v", err)
+	}
+	if err := UpsertCommanderTask(&CommanderTask{CommanderID: 9931, TaskID: 11, AcceptTime: 100}); err != nil {
+		t.Fatalf("upsert task: %v", err)
+	}
+
+	ctx := context.Background()
+	submit := func() error {
+		return WithPGXTx(ctx, func(tx pgx.Tx) error {
+			return SubmitCommanderTaskTx(ctx, tx, 9931, 10, 200)
+		})
+	}
+	if err := submit(); err != nil {
+		t.Fatalf("submit task: %v", err)
+	}
+	if err := submit(); !db.IsNotFound(err) {
+		t.Fatalf("expected second submit to be rejected, got %v", err)
+	}
+	task, err := GetCommanderTask(9931, 10)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if task.Progress != 3 || task.SubmitTime != 200 {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return DeleteCommanderTasksTx(ctx, tx, 9931, []uint32{10})
+	}); err != nil {
+		t.Fatalf("delete tasks: %v", err)
+	}
+	tasks, err := ListCommanderTasks(9931)
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].TaskID != 11 {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+	loaded, err := GetOrCreateCommanderTaskState(9931)
+	if err != nil {
+		t.Fatalf("reload task state: %v", err)
+	}
+	if loaded.WeeklyPt != 30 {
+		t.Fatalf("expected weekly pt 30, got %d", loaded.WeeklyPt)
+	}
+}
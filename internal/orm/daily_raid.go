@@ -0,0 +1,226 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+var ErrDailyRaidLimit = errors.New("daily raid attempts exhausted")
+
+// DailyRaidState holds the per-day battle counters that are reported in
+// SC_13201 besides the per-raid attempts.
+type DailyRaidState struct {
+	CommanderID           uint32
+	EliteExpeditionCount  uint32
+	EscortExpeditionCount uint32
+	LastDailyResetAt      time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// DailyRaidCount is the number of attempts used today on a daily raid.
+type DailyRaidCount struct {
+	DailyID uint32
+	Count   uint32
+}
+
+// StageClear records that a commander won a stage at least once.
+type StageClear struct {
+	CommanderID    uint32
+	StageID        uint32
+	BestScore      uint32
+	ClearCount     uint32
+	FirstClearedAt time.Time
+}
+
+func GetOrCreateDailyRaidState(commanderID uint32) (*DailyRaidState, error) {
+	ctx := context.Background()
+	var state DailyRaidState
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT commander_id, elite_expedition_count, escort_expedition_count, last_daily_reset_at, created_at, updated_at
+FROM commander_daily_raid_states
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&state.CommanderID, &state.EliteExpeditionCount, &state.EscortExpeditionCount, &state.LastDailyResetAt, &state.CreatedAt, &state.UpdatedAt)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &state, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	state = DailyRaidState{CommanderID: commanderID, LastDailyResetAt: time.Unix(0, 0)}
+	if err := SaveDailyRaidState(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func SaveDailyRaidStateTx(ctx context.Context, tx pgx.Tx, state *DailyRaidState) error {
+	now := time.Now().UTC()
+	if state.LastDailyResetAt.IsZero() {
+		state.LastDailyResetAt = time.Unix(0, 0)
+	}
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
+	state.UpdatedAt = now
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_daily_raid_states (
+  commander_id,
+  elite_expedition_count,
+  escort_expedition_count,
+  last_daily_reset_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  elite_expedition_count = EXCLUDED.elite_expedition_count,
+  escort_expedition_count = EXCLUDED.escort_expedition_count,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at,
+  updated_at = EXCLUDED.updated_at
+`, int64(state.CommanderID), int64(state.EliteExpeditionCount), int64(state.EscortExpeditionCount), state.LastDailyResetAt, state.CreatedAt, state.UpdatedAt)
+	return err
+}
+
+func SaveDailyRaidState(state *DailyRaidState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveDailyRaidStateTx(ctx, tx, state)
+	})
+}
+
+// ApplyDailyRaidDailyReset clears the daily counters once a new UTC day
+// started. The caller is expected to also clear the raid attempts.
+func ApplyDailyRaidDailyReset(state *DailyRaidState, now time.Time) bool {
+	resetAt := startOfDay(now.UTC())
+	if state.LastDailyResetAt.Before(resetAt) {
+		state.EliteExpeditionCount = 0
+		state.EscortExpeditionCount = 0
+		state.LastDailyResetAt = resetAt
+		return true
+	}
+	return false
+}
+
+func ClearDailyRaidCountsTx(ctx context.Context, tx pgx.Tx, commanderID uint32) error {
+	_, err := tx.Exec(ctx, `DELETE FROM commander_daily_raid_counts WHERE commander_id = $1`, int64(commanderID))
+	return err
+}
+
+func ListDailyRaidCounts(commanderID uint32) ([]DailyRaidCount, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT daily_id, count
+FROM commander_daily_raid_counts
+WHERE commander_id = $1
+ORDER BY daily_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make([]DailyRaidCount, 0)
+	for rows.Next() {
+		var count DailyRaidCount
+		if err := rows.Scan(&count.DailyID, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// AddDailyRaidCountTx spends delta attempts of a daily raid, returning
+// ErrDailyRaidLimit when that would exceed limit.
+func AddDailyRaidCountTx(ctx context.Context, tx pgx.Tx, commanderID uint32, dailyID uint32, delta uint32, limit uint32) error {
+	if delta > limit {
+		return ErrDailyRaidLimit
+	}
+	tag, err := tx.Exec(ctx, `
+INSERT INTO commander_daily_raid_counts (commander_id, daily_id, count)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, daily_id) DO UPDATE
+SET count = commander_daily_raid_counts.count + EXCLUDED.count
+WHERE commander_daily_raid_counts.count + EXCLUDED.count <= $4
+`, int64(commanderID), int64(dailyID), int64(delta), int64(limit))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDailyRaidLimit
+	}
+	return nil
+}
+
+// RecordStageClear stores a won battle on stageID, keeping the best score,
+// and reports whether it was the first clear.
+func RecordStageClear(commanderID uint32, stageID uint32, score uint32) (bool, error) {
+	ctx := context.Background()
+	var clearCount uint32
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO commander_stage_clears (commander_id, stage_id, best_score, clear_count, first_cleared_at)
+VALUES ($1, $2, $3, 1, NOW())
+ON CONFLICT (commander_id, stage_id) DO UPDATE
+SET best_score = GREATEST(commander_stage_clears.best_score, EXCLUDED.best_score),
+	clear_count = commander_stage_clears.clear_count + 1
+RETURNING clear_count
+`, int64(commanderID), int64(stageID), int64(score)).Scan(&clearCount)
+	if err != nil {
+		return false, err
+	}
+	return clearCount == 1, nil
+}
+
+// GetStageClear returns the clear record of stageID, or db.ErrNotFound when
+// the stage was never won.
+func GetStageClear(commanderID uint32, stageID uint32) (*StageClear, error) {
+	ctx := context.Background()
+	entry := StageClear{CommanderID: commanderID, StageID: stageID}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT best_score, clear_count, first_cleared_at
+FROM commander_stage_clears
+WHERE commander_id = $1 AND stage_id = $2
+`, int64(commanderID), int64(stageID)).Scan(&entry.BestScore, &entry.ClearCount, &entry.FirstClearedAt)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func ListStageClears(commanderID uint32) ([]StageClear, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, stage_id, best_score, clear_count, first_cleared_at
+FROM commander_stage_clears
+WHERE commander_id = $1
+ORDER BY stage_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	clears := make([]StageClear, 0)
+	for rows.Next() {
+		var entry StageClear
+		if err := rows.Scan(&entry.CommanderID, &entry.StageID, &entry.BestScore, &entry.ClearCount, &entry.FirstClearedAt); err != nil {
+			return nil, err
+		}
+		clears = append(clears, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return clears, nil
+}
@@ -0,0 +1,53 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestDailyRaidCountsRespectLimit(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9941, "Daily Raid ORM")
+
+	ctx := context.Background()
+	add := func(delta uint32) error {
+		return WithPGXTx(ctx, func(tx pgx.Tx) error {
+			return AddDailyRaidCountTx(ctx, tx, 9941, 101, delta, 3)
+		})
+	}
+	if err := add(2); err != nil {
+		t.Fatalf("add count: %v", err)
+	}
+	if err := add(2); !errors.Is(err, ErrDailyRaidLimit) {
+		t.Fatalf("expected ErrDailyRaidLimit, got %v", err)
+	}
+	if err := add(1); err != nil {
+		t.Fatalf("add count: %v", err)
+	}
+	counts, err := ListDailyRaidCounts(9941)
+	if err != nil {
+		t.Fatalf("list counts: %v", err)
+	}
+	if len(counts) != 1 || counts[0].Count != 3 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+
+	first, err := RecordStageClear(9941, 5001, 2)
+	if err != nil || !first {
+		t.Fatalf("expected first clear, got %v %v", first, err)
+	}
+	first, err = RecordStageClear(9941, 5001, 4)
+	if err != nil || first {
+		t.Fatalf("expected repeated clear, got %v %v", first, err)
+	}
+	entry, err := GetStageClear(9941, 5001)
+	if err != nil {
+		t.Fatalf("get stage clear: %v", err)
+	}
+	if entry.BestScore != 4 || entry.ClearCount != 2 {
+		t.Fatalf("unexpected stage clear: %+v", entry)
+	}
+}
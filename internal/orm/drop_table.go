@@ -0,0 +1,416 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	// DropKindChapter tables roll when an enemy of a chapter is defeated.
+	DropKindChapter = "chapter"
+	// DropKindEvent tables roll along the chapter ones while their activity
+	// is open.
+	DropKindEvent = "event"
+	// DropKindItem tables pick what a virtual item turns into.
+	DropKindItem = "item"
+
+	// DropRateScale is the denominator of the drop rates, a rate of 150
+	// making drops 1.5 times as likely.
+	DropRateScale = uint32(100)
+)
+
+var ErrDropTableExists = errors.New("drop table already exists for this source")
+
+// DropTable holds the weights of the drops of a chapter, an event or a
+// virtual item. Each roll picks one entry, or nothing with EmptyWeight.
+//
+// The rank rates and RateMultiplier scale the weights of the entries, not
+// EmptyWeight: they only change the odds of tables with an empty weight.
+type DropTable struct {
+	ID             uint32
+	Kind           string
+	SourceID       uint32
+	Name           string
+	Rolls          uint32
+	EmptyWeight    uint32
+	RankSRate      uint32
+	RankARate      uint32
+	RankBRate      uint32
+	RateMultiplier uint32
+	Enabled        bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Entries        []DropTableEntry
+}
+
+type DropTableEntry struct {
+	DropType uint32
+	DropID   uint32
+	Count    uint32
+	Weight   uint32
+}
+
+// DropBonus scales the drop rates while it runs. A bonus without kind
+// applies to every table, one without source to every table of its kind.
+type DropBonus struct {
+	ID        uint32
+	Name      string
+	Kind      *string
+	SourceID  *uint32
+	Rate      uint32
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (DropTable) TableName() string {
+	return "drop_tables"
+}
+
+func (DropBonus) TableName() string {
+	return "drop_bonuses"
+}
+
+// AppliesTo reports whether the bonus scales the table of kind and sourceID.
+func (bonus *DropBonus) AppliesTo(kind string, sourceID uint32) bool {
+	if bonus.Kind != nil && *bonus.Kind != kind {
+		return false
+	}
+	return bonus.SourceID == nil || *bonus.SourceID == sourceID
+}
+
+const dropTableColumns = `id, kind, source_id, name, rolls, empty_weight, rank_s_rate, rank_a_rate, rank_b_rate, rate_multiplier, enabled, created_at, updated_at`
+
+func scanDropTable(scanner rowScanner) (DropTable, error) {
+	var table DropTable
+	err := scanner.Scan(
+		&table.ID,
+		&table.Kind,
+		&table.SourceID,
+		&table.Name,
+		&table.Rolls,
+		&table.EmptyWeight,
+		&table.RankSRate,
+		&table.RankARate,
+		&table.RankBRate,
+		&table.RateMultiplier,
+		&table.Enabled,
+		&table.CreatedAt,
+		&table.UpdatedAt,
+	)
+	return table, err
+}
+
+func scanDropTables(rows pgx.Rows) ([]DropTable, error) {
+	tables := make([]DropTable, 0)
+	for rows.Next() {
+		table, err := scanDropTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func loadDropTableEntries(ctx context.Context, tables []DropTable) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(tables))
+	index := make(map[uint32]int, len(tables))
+	for i := range tables {
+		tables[i].Entries = []DropTableEntry{}
+		ids = append(ids, int64(tables[i].ID))
+		index[tables[i].ID] = i
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT table_id, drop_type, drop_id, count, weight
+FROM drop_table_entries
+WHERE table_id = ANY($1)
+ORDER BY table_id ASC, position ASC
+`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableID uint32
+		var entry DropTableEntry
+		if err := rows.Scan(&tableID, &entry.DropType, &entry.DropID, &entry.Count, &entry.Weight); err != nil {
+			return err
+		}
+		table := &tables[index[tableID]]
+		table.Entries = append(table.Entries, entry)
+	}
+	return rows.Err()
+}
+
+func insertDropTableEntriesTx(ctx context.Context, tx pgx.Tx, table *DropTable) error {
+	for i, entry := range table.Entries {
+		_, err := tx.Exec(ctx, `
+INSERT INTO drop_table_entries (table_id, position, drop_type, drop_id, count, weight)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, int64(table.ID), int64(i), int64(entry.DropType), int64(entry.DropID), int64(entry.Count), int64(entry.Weight))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapDropTableUnique(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDropTableExists
+	}
+	return err
+}
+
+func CreateDropTable(table *DropTable) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+INSERT INTO drop_tables (kind, source_id, name, rolls, empty_weight, rank_s_rate, rank_a_rate, rank_b_rate, rate_multiplier, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, created_at, updated_at
+`, table.Kind, int64(table.SourceID), table.Name, int64(table.Rolls), int64(table.EmptyWeight), int64(table.RankSRate), int64(table.RankARate), int64(table.RankBRate), int64(table.RateMultiplier), table.Enabled).Scan(&table.ID, &table.CreatedAt, &table.UpdatedAt)
+		if err != nil {
+			return mapDropTableUnique(err)
+		}
+		return insertDropTableEntriesTx(ctx, tx, table)
+	})
+}
+
+// UpdateDropTable saves table and replaces its entries.
+func UpdateDropTable(table *DropTable) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+UPDATE drop_tables
+SET kind = $2,
+	source_id = $3,
+	name = $4,
+	rolls = $5,
+	empty_weight = $6,
+	rank_s_rate = $7,
+	rank_a_rate = $8,
+	rank_b_rate = $9,
+	rate_multiplier = $10,
+	enabled = $11,
+	updated_at = NOW()
+WHERE id = $1
+RETURNING created_at, updated_at
+`, int64(table.ID), table.Kind, int64(table.SourceID), table.Name, int64(table.Rolls), int64(table.EmptyWeight), int64(table.RankSRate), int64(table.RankARate), int64(table.RankBRate), int64(table.RateMultiplier), table.Enabled).Scan(&table.CreatedAt, &table.UpdatedAt)
+		if err := db.MapNotFound(mapDropTableUnique(err)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM drop_table_entries WHERE table_id = $1`, int64(table.ID)); err != nil {
+			return err
+		}
+		return insertDropTableEntriesTx(ctx, tx, table)
+	})
+}
+
+func GetDropTable(tableID uint32) (*DropTable, error) {
+	ctx := context.Background()
+	table, err := scanDropTable(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+dropTableColumns+`
+FROM drop_tables
+WHERE id = $1
+`, int64(tableID)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	tables := []DropTable{table}
+	if err := loadDropTableEntries(ctx, tables); err != nil {
+		return nil, err
+	}
+	return &tables[0], nil
+}
+
+// GetDropTableBySource returns the table of a source, enabled or not. It
+// returns db.ErrNotFound when the source has none.
+func GetDropTableBySource(kind string, sourceID uint32) (*DropTable, error) {
+	ctx := context.Background()
+	table, err := scanDropTable(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+dropTableColumns+`
+FROM drop_tables
+WHERE kind = $1
+  AND source_id = $2
+`, kind, int64(sourceID)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	tables := []DropTable{table}
+	if err := loadDropTableEntries(ctx, tables); err != nil {
+		return nil, err
+	}
+	return &tables[0], nil
+}
+
+// ListEnabledDropTables returns the enabled tables of kind among sourceIDs.
+func ListEnabledDropTables(kind string, sourceIDs []uint32) ([]DropTable, error) {
+	if len(sourceIDs) == 0 {
+		return []DropTable{}, nil
+	}
+	ctx := context.Background()
+	ids := make([]int64, 0, len(sourceIDs))
+	for _, sourceID := range sourceIDs {
+		ids = append(ids, int64(sourceID))
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+dropTableColumns+`
+FROM drop_tables
+WHERE kind = $1
+  AND source_id = ANY($2)
+  AND enabled
+ORDER BY source_id ASC
+`, kind, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables, err := scanDropTables(rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := loadDropTableEntries(ctx, tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// ListDropTables returns the tables of kind, every table when kind is empty.
+func ListDropTables(kind string, offset int, limit int) ([]DropTable, int64, error) {
+	ctx := context.Background()
+	offset, limit, unlimited := normalizePagination(offset, limit)
+
+	var total int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM drop_tables
+WHERE ($1 = '' OR kind = $1)
+`, kind).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	query := `
+SELECT ` + dropTableColumns + `
+FROM drop_tables
+WHERE ($1 = '' OR kind = $1)
+ORDER BY kind ASC, source_id ASC
+OFFSET $2
+`
+	args := []any{kind, int64(offset)}
+	if !unlimited {
+		query += `LIMIT $3`
+		args = append(args, int64(limit))
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	tables, err := scanDropTables(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+	if err := loadDropTableEntries(ctx, tables); err != nil {
+		return nil, 0, err
+	}
+	return tables, total, nil
+}
+
+func DeleteDropTable(tableID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM drop_tables WHERE id = $1`, int64(tableID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+const dropBonusColumns = `id, name, kind, source_id, rate, starts_at, ends_at, created_at`
+
+func scanDropBonuses(rows pgx.Rows) ([]DropBonus, error) {
+	bonuses := make([]DropBonus, 0)
+	for rows.Next() {
+		var bonus DropBonus
+		if err := rows.Scan(&bonus.ID, &bonus.Name, &bonus.Kind, &bonus.SourceID, &bonus.Rate, &bonus.StartsAt, &bonus.EndsAt, &bonus.CreatedAt); err != nil {
+			return nil, err
+		}
+		bonuses = append(bonuses, bonus)
+	}
+	return bonuses, rows.Err()
+}
+
+func CreateDropBonus(bonus *DropBonus) error {
+	ctx := context.Background()
+	var sourceID *int64
+	if bonus.SourceID != nil {
+		value := int64(*bonus.SourceID)
+		sourceID = &value
+	}
+	return db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO drop_bonuses (name, kind, source_id, rate, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`, bonus.Name, bonus.Kind, sourceID, int64(bonus.Rate), bonus.StartsAt, bonus.EndsAt).Scan(&bonus.ID, &bonus.CreatedAt)
+}
+
+// ListDropBonuses returns every bonus, the most recent first.
+func ListDropBonuses() ([]DropBonus, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+dropBonusColumns+`
+FROM drop_bonuses
+ORDER BY id DESC
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDropBonuses(rows)
+}
+
+// ListActiveDropBonuses returns the bonuses running at now.
+func ListActiveDropBonuses(now time.Time) ([]DropBonus, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+dropBonusColumns+`
+FROM drop_bonuses
+WHERE (starts_at IS NULL OR starts_at <= $1)
+  AND (ends_at IS NULL OR ends_at > $1)
+ORDER BY id ASC
+`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDropBonuses(rows)
+}
+
+func DeleteDropBonus(bonusID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM drop_bonuses WHERE id = $1`, int64(bonusID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
@@ -115,12 +115,26 @@ func (f *Fleet) UpdateShipList(owner *Commander, ships []uint32) error {
 	return nil
 }
 
-func (f *Fleet) AddMeowfficer(shipID uint32) error {
-	panic("not implemented")
-}
-
-func (f *Fleet) RemoveMeowfficer(shipID uint32) error {
-	panic("not implemented")
+// Updates the meowfficers of the fleet, meowfficers[i] being the one at
+// position i+1 (0 for none)
+func (f *Fleet) UpdateMeowfficerList(owner *Commander, meowfficers []uint32) error {
+	f.MeowfficerList = ToInt64List(meowfficers)
+	ctx := context.Background()
+	meowJSON, err := json.Marshal(f.MeowfficerList)
+	if err != nil {
+		return err
+	}
+	if err := db.DefaultStore.Queries.UpdateFleetMeowfficerList(ctx, gen.UpdateFleetMeowfficerListParams{ID: int64(f.ID), MeowfficerList: meowJSON}); err != nil {
+		return err
+	}
+	owner.FleetsMap[f.GameID] = f
+	for i, fleet := range owner.Fleets {
+		if fleet.ID == f.ID {
+			owner.Fleets[i] = *f
+			break
+		}
+	}
+	return nil
 }
 
 func DeleteFleetByCommanderAndGameID(commanderID uint32, gameID uint32) error {
@@ -0,0 +1,500 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	fleetTechGroupCategory    = "ShareCfg/fleet_tech_group.json"
+	fleetTechTemplateCategory = "ShareCfg/fleet_tech_template.json"
+	fleetTechShipCategory     = "ShareCfg/fleet_tech_ship_template.json"
+
+	// FleetTechMaxShipLevel is the ship level granting the level bonus of
+	// its group.
+	FleetTechMaxShipLevel = uint32(120)
+)
+
+// FleetTech is the progress of a commander in a fleet technology group.
+// EffectTechID is the template of the level reached, StudyTechID the one
+// being studied until StudyFinishTime, and RewardedTech the last level
+// whose rewards were claimed.
+type FleetTech struct {
+	CommanderID     uint32
+	GroupID         uint32
+	EffectTechID    uint32
+	StudyTechID     uint32
+	StudyFinishTime uint32
+	RewardedTech    uint32
+}
+
+// FleetTechSet caps the value of a stat bonus for a hull type below the
+// value the commander unlocked.
+type FleetTechSet struct {
+	ShipType uint32
+	AttrType uint32
+	Value    uint32
+}
+
+// FleetTechAttr is a stat bonus granted to a hull type.
+type FleetTechAttr struct {
+	ShipType uint32
+	AttrType uint32
+	Value    uint32
+}
+
+// FleetTechGroup is a fleet technology group; only ships of nation count
+// towards its points, every ship when nation is 0.
+type FleetTechGroup struct {
+	ID     uint32 `json:"id"`
+	Nation uint32 `json:"nation"`
+}
+
+// FleetTechTemplate is a level of a group. pt is the number of tech points
+// of the group required to study it, time the study duration, add the
+// [[ship types], attr, value] bonuses it grants and rewards a list of
+// [type, id, count] claimable once it is reached.
+type FleetTechTemplate struct {
+	ID      uint32          `json:"id"`
+	Group   uint32          `json:"group"`
+	Level   uint32          `json:"level"`
+	Pt      uint32          `json:"pt"`
+	Time    uint32          `json:"time"`
+	Add     json.RawMessage `json:"add"`
+	Rewards [][]uint32      `json:"rewards"`
+}
+
+// FleetTechShipConfig is the tech points and bonuses a ship group (the
+// group_type of its templates) yields once obtained, once a ship of the
+// group is fully limit broken and once one reaches level 120.
+type FleetTechShipConfig struct {
+	ID               uint32   `json:"id"`
+	Nation           uint32   `json:"nation"`
+	PtGet            uint32   `json:"pt_get"`
+	PtUpgrade        uint32   `json:"pt_upgrage"`
+	PtLevel          uint32   `json:"pt_level"`
+	AddGetShipType   []uint32 `json:"add_get_shiptype"`
+	AddGetAttr       uint32   `json:"add_get_attr"`
+	AddGetValue      uint32   `json:"add_get_value"`
+	AddLevelShipType []uint32 `json:"add_level_shiptype"`
+	AddLevelAttr     uint32   `json:"add_level_attr"`
+	AddLevelValue    uint32   `json:"add_level_value"`
+}
+
+// Effects decodes add, skipping malformed entries.
+func (template *FleetTechTemplate) Effects() []FleetTechAttr {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(template.Add, &entries); err != nil {
+		return nil
+	}
+	effects := []FleetTechAttr{}
+	for _, raw := range entries {
+		var parts []json.RawMessage
+		if err := json.Unmarshal(raw, &parts); err != nil || len(parts) < 3 {
+			continue
+		}
+		var shipTypes []uint32
+		var attr, value uint32
+		if json.Unmarshal(parts[0], &shipTypes) != nil || json.Unmarshal(parts[1], &attr) != nil || json.Unmarshal(parts[2], &value) != nil {
+			continue
+		}
+		for _, shipType := range shipTypes {
+			effects = append(effects, FleetTechAttr{ShipType: shipType, AttrType: attr, Value: value})
+		}
+	}
+	return effects
+}
+
+type FleetTechCatalog struct {
+	Groups    []FleetTechGroup
+	Templates map[uint32]FleetTechTemplate
+	byGroup   map[uint32][]FleetTechTemplate
+}
+
+func LoadFleetTechCatalog() (*FleetTechCatalog, error) {
+	catalog := &FleetTechCatalog{
+		Templates: make(map[uint32]FleetTechTemplate),
+		byGroup:   make(map[uint32][]FleetTechTemplate),
+	}
+	groups, err := ListConfigEntries(fleetTechGroupCategory)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range groups {
+		var group FleetTechGroup
+		if err := json.Unmarshal(entry.Data, &group); err != nil {
+			return nil, err
+		}
+		catalog.Groups = append(catalog.Groups, group)
+	}
+	sort.Slice(catalog.Groups, func(i, j int) bool { return catalog.Groups[i].ID < catalog.Groups[j].ID })
+	templates, err := ListConfigEntries(fleetTechTemplateCategory)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range templates {
+		var template FleetTechTemplate
+		if err := json.Unmarshal(entry.Data, &template); err != nil {
+			return nil, err
+		}
+		if template.ID == 0 || template.Group == 0 {
+			continue
+		}
+		catalog.Templates[template.ID] = template
+		catalog.byGroup[template.Group] = append(catalog.byGroup[template.Group], template)
+	}
+	for group := range catalog.byGroup {
+		levels := catalog.byGroup[group]
+		sort.Slice(levels, func(i, j int) bool { return levels[i].Level < levels[j].Level })
+	}
+	return catalog, nil
+}
+
+func (catalog *FleetTechCatalog) Group(groupID uint32) (*FleetTechGroup, bool) {
+	for i := range catalog.Groups {
+		if catalog.Groups[i].ID == groupID {
+			return &catalog.Groups[i], true
+		}
+	}
+	return nil, false
+}
+
+// Level returns the level reached by techID in its group, 0 for none.
+func (catalog *FleetTechCatalog) Level(techID uint32) uint32 {
+	return catalog.Templates[techID].Level
+}
+
+// NextLevel returns the template following techID in groupID.
+func (catalog *FleetTechCatalog) NextLevel(groupID uint32, techID uint32) (*FleetTechTemplate, bool) {
+	level := catalog.Level(techID)
+	for i := range catalog.byGroup[groupID] {
+		if catalog.byGroup[groupID][i].Level > level {
+			return &catalog.byGroup[groupID][i], true
+		}
+	}
+	return nil, false
+}
+
+// LevelsUpTo returns the templates of groupID up to the level of techID.
+func (catalog *FleetTechCatalog) LevelsUpTo(groupID uint32, techID uint32) []FleetTechTemplate {
+	if techID == 0 {
+		return nil
+	}
+	level := catalog.Level(techID)
+	levels := []FleetTechTemplate{}
+	for _, template := range catalog.byGroup[groupID] {
+		if template.Level <= level {
+			levels = append(levels, template)
+		}
+	}
+	return levels
+}
+
+func ListFleetTechs(commanderID uint32) ([]FleetTech, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, group_id, effect_tech_id, study_tech_id, study_finish_time, rewarded_tech
+FROM commander_fleet_techs
+WHERE commander_id = $1
+ORDER BY group_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	techs := make([]FleetTech, 0)
+	for rows.Next() {
+		var tech FleetTech
+		if err := rows.Scan(&tech.CommanderID, &tech.GroupID, &tech.EffectTechID, &tech.StudyTechID, &tech.StudyFinishTime, &tech.RewardedTech); err != nil {
+			return nil, err
+		}
+		techs = append(techs, tech)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return techs, nil
+}
+
+// GetFleetTech returns the progress of groupID, blank when nothing was
+// studied in it yet.
+func GetFleetTech(commanderID uint32, groupID uint32) (*FleetTech, error) {
+	ctx := context.Background()
+	tech := FleetTech{CommanderID: commanderID, GroupID: groupID}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT effect_tech_id, study_tech_id, study_finish_time, rewarded_tech
+FROM commander_fleet_techs
+WHERE commander_id = $1 AND group_id = $2
+`, int64(commanderID), int64(groupID)).Scan(&tech.EffectTechID, &tech.StudyTechID, &tech.StudyFinishTime, &tech.RewardedTech)
+	err = db.MapNotFound(err)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	return &tech, nil
+}
+
+func SaveFleetTechTx(ctx context.Context, tx pgx.Tx, tech *FleetTech) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_fleet_techs (commander_id, group_id, effect_tech_id, study_tech_id, study_finish_time, rewarded_tech)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (commander_id, group_id)
+DO UPDATE SET
+  effect_tech_id = EXCLUDED.effect_tech_id,
+  study_tech_id = EXCLUDED.study_tech_id,
+  study_finish_time = EXCLUDED.study_finish_time,
+  rewarded_tech = EXCLUDED.rewarded_tech
+`, int64(tech.CommanderID), int64(tech.GroupID), int64(tech.EffectTechID), int64(tech.StudyTechID), int64(tech.StudyFinishTime), int64(tech.RewardedTech))
+	return err
+}
+
+func SaveFleetTech(tech *FleetTech) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveFleetTechTx(ctx, tx, tech)
+	})
+}
+
+func ListFleetTechSets(commanderID uint32) ([]FleetTechSet, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT ship_type, attr_type, set_value
+FROM commander_fleet_tech_sets
+WHERE commander_id = $1
+ORDER BY ship_type ASC, attr_type ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sets := make([]FleetTechSet, 0)
+	for rows.Next() {
+		var set FleetTechSet
+		if err := rows.Scan(&set.ShipType, &set.AttrType, &set.Value); err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// ReplaceFleetTechSets replaces every stat cap of the commander.
+func ReplaceFleetTechSets(commanderID uint32, sets []FleetTechSet) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM commander_fleet_tech_sets WHERE commander_id = $1`, int64(commanderID)); err != nil {
+			return err
+		}
+		for _, set := range sets {
+			if _, err := tx.Exec(ctx, `
+INSERT INTO commander_fleet_tech_sets (commander_id, ship_type, attr_type, set_value)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (commander_id, ship_type, attr_type) DO UPDATE SET set_value = EXCLUDED.set_value
+`, int64(commanderID), int64(set.ShipType), int64(set.AttrType), int64(set.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FleetTechCollection is what the ships of a commander contribute to fleet
+// technology: tech points per nation and stat bonuses per hull type and
+// attribute.
+type FleetTechCollection struct {
+	Points  map[uint32]uint32
+	Total   uint32
+	Bonuses map[uint32]map[uint32]uint32
+}
+
+// NationPoints returns the points of nation, the total for 0.
+func (collection *FleetTechCollection) NationPoints(nation uint32) uint32 {
+	if nation == 0 {
+		return collection.Total
+	}
+	return collection.Points[nation]
+}
+
+func (collection *FleetTechCollection) add(shipTypes []uint32, attr uint32, value uint32) {
+	if attr == 0 || value == 0 {
+		return
+	}
+	for _, shipType := range shipTypes {
+		if collection.Bonuses[shipType] == nil {
+			collection.Bonuses[shipType] = make(map[uint32]uint32)
+		}
+		collection.Bonuses[shipType][attr] += value
+	}
+}
+
+type fleetTechShipGroupProgress struct {
+	maxLimitBreak bool
+	maxLevel      bool
+}
+
+// LoadFleetTechCollection sums the tech points and bonuses of every ship
+// group the commander owns a ship of.
+func LoadFleetTechCollection(commanderID uint32) (*FleetTechCollection, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT ship_id, level
+FROM owned_ships
+WHERE owner_id = $1
+  AND deleted_at IS NULL
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	type ownedShipLevel struct {
+		templateID uint32
+		level      uint32
+	}
+	owned := []ownedShipLevel{}
+	for rows.Next() {
+		var ship ownedShipLevel
+		if err := rows.Scan(&ship.templateID, &ship.level); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		owned = append(owned, ship)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	collection := &FleetTechCollection{
+		Points:  make(map[uint32]uint32),
+		Bonuses: make(map[uint32]map[uint32]uint32),
+	}
+	if len(owned) == 0 {
+		return collection, nil
+	}
+	shipConfigs, err := ListConfigEntries(fleetTechShipCategory)
+	if err != nil {
+		return nil, err
+	}
+	if len(shipConfigs) == 0 {
+		return collection, nil
+	}
+	shipTemplates, err := ListConfigEntries(shipDataTemplateCategory)
+	if err != nil {
+		return nil, err
+	}
+	groupTypes := make(map[uint32]uint32, len(shipTemplates))
+	for _, entry := range shipTemplates {
+		var template ShipTemplateConfig
+		if err := json.Unmarshal(entry.Data, &template); err != nil {
+			return nil, err
+		}
+		groupTypes[template.ID] = template.GroupType
+	}
+	breakouts, err := ListConfigEntries(shipBreakoutCategory)
+	if err != nil {
+		return nil, err
+	}
+	maxBreakout := make(map[uint32]bool, len(breakouts))
+	for _, entry := range breakouts {
+		var breakout ShipBreakoutConfig
+		if err := json.Unmarshal(entry.Data, &breakout); err != nil {
+			return nil, err
+		}
+		maxBreakout[breakout.ID] = breakout.BreakoutID == 0
+	}
+
+	groups := make(map[uint32]*fleetTechShipGroupProgress)
+	for _, ship := range owned {
+		groupID := groupTypes[ship.templateID]
+		if groupID == 0 {
+			groupID = ship.templateID / 10
+		}
+		progress, ok := groups[groupID]
+		if !ok {
+			progress = &fleetTechShipGroupProgress{}
+			groups[groupID] = progress
+		}
+		if maxBreakout[ship.templateID] {
+			progress.maxLimitBreak = true
+		}
+		if ship.level >= FleetTechMaxShipLevel {
+			progress.maxLevel = true
+		}
+	}
+	for _, entry := range shipConfigs {
+		var config FleetTechShipConfig
+		if err := json.Unmarshal(entry.Data, &config); err != nil {
+			return nil, err
+		}
+		progress, ok := groups[config.ID]
+		if !ok {
+			continue
+		}
+		points := config.PtGet
+		collection.add(config.AddGetShipType, config.AddGetAttr, config.AddGetValue)
+		if progress.maxLimitBreak {
+			points += config.PtUpgrade
+		}
+		if progress.maxLevel {
+			points += config.PtLevel
+			collection.add(config.AddLevelShipType, config.AddLevelAttr, config.AddLevelValue)
+		}
+		collection.Points[config.Nation] += points
+		collection.Total += points
+	}
+	return collection, nil
+}
+
+// GetFleetTechUnlockedBonuses returns the stat bonuses per hull type and
+// attribute unlocked by the ship collection and the fleet technology levels
+// reached, before the caps of the commander apply.
+func GetFleetTechUnlockedBonuses(commanderID uint32) (map[uint32]map[uint32]uint32, error) {
+	collection, err := LoadFleetTechCollection(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	techs, err := ListFleetTechs(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(techs) == 0 {
+		return collection.Bonuses, nil
+	}
+	catalog, err := LoadFleetTechCatalog()
+	if err != nil {
+		return nil, err
+	}
+	for _, tech := range techs {
+		for _, template := range catalog.LevelsUpTo(tech.GroupID, tech.EffectTechID) {
+			for _, effect := range template.Effects() {
+				collection.add([]uint32{effect.ShipType}, effect.AttrType, effect.Value)
+			}
+		}
+	}
+	return collection.Bonuses, nil
+}
+
+// GetFleetTechBonuses returns the fleet technology stat bonuses applying to
+// the ships of the commander, by hull type then attribute.
+func GetFleetTechBonuses(commanderID uint32) (map[uint32]map[uint32]uint32, error) {
+	bonuses, err := GetFleetTechUnlockedBonuses(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := ListFleetTechSets(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		if value, ok := bonuses[set.ShipType][set.AttrType]; ok && set.Value < value {
+			bonuses[set.ShipType][set.AttrType] = set.Value
+		}
+	}
+	return bonuses, nil
+}
@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"testing"
+)
+
+func seedFleetTechConfig(t *testing.T, category string, key string, payload string) {
+	t.Helper()
+	if err := UpsertConfigEntry(category, key, []byte(payload)); err != nil {
+		t.Fatalf("seed %s: %v", category, err)
+	}
+}
+
+func TestFleetTechCollectionAndBonuses(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearTable(t, &ConfigEntry{})
+	clearTable(t, &Ship{})
+	seedFriendTestCommander(t, 9961, "Fleet Tech")
+
+	for _, templateID := range []uint32{99701, 99704, 99711} {
+		ship := Ship{TemplateID: templateID, Name: "Tech", EnglishName: "Tech", RarityID: 4, Star: 1, Type: 1, Nationality: 1, BuildTime: 10}
+		if err := ship.Create(); err != nil {
+			t.Fatalf("seed ship: %v", err)
+		}
+	}
+	seedFleetTechConfig(t, shipDataTemplateCategory, "99701", `{"id":99701,"group_type":9970}`)
+	seedFleetTechConfig(t, shipDataTemplateCategory, "99704", `{"id":99704,"group_type":9970}`)
+	seedFleetTechConfig(t, shipBreakoutCategory, "99701", `{"id":99701,"breakout_id":99702}`)
+	seedFleetTechConfig(t, shipBreakoutCategory, "99704", `{"id":99704,"breakout_id":0}`)
+	seedFleetTechConfig(t, fleetTechShipCategory, "9970", `{"id":9970,"nation":1,"pt_get":10,"pt_upgrage":5,"pt_level":20,"add_get_shiptype":[1,2],"add_get_attr":3,"add_get_value":1,"add_level_shiptype":[1],"add_level_attr":3,"add_level_value":2}`)
+	seedFleetTechConfig(t, fleetTechShipCategory, "9971", `{"id":9971,"nation":2,"pt_get":7,"add_get_shiptype":[1],"add_get_attr":4,"add_get_value":1}`)
+	seedFleetTechConfig(t, fleetTechGroupCategory, "1", `{"id":1,"nation":1}`)
+	seedFleetTechConfig(t, fleetTechTemplateCategory, "101", `{"id":101,"group":1,"level":1,"pt":10,"time":0,"add":[[[1],3,5]]}`)
+	seedFleetTechConfig(t, fleetTechTemplateCategory, "102", `{"id":102,"group":1,"level":2,"pt":20,"time":0,"add":[[[1],3,5]]}`)
+
+	commander := Commander{CommanderID: 9961}
+	if _, err := commander.AddShip(99701); err != nil {
+		t.Fatalf("add ship: %v", err)
+	}
+	if _, err := commander.AddShip(99711); err != nil {
+		t.Fatalf("add ship: %v", err)
+	}
+	collection, err := LoadFleetTechCollection(9961)
+	if err != nil {
+		t.Fatalf("load collection: %v", err)
+	}
+	if collection.NationPoints(1) != 10 || collection.NationPoints(2) != 7 || collection.Total != 17 {
+		t.Fatalf("unexpected points: %+v", collection)
+	}
+
+	maxed, err := commander.AddShip(99704)
+	if err != nil {
+		t.Fatalf("add ship: %v", err)
+	}
+	maxed.Level = FleetTechMaxShipLevel
+	if err := maxed.Update(); err != nil {
+		t.Fatalf("level ship: %v", err)
+	}
+	collection, err = LoadFleetTechCollection(9961)
+	if err != nil {
+		t.Fatalf("load collection: %v", err)
+	}
+	if collection.NationPoints(1) != 35 || collection.Bonuses[1][3] != 3 || collection.Bonuses[2][3] != 1 {
+		t.Fatalf("unexpected collection: %+v", collection)
+	}
+
+	if err := SaveFleetTech(&FleetTech{CommanderID: 9961, GroupID: 1, EffectTechID: 101}); err != nil {
+		t.Fatalf("save fleet tech: %v", err)
+	}
+	if err := ReplaceFleetTechSets(9961, []FleetTechSet{{ShipType: 2, AttrType: 3, Value: 0}}); err != nil {
+		t.Fatalf("save tech sets: %v", err)
+	}
+	bonuses, err := GetFleetTechBonuses(9961)
+	if err != nil {
+		t.Fatalf("get bonuses: %v", err)
+	}
+	if bonuses[1][3] != 8 || bonuses[1][4] != 1 || bonuses[2][3] != 0 {
+		t.Fatalf("unexpected bonuses: %+v", bonuses)
+	}
+}
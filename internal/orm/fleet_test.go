@@ -47,28 +47,14 @@ func TestFleetCreateAndUpdate(t *testing.T) {
 	if err := fleet.UpdateShipList(&commander, []uint32{999}); err == nil {
 		t.Fatalf("expected invalid ship id error")
 	}
+	if err := fleet.UpdateMeowfficerList(&commander, []uint32{7, 0}); err != nil {
+		t.Fatalf("update meowfficer list: %v", err)
+	}
+	if len(commander.FleetsMap[1].MeowfficerList) != 2 || commander.FleetsMap[1].MeowfficerList[0] != 7 {
+		t.Fatalf("unexpected meowfficer list: %v", commander.FleetsMap[1].MeowfficerList)
+	}
 
 	if err := CreateFleet(&commander, 2, "Bad", []uint32{999}); err == nil {
 		t.Fatalf("expected invalid ship id error")
 	}
 }
-
-func TestFleetMeowfficerPanics(t *testing.T) {
-	fleet := Fleet{}
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatalf("expected panic from add meowfficer")
-		}
-	}()
-	_ = fleet.AddMeowfficer(1)
-}
-
-func TestFleetRemoveMeowfficerPanics(t *testing.T) {
-	fleet := Fleet{}
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatalf("expected panic from remove meowfficer")
-		}
-	}()
-	_ = fleet.RemoveMeowfficer(1)
-}
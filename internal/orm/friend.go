@@ -0,0 +1,398 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+type CommanderFriend struct {
+	CommanderID uint32
+	FriendID    uint32
+	CreatedAt   time.Time
+}
+
+type CommanderFriendRequest struct {
+	SenderID   uint32
+	ReceiverID uint32
+	Content    string
+	CreatedAt  time.Time
+}
+
+type CommanderFriendBlock struct {
+	CommanderID uint32
+	BlockedID   uint32
+	CreatedAt   time.Time
+}
+
+// FriendProfile is the subset of a commander row needed to render friend,
+// request and blacklist entries.
+type FriendProfile struct {
+	CommanderID         uint32
+	Name                string
+	Level               uint32
+	Manifesto           string
+	LastLogin           time.Time
+	DisplayIconID       uint32
+	DisplaySkinID       uint32
+	SelectedIconFrameID uint32
+	SelectedChatFrameID uint32
+	DisplayIconThemeID  uint32
+	Since               time.Time
+	Content             string
+}
+
+var (
+	ErrFriendRequestExists = errors.New("friend request already exists")
+	ErrAlreadyFriends      = errors.New("commanders are already friends")
+)
+
+const friendProfileColumns = `c.commander_id, c.name, c.level, c.manifesto, c.last_login, c.display_icon_id, c.display_skin_id, c.selected_icon_frame_id, c.selected_chat_frame_id, c.display_icon_theme_id`
+
+func scanFriendProfiles(rows pgx.Rows, withContent bool) ([]FriendProfile, error) {
+	defer rows.Close()
+	profiles := make([]FriendProfile, 0)
+	for rows.Next() {
+		var profile FriendProfile
+		targets := []any{
+			&profile.CommanderID,
+			&profile.Name,
+			&profile.Level,
+			&profile.Manifesto,
+			&profile.LastLogin,
+			&profile.DisplayIconID,
+			&profile.DisplaySkinID,
+			&profile.SelectedIconFrameID,
+			&profile.SelectedChatFrameID,
+			&profile.DisplayIconThemeID,
+			&profile.Since,
+		}
+		if withContent {
+			targets = append(targets, &profile.Content)
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func ListCommanderFriends(commanderID uint32) ([]FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, f.created_at
+FROM commander_friends f
+JOIN commanders c ON c.commander_id = f.friend_id
+WHERE f.commander_id = $1
+  AND c.deleted_at IS NULL
+ORDER BY f.created_at ASC, c.commander_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	return scanFriendProfiles(rows, false)
+}
+
+func ListIncomingFriendRequests(commanderID uint32) ([]FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, r.created_at, r.content
+FROM commander_friend_requests r
+JOIN commanders c ON c.commander_id = r.sender_id
+WHERE r.receiver_id = $1
+  AND c.deleted_at IS NULL
+ORDER BY r.created_at ASC, c.commander_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	return scanFriendProfiles(rows, true)
+}
+
+func ListOutgoingFriendRequests(commanderID uint32) ([]FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, r.created_at, r.content
+FROM commander_friend_requests r
+JOIN commanders c ON c.commander_id = r.receiver_id
+WHERE r.sender_id = $1
+  AND c.deleted_at IS NULL
+ORDER BY r.created_at ASC, c.commander_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	return scanFriendProfiles(rows, true)
+}
+
+func ListCommanderBlocks(commanderID uint32) ([]FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, b.created_at
+FROM commander_friend_blocks b
+JOIN commanders c ON c.commander_id = b.blocked_id
+WHERE b.commander_id = $1
+  AND c.deleted_at IS NULL
+ORDER BY b.created_at ASC, c.commander_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	return scanFriendProfiles(rows, false)
+}
+
+// ListFriendCandidates returns commanders that are not already friends,
+// blocked or pending with commanderID, most recently active first.
+func ListFriendCandidates(commanderID uint32, limit int) ([]FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, c.last_login
+FROM commanders c
+WHERE c.commander_id <> $1
+  AND c.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM commander_friends f WHERE f.commander_id = $1 AND f.friend_id = c.commander_id)
+  AND NOT EXISTS (SELECT 1 FROM commander_friend_blocks b WHERE (b.commander_id = $1 AND b.blocked_id = c.commander_id) OR (b.commander_id = c.commander_id AND b.blocked_id = $1))
+  AND NOT EXISTS (SELECT 1 FROM commander_friend_requests r WHERE (r.sender_id = $1 AND r.receiver_id = c.commander_id) OR (r.sender_id = c.commander_id AND r.receiver_id = $1))
+ORDER BY c.last_login DESC, c.commander_id ASC
+LIMIT $2
+`, int64(commanderID), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	return scanFriendProfiles(rows, false)
+}
+
+func GetFriendProfile(commanderID uint32) (*FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, c.last_login
+FROM commanders c
+WHERE c.commander_id = $1
+  AND c.deleted_at IS NULL
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := scanFriendProfiles(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return &profiles[0], nil
+}
+
+func FindFriendProfileByName(name string) (*FriendProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+friendProfileColumns+`, c.last_login
+FROM commanders c
+WHERE lower(c.name) = lower($1)
+  AND c.deleted_at IS NULL
+ORDER BY c.commander_id ASC
+LIMIT 1
+`, name)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := scanFriendProfiles(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return &profiles[0], nil
+}
+
+func CountCommanderFriends(commanderID uint32) (int, error) {
+	ctx := context.Background()
+	var count int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM commander_friends
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&count)
+	return int(count), err
+}
+
+func AreFriends(commanderID uint32, otherID uint32) (bool, error) {
+	ctx := context.Background()
+	var exists bool
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM commander_friends WHERE commander_id = $1 AND friend_id = $2
+)
+`, int64(commanderID), int64(otherID)).Scan(&exists)
+	return exists, err
+}
+
+// IsBlockedBy reports whether blockerID has blacklisted commanderID.
+func IsBlockedBy(commanderID uint32, blockerID uint32) (bool, error) {
+	ctx := context.Background()
+	var exists bool
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM commander_friend_blocks WHERE commander_id = $1 AND blocked_id = $2
+)
+`, int64(blockerID), int64(commanderID)).Scan(&exists)
+	return exists, err
+}
+
+func FriendRequestExists(senderID uint32, receiverID uint32) (bool, error) {
+	ctx := context.Background()
+	var exists bool
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM commander_friend_requests WHERE sender_id = $1 AND receiver_id = $2
+)
+`, int64(senderID), int64(receiverID)).Scan(&exists)
+	return exists, err
+}
+
+func CreateFriendRequest(senderID uint32, receiverID uint32, content string) (*CommanderFriendRequest, error) {
+	ctx := context.Background()
+	request := CommanderFriendRequest{SenderID: senderID, ReceiverID: receiverID, Content: content}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO commander_friend_requests (sender_id, receiver_id, content, created_at)
+VALUES ($1, $2, $3, NOW())
+RETURNING created_at
+`, int64(senderID), int64(receiverID), content).Scan(&request.CreatedAt)
+	if err == nil {
+		return &request, nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrFriendRequestExists
+	}
+	return nil, err
+}
+
+func DeleteFriendRequest(senderID uint32, receiverID uint32) error {
+	ctx := context.Background()
+	res, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM commander_friend_requests
+WHERE sender_id = $1 AND receiver_id = $2
+`, int64(senderID), int64(receiverID))
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteIncomingFriendRequests clears every pending request addressed to
+// receiverID and returns the number of removed rows.
+func DeleteIncomingFriendRequests(receiverID uint32) (int64, error) {
+	ctx := context.Background()
+	res, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM commander_friend_requests
+WHERE receiver_id = $1
+`, int64(receiverID))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// CreateFriendshipTx links both commanders and drops any pending request
+// between them, in either direction.
+func CreateFriendshipTx(ctx context.Context, tx pgx.Tx, commanderID uint32, friendID uint32) error {
+	res, err := tx.Exec(ctx, `
+INSERT INTO commander_friends (commander_id, friend_id, created_at)
+VALUES ($1, $2, NOW()), ($2, $1, NOW())
+ON CONFLICT (commander_id, friend_id) DO NOTHING
+`, int64(commanderID), int64(friendID))
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrAlreadyFriends
+	}
+	_, err = tx.Exec(ctx, `
+DELETE FROM commander_friend_requests
+WHERE (sender_id = $1 AND receiver_id = $2) OR (sender_id = $2 AND receiver_id = $1)
+`, int64(commanderID), int64(friendID))
+	return err
+}
+
+func CreateFriendship(commanderID uint32, friendID uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return CreateFriendshipTx(ctx, tx, commanderID, friendID)
+	})
+}
+
+func DeleteFriendshipTx(ctx context.Context, tx pgx.Tx, commanderID uint32, friendID uint32) error {
+	res, err := tx.Exec(ctx, `
+DELETE FROM commander_friends
+WHERE (commander_id = $1 AND friend_id = $2) OR (commander_id = $2 AND friend_id = $1)
+`, int64(commanderID), int64(friendID))
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func DeleteFriendship(commanderID uint32, friendID uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return DeleteFriendshipTx(ctx, tx, commanderID, friendID)
+	})
+}
+
+// BlockCommander blacklists blockedID for commanderID. Any friendship or
+// pending request between them is removed in the same transaction.
+func BlockCommander(commanderID uint32, blockedID uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO commander_friend_blocks (commander_id, blocked_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (commander_id, blocked_id) DO NOTHING
+`, int64(commanderID), int64(blockedID)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+DELETE FROM commander_friends
+WHERE (commander_id = $1 AND friend_id = $2) OR (commander_id = $2 AND friend_id = $1)
+`, int64(commanderID), int64(blockedID)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+DELETE FROM commander_friend_requests
+WHERE (sender_id = $1 AND receiver_id = $2) OR (sender_id = $2 AND receiver_id = $1)
+`, int64(commanderID), int64(blockedID))
+		return err
+	})
+}
+
+func UnblockCommander(commanderID uint32, blockedID uint32) error {
+	ctx := context.Background()
+	res, err := db.DefaultStore.Pool.Exec(ctx, `
+DELETE FROM commander_friend_blocks
+WHERE commander_id = $1 AND blocked_id = $2
+`, int64(commanderID), int64(blockedID))
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func seedFriendTestCommander(t *testing.T, commanderID uint32, name string) {
+	t.Helper()
+	if _, err := db.DefaultStore.Pool.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE commander_id = $1", QualifiedTable("commanders")), int64(commanderID)); err != nil {
+		t.Fatalf("delete commander: %v", err)
+	}
+	if err := CreateCommanderRoot(commanderID, commanderID, name, 0, 0); err != nil {
+		t.Fatalf("create commander root: %v", err)
+	}
+}
+
+func TestFriendRequestsAndFriendships(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9901, "Friend ORM A")
+	seedFriendTestCommander(t, 9902, "Friend ORM B")
+
+	if _, err := CreateFriendRequest(9901, 9902, "hi"); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	if _, err := CreateFriendRequest(9901, 9902, "hi again"); !errors.Is(err, ErrFriendRequestExists) {
+		t.Fatalf("expected ErrFriendRequestExists, got %v", err)
+	}
+	incoming, err := ListIncomingFriendRequests(9902)
+	if err != nil {
+		t.Fatalf("list incoming: %v", err)
+	}
+	if len(incoming) != 1 || incoming[0].CommanderID != 9901 || incoming[0].Content != "hi" {
+		t.Fatalf("unexpected incoming requests: %+v", incoming)
+	}
+
+	if err := CreateFriendship(9902, 9901); err != nil {
+		t.Fatalf("create friendship: %v", err)
+	}
+	if err := CreateFriendship(9901, 9902); !errors.Is(err, ErrAlreadyFriends) {
+		t.Fatalf("expected ErrAlreadyFriends, got %v", err)
+	}
+	exists, err := FriendRequestExists(9901, 9902)
+	if err != nil {
+		t.Fatalf("request exists: %v", err)
+	}
+	if exists {
+		t.Fatalf("expected accepted request to be removed")
+	}
+	count, err := CountCommanderFriends(9901)
+	if err != nil {
+		t.Fatalf("count friends: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 friend, got %d", count)
+	}
+	friends, err := ListCommanderFriends(9902)
+	if err != nil {
+		t.Fatalf("list friends: %v", err)
+	}
+	if len(friends) != 1 || friends[0].Name != "Friend ORM A" {
+		t.Fatalf("unexpected friends: %+v", friends)
+	}
+
+	if err := DeleteFriendship(9901, 9902); err != nil {
+		t.Fatalf("delete friendship: %v", err)
+	}
+	if err := DeleteFriendship(9901, 9902); !db.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestFriendBlockRemovesRelations(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9903, "Friend ORM C")
+	seedFriendTestCommander(t, 9904, "Friend ORM D")
+
+	if err := CreateFriendship(9903, 9904); err != nil {
+		t.Fatalf("create friendship: %v", err)
+	}
+	if _, err := CreateFriendRequest(9904, 9903, ""); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+	if err := BlockCommander(9903, 9904); err != nil {
+		t.Fatalf("block commander: %v", err)
+	}
+	friends, err := AreFriends(9903, 9904)
+	if err != nil {
+		t.Fatalf("are friends: %v", err)
+	}
+	if friends {
+		t.Fatalf("expected friendship removed")
+	}
+	blocked, err := IsBlockedBy(9904, 9903)
+	if err != nil {
+		t.Fatalf("is blocked: %v", err)
+	}
+	if !blocked {
+		t.Fatalf("expected 9904 to be blocked by 9903")
+	}
+	blocks, err := ListCommanderBlocks(9903)
+	if err != nil {
+		t.Fatalf("list blocks: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].CommanderID != 9904 {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+	if err := UnblockCommander(9903, 9904); err != nil {
+		t.Fatalf("unblock: %v", err)
+	}
+	if err := UnblockCommander(9903, 9904); !db.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// GameDataChecksum is the digest of the files a game data table was last
+// imported from.
+type GameDataChecksum struct {
+	TableName string
+	Checksum  string
+	UpdatedAt time.Time
+}
+
+func ListGameDataChecksums() ([]GameDataChecksum, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT table_name, checksum, updated_at
+FROM game_data_checksums
+ORDER BY table_name
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	checksums := []GameDataChecksum{}
+	for rows.Next() {
+		var checksum GameDataChecksum
+		if err := rows.Scan(&checksum.TableName, &checksum.Checksum, &checksum.UpdatedAt); err != nil {
+			return nil, err
+		}
+		checksums = append(checksums, checksum)
+	}
+	return checksums, rows.Err()
+}
+
+func SaveGameDataChecksum(tableName string, checksum string) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+INSERT INTO game_data_checksums (table_name, checksum, updated_at)
+VALUES ($1, $2, CURRENT_TIMESTAMP)
+ON CONFLICT (table_name)
+DO UPDATE SET checksum = EXCLUDED.checksum, updated_at = EXCLUDED.updated_at
+`, tableName, checksum)
+	return err
+}
@@ -0,0 +1,645 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	GuildDutyCommander = uint32(1)
+	GuildDutyDeputy    = uint32(2)
+	GuildDutyPicked    = uint32(3)
+	GuildDutyOrdinary  = uint32(4)
+)
+
+const (
+	GuildLogCreate  = uint32(1)
+	GuildLogJoin    = uint32(2)
+	GuildLogQuit    = uint32(3)
+	GuildLogKick    = uint32(4)
+	GuildLogDuty    = uint32(5)
+	GuildLogImpeach = uint32(6)
+)
+
+const GuildDefaultCapacity = uint32(30)
+
+var (
+	ErrGuildNameTaken         = errors.New("guild name already taken")
+	ErrAlreadyInGuild         = errors.New("commander already belongs to a guild")
+	ErrGuildFull              = errors.New("guild is full")
+	ErrGuildApplicationExists = errors.New("guild application already exists")
+	ErrGuildImpeachCooldown   = errors.New("guild leader was impeached recently")
+)
+
+type Guild struct {
+	ID                 uint32
+	Name               string
+	Faction            uint32
+	Policy             uint32
+	Level              uint32
+	Exp                uint32
+	Capacity           uint32
+	Announce           string
+	Manifesto          string
+	ChangeFactionCD    uint32
+	KickLeaderCD       uint32
+	CreatedAt          time.Time
+	MemberCount        uint32
+	Capital            uint32
+	TechGroup          uint32
+	TechCancelCnt      uint32
+	WeeklyTaskProgress uint32
+	WeeklyTaskWeek     uint32
+	ActiveEventCnt     uint32
+}
+
+type GuildMember struct {
+	GuildID      uint32
+	CommanderID  uint32
+	Duty         uint32
+	Liveness     uint32
+	Contribution uint32
+	JoinedAt     time.Time
+}
+
+// GuildMemberProfile joins a membership (or an application) with the
+// commander fields the client renders in member and request lists.
+type GuildMemberProfile struct {
+	GuildMember
+	Name                string
+	Level               uint32
+	Manifesto           string
+	LastLogin           time.Time
+	DisplayIconID       uint32
+	DisplaySkinID       uint32
+	SelectedIconFrameID uint32
+	SelectedChatFrameID uint32
+	DisplayIconThemeID  uint32
+	Content             string
+}
+
+type GuildApplication struct {
+	GuildID     uint32
+	CommanderID uint32
+	Content     string
+	CreatedAt   time.Time
+}
+
+type GuildLog struct {
+	ID          uint32
+	GuildID     uint32
+	Cmd         uint32
+	CommanderID uint32
+	Name        string
+	Arg1        uint32
+	CreatedAt   time.Time
+}
+
+const guildColumns = `g.id, g.name, g.faction, g.policy, g.level, g.exp, g.capacity, g.announce, g.manifesto, g.change_faction_cd, g.kick_leader_cd, g.created_at,
+	(SELECT COUNT(*) FROM guild_members m WHERE m.guild_id = g.id),
+	g.capital, g.tech_group, g.tech_cancel_cnt, g.weekly_task_progress, g.weekly_task_week, g.active_event_cnt`
+
+const guildMemberProfileColumns = `c.commander_id, c.name, c.level, c.manifesto, c.last_login, c.display_icon_id, c.display_skin_id, c.selected_icon_frame_id, c.selected_chat_frame_id, c.display_icon_theme_id`
+
+func scanGuild(scanner rowScanner) (Guild, error) {
+	var guild Guild
+	err := scanner.Scan(
+		&guild.ID,
+		&guild.Name,
+		&guild.Faction,
+		&guild.Policy,
+		&guild.Level,
+		&guild.Exp,
+		&guild.Capacity,
+		&guild.Announce,
+		&guild.Manifesto,
+		&guild.ChangeFactionCD,
+		&guild.KickLeaderCD,
+		&guild.CreatedAt,
+		&guild.MemberCount,
+		&guild.Capital,
+		&guild.TechGroup,
+		&guild.TechCancelCnt,
+		&guild.WeeklyTaskProgress,
+		&guild.WeeklyTaskWeek,
+		&guild.ActiveEventCnt,
+	)
+	return guild, err
+}
+
+func scanGuilds(rows pgx.Rows) ([]Guild, error) {
+	defer rows.Close()
+	guilds := make([]Guild, 0)
+	for rows.Next() {
+		guild, err := scanGuild(rows)
+		if err != nil {
+			return nil, err
+		}
+		guilds = append(guilds, guild)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+func scanGuildMemberProfiles(rows pgx.Rows, withContent bool) ([]GuildMemberProfile, error) {
+	defer rows.Close()
+	profiles := make([]GuildMemberProfile, 0)
+	for rows.Next() {
+		var profile GuildMemberProfile
+		targets := []any{
+			&profile.CommanderID,
+			&profile.Name,
+			&profile.Level,
+			&profile.Manifesto,
+			&profile.LastLogin,
+			&profile.DisplayIconID,
+			&profile.DisplaySkinID,
+			&profile.SelectedIconFrameID,
+			&profile.SelectedChatFrameID,
+			&profile.DisplayIconThemeID,
+			&profile.GuildID,
+			&profile.Duty,
+			&profile.Liveness,
+			&profile.Contribution,
+			&profile.JoinedAt,
+		}
+		if withContent {
+			targets = append(targets, &profile.Content)
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func mapGuildUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case "idx_guilds_name":
+		return ErrGuildNameTaken
+	case "guild_members_pkey":
+		return ErrAlreadyInGuild
+	case "guild_applications_pkey":
+		return ErrGuildApplicationExists
+	}
+	return err
+}
+
+func ListGuilds(offset int, limit int) ([]Guild, int64, error) {
+	ctx := context.Background()
+	offset, limit, unlimited := normalizePagination(offset, limit)
+
+	var total int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM guilds`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	query := `
+SELECT ` + guildColumns + `
+FROM guilds g
+ORDER BY g.id ASC
+OFFSET $1
+`
+	args := []any{int64(offset)}
+	if !unlimited {
+		query += `LIMIT $2`
+		args = append(args, int64(limit))
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	guilds, err := scanGuilds(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return guilds, total, nil
+}
+
+// ListRecommendedGuilds returns guilds that still have room, busiest first.
+func ListRecommendedGuilds(limit int) ([]Guild, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+guildColumns+`
+FROM guilds g
+WHERE (SELECT COUNT(*) FROM guild_members m WHERE m.guild_id = g.id) < g.capacity
+ORDER BY (SELECT COUNT(*) FROM guild_members m WHERE m.guild_id = g.id) DESC, g.id ASC
+LIMIT $1
+`, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuilds(rows)
+}
+
+// SearchGuildsByName matches guild names case-insensitively.
+func SearchGuildsByName(keyword string, limit int) ([]Guild, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+guildColumns+`
+FROM guilds g
+WHERE g.name ILIKE '%' || $1 || '%'
+ORDER BY g.id ASC
+LIMIT $2
+`, keyword, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuilds(rows)
+}
+
+func GetGuild(guildID uint32) (*Guild, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+guildColumns+`
+FROM guilds g
+WHERE g.id = $1
+`, int64(guildID))
+	guild, err := scanGuild(row)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &guild, nil
+}
+
+// CreateGuild inserts guild and makes leaderID its commander. guild.ID is
+// filled in on success.
+func CreateGuild(guild *Guild, leaderID uint32, leaderName string) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return CreateGuildTx(ctx, tx, guild, leaderID, leaderName)
+	})
+}
+
+// CreateGuildTx creates a guild led by leaderID within tx, so the caller can
+// charge its cost in the same transaction.
+func CreateGuildTx(ctx context.Context, tx pgx.Tx, guild *Guild, leaderID uint32, leaderName string) error {
+	if guild.Capacity == 0 {
+		guild.Capacity = GuildDefaultCapacity
+	}
+	if guild.Level == 0 {
+		guild.Level = 1
+	}
+	var id int64
+	if err := tx.QueryRow(ctx, `
+INSERT INTO guilds (name, faction, policy, level, exp, capacity, announce, manifesto)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at
+`, guild.Name, int64(guild.Faction), int64(guild.Policy), int64(guild.Level), int64(guild.Exp), int64(guild.Capacity), guild.Announce, guild.Manifesto).Scan(&id, &guild.CreatedAt); err != nil {
+		return mapGuildUniqueViolation(err)
+	}
+	guild.ID = uint32(id)
+	if _, err := tx.Exec(ctx, `
+INSERT INTO guild_members (commander_id, guild_id, duty, joined_at)
+VALUES ($1, $2, $3, NOW())
+`, int64(leaderID), id, int64(GuildDutyCommander)); err != nil {
+		return mapGuildUniqueViolation(err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM guild_applications WHERE commander_id = $1`, int64(leaderID)); err != nil {
+		return err
+	}
+	if err := AddGuildLogTx(ctx, tx, guild.ID, GuildLogCreate, leaderID, leaderName, 0); err != nil {
+		return err
+	}
+	guild.MemberCount = 1
+	return nil
+}
+
+func UpdateGuild(guild *Guild) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE guilds
+SET name = $2,
+	faction = $3,
+	policy = $4,
+	level = $5,
+	exp = $6,
+	capacity = $7,
+	announce = $8,
+	manifesto = $9,
+	change_faction_cd = $10,
+	kick_leader_cd = $11
+WHERE id = $1
+`, int64(guild.ID), guild.Name, int64(guild.Faction), int64(guild.Policy), int64(guild.Level), int64(guild.Exp), int64(guild.Capacity), guild.Announce, guild.Manifesto, int64(guild.ChangeFactionCD), int64(guild.KickLeaderCD))
+	if err != nil {
+		return mapGuildUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteGuild dissolves a guild; members, applications and logs cascade.
+func DeleteGuild(guildID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM guilds WHERE id = $1`, int64(guildID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// GetGuildMember returns the membership of commanderID, or db.ErrNotFound
+// when the commander is not in a guild.
+func GetGuildMember(commanderID uint32) (*GuildMember, error) {
+	ctx := context.Background()
+	var member GuildMember
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT guild_id, commander_id, duty, liveness, contribution, joined_at
+FROM guild_members
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&member.GuildID, &member.CommanderID, &member.Duty, &member.Liveness, &member.Contribution, &member.JoinedAt)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func ListGuildMembers(guildID uint32) ([]GuildMemberProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+guildMemberProfileColumns+`, m.guild_id, m.duty, m.liveness, m.contribution, m.joined_at
+FROM guild_members m
+JOIN commanders c ON c.commander_id = m.commander_id
+WHERE m.guild_id = $1
+ORDER BY m.duty ASC, m.joined_at ASC, m.commander_id ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuildMemberProfiles(rows, false)
+}
+
+func ListGuildMemberIDs(guildID uint32) ([]uint32, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `SELECT commander_id FROM guild_members WHERE guild_id = $1 ORDER BY commander_id ASC`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := make([]uint32, 0)
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func GetGuildLeader(guildID uint32) (*GuildMemberProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+guildMemberProfileColumns+`, m.guild_id, m.duty, m.liveness, m.contribution, m.joined_at
+FROM guild_members m
+JOIN commanders c ON c.commander_id = m.commander_id
+WHERE m.guild_id = $1 AND m.duty = $2
+LIMIT 1
+`, int64(guildID), int64(GuildDutyCommander))
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := scanGuildMemberProfiles(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return &profiles[0], nil
+}
+
+// AddGuildMemberTx inserts a membership if the guild still has room and
+// drops every pending application of the commander.
+func AddGuildMemberTx(ctx context.Context, tx pgx.Tx, guildID uint32, commanderID uint32, duty uint32) error {
+	var capacity, count int64
+	if err := tx.QueryRow(ctx, `SELECT capacity FROM guilds WHERE id = $1 FOR UPDATE`, int64(guildID)).Scan(&capacity); err != nil {
+		return db.MapNotFound(err)
+	}
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM guild_members WHERE guild_id = $1`, int64(guildID)).Scan(&count); err != nil {
+		return err
+	}
+	if count >= capacity {
+		return ErrGuildFull
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO guild_members (commander_id, guild_id, duty, joined_at)
+VALUES ($1, $2, $3, NOW())
+`, int64(commanderID), int64(guildID), int64(duty)); err != nil {
+		return mapGuildUniqueViolation(err)
+	}
+	_, err := tx.Exec(ctx, `DELETE FROM guild_applications WHERE commander_id = $1`, int64(commanderID))
+	return err
+}
+
+func AddGuildMember(guildID uint32, commanderID uint32, duty uint32, name string) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := AddGuildMemberTx(ctx, tx, guildID, commanderID, duty); err != nil {
+			return err
+		}
+		return AddGuildLogTx(ctx, tx, guildID, GuildLogJoin, commanderID, name, 0)
+	})
+}
+
+// AcceptGuildApplication consumes the application of commanderID and adds
+// them to the guild.
+func AcceptGuildApplication(guildID uint32, commanderID uint32, name string) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM guild_applications WHERE guild_id = $1 AND commander_id = $2`, int64(guildID), int64(commanderID))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return db.ErrNotFound
+		}
+		if err := AddGuildMemberTx(ctx, tx, guildID, commanderID, GuildDutyOrdinary); err != nil {
+			return err
+		}
+		return AddGuildLogTx(ctx, tx, guildID, GuildLogJoin, commanderID, name, 0)
+	})
+}
+
+// RemoveGuildMember deletes the membership and records cmd (quit or kick)
+// in the guild log.
+func RemoveGuildMember(guildID uint32, commanderID uint32, name string, cmd uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM guild_members WHERE guild_id = $1 AND commander_id = $2`, int64(guildID), int64(commanderID))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return db.ErrNotFound
+		}
+		return AddGuildLogTx(ctx, tx, guildID, cmd, commanderID, name, 0)
+	})
+}
+
+func setGuildMemberDutyTx(ctx context.Context, tx pgx.Tx, guildID uint32, commanderID uint32, duty uint32) error {
+	tag, err := tx.Exec(ctx, `UPDATE guild_members SET duty = $3 WHERE guild_id = $1 AND commander_id = $2`, int64(guildID), int64(commanderID), int64(duty))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// SetGuildMemberDuty changes a member's duty. Promoting someone to
+// commander demotes the current commander to an ordinary member.
+func SetGuildMemberDuty(guildID uint32, commanderID uint32, duty uint32, name string) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if duty == GuildDutyCommander {
+			if _, err := tx.Exec(ctx, `UPDATE guild_members SET duty = $2 WHERE guild_id = $1 AND duty = $3`, int64(guildID), int64(GuildDutyOrdinary), int64(GuildDutyCommander)); err != nil {
+				return err
+			}
+		}
+		if err := setGuildMemberDutyTx(ctx, tx, guildID, commanderID, duty); err != nil {
+			return err
+		}
+		return AddGuildLogTx(ctx, tx, guildID, GuildLogDuty, commanderID, name, duty)
+	})
+}
+
+// ImpeachGuildLeader hands the guild over to newLeaderID and demotes the
+// previous commander. The guild cannot impeach again until cooldownUntil;
+// ErrGuildImpeachCooldown is returned while a previous cooldown runs.
+func ImpeachGuildLeader(guildID uint32, leaderID uint32, newLeaderID uint32, name string, now uint32, cooldownUntil uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+UPDATE guilds
+SET kick_leader_cd = $2
+WHERE id = $1 AND kick_leader_cd <= $3
+`, int64(guildID), int64(cooldownUntil), int64(now))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrGuildImpeachCooldown
+		}
+		if err := setGuildMemberDutyTx(ctx, tx, guildID, leaderID, GuildDutyOrdinary); err != nil {
+			return err
+		}
+		if err := setGuildMemberDutyTx(ctx, tx, guildID, newLeaderID, GuildDutyCommander); err != nil {
+			return err
+		}
+		return AddGuildLogTx(ctx, tx, guildID, GuildLogImpeach, newLeaderID, name, leaderID)
+	})
+}
+
+func CreateGuildApplication(guildID uint32, commanderID uint32, content string) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+INSERT INTO guild_applications (guild_id, commander_id, content, created_at)
+VALUES ($1, $2, $3, NOW())
+`, int64(guildID), int64(commanderID), content)
+	return mapGuildUniqueViolation(err)
+}
+
+func DeleteGuildApplication(guildID uint32, commanderID uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `DELETE FROM guild_applications WHERE guild_id = $1 AND commander_id = $2`, int64(guildID), int64(commanderID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ListGuildApplications returns pending applications with applicant
+// profiles. JoinedAt carries the application time.
+func ListGuildApplications(guildID uint32) ([]GuildMemberProfile, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+guildMemberProfileColumns+`, a.guild_id, 0, 0, 0, a.created_at, a.content
+FROM guild_applications a
+JOIN commanders c ON c.commander_id = a.commander_id
+WHERE a.guild_id = $1
+ORDER BY a.created_at ASC, a.commander_id ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuildMemberProfiles(rows, true)
+}
+
+func CountGuildApplications(guildID uint32) (uint32, error) {
+	ctx := context.Background()
+	var count int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM guild_applications WHERE guild_id = $1`, int64(guildID)).Scan(&count); err != nil {
+		return 0, err
+	}
+	return uint32(count), nil
+}
+
+func AddGuildLogTx(ctx context.Context, tx pgx.Tx, guildID uint32, cmd uint32, commanderID uint32, name string, arg1 uint32) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO guild_logs (guild_id, cmd, commander_id, name, arg1, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+`, int64(guildID), int64(cmd), int64(commanderID), name, int64(arg1))
+	return err
+}
+
+// ListGuildLogs returns the newest limit entries, oldest first.
+func ListGuildLogs(guildID uint32, limit int) ([]GuildLog, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT id, guild_id, cmd, commander_id, name, arg1, created_at
+FROM (
+	SELECT id, guild_id, cmd, commander_id, name, arg1, created_at
+	FROM guild_logs
+	WHERE guild_id = $1
+	ORDER BY created_at DESC, id DESC
+	LIMIT $2
+) recent
+ORDER BY created_at ASC, id ASC
+`, int64(guildID), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	logs := make([]GuildLog, 0)
+	for rows.Next() {
+		var entry GuildLog
+		if err := rows.Scan(&entry.ID, &entry.GuildID, &entry.Cmd, &entry.CommanderID, &entry.Name, &entry.Arg1, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
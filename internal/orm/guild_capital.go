@@ -0,0 +1,171 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	GuildCapitalLogDonate    = uint32(1)
+	GuildCapitalLogTech      = uint32(2)
+	GuildCapitalLogOperation = uint32(3)
+	GuildCapitalLogAdmin     = uint32(4)
+)
+
+var ErrGuildCapitalNotEnough = errors.New("guild capital not enough")
+
+type GuildCapitalLog struct {
+	ID          uint32
+	GuildID     uint32
+	CommanderID uint32
+	Name        string
+	EventType   uint32
+	EventTarget Int64List
+	Amount      int64
+	CreatedAt   time.Time
+}
+
+// CommanderGuildState holds the per-commander guild counters. It outlives a
+// membership so leaving and rejoining does not reset the weekly donations.
+type CommanderGuildState struct {
+	CommanderID    uint32
+	DonateCount    uint32
+	DonateWeek     uint32
+	DonateTasks    Int64List
+	WeeklyTaskFlag uint32
+}
+
+// AddGuildCapitalTx applies delta to the guild capital and records it in the
+// capital log. Spending more than the guild owns returns
+// ErrGuildCapitalNotEnough.
+func AddGuildCapitalTx(ctx context.Context, tx pgx.Tx, guildID uint32, delta int64, commanderID uint32, name string, eventType uint32, targets []uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE guilds
+SET capital = capital + $2
+WHERE id = $1 AND capital + $2 >= 0
+`, int64(guildID), delta)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM guilds WHERE id = $1)`, int64(guildID)).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return db.ErrNotFound
+		}
+		return ErrGuildCapitalNotEnough
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO guild_capital_logs (guild_id, commander_id, name, event_type, event_target, amount, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+`, int64(guildID), int64(commanderID), name, int64(eventType), ToInt64List(targets), delta)
+	return err
+}
+
+func AddGuildCapital(guildID uint32, delta int64, commanderID uint32, name string, eventType uint32, targets []uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return AddGuildCapitalTx(ctx, tx, guildID, delta, commanderID, name, eventType, targets)
+	})
+}
+
+// ListGuildCapitalLogs returns the newest limit entries, newest first.
+func ListGuildCapitalLogs(guildID uint32, limit int) ([]GuildCapitalLog, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT id, guild_id, commander_id, name, event_type, event_target, amount, created_at
+FROM guild_capital_logs
+WHERE guild_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`, int64(guildID), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	logs := make([]GuildCapitalLog, 0)
+	for rows.Next() {
+		var log GuildCapitalLog
+		if err := rows.Scan(&log.ID, &log.GuildID, &log.CommanderID, &log.Name, &log.EventType, &log.EventTarget, &log.Amount, &log.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func AddGuildMemberContributionTx(ctx context.Context, tx pgx.Tx, guildID uint32, commanderID uint32, amount uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE guild_members
+SET contribution = contribution + $3
+WHERE guild_id = $1 AND commander_id = $2
+`, int64(guildID), int64(commanderID), int64(amount))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// AdvanceGuildWeeklyTaskTx adds amount to the weekly task progress, starting
+// over when week (the Monday 00:00 UTC timestamp) moved on.
+func AdvanceGuildWeeklyTaskTx(ctx context.Context, tx pgx.Tx, guildID uint32, week uint32, amount uint32) (uint32, error) {
+	var progress uint32
+	err := tx.QueryRow(ctx, `
+UPDATE guilds
+SET weekly_task_progress = CASE WHEN weekly_task_week = $2 THEN weekly_task_progress + $3 ELSE $3 END,
+	weekly_task_week = $2
+WHERE id = $1
+RETURNING weekly_task_progress
+`, int64(guildID), int64(week), int64(amount)).Scan(&progress)
+	return progress, db.MapNotFound(err)
+}
+
+// GetCommanderGuildState returns the guild counters of commanderID, or a
+// zero state when none were stored yet.
+func GetCommanderGuildState(commanderID uint32) (*CommanderGuildState, error) {
+	ctx := context.Background()
+	state := CommanderGuildState{CommanderID: commanderID, DonateTasks: Int64List{}}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT donate_count, donate_week, donate_tasks, weekly_task_flag
+FROM commander_guild_states
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&state.DonateCount, &state.DonateWeek, &state.DonateTasks, &state.WeeklyTaskFlag)
+	err = db.MapNotFound(err)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func SaveCommanderGuildStateTx(ctx context.Context, tx pgx.Tx, state *CommanderGuildState) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_guild_states (commander_id, donate_count, donate_week, donate_tasks, weekly_task_flag)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (commander_id) DO UPDATE
+SET donate_count = EXCLUDED.donate_count,
+	donate_week = EXCLUDED.donate_week,
+	donate_tasks = EXCLUDED.donate_tasks,
+	weekly_task_flag = EXCLUDED.weekly_task_flag
+`, int64(state.CommanderID), int64(state.DonateCount), int64(state.DonateWeek), state.DonateTasks, int64(state.WeeklyTaskFlag))
+	return err
+}
+
+func SaveCommanderGuildState(state *CommanderGuildState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveCommanderGuildStateTx(ctx, tx, state)
+	})
+}
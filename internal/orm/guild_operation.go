@@ -0,0 +1,315 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+var ErrGuildShipDispatched = errors.New("ship already dispatched to a guild event")
+
+// GuildOperation is the operation a guild is currently running, including
+// the shared boss state.
+type GuildOperation struct {
+	GuildID     uint32
+	OperationID uint32
+	StartTime   uint32
+	BossID      uint32
+	BossHP      uint32
+	BossDamage  uint32
+}
+
+// GuildOperationEvent is one base event of the running operation. An event
+// is unlocked once a member dispatched ships, and its nodes are cleared
+// progressively until CompleteTime.
+type GuildOperationEvent struct {
+	GuildID      uint32
+	EventID      uint32
+	Position     uint32
+	StartTime    uint32
+	CompleteTime uint32
+}
+
+type GuildOperationShip struct {
+	EventID     uint32
+	CommanderID uint32
+	ShipID      uint32
+}
+
+type GuildOperationDamage struct {
+	CommanderID uint32
+	Damage      uint32
+}
+
+type GuildAssaultShip struct {
+	CommanderID uint32
+	Pos         uint32
+	ShipID      uint32
+	UpdatedAt   time.Time
+}
+
+// StartGuildOperation spends cost capital and replaces any previous
+// operation state of the guild with op and its events.
+func StartGuildOperation(op *GuildOperation, events []GuildOperationEvent, commanderID uint32, name string, cost uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if cost > 0 {
+			if err := AddGuildCapitalTx(ctx, tx, op.GuildID, -int64(cost), commanderID, name, GuildCapitalLogOperation, []uint32{op.OperationID}); err != nil {
+				return err
+			}
+		}
+		for _, table := range []string{"guild_operation_event_ships", "guild_operation_events", "guild_operation_damages", "guild_operations"} {
+			if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE guild_id = $1`, int64(op.GuildID)); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(ctx, `
+INSERT INTO guild_operations (guild_id, operation_id, start_time, boss_id, boss_hp, boss_damage)
+VALUES ($1, $2, $3, $4, $5, 0)
+`, int64(op.GuildID), int64(op.OperationID), int64(op.StartTime), int64(op.BossID), int64(op.BossHP)); err != nil {
+			return err
+		}
+		for _, event := range events {
+			if _, err := tx.Exec(ctx, `
+INSERT INTO guild_operation_events (guild_id, event_id, position, start_time, complete_time)
+VALUES ($1, $2, $3, 0, 0)
+`, int64(op.GuildID), int64(event.EventID), int64(event.Position)); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, `UPDATE guilds SET active_event_cnt = active_event_cnt + 1 WHERE id = $1`, int64(op.GuildID))
+		return err
+	})
+}
+
+// GetGuildOperation returns the running operation, or db.ErrNotFound.
+func GetGuildOperation(guildID uint32) (*GuildOperation, error) {
+	ctx := context.Background()
+	op := GuildOperation{GuildID: guildID}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT operation_id, start_time, boss_id, boss_hp, boss_damage
+FROM guild_operations
+WHERE guild_id = $1
+`, int64(guildID)).Scan(&op.OperationID, &op.StartTime, &op.BossID, &op.BossHP, &op.BossDamage)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+func ListGuildOperationEvents(guildID uint32) ([]GuildOperationEvent, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT guild_id, event_id, position, start_time, complete_time
+FROM guild_operation_events
+WHERE guild_id = $1
+ORDER BY position ASC, event_id ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]GuildOperationEvent, 0)
+	for rows.Next() {
+		var event GuildOperationEvent
+		if err := rows.Scan(&event.GuildID, &event.EventID, &event.Position, &event.StartTime, &event.CompleteTime); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func ListGuildOperationShips(guildID uint32) ([]GuildOperationShip, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT event_id, commander_id, ship_id
+FROM guild_operation_event_ships
+WHERE guild_id = $1
+ORDER BY event_id ASC, commander_id ASC, ship_id ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ships := make([]GuildOperationShip, 0)
+	for rows.Next() {
+		var ship GuildOperationShip
+		if err := rows.Scan(&ship.EventID, &ship.CommanderID, &ship.ShipID); err != nil {
+			return nil, err
+		}
+		ships = append(ships, ship)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ships, nil
+}
+
+// JoinGuildOperationEvent dispatches shipIDs of commanderID to an event. The
+// first dispatch starts the event clock; later ones leave it untouched.
+func JoinGuildOperationEvent(guildID uint32, eventID uint32, commanderID uint32, shipIDs []uint32, startTime uint32, completeTime uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+UPDATE guild_operation_events
+SET start_time = $3,
+	complete_time = $4
+WHERE guild_id = $1 AND event_id = $2 AND start_time = 0
+`, int64(guildID), int64(eventID), int64(startTime), int64(completeTime))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM guild_operation_events WHERE guild_id = $1 AND event_id = $2)`, int64(guildID), int64(eventID)).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return db.ErrNotFound
+			}
+		}
+		for _, shipID := range shipIDs {
+			if _, err := tx.Exec(ctx, `
+INSERT INTO guild_operation_event_ships (guild_id, event_id, commander_id, ship_id)
+VALUES ($1, $2, $3, $4)
+`, int64(guildID), int64(eventID), int64(commanderID), int64(shipID)); err != nil {
+				if IsUniqueViolation(err) {
+					return ErrGuildShipDispatched
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddGuildBossDamage records damage dealt by commanderID to the operation
+// boss. The shared damage is capped at the boss HP.
+func AddGuildBossDamage(guildID uint32, commanderID uint32, damage uint32) (*GuildOperation, error) {
+	ctx := context.Background()
+	op := GuildOperation{GuildID: guildID}
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+UPDATE guild_operations
+SET boss_damage = LEAST(boss_hp, boss_damage + $2)
+WHERE guild_id = $1
+RETURNING operation_id, start_time, boss_id, boss_hp, boss_damage
+`, int64(guildID), int64(damage)).Scan(&op.OperationID, &op.StartTime, &op.BossID, &op.BossHP, &op.BossDamage); err != nil {
+			return db.MapNotFound(err)
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO guild_operation_damages (guild_id, commander_id, damage)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id, commander_id) DO UPDATE
+SET damage = guild_operation_damages.damage + EXCLUDED.damage
+`, int64(guildID), int64(commanderID), int64(damage))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// ListGuildOperationDamages returns the boss damage ranking, highest first.
+func ListGuildOperationDamages(guildID uint32) ([]GuildOperationDamage, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, damage
+FROM guild_operation_damages
+WHERE guild_id = $1
+ORDER BY damage DESC, commander_id ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	damages := make([]GuildOperationDamage, 0)
+	for rows.Next() {
+		var damage GuildOperationDamage
+		if err := rows.Scan(&damage.CommanderID, &damage.Damage); err != nil {
+			return nil, err
+		}
+		damages = append(damages, damage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return damages, nil
+}
+
+// SetGuildAssaultShips replaces the assault fleet commanderID lends to
+// their guild.
+func SetGuildAssaultShips(commanderID uint32, ships []GuildAssaultShip) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM guild_assault_ships WHERE commander_id = $1`, int64(commanderID)); err != nil {
+			return err
+		}
+		for _, ship := range ships {
+			if _, err := tx.Exec(ctx, `
+INSERT INTO guild_assault_ships (commander_id, pos, ship_id, updated_at)
+VALUES ($1, $2, $3, NOW())
+`, int64(commanderID), int64(ship.Pos), int64(ship.ShipID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func ListGuildAssaultShips(commanderID uint32) ([]GuildAssaultShip, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, pos, ship_id, updated_at
+FROM guild_assault_ships
+WHERE commander_id = $1
+ORDER BY pos ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuildAssaultShips(rows)
+}
+
+// ListGuildAssaultShipsByGuild returns the assault fleets of every current
+// member of guildID.
+func ListGuildAssaultShipsByGuild(guildID uint32) ([]GuildAssaultShip, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT a.commander_id, a.pos, a.ship_id, a.updated_at
+FROM guild_assault_ships a
+JOIN guild_members m ON m.commander_id = a.commander_id
+WHERE m.guild_id = $1
+ORDER BY a.commander_id ASC, a.pos ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	return scanGuildAssaultShips(rows)
+}
+
+func scanGuildAssaultShips(rows pgx.Rows) ([]GuildAssaultShip, error) {
+	defer rows.Close()
+	ships := make([]GuildAssaultShip, 0)
+	for rows.Next() {
+		var ship GuildAssaultShip
+		if err := rows.Scan(&ship.CommanderID, &ship.Pos, &ship.ShipID, &ship.UpdatedAt); err != nil {
+			return nil, err
+		}
+		ships = append(ships, ship)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ships, nil
+}
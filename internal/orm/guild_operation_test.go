@@ -0,0 +1,159 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGuildCapitalAndResearch(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearGuildTestData(t)
+	seedFriendTestCommander(t, 9921, "Guild ORM Capital")
+
+	guild := Guild{Name: "Guild ORM Capital", Faction: 1, Policy: 1}
+	if err := CreateGuild(&guild, 9921, "Guild ORM Capital"); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	if err := AddGuildCapital(guild.ID, 120, 9921, "Guild ORM Capital", GuildCapitalLogDonate, []uint32{3}); err != nil {
+		t.Fatalf("add capital: %v", err)
+	}
+	if err := AddGuildCapital(guild.ID, -200, 9921, "Guild ORM Capital", GuildCapitalLogOperation, nil); !errors.Is(err, ErrGuildCapitalNotEnough) {
+		t.Fatalf("expected ErrGuildCapitalNotEnough, got %v", err)
+	}
+	if err := AddGuildCapital(guild.ID, -20, 9921, "Guild ORM Capital", GuildCapitalLogOperation, []uint32{1}); err != nil {
+		t.Fatalf("spend capital: %v", err)
+	}
+	loaded, err := GetGuild(guild.ID)
+	if err != nil {
+		t.Fatalf("load guild: %v", err)
+	}
+	if loaded.Capital != 100 {
+		t.Fatalf("expected capital 100, got %d", loaded.Capital)
+	}
+	logs, err := ListGuildCapitalLogs(guild.ID, 10)
+	if err != nil {
+		t.Fatalf("list capital logs: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Amount != -20 || logs[1].Amount != 120 || len(logs[1].EventTarget) != 1 {
+		t.Fatalf("unexpected capital logs: %+v", logs)
+	}
+
+	if err := SetGuildResearch(guild.ID, 4, false); err != nil {
+		t.Fatalf("set research: %v", err)
+	}
+	if err := SaveGuildTechnology(&GuildTechnology{GuildID: guild.ID, Group: 4, Level: 2, Progress: 15}); err != nil {
+		t.Fatalf("save technology: %v", err)
+	}
+	if err := SetGuildResearch(guild.ID, 0, true); err != nil {
+		t.Fatalf("cancel research: %v", err)
+	}
+	loaded, err = GetGuild(guild.ID)
+	if err != nil {
+		t.Fatalf("load guild: %v", err)
+	}
+	if loaded.TechGroup != 0 || loaded.TechCancelCnt != 1 {
+		t.Fatalf("unexpected research state: %+v", loaded)
+	}
+	techs, err := ListGuildTechnologies(guild.ID)
+	if err != nil {
+		t.Fatalf("list technologies: %v", err)
+	}
+	if len(techs) != 1 || techs[0].Level != 2 || techs[0].Progress != 15 {
+		t.Fatalf("unexpected technologies: %+v", techs)
+	}
+
+	state, err := GetCommanderGuildState(9921)
+	if err != nil {
+		t.Fatalf("get guild state: %v", err)
+	}
+	state.DonateCount = 2
+	state.DonateTasks = ToInt64List([]uint32{1, 2, 3})
+	if err := SaveCommanderGuildState(state); err != nil {
+		t.Fatalf("save guild state: %v", err)
+	}
+	state, err = GetCommanderGuildState(9921)
+	if err != nil {
+		t.Fatalf("reload guild state: %v", err)
+	}
+	if state.DonateCount != 2 || len(state.DonateTasks) != 3 {
+		t.Fatalf("unexpected guild state: %+v", state)
+	}
+}
+
+func TestGuildOperationProgress(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearGuildTestData(t)
+	seedFriendTestCommander(t, 9931, "Guild ORM Operation")
+
+	guild := Guild{Name: "Guild ORM Operation", Faction: 1, Policy: 1}
+	if err := CreateGuild(&guild, 9931, "Guild ORM Operation"); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	op := GuildOperation{GuildID: guild.ID, OperationID: 1, StartTime: 100, BossID: 7, BossHP: 500}
+	events := []GuildOperationEvent{{EventID: 11, Position: 1}, {EventID: 12, Position: 2}}
+	if err := StartGuildOperation(&op, events, 9931, "Guild ORM Operation", 50); !errors.Is(err, ErrGuildCapitalNotEnough) {
+		t.Fatalf("expected ErrGuildCapitalNotEnough, got %v", err)
+	}
+	if err := StartGuildOperation(&op, events, 9931, "Guild ORM Operation", 0); err != nil {
+		t.Fatalf("start operation: %v", err)
+	}
+	if err := JoinGuildOperationEvent(guild.ID, 11, 9931, []uint32{1, 2}, 200, 800); err != nil {
+		t.Fatalf("join event: %v", err)
+	}
+	if err := JoinGuildOperationEvent(guild.ID, 12, 9931, []uint32{2}, 300, 900); !errors.Is(err, ErrGuildShipDispatched) {
+		t.Fatalf("expected ErrGuildShipDispatched, got %v", err)
+	}
+	if err := JoinGuildOperationEvent(guild.ID, 99, 9931, []uint32{3}, 300, 900); err == nil {
+		t.Fatalf("expected missing event to fail")
+	}
+	loadedEvents, err := ListGuildOperationEvents(guild.ID)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(loadedEvents) != 2 || loadedEvents[0].StartTime != 200 || loadedEvents[0].CompleteTime != 800 || loadedEvents[1].StartTime != 0 {
+		t.Fatalf("unexpected events: %+v", loadedEvents)
+	}
+	ships, err := ListGuildOperationShips(guild.ID)
+	if err != nil {
+		t.Fatalf("list ships: %v", err)
+	}
+	if len(ships) != 2 {
+		t.Fatalf("expected 2 dispatched ships, got %+v", ships)
+	}
+
+	updated, err := AddGuildBossDamage(guild.ID, 9931, 300)
+	if err != nil {
+		t.Fatalf("add damage: %v", err)
+	}
+	if updated.BossDamage != 300 {
+		t.Fatalf("expected damage 300, got %d", updated.BossDamage)
+	}
+	updated, err = AddGuildBossDamage(guild.ID, 9931, 300)
+	if err != nil {
+		t.Fatalf("add damage: %v", err)
+	}
+	if updated.BossDamage != 500 {
+		t.Fatalf("expected damage capped at 500, got %d", updated.BossDamage)
+	}
+	damages, err := ListGuildOperationDamages(guild.ID)
+	if err != nil {
+		t.Fatalf("list damages: %v", err)
+	}
+	if len(damages) != 1 || damages[0].Damage != 600 {
+		t.Fatalf("unexpected damages: %+v", damages)
+	}
+
+	if err := SetGuildAssaultShips(9931, []GuildAssaultShip{{Pos: 1, ShipID: 5}}); err != nil {
+		t.Fatalf("set assault ships: %v", err)
+	}
+	assault, err := ListGuildAssaultShipsByGuild(guild.ID)
+	if err != nil {
+		t.Fatalf("list assault ships: %v", err)
+	}
+	if len(assault) != 1 || assault[0].ShipID != 5 {
+		t.Fatalf("unexpected assault ships: %+v", assault)
+	}
+	if err := SetGuildAssaultShips(9931, nil); err != nil {
+		t.Fatalf("clear assault ships: %v", err)
+	}
+}
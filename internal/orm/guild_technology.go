@@ -0,0 +1,143 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// GuildTechnology is the guild-wide research state of one technology group.
+type GuildTechnology struct {
+	GuildID  uint32
+	Group    uint32
+	Level    uint32
+	Progress uint32
+}
+
+// CommanderGuildTechnology is the level a commander personally unlocked in
+// a technology group; it is what the bonuses are read from.
+type CommanderGuildTechnology struct {
+	CommanderID uint32
+	Group       uint32
+	Level       uint32
+}
+
+func ListGuildTechnologies(guildID uint32) ([]GuildTechnology, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT guild_id, tech_group, level, progress
+FROM guild_technologies
+WHERE guild_id = $1
+ORDER BY tech_group ASC
+`, int64(guildID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	techs := make([]GuildTechnology, 0)
+	for rows.Next() {
+		var tech GuildTechnology
+		if err := rows.Scan(&tech.GuildID, &tech.Group, &tech.Level, &tech.Progress); err != nil {
+			return nil, err
+		}
+		techs = append(techs, tech)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return techs, nil
+}
+
+func GetGuildTechnologyTx(ctx context.Context, tx pgx.Tx, guildID uint32, group uint32) (*GuildTechnology, error) {
+	tech := GuildTechnology{GuildID: guildID, Group: group}
+	err := tx.QueryRow(ctx, `
+SELECT level, progress
+FROM guild_technologies
+WHERE guild_id = $1 AND tech_group = $2
+FOR UPDATE
+`, int64(guildID), int64(group)).Scan(&tech.Level, &tech.Progress)
+	err = db.MapNotFound(err)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	return &tech, nil
+}
+
+func SaveGuildTechnologyTx(ctx context.Context, tx pgx.Tx, tech *GuildTechnology) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO guild_technologies (guild_id, tech_group, level, progress)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (guild_id, tech_group) DO UPDATE
+SET level = EXCLUDED.level,
+	progress = EXCLUDED.progress
+`, int64(tech.GuildID), int64(tech.Group), int64(tech.Level), int64(tech.Progress))
+	return err
+}
+
+func SaveGuildTechnology(tech *GuildTechnology) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveGuildTechnologyTx(ctx, tx, tech)
+	})
+}
+
+// SetGuildResearch selects the technology group the guild is researching.
+// Clearing an active group counts as a cancellation.
+func SetGuildResearch(guildID uint32, group uint32, cancelled bool) error {
+	ctx := context.Background()
+	cancelDelta := int64(0)
+	if cancelled {
+		cancelDelta = 1
+	}
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE guilds
+SET tech_group = $2,
+	tech_cancel_cnt = tech_cancel_cnt + $3
+WHERE id = $1
+`, int64(guildID), int64(group), cancelDelta)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func ListCommanderGuildTechnologies(commanderID uint32) ([]CommanderGuildTechnology, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, tech_group, level
+FROM commander_guild_technologies
+WHERE commander_id = $1
+ORDER BY tech_group ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	techs := make([]CommanderGuildTechnology, 0)
+	for rows.Next() {
+		var tech CommanderGuildTechnology
+		if err := rows.Scan(&tech.CommanderID, &tech.Group, &tech.Level); err != nil {
+			return nil, err
+		}
+		techs = append(techs, tech)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return techs, nil
+}
+
+func SetCommanderGuildTechnologyTx(ctx context.Context, tx pgx.Tx, commanderID uint32, group uint32, level uint32) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_guild_technologies (commander_id, tech_group, level)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, tech_group) DO UPDATE
+SET level = EXCLUDED.level
+`, int64(commanderID), int64(group), int64(level))
+	return err
+}
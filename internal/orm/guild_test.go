@@ -0,0 +1,160 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func clearGuildTestData(t *testing.T) {
+	t.Helper()
+	if _, err := db.DefaultStore.Pool.Exec(context.Background(), `DELETE FROM guilds WHERE name LIKE 'Guild ORM%'`); err != nil {
+		t.Fatalf("delete guilds: %v", err)
+	}
+}
+
+func TestGuildLifecycle(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearGuildTestData(t)
+	seedFriendTestCommander(t, 9911, "Guild ORM Leader")
+	seedFriendTestCommander(t, 9912, "Guild ORM Member")
+	seedFriendTestCommander(t, 9913, "Guild ORM Other")
+
+	guild := Guild{Name: "Guild ORM One", Faction: 1, Policy: 1, Manifesto: "hello"}
+	if err := CreateGuild(&guild, 9911, "Guild ORM Leader"); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	if guild.ID == 0 || guild.Capacity != GuildDefaultCapacity || guild.Level != 1 {
+		t.Fatalf("unexpected guild defaults: %+v", guild)
+	}
+	duplicate := Guild{Name: "guild orm one"}
+	if err := CreateGuild(&duplicate, 9913, "Guild ORM Other"); !errors.Is(err, ErrGuildNameTaken) {
+		t.Fatalf("expected ErrGuildNameTaken, got %v", err)
+	}
+	other := Guild{Name: "Guild ORM Two"}
+	if err := CreateGuild(&other, 9911, "Guild ORM Leader"); !errors.Is(err, ErrAlreadyInGuild) {
+		t.Fatalf("expected ErrAlreadyInGuild, got %v", err)
+	}
+
+	if err := CreateGuildApplication(guild.ID, 9912, "let me in"); err != nil {
+		t.Fatalf("create application: %v", err)
+	}
+	if err := CreateGuildApplication(guild.ID, 9912, "again"); !errors.Is(err, ErrGuildApplicationExists) {
+		t.Fatalf("expected ErrGuildApplicationExists, got %v", err)
+	}
+	applications, err := ListGuildApplications(guild.ID)
+	if err != nil {
+		t.Fatalf("list applications: %v", err)
+	}
+	if len(applications) != 1 || applications[0].CommanderID != 9912 || applications[0].Content != "let me in" {
+		t.Fatalf("unexpected applications: %+v", applications)
+	}
+	if err := AcceptGuildApplication(guild.ID, 9912, "Guild ORM Member"); err != nil {
+		t.Fatalf("accept application: %v", err)
+	}
+	count, err := CountGuildApplications(guild.ID)
+	if err != nil {
+		t.Fatalf("count applications: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected accepted application to be removed, got %d", count)
+	}
+	loaded, err := GetGuild(guild.ID)
+	if err != nil {
+		t.Fatalf("get guild: %v", err)
+	}
+	if loaded.MemberCount != 2 {
+		t.Fatalf("expected 2 members, got %d", loaded.MemberCount)
+	}
+
+	if err := SetGuildMemberDuty(guild.ID, 9912, GuildDutyCommander, "Guild ORM Member"); err != nil {
+		t.Fatalf("set duty: %v", err)
+	}
+	leader, err := GetGuildLeader(guild.ID)
+	if err != nil {
+		t.Fatalf("get leader: %v", err)
+	}
+	if leader.CommanderID != 9912 {
+		t.Fatalf("expected leadership to move to 9912, got %d", leader.CommanderID)
+	}
+	previous, err := GetGuildMember(9911)
+	if err != nil {
+		t.Fatalf("get previous leader: %v", err)
+	}
+	if previous.Duty != GuildDutyOrdinary {
+		t.Fatalf("expected previous leader to be demoted, got duty %d", previous.Duty)
+	}
+
+	if err := RemoveGuildMember(guild.ID, 9911, "Guild ORM Leader", GuildLogQuit); err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+	if _, err := GetGuildMember(9911); !db.IsNotFound(err) {
+		t.Fatalf("expected removed member to be gone, got %v", err)
+	}
+	logs, err := ListGuildLogs(guild.ID, 10)
+	if err != nil {
+		t.Fatalf("list logs: %v", err)
+	}
+	if len(logs) != 4 || logs[0].Cmd != GuildLogCreate || logs[len(logs)-1].Cmd != GuildLogQuit {
+		t.Fatalf("unexpected logs: %+v", logs)
+	}
+
+	if err := DeleteGuild(guild.ID); err != nil {
+		t.Fatalf("delete guild: %v", err)
+	}
+	if _, err := GetGuildMember(9912); !db.IsNotFound(err) {
+		t.Fatalf("expected memberships to cascade, got %v", err)
+	}
+}
+
+func TestGuildCapacityLimit(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearGuildTestData(t)
+	seedFriendTestCommander(t, 9914, "Guild ORM Small Leader")
+	seedFriendTestCommander(t, 9915, "Guild ORM Small Member")
+
+	guild := Guild{Name: "Guild ORM Small", Capacity: 1}
+	if err := CreateGuild(&guild, 9914, "Guild ORM Small Leader"); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	if err := AddGuildMember(guild.ID, 9915, GuildDutyOrdinary, "Guild ORM Small Member"); !errors.Is(err, ErrGuildFull) {
+		t.Fatalf("expected ErrGuildFull, got %v", err)
+	}
+	ids, err := ListGuildMemberIDs(guild.ID)
+	if err != nil {
+		t.Fatalf("list member ids: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 9914 {
+		t.Fatalf("unexpected member ids: %v", ids)
+	}
+}
+
+func TestImpeachGuildLeaderSetsCooldown(t *testing.T) {
+	initCommanderItemTestDB(t)
+	clearGuildTestData(t)
+	seedFriendTestCommander(t, 9916, "Guild ORM Impeach Leader")
+	seedFriendTestCommander(t, 9917, "Guild ORM Impeach Deputy")
+
+	guild := Guild{Name: "Guild ORM Impeach"}
+	if err := CreateGuild(&guild, 9916, "Guild ORM Impeach Leader"); err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	if err := AddGuildMember(guild.ID, 9917, GuildDutyDeputy, "Guild ORM Impeach Deputy"); err != nil {
+		t.Fatalf("add deputy: %v", err)
+	}
+	if err := ImpeachGuildLeader(guild.ID, 9916, 9917, "Guild ORM Impeach Deputy", 1000, 2000); err != nil {
+		t.Fatalf("impeach leader: %v", err)
+	}
+	loaded, err := GetGuild(guild.ID)
+	if err != nil {
+		t.Fatalf("load guild: %v", err)
+	}
+	if loaded.KickLeaderCD != 2000 {
+		t.Fatalf("expected impeach cooldown 2000, got %d", loaded.KickLeaderCD)
+	}
+	if err := ImpeachGuildLeader(guild.ID, 9917, 9916, "Guild ORM Impeach Leader", 1500, 2500); !errors.Is(err, ErrGuildImpeachCooldown) {
+		t.Fatalf("expected ErrGuildImpeachCooldown, got %v", err)
+	}
+}
@@ -0,0 +1,551 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// Meowfficer is a commander cat owned by a commander. Abilities are the
+// talents it currently has, AbilityOrigin the ones it was obtained with and
+// AbilityCandidates the talents offered by the last refresh, which can be
+// refreshed again once AbilityTime is reached. UsedPt is the sum of the
+// talent points spent on Abilities. RenameTime and the home times are unix
+// times of the last rename, feeding and play.
+type Meowfficer struct {
+	ID                uint32
+	CommanderID       uint32
+	TemplateID        uint32
+	Level             uint32
+	Exp               uint32
+	IsLocked          bool
+	Name              string
+	RenameTime        uint32
+	Abilities         Int64List
+	AbilityOrigin     Int64List
+	AbilityCandidates Int64List
+	AbilityTime       uint32
+	UsedPt            uint32
+	SkillID           uint32
+	SkillExp          uint32
+	HomeFeedTime      uint32
+	HomePlayTime      uint32
+	CreatedAt         time.Time
+}
+
+// MeowfficerBox is a meowfficer box of a pool. BeginTime and FinishTime
+// are 0 until the box is put in training, and the meowfficer can be taken
+// out of it once FinishTime is reached.
+type MeowfficerBox struct {
+	ID          uint32
+	CommanderID uint32
+	PoolID      uint32
+	BeginTime   uint32
+	FinishTime  uint32
+}
+
+// MeowfficerHome is the cat lodge of a commander. CleanTime is the unix
+// time of its last cleaning and UsageCount the number of boxes opened
+// since the last daily reset.
+type MeowfficerHome struct {
+	CommanderID      uint32
+	Level            uint32
+	Exp              uint32
+	CleanTime        uint32
+	UsageCount       uint32
+	LastDailyResetAt time.Time
+}
+
+// MeowfficerHomeSlot is a slot of the cat lodge. The meowfficer in it
+// trains since ExpTime.
+type MeowfficerHomeSlot struct {
+	CommanderID  uint32
+	SlotID       uint32
+	MeowfficerID uint32
+	Style        uint32
+	ExpTime      uint32
+}
+
+// MeowfficerPreset is a saved fleet assignment; Meowfficers holds the
+// meowfficer of each position, starting at position 1.
+type MeowfficerPreset struct {
+	CommanderID uint32
+	PresetID    uint32
+	Name        string
+	Meowfficers Int64List
+}
+
+const meowfficerColumns = `id, commander_id, template_id, level, exp, is_locked, name, rename_time, abilities, ability_origin, ability_candidates, ability_time, used_pt, skill_id, skill_exp, home_feed_time, home_play_time, created_at`
+
+func scanMeowfficer(scanner rowScanner) (Meowfficer, error) {
+	var meowfficer Meowfficer
+	err := scanner.Scan(
+		&meowfficer.ID,
+		&meowfficer.CommanderID,
+		&meowfficer.TemplateID,
+		&meowfficer.Level,
+		&meowfficer.Exp,
+		&meowfficer.IsLocked,
+		&meowfficer.Name,
+		&meowfficer.RenameTime,
+		&meowfficer.Abilities,
+		&meowfficer.AbilityOrigin,
+		&meowfficer.AbilityCandidates,
+		&meowfficer.AbilityTime,
+		&meowfficer.UsedPt,
+		&meowfficer.SkillID,
+		&meowfficer.SkillExp,
+		&meowfficer.HomeFeedTime,
+		&meowfficer.HomePlayTime,
+		&meowfficer.CreatedAt,
+	)
+	return meowfficer, err
+}
+
+func (meowfficer *Meowfficer) normalizeLists() {
+	if meowfficer.Abilities == nil {
+		meowfficer.Abilities = Int64List{}
+	}
+	if meowfficer.AbilityOrigin == nil {
+		meowfficer.AbilityOrigin = Int64List{}
+	}
+	if meowfficer.AbilityCandidates == nil {
+		meowfficer.AbilityCandidates = Int64List{}
+	}
+}
+
+func ListMeowfficers(commanderID uint32) ([]Meowfficer, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+meowfficerColumns+`
+FROM commander_meowfficers
+WHERE commander_id = $1
+ORDER BY id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	meowfficers := make([]Meowfficer, 0)
+	for rows.Next() {
+		meowfficer, err := scanMeowfficer(rows)
+		if err != nil {
+			return nil, err
+		}
+		meowfficers = append(meowfficers, meowfficer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return meowfficers, nil
+}
+
+// GetMeowfficer returns the meowfficer id of the commander, or
+// db.ErrNotFound when the commander doesn't own it.
+func GetMeowfficer(commanderID uint32, id uint32) (*Meowfficer, error) {
+	ctx := context.Background()
+	meowfficer, err := scanMeowfficer(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+meowfficerColumns+`
+FROM commander_meowfficers
+WHERE commander_id = $1 AND id = $2
+`, int64(commanderID), int64(id)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &meowfficer, nil
+}
+
+// CreateMeowfficerTx inserts meowfficer, setting its ID.
+func CreateMeowfficerTx(ctx context.Context, tx pgx.Tx, meowfficer *Meowfficer) error {
+	meowfficer.normalizeLists()
+	if meowfficer.Level == 0 {
+		meowfficer.Level = 1
+	}
+	if meowfficer.CreatedAt.IsZero() {
+		meowfficer.CreatedAt = time.Now().UTC()
+	}
+	var id int64
+	err := tx.QueryRow(ctx, `
+INSERT INTO commander_meowfficers (
+  commander_id, template_id, level, exp, is_locked, name, rename_time,
+  abilities, ability_origin, ability_candidates, ability_time, used_pt,
+  skill_id, skill_exp, home_feed_time, home_play_time, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+)
+RETURNING id
+`, int64(meowfficer.CommanderID), int64(meowfficer.TemplateID), int64(meowfficer.Level), int64(meowfficer.Exp), meowfficer.IsLocked, meowfficer.Name, int64(meowfficer.RenameTime),
+		meowfficer.Abilities, meowfficer.AbilityOrigin, meowfficer.AbilityCandidates, int64(meowfficer.AbilityTime), int64(meowfficer.UsedPt),
+		int64(meowfficer.SkillID), int64(meowfficer.SkillExp), int64(meowfficer.HomeFeedTime), int64(meowfficer.HomePlayTime), meowfficer.CreatedAt).Scan(&id)
+	if err != nil {
+		return err
+	}
+	meowfficer.ID = uint32(id)
+	return nil
+}
+
+func CreateMeowfficer(meowfficer *Meowfficer) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return CreateMeowfficerTx(ctx, tx, meowfficer)
+	})
+}
+
+// SaveMeowfficerTx updates an owned meowfficer, returning db.ErrNotFound
+// when it doesn't exist.
+func SaveMeowfficerTx(ctx context.Context, tx pgx.Tx, meowfficer *Meowfficer) error {
+	meowfficer.normalizeLists()
+	tag, err := tx.Exec(ctx, `
+UPDATE commander_meowfficers
+SET template_id = $3,
+    level = $4,
+    exp = $5,
+    is_locked = $6,
+    name = $7,
+    rename_time = $8,
+    abilities = $9,
+    ability_origin = $10,
+    ability_candidates = $11,
+    ability_time = $12,
+    used_pt = $13,
+    skill_id = $14,
+    skill_exp = $15,
+    home_feed_time = $16,
+    home_play_time = $17
+WHERE commander_id = $1 AND id = $2
+`, int64(meowfficer.CommanderID), int64(meowfficer.ID), int64(meowfficer.TemplateID), int64(meowfficer.Level), int64(meowfficer.Exp), meowfficer.IsLocked, meowfficer.Name, int64(meowfficer.RenameTime),
+		meowfficer.Abilities, meowfficer.AbilityOrigin, meowfficer.AbilityCandidates, int64(meowfficer.AbilityTime), int64(meowfficer.UsedPt),
+		int64(meowfficer.SkillID), int64(meowfficer.SkillExp), int64(meowfficer.HomeFeedTime), int64(meowfficer.HomePlayTime))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func SaveMeowfficer(meowfficer *Meowfficer) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveMeowfficerTx(ctx, tx, meowfficer)
+	})
+}
+
+// DeleteMeowfficersTx deletes the given meowfficers of the commander,
+// returning db.ErrNotFound when one of them is not owned.
+func DeleteMeowfficersTx(ctx context.Context, tx pgx.Tx, commanderID uint32, ids []uint32) error {
+	tag, err := tx.Exec(ctx, `
+DELETE FROM commander_meowfficers
+WHERE commander_id = $1 AND id = ANY($2)
+`, int64(commanderID), []int64(ToInt64List(ids)))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != int64(len(ids)) {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func DeleteMeowfficer(commanderID uint32, id uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return DeleteMeowfficersTx(ctx, tx, commanderID, []uint32{id})
+	})
+}
+
+// GetBusyMeowfficerIDs returns the meowfficers assigned to a fleet or
+// training in the cat lodge, which can't be consumed.
+func GetBusyMeowfficerIDs(commanderID uint32) (map[uint32]struct{}, error) {
+	ctx := context.Background()
+	busy := make(map[uint32]struct{})
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT meowfficer_list
+FROM fleets
+WHERE commander_id = $1
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var list Int64List
+		if err := rows.Scan(&list); err != nil {
+			return nil, err
+		}
+		for _, id := range list {
+			if id != 0 {
+				busy[uint32(id)] = struct{}{}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slots, err := ListMeowfficerHomeSlots(commanderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.MeowfficerID != 0 {
+			busy[slot.MeowfficerID] = struct{}{}
+		}
+	}
+	return busy, nil
+}
+
+func ListMeowfficerBoxes(commanderID uint32) ([]MeowfficerBox, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT id, commander_id, pool_id, begin_time, finish_time
+FROM commander_meowfficer_boxes
+WHERE commander_id = $1
+ORDER BY id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	boxes := make([]MeowfficerBox, 0)
+	for rows.Next() {
+		var box MeowfficerBox
+		if err := rows.Scan(&box.ID, &box.CommanderID, &box.PoolID, &box.BeginTime, &box.FinishTime); err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, box)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return boxes, nil
+}
+
+// GetMeowfficerBox returns the box id of the commander, or db.ErrNotFound.
+func GetMeowfficerBox(commanderID uint32, id uint32) (*MeowfficerBox, error) {
+	ctx := context.Background()
+	var box MeowfficerBox
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT id, commander_id, pool_id, begin_time, finish_time
+FROM commander_meowfficer_boxes
+WHERE commander_id = $1 AND id = $2
+`, int64(commanderID), int64(id)).Scan(&box.ID, &box.CommanderID, &box.PoolID, &box.BeginTime, &box.FinishTime)
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &box, nil
+}
+
+// CreateMeowfficerBox inserts box, setting its ID.
+func CreateMeowfficerBox(box *MeowfficerBox) error {
+	ctx := context.Background()
+	var id int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO commander_meowfficer_boxes (commander_id, pool_id, begin_time, finish_time)
+VALUES ($1, $2, $3, $4)
+RETURNING id
+`, int64(box.CommanderID), int64(box.PoolID), int64(box.BeginTime), int64(box.FinishTime)).Scan(&id)
+	if err != nil {
+		return err
+	}
+	box.ID = uint32(id)
+	return nil
+}
+
+func SaveMeowfficerBox(box *MeowfficerBox) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE commander_meowfficer_boxes
+SET pool_id = $3, begin_time = $4, finish_time = $5
+WHERE commander_id = $1 AND id = $2
+`, int64(box.CommanderID), int64(box.ID), int64(box.PoolID), int64(box.BeginTime), int64(box.FinishTime))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func DeleteMeowfficerBoxTx(ctx context.Context, tx pgx.Tx, commanderID uint32, id uint32) error {
+	tag, err := tx.Exec(ctx, `
+DELETE FROM commander_meowfficer_boxes
+WHERE commander_id = $1 AND id = $2
+`, int64(commanderID), int64(id))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func GetOrCreateMeowfficerHome(commanderID uint32) (*MeowfficerHome, error) {
+	ctx := context.Background()
+	var home MeowfficerHome
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT commander_id, level, exp, clean_time, usage_count, last_daily_reset_at
+FROM commander_meowfficer_homes
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&home.CommanderID, &home.Level, &home.Exp, &home.CleanTime, &home.UsageCount, &home.LastDailyResetAt)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &home, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	home = MeowfficerHome{CommanderID: commanderID, Level: 1, LastDailyResetAt: time.Unix(0, 0)}
+	if err := SaveMeowfficerHome(&home); err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+func SaveMeowfficerHomeTx(ctx context.Context, tx pgx.Tx, home *MeowfficerHome) error {
+	if home.LastDailyResetAt.IsZero() {
+		home.LastDailyResetAt = time.Unix(0, 0)
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_meowfficer_homes (commander_id, level, exp, clean_time, usage_count, last_daily_reset_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  level = EXCLUDED.level,
+  exp = EXCLUDED.exp,
+  clean_time = EXCLUDED.clean_time,
+  usage_count = EXCLUDED.usage_count,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at
+`, int64(home.CommanderID), int64(home.Level), int64(home.Exp), int64(home.CleanTime), int64(home.UsageCount), home.LastDailyResetAt)
+	return err
+}
+
+func SaveMeowfficerHome(home *MeowfficerHome) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveMeowfficerHomeTx(ctx, tx, home)
+	})
+}
+
+// ApplyMeowfficerDailyReset clears the number of boxes opened once a new
+// UTC day started.
+func ApplyMeowfficerDailyReset(home *MeowfficerHome, now time.Time) bool {
+	resetAt := startOfDay(now.UTC())
+	if home.LastDailyResetAt.Before(resetAt) {
+		home.UsageCount = 0
+		home.LastDailyResetAt = resetAt
+		return true
+	}
+	return false
+}
+
+func ListMeowfficerHomeSlots(commanderID uint32) ([]MeowfficerHomeSlot, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, slot_id, meowfficer_id, style, exp_time
+FROM commander_meowfficer_home_slots
+WHERE commander_id = $1
+ORDER BY slot_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	slots := make([]MeowfficerHomeSlot, 0)
+	for rows.Next() {
+		var slot MeowfficerHomeSlot
+		if err := rows.Scan(&slot.CommanderID, &slot.SlotID, &slot.MeowfficerID, &slot.Style, &slot.ExpTime); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func SaveMeowfficerHomeSlotTx(ctx context.Context, tx pgx.Tx, slot *MeowfficerHomeSlot) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_meowfficer_home_slots (commander_id, slot_id, meowfficer_id, style, exp_time)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (commander_id, slot_id)
+DO UPDATE SET
+  meowfficer_id = EXCLUDED.meowfficer_id,
+  style = EXCLUDED.style,
+  exp_time = EXCLUDED.exp_time
+`, int64(slot.CommanderID), int64(slot.SlotID), int64(slot.MeowfficerID), int64(slot.Style), int64(slot.ExpTime))
+	return err
+}
+
+func SaveMeowfficerHomeSlot(slot *MeowfficerHomeSlot) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveMeowfficerHomeSlotTx(ctx, tx, slot)
+	})
+}
+
+func ListMeowfficerPresets(commanderID uint32) ([]MeowfficerPreset, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, preset_id, name, meowfficers
+FROM commander_meowfficer_presets
+WHERE commander_id = $1
+ORDER BY preset_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	presets := make([]MeowfficerPreset, 0)
+	for rows.Next() {
+		var preset MeowfficerPreset
+		if err := rows.Scan(&preset.CommanderID, &preset.PresetID, &preset.Name, &preset.Meowfficers); err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// GetMeowfficerPreset returns a blank preset when presetID was never saved.
+func GetMeowfficerPreset(commanderID uint32, presetID uint32) (*MeowfficerPreset, error) {
+	ctx := context.Background()
+	preset := MeowfficerPreset{CommanderID: commanderID, PresetID: presetID, Meowfficers: Int64List{}}
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT name, meowfficers
+FROM commander_meowfficer_presets
+WHERE commander_id = $1 AND preset_id = $2
+`, int64(commanderID), int64(presetID)).Scan(&preset.Name, &preset.Meowfficers)
+	err = db.MapNotFound(err)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+func SaveMeowfficerPreset(preset *MeowfficerPreset) error {
+	ctx := context.Background()
+	if preset.Meowfficers == nil {
+		preset.Meowfficers = Int64List{}
+	}
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+INSERT INTO commander_meowfficer_presets (commander_id, preset_id, name, meowfficers)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (commander_id, preset_id)
+DO UPDATE SET
+  name = EXCLUDED.name,
+  meowfficers = EXCLUDED.meowfficers
+`, int64(preset.CommanderID), int64(preset.PresetID), preset.Name, preset.Meowfficers)
+	return err
+}
@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestMeowfficerPersistence(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9971, "Meowfficer")
+
+	meowfficer := Meowfficer{CommanderID: 9971, TemplateID: 101, Abilities: Int64List{11}, AbilityOrigin: Int64List{11}, SkillID: 501}
+	if err := CreateMeowfficer(&meowfficer); err != nil {
+		t.Fatalf("create meowfficer: %v", err)
+	}
+	if meowfficer.ID == 0 || meowfficer.Level != 1 {
+		t.Fatalf("unexpected created meowfficer: %+v", meowfficer)
+	}
+	meowfficer.Name = "Tiger"
+	meowfficer.IsLocked = true
+	if err := SaveMeowfficer(&meowfficer); err != nil {
+		t.Fatalf("save meowfficer: %v", err)
+	}
+	loaded, err := GetMeowfficer(9971, meowfficer.ID)
+	if err != nil {
+		t.Fatalf("get meowfficer: %v", err)
+	}
+	if loaded.Name != "Tiger" || !loaded.IsLocked || len(loaded.Abilities) != 1 || len(loaded.AbilityCandidates) != 0 {
+		t.Fatalf("unexpected meowfficer: %+v", loaded)
+	}
+
+	if err := SaveMeowfficerHomeSlot(&MeowfficerHomeSlot{CommanderID: 9971, SlotID: 1, MeowfficerID: meowfficer.ID}); err != nil {
+		t.Fatalf("save home slot: %v", err)
+	}
+	busy, err := GetBusyMeowfficerIDs(9971)
+	if err != nil {
+		t.Fatalf("get busy meowfficers: %v", err)
+	}
+	if _, ok := busy[meowfficer.ID]; !ok {
+		t.Fatalf("expected training meowfficer to be busy")
+	}
+
+	box := MeowfficerBox{CommanderID: 9971, PoolID: 1}
+	if err := CreateMeowfficerBox(&box); err != nil {
+		t.Fatalf("create box: %v", err)
+	}
+	box.BeginTime, box.FinishTime = 10, 20
+	if err := SaveMeowfficerBox(&box); err != nil {
+		t.Fatalf("save box: %v", err)
+	}
+	boxes, err := ListMeowfficerBoxes(9971)
+	if err != nil {
+		t.Fatalf("list boxes: %v", err)
+	}
+	if len(boxes) != 1 || boxes[0].FinishTime != 20 {
+		t.Fatalf("unexpected boxes: %+v", boxes)
+	}
+
+	home, err := GetOrCreateMeowfficerHome(9971)
+	if err != nil {
+		t.Fatalf("get home: %v", err)
+	}
+	home.UsageCount = 3
+	if !ApplyMeowfficerDailyReset(home, time.Now()) || home.UsageCount != 0 {
+		t.Fatalf("expected daily reset to clear usage count: %+v", home)
+	}
+
+	if err := SaveMeowfficerPreset(&MeowfficerPreset{CommanderID: 9971, PresetID: 1, Name: "Main", Meowfficers: Int64List{int64(meowfficer.ID), 0}}); err != nil {
+		t.Fatalf("save preset: %v", err)
+	}
+	preset, err := GetMeowfficerPreset(9971, 1)
+	if err != nil {
+		t.Fatalf("get preset: %v", err)
+	}
+	if preset.Name != "Main" || len(preset.Meowfficers) != 2 {
+		t.Fatalf("unexpected preset: %+v", preset)
+	}
+
+	if err := DeleteMeowfficer(9971, meowfficer.ID); err != nil {
+		t.Fatalf("delete meowfficer: %v", err)
+	}
+	if _, err := GetMeowfficer(9971, meowfficer.ID); !db.IsNotFound(err) {
+		t.Fatalf("expected deleted meowfficer to be missing, got %v", err)
+	}
+}
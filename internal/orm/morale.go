@@ -0,0 +1,96 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// MoraleShip is the part of an owned ship morale recovery depends on.
+type MoraleShip struct {
+	ID      uint32
+	Energy  uint32
+	State   uint32
+	Propose bool
+	InOnsen bool
+}
+
+// GetMoraleTickTx returns the unix time of the last morale tick applied to
+// a commander, 0 when none was.
+func GetMoraleTickTx(ctx context.Context, tx pgx.Tx, commanderID uint32) (int64, error) {
+	var tickAt int64
+	err := tx.QueryRow(ctx, `
+SELECT morale_tick_at
+FROM commanders
+WHERE commander_id = $1
+FOR UPDATE
+`, int64(commanderID)).Scan(&tickAt)
+	return tickAt, db.MapNotFound(err)
+}
+
+func SetMoraleTickTx(ctx context.Context, tx pgx.Tx, commanderID uint32, tickAt int64) error {
+	_, err := tx.Exec(ctx, `
+UPDATE commanders
+SET morale_tick_at = $2
+WHERE commander_id = $1
+`, int64(commanderID), tickAt)
+	return err
+}
+
+func ListMoraleShipsTx(ctx context.Context, tx pgx.Tx, commanderID uint32) ([]MoraleShip, error) {
+	rows, err := tx.Query(ctx, `
+SELECT id, energy, state, propose, in_onsen
+FROM owned_ships
+WHERE owner_id = $1
+  AND deleted_at IS NULL
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ships := []MoraleShip{}
+	for rows.Next() {
+		var ship MoraleShip
+		if err := rows.Scan(&ship.ID, &ship.Energy, &ship.State, &ship.Propose, &ship.InOnsen); err != nil {
+			return nil, err
+		}
+		ships = append(ships, ship)
+	}
+	return ships, rows.Err()
+}
+
+// SaveShipEnergiesTx stores the morale of several ships of a commander,
+// keyed by owned ship id.
+func SaveShipEnergiesTx(ctx context.Context, tx pgx.Tx, commanderID uint32, energies map[uint32]uint32) error {
+	batch := &pgx.Batch{}
+	for shipID, energy := range energies {
+		batch.Queue(`
+UPDATE owned_ships
+SET energy = $3
+WHERE owner_id = $1
+  AND id = $2
+`, int64(commanderID), int64(shipID), int64(energy))
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// SetOwnedShipOnsen moves a ship in or out of the onsen.
+func SetOwnedShipOnsen(ownerID uint32, shipID uint32, inOnsen bool) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE owned_ships
+SET in_onsen = $3
+WHERE owner_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+`, int64(ownerID), int64(shipID), inOnsen)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
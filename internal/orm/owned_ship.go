@@ -188,6 +188,21 @@ WHERE owner_id = $1
 	return &ship, nil
 }
 
+func CountOwnedShipsByOwner(ownerID uint32) (uint32, error) {
+	ctx := context.Background()
+	var count int64
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM owned_ships
+WHERE owner_id = $1
+  AND deleted_at IS NULL
+`, int64(ownerID)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(count), nil
+}
+
 func (s *OwnedShip) ProposeShip() error {
 	s.Propose = true
 	return s.Update()
@@ -0,0 +1,357 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusPaid      = "paid"
+	PaymentStatusDelivered = "delivered"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusRefunded  = "refunded"
+
+	// PaymentKindCharge buys a pay_data_display goods.
+	PaymentKindCharge = "charge"
+	// PaymentKindRepay pays back a refunded charge of the same goods.
+	PaymentKindRepay = "repay"
+)
+
+// PaymentOrder is an entry of the payment ledger. Gem and GemFree are the
+// gems granted on delivery, fixed when the order is paid.
+type PaymentOrder struct {
+	ID          uint32
+	PayID       string
+	CommanderID uint32
+	ShopID      uint32
+	Kind        string
+	Provider    string
+	Status      string
+	Money       uint32
+	Gem         uint32
+	GemFree     uint32
+	FailCode    uint32
+	CreatedAt   time.Time
+	PaidAt      *time.Time
+	DeliveredAt *time.Time
+	RefundedAt  *time.Time
+	RepaidAt    *time.Time
+}
+
+func (PaymentOrder) TableName() string {
+	return "payment_orders"
+}
+
+type PaymentOrderSearch struct {
+	CommanderID uint32
+	Status      string
+	Offset      int
+	Limit       int
+}
+
+const paymentOrderColumns = `id, pay_id, commander_id, shop_id, kind, provider, status, money, gem, gem_free, fail_code, created_at, paid_at, delivered_at, refunded_at, repaid_at`
+
+func scanPaymentOrder(row rowScanner, order *PaymentOrder) error {
+	return row.Scan(
+		&order.ID,
+		&order.PayID,
+		&order.CommanderID,
+		&order.ShopID,
+		&order.Kind,
+		&order.Provider,
+		&order.Status,
+		&order.Money,
+		&order.Gem,
+		&order.GemFree,
+		&order.FailCode,
+		&order.CreatedAt,
+		&order.PaidAt,
+		&order.DeliveredAt,
+		&order.RefundedAt,
+		&order.RepaidAt,
+	)
+}
+
+func scanPaymentOrders(rows pgx.Rows) ([]PaymentOrder, error) {
+	orders := []PaymentOrder{}
+	for rows.Next() {
+		var order PaymentOrder
+		if err := scanPaymentOrder(rows, &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// CreatePaymentOrder records a new pending order.
+func CreatePaymentOrder(order *PaymentOrder) error {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+INSERT INTO payment_orders (pay_id, commander_id, shop_id, kind, provider, status, money)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING `+paymentOrderColumns,
+		order.PayID, int64(order.CommanderID), int64(order.ShopID), order.Kind, order.Provider, PaymentStatusPending, int64(order.Money))
+	return scanPaymentOrder(row, order)
+}
+
+func GetPaymentOrder(id uint32) (*PaymentOrder, error) {
+	ctx := context.Background()
+	var order PaymentOrder
+	err := scanPaymentOrder(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+paymentOrderColumns+`
+FROM payment_orders
+WHERE id = $1
+`, int64(id)), &order)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &order, nil
+}
+
+// GetCommanderPaymentOrderTx returns an order of a commander by pay id,
+// locking it until the end of tx.
+func GetCommanderPaymentOrderTx(ctx context.Context, tx pgx.Tx, commanderID uint32, payID string) (*PaymentOrder, error) {
+	var order PaymentOrder
+	err := scanPaymentOrder(tx.QueryRow(ctx, `
+SELECT `+paymentOrderColumns+`
+FROM payment_orders
+WHERE commander_id = $1
+  AND pay_id = $2
+FOR UPDATE
+`, int64(commanderID), payID), &order)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &order, nil
+}
+
+// SearchPaymentOrders returns the orders matching search, newest first, and
+// their total count.
+func SearchPaymentOrders(search PaymentOrderSearch) ([]PaymentOrder, int64, error) {
+	ctx := context.Background()
+	var total int64
+	if err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM payment_orders
+WHERE ($1 = 0 OR commander_id = $1)
+  AND ($2 = '' OR status = $2)
+`, int64(search.CommanderID), search.Status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+paymentOrderColumns+`
+FROM payment_orders
+WHERE ($1 = 0 OR commander_id = $1)
+  AND ($2 = '' OR status = $2)
+ORDER BY id DESC
+OFFSET $3
+LIMIT $4
+`, int64(search.CommanderID), search.Status, search.Offset, search.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	orders, err := scanPaymentOrders(rows)
+	return orders, total, err
+}
+
+// ListPaidPaymentOrders returns the orders of a commander that were paid but
+// not delivered yet.
+func ListPaidPaymentOrders(commanderID uint32) ([]PaymentOrder, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+paymentOrderColumns+`
+FROM payment_orders
+WHERE commander_id = $1
+  AND status = $2
+ORDER BY id ASC
+`, int64(commanderID), PaymentStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPaymentOrders(rows)
+}
+
+// ListUnsettledRefunds returns the refunded orders of a commander that were
+// not paid back yet.
+func ListUnsettledRefunds(commanderID uint32) ([]PaymentOrder, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+paymentOrderColumns+`
+FROM payment_orders
+WHERE commander_id = $1
+  AND status = $2
+  AND repaid_at IS NULL
+ORDER BY id ASC
+`, int64(commanderID), PaymentStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPaymentOrders(rows)
+}
+
+// ChargeCount is the number of delivered charges of a goods.
+type ChargeCount struct {
+	ShopID uint32
+	Count  uint32
+}
+
+// ListChargeCounts returns how many times a commander was delivered each
+// goods, refunded charges included.
+func ListChargeCounts(commanderID uint32) ([]ChargeCount, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT shop_id, COUNT(*)
+FROM payment_orders
+WHERE commander_id = $1
+  AND kind = $2
+  AND status IN ($3, $4)
+GROUP BY shop_id
+ORDER BY shop_id ASC
+`, int64(commanderID), PaymentKindCharge, PaymentStatusDelivered, PaymentStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := []ChargeCount{}
+	for rows.Next() {
+		var count ChargeCount
+		if err := rows.Scan(&count.ShopID, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// HasChargedGoods reports whether a commander already paid for a goods,
+// excluding the order excludeID.
+func HasChargedGoods(commanderID uint32, shopID uint32, excludeID uint32) (bool, error) {
+	ctx := context.Background()
+	var charged bool
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1
+	FROM payment_orders
+	WHERE commander_id = $1
+	  AND shop_id = $2
+	  AND kind = $3
+	  AND status IN ($4, $5, $6)
+	  AND id <> $7
+)
+`, int64(commanderID), int64(shopID), PaymentKindCharge, PaymentStatusPaid, PaymentStatusDelivered, PaymentStatusRefunded, int64(excludeID)).Scan(&charged)
+	return charged, err
+}
+
+// MarkPaymentOrderPaid moves a pending order to paid with the gems it grants.
+// It returns db.ErrNotFound when the order is not pending.
+func MarkPaymentOrderPaid(id uint32, gem uint32, gemFree uint32) (*PaymentOrder, error) {
+	ctx := context.Background()
+	var order PaymentOrder
+	err := scanPaymentOrder(db.DefaultStore.Pool.QueryRow(ctx, `
+UPDATE payment_orders
+SET status = $2,
+    gem = $3,
+    gem_free = $4,
+    paid_at = NOW()
+WHERE id = $1
+  AND status = $5
+RETURNING `+paymentOrderColumns,
+		int64(id), PaymentStatusPaid, int64(gem), int64(gemFree), PaymentStatusPending), &order)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &order, nil
+}
+
+// MarkPaymentOrderFailed moves a pending order of a commander to failed.
+func MarkPaymentOrderFailed(commanderID uint32, payID string, code uint32) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE payment_orders
+SET status = $3,
+    fail_code = $4
+WHERE commander_id = $1
+  AND pay_id = $2
+  AND status = $5
+`, int64(commanderID), payID, PaymentStatusFailed, int64(code), PaymentStatusPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func MarkPaymentOrderDeliveredTx(ctx context.Context, tx pgx.Tx, id uint32) error {
+	_, err := tx.Exec(ctx, `
+UPDATE payment_orders
+SET status = $2,
+    delivered_at = NOW()
+WHERE id = $1
+`, int64(id), PaymentStatusDelivered)
+	return err
+}
+
+// SettleRefundsTx marks the unsettled refunds of a goods as paid back.
+func SettleRefundsTx(ctx context.Context, tx pgx.Tx, commanderID uint32, shopID uint32) error {
+	_, err := tx.Exec(ctx, `
+UPDATE payment_orders
+SET repaid_at = NOW()
+WHERE commander_id = $1
+  AND shop_id = $2
+  AND status = $3
+  AND repaid_at IS NULL
+`, int64(commanderID), int64(shopID), PaymentStatusRefunded)
+	return err
+}
+
+// RefundPaymentOrder marks a delivered order as refunded and takes its
+// amount back from the accumulated pay level of the commander. It returns
+// db.ErrNotFound when the order is not delivered.
+func RefundPaymentOrder(id uint32) (*PaymentOrder, error) {
+	ctx := context.Background()
+	var order PaymentOrder
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		err := scanPaymentOrder(tx.QueryRow(ctx, `
+UPDATE payment_orders
+SET status = $2,
+    refunded_at = NOW()
+WHERE id = $1
+  AND status = $3
+RETURNING `+paymentOrderColumns,
+			int64(id), PaymentStatusRefunded, PaymentStatusDelivered), &order)
+		if err != nil {
+			return db.MapNotFound(err)
+		}
+		_, err = tx.Exec(ctx, `
+UPDATE commanders
+SET acc_pay_lv = GREATEST(acc_pay_lv - $2, 0)
+WHERE commander_id = $1
+`, int64(order.CommanderID), int64(order.Money))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// AddAccPayLvTx adds money to the accumulated pay level of a commander.
+func AddAccPayLvTx(ctx context.Context, tx pgx.Tx, commanderID uint32, money uint32) error {
+	_, err := tx.Exec(ctx, `
+UPDATE commanders
+SET acc_pay_lv = acc_pay_lv + $2
+WHERE commander_id = $1
+`, int64(commanderID), int64(money))
+	return err
+}
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"errors"
+	"math"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -131,24 +132,50 @@ var (
 	shipRng = rng.NewLockedRand()
 )
 
-// Returns a random ship from a pool, based on Azur Lane's rates
+// buildRarityWeight is the share of a rarity in a build roll.
+type buildRarityWeight struct {
+	RarityID uint32
+	Weight   uint32
+}
+
+// Azur Lane's standard rates, used by pools without an active banner:
 // 7% Super Rare (gold color)
 // 12% Elite (purple color)
 // 51% Rare (blue color)
 // 30% Common (gray color)
-// Azur Lane has sometime some boosted rates for some ships, but we don't care about that for now
-func GetRandomPoolShip(poolId uint32) (Ship, error) {
-	randomN := shipRng.Uint32N(100) + 1 // between 1 and 100
-	var rarity uint32
-	if randomN <= 7 {
-		rarity = 5 // SR
-	} else if randomN <= 19 {
-		rarity = 4 // Elite
-	} else if randomN <= 70 {
-		rarity = 3 // Rare
-	} else {
-		rarity = 2 // Common
+var defaultBuildRarityWeights = []buildRarityWeight{
+	{RarityID: 5, Weight: 7},
+	{RarityID: 4, Weight: 12},
+	{RarityID: 3, Weight: 51},
+	{RarityID: 2, Weight: 30},
+}
+
+func rollBuildRarity(weights []buildRarityWeight) uint32 {
+	total := uint64(0)
+	for _, weight := range weights {
+		total += uint64(weight.Weight)
+	}
+	// banners are validated by the API, but a wrapped total would skew rates
+	if total == 0 || total > math.MaxUint32 {
+		return rollBuildRarity(defaultBuildRarityWeights)
 	}
+	randomN := shipRng.Uint32N(uint32(total))
+	for _, weight := range weights {
+		if randomN < weight.Weight {
+			return weight.RarityID
+		}
+		randomN -= weight.Weight
+	}
+	return weights[len(weights)-1].RarityID
+}
+
+// Returns a random ship from a pool, based on Azur Lane's standard rates.
+// Limited rates are handled by build banners, see Commander.RollBuildShip.
+func GetRandomPoolShip(poolId uint32) (Ship, error) {
+	return getRandomPoolShipByRarity(poolId, rollBuildRarity(defaultBuildRarityWeights))
+}
+
+func getRandomPoolShipByRarity(poolId uint32, rarity uint32) (Ship, error) {
 	ctx := context.Background()
 	row, err := db.DefaultStore.Queries.GetRandomPoolShip(ctx, gen.GetRandomPoolShipParams{PoolID: pgtype.Int8{Int64: int64(poolId), Valid: true}, RarityID: int64(rarity)})
 	err = db.MapNotFound(err)
@@ -0,0 +1,389 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+var ErrTechCatchupLimit = errors.New("catch-up blueprint limit reached")
+
+// TechState holds the research counters of a commander. RefreshFlag is set
+// once the daily manual refresh of the research projects was used, and
+// ColdTime is the unix time before which a new blueprint development can't
+// be started after one was stopped.
+type TechState struct {
+	CommanderID      uint32
+	RefreshFlag      uint32
+	ColdTime         uint32
+	CatchupVersion   uint32
+	CatchupTarget    uint32
+	LastDailyResetAt time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// TechProject is a research project offered in a refresh slot. FinishTime
+// is the unix time the project completes at, 0 while it is not started.
+type TechProject struct {
+	CommanderID uint32
+	RefreshID   uint32
+	TechID      uint32
+	FinishTime  uint32
+}
+
+// TechCatchup is the number of catch-up blueprints obtained for a blueprint
+// of a research series.
+type TechCatchup struct {
+	Version     uint32
+	BlueprintID uint32
+	Number      uint32
+}
+
+// CommanderBlueprint is the development progress of a research ship.
+// ShipID is the owned ship once the development is finished; StartTime and
+// Duration describe the development timer before that.
+type CommanderBlueprint struct {
+	CommanderID    uint32
+	BlueprintID    uint32
+	ShipID         uint32
+	StartTime      uint32
+	Duration       uint32
+	Level          uint32
+	Exp            uint32
+	SubmittedTasks Int64List
+}
+
+func GetOrCreateTechState(commanderID uint32) (*TechState, error) {
+	ctx := context.Background()
+	var state TechState
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT commander_id, refresh_flag, cold_time, catchup_version, catchup_target, last_daily_reset_at, created_at, updated_at
+FROM commander_tech_states
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(&state.CommanderID, &state.RefreshFlag, &state.ColdTime, &state.CatchupVersion, &state.CatchupTarget, &state.LastDailyResetAt, &state.CreatedAt, &state.UpdatedAt)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &state, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	state = TechState{CommanderID: commanderID, LastDailyResetAt: time.Unix(0, 0)}
+	if err := SaveTechState(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func SaveTechStateTx(ctx context.Context, tx pgx.Tx, state *TechState) error {
+	now := time.Now().UTC()
+	if state.LastDailyResetAt.IsZero() {
+		state.LastDailyResetAt = time.Unix(0, 0)
+	}
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
+	state.UpdatedAt = now
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_tech_states (
+  commander_id,
+  refresh_flag,
+  cold_time,
+  catchup_version,
+  catchup_target,
+  last_daily_reset_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  refresh_flag = EXCLUDED.refresh_flag,
+  cold_time = EXCLUDED.cold_time,
+  catchup_version = EXCLUDED.catchup_version,
+  catchup_target = EXCLUDED.catchup_target,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at,
+  updated_at = EXCLUDED.updated_at
+`, int64(state.CommanderID), int64(state.RefreshFlag), int64(state.ColdTime), int64(state.CatchupVersion), int64(state.CatchupTarget), state.LastDailyResetAt, state.CreatedAt, state.UpdatedAt)
+	return err
+}
+
+func SaveTechState(state *TechState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveTechStateTx(ctx, tx, state)
+	})
+}
+
+// ApplyTechDailyReset gives back the daily manual refresh once a new UTC day
+// started. The caller is expected to roll new projects when it returns true.
+func ApplyTechDailyReset(state *TechState, now time.Time) bool {
+	resetAt := startOfDay(now.UTC())
+	if state.LastDailyResetAt.Before(resetAt) {
+		state.RefreshFlag = 0
+		state.LastDailyResetAt = resetAt
+		return true
+	}
+	return false
+}
+
+func ListTechProjects(commanderID uint32) ([]TechProject, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, refresh_id, tech_id, finish_time
+FROM commander_tech_projects
+WHERE commander_id = $1
+ORDER BY refresh_id ASC, tech_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	projects := make([]TechProject, 0)
+	for rows.Next() {
+		var project TechProject
+		if err := rows.Scan(&project.CommanderID, &project.RefreshID, &project.TechID, &project.FinishTime); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// ReplaceTechProjectsTx replaces the projects offered in refreshID.
+func ReplaceTechProjectsTx(ctx context.Context, tx pgx.Tx, commanderID uint32, refreshID uint32, techIDs []uint32) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM commander_tech_projects WHERE commander_id = $1 AND refresh_id = $2`, int64(commanderID), int64(refreshID)); err != nil {
+		return err
+	}
+	for _, techID := range techIDs {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO commander_tech_projects (commander_id, refresh_id, tech_id, finish_time)
+VALUES ($1, $2, $3, 0)
+`, int64(commanderID), int64(refreshID), int64(techID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTechProjectFinishTimeTx starts (or stops, with 0) an offered project,
+// returning db.ErrNotFound when it is not offered.
+func SetTechProjectFinishTimeTx(ctx context.Context, tx pgx.Tx, commanderID uint32, refreshID uint32, techID uint32, finishTime uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE commander_tech_projects
+SET finish_time = $4
+WHERE commander_id = $1 AND refresh_id = $2 AND tech_id = $3
+`, int64(commanderID), int64(refreshID), int64(techID), int64(finishTime))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ListTechTargets returns the blueprint targeted by each refresh slot.
+func ListTechTargets(commanderID uint32) (map[uint32]uint32, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT refresh_id, target
+FROM commander_tech_targets
+WHERE commander_id = $1
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	targets := make(map[uint32]uint32)
+	for rows.Next() {
+		var refreshID, target uint32
+		if err := rows.Scan(&refreshID, &target); err != nil {
+			return nil, err
+		}
+		targets[refreshID] = target
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+func SetTechTarget(commanderID uint32, refreshID uint32, target uint32) error {
+	ctx := context.Background()
+	_, err := db.DefaultStore.Pool.Exec(ctx, `
+INSERT INTO commander_tech_targets (commander_id, refresh_id, target)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, refresh_id) DO UPDATE SET target = EXCLUDED.target
+`, int64(commanderID), int64(refreshID), int64(target))
+	return err
+}
+
+func ListTechCatchups(commanderID uint32) ([]TechCatchup, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT version, blueprint_id, number
+FROM commander_tech_catchups
+WHERE commander_id = $1
+ORDER BY version ASC, blueprint_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	catchups := make([]TechCatchup, 0)
+	for rows.Next() {
+		var catchup TechCatchup
+		if err := rows.Scan(&catchup.Version, &catchup.BlueprintID, &catchup.Number); err != nil {
+			return nil, err
+		}
+		catchups = append(catchups, catchup)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return catchups, nil
+}
+
+// AddTechCatchupTx counts delta catch-up blueprints for blueprintID,
+// returning ErrTechCatchupLimit when that would exceed limit.
+func AddTechCatchupTx(ctx context.Context, tx pgx.Tx, commanderID uint32, version uint32, blueprintID uint32, delta uint32, limit uint32) error {
+	if delta > limit {
+		return ErrTechCatchupLimit
+	}
+	tag, err := tx.Exec(ctx, `
+INSERT INTO commander_tech_catchups (commander_id, version, blueprint_id, number)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (commander_id, version, blueprint_id) DO UPDATE
+SET number = commander_tech_catchups.number + EXCLUDED.number
+WHERE commander_tech_catchups.number + EXCLUDED.number <= $5
+`, int64(commanderID), int64(version), int64(blueprintID), int64(delta), int64(limit))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTechCatchupLimit
+	}
+	return nil
+}
+
+const commanderBlueprintColumns = `commander_id, blueprint_id, ship_id, start_time, duration, level, exp, submitted_tasks`
+
+func scanCommanderBlueprint(scanner rowScanner) (CommanderBlueprint, error) {
+	var blueprint CommanderBlueprint
+	err := scanner.Scan(
+		&blueprint.CommanderID,
+		&blueprint.BlueprintID,
+		&blueprint.ShipID,
+		&blueprint.StartTime,
+		&blueprint.Duration,
+		&blueprint.Level,
+		&blueprint.Exp,
+		&blueprint.SubmittedTasks,
+	)
+	return blueprint, err
+}
+
+func ListCommanderBlueprints(commanderID uint32) ([]CommanderBlueprint, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+commanderBlueprintColumns+`
+FROM commander_blueprints
+WHERE commander_id = $1
+ORDER BY blueprint_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	blueprints := make([]CommanderBlueprint, 0)
+	for rows.Next() {
+		blueprint, err := scanCommanderBlueprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		blueprints = append(blueprints, blueprint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return blueprints, nil
+}
+
+// GetCommanderBlueprint returns the progress of blueprintID, or
+// db.ErrNotFound when its development never started.
+func GetCommanderBlueprint(commanderID uint32, blueprintID uint32) (*CommanderBlueprint, error) {
+	ctx := context.Background()
+	blueprint, err := scanCommanderBlueprint(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+commanderBlueprintColumns+`
+FROM commander_blueprints
+WHERE commander_id = $1 AND blueprint_id = $2
+`, int64(commanderID), int64(blueprintID)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+// GetCommanderBlueprintByShip returns the blueprint the owned ship shipID
+// was developed from.
+func GetCommanderBlueprintByShip(commanderID uint32, shipID uint32) (*CommanderBlueprint, error) {
+	ctx := context.Background()
+	blueprint, err := scanCommanderBlueprint(db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+commanderBlueprintColumns+`
+FROM commander_blueprints
+WHERE commander_id = $1 AND ship_id = $2 AND ship_id <> 0
+`, int64(commanderID), int64(shipID)))
+	err = db.MapNotFound(err)
+	if err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+func SaveCommanderBlueprintTx(ctx context.Context, tx pgx.Tx, blueprint *CommanderBlueprint) error {
+	if blueprint.SubmittedTasks == nil {
+		blueprint.SubmittedTasks = Int64List{}
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_blueprints (commander_id, blueprint_id, ship_id, start_time, duration, level, exp, submitted_tasks)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (commander_id, blueprint_id)
+DO UPDATE SET
+  ship_id = EXCLUDED.ship_id,
+  start_time = EXCLUDED.start_time,
+  duration = EXCLUDED.duration,
+  level = EXCLUDED.level,
+  exp = EXCLUDED.exp,
+  submitted_tasks = EXCLUDED.submitted_tasks
+`, int64(blueprint.CommanderID), int64(blueprint.BlueprintID), int64(blueprint.ShipID), int64(blueprint.StartTime), int64(blueprint.Duration), int64(blueprint.Level), int64(blueprint.Exp), blueprint.SubmittedTasks)
+	return err
+}
+
+func SaveCommanderBlueprint(blueprint *CommanderBlueprint) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveCommanderBlueprintTx(ctx, tx, blueprint)
+	})
+}
+
+func DeleteCommanderBlueprintTx(ctx context.Context, tx pgx.Tx, commanderID uint32, blueprintID uint32) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM commander_blueprints WHERE commander_id = $1 AND blueprint_id = $2`, int64(commanderID), int64(blueprintID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
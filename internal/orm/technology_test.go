@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestTechStateDailyReset(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9951, "Tech State")
+
+	state, err := GetOrCreateTechState(9951)
+	if err != nil {
+		t.Fatalf("get tech state: %v", err)
+	}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	if !ApplyTechDailyReset(state, now) {
+		t.Fatalf("expected first reset to apply")
+	}
+	state.RefreshFlag = 1
+	state.ColdTime = 1234
+	if err := SaveTechState(state); err != nil {
+		t.Fatalf("save tech state: %v", err)
+	}
+	if ApplyTechDailyReset(state, now.Add(time.Hour)) {
+		t.Fatalf("expected reset to be skipped on the same day")
+	}
+	reloaded, err := GetOrCreateTechState(9951)
+	if err != nil {
+		t.Fatalf("reload tech state: %v", err)
+	}
+	if reloaded.RefreshFlag != 1 || reloaded.ColdTime != 1234 {
+		t.Fatalf("unexpected tech state: %+v", reloaded)
+	}
+	if !ApplyTechDailyReset(reloaded, now.Add(24*time.Hour)) || reloaded.RefreshFlag != 0 {
+		t.Fatalf("expected next day reset to clear refresh flag: %+v", reloaded)
+	}
+}
+
+func TestTechProjectsAndCatchups(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9952, "Tech Projects")
+	ctx := context.Background()
+
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return ReplaceTechProjectsTx(ctx, tx, 9952, 1, []uint32{101, 102})
+	}); err != nil {
+		t.Fatalf("replace projects: %v", err)
+	}
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SetTechProjectFinishTimeTx(ctx, tx, 9952, 1, 102, 500)
+	}); err != nil {
+		t.Fatalf("start project: %v", err)
+	}
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SetTechProjectFinishTimeTx(ctx, tx, 9952, 1, 103, 500)
+	})
+	if !db.IsNotFound(err) {
+		t.Fatalf("expected not found for unknown project, got %v", err)
+	}
+	projects, err := ListTechProjects(9952)
+	if err != nil {
+		t.Fatalf("list projects: %v", err)
+	}
+	if len(projects) != 2 || projects[1].TechID != 102 || projects[1].FinishTime != 500 {
+		t.Fatalf("unexpected projects: %+v", projects)
+	}
+
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return AddTechCatchupTx(ctx, tx, 9952, 1, 10001, 1, 2)
+	}); err != nil {
+		t.Fatalf("add catchup: %v", err)
+	}
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return AddTechCatchupTx(ctx, tx, 9952, 1, 10001, 1, 2)
+	}); err != nil {
+		t.Fatalf("add catchup: %v", err)
+	}
+	err = WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return AddTechCatchupTx(ctx, tx, 9952, 1, 10001, 1, 2)
+	})
+	if err != ErrTechCatchupLimit {
+		t.Fatalf("expected catchup limit, got %v", err)
+	}
+	catchups, err := ListTechCatchups(9952)
+	if err != nil {
+		t.Fatalf("list catchups: %v", err)
+	}
+	if len(catchups) != 1 || catchups[0].Number != 2 {
+		t.Fatalf("unexpected catchups: %+v", catchups)
+	}
+}
+
+func TestCommanderBlueprintProgress(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9953, "Tech Blueprints")
+
+	blueprint := CommanderBlueprint{CommanderID: 9953, BlueprintID: 10001, StartTime: 100, Duration: 60}
+	if err := SaveCommanderBlueprint(&blueprint); err != nil {
+		t.Fatalf("save blueprint: %v", err)
+	}
+	blueprint.ShipID = 77
+	blueprint.SubmittedTasks = Int64List{1, 2}
+	if err := SaveCommanderBlueprint(&blueprint); err != nil {
+		t.Fatalf("update blueprint: %v", err)
+	}
+	loaded, err := GetCommanderBlueprintByShip(9953, 77)
+	if err != nil {
+		t.Fatalf("get blueprint by ship: %v", err)
+	}
+	if loaded.BlueprintID != 10001 || len(loaded.SubmittedTasks) != 2 {
+		t.Fatalf("unexpected blueprint: %+v", loaded)
+	}
+	if _, err := GetCommanderBlueprint(9953, 10002); !db.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	ctx := context.Background()
+	if err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return DeleteCommanderBlueprintTx(ctx, tx, 9953, 10001)
+	}); err != nil {
+		t.Fatalf("delete blueprint: %v", err)
+	}
+	blueprints, err := ListCommanderBlueprints(9953)
+	if err != nil {
+		t.Fatalf("list blueprints: %v", err)
+	}
+	if len(blueprints) != 0 {
+		t.Fatalf("expected no blueprints, got %+v", blueprints)
+	}
+}
@@ -0,0 +1,354 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// World is the Operation Siren state of a commander. Camp is 0 until the
+// commander entered the world, MapID is the map template the fleets are on
+// and LastRecoverTime the unix time action power was last regenerated at.
+type World struct {
+	CommanderID           uint32
+	Camp                  uint32
+	MapID                 uint32
+	Progress              uint32
+	ActionPower           uint32
+	ActionPowerExtra      uint32
+	LastRecoverTime       uint32
+	ActionPowerFetchCount uint32
+	StepCount             uint32
+	TreasureCount         uint32
+	ActivateCount         uint32
+	LastDailyResetAt      time.Time
+}
+
+// WorldMap is a map unlocked by a commander.
+type WorldMap struct {
+	CommanderID uint32
+	MapID       uint32
+	IsCleared   bool
+}
+
+// WorldGroup is a fleet sent to the world and its position on the grid of
+// the current map.
+type WorldGroup struct {
+	CommanderID uint32
+	GroupID     uint32
+	ShipList    Int64List
+	Row         uint32
+	Column      uint32
+}
+
+// WorldItem is a stack of the world item bag.
+type WorldItem struct {
+	CommanderID uint32
+	ItemID      uint32
+	Count       uint32
+}
+
+const worldColumns = `commander_id, camp, map_id, progress, action_power, action_power_extra, last_recover_time, action_power_fetch_count, step_count, treasure_count, activate_count, last_daily_reset_at`
+
+func GetOrCreateWorld(commanderID uint32) (*World, error) {
+	ctx := context.Background()
+	var world World
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+worldColumns+`
+FROM commander_worlds
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(
+		&world.CommanderID,
+		&world.Camp,
+		&world.MapID,
+		&world.Progress,
+		&world.ActionPower,
+		&world.ActionPowerExtra,
+		&world.LastRecoverTime,
+		&world.ActionPowerFetchCount,
+		&world.StepCount,
+		&world.TreasureCount,
+		&world.ActivateCount,
+		&world.LastDailyResetAt,
+	)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &world, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	world = World{CommanderID: commanderID, LastDailyResetAt: time.Unix(0, 0)}
+	if err := SaveWorld(&world); err != nil {
+		return nil, err
+	}
+	return &world, nil
+}
+
+func SaveWorldTx(ctx context.Context, tx pgx.Tx, world *World) error {
+	if world.LastDailyResetAt.IsZero() {
+		world.LastDailyResetAt = time.Unix(0, 0)
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_worlds (`+worldColumns+`)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  camp = EXCLUDED.camp,
+  map_id = EXCLUDED.map_id,
+  progress = EXCLUDED.progress,
+  action_power = EXCLUDED.action_power,
+  action_power_extra = EXCLUDED.action_power_extra,
+  last_recover_time = EXCLUDED.last_recover_time,
+  action_power_fetch_count = EXCLUDED.action_power_fetch_count,
+  step_count = EXCLUDED.step_count,
+  treasure_count = EXCLUDED.treasure_count,
+  activate_count = EXCLUDED.activate_count,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at
+`, int64(world.CommanderID), int64(world.Camp), int64(world.MapID), int64(world.Progress), int64(world.ActionPower), int64(world.ActionPowerExtra),
+		int64(world.LastRecoverTime), int64(world.ActionPowerFetchCount), int64(world.StepCount), int64(world.TreasureCount), int64(world.ActivateCount), world.LastDailyResetAt)
+	return err
+}
+
+func SaveWorld(world *World) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveWorldTx(ctx, tx, world)
+	})
+}
+
+// ApplyWorldDailyReset clears the daily counters and the port purchases
+// once a new UTC day started.
+func ApplyWorldDailyReset(world *World, now time.Time) (bool, error) {
+	resetAt := startOfDay(now.UTC())
+	if !world.LastDailyResetAt.Before(resetAt) {
+		return false, nil
+	}
+	world.ActionPowerFetchCount = 0
+	world.LastDailyResetAt = resetAt
+	ctx := context.Background()
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM commander_world_port_goods WHERE commander_id = $1`, int64(world.CommanderID)); err != nil {
+			return err
+		}
+		return SaveWorldTx(ctx, tx, world)
+	})
+	return true, err
+}
+
+// ResetWorld wipes the world state of a commander: the commander has to
+// enter the world again and every map, fleet, item and purchase is lost.
+func ResetWorld(commanderID uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		for _, table := range []string{"commander_world_port_goods", "commander_world_items", "commander_world_groups", "commander_world_maps", "commander_worlds"} {
+			if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE commander_id = $1`, int64(commanderID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func ListWorldMaps(commanderID uint32) ([]WorldMap, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, map_id, is_cleared
+FROM commander_world_maps
+WHERE commander_id = $1
+ORDER BY map_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	maps := []WorldMap{}
+	for rows.Next() {
+		var worldMap WorldMap
+		if err := rows.Scan(&worldMap.CommanderID, &worldMap.MapID, &worldMap.IsCleared); err != nil {
+			return nil, err
+		}
+		maps = append(maps, worldMap)
+	}
+	return maps, rows.Err()
+}
+
+func SaveWorldMapTx(ctx context.Context, tx pgx.Tx, worldMap *WorldMap) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_world_maps (commander_id, map_id, is_cleared)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, map_id)
+DO UPDATE SET is_cleared = EXCLUDED.is_cleared
+`, int64(worldMap.CommanderID), int64(worldMap.MapID), worldMap.IsCleared)
+	return err
+}
+
+func SaveWorldMap(worldMap *WorldMap) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveWorldMapTx(ctx, tx, worldMap)
+	})
+}
+
+func ListWorldGroups(commanderID uint32) ([]WorldGroup, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, group_id, ship_list, pos_row, pos_column
+FROM commander_world_groups
+WHERE commander_id = $1
+ORDER BY group_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	groups := []WorldGroup{}
+	for rows.Next() {
+		var group WorldGroup
+		if err := rows.Scan(&group.CommanderID, &group.GroupID, &group.ShipList, &group.Row, &group.Column); err != nil {
+			return nil, err
+		}
+		if group.ShipList == nil {
+			group.ShipList = Int64List{}
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func SaveWorldGroupTx(ctx context.Context, tx pgx.Tx, group *WorldGroup) error {
+	if group.ShipList == nil {
+		group.ShipList = Int64List{}
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_world_groups (commander_id, group_id, ship_list, pos_row, pos_column)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (commander_id, group_id)
+DO UPDATE SET
+  ship_list = EXCLUDED.ship_list,
+  pos_row = EXCLUDED.pos_row,
+  pos_column = EXCLUDED.pos_column
+`, int64(group.CommanderID), int64(group.GroupID), group.ShipList, int64(group.Row), int64(group.Column))
+	return err
+}
+
+func SaveWorldGroup(group *WorldGroup) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveWorldGroupTx(ctx, tx, group)
+	})
+}
+
+func DeleteWorldGroupsTx(ctx context.Context, tx pgx.Tx, commanderID uint32) error {
+	_, err := tx.Exec(ctx, `DELETE FROM commander_world_groups WHERE commander_id = $1`, int64(commanderID))
+	return err
+}
+
+func ListWorldItems(commanderID uint32) ([]WorldItem, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT commander_id, item_id, count
+FROM commander_world_items
+WHERE commander_id = $1 AND count > 0
+ORDER BY item_id ASC
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WorldItem{}
+	for rows.Next() {
+		var item WorldItem
+		if err := rows.Scan(&item.CommanderID, &item.ItemID, &item.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func GetWorldItemCount(commanderID uint32, itemID uint32) (uint32, error) {
+	ctx := context.Background()
+	var count uint32
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT count
+FROM commander_world_items
+WHERE commander_id = $1 AND item_id = $2
+`, int64(commanderID), int64(itemID)).Scan(&count)
+	err = db.MapNotFound(err)
+	if db.IsNotFound(err) {
+		return 0, nil
+	}
+	return count, err
+}
+
+func AddWorldItemTx(ctx context.Context, tx pgx.Tx, commanderID uint32, itemID uint32, count uint32) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_world_items (commander_id, item_id, count)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, item_id)
+DO UPDATE SET count = commander_world_items.count + EXCLUDED.count
+`, int64(commanderID), int64(itemID), int64(count))
+	return err
+}
+
+func AddWorldItem(commanderID uint32, itemID uint32, count uint32) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return AddWorldItemTx(ctx, tx, commanderID, itemID, count)
+	})
+}
+
+// ConsumeWorldItemTx removes count items from the bag, returning
+// db.ErrNotFound when the commander does not have enough of them.
+func ConsumeWorldItemTx(ctx context.Context, tx pgx.Tx, commanderID uint32, itemID uint32, count uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE commander_world_items
+SET count = count - $3
+WHERE commander_id = $1 AND item_id = $2 AND count >= $3
+`, int64(commanderID), int64(itemID), int64(count))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ListWorldPortPurchases returns the number of times each port goods was
+// bought since the last daily reset.
+func ListWorldPortPurchases(commanderID uint32) (map[uint32]uint32, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT goods_id, bought
+FROM commander_world_port_goods
+WHERE commander_id = $1
+`, int64(commanderID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	purchases := map[uint32]uint32{}
+	for rows.Next() {
+		var goodsID, bought uint32
+		if err := rows.Scan(&goodsID, &bought); err != nil {
+			return nil, err
+		}
+		purchases[goodsID] = bought
+	}
+	return purchases, rows.Err()
+}
+
+func AddWorldPortPurchaseTx(ctx context.Context, tx pgx.Tx, commanderID uint32, goodsID uint32, count uint32) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_world_port_goods (commander_id, goods_id, bought)
+VALUES ($1, $2, $3)
+ON CONFLICT (commander_id, goods_id)
+DO UPDATE SET bought = commander_world_port_goods.bought + EXCLUDED.bought
+`, int64(commanderID), int64(goodsID), int64(count))
+	return err
+}
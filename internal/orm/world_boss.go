@@ -0,0 +1,378 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+// WorldBoss is a boss summoned by a commander. Its HP is shared by every
+// commander it is visible to: the owner, and friends, guild members or
+// everyone once the owner asked them for support. The boss can be fought
+// until ExpireTime, and KillTime is set when its HP reaches 0.
+type WorldBoss struct {
+	ID            uint32
+	OwnerID       uint32
+	TemplateID    uint32
+	Level         uint32
+	HP            uint32
+	MaxHP         uint32
+	ExpireTime    uint32
+	KillTime      uint32
+	FightCount    uint32
+	FriendSupport bool
+	GuildSupport  bool
+	WorldSupport  bool
+	RankCount     uint32
+	CreatedAt     time.Time
+}
+
+// Alive reports whether the boss can still be fought at now.
+func (boss *WorldBoss) Alive(now uint32) bool {
+	return boss.HP > 0 && boss.ExpireTime > now
+}
+
+// WorldBossDamage is the damage a commander dealt to a boss; MaxDamage is
+// the best single fight, used by auto fights.
+type WorldBossDamage struct {
+	BossID      uint32
+	CommanderID uint32
+	Name        string
+	Damage      uint32
+	MaxDamage   uint32
+	FightCount  uint32
+	Awarded     bool
+}
+
+// WorldBossState holds the summon points and fight counts of a commander.
+// FightCount regenerates from FightCountUpdateTime, and an auto fight
+// spends AutoFightCount fights on AutoFightBossID until
+// AutoFightFinishTime.
+type WorldBossState struct {
+	CommanderID          uint32
+	SummonPt             uint32
+	SummonPtDailyAcc     uint32
+	FightCount           uint32
+	FightCountUpdateTime uint32
+	AutoFightBossID      uint32
+	AutoFightCount       uint32
+	AutoFightFinishTime  uint32
+	LastDailyResetAt     time.Time
+}
+
+const worldBossColumns = `b.id, b.owner_id, b.template_id, b.level, b.hp, b.max_hp, b.expire_time, b.kill_time, b.fight_count, b.friend_support, b.guild_support, b.world_support,
+  (SELECT COUNT(*) FROM world_boss_damages d WHERE d.boss_id = b.id), b.created_at`
+
+func scanWorldBoss(scanner rowScanner) (WorldBoss, error) {
+	var boss WorldBoss
+	err := scanner.Scan(
+		&boss.ID,
+		&boss.OwnerID,
+		&boss.TemplateID,
+		&boss.Level,
+		&boss.HP,
+		&boss.MaxHP,
+		&boss.ExpireTime,
+		&boss.KillTime,
+		&boss.FightCount,
+		&boss.FriendSupport,
+		&boss.GuildSupport,
+		&boss.WorldSupport,
+		&boss.RankCount,
+		&boss.CreatedAt,
+	)
+	return boss, err
+}
+
+func CreateWorldBossTx(ctx context.Context, tx pgx.Tx, boss *WorldBoss) error {
+	if boss.CreatedAt.IsZero() {
+		boss.CreatedAt = time.Now().UTC()
+	}
+	var id int64
+	err := tx.QueryRow(ctx, `
+INSERT INTO world_bosses (owner_id, template_id, level, hp, max_hp, expire_time, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
+`, int64(boss.OwnerID), int64(boss.TemplateID), int64(boss.Level), int64(boss.HP), int64(boss.MaxHP), int64(boss.ExpireTime), boss.CreatedAt).Scan(&id)
+	if err != nil {
+		return err
+	}
+	boss.ID = uint32(id)
+	return nil
+}
+
+func CreateWorldBoss(boss *WorldBoss) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return CreateWorldBossTx(ctx, tx, boss)
+	})
+}
+
+func GetWorldBoss(bossID uint32) (*WorldBoss, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+worldBossColumns+`
+FROM world_bosses b
+WHERE b.id = $1
+`, int64(bossID))
+	boss, err := scanWorldBoss(row)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &boss, nil
+}
+
+// GetActiveWorldBoss returns the boss of ownerID that can still be fought
+// at now.
+func GetActiveWorldBoss(ownerID uint32, now uint32) (*WorldBoss, error) {
+	ctx := context.Background()
+	row := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT `+worldBossColumns+`
+FROM world_bosses b
+WHERE b.owner_id = $1 AND b.hp > 0 AND b.expire_time > $2
+ORDER BY b.id DESC
+LIMIT 1
+`, int64(ownerID), int64(now))
+	boss, err := scanWorldBoss(row)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &boss, nil
+}
+
+// ListWorldBossesByIDs returns the bosses among bossIDs, skipping unknown
+// ones.
+func ListWorldBossesByIDs(bossIDs []uint32) ([]WorldBoss, error) {
+	return listWorldBosses(`WHERE b.id = ANY($1)`, []int64(ToInt64List(bossIDs)))
+}
+
+// ListActiveWorldBossesByOwners returns the bosses of ownerIDs that can
+// still be fought at now.
+func ListActiveWorldBossesByOwners(ownerIDs []uint32, now uint32) ([]WorldBoss, error) {
+	return listWorldBosses(`WHERE b.owner_id = ANY($1) AND b.hp > 0 AND b.expire_time > $2`, []int64(ToInt64List(ownerIDs)), int64(now))
+}
+
+func listWorldBosses(where string, args ...any) ([]WorldBoss, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT `+worldBossColumns+`
+FROM world_bosses b
+`+where+`
+ORDER BY b.id ASC
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	bosses := []WorldBoss{}
+	for rows.Next() {
+		boss, err := scanWorldBoss(rows)
+		if err != nil {
+			return nil, err
+		}
+		bosses = append(bosses, boss)
+	}
+	return bosses, rows.Err()
+}
+
+// SaveWorldBossSupport stores who the owner asked for support.
+func SaveWorldBossSupport(boss *WorldBoss) error {
+	ctx := context.Background()
+	tag, err := db.DefaultStore.Pool.Exec(ctx, `
+UPDATE world_bosses
+SET friend_support = $2, guild_support = $3, world_support = $4
+WHERE id = $1
+`, int64(boss.ID), boss.FriendSupport, boss.GuildSupport, boss.WorldSupport)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// DamageWorldBoss deals damage over fights fights to a boss that can still
+// be fought at now, crediting it to commanderID, and returns the updated
+// boss. db.ErrNotFound is returned for dead or expired bosses.
+func DamageWorldBoss(bossID uint32, commanderID uint32, damage uint32, fights uint32, now uint32) (*WorldBoss, error) {
+	ctx := context.Background()
+	maxDamage := damage
+	if fights > 1 {
+		maxDamage = damage / fights
+	}
+	var boss *WorldBoss
+	err := WithPGXTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+UPDATE world_bosses
+SET hp = GREATEST(hp - $2, 0),
+  fight_count = fight_count + $3,
+  kill_time = CASE WHEN hp <= $2 THEN $4 ELSE kill_time END
+WHERE id = $1 AND hp > 0 AND expire_time > $4
+`, int64(bossID), int64(damage), int64(fights), int64(now))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return db.ErrNotFound
+		}
+		if _, err := tx.Exec(ctx, `
+INSERT INTO world_boss_damages (boss_id, commander_id, damage, max_damage, fight_count)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (boss_id, commander_id) DO UPDATE
+SET damage = world_boss_damages.damage + EXCLUDED.damage,
+  max_damage = GREATEST(world_boss_damages.max_damage, EXCLUDED.max_damage),
+  fight_count = world_boss_damages.fight_count + EXCLUDED.fight_count
+`, int64(bossID), int64(commanderID), int64(damage), int64(maxDamage), int64(fights)); err != nil {
+			return err
+		}
+		row := tx.QueryRow(ctx, `
+SELECT `+worldBossColumns+`
+FROM world_bosses b
+WHERE b.id = $1
+`, int64(bossID))
+		updated, err := scanWorldBoss(row)
+		if err != nil {
+			return err
+		}
+		boss = &updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return boss, nil
+}
+
+// ListWorldBossDamages returns the damage ranking of a boss, highest first.
+func ListWorldBossDamages(bossID uint32) ([]WorldBossDamage, error) {
+	ctx := context.Background()
+	rows, err := db.DefaultStore.Pool.Query(ctx, `
+SELECT d.boss_id, d.commander_id, c.name, d.damage, d.max_damage, d.fight_count, d.awarded
+FROM world_boss_damages d
+JOIN commanders c ON c.commander_id = d.commander_id
+WHERE d.boss_id = $1
+ORDER BY d.damage DESC, d.commander_id ASC
+`, int64(bossID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	damages := []WorldBossDamage{}
+	for rows.Next() {
+		var damage WorldBossDamage
+		if err := rows.Scan(&damage.BossID, &damage.CommanderID, &damage.Name, &damage.Damage, &damage.MaxDamage, &damage.FightCount, &damage.Awarded); err != nil {
+			return nil, err
+		}
+		damages = append(damages, damage)
+	}
+	return damages, rows.Err()
+}
+
+func GetWorldBossDamage(bossID uint32, commanderID uint32) (*WorldBossDamage, error) {
+	ctx := context.Background()
+	var damage WorldBossDamage
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT d.boss_id, d.commander_id, c.name, d.damage, d.max_damage, d.fight_count, d.awarded
+FROM world_boss_damages d
+JOIN commanders c ON c.commander_id = d.commander_id
+WHERE d.boss_id = $1 AND d.commander_id = $2
+`, int64(bossID), int64(commanderID)).Scan(&damage.BossID, &damage.CommanderID, &damage.Name, &damage.Damage, &damage.MaxDamage, &damage.FightCount, &damage.Awarded)
+	if err != nil {
+		return nil, db.MapNotFound(err)
+	}
+	return &damage, nil
+}
+
+// MarkWorldBossAwardedTx flags the kill reward of a boss as claimed,
+// returning db.ErrNotFound when it already was.
+func MarkWorldBossAwardedTx(ctx context.Context, tx pgx.Tx, bossID uint32, commanderID uint32) error {
+	tag, err := tx.Exec(ctx, `
+UPDATE world_boss_damages
+SET awarded = true
+WHERE boss_id = $1 AND commander_id = $2 AND awarded = false
+`, int64(bossID), int64(commanderID))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+func GetOrCreateWorldBossState(commanderID uint32) (*WorldBossState, error) {
+	ctx := context.Background()
+	var state WorldBossState
+	err := db.DefaultStore.Pool.QueryRow(ctx, `
+SELECT commander_id, summon_pt, summon_pt_daily_acc, fight_count, fight_count_update_time, auto_fight_boss_id, auto_fight_count, auto_fight_finish_time, last_daily_reset_at
+FROM commander_world_boss_states
+WHERE commander_id = $1
+`, int64(commanderID)).Scan(
+		&state.CommanderID,
+		&state.SummonPt,
+		&state.SummonPtDailyAcc,
+		&state.FightCount,
+		&state.FightCountUpdateTime,
+		&state.AutoFightBossID,
+		&state.AutoFightCount,
+		&state.AutoFightFinishTime,
+		&state.LastDailyResetAt,
+	)
+	err = db.MapNotFound(err)
+	if err == nil {
+		return &state, nil
+	}
+	if !db.IsNotFound(err) {
+		return nil, err
+	}
+	state = WorldBossState{CommanderID: commanderID, LastDailyResetAt: time.Unix(0, 0)}
+	if err := SaveWorldBossState(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func SaveWorldBossStateTx(ctx context.Context, tx pgx.Tx, state *WorldBossState) error {
+	if state.LastDailyResetAt.IsZero() {
+		state.LastDailyResetAt = time.Unix(0, 0)
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO commander_world_boss_states (commander_id, summon_pt, summon_pt_daily_acc, fight_count, fight_count_update_time, auto_fight_boss_id, auto_fight_count, auto_fight_finish_time, last_daily_reset_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (commander_id)
+DO UPDATE SET
+  summon_pt = EXCLUDED.summon_pt,
+  summon_pt_daily_acc = EXCLUDED.summon_pt_daily_acc,
+  fight_count = EXCLUDED.fight_count,
+  fight_count_update_time = EXCLUDED.fight_count_update_time,
+  auto_fight_boss_id = EXCLUDED.auto_fight_boss_id,
+  auto_fight_count = EXCLUDED.auto_fight_count,
+  auto_fight_finish_time = EXCLUDED.auto_fight_finish_time,
+  last_daily_reset_at = EXCLUDED.last_daily_reset_at
+`, int64(state.CommanderID), int64(state.SummonPt), int64(state.SummonPtDailyAcc), int64(state.FightCount), int64(state.FightCountUpdateTime),
+		int64(state.AutoFightBossID), int64(state.AutoFightCount), int64(state.AutoFightFinishTime), state.LastDailyResetAt)
+	return err
+}
+
+func SaveWorldBossState(state *WorldBossState) error {
+	ctx := context.Background()
+	return WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return SaveWorldBossStateTx(ctx, tx, state)
+	})
+}
+
+// ApplyWorldBossDailyReset clears the summon points earned today once a
+// new UTC day started.
+func ApplyWorldBossDailyReset(state *WorldBossState, now time.Time) bool {
+	resetAt := startOfDay(now.UTC())
+	if state.LastDailyResetAt.Before(resetAt) {
+		state.SummonPtDailyAcc = 0
+		state.LastDailyResetAt = resetAt
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestWorldBossSharedDamage(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9973, "Boss Owner")
+	seedFriendTestCommander(t, 9974, "Boss Helper")
+
+	now := uint32(time.Now().Unix())
+	boss := WorldBoss{OwnerID: 9973, TemplateID: 1, Level: 1, HP: 100, MaxHP: 100, ExpireTime: now + 3600}
+	if err := CreateWorldBoss(&boss); err != nil {
+		t.Fatalf("create world boss: %v", err)
+	}
+	active, err := GetActiveWorldBoss(9973, now)
+	if err != nil || active.ID != boss.ID {
+		t.Fatalf("expected active boss %d, got %+v (%v)", boss.ID, active, err)
+	}
+
+	if _, err := DamageWorldBoss(boss.ID, 9974, 30, 1, now); err != nil {
+		t.Fatalf("damage world boss: %v", err)
+	}
+	updated, err := DamageWorldBoss(boss.ID, 9973, 90, 2, now)
+	if err != nil {
+		t.Fatalf("damage world boss: %v", err)
+	}
+	if updated.HP != 0 || updated.KillTime != now || updated.FightCount != 3 || updated.RankCount != 2 {
+		t.Fatalf("unexpected killed boss: %+v", updated)
+	}
+	if _, err := DamageWorldBoss(boss.ID, 9974, 10, 1, now); !db.IsNotFound(err) {
+		t.Fatalf("expected dead boss to be refused, got %v", err)
+	}
+
+	ranking, err := ListWorldBossDamages(boss.ID)
+	if err != nil {
+		t.Fatalf("list world boss damages: %v", err)
+	}
+	if len(ranking) != 2 || ranking[0].CommanderID != 9973 || ranking[0].MaxDamage != 45 || ranking[1].Name != "Boss Helper" {
+		t.Fatalf("unexpected ranking: %+v", ranking)
+	}
+
+	ctx := context.Background()
+	award := func() error {
+		return WithPGXTx(ctx, func(tx pgx.Tx) error {
+			return MarkWorldBossAwardedTx(ctx, tx, boss.ID, 9974)
+		})
+	}
+	if err := award(); err != nil {
+		t.Fatalf("mark awarded: %v", err)
+	}
+	if err := award(); !db.IsNotFound(err) {
+		t.Fatalf("expected reward to be claimed once, got %v", err)
+	}
+
+	state, err := GetOrCreateWorldBossState(9974)
+	if err != nil {
+		t.Fatalf("get world boss state: %v", err)
+	}
+	state.SummonPt, state.SummonPtDailyAcc = 50, 50
+	if err := SaveWorldBossState(state); err != nil {
+		t.Fatalf("save world boss state: %v", err)
+	}
+	if !ApplyWorldBossDailyReset(state, time.Now()) || state.SummonPtDailyAcc != 0 || state.SummonPt != 50 {
+		t.Fatalf("unexpected daily reset: %+v", state)
+	}
+}
@@ -0,0 +1,82 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ggmolly/belfast/internal/db"
+)
+
+func TestWorldPersistence(t *testing.T) {
+	initCommanderItemTestDB(t)
+	seedFriendTestCommander(t, 9972, "World")
+
+	world, err := GetOrCreateWorld(9972)
+	if err != nil {
+		t.Fatalf("get world: %v", err)
+	}
+	world.Camp, world.MapID, world.ActionPower, world.ActionPowerFetchCount = 1, 10, 150, 2
+	if err := SaveWorld(world); err != nil {
+		t.Fatalf("save world: %v", err)
+	}
+	if err := SaveWorldMap(&WorldMap{CommanderID: 9972, MapID: 10, IsCleared: true}); err != nil {
+		t.Fatalf("save world map: %v", err)
+	}
+	if err := SaveWorldGroup(&WorldGroup{CommanderID: 9972, GroupID: 1, ShipList: Int64List{1, 2}, Row: 3, Column: 4}); err != nil {
+		t.Fatalf("save world group: %v", err)
+	}
+	groups, err := ListWorldGroups(9972)
+	if err != nil {
+		t.Fatalf("list world groups: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].ShipList) != 2 || groups[0].Row != 3 || groups[0].Column != 4 {
+		t.Fatalf("unexpected world groups: %+v", groups)
+	}
+
+	if err := AddWorldItem(9972, 50, 3); err != nil {
+		t.Fatalf("add world item: %v", err)
+	}
+	ctx := context.Background()
+	err = WithPGXTx(ctx, func(tx pgx.Tx) error {
+		if err := ConsumeWorldItemTx(ctx, tx, 9972, 50, 2); err != nil {
+			return err
+		}
+		return AddWorldPortPurchaseTx(ctx, tx, 9972, 7, 1)
+	})
+	if err != nil {
+		t.Fatalf("consume world item: %v", err)
+	}
+	if count, err := GetWorldItemCount(9972, 50); err != nil || count != 1 {
+		t.Fatalf("expected 1 world item left, got %d (%v)", count, err)
+	}
+	err = WithPGXTx(ctx, func(tx pgx.Tx) error {
+		return ConsumeWorldItemTx(ctx, tx, 9972, 50, 2)
+	})
+	if !db.IsNotFound(err) {
+		t.Fatalf("expected missing items to be refused, got %v", err)
+	}
+
+	reset, err := ApplyWorldDailyReset(world, time.Now())
+	if err != nil || !reset || world.ActionPowerFetchCount != 0 {
+		t.Fatalf("expected daily reset, got %v (%v): %+v", reset, err, world)
+	}
+	purchases, err := ListWorldPortPurchases(9972)
+	if err != nil || len(purchases) != 0 {
+		t.Fatalf("expected daily reset to clear purchases, got %v (%v)", purchases, err)
+	}
+
+	if err := ResetWorld(9972); err != nil {
+		t.Fatalf("reset world: %v", err)
+	}
+	maps, err := ListWorldMaps(9972)
+	if err != nil || len(maps) != 0 {
+		t.Fatalf("expected reset to clear maps, got %v (%v)", maps, err)
+	}
+	world, err = GetOrCreateWorld(9972)
+	if err != nil || world.Camp != 0 {
+		t.Fatalf("expected a fresh world after reset, got %+v (%v)", world, err)
+	}
+}
@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { setCurrent(nil) })
+
+	if err := Configure(config.PaymentsConfig{Provider: SandboxName, AutoApprove: true}); err != nil {
+		t.Fatalf("configure sandbox: %v", err)
+	}
+	sandbox, ok := Current().(*Sandbox)
+	if !ok || !sandbox.AutoApprove {
+		t.Fatalf("expected auto-approving sandbox, got %#v", Current())
+	}
+	if err := Configure(config.PaymentsConfig{Provider: "nope"}); err == nil {
+		t.Fatalf("expected unknown provider to be refused")
+	}
+	if Current() != sandbox {
+		t.Fatalf("expected unknown provider to keep the previous one")
+	}
+	if err := Configure(config.PaymentsConfig{Provider: "disabled"}); err != nil {
+		t.Fatalf("configure disabled: %v", err)
+	}
+	if Current() != nil {
+		t.Fatalf("expected payments to be disabled")
+	}
+}
+
+func TestSandboxCheckout(t *testing.T) {
+	order := orm.PaymentOrder{PayID: "sandbox-1"}
+	checkout, err := (&Sandbox{}).Checkout(&order)
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if checkout.Sign != "sandbox-1" || checkout.Approved {
+		t.Fatalf("unexpected checkout %+v", checkout)
+	}
+	checkout, _ = (&Sandbox{AutoApprove: true}).Checkout(&order)
+	if !checkout.Approved {
+		t.Fatalf("expected auto-approved checkout")
+	}
+}
+
+func TestParseGoods(t *testing.T) {
+	goods, err := parseGoods([]byte(`{"id":3,"money":4.99,"gem":300,"extra_gem":30,"first_pay_double":1,"extra_service_item":[[2,20001,1]]}`))
+	if err != nil {
+		t.Fatalf("parse goods: %v", err)
+	}
+	if goods.ID != 3 || goods.Money != 5 || !goods.FirstPayDouble {
+		t.Fatalf("unexpected goods %+v", goods)
+	}
+	if len(goods.ExtraServiceItem) != 1 || goods.ExtraServiceItem[0][1] != 20001 {
+		t.Fatalf("unexpected extra items %v", goods.ExtraServiceItem)
+	}
+	if gem, gemFree := goods.Gems(true); gem != 600 || gemFree != 30 {
+		t.Fatalf("expected doubled first charge, got %d+%d", gem, gemFree)
+	}
+	if gem, _ := goods.Gems(false); gem != 300 {
+		t.Fatalf("expected plain charge, got %d", gem)
+	}
+
+	goods, err = parseGoods([]byte(`{"id":4,"money":30,"gem":0,"extra_gem":0,"first_pay_double":0,"extra_service_item":""}`))
+	if err != nil {
+		t.Fatalf("parse goods without items: %v", err)
+	}
+	if goods.Money != 30 || len(goods.ExtraServiceItem) != 0 || !goods.Deliverable() {
+		t.Fatalf("unexpected goods %+v", goods)
+	}
+
+	goods, err = parseGoods([]byte(`{"id":1,"money":30,"gem":300,"extra_gem":0,"first_pay_double":0,"extra_service_item":"","genre":"monthly_card"}`))
+	if err != nil {
+		t.Fatalf("parse monthly card: %v", err)
+	}
+	if goods.Deliverable() {
+		t.Fatalf("expected monthly cards not to be deliverable")
+	}
+}
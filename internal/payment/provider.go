@@ -0,0 +1,85 @@
+// Package payment runs the charge shop: orders are recorded in a ledger,
+// approved by a payment provider, then delivered to the commander when the
+// client confirms them.
+package payment
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ggmolly/belfast/internal/config"
+	"github.com/ggmolly/belfast/internal/orm"
+)
+
+// Checkout is what the client needs to pay for an order. Approved reports
+// that the provider already approved the payment.
+type Checkout struct {
+	URL      string
+	Sign     string
+	Approved bool
+}
+
+// Provider takes the payment of the orders. A provider approving payments
+// asynchronously calls Approve once the payment went through.
+type Provider interface {
+	Name() string
+	Checkout(order *orm.PaymentOrder) (Checkout, error)
+}
+
+// Factory builds a provider from the [payments] section.
+type Factory func(cfg config.PaymentsConfig) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		SandboxName: func(cfg config.PaymentsConfig) (Provider, error) {
+			return &Sandbox{AutoApprove: cfg.AutoApprove}, nil
+		},
+	}
+
+	currentMu sync.RWMutex
+	current   Provider
+)
+
+// RegisterProvider makes a provider selectable by name in the config.
+func RegisterProvider(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// Configure selects the provider named in cfg. The previous provider is kept
+// when cfg is invalid.
+func Configure(cfg config.PaymentsConfig) error {
+	name := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if name == "" || name == "disabled" {
+		setCurrent(nil)
+		return nil
+	}
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown payment provider %q", cfg.Provider)
+	}
+	provider, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+	setCurrent(provider)
+	return nil
+}
+
+// Current returns the configured provider, nil when charging is disabled.
+func Current() Provider {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+func setCurrent(provider Provider) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = provider
+}
@@ -0,0 +1,19 @@
+package payment
+
+import "github.com/ggmolly/belfast/internal/orm"
+
+const SandboxName = "sandbox"
+
+// Sandbox approves payments without charging anything, for testing the shop.
+// Unless AutoApprove is set, orders wait for an admin to approve them.
+type Sandbox struct {
+	AutoApprove bool
+}
+
+func (sandbox *Sandbox) Name() string {
+	return SandboxName
+}
+
+func (sandbox *Sandbox) Checkout(order *orm.PaymentOrder) (Checkout, error) {
+	return Checkout{Sign: order.PayID, Approved: sandbox.AutoApprove}, nil
+}
@@ -0,0 +1,225 @@
+package payment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ggmolly/belfast/internal/connection"
+	"github.com/ggmolly/belfast/internal/db"
+	"github.com/ggmolly/belfast/internal/logger"
+	"github.com/ggmolly/belfast/internal/orm"
+	"github.com/ggmolly/belfast/internal/protobuf"
+)
+
+const (
+	goodsCategory = "ShareCfg/pay_data_display.json"
+	// genre of the monthly card goods, whose card and daily grant are not
+	// implemented: they can't be bought
+	monthlyCardGenre = "monthly_card"
+)
+
+var (
+	ErrDisabled         = errors.New("charging is disabled")
+	ErrUnknownGoods     = errors.New("unknown goods")
+	ErrNoRefund         = errors.New("no refund to pay back")
+	ErrUnsupportedGoods = errors.New("goods cannot be delivered")
+)
+
+// Goods is an entry of pay_data_display. ExtraServiceItem lists the
+// [type, id, count] drops granted along with the gems.
+type Goods struct {
+	ID               uint32
+	Money            uint32
+	Gem              uint32
+	ExtraGem         uint32
+	FirstPayDouble   bool
+	ExtraServiceItem [][]uint32
+	MonthlyCard      bool
+}
+
+type goodsTemplate struct {
+	ID               uint32          `json:"id"`
+	Money            json.Number     `json:"money"`
+	Gem              uint32          `json:"gem"`
+	ExtraGem         uint32          `json:"extra_gem"`
+	FirstPayDouble   uint32          `json:"first_pay_double"`
+	ExtraServiceItem json.RawMessage `json:"extra_service_item"`
+	Genre            string          `json:"genre"`
+}
+
+// LoadGoods returns a pay_data_display entry, ErrUnknownGoods when there is
+// none.
+func LoadGoods(shopID uint32) (*Goods, error) {
+	entry, err := orm.GetConfigEntry(goodsCategory, strconv.FormatUint(uint64(shopID), 10))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, ErrUnknownGoods
+		}
+		return nil, err
+	}
+	return parseGoods(entry.Data)
+}
+
+func parseGoods(data []byte) (*Goods, error) {
+	var tpl goodsTemplate
+	if err := json.Unmarshal(data, &tpl); err != nil {
+		return nil, err
+	}
+	goods := Goods{
+		ID:             tpl.ID,
+		Gem:            tpl.Gem,
+		ExtraGem:       tpl.ExtraGem,
+		FirstPayDouble: tpl.FirstPayDouble == 1,
+		MonthlyCard:    tpl.Genre == monthlyCardGenre,
+	}
+	// prices are not whole numbers in every region
+	if money, err := tpl.Money.Float64(); err == nil && money > 0 {
+		goods.Money = uint32(math.Ceil(money))
+	}
+	// extra_service_item is an empty string on goods without items
+	if len(tpl.ExtraServiceItem) > 0 && tpl.ExtraServiceItem[0] == '[' {
+		if err := json.Unmarshal(tpl.ExtraServiceItem, &goods.ExtraServiceItem); err != nil {
+			return nil, err
+		}
+	}
+	return &goods, nil
+}
+
+// Deliverable reports whether the goods can be granted: monthly cards are
+// refused, only gems and extra_service_item drops are delivered.
+func (goods *Goods) Deliverable() bool {
+	return !goods.MonthlyCard
+}
+
+// Gems returns the gems granted by a charge of goods; the paid gems are
+// doubled on the first charge of goods with first_pay_double.
+func (goods *Goods) Gems(firstCharge bool) (uint32, uint32) {
+	gem := goods.Gem
+	if firstCharge && goods.FirstPayDouble {
+		gem *= 2
+	}
+	return gem, goods.ExtraGem
+}
+
+// CreateOrder records an order of a commander for a goods and starts its
+// payment. Repay orders require an unsettled refund of the goods.
+func CreateOrder(commanderID uint32, shopID uint32, kind string) (*orm.PaymentOrder, Checkout, error) {
+	provider := Current()
+	if provider == nil {
+		return nil, Checkout{}, ErrDisabled
+	}
+	goods, err := LoadGoods(shopID)
+	if err != nil {
+		return nil, Checkout{}, err
+	}
+	if !goods.Deliverable() {
+		return nil, Checkout{}, ErrUnsupportedGoods
+	}
+	if kind == orm.PaymentKindRepay {
+		refunds, err := orm.ListUnsettledRefunds(commanderID)
+		if err != nil {
+			return nil, Checkout{}, err
+		}
+		found := false
+		for _, refund := range refunds {
+			found = found || refund.ShopID == shopID
+		}
+		if !found {
+			return nil, Checkout{}, ErrNoRefund
+		}
+	}
+	payID, err := newPayID(provider.Name())
+	if err != nil {
+		return nil, Checkout{}, err
+	}
+	order := orm.PaymentOrder{
+		PayID:       payID,
+		CommanderID: commanderID,
+		ShopID:      shopID,
+		Kind:        kind,
+		Provider:    provider.Name(),
+		Money:       goods.Money,
+	}
+	if err := orm.CreatePaymentOrder(&order); err != nil {
+		return nil, Checkout{}, err
+	}
+	checkout, err := provider.Checkout(&order)
+	if err != nil {
+		return nil, Checkout{}, err
+	}
+	if checkout.Approved {
+		approved, err := Approve(order.ID)
+		if err != nil {
+			return nil, Checkout{}, err
+		}
+		order = *approved
+	}
+	return &order, checkout, nil
+}
+
+// Approve marks a pending order as paid and tells the commander, whose
+// client then confirms it to get the goods. It returns db.ErrNotFound when
+// the order is not pending.
+func Approve(orderID uint32) (*orm.PaymentOrder, error) {
+	order, err := orm.GetPaymentOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	var gem, gemFree uint32
+	if order.Kind == orm.PaymentKindCharge {
+		goods, err := LoadGoods(order.ShopID)
+		if err != nil {
+			return nil, err
+		}
+		charged, err := orm.HasChargedGoods(order.CommanderID, order.ShopID, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		gem, gemFree = goods.Gems(!charged)
+	}
+	order, err = orm.MarkPaymentOrderPaid(orderID, gem, gemFree)
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("Payment", "Approve", fmt.Sprintf("order %s of %d paid (shop %d)", order.PayID, order.CommanderID, order.ShopID), logger.LOG_LEVEL_INFO)
+	if connection.BelfastInstance != nil {
+		connection.BelfastInstance.PushToCommander(order.CommanderID, 11503, PaidMessage(order))
+	}
+	return order, nil
+}
+
+// Refund refunds a delivered order. The commander keeps the goods but has to
+// pay the refund back before charging the same goods again.
+func Refund(orderID uint32) (*orm.PaymentOrder, error) {
+	order, err := orm.RefundPaymentOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	logger.LogEvent("Payment", "Refund", fmt.Sprintf("order %s of %d refunded (shop %d)", order.PayID, order.CommanderID, order.ShopID), logger.LOG_LEVEL_INFO)
+	return order, nil
+}
+
+// PaidMessage builds the SC_11503 telling the client an order was paid.
+func PaidMessage(order *orm.PaymentOrder) *protobuf.SC_11503 {
+	return &protobuf.SC_11503{
+		ShopId:  proto.Uint32(order.ShopID),
+		PayId:   proto.String(order.PayID),
+		Gem:     proto.Uint32(order.Gem),
+		GemFree: proto.Uint32(order.GemFree),
+	}
+}
+
+func newPayID(provider string) (string, error) {
+	buffer := make([]byte, 12)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return provider + "-" + hex.EncodeToString(buffer), nil
+}
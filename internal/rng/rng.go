@@ -42,6 +42,13 @@ func (r *LockedRand) Uint32N(n uint32) uint32 {
 	return value
 }
 
+func (r *LockedRand) Uint64N(n uint64) uint64 {
+	r.mu.Lock()
+	value := r.r.Uint64N(n)
+	r.mu.Unlock()
+	return value
+}
+
 func (r *LockedRand) Shuffle(n int, swap func(i, j int)) {
 	r.mu.Lock()
 	r.r.Shuffle(n, swap)
@@ -301,3 +301,12 @@ func TestUint32NZero(t *testing.T) {
 		}
 	}
 }
+
+func TestUint64N(t *testing.T) {
+	r := NewLockedRandFromSeed(7)
+	for i := 0; i < 100; i++ {
+		if value := r.Uint64N(1 << 40); value >= 1<<40 {
+			t.Fatalf("expected Uint64N to stay below n, got %d", value)
+		}
+	}
+}
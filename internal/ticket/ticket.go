@@ -0,0 +1,174 @@
+// Package ticket signs and verifies the server tickets handed out by the
+// gateway (SC_10021) and presented to a game server when joining it
+// (CS_10022).
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+const (
+	version = "BELFAST1"
+	// DefaultTTL is the lifetime of a ticket when ttl_seconds is not set.
+	DefaultTTL = 30 * time.Minute
+)
+
+var (
+	ErrDisabled     = errors.New("ticket signing is not configured")
+	ErrMalformed    = errors.New("malformed ticket")
+	ErrUnknownKey   = errors.New("ticket signed with an unknown key")
+	ErrBadSignature = errors.New("invalid ticket signature")
+	ErrExpired      = errors.New("ticket expired")
+	ErrWrongServer  = errors.New("ticket issued for another server")
+)
+
+// Claims are the signed contents of a ticket. A ServerID of 0 lets the
+// ticket be used on any server sharing the key; other tickets are only
+// accepted by the server configured with that id.
+type Claims struct {
+	Arg2      uint32
+	ServerID  uint32
+	ExpiresAt time.Time
+}
+
+// Keyring holds the keys tickets are signed with. It is safe for concurrent
+// use and can be reconfigured at any time to rotate keys.
+type Keyring struct {
+	mu       sync.RWMutex
+	active   string
+	keys     map[string][]byte
+	ttl      time.Duration
+	serverID uint32
+}
+
+// Default is the keyring used by the packet handlers.
+var Default = NewKeyring()
+
+func NewKeyring() *Keyring {
+	return &Keyring{keys: map[string][]byte{}, ttl: DefaultTTL}
+}
+
+// Configure replaces the keys of the keyring. The previous keys are kept
+// when cfg is invalid.
+func (keyring *Keyring) Configure(cfg config.TicketConfig) error {
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ".") {
+			return fmt.Errorf("invalid ticket key id %q", key.ID)
+		}
+		if key.Secret == "" {
+			return fmt.Errorf("ticket key %q has no secret", key.ID)
+		}
+		if _, ok := keys[key.ID]; ok {
+			return fmt.Errorf("duplicate ticket key %q", key.ID)
+		}
+		keys[key.ID] = []byte(key.Secret)
+	}
+	active := cfg.ActiveKey
+	if active == "" && len(cfg.Keys) == 1 {
+		active = cfg.Keys[0].ID
+	}
+	if len(keys) > 0 {
+		if _, ok := keys[active]; !ok {
+			return fmt.Errorf("active ticket key %q is not configured", active)
+		}
+	}
+	ttl := DefaultTTL
+	if cfg.TTLSeconds > 0 {
+		ttl = time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	keyring.mu.Lock()
+	defer keyring.mu.Unlock()
+	keyring.active = active
+	keyring.keys = keys
+	keyring.ttl = ttl
+	keyring.serverID = cfg.ServerID
+	return nil
+}
+
+// Enabled reports whether tickets are signed.
+func (keyring *Keyring) Enabled() bool {
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+	return len(keyring.keys) > 0
+}
+
+// Issue signs a ticket for the account arg2 on serverID with the active key.
+func (keyring *Keyring) Issue(arg2 uint32, serverID uint32, now time.Time) (string, error) {
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+	if len(keyring.keys) == 0 {
+		return "", ErrDisabled
+	}
+	expiresAt := now.Add(keyring.ttl).Unix()
+	payload := fmt.Sprintf("%s.%s.%d.%d.%d", version, keyring.active, arg2, serverID, expiresAt)
+	return payload + "." + sign(keyring.keys[keyring.active], payload), nil
+}
+
+// Verify checks the signature and expiry of a ticket presented to serverID.
+// serverID is client-provided, so server-bound tickets are checked against
+// the configured server id instead.
+func (keyring *Keyring) Verify(value string, serverID uint32, now time.Time) (Claims, error) {
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+	if len(keyring.keys) == 0 {
+		return Claims{}, ErrDisabled
+	}
+	cut := strings.LastIndexByte(value, '.')
+	if cut < 0 {
+		return Claims{}, ErrMalformed
+	}
+	payload, signature := value[:cut], value[cut+1:]
+	parts := strings.Split(payload, ".")
+	if len(parts) != 5 || parts[0] != version {
+		return Claims{}, ErrMalformed
+	}
+	secret, ok := keyring.keys[parts[1]]
+	if !ok {
+		return Claims{}, ErrUnknownKey
+	}
+	if !hmac.Equal([]byte(signature), []byte(sign(secret, payload))) {
+		return Claims{}, ErrBadSignature
+	}
+	arg2, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	ticketServerID, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	expiresAt, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	claims := Claims{Arg2: uint32(arg2), ServerID: uint32(ticketServerID), ExpiresAt: time.Unix(expiresAt, 0)}
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrExpired
+	}
+	if keyring.serverID != 0 && serverID != keyring.serverID {
+		return Claims{}, ErrWrongServer
+	}
+	// the ticket is bound to the configured id, never to the serverID the
+	// client asked for: a node without server_id refuses bound tickets
+	if claims.ServerID != 0 && claims.ServerID != keyring.serverID {
+		return Claims{}, ErrWrongServer
+	}
+	return claims, nil
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
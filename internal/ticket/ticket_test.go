@@ -0,0 +1,116 @@
+package ticket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggmolly/belfast/internal/config"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	keyring := NewKeyring()
+	if err := keyring.Configure(config.TicketConfig{TTLSeconds: 60, ServerID: 2, Keys: []config.TicketKeyConfig{{ID: "k1", Secret: "one"}}}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	value, err := keyring.Issue(12345, 2, now)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	claims, err := keyring.Verify(value, 2, now.Add(30*time.Second))
+	if err != nil || claims.Arg2 != 12345 || claims.ServerID != 2 {
+		t.Fatalf("unexpected claims %+v (%v)", claims, err)
+	}
+	if _, err := keyring.Verify(value, 3, now); !errors.Is(err, ErrWrongServer) {
+		t.Fatalf("expected wrong server, got %v", err)
+	}
+	if _, err := keyring.Verify(value, 2, now.Add(time.Minute)); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected expired ticket, got %v", err)
+	}
+	forged := strings.Replace(value, ".12345.", ".54321.", 1)
+	if _, err := keyring.Verify(forged, 2, now); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected forged ticket to be refused, got %v", err)
+	}
+	if _, err := keyring.Verify("=*=*=*=BELFAST=*=*=*=:12345", 2, now); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected legacy ticket to be refused, got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	keyring := NewKeyring()
+	now := time.Unix(1700000000, 0)
+	if err := keyring.Configure(config.TicketConfig{Keys: []config.TicketKeyConfig{{ID: "old", Secret: "one"}}}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	old, err := keyring.Issue(1, 0, now)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	rotated := config.TicketConfig{ActiveKey: "new", Keys: []config.TicketKeyConfig{{ID: "old", Secret: "one"}, {ID: "new", Secret: "two"}}}
+	if err := keyring.Configure(rotated); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if _, err := keyring.Verify(old, 7, now); err != nil {
+		t.Fatalf("expected tickets of the previous key to stay valid, got %v", err)
+	}
+	fresh, err := keyring.Issue(1, 0, now)
+	if err != nil || !strings.HasPrefix(fresh, version+".new.") {
+		t.Fatalf("expected the active key to sign, got %q (%v)", fresh, err)
+	}
+	if err := keyring.Configure(config.TicketConfig{ActiveKey: "new", Keys: []config.TicketKeyConfig{{ID: "new", Secret: "two"}}}); err != nil {
+		t.Fatalf("retire: %v", err)
+	}
+	if _, err := keyring.Verify(old, 7, now); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected retired key to be refused, got %v", err)
+	}
+
+	if err := keyring.Configure(config.TicketConfig{ActiveKey: "missing", Keys: []config.TicketKeyConfig{{ID: "new", Secret: "two"}}}); err == nil {
+		t.Fatalf("expected a missing active key to be refused")
+	}
+	if _, err := keyring.Verify(fresh, 7, now); err != nil {
+		t.Fatalf("expected an invalid config to keep the previous keys, got %v", err)
+	}
+}
+
+func TestServerBinding(t *testing.T) {
+	keyring := NewKeyring()
+	if err := keyring.Configure(config.TicketConfig{ServerID: 2, Keys: []config.TicketKeyConfig{{ID: "k1", Secret: "one"}}}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	value, err := keyring.Issue(1, 0, now)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := keyring.Verify(value, 2, now); err != nil {
+		t.Fatalf("expected unbound ticket to be accepted, got %v", err)
+	}
+	if _, err := keyring.Verify(value, 3, now); !errors.Is(err, ErrWrongServer) {
+		t.Fatalf("expected joins for another server to be refused, got %v", err)
+	}
+
+	bound, err := keyring.Issue(1, 3, now)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := keyring.Verify(bound, 3, now); !errors.Is(err, ErrWrongServer) {
+		t.Fatalf("expected a ticket of another server to be refused, got %v", err)
+	}
+}
+
+func TestBoundTicketNeedsServerID(t *testing.T) {
+	keyring := NewKeyring()
+	if err := keyring.Configure(config.TicketConfig{Keys: []config.TicketKeyConfig{{ID: "k1", Secret: "one"}}}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	value, err := keyring.Issue(1, 2, now)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := keyring.Verify(value, 2, now); !errors.Is(err, ErrWrongServer) {
+		t.Fatalf("expected a bound ticket to be refused without server_id, got %v", err)
+	}
+}